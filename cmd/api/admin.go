@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/config"
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/repository"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/logger"
+)
+
+const adminUsage = `Usage: todo-app admin <command> [flags]
+
+Commands:
+  revoke-tokens   --user=<id|email>            Sign a user out of every device
+  reset-password  --user=<id|email> [--stdin]  Set a new password (generated unless read from stdin)
+  recalc-scores   [--user=<id|email>]          Recompute smart scores (all users when --user is omitted)
+  reindex-search                               Rebuild the task indexes behind search
+  set-admin       --user=<id|email> [--revoke] Grant (or revoke) the admin role for /admin endpoints
+`
+
+// runAdmin executes an operator subcommand against the database and returns
+// the process exit code.
+func runAdmin(cfg *config.Config, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, adminUsage)
+		return 2
+	}
+	switch args[0] {
+	case "revoke-tokens", "reset-password", "recalc-scores", "reindex-search", "set-admin":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown admin command %q\n\n%s", args[0], adminUsage)
+		return 2
+	}
+
+	log := logger.New(cfg.App.LogLevel, cfg.App.Env)
+
+	db, err := connectDB(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	hasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid password hashing settings: %v\n", err)
+		return 1
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	taskSvc := service.NewTaskService(repository.NewTaskRepository(db), repository.NewProjectRepository(db), repository.NewTimeEntryRepository(db), log)
+	adminSvc := service.NewAdminService(
+		userRepo,
+		repository.NewRefreshTokenRepository(db),
+		repository.NewMaintenanceRepository(db),
+		taskSvc,
+		hasher,
+		log,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	cmd, flags := args[0], flag.NewFlagSet("admin "+args[0], flag.ContinueOnError)
+	userRef := flags.String("user", "", "user id or email")
+	fromStdin := flags.Bool("stdin", false, "read the new password from stdin")
+	revoke := flags.Bool("revoke", false, "revoke instead of grant")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	if err := dispatchAdmin(ctx, adminSvc, cmd, adminFlags{user: *userRef, stdin: *fromStdin, revoke: *revoke}); err != nil {
+		fmt.Fprintf(os.Stderr, "admin %s: %v\n", cmd, err)
+		if errors.Is(err, errAdminUsage) {
+			fmt.Fprint(os.Stderr, adminUsage)
+			return 2
+		}
+		return 1
+	}
+	return 0
+}
+
+var errAdminUsage = errors.New("invalid usage")
+
+type adminFlags struct {
+	user   string
+	stdin  bool
+	revoke bool
+}
+
+func dispatchAdmin(ctx context.Context, svc *service.AdminService, cmd string, f adminFlags) error {
+	userRef, fromStdin := f.user, f.stdin
+	switch cmd {
+	case "revoke-tokens":
+		user, err := requireUser(ctx, svc, userRef)
+		if err != nil {
+			return err
+		}
+		if err := svc.RevokeTokens(ctx, user.ID); err != nil {
+			return err
+		}
+		fmt.Printf("revoked all refresh tokens for %s (%s)\n", user.Email, user.ID)
+
+	case "reset-password":
+		user, err := requireUser(ctx, svc, userRef)
+		if err != nil {
+			return err
+		}
+		var password string
+		if fromStdin {
+			if password, err = readLine(os.Stdin); err != nil {
+				return err
+			}
+		}
+		set, err := svc.ResetPassword(ctx, user.ID, password)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("password reset for %s (%s); all sessions revoked\n", user.Email, user.ID)
+		if !fromStdin {
+			fmt.Printf("new password: %s\n", set)
+		}
+
+	case "recalc-scores":
+		var n int
+		if userRef != "" {
+			user, err := requireUser(ctx, svc, userRef)
+			if err != nil {
+				return err
+			}
+			n, err = svc.RecalcScores(ctx, &user.ID)
+			if err != nil {
+				return err
+			}
+		} else {
+			var err error
+			if n, err = svc.RecalcScores(ctx, nil); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("recalculated smart scores for %d user(s)\n", n)
+
+	case "reindex-search":
+		if err := svc.ReindexSearch(ctx); err != nil {
+			return err
+		}
+		fmt.Println("task search indexes rebuilt")
+
+	case "set-admin":
+		user, err := requireUser(ctx, svc, userRef)
+		if err != nil {
+			return err
+		}
+		if err := svc.SetAdmin(ctx, user.ID, !f.revoke); err != nil {
+			return err
+		}
+		if f.revoke {
+			fmt.Printf("revoked admin role from %s (%s)\n", user.Email, user.ID)
+		} else {
+			fmt.Printf("granted admin role to %s (%s)\n", user.Email, user.ID)
+		}
+
+	default:
+		return fmt.Errorf("%w: unknown command %q", errAdminUsage, cmd)
+	}
+	return nil
+}
+
+func requireUser(ctx context.Context, svc *service.AdminService, ref string) (*domain.User, error) {
+	if ref == "" {
+		return nil, fmt.Errorf("%w: --user is required", errAdminUsage)
+	}
+	user, err := svc.FindUser(ctx, ref)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("user %q not found", ref)
+	}
+	return user, err
+}
+
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
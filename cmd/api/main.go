@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,13 +11,26 @@ import (
 	"time"
 
 	"github.com/galihaleanda/todo-app/internal/config"
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
 	"github.com/galihaleanda/todo-app/internal/handler"
+	"github.com/galihaleanda/todo-app/internal/holiday"
+	"github.com/galihaleanda/todo-app/internal/middleware"
 	"github.com/galihaleanda/todo-app/internal/repository"
 	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
+	"github.com/galihaleanda/todo-app/pkg/hash"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
 	pkgjwt "github.com/galihaleanda/todo-app/pkg/jwt"
+	"github.com/galihaleanda/todo-app/pkg/llm"
 	"github.com/galihaleanda/todo-app/pkg/logger"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/galihaleanda/todo-app/pkg/signing"
+	"github.com/galihaleanda/todo-app/pkg/storage"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
 )
 
 func main() {
@@ -27,6 +41,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Operator subcommands: todo-app admin <command>
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		os.Exit(runAdmin(cfg, os.Args[2:]))
+	}
+	// Schema migrations: todo-app migrate [flags]
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg, os.Args[2:]))
+	}
+
 	// 2. Bootstrap logger
 	log := logger.New(cfg.App.LogLevel, cfg.App.Env)
 	log.WithField("env", cfg.App.Env).Info("starting todo-app")
@@ -39,6 +62,12 @@ func main() {
 	defer db.Close()
 	log.Info("connected to database")
 
+	// Redis is optional; without it, per-instance fallbacks are used
+	rdb := connectRedis(cfg, log)
+	if rdb != nil {
+		defer rdb.Close()
+	}
+
 	// 4. Wire dependencies (manual DI — no framework needed at this scale)
 	jwtManager := pkgjwt.New(
 		cfg.JWT.AccessSecret,
@@ -46,29 +75,370 @@ func main() {
 		cfg.JWT.AccessTokenTTL,
 		cfg.JWT.RefreshTokenTTL,
 	)
+	hasher, err := newPasswordHasher(cfg)
+	if err != nil {
+		log.WithError(err).Fatal("invalid password hashing settings")
+	}
 
 	// Repositories
 	userRepo := repository.NewUserRepository(db)
 	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	twoFactorRepo := repository.NewTwoFactorRepository(db)
+	personalAccessTokenRepo := repository.NewPersonalAccessTokenRepository(db)
+	inviteRepo := repository.NewInviteRepository(db)
+	referralRepo := repository.NewReferralRepository(db)
 	taskRepo := repository.NewTaskRepository(db)
+	timeEntryRepo := repository.NewTimeEntryRepository(db)
+	taskOccurrenceRepo := repository.NewTaskOccurrenceRepository(db)
+	taskEscalationRepo := repository.NewTaskEscalationRepository(db)
+	calendarFeedRepo := repository.NewCalendarFeedRepository(db)
 	projectRepo := repository.NewProjectRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	taskDependencyRepo := repository.NewTaskDependencyRepository(db)
+	attachmentRepo := repository.NewAttachmentRepository(db)
+	reminderRepo := repository.NewReminderRepository(db)
+	taskLinkRepo := repository.NewTaskLinkRepository(db)
+	projectMemberRepo := repository.NewProjectMemberRepository(db)
+	taskRevisionRepo := repository.NewTaskRevisionRepository(db)
+	automationRuleRepo := repository.NewAutomationRuleRepository(db)
+	dueDateRuleRepo := repository.NewDueDateRuleRepository(db)
 	analyticsRepo := repository.NewAnalyticsRepository(db)
+	emailSuppressionRepo := repository.NewEmailSuppressionRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	quietHoursRepo := repository.NewQuietHoursRepository(db)
+	deferredNotificationRepo := repository.NewDeferredNotificationRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	maintenanceRepo := repository.NewMaintenanceRepository(db)
+	retentionRepo := repository.NewRetentionRepository(db)
+	taskEventRepo := repository.NewTaskEventRepository(db)
+	smartViewRepo := repository.NewSmartViewRepository(db)
+	changelogRepo := repository.NewChangelogRepository(db)
+	feedbackRepo := repository.NewFeedbackRepository(db)
+	clientErrorRepo := repository.NewClientErrorRepository(db)
+	businessCalendarRepo := repository.NewBusinessCalendarRepository(db)
+	dayOffRepo := repository.NewDayOffRepository(db)
+	operationRepo := repository.NewOperationRepository(db)
+	brandingRepo := repository.NewBrandingRepository(db)
+	meteringRepo := repository.NewMeteringRepository(db)
+	instanceSettingsRepo := repository.NewInstanceSettingsRepository(db)
+	backupRepo := repository.NewBackupRepository(db)
+	scheduleClaimRepo := repository.NewScheduleClaimRepository(db)
+	taskViewRepo := repository.NewMemoryTaskViewRepository()
+	if rdb != nil {
+		taskViewRepo = repository.NewTaskViewRepository(rdb)
+	}
 
 	// Services
-	authSvc := service.NewAuthService(userRepo, refreshTokenRepo, jwtManager, log)
-	taskSvc := service.NewTaskService(taskRepo, projectRepo, log)
+	inviteSvc := service.NewInviteService(inviteRepo, userRepo, service.SignupPolicy{
+		Mode:        cfg.Signup.Mode,
+		InviteQuota: cfg.Signup.InviteQuota,
+	}, log)
+	referralSvc := service.NewReferralService(referralRepo, userRepo, log)
+	meteringSvc := service.NewMeteringService(meteringRepo, log)
+	userSvc := service.NewUserService(userRepo, log)
+	// Domain events: services publish what they changed, and notifications,
+	// webhooks, history and automation subscribe to it.
+	eventBus := eventbus.New(eventbus.Config{
+		Workers:    cfg.Events.Workers,
+		BufferSize: cfg.Events.BufferSize,
+	}, log)
+	authSvc := service.NewAuthService(userRepo, refreshTokenRepo, inviteSvc, hasher, jwtManager, log)
+	authSvc.UseEvents(eventBus)
+	twoFactorSvc := service.NewTwoFactorService(twoFactorRepo, userRepo, cfg.Branding.ProductName, log)
+	authSvc.UseTwoFactor(twoFactorSvc)
+	personalAccessTokenSvc := service.NewPersonalAccessTokenService(personalAccessTokenRepo, log)
+	eventbus.SubscribeAsync(eventBus, "referrals.attribute", referralSvc.UserRegistered)
+	taskSvc := service.NewTaskService(taskRepo, projectRepo, timeEntryRepo, log)
+	taskSvc.UseLocator(userSvc)
+	taskSvc.UseMembers(projectMemberRepo)
+	taskSvc.UseEvents(eventBus)
+	eventbus.SubscribeAsync(eventBus, "referrals.qualify", referralSvc.TaskCompleted)
+	taskHistorySvc := service.NewTaskHistoryService(taskEventRepo, taskSvc, log)
+	eventbus.Subscribe(eventBus, "task_history.record", taskHistorySvc.TaskChanged)
+	recurrenceSvc := service.NewRecurrenceService(taskOccurrenceRepo, taskSvc, log)
+	eventbus.Subscribe(eventBus, "recurrence.record", recurrenceSvc.TaskCompleted)
+	recentTaskSvc := service.NewRecentTaskService(taskRepo, taskViewRepo, log)
 	projectSvc := service.NewProjectService(projectRepo, log)
-	analyticsSvc := service.NewAnalyticsService(analyticsRepo)
+	projectSvc.UseMembers(projectMemberRepo, userRepo)
+	projectSvc.UseEvents(eventBus)
+	tagSvc := service.NewTagService(tagRepo, taskSvc, log)
+	tagSvc.UseEvents(eventBus)
+	dueDateRuleSvc := service.NewDueDateRuleService(dueDateRuleRepo, projectRepo, log)
+	taskSvc.UseDefaulter(dueDateRuleSvc)
+	holidays, err := holiday.Load(cfg.Holidays.CalendarsFile)
+	if err != nil {
+		log.WithError(err).Fatal("failed to load holiday calendars")
+	}
+	businessCalendarSvc := service.NewBusinessCalendarService(businessCalendarRepo, dayOffRepo, holidays, log)
+	taskSvc.UseDueDateAdjuster(businessCalendarSvc)
+	scheduleSvc := service.NewScheduleService(businessCalendarSvc, taskSvc, userRepo, log)
+	taskSvc.UseOverdueFilter(scheduleSvc)
+	calendarFeedSvc := service.NewCalendarFeedService(calendarFeedRepo, taskSvc, cfg.App.BaseURL, log)
+	qrSvc := service.NewQRService()
+	loadShedder := service.NewLoadShedder(maintenanceRepo, domain.SheddingThresholds{
+		MaxLatencyMs:    int(cfg.Shedding.MaxLatency.Milliseconds()),
+		MaxErrorPercent: cfg.Shedding.MaxErrorPercent,
+	}, cfg.Shedding.ProbeInterval, log)
+	taskDependencySvc := service.NewTaskDependencyService(taskDependencyRepo, taskSvc, log)
+	taskSvc.UseCompletionGuard(taskDependencySvc)
+	taskRevisionSvc := service.NewTaskRevisionService(taskRevisionRepo, taskSvc, cfg.Revisions.Keep, log)
+	taskSvc.UseDescriptionArchiver(taskRevisionSvc)
+	autocompleteSvc := service.NewAutocompleteService(projectRepo, tagRepo)
+	eventbus.Subscribe(eventBus, "autocomplete.projects", autocompleteSvc.ProjectChanged)
+	eventbus.Subscribe(eventBus, "autocomplete.tags", autocompleteSvc.TagChanged)
+	readCoalescer := service.NewReadCoalescer()
+	analyticsSvc := service.NewAnalyticsService(analyticsRepo, userRepo)
+	analyticsSvc.UseCoalescer(readCoalescer)
+	smartViewSvc := service.NewSmartViewService(smartViewRepo, userRepo)
+	smartViewSvc.UseCoalescer(readCoalescer)
+	changelogSvc := service.NewChangelogService(changelogRepo, userRepo, log)
+	adminSvc := service.NewAdminService(
+		userRepo, refreshTokenRepo, maintenanceRepo, taskSvc, hasher, log,
+	)
+	retentionSvc := service.NewRetentionService(
+		retentionRepo, maintenanceRepo, userRepo,
+		domain.RetentionPolicy{
+			domain.RetentionTasks:    cfg.Retention.TaskDays,
+			domain.RetentionProjects: cfg.Retention.ProjectDays,
+		},
+		cfg.Retention.PurgeInterval, log,
+	)
+	experiment, err := service.ParseRankerExperiment(cfg.Ranking.Experiment)
+	if err != nil {
+		log.WithError(err).Fatal("invalid RANKING_EXPERIMENT")
+	}
+	var rankers []service.Ranker
+	if cfg.Ranking.ExternalURL != "" {
+		rankers = append(rankers, service.NewExternalRanker(cfg.Ranking.ExternalURL, cfg.Ranking.ExternalTimeout))
+	}
+	rankingSvc := service.NewRankingService(taskRepo, userRepo, rankers, experiment, log)
+	llmProvider, err := llm.New(llm.Config{
+		Driver:  cfg.LLM.Driver,
+		BaseURL: cfg.LLM.BaseURL,
+		APIKey:  cfg.LLM.APIKey,
+		Model:   cfg.LLM.Model,
+		Timeout: cfg.LLM.Timeout,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure LLM provider")
+	}
+	breakdownSvc := service.NewBreakdownService(taskSvc, llmProvider, log)
+	taskExchangeSvc := service.NewTaskExchangeService(taskSvc, projectSvc, tagSvc, taskDependencySvc, log)
+	attachmentStore, err := storage.New(storage.Config{
+		Driver:          cfg.Storage.Driver,
+		Endpoint:        cfg.Storage.Endpoint,
+		Region:          cfg.Storage.Region,
+		Bucket:          cfg.Storage.Bucket,
+		AccessKeyID:     cfg.Storage.AccessKeyID,
+		SecretAccessKey: cfg.Storage.SecretAccessKey,
+		PathStyle:       cfg.Storage.PathStyle,
+		Timeout:         10 * time.Second,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure attachment storage")
+	}
+	attachmentSvc := service.NewAttachmentService(attachmentRepo, taskSvc, attachmentStore, service.AttachmentPolicy{
+		MaxBytes:     cfg.Storage.MaxUploadBytes,
+		AllowedTypes: cfg.Storage.AllowedTypes,
+		URLExpiry:    cfg.Storage.URLExpiry,
+	}, log)
+
+	// Email templates
+	emailRenderer, err := email.NewRenderer(email.Branding{
+		ProductName:  cfg.Branding.ProductName,
+		LogoURL:      cfg.Branding.LogoURL,
+		PrimaryColor: cfg.Branding.PrimaryColor,
+		AccentColor:  cfg.Branding.AccentColor,
+		FooterText:   cfg.Branding.FooterText,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("failed to load email templates")
+	}
+	brandingSvc := service.NewBrandingService(brandingRepo, emailRenderer, cfg.Mail.From, net.DefaultResolver, log)
+	if err := brandingSvc.Load(context.Background()); err != nil {
+		log.WithError(err).Fatal("failed to load workspace branding")
+	}
+	calendarFeedSvc.UseBranding(brandingSvc)
+
+	// Background jobs
+	jobQueue := jobs.New(jobs.Config{
+		Workers:     cfg.Jobs.Workers,
+		BufferSize:  cfg.Jobs.BufferSize,
+		MaxAttempts: cfg.Jobs.MaxAttempts,
+	}, log)
+
+	// Async operations
+	operationSvc := service.NewOperationService(operationRepo, jobQueue, cfg.Jobs.OperationTimeout, log)
+	operationSvc.Handle(service.OperationExportTasks, taskExchangeSvc.RunExport)
+	operationSvc.Handle(service.OperationImportTasks, taskExchangeSvc.RunImport)
+	operationSvc.Handle(service.OperationPurgeTrash, adminSvc.RunPurgeTrash)
+	operationSvc.Handle(service.OperationHardDeleteUser, adminSvc.RunHardDeleteUser)
+
+	// Outgoing email
+	mail, err := mailer.New(mailer.Config{
+		Driver:             cfg.Mail.Driver,
+		From:               cfg.Mail.From,
+		FromName:           cfg.Mail.FromName,
+		SMTPHost:           cfg.Mail.SMTPHost,
+		SMTPPort:           cfg.Mail.SMTPPort,
+		SMTPUsername:       cfg.Mail.SMTPUsername,
+		SMTPPassword:       cfg.Mail.SMTPPassword,
+		SendGridAPIKey:     cfg.Mail.SendGridAPIKey,
+		SESRegion:          cfg.Mail.SESRegion,
+		SESAccessKeyID:     cfg.Mail.SESAccessKeyID,
+		SESSecretAccessKey: cfg.Mail.SESSecretAccessKey,
+		MailgunDomain:      cfg.Mail.MailgunDomain,
+		MailgunAPIKey:      cfg.Mail.MailgunAPIKey,
+		MailgunBaseURL:     cfg.Mail.MailgunBaseURL,
+	}, log)
+	if err != nil {
+		log.WithError(err).Fatal("failed to configure mailer")
+	}
+	mailSvc := service.NewMailService(mail, emailRenderer, jobQueue, emailSuppressionRepo, log)
+	mailSvc.UseBranding(brandingSvc)
+	instanceSettingsSvc := service.NewInstanceSettingsService(instanceSettingsRepo, userRepo, inviteSvc, attachmentSvc, taskRevisionSvc, mail, cfg.Mail.From, log)
+	if err := instanceSettingsSvc.Load(context.Background()); err != nil {
+		log.WithError(err).Fatal("failed to load instance settings")
+	}
+	backupSvc := service.NewBackupService(backupRepo, attachmentStore, cfg.Backup.Keep, log)
+	backupSvc.UseReloaders(brandingSvc.Load, instanceSettingsSvc.Load)
+	operationSvc.Handle(service.OperationBackup, backupSvc.RunBackup)
+	notificationSvc := service.NewNotificationService(
+		notificationRepo, userRepo, quietHoursRepo, deferredNotificationRepo, mailSvc,
+		service.DefaultNotificationRules(cfg.Notify.BatchWindow), log,
+	)
+
+	webhookKeys, err := signing.New(signing.Config{
+		KeyID:              cfg.Webhook.SigningKeyID,
+		PrivateKey:         cfg.Webhook.SigningKey,
+		CreatedAt:          cfg.Webhook.SigningKeyCreatedAt,
+		PreviousPublicKeys: cfg.Webhook.PreviousPublicKeys,
+		RotationInterval:   cfg.Webhook.KeyRotationInterval,
+	})
+	if err != nil {
+		log.WithError(err).Fatal("failed to load webhook signing key")
+	}
+	if !webhookKeys.Enabled() {
+		log.Warn("WEBHOOK_SIGNING_KEY not set; webhooks are signed with per-webhook secrets only")
+	}
+	breaker := service.CircuitBreakerPolicy{Threshold: cfg.Jobs.BreakerThreshold, Cooloff: cfg.Jobs.BreakerCooloff}
+	webhookSvc := service.NewWebhookService(
+		webhookRepo, webhookDeliveryRepo, jobQueue, webhookKeys, cfg.Webhook.EgressIPs, breaker, notificationSvc, log,
+	)
+	eventbus.Subscribe(eventBus, "webhooks.deliver", webhookSvc.TaskChanged)
+	automationSvc := service.NewAutomationService(
+		automationRuleRepo, projectRepo, taskSvc, tagSvc, notificationSvc, jobQueue, breaker, log,
+	)
+	eventbus.Subscribe(eventBus, "automation.run", automationSvc.TaskChanged)
+	reminderSvc := service.NewReminderService(reminderRepo, taskSvc, notificationSvc, log)
+	taskLinkSvc := service.NewTaskLinkService(taskLinkRepo, taskSvc)
+	eventbus.Subscribe(eventBus, "reminders.reschedule", reminderSvc.TaskUpdated)
+	escalationPolicy := domain.EscalationPolicy{
+		MediumWithin: cfg.Escalate.MediumWithin,
+		HighWithin:   cfg.Escalate.HighWithin,
+	}
+	escalationSvc := service.NewEscalationService(taskEscalationRepo, taskSvc, escalationPolicy, log)
+	agingPolicy := domain.AgingPolicy{
+		After:        cfg.Aging.After,
+		PointsPerDay: float64(cfg.Aging.PointsPerDay),
+		MaxPoints:    float64(cfg.Aging.MaxPoints),
+		MediumAfter:  cfg.Aging.MediumAfter,
+		HighAfter:    cfg.Aging.HighAfter,
+	}
+	escalationSvc.UseAging(agingPolicy)
+	feedbackSvc := service.NewFeedbackService(feedbackRepo, userRepo, jobQueue, service.FeedbackForwarding{
+		URL:     cfg.Feedback.ForwardURL,
+		Token:   cfg.Feedback.ForwardToken,
+		Timeout: cfg.Feedback.ForwardTimeout,
+	}, log)
+	telemetrySvc := service.NewTelemetryService(clientErrorRepo, time.Duration(cfg.Telemetry.RetentionDays)*24*time.Hour, log)
+
+	// Instances sharing the database take turns: each slot of a scheduled
+	// task runs on whichever claims it first.
+	scheduler := jobs.NewScheduler(log)
+	scheduler.UseClaimer(scheduleClaimRepo, instanceName())
+	scheduler.Every("notifications.flush_deferred", time.Minute, notificationSvc.FlushDeferred)
+	scheduler.Every("notifications.resurface_snoozed", time.Minute, notificationSvc.ResurfaceSnoozed)
+	scheduler.Every("webhooks.prune_deliveries", time.Hour, webhookSvc.PruneDeliveries)
+	scheduler.Every("retention.purge", cfg.Retention.PurgeInterval, retentionSvc.Run)
+	scheduler.Every("automation.overdue", 5*time.Minute, automationSvc.RunOverdue)
+	scheduler.Every("attachments.prune_pending", time.Hour, attachmentSvc.PrunePending)
+	scheduler.Every("reminders.fire_due", time.Minute, reminderSvc.FireDue)
+	scheduler.Every("reminders.nudge_effort", 15*time.Minute, reminderSvc.NudgeEffortExceeded)
+	scheduler.Every("tasks.score_started", time.Minute, taskSvc.ScoreStarted)
+	scheduler.Every("referrals.grant_pending", time.Hour, referralSvc.GrantPending)
+	scheduler.Every("telemetry.prune_errors", time.Hour, telemetrySvc.Prune)
+	scheduler.Every("operations.prune", time.Minute, operationSvc.Prune)
+	scheduler.Every("metering.record", time.Hour, meteringSvc.Run)
+	if escalationPolicy.Enabled() {
+		scheduler.Every("tasks.escalate_priority", cfg.Escalate.Interval, escalationSvc.Run)
+	}
+	if agingPolicy.Enabled() {
+		scheduler.Every("tasks.age", cfg.Aging.Interval, escalationSvc.RunAging)
+	}
+	if backupSvc.Enabled() && cfg.Backup.Interval > 0 {
+		scheduler.Every("backups.snapshot", cfg.Backup.Interval, backupSvc.Run)
+	}
+	if cfg.Shedding.ProbeInterval > 0 {
+		scheduler.EveryInstance("health.probe_database", cfg.Shedding.ProbeInterval, loadShedder.Probe)
+	}
 
 	// Handlers
-	authHandler := handler.NewAuthHandler(authSvc)
-	taskHandler := handler.NewTaskHandler(taskSvc)
-	projectHandler := handler.NewProjectHandler(projectSvc)
-	analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc)
+	authHandler := handler.NewAuthHandler(authSvc, twoFactorSvc)
+	personalAccessTokenHandler := handler.NewPersonalAccessTokenHandler(personalAccessTokenSvc)
+	inviteHandler := handler.NewInviteHandler(inviteSvc)
+	referralHandler := handler.NewReferralHandler(referralSvc)
+	userHandler := handler.NewUserHandler(userSvc)
+	taskHandler := handler.NewTaskHandler(taskSvc, taskHistorySvc, recentTaskSvc, rankingSvc)
+	breakdownHandler := handler.NewBreakdownHandler(breakdownSvc)
+	taskExchangeHandler := handler.NewTaskExchangeHandler(taskExchangeSvc, operationSvc)
+	recurrenceHandler := handler.NewRecurrenceHandler(recurrenceSvc)
+	escalationHandler := handler.NewEscalationHandler(escalationSvc)
+	taskDependencyHandler := handler.NewTaskDependencyHandler(taskDependencySvc)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentSvc)
+	reminderHandler := handler.NewReminderHandler(reminderSvc)
+	taskLinkHandler := handler.NewTaskLinkHandler(taskLinkSvc)
+	taskRevisionHandler := handler.NewTaskRevisionHandler(taskRevisionSvc)
+	projectTransferSvc := service.NewProjectTransferService(projectSvc, taskSvc, tagSvc, taskDependencySvc, log)
+	projectTransferSvc.UseBranding(brandingSvc)
+	projectHandler := handler.NewProjectHandler(projectSvc, projectTransferSvc)
+	tagHandler := handler.NewTagHandler(tagSvc)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsSvc, emailRenderer)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc)
+	autocompleteHandler := handler.NewAutocompleteHandler(autocompleteSvc)
+	smartViewHandler := handler.NewSmartViewHandler(smartViewSvc)
+	rankingHandler := handler.NewRankingHandler(rankingSvc)
+	dueDateRuleHandler := handler.NewDueDateRuleHandler(dueDateRuleSvc)
+	businessCalendarHandler := handler.NewBusinessCalendarHandler(businessCalendarSvc)
+	scheduleHandler := handler.NewScheduleHandler(scheduleSvc)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(calendarFeedSvc)
+	qrHandler := handler.NewQRHandler(qrSvc)
+	automationHandler := handler.NewAutomationHandler(automationSvc)
+	webhookHandler := handler.NewWebhookHandler(webhookSvc)
+	operationHandler := handler.NewOperationHandler(operationSvc)
+	adminHandler := handler.NewAdminHandler(adminSvc, retentionSvc, operationSvc, loadShedder, readCoalescer, brandingSvc, meteringSvc, instanceSettingsSvc, backupSvc)
+	changelogHandler := handler.NewChangelogHandler(changelogSvc)
+	feedbackHandler := handler.NewFeedbackHandler(feedbackSvc)
+	telemetryHandler := handler.NewTelemetryHandler(telemetrySvc, cfg.Telemetry.MaxBatchBytes)
+
+	var devHandler *handler.DevHandler
+	if cfg.App.Env == "development" {
+		devHandler = handler.NewDevHandler(emailRenderer, cfg.App.BaseURL)
+	}
+
+	mailWebhookHandler := handler.NewMailWebhookHandler(mailSvc, cfg.Mail.WebhookSecret, log)
 
 	// Router
-	router := handler.NewRouter(authHandler, taskHandler, projectHandler, analyticsHandler, jwtManager, log)
-	engine := router.Setup()
+	router := handler.NewRouter(
+		authHandler, personalAccessTokenHandler, inviteHandler, referralHandler, userHandler, taskHandler, breakdownHandler, taskExchangeHandler, recurrenceHandler, escalationHandler, taskDependencyHandler, attachmentHandler, reminderHandler, taskLinkHandler, taskRevisionHandler, projectHandler, tagHandler, analyticsHandler, notificationHandler,
+		autocompleteHandler, smartViewHandler, rankingHandler, dueDateRuleHandler, businessCalendarHandler, scheduleHandler, calendarFeedHandler, qrHandler, automationHandler, webhookHandler, operationHandler, adminHandler, changelogHandler, feedbackHandler, telemetryHandler, devHandler, mailWebhookHandler,
+		middleware.RateLimit(cfg.Signup.RateLimit, cfg.Signup.RateWindow), middleware.RateLimit(cfg.Telemetry.RateLimit, cfg.Telemetry.RateWindow), middleware.LoadShed(loadShedder.Shedding), jwtManager, log,
+	)
+	engine, err := router.Setup(cfg.App.TrustedProxies)
+	if err != nil {
+		log.WithError(err).Fatal("invalid trusted proxies")
+	}
 
 	// 5. HTTP server with graceful shutdown
 	srv := &http.Server{
@@ -79,6 +449,16 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	jobQueue.Start(workerCtx)
+
+	// The scheduler gets its own context so it can be stopped before the
+	// queue drains whatever it enqueued.
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	defer stopScheduler()
+	scheduler.Start(schedulerCtx)
+
 	// Start server in goroutine
 	go func() {
 		log.Infof("listening on :%s", cfg.App.Port)
@@ -99,10 +479,28 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.WithError(err).Fatal("server forced shutdown")
 	}
+	stopScheduler()
+	scheduler.Wait()
+	eventBus.Close(ctx)
+	notificationSvc.Flush(ctx)
+	jobQueue.Stop(ctx)
 
 	log.Info("server stopped cleanly")
 }
 
+// newPasswordHasher builds the password hasher from cfg.
+func newPasswordHasher(cfg *config.Config) (*hash.Hasher, error) {
+	return hash.New(hash.Config{
+		Algorithm:  cfg.Password.Algorithm,
+		BcryptCost: cfg.Password.BcryptCost,
+		Argon2: hash.Argon2Params{
+			Memory:      uint32(cfg.Password.Argon2Memory),
+			Iterations:  uint32(cfg.Password.Argon2Iterations),
+			Parallelism: uint8(cfg.Password.Argon2Parallelism),
+		},
+	})
+}
+
 // connectDB establishes and configures the PostgreSQL connection pool.
 func connectDB(cfg *config.Config) (*sqlx.DB, error) {
 	db, err := sqlx.Connect("postgres", cfg.Database.DSN())
@@ -116,3 +514,31 @@ func connectDB(cfg *config.Config) (*sqlx.DB, error) {
 
 	return db, nil
 }
+
+// instanceName identifies this process in the scheduler's claims.
+func instanceName() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// connectRedis returns a client if Redis answers a ping, or nil.
+func connectRedis(cfg *config.Config, log *logrus.Logger) *redis.Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.WithError(err).Warn("redis unavailable; recently viewed tasks are kept in memory")
+		rdb.Close()
+		return nil
+	}
+	log.Info("connected to redis")
+	return rdb
+}
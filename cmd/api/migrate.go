@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/config"
+	"github.com/galihaleanda/todo-app/internal/migrate"
+	"github.com/galihaleanda/todo-app/migrations"
+)
+
+const migrateUsage = `Usage: todo-app migrate [flags]
+
+Checks the pending migrations for statements that would lock, rewrite or
+break tables the running release uses, then applies them. Unsafe findings
+stop the run unless --allow-unsafe is given.
+
+Flags:
+  --check             Report the pending migrations and their findings; apply nothing
+  --allow-unsafe      Apply even when a finding is unsafe
+  --max-lock=2s       Longest a statement may block a table before it counts as unsafe
+  --lock-timeout=5s   Give up on a statement waiting this long for its lock (0 waits)
+  --baseline=<n>      Record migrations up to n as applied without running them
+`
+
+// runMigrate checks and applies the pending migrations and returns the
+// process exit code.
+func runMigrate(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.Usage = func() { fmt.Fprint(os.Stderr, migrateUsage) }
+	check := flags.Bool("check", false, "report only")
+	allowUnsafe := flags.Bool("allow-unsafe", false, "apply unsafe migrations")
+	maxLock := flags.Duration("max-lock", 2*time.Second, "longest acceptable lock")
+	lockTimeout := flags.Duration("lock-timeout", 5*time.Second, "lock wait timeout")
+	baseline := flags.Int("baseline", 0, "record migrations up to this version as applied")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected argument %q\n\n%s", flags.Arg(0), migrateUsage)
+		return 2
+	}
+
+	all, err := migrate.Parse(migrations.Schema)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+
+	db, err := connectDB(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to connect to database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Hour)
+	defer cancel()
+	runner := migrate.NewRunner(db)
+
+	if *baseline > 0 {
+		n, err := runner.Baseline(ctx, all, *baseline)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+			return 1
+		}
+		fmt.Printf("recorded %d migration(s) up to %03d as applied\n", n, *baseline)
+	}
+
+	pending, err := runner.Pending(ctx, all)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		if errors.Is(err, migrate.ErrUntracked) {
+			fmt.Fprintf(os.Stderr, "the latest migration in this release is %03d\n", all[len(all)-1].Version)
+		}
+		return 1
+	}
+	if len(pending) == 0 {
+		fmt.Println("no pending migrations")
+		return 0
+	}
+
+	stats, err := runner.Stats(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		return 1
+	}
+	checker := migrate.Checker{MaxLock: *maxLock}
+	var findings []migrate.Finding
+	for _, m := range pending {
+		found := checker.Check(m, stats)
+		findings = append(findings, found...)
+		fmt.Println(m.Name)
+		for _, f := range found {
+			fmt.Printf("  %s\n    %s\n", f, firstLine(f.Statement))
+		}
+	}
+
+	unsafe := migrate.Unsafe(findings)
+	if *check {
+		if unsafe {
+			return 1
+		}
+		return 0
+	}
+	if unsafe && !*allowUnsafe {
+		fmt.Fprintln(os.Stderr, "refusing to apply unsafe migrations; rerun with --allow-unsafe during a maintenance window")
+		return 1
+	}
+
+	for _, m := range pending {
+		start := time.Now()
+		if err := runner.Apply(ctx, m, *lockTimeout); err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			return 1
+		}
+		fmt.Printf("applied %s in %s\n", m.Name, time.Since(start).Round(time.Millisecond))
+	}
+	return 0
+}
+
+func firstLine(stmt string) string {
+	line, _, more := strings.Cut(stmt, "\n")
+	if more {
+		line += " …"
+	}
+	return line
+}
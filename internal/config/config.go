@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,10 +12,30 @@ import (
 
 // Config holds all application configuration loaded from environment variables.
 type Config struct {
-	App      AppConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
+	App       AppConfig
+	Database  DatabaseConfig
+	Redis     RedisConfig
+	JWT       JWTConfig
+	Password  PasswordConfig
+	Branding  BrandingConfig
+	Mail      MailConfig
+	Jobs      JobsConfig
+	Events    EventsConfig
+	Notify    NotifyConfig
+	Webhook   WebhookConfig
+	Retention RetentionConfig
+	Ranking   RankingConfig
+	LLM       LLMConfig
+	Storage   StorageConfig
+	Signup    SignupConfig
+	Feedback  FeedbackConfig
+	Telemetry TelemetryConfig
+	Holidays  HolidayConfig
+	Escalate  EscalationConfig
+	Aging     AgingConfig
+	Revisions RevisionConfig
+	Shedding  SheddingConfig
+	Backup    BackupConfig
 }
 
 // AppConfig holds general application settings.
@@ -24,6 +45,9 @@ type AppConfig struct {
 	Port        string
 	LogLevel    string
 	BaseURL     string
+	// TrustedProxies lists the proxy IPs or CIDRs whose X-Forwarded-For is
+	// believed; with none, the client IP is the connecting peer.
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds PostgreSQL connection settings.
@@ -68,6 +92,191 @@ type JWTConfig struct {
 	RefreshTokenTTL    time.Duration
 }
 
+// PasswordConfig selects how new password digests are made. Digests made
+// otherwise are still accepted and replaced at the next login.
+type PasswordConfig struct {
+	Algorithm         string // argon2id | bcrypt
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+}
+
+// BrandingConfig holds per-deployment branding applied to outgoing emails.
+type BrandingConfig struct {
+	ProductName  string
+	LogoURL      string
+	PrimaryColor string
+	AccentColor  string
+	FooterText   string
+}
+
+// MailConfig selects and configures the outgoing email backend.
+type MailConfig struct {
+	Driver        string // log | smtp | sendgrid | ses | mailgun
+	From          string
+	FromName      string
+	WebhookSecret string // shared secret required on bounce webhook callbacks
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+
+	SESRegion          string
+	SESAccessKeyID     string
+	SESSecretAccessKey string
+
+	MailgunDomain  string
+	MailgunAPIKey  string
+	MailgunBaseURL string
+}
+
+// JobsConfig tunes the in-process background job queue.
+type JobsConfig struct {
+	Workers     int
+	BufferSize  int
+	MaxAttempts int
+	// Consecutive failures after which an automation rule or webhook is
+	// paused for BreakerCooloff; 0 disables the breakers.
+	BreakerThreshold int
+	BreakerCooloff   time.Duration
+	// How long an async operation (export, import, hard delete) may run
+	// before it is failed.
+	OperationTimeout time.Duration
+}
+
+// EventsConfig tunes the in-process domain event bus.
+type EventsConfig struct {
+	Workers    int
+	BufferSize int
+}
+
+// NotifyConfig tunes notification delivery.
+type NotifyConfig struct {
+	BatchWindow time.Duration // how long bursts of similar events are coalesced
+}
+
+// WebhookConfig holds the key pair used to sign outgoing webhooks and the
+// details published to receivers at GET /webhooks/meta.
+type WebhookConfig struct {
+	SigningKeyID        string
+	SigningKey          string // base64 Ed25519 seed; empty disables asymmetric signatures
+	SigningKeyCreatedAt string // YYYY-MM-DD
+	PreviousPublicKeys  string // "id=base64key,..." still accepted during rotation
+	KeyRotationInterval time.Duration
+	EgressIPs           []string
+}
+
+// RetentionConfig sets how long soft-deleted rows are kept before the purge
+// job hard-deletes them. Admins can override the windows per user.
+type RetentionConfig struct {
+	TaskDays      int
+	ProjectDays   int
+	PurgeInterval time.Duration
+}
+
+// RankingConfig selects task list rankers beyond the built-in smart score.
+type RankingConfig struct {
+	ExternalURL     string // ranking service endpoint; empty disables the external ranker
+	ExternalTimeout time.Duration
+	Experiment      string // "ranker:weight,..." for users without an explicit choice
+}
+
+// LLMConfig selects the language model backing task breakdown. An empty
+// driver disables it.
+type LLMConfig struct {
+	Driver  string // none | openai
+	BaseURL string // for OpenAI-compatible servers
+	APIKey  string
+	Model   string
+	Timeout time.Duration
+}
+
+// StorageConfig selects the object store holding task attachments and
+// limits what may be uploaded. An empty driver disables attachments.
+type StorageConfig struct {
+	Driver          string // none | s3
+	Endpoint        string // S3-compatible endpoint such as MinIO; empty means AWS
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool
+	MaxUploadBytes  int64
+	AllowedTypes    []string // e.g. image/*,application/pdf; empty allows any
+	URLExpiry       time.Duration
+}
+
+// SignupConfig controls public registration.
+type SignupConfig struct {
+	Mode        string // open | invite_only
+	InviteQuota int    // invite codes each non-admin user may create
+	RateLimit   int    // registrations allowed per client IP per RateWindow; 0 disables the limit
+	RateWindow  time.Duration
+}
+
+// FeedbackConfig configures forwarding of user feedback to an issue tracker.
+type FeedbackConfig struct {
+	ForwardURL     string // POST endpoint receiving new reports; empty disables forwarding
+	ForwardToken   string // sent as a bearer token when set
+	ForwardTimeout time.Duration
+}
+
+// TelemetryConfig bounds client error ingestion at POST /telemetry/errors.
+type TelemetryConfig struct {
+	MaxBatchBytes int64
+	RateLimit     int // batches allowed per client IP per RateWindow; 0 disables the limit
+	RateWindow    time.Duration
+	RetentionDays int
+}
+
+// HolidayConfig holds the country holiday calendars used for business days.
+type HolidayConfig struct {
+	CalendarsFile string // YAML file adding to or replacing the built-in calendars; empty for the built-ins only
+}
+
+// EscalationConfig sets when the escalation job raises task priority ahead
+// of the due date. Both windows default to zero, which leaves it off.
+type EscalationConfig struct {
+	MediumWithin time.Duration // low becomes medium when due within this
+	HighWithin   time.Duration // low and medium become high when due within this
+	Interval     time.Duration
+}
+
+// AgingConfig sets how tasks left in todo gain score and, optionally,
+// priority. Every threshold defaults to zero, which leaves it off.
+type AgingConfig struct {
+	After        time.Duration // time in todo before the score starts to grow
+	PointsPerDay int           // score gained per day past After
+	MaxPoints    int           // cap on the score gained; 0 for none
+	MediumAfter  time.Duration // low becomes medium after this long in todo
+	HighAfter    time.Duration // low and medium become high after this long in todo
+	Interval     time.Duration
+}
+
+// RevisionConfig sets how many earlier descriptions are kept per task.
+type RevisionConfig struct {
+	Keep int // 0 keeps none
+}
+
+// SheddingConfig sets the starting thresholds for shedding low-priority
+// requests while the database is degraded; admins can change them at runtime.
+type SheddingConfig struct {
+	MaxLatency      time.Duration // mean probe round trip; 0 disables the check
+	MaxErrorPercent int           // failed probes; 0 disables the check
+	ProbeInterval   time.Duration
+}
+
+// BackupConfig schedules backups into the attachment object store; they
+// are off while no store is configured.
+type BackupConfig struct {
+	Interval time.Duration // 0 disables scheduled backups
+	Keep     int           // scheduled backups kept; older ones are pruned
+}
+
 // Load reads configuration from .env and environment variables.
 // Environment variables take precedence over .env values.
 func Load() (*Config, error) {
@@ -81,6 +290,7 @@ func Load() (*Config, error) {
 			Port:     getEnv("APP_PORT", "8080"),
 			LogLevel: getEnv("LOG_LEVEL", "info"),
 			BaseURL:  getEnv("APP_BASE_URL", "http://localhost:8080"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -105,6 +315,135 @@ func Load() (*Config, error) {
 			AccessTokenTTL:  getEnvDuration("JWT_ACCESS_TTL", 15*time.Minute),
 			RefreshTokenTTL: getEnvDuration("JWT_REFRESH_TTL", 7*24*time.Hour),
 		},
+		Password: PasswordConfig{
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "argon2id"),
+			BcryptCost:        getEnvInt("PASSWORD_BCRYPT_COST", 10),
+			Argon2Memory:      getEnvInt("PASSWORD_ARGON2_MEMORY", 64*1024),
+			Argon2Iterations:  getEnvInt("PASSWORD_ARGON2_ITERATIONS", 3),
+			Argon2Parallelism: getEnvInt("PASSWORD_ARGON2_PARALLELISM", 4),
+		},
+		Branding: BrandingConfig{
+			ProductName:  getEnv("BRAND_PRODUCT_NAME", "Todo App"),
+			LogoURL:      getEnv("BRAND_LOGO_URL", ""),
+			PrimaryColor: getEnv("BRAND_PRIMARY_COLOR", "#6366F1"),
+			AccentColor:  getEnv("BRAND_ACCENT_COLOR", "#F59E0B"),
+			FooterText:   getEnv("BRAND_FOOTER_TEXT", "You are receiving this email because you have an account with us."),
+		},
+		Mail: MailConfig{
+			Driver:             getEnv("MAIL_DRIVER", "log"),
+			From:               getEnv("MAIL_FROM", "no-reply@localhost"),
+			FromName:           getEnv("MAIL_FROM_NAME", "Todo App"),
+			WebhookSecret:      getEnv("MAIL_WEBHOOK_SECRET", ""),
+			SMTPHost:           getEnv("MAIL_SMTP_HOST", ""),
+			SMTPPort:           getEnv("MAIL_SMTP_PORT", "587"),
+			SMTPUsername:       getEnv("MAIL_SMTP_USERNAME", ""),
+			SMTPPassword:       getEnv("MAIL_SMTP_PASSWORD", ""),
+			SendGridAPIKey:     getEnv("MAIL_SENDGRID_API_KEY", ""),
+			SESRegion:          getEnv("MAIL_SES_REGION", ""),
+			SESAccessKeyID:     getEnv("AWS_ACCESS_KEY_ID", ""),
+			SESSecretAccessKey: getEnv("AWS_SECRET_ACCESS_KEY", ""),
+			MailgunDomain:      getEnv("MAIL_MAILGUN_DOMAIN", ""),
+			MailgunAPIKey:      getEnv("MAIL_MAILGUN_API_KEY", ""),
+			MailgunBaseURL:     getEnv("MAIL_MAILGUN_BASE_URL", ""),
+		},
+		Jobs: JobsConfig{
+			Workers:     getEnvInt("JOBS_WORKERS", 4),
+			BufferSize:  getEnvInt("JOBS_BUFFER_SIZE", 1000),
+			MaxAttempts: getEnvInt("JOBS_MAX_ATTEMPTS", 5),
+
+			BreakerThreshold: getEnvInt("JOBS_BREAKER_THRESHOLD", 10),
+			BreakerCooloff:   getEnvDuration("JOBS_BREAKER_COOLOFF", time.Hour),
+			OperationTimeout: getEnvDuration("JOBS_OPERATION_TIMEOUT", 30*time.Minute),
+		},
+		Events: EventsConfig{
+			Workers:    getEnvInt("EVENTS_WORKERS", 2),
+			BufferSize: getEnvInt("EVENTS_BUFFER_SIZE", 1000),
+		},
+		Notify: NotifyConfig{
+			BatchWindow: getEnvDuration("NOTIFY_BATCH_WINDOW", 2*time.Minute),
+		},
+		Webhook: WebhookConfig{
+			SigningKeyID:        getEnv("WEBHOOK_SIGNING_KEY_ID", ""),
+			SigningKey:          getEnv("WEBHOOK_SIGNING_KEY", ""),
+			SigningKeyCreatedAt: getEnv("WEBHOOK_SIGNING_KEY_CREATED_AT", ""),
+			PreviousPublicKeys:  getEnv("WEBHOOK_PREVIOUS_PUBLIC_KEYS", ""),
+			KeyRotationInterval: getEnvDuration("WEBHOOK_KEY_ROTATION_INTERVAL", 90*24*time.Hour),
+			EgressIPs:           getEnvList("WEBHOOK_EGRESS_IPS"),
+		},
+		Retention: RetentionConfig{
+			TaskDays:      getEnvInt("RETENTION_TASKS_DAYS", 30),
+			ProjectDays:   getEnvInt("RETENTION_PROJECTS_DAYS", 30),
+			PurgeInterval: getEnvDuration("RETENTION_PURGE_INTERVAL", 24*time.Hour),
+		},
+		Ranking: RankingConfig{
+			ExternalURL:     getEnv("RANKING_EXTERNAL_URL", ""),
+			ExternalTimeout: getEnvDuration("RANKING_EXTERNAL_TIMEOUT", 300*time.Millisecond),
+			Experiment:      getEnv("RANKING_EXPERIMENT", ""),
+		},
+		LLM: LLMConfig{
+			Driver:  getEnv("LLM_DRIVER", ""),
+			BaseURL: getEnv("LLM_BASE_URL", ""),
+			APIKey:  getEnv("LLM_API_KEY", ""),
+			Model:   getEnv("LLM_MODEL", ""),
+			Timeout: getEnvDuration("LLM_TIMEOUT", 30*time.Second),
+		},
+		Storage: StorageConfig{
+			Driver:          getEnv("STORAGE_DRIVER", ""),
+			Endpoint:        getEnv("STORAGE_ENDPOINT", ""),
+			Region:          getEnv("STORAGE_REGION", "us-east-1"),
+			Bucket:          getEnv("STORAGE_BUCKET", ""),
+			AccessKeyID:     getEnv("STORAGE_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnv("STORAGE_SECRET_ACCESS_KEY", ""),
+			PathStyle:       getEnv("STORAGE_PATH_STYLE", "false") == "true",
+			MaxUploadBytes:  int64(getEnvInt("STORAGE_MAX_UPLOAD_BYTES", 25<<20)),
+			AllowedTypes:    getEnvList("STORAGE_ALLOWED_TYPES"),
+			URLExpiry:       getEnvDuration("STORAGE_URL_EXPIRY", 15*time.Minute),
+		},
+		Signup: SignupConfig{
+			Mode:        getEnv("SIGNUP_MODE", "open"),
+			InviteQuota: getEnvInt("SIGNUP_INVITE_QUOTA", 5),
+			RateLimit:   getEnvInt("SIGNUP_RATE_LIMIT", 5),
+			RateWindow:  getEnvDuration("SIGNUP_RATE_WINDOW", time.Hour),
+		},
+		Feedback: FeedbackConfig{
+			ForwardURL:     getEnv("FEEDBACK_FORWARD_URL", ""),
+			ForwardToken:   getEnv("FEEDBACK_FORWARD_TOKEN", ""),
+			ForwardTimeout: getEnvDuration("FEEDBACK_FORWARD_TIMEOUT", 10*time.Second),
+		},
+		Telemetry: TelemetryConfig{
+			MaxBatchBytes: int64(getEnvInt("TELEMETRY_MAX_BATCH_BYTES", 256<<10)),
+			RateLimit:     getEnvInt("TELEMETRY_RATE_LIMIT", 30),
+			RateWindow:    getEnvDuration("TELEMETRY_RATE_WINDOW", time.Minute),
+			RetentionDays: getEnvInt("TELEMETRY_RETENTION_DAYS", 30),
+		},
+		Holidays: HolidayConfig{
+			CalendarsFile: getEnv("HOLIDAY_CALENDARS_FILE", ""),
+		},
+		Escalate: EscalationConfig{
+			MediumWithin: getEnvDuration("ESCALATION_MEDIUM_WITHIN", 0),
+			HighWithin:   getEnvDuration("ESCALATION_HIGH_WITHIN", 0),
+			Interval:     getEnvDuration("ESCALATION_INTERVAL", 15*time.Minute),
+		},
+		Aging: AgingConfig{
+			After:        getEnvDuration("AGING_AFTER", 0),
+			PointsPerDay: getEnvInt("AGING_POINTS_PER_DAY", 2),
+			MaxPoints:    getEnvInt("AGING_MAX_POINTS", 20),
+			MediumAfter:  getEnvDuration("AGING_MEDIUM_AFTER", 0),
+			HighAfter:    getEnvDuration("AGING_HIGH_AFTER", 0),
+			Interval:     getEnvDuration("AGING_INTERVAL", 24*time.Hour),
+		},
+		Revisions: RevisionConfig{
+			Keep: getEnvInt("TASK_DESCRIPTION_REVISIONS", 20),
+		},
+		Shedding: SheddingConfig{
+			MaxLatency:      getEnvDuration("SHED_MAX_DB_LATENCY", 500*time.Millisecond),
+			MaxErrorPercent: getEnvInt("SHED_MAX_DB_ERROR_PERCENT", 25),
+			ProbeInterval:   getEnvDuration("SHED_PROBE_INTERVAL", 5*time.Second),
+		},
+		Backup: BackupConfig{
+			Interval: getEnvDuration("BACKUP_INTERVAL", 24*time.Hour),
+			Keep:     getEnvInt("BACKUP_KEEP", 7),
+		},
 	}
 
 	if err := cfg.validate(); err != nil {
@@ -115,6 +454,12 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) validate() error {
+	if c.Signup.Mode != "open" && c.Signup.Mode != "invite_only" {
+		return fmt.Errorf("SIGNUP_MODE must be open or invite_only, got %q", c.Signup.Mode)
+	}
+	if c.Backup.Keep < 1 {
+		return fmt.Errorf("BACKUP_KEEP must be at least 1, got %d", c.Backup.Keep)
+	}
 	if c.App.Env == "production" {
 		if c.JWT.AccessSecret == "change-me-access-secret" {
 			return fmt.Errorf("JWT_ACCESS_SECRET must be changed in production")
@@ -150,3 +495,13 @@ func getEnvDuration(key string, fallback time.Duration) time.Duration {
 	}
 	return fallback
 }
+
+func getEnvList(key string) []string {
+	var out []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
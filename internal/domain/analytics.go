@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // DailyStats holds productivity stats for a single day.
 type DailyStats struct {
@@ -32,4 +36,56 @@ type AnalyticsDashboard struct {
 	HighPriorityPending   int `json:"high_priority_pending"`
 	MediumPriorityPending int `json:"medium_priority_pending"`
 	LowPriorityPending    int `json:"low_priority_pending"`
+
+	// Recurring tasks (last 30 days): occurrences completed, how many by
+	// their due date, and that share in percent
+	RecurringCompleted  int     `json:"recurring_completed"`
+	RecurringOnTime     int     `json:"recurring_on_time"`
+	RecurrenceAdherence float64 `json:"recurrence_adherence_percent"`
+}
+
+// EstimateStats compares estimated with actual hours over finished tasks
+// that had an estimate and either actual hours or tracked time.
+type EstimateStats struct {
+	Tasks          int     `json:"tasks" db:"tasks"`
+	EstimatedHours float64 `json:"estimated_hours" db:"estimated_hours"`
+	ActualHours    float64 `json:"actual_hours" db:"actual_hours"`
+	// Ratio is actual over estimated hours in total; above 1, work takes
+	// longer than planned. MedianRatio is the typical task's.
+	Ratio       float64 `json:"actual_to_estimate_ratio" db:"ratio"`
+	MedianRatio float64 `json:"median_ratio" db:"median_ratio"`
+	// OnTarget counts tasks that took within 20% of their estimate;
+	// Underestimated and Overestimated those that took longer or shorter.
+	OnTarget       int `json:"on_target" db:"on_target"`
+	Underestimated int `json:"underestimated" db:"underestimated"`
+	Overestimated  int `json:"overestimated" db:"overestimated"`
+}
+
+// EstimateAccuracy is EstimateStats for the tasks completed since Since,
+// overall and per priority.
+type EstimateAccuracy struct {
+	Since time.Time `json:"since"`
+	EstimateStats
+	ByPriority map[TaskPriority]EstimateStats `json:"by_priority"`
+}
+
+// Raw analytics events, the facts the aggregates are counted from.
+const (
+	AnalyticsEventCreated   = "task.created"
+	AnalyticsEventCompleted = "task.completed"
+)
+
+// AnalyticsRawEvent is one task created or completed, with the attributes
+// analytics groups and compares by, as of now rather than of At.
+type AnalyticsRawEvent struct {
+	Event          string       `json:"event" db:"event"`
+	At             time.Time    `json:"at" db:"at"`
+	TaskID         uuid.UUID    `json:"task_id" db:"task_id"`
+	ProjectID      *uuid.UUID   `json:"project_id,omitempty" db:"project_id"`
+	ParentID       *uuid.UUID   `json:"parent_id,omitempty" db:"parent_id"`
+	Priority       TaskPriority `json:"priority" db:"priority"`
+	EstimatedHours *float64     `json:"estimated_hours,omitempty" db:"estimated_hours"`
+	ActualHours    *float64     `json:"actual_hours,omitempty" db:"actual_hours"`
+	DueDate        *time.Time   `json:"due_date,omitempty" db:"due_date"`
+	Recurring      bool         `json:"recurring" db:"recurring"`
 }
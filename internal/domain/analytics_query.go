@@ -0,0 +1,54 @@
+package domain
+
+import "time"
+
+// Metrics an analytics query can compute.
+const (
+	MetricTasksCompleted     = "tasks_completed"
+	MetricTasksCreated       = "tasks_created"
+	MetricTasksOverdue       = "tasks_overdue"
+	MetricAvgCompletionHours = "avg_completion_hours"
+)
+
+// Dimensions an analytics query can group by.
+const (
+	GroupByProject  = "project"
+	GroupByPriority = "priority"
+	GroupByStatus   = "status"
+	GroupByDay      = "day"
+	GroupByWeek     = "week"
+	GroupByMonth    = "month"
+)
+
+// AnalyticsQuery is a structured question over the user's tasks. Every field
+// selects from a fixed set, so the repository can build SQL from vetted
+// fragments only.
+type AnalyticsQuery struct {
+	Metric  string `json:"metric"`
+	GroupBy string `json:"group_by,omitempty"`
+	// From and To bound the metric's own timestamp (completed_at for
+	// completions, created_at for creations, due_date for overdue tasks) as
+	// [From, To). Nil leaves that side open.
+	From     *time.Time `json:"from,omitempty"`
+	To       *time.Time `json:"to,omitempty"`
+	Timezone string     `json:"timezone"`
+}
+
+// AnalyticsRow is one value of an analytics result; Group is empty when the
+// query is not grouped.
+type AnalyticsRow struct {
+	Group string  `json:"group,omitempty" db:"grp"`
+	Value float64 `json:"value" db:"value"`
+}
+
+// AnalyticsAnswer is the response to a natural-language analytics question.
+type AnalyticsAnswer struct {
+	Question string         `json:"question"`
+	Query    AnalyticsQuery `json:"query"`
+	Rows     []AnalyticsRow `json:"rows"`
+}
+
+// AskAnalyticsRequest is the payload for POST /analytics/ask.
+type AskAnalyticsRequest struct {
+	Question string `json:"question" validate:"required,min=3,max=500"`
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment upload states.
+const (
+	AttachmentPending  = "pending"  // presigned upload issued, not yet confirmed
+	AttachmentUploaded = "uploaded" // object verified in storage
+)
+
+// Attachment is a file stored alongside a task. The file itself lives in
+// object storage under StorageKey.
+type Attachment struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TaskID      uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID      uuid.UUID  `json:"user_id" db:"user_id"`
+	Filename    string     `json:"filename" db:"filename"`
+	ContentType string     `json:"content_type" db:"content_type"`
+	SizeBytes   int64      `json:"size_bytes" db:"size_bytes"`
+	StorageKey  string     `json:"-" db:"storage_key"`
+	Status      string     `json:"status" db:"status"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UploadedAt  *time.Time `json:"uploaded_at,omitempty" db:"uploaded_at"`
+}
+
+// CreateAttachmentRequest describes a file the client is about to upload.
+type CreateAttachmentRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"required,max=255"`
+	SizeBytes   int64  `json:"size_bytes" validate:"required,min=1"`
+}
+
+// PresignedURL is a request the client sends directly to object storage.
+type PresignedURL struct {
+	Method    string            `json:"method"`
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"` // must be sent exactly as given
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// AttachmentUpload is the response to creating an attachment: the pending
+// record and where to PUT the file.
+type AttachmentUpload struct {
+	Attachment *Attachment   `json:"attachment"`
+	Upload     *PresignedURL `json:"upload"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "github.com/google/uuid"
+
+// Autocomplete suggestion types.
+const (
+	AutocompleteProject = "project"
+	AutocompleteTag     = "tag"
+)
+
+// Suggestion is one autocomplete result.
+type Suggestion struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Automation triggers. They reuse the task event names that fire them.
+const (
+	AutomationTriggerCompleted = EventTaskCompleted
+	AutomationTriggerOverdue   = EventTaskOverdue
+	AutomationTriggerMoved     = EventTaskMoved
+)
+
+// Automation action types.
+const (
+	AutomationSetPriority = "set_priority"
+	AutomationMoveProject = "move_project"
+	AutomationAddTag      = "add_tag"
+	AutomationNotify      = "notify"
+)
+
+// Automation execution outcomes.
+const (
+	AutomationExecSucceeded = "succeeded"
+	AutomationExecFailed    = "failed"
+	AutomationExecQueued    = "queued"
+	AutomationExecSkipped   = "skipped" // circuit breaker open
+)
+
+// AutomationRule runs its actions on a task whenever Trigger fires for it and
+// the task matches Condition. Async rules run on the background job queue
+// instead of inside the request that fired them.
+type AutomationRule struct {
+	ID        uuid.UUID           `json:"id" db:"id"`
+	UserID    uuid.UUID           `json:"user_id" db:"user_id"`
+	Name      string              `json:"name" db:"name"`
+	Enabled   bool                `json:"enabled" db:"enabled"`
+	Trigger   string              `json:"trigger" db:"trigger"`
+	Condition AutomationCondition `json:"condition" db:"condition"`
+	Actions   AutomationActions   `json:"actions" db:"actions"`
+	Async     bool                `json:"async" db:"async"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+	CircuitBreaker
+}
+
+// AutomationCondition narrows which tasks a rule applies to. Empty fields
+// match anything; all set fields must match.
+type AutomationCondition struct {
+	ProjectID     *uuid.UUID    `json:"project_id,omitempty"`
+	Priority      *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+	TitleContains string        `json:"title_contains,omitempty" validate:"max=100"` // case-insensitive
+}
+
+// AutomationAction is one step a rule performs. Which fields are required
+// depends on Type.
+type AutomationAction struct {
+	Type      string        `json:"type" validate:"required,oneof=set_priority move_project add_tag notify"`
+	Priority  *TaskPriority `json:"priority,omitempty" validate:"required_if=Type set_priority,omitempty,task_priority"`
+	ProjectID *uuid.UUID    `json:"project_id,omitempty" validate:"required_if=Type move_project"`
+	TagID     *uuid.UUID    `json:"tag_id,omitempty" validate:"required_if=Type add_tag"`
+	Message   string        `json:"message,omitempty" validate:"max=200"` // notify only; defaults to the rule name
+}
+
+// AutomationActions is the ordered action list stored as JSONB.
+type AutomationActions []AutomationAction
+
+// AutomationExecution records one evaluation of a rule against a task, for
+// debugging rules after the fact.
+type AutomationExecution struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	RuleID    uuid.UUID `json:"rule_id" db:"rule_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	TaskID    uuid.UUID `json:"task_id" db:"task_id"`
+	Trigger   string    `json:"trigger" db:"trigger"`
+	Status    string    `json:"status" db:"status"`
+	Applied   int       `json:"actions_applied" db:"actions_applied"`
+	Error     *string   `json:"error,omitempty" db:"error"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateAutomationRuleRequest is the payload for creating an automation rule.
+type CreateAutomationRuleRequest struct {
+	Name      string              `json:"name" validate:"required,min=1,max=100"`
+	Enabled   *bool               `json:"enabled"` // default true
+	Trigger   string              `json:"trigger" validate:"required,oneof=task.completed task.overdue task.moved"`
+	Condition AutomationCondition `json:"condition"`
+	Actions   []AutomationAction  `json:"actions" validate:"required,min=1,max=10,dive"`
+	Async     bool                `json:"async"`
+}
+
+// UpdateAutomationRuleRequest is the payload for updating an automation rule;
+// condition and actions are replaced as a whole.
+type UpdateAutomationRuleRequest struct {
+	Name      *string              `json:"name" validate:"omitempty,min=1,max=100"`
+	Enabled   *bool                `json:"enabled"`
+	Trigger   *string              `json:"trigger" validate:"omitempty,oneof=task.completed task.overdue task.moved"`
+	Condition *AutomationCondition `json:"condition"`
+	Actions   []AutomationAction   `json:"actions" validate:"omitempty,min=1,max=10,dive"`
+	Async     *bool                `json:"async"`
+}
+
+// Matches reports whether task satisfies the condition.
+func (c AutomationCondition) Matches(task *Task) bool {
+	if c.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *c.ProjectID) {
+		return false
+	}
+	if c.Priority != nil && *c.Priority != task.Priority {
+		return false
+	}
+	if c.TitleContains != "" && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(c.TitleContains)) {
+		return false
+	}
+	return true
+}
+
+// Value implements driver.Valuer, storing the condition as JSONB.
+func (c AutomationCondition) Value() (driver.Value, error) { return json.Marshal(c) }
+
+// Scan implements sql.Scanner.
+func (c *AutomationCondition) Scan(src any) error { return scanJSON(src, c) }
+
+// Value implements driver.Valuer, storing the actions as JSONB.
+func (a AutomationActions) Value() (driver.Value, error) { return json.Marshal(a) }
+
+// Scan implements sql.Scanner.
+func (a *AutomationActions) Scan(src any) error { return scanJSON(src, a) }
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// What started a backup.
+const (
+	BackupManual     = "manual"
+	BackupScheduled  = "scheduled"
+	BackupPreRestore = "pre_restore" // taken automatically before a restore
+)
+
+// Backup statuses.
+const (
+	BackupRunning   = "running"
+	BackupSucceeded = "succeeded"
+	BackupFailed    = "failed"
+)
+
+// Backup is a logical snapshot of the database kept in object storage.
+type Backup struct {
+	ID         uuid.UUID  `json:"id" db:"id"`
+	Kind       string     `json:"kind" db:"kind"`
+	Status     string     `json:"status" db:"status"`
+	StorageKey string     `json:"-" db:"storage_key"`
+	SizeBytes  int64      `json:"size_bytes" db:"size_bytes"`
+	Checksum   string     `json:"checksum,omitempty" db:"checksum"` // hex SHA-256 of the stored file
+	RowCount   int64      `json:"row_count" db:"row_count"`
+	Error      *string    `json:"error,omitempty" db:"error"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	RestoredAt *time.Time `json:"restored_at,omitempty" db:"restored_at"`
+}
+
+// BackupTable is one table's rows as held in a backup, each row a JSON
+// object keyed by column.
+type BackupTable struct {
+	Name    string
+	Columns []string
+	Rows    []json.RawMessage
+}
+
+// RestoreBackupRequest confirms a restore by repeating the backup's id.
+type RestoreBackupRequest struct {
+	Confirm string `json:"confirm"`
+}
+
+// BackupRestoreResult reports the rows a restore wrote, or would write on a
+// dry run, by table.
+type BackupRestoreResult struct {
+	BackupID uuid.UUID      `json:"backup_id"`
+	DryRun   bool           `json:"dry_run"`
+	Tables   map[string]int `json:"tables"`
+	Total    int            `json:"total"`
+	// SafetyBackupID is the backup taken of the data the restore replaced.
+	SafetyBackupID *uuid.UUID `json:"safety_backup_id,omitempty"`
+}
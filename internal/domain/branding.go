@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// DKIM setup states of a custom email domain.
+const (
+	DKIMUnconfigured = "unconfigured" // no custom domain is set
+	DKIMPending      = "pending"      // the key is not published yet
+	DKIMVerified     = "verified"
+	DKIMFailed       = "failed" // a record was found but holds no key
+)
+
+// WorkspaceBranding is how this deployment, the one workspace it serves,
+// presents itself in emails, shared calendar feeds and printed exports.
+// Empty fields fall back to the BRAND_* and MAIL_* settings.
+type WorkspaceBranding struct {
+	Name        string `json:"name" db:"name"`
+	LogoURL     string `json:"logo_url" db:"logo_url"`
+	AccentColor string `json:"accent_color" db:"accent_color"`
+	// EmailFromDomain, once its DKIM key is verified, replaces the domain
+	// of the configured From address.
+	EmailFromDomain string     `json:"email_from_domain" db:"email_from_domain"`
+	DKIMSelector    string     `json:"dkim_selector" db:"dkim_selector"`
+	DKIMStatus      string     `json:"dkim_status" db:"dkim_status"`
+	DKIMCheckedAt   *time.Time `json:"dkim_checked_at,omitempty" db:"dkim_checked_at"`
+	// DKIMRecord is the DNS name the selector's public key is published at.
+	DKIMRecord string    `json:"dkim_record,omitempty" db:"-"`
+	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateWorkspaceBrandingRequest changes the workspace branding; omitted
+// fields are left as they are and an empty string restores the default.
+type UpdateWorkspaceBrandingRequest struct {
+	Name            *string `json:"name" validate:"omitempty,max=100"`
+	LogoURL         *string `json:"logo_url" validate:"omitempty,max=2048,url"`
+	AccentColor     *string `json:"accent_color" validate:"omitempty,hexcolor"`
+	EmailFromDomain *string `json:"email_from_domain" validate:"omitempty,max=253,fqdn"`
+	DKIMSelector    *string `json:"dkim_selector" validate:"omitempty,max=63,hostname"`
+}
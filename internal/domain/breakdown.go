@@ -0,0 +1,27 @@
+package domain
+
+import "github.com/google/uuid"
+
+// SubtaskSuggestion is one proposed step of a task breakdown.
+type SubtaskSuggestion struct {
+	Title          string   `json:"title" validate:"required,min=1,max=255"`
+	Description    string   `json:"description" validate:"max=5000"`
+	EstimatedHours *float64 `json:"estimated_hours,omitempty" validate:"omitempty,min=0,max=999"`
+}
+
+// TaskBreakdown is a proposed split of a task into subtasks. Nothing is
+// created until the suggestions are accepted.
+type TaskBreakdown struct {
+	TaskID   uuid.UUID           `json:"task_id"`
+	Provider string              `json:"provider"`
+	Subtasks []SubtaskSuggestion `json:"subtasks"`
+	// TotalEstimatedHours is the sum of the subtask estimates. When the task
+	// has an estimate, the split is scaled to match it.
+	TotalEstimatedHours float64 `json:"total_estimated_hours"`
+}
+
+// AcceptBreakdownRequest creates the chosen, possibly edited, suggestions as
+// subtasks.
+type AcceptBreakdownRequest struct {
+	Subtasks []SubtaskSuggestion `json:"subtasks" validate:"required,min=1,max=10,dive"`
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BusinessCalendar is a user's working week: which weekdays are the weekend,
+// which country's public holidays they observe, their working hours, and
+// whether due dates falling on a day off are moved to the next business day.
+type BusinessCalendar struct {
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Country  *string   `json:"country" db:"country"`   // holiday calendar code, e.g. "US"; nil for none
+	Timezone string    `json:"timezone" db:"timezone"` // IANA name the days are counted in
+	// WeekendDays is a bitmask like QuietHours.QuietDays.
+	WeekendDays int      `json:"-" db:"weekend_days"`
+	Weekend     []string `json:"weekend_days" db:"-"`
+	// ShiftDueDates moves the due date of new tasks, and of each new
+	// occurrence of a recurring task, off weekends, holidays and days off.
+	ShiftDueDates bool `json:"shift_due_dates" db:"shift_due_dates"`
+	// WorkStart and WorkEnd are the local "HH:MM" working hours of a
+	// business day.
+	WorkStart string `json:"work_start" db:"work_start"`
+	WorkEnd   string `json:"work_end" db:"work_end"`
+	// WorkingTimeOnly counts only working hours when measuring how overdue a
+	// task is, workload and free slots, rather than every hour of the clock.
+	WorkingTimeOnly bool      `json:"working_time_only" db:"working_time_only"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// DefaultWeekendDays is Saturday and Sunday.
+const DefaultWeekendDays = 1<<time.Saturday | 1<<time.Sunday
+
+// Default working hours of a business day.
+const (
+	DefaultWorkStart = "09:00"
+	DefaultWorkEnd   = "17:00"
+)
+
+// WorkingHours returns WorkStart and WorkEnd as offsets from local midnight.
+// ok is false when they are unset, malformed or not in order.
+func (c *BusinessCalendar) WorkingHours() (start, end time.Duration, ok bool) {
+	s, err1 := time.Parse("15:04", c.WorkStart)
+	e, err2 := time.Parse("15:04", c.WorkEnd)
+	if err1 != nil || err2 != nil || !e.After(s) {
+		return 0, 0, false
+	}
+	midnight := time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+	return s.Sub(midnight), e.Sub(midnight), true
+}
+
+// UpdateBusinessCalendarRequest is the payload for PUT /me/calendar.
+type UpdateBusinessCalendarRequest struct {
+	Country       *string  `json:"country" validate:"omitempty,len=2,alpha"`
+	Timezone      string   `json:"timezone" validate:"required,timezone"`
+	WeekendDays   []string `json:"weekend_days" validate:"max=6,unique,dive,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	ShiftDueDates bool     `json:"shift_due_dates"`
+	WorkStart     string   `json:"work_start" validate:"omitempty,datetime=15:04"` // default 09:00
+	WorkEnd       string   `json:"work_end" validate:"omitempty,datetime=15:04"`   // default 17:00, after work_start
+	// WorkingTimeOnly switches overdue time, workload and slots to working hours.
+	WorkingTimeOnly bool `json:"working_time_only"`
+}
+
+// DayOff is a user-defined day off, such as a vacation day, treated like a
+// public holiday.
+type DayOff struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Date      string    `json:"date" db:"date"` // YYYY-MM-DD
+	Name      string    `json:"name" db:"name"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// CreateDayOffRequest is the payload for adding a day off.
+type CreateDayOffRequest struct {
+	Date string `json:"date" validate:"required,datetime=2006-01-02"`
+	Name string `json:"name" validate:"max=100"`
+}
+
+// TimeSlot is a stretch of time suggested for working on a task.
+type TimeSlot struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// TaskSchedule is where a task stands against the user's clock: working
+// hours only when their calendar asks for it, every hour otherwise.
+type TaskSchedule struct {
+	TaskID          uuid.UUID  `json:"task_id"`
+	WorkingTimeOnly bool       `json:"working_time_only"`
+	Deadline        *time.Time `json:"deadline"` // nil without a due date
+	Overdue         bool       `json:"overdue"`
+	OverdueHours    float64    `json:"overdue_hours"`
+	HoursLeft       float64    `json:"hours_left"` // until the deadline
+	SlotHours       float64    `json:"slot_hours"` // the remaining estimate, or 1 without one
+	Slots           []TimeSlot `json:"slots"`      // earliest first, finishing by the deadline
+}
+
+// Workload compares the open work due by the end of a window with the time
+// available in it.
+type Workload struct {
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	WorkingTimeOnly bool      `json:"working_time_only"`
+	AvailableHours  float64   `json:"available_hours"`
+	// PlannedHours is the remaining estimate (estimate less tracked time) of
+	// open tasks due by To, overdue ones included; a task with estimated
+	// subtasks counts their open estimate instead.
+	PlannedHours       float64 `json:"planned_hours"`
+	Tasks              int     `json:"tasks"`
+	UnestimatedTasks   int     `json:"unestimated_tasks"`
+	UtilizationPercent float64 `json:"utilization_percent"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CalendarFeed is a user's iCalendar subscription. Token is the secret in
+// the feed URL, so anyone holding the URL can read the feed until it is
+// rotated or revoked.
+type CalendarFeed struct {
+	UserID    uuid.UUID `json:"-" db:"user_id"`
+	Token     string    `json:"-" db:"token"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// URL is the subscription address; set when the feed is created.
+	URL string `json:"url,omitempty" db:"-"`
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChangelogEntry is one release note shown in clients' "what's new" dialog.
+// Entries with a future PublishedAt stay hidden until then.
+type ChangelogEntry struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	Version     string     `json:"version" db:"version"`
+	Title       string     `json:"title" db:"title"`
+	Body        string     `json:"body" db:"body"`
+	PublishedAt time.Time  `json:"published_at" db:"published_at"`
+	CreatedBy   *uuid.UUID `json:"-" db:"created_by"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	// Unread is computed per user: published since they last marked the
+	// changelog seen, or since they signed up.
+	Unread bool `json:"unread" db:"-"`
+}
+
+// CreateChangelogRequest is the payload for publishing a changelog entry.
+type CreateChangelogRequest struct {
+	Version     string     `json:"version" validate:"required,max=32"`
+	Title       string     `json:"title" validate:"required,min=1,max=200"`
+	Body        string     `json:"body" validate:"max=20000"` // Markdown
+	PublishedAt *time.Time `json:"published_at"`              // default now
+}
+
+// ChangelogFeed is the changelog as seen by one user, newest version first.
+type ChangelogFeed struct {
+	Entries       []*ChangelogEntry `json:"entries"`
+	UnreadCount   int               `json:"unread_count"`
+	LatestVersion string            `json:"latest_version,omitempty"`
+}
+
+// Version is a MAJOR.MINOR.PATCH release number.
+type Version [3]int
+
+// ParseVersion parses "1.4.2", with an optional leading "v". Missing minor
+// and patch numbers count as zero.
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "v"), ".")
+	if len(parts) > 3 {
+		return v, fmt.Errorf("version %q has more than three parts", s)
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("version %q is not MAJOR.MINOR.PATCH", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// String formats the version as MAJOR.MINOR.PATCH.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v[0], v[1], v[2])
+}
+
+// After reports whether v is a later release than o.
+func (v Version) After(o Version) bool {
+	for i := range v {
+		if v[i] != o[i] {
+			return v[i] > o[i]
+		}
+	}
+	return false
+}
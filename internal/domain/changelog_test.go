@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	v, err := domain.ParseVersion("v1.10")
+	require.NoError(t, err)
+	assert.Equal(t, "1.10.0", v.String())
+
+	for _, bad := range []string{"", "1.x", "1.2.3.4", "1.-2"} {
+		_, err := domain.ParseVersion(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestVersion_After(t *testing.T) {
+	parse := func(s string) domain.Version {
+		v, err := domain.ParseVersion(s)
+		require.NoError(t, err)
+		return v
+	}
+	assert.True(t, parse("1.10.0").After(parse("1.9.9")), "numbers compare numerically")
+	assert.True(t, parse("2.0.0").After(parse("1.99.0")))
+	assert.False(t, parse("1.2.0").After(parse("1.2")))
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// CircuitBreaker is the failure state of something that runs unattended,
+// such as an automation rule or a webhook. After enough consecutive failures
+// the breaker opens and runs are skipped until OpenUntil; the next run after
+// that is a trial, which closes the breaker on success or reopens it.
+type CircuitBreaker struct {
+	Failures  int        `json:"consecutive_failures" db:"consecutive_failures"`
+	OpenUntil *time.Time `json:"breaker_open_until,omitempty" db:"breaker_open_until"`
+}
+
+// IsOpen reports whether runs should be skipped at now.
+func (b CircuitBreaker) IsOpen(now time.Time) bool {
+	return b.OpenUntil != nil && now.Before(*b.OpenUntil)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientError is one error or crash reported by a frontend, after PII
+// scrubbing. Reports with the same Fingerprint are the same bug.
+type ClientError struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	UserID      *uuid.UUID `json:"user_id,omitempty" db:"user_id"` // nil when reported before sign-in
+	Fingerprint string     `json:"fingerprint" db:"fingerprint"`
+	Kind        string     `json:"kind,omitempty" db:"kind"`
+	Message     string     `json:"message" db:"message"`
+	Stack       string     `json:"stack,omitempty" db:"stack"`
+	URL         string     `json:"url,omitempty" db:"url"`
+	// RequestID is the X-Request-ID of the API call that led to the error,
+	// matching the server's request log.
+	RequestID     string             `json:"request_id,omitempty" db:"request_id"`
+	ClientVersion string             `json:"client_version,omitempty" db:"client_version"`
+	Platform      string             `json:"platform,omitempty" db:"platform"`
+	UserAgent     string             `json:"user_agent,omitempty" db:"user_agent"`
+	Context       ClientErrorContext `json:"context,omitempty" db:"context"`
+	OccurredAt    time.Time          `json:"occurred_at" db:"occurred_at"`
+	ReceivedAt    time.Time          `json:"received_at" db:"received_at"`
+}
+
+// ClientErrorContext is free-form key/value detail attached by the client,
+// such as the screen or feature flag state.
+type ClientErrorContext map[string]string
+
+// Value implements driver.Valuer, storing the context as JSONB.
+func (c ClientErrorContext) Value() (driver.Value, error) { return json.Marshal(c) }
+
+// Scan implements sql.Scanner.
+func (c *ClientErrorContext) Scan(src any) error { return scanJSON(src, c) }
+
+// ClientErrorBatch is the payload of POST /telemetry/errors.
+type ClientErrorBatch struct {
+	ClientVersion string              `json:"client_version" validate:"max=64"`
+	Platform      string              `json:"platform" validate:"max=100"`
+	Errors        []ClientErrorReport `json:"errors" validate:"required,min=1,max=50,dive"`
+}
+
+// ClientErrorReport is one error within a batch.
+type ClientErrorReport struct {
+	Kind       string             `json:"kind" validate:"max=100"` // e.g. TypeError
+	Message    string             `json:"message" validate:"required,min=1,max=2000"`
+	Stack      string             `json:"stack" validate:"max=16000"`
+	URL        string             `json:"url" validate:"max=2000"` // page or route the error happened on
+	RequestID  string             `json:"request_id" validate:"max=64"`
+	OccurredAt time.Time          `json:"occurred_at" validate:"required"`
+	Context    ClientErrorContext `json:"context" validate:"max=20,dive,keys,min=1,max=50,endkeys,max=500"`
+}
+
+// ClientErrorIngestResult reports how many errors of a batch were stored.
+type ClientErrorIngestResult struct {
+	Accepted int `json:"accepted"`
+}
+
+// ClientErrorFilter narrows the admin client error list.
+type ClientErrorFilter struct {
+	Fingerprint   string
+	RequestID     string
+	ClientVersion string
+	UserID        *uuid.UUID
+	Page          int
+	Limit         int
+}
+
+// ClientErrorGroup summarises the reports sharing a fingerprint.
+type ClientErrorGroup struct {
+	Fingerprint   string    `json:"fingerprint" db:"fingerprint"`
+	Kind          string    `json:"kind,omitempty" db:"kind"`
+	Message       string    `json:"message" db:"message"` // of the latest report
+	Count         int       `json:"count" db:"count"`
+	Users         int       `json:"users" db:"users"`
+	ClientVersion string    `json:"client_version,omitempty" db:"client_version"` // of the latest report
+	FirstSeen     time.Time `json:"first_seen" db:"first_seen"`
+	LastSeen      time.Time `json:"last_seen" db:"last_seen"`
+}
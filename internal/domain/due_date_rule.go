@@ -0,0 +1,167 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Due-date rule actions.
+const (
+	DueToday       = "today"
+	DueTomorrow    = "tomorrow"
+	DueNextWeekday = "next_weekday" // the coming Weekday, today included
+	DueInDays      = "in_days"
+)
+
+// defaultDueAt is the local time of day a rule sets when At is empty.
+const defaultDueAt = "17:00"
+
+// DueDateRule fills in the due date of new tasks created without one. A
+// user's enabled rules are tried in Position order and the first match wins.
+type DueDateRule struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	UserID    uuid.UUID        `json:"user_id" db:"user_id"`
+	Name      string           `json:"name" db:"name"`
+	Enabled   bool             `json:"enabled" db:"enabled"`
+	Position  int              `json:"position" db:"position"`
+	Timezone  string           `json:"timezone" db:"timezone"` // IANA name used for clock times and days
+	Condition DueDateCondition `json:"condition" db:"condition"`
+	Action    DueDateAction    `json:"action" db:"action"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// DueDateCondition is what a new task must satisfy for a rule to apply.
+// Empty fields match anything; all set fields must match.
+type DueDateCondition struct {
+	// CreatedAfter and CreatedBefore bound the local creation time of day
+	// ("HH:MM"). After later than before means the window spans midnight.
+	CreatedAfter  string        `json:"created_after,omitempty" validate:"omitempty,datetime=15:04"`
+	CreatedBefore string        `json:"created_before,omitempty" validate:"omitempty,datetime=15:04"`
+	Weekdays      []string      `json:"weekdays,omitempty" validate:"omitempty,dive,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	ProjectID     *uuid.UUID    `json:"project_id,omitempty"`
+	ProjectName   string        `json:"project_name,omitempty" validate:"max=100"` // case-insensitive
+	Priority      *TaskPriority `json:"priority,omitempty" validate:"omitempty,task_priority"`
+}
+
+// DueDateAction is the due date a matching rule assigns.
+type DueDateAction struct {
+	Due     string `json:"due" validate:"required,oneof=today tomorrow next_weekday in_days"`
+	Weekday string `json:"weekday,omitempty" validate:"required_if=Due next_weekday,omitempty,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+	Days    int    `json:"days,omitempty" validate:"min=0,max=365"`
+	At      string `json:"at,omitempty" validate:"omitempty,datetime=15:04"` // local time of day, default 17:00
+}
+
+// CreateDueDateRuleRequest is the payload for creating a due-date rule.
+type CreateDueDateRuleRequest struct {
+	Name      string           `json:"name" validate:"required,min=1,max=100"`
+	Enabled   *bool            `json:"enabled"` // default true
+	Position  int              `json:"position" validate:"min=0"`
+	Timezone  string           `json:"timezone" validate:"required,timezone"`
+	Condition DueDateCondition `json:"condition"`
+	Action    DueDateAction    `json:"action"`
+}
+
+// UpdateDueDateRuleRequest is the payload for updating a due-date rule;
+// condition and action are replaced as a whole.
+type UpdateDueDateRuleRequest struct {
+	Name      *string           `json:"name" validate:"omitempty,min=1,max=100"`
+	Enabled   *bool             `json:"enabled"`
+	Position  *int              `json:"position" validate:"omitempty,min=0"`
+	Timezone  *string           `json:"timezone" validate:"omitempty,timezone"`
+	Condition *DueDateCondition `json:"condition"`
+	Action    *DueDateAction    `json:"action"`
+}
+
+// Matches reports whether a task created at now (in the rule's time zone)
+// satisfies the condition. projectName is the task's project name, if any.
+func (c DueDateCondition) Matches(task *Task, projectName string, now time.Time) bool {
+	if c.ProjectID != nil && (task.ProjectID == nil || *task.ProjectID != *c.ProjectID) {
+		return false
+	}
+	if c.ProjectName != "" && !strings.EqualFold(c.ProjectName, projectName) {
+		return false
+	}
+	if c.Priority != nil && *c.Priority != task.Priority {
+		return false
+	}
+	if len(c.Weekdays) > 0 {
+		mask, err := ParseWeekdays(c.Weekdays)
+		if err != nil || mask&(1<<now.Weekday()) == 0 {
+			return false
+		}
+	}
+
+	clock := now.Format("15:04")
+	switch after, before := c.CreatedAfter, c.CreatedBefore; {
+	case after != "" && before != "" && after > before:
+		return clock >= after || clock < before
+	case after != "" && clock < after:
+		return false
+	case before != "" && clock >= before:
+		return false
+	}
+	return true
+}
+
+// DueDate computes the due date for a task created at now, in now's location.
+func (a DueDateAction) DueDate(now time.Time) (time.Time, error) {
+	at := a.At
+	if at == "" {
+		at = defaultDueAt
+	}
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("due-date action: invalid time %q", at)
+	}
+
+	day := now
+	switch a.Due {
+	case DueToday:
+	case DueTomorrow:
+		day = now.AddDate(0, 0, 1)
+	case DueInDays:
+		day = now.AddDate(0, 0, a.Days)
+	case DueNextWeekday:
+		mask, err := ParseWeekdays([]string{a.Weekday})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("due-date action: %w", err)
+		}
+		for mask&(1<<day.Weekday()) == 0 {
+			day = day.AddDate(0, 0, 1)
+		}
+	default:
+		return time.Time{}, fmt.Errorf("due-date action: unknown due %q", a.Due)
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location()), nil
+}
+
+// Value implements driver.Valuer, storing the condition as JSONB.
+func (c DueDateCondition) Value() (driver.Value, error) { return json.Marshal(c) }
+
+// Scan implements sql.Scanner.
+func (c *DueDateCondition) Scan(src any) error { return scanJSON(src, c) }
+
+// Value implements driver.Valuer, storing the action as JSONB.
+func (a DueDateAction) Value() (driver.Value, error) { return json.Marshal(a) }
+
+// Scan implements sql.Scanner.
+func (a *DueDateAction) Scan(src any) error { return scanJSON(src, a) }
+
+func scanJSON(src, dst any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, dst)
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+	case nil:
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T as JSON", src)
+	}
+}
@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueDateCondition_Matches(t *testing.T) {
+	evening := time.Date(2026, 10, 14, 19, 30, 0, 0, time.UTC) // Wednesday
+	morning := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	projectID := uuid.New()
+	high := domain.TaskPriorityHigh
+	task := &domain.Task{ProjectID: &projectID, Priority: domain.TaskPriorityLow}
+
+	tests := []struct {
+		name string
+		cond domain.DueDateCondition
+		now  time.Time
+		want bool
+	}{
+		{"empty matches", domain.DueDateCondition{}, morning, true},
+		{"after 18:00", domain.DueDateCondition{CreatedAfter: "18:00"}, evening, true},
+		{"not after 18:00", domain.DueDateCondition{CreatedAfter: "18:00"}, morning, false},
+		{"window over midnight", domain.DueDateCondition{CreatedAfter: "22:00", CreatedBefore: "06:00"}, evening, false},
+		{"weekday", domain.DueDateCondition{Weekdays: []string{"wednesday"}}, morning, true},
+		{"other weekday", domain.DueDateCondition{Weekdays: []string{"monday"}}, morning, false},
+		{"project name ignores case", domain.DueDateCondition{ProjectName: "errands"}, morning, true},
+		{"project id", domain.DueDateCondition{ProjectID: &projectID}, morning, true},
+		{"priority", domain.DueDateCondition{Priority: &high}, morning, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cond.Matches(task, "Errands", tt.now))
+		})
+	}
+}
+
+func TestDueDateAction_DueDate(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	now := time.Date(2026, 10, 14, 19, 30, 0, 0, jakarta) // Wednesday
+
+	tests := []struct {
+		action domain.DueDateAction
+		want   time.Time
+	}{
+		{domain.DueDateAction{Due: domain.DueTomorrow}, time.Date(2026, 10, 15, 17, 0, 0, 0, jakarta)},
+		{domain.DueDateAction{Due: domain.DueToday, At: "23:00"}, time.Date(2026, 10, 14, 23, 0, 0, 0, jakarta)},
+		{domain.DueDateAction{Due: domain.DueNextWeekday, Weekday: "saturday", At: "10:00"}, time.Date(2026, 10, 17, 10, 0, 0, 0, jakarta)},
+		{domain.DueDateAction{Due: domain.DueNextWeekday, Weekday: "wednesday"}, time.Date(2026, 10, 14, 17, 0, 0, 0, jakarta)},
+		{domain.DueDateAction{Due: domain.DueInDays, Days: 3}, time.Date(2026, 10, 17, 17, 0, 0, 0, jakarta)},
+	}
+	for _, tt := range tests {
+		got, err := tt.action.DueDate(now)
+		require.NoError(t, err)
+		assert.True(t, tt.want.Equal(got), "%+v: got %s", tt.action, got)
+	}
+}
@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueExprExamples shows the supported due expression forms. Any of them may
+// end in "at HH:MM" to set the local time of day.
+var DueExprExamples = []string{
+	"today", "tomorrow", "next friday",
+	"in 3 days", "3 days after creation", "2 weeks after creation", "in 1 month",
+	"end of day", "end of week", "end of next week", "end of month", "end of next month", "end of year",
+}
+
+type dueExprKind int
+
+const (
+	dueExprToday dueExprKind = iota
+	dueExprTomorrow
+	dueExprNextWeekday
+	dueExprOffset
+	dueExprEndOf
+)
+
+// DueExpr is a parsed symbolic due date such as "3 days after creation" or
+// "end of month", resolved against the moment a task or an occurrence of it
+// starts. Parse one with ParseDueExpr.
+type DueExpr struct {
+	kind    dueExprKind
+	n       int    // offset amount, or 1 for "end of next ..."
+	unit    string // day, week, month or year
+	weekday time.Weekday
+	at      string // "HH:MM", empty to keep the default
+}
+
+// ParseDueExpr parses a due expression, ignoring case and extra spaces. The
+// error names the supported forms.
+func ParseDueExpr(s string) (DueExpr, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	var e DueExpr
+	if n := len(fields); n >= 2 && fields[n-2] == "at" {
+		if _, err := time.Parse("15:04", fields[n-1]); err != nil {
+			return e, fmt.Errorf("due expression %q: time must be HH:MM", s)
+		}
+		e.at = fields[n-1]
+		fields = fields[:n-2]
+	}
+
+	ok := false
+	switch {
+	case len(fields) == 1 && fields[0] == "today":
+		e.kind, ok = dueExprToday, true
+	case len(fields) == 1 && fields[0] == "tomorrow":
+		e.kind, ok = dueExprTomorrow, true
+	case len(fields) == 2 && fields[0] == "next":
+		mask, err := ParseWeekdays(fields[1:])
+		if err == nil {
+			e.kind, ok = dueExprNextWeekday, true
+			for d := time.Sunday; d <= time.Saturday; d++ {
+				if mask&(1<<d) != 0 {
+					e.weekday = d
+				}
+			}
+		}
+	case len(fields) == 3 && fields[0] == "in":
+		e.kind = dueExprOffset
+		e.n, e.unit, ok = parseDueAmount(fields[1], fields[2])
+	case len(fields) == 4 && fields[2] == "after" && fields[3] == "creation":
+		e.kind = dueExprOffset
+		e.n, e.unit, ok = parseDueAmount(fields[0], fields[1])
+	case len(fields) == 3 && fields[0] == "end" && fields[1] == "of":
+		e.kind, e.unit = dueExprEndOf, fields[2]
+		ok = e.unit == "day" || e.unit == "week" || e.unit == "month" || e.unit == "year"
+	case len(fields) == 4 && fields[0] == "end" && fields[1] == "of" && fields[2] == "next":
+		e.kind, e.unit, e.n = dueExprEndOf, fields[3], 1
+		ok = e.unit == "week" || e.unit == "month" || e.unit == "year"
+	}
+	if !ok {
+		return DueExpr{}, fmt.Errorf("due expression %q not understood; try e.g. %s", s, strings.Join(DueExprExamples, ", "))
+	}
+	return e, nil
+}
+
+// parseDueAmount reads "3" "days" as an offset of at most a few years.
+func parseDueAmount(amount, unit string) (int, string, bool) {
+	n, err := strconv.Atoi(amount)
+	if err != nil || n < 0 || n > 1000 {
+		return 0, "", false
+	}
+	unit = strings.TrimSuffix(unit, "s")
+	if unit != "day" && unit != "week" && unit != "month" {
+		return 0, "", false
+	}
+	return n, unit, true
+}
+
+// Resolve returns the due date the expression gives for a task starting at
+// base, in base's location. Offsets keep the time of day of base; the other
+// forms are date-only (local midnight, due all of that day), with weeks
+// running Monday to Sunday. "at HH:MM" sets the time of day explicitly.
+func (e DueExpr) Resolve(base time.Time) time.Time {
+	day := time.Date(base.Year(), base.Month(), base.Day(), 0, 0, 0, 0, base.Location())
+	var due time.Time
+	switch e.kind {
+	case dueExprToday:
+		due = day
+	case dueExprTomorrow:
+		due = day.AddDate(0, 0, 1)
+	case dueExprNextWeekday:
+		due = day.AddDate(0, 0, 1)
+		for due.Weekday() != e.weekday {
+			due = due.AddDate(0, 0, 1)
+		}
+	case dueExprOffset:
+		switch e.unit {
+		case "week":
+			due = base.AddDate(0, 0, 7*e.n)
+		case "month":
+			due = addMonthsClamped(base, e.n)
+		default:
+			due = base.AddDate(0, 0, e.n)
+		}
+		if e.at == "" {
+			return due
+		}
+	case dueExprEndOf:
+		switch e.unit {
+		case "day":
+			due = day
+		case "week":
+			// Days until Sunday, the last day of an ISO week.
+			due = day.AddDate(0, 0, (7-int(day.Weekday()))%7+7*e.n)
+		case "month":
+			due = time.Date(day.Year(), day.Month()+time.Month(e.n)+1, 0, 0, 0, 0, 0, day.Location())
+		case "year":
+			due = time.Date(day.Year()+e.n, time.December, 31, 0, 0, 0, 0, day.Location())
+		}
+	}
+	if e.at != "" {
+		clock, _ := time.Parse("15:04", e.at)
+		due = time.Date(due.Year(), due.Month(), due.Day(), clock.Hour(), clock.Minute(), 0, 0, due.Location())
+	}
+	return due
+}
+
+// ResolveDueExpr sets the task's due date from its due expression, for an
+// occurrence starting at base, in loc. It does nothing without one.
+func (t *Task) ResolveDueExpr(base time.Time, loc *time.Location) error {
+	if t.DueExpr == nil {
+		return nil
+	}
+	e, err := ParseDueExpr(*t.DueExpr)
+	if err != nil {
+		return err
+	}
+	due := e.Resolve(base.In(loc))
+	t.DueDate = &due
+	return nil
+}
+
+// RollForwardExpr is RollForward for a recurring task with a due
+// expression. Its series counts occurrence starts rather than due dates:
+// the next occurrence starts at the first date of the series after both now
+// and the current start, and is due when the expression resolves from there.
+func (t *Task) RollForwardExpr(now time.Time, loc *time.Location) error {
+	after := now
+	if s := t.Recurrence.Start; s != nil && s.After(after) {
+		after = *s
+	}
+	next := t.Recurrence.Next(after)
+	r := *t.Recurrence
+	r.Start = &next
+	t.Recurrence = &r
+	t.Status = TaskStatusTodo
+	t.CompletedAt = nil
+	return t.ResolveDueExpr(next, loc)
+}
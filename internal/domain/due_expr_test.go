@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDueExpr_Resolve(t *testing.T) {
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	base := time.Date(2026, 1, 29, 14, 30, 0, 0, jkt) // a Thursday
+
+	for expr, want := range map[string]time.Time{
+		"today":                      time.Date(2026, 1, 29, 0, 0, 0, 0, jkt),
+		"Tomorrow at 09:00":          time.Date(2026, 1, 30, 9, 0, 0, 0, jkt),
+		"next thursday":              time.Date(2026, 2, 5, 0, 0, 0, 0, jkt),
+		"in 3 days":                  time.Date(2026, 2, 1, 14, 30, 0, 0, jkt),
+		"3 days after creation":      time.Date(2026, 2, 1, 14, 30, 0, 0, jkt),
+		"1 day after creation":       time.Date(2026, 1, 30, 14, 30, 0, 0, jkt),
+		"2 weeks after creation":     time.Date(2026, 2, 12, 14, 30, 0, 0, jkt),
+		"in 1 month":                 time.Date(2026, 2, 28, 14, 30, 0, 0, jkt),
+		"end of day at 18:00":        time.Date(2026, 1, 29, 18, 0, 0, 0, jkt),
+		"end of week":                time.Date(2026, 2, 1, 0, 0, 0, 0, jkt),
+		"end  of  next  week":        time.Date(2026, 2, 8, 0, 0, 0, 0, jkt),
+		"end of month":               time.Date(2026, 1, 31, 0, 0, 0, 0, jkt),
+		"end of next month at 17:00": time.Date(2026, 2, 28, 17, 0, 0, 0, jkt),
+		"end of year":                time.Date(2026, 12, 31, 0, 0, 0, 0, jkt),
+	} {
+		t.Run(expr, func(t *testing.T) {
+			e, err := domain.ParseDueExpr(expr)
+			require.NoError(t, err)
+			assert.Equal(t, want, e.Resolve(base))
+		})
+	}
+}
+
+func TestParseDueExpr_Rejects(t *testing.T) {
+	for _, expr := range []string{"", "soon", "in three days", "in 3 fortnights", "next funday", "end of decade", "end of next day", "tomorrow at 25:00", "-1 days after creation"} {
+		_, err := domain.ParseDueExpr(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestTask_RollForwardExpr_CountsFromOccurrenceStarts(t *testing.T) {
+	created := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC) // a Monday
+	expr := "end of week"
+	task := &domain.Task{
+		Status:     domain.TaskStatusDone,
+		DueExpr:    &expr,
+		Recurrence: &domain.Recurrence{Frequency: domain.RecurWeekly, Anchor: created, Start: &created},
+	}
+	require.NoError(t, task.ResolveDueExpr(created, time.UTC))
+	assert.Equal(t, time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC), *task.DueDate)
+
+	// Done on Wednesday: the next occurrence starts the following Monday.
+	require.NoError(t, task.RollForwardExpr(time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC), time.UTC))
+	assert.Equal(t, domain.TaskStatusTodo, task.Status)
+	assert.Equal(t, time.Date(2026, 3, 9, 10, 0, 0, 0, time.UTC), *task.Recurrence.Start)
+	assert.Equal(t, time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), *task.DueDate)
+}
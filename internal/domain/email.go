@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// EmailSuppression records an address that must not receive further email,
+// typically because the provider reported a hard bounce or spam complaint.
+type EmailSuppression struct {
+	Email     string    `json:"email" db:"email"`
+	Reason    string    `json:"reason" db:"reason"`
+	Type      string    `json:"type" db:"type"`
+	Provider  string    `json:"provider" db:"provider"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
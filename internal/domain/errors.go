@@ -14,4 +14,17 @@ var (
 	ErrTokenInvalid      = errors.New("token invalid")
 	ErrValidation        = errors.New("validation error")
 	ErrInternal          = errors.New("internal server error")
+	ErrFeatureDisabled   = errors.New("feature disabled")
+	ErrTaskBlocked       = errors.New("task is blocked by open tasks")
+	ErrTimerRunning      = errors.New("a timer is already running")
+	ErrTimerNotRunning   = errors.New("no timer is running")
+	ErrScheduleConflict  = errors.New("time slot overlaps another scheduled task")
+	ErrQuotaExceeded     = errors.New("quota exceeded")
+	ErrInviteRequired    = errors.New("an invite code is required")
+	ErrInviteInvalid     = errors.New("invite code is invalid or used up")
+	ErrPreconditionFailed = errors.New("resource has changed since it was read")
+	ErrUnavailable       = errors.New("temporarily unavailable")
+	ErrProjectNotEmpty   = errors.New("project still has tasks")
+	ErrBackupMismatch    = errors.New("backup does not match this database")
+	ErrTooManyAttempts   = errors.New("too many failed attempts")
 )
@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EscalationPolicy raises the priority of open tasks as their due date
+// nears. A zero window disables that step.
+type EscalationPolicy struct {
+	MediumWithin time.Duration // low tasks due within this become medium
+	HighWithin   time.Duration // low and medium tasks due within this become high
+}
+
+// Enabled reports whether the policy escalates anything.
+func (p EscalationPolicy) Enabled() bool {
+	return p.MediumWithin > 0 || p.HighWithin > 0
+}
+
+// Target returns the priority the policy raises t to at now. ok is false
+// when the task is not escalated: it is done, opted out, has no due date,
+// or already has that priority or a higher one.
+func (p EscalationPolicy) Target(t *Task, now time.Time) (TaskPriority, bool) {
+	if t.DueDate == nil || t.Status == TaskStatusDone || t.NoEscalation {
+		return "", false
+	}
+	until := t.DueDate.Sub(now)
+	switch {
+	case p.HighWithin > 0 && until <= p.HighWithin && t.Priority != TaskPriorityHigh:
+		return TaskPriorityHigh, true
+	case p.MediumWithin > 0 && until <= p.MediumWithin && t.Priority == TaskPriorityLow:
+		return TaskPriorityMedium, true
+	}
+	return "", false
+}
+
+// AgingPolicy makes tasks left in todo gain urgency: first smart score,
+// then optionally priority. Time in todo counts from the task's last status
+// change. A zero field disables that part.
+type AgingPolicy struct {
+	After        time.Duration // time in todo before the score starts to grow
+	PointsPerDay float64       // score gained per day past After
+	MaxPoints    float64       // cap on the score gained
+	MediumAfter  time.Duration // low tasks in todo this long become medium
+	HighAfter    time.Duration // low and medium tasks in todo this long become high
+}
+
+// Enabled reports whether the policy ages anything.
+func (p AgingPolicy) Enabled() bool {
+	return p.ScoresAge() || p.MediumAfter > 0 || p.HighAfter > 0
+}
+
+// ScoresAge reports whether tasks gain score as they age.
+func (p AgingPolicy) ScoresAge() bool {
+	return p.After > 0 && p.PointsPerDay > 0
+}
+
+// Target returns the priority the policy raises t to at now. ok is false
+// when the task is not raised: it is not in todo, opted out of escalation,
+// or already has that priority or a higher one.
+func (p AgingPolicy) Target(t *Task, now time.Time) (TaskPriority, bool) {
+	if t.Status != TaskStatusTodo || t.NoEscalation {
+		return "", false
+	}
+	age := now.Sub(t.StatusChangedAt)
+	switch {
+	case p.HighAfter > 0 && age >= p.HighAfter && t.Priority != TaskPriorityHigh:
+		return TaskPriorityHigh, true
+	case p.MediumAfter > 0 && age >= p.MediumAfter && t.Priority == TaskPriorityLow:
+		return TaskPriorityMedium, true
+	}
+	return "", false
+}
+
+// Escalation reasons: what triggered an automatic priority raise.
+const (
+	EscalationDueDate = "due_date"
+	EscalationAging   = "aging"
+)
+
+// TaskEscalation records one automatic priority raise. A task is escalated
+// to a priority at most once per due date, or once per stint in todo when
+// aging, so lowering it again by hand sticks until that changes.
+type TaskEscalation struct {
+	ID           uuid.UUID    `json:"id" db:"id"`
+	TaskID       uuid.UUID    `json:"task_id" db:"task_id"`
+	UserID       uuid.UUID    `json:"user_id" db:"user_id"`
+	FromPriority TaskPriority `json:"from_priority" db:"from_priority"`
+	ToPriority   TaskPriority `json:"to_priority" db:"to_priority"`
+	Reason       string       `json:"reason" db:"reason"`
+	DueDate      *time.Time   `json:"due_date,omitempty" db:"due_date"` // the due date that triggered it
+	EscalatedAt  time.Time    `json:"escalated_at" db:"escalated_at"`
+}
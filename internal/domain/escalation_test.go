@@ -0,0 +1,72 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscalationPolicy_Target(t *testing.T) {
+	policy := domain.EscalationPolicy{MediumWithin: 72 * time.Hour, HighWithin: 24 * time.Hour}
+	now := time.Date(2026, 3, 4, 9, 0, 0, 0, time.UTC)
+	in := func(d time.Duration) *time.Time { due := now.Add(d); return &due }
+
+	for name, tc := range map[string]struct {
+		task   domain.Task
+		want   domain.TaskPriority
+		raised bool
+	}{
+		"low within the medium window":  {task: domain.Task{Priority: domain.TaskPriorityLow, DueDate: in(48 * time.Hour)}, want: domain.TaskPriorityMedium, raised: true},
+		"low within the high window":    {task: domain.Task{Priority: domain.TaskPriorityLow, DueDate: in(12 * time.Hour)}, want: domain.TaskPriorityHigh, raised: true},
+		"medium within the high window": {task: domain.Task{Priority: domain.TaskPriorityMedium, DueDate: in(12 * time.Hour)}, want: domain.TaskPriorityHigh, raised: true},
+		"overdue":                       {task: domain.Task{Priority: domain.TaskPriorityLow, DueDate: in(-time.Hour)}, want: domain.TaskPriorityHigh, raised: true},
+		"medium in the medium window":   {task: domain.Task{Priority: domain.TaskPriorityMedium, DueDate: in(48 * time.Hour)}},
+		"already high":                  {task: domain.Task{Priority: domain.TaskPriorityHigh, DueDate: in(time.Hour)}},
+		"not due yet":                   {task: domain.Task{Priority: domain.TaskPriorityLow, DueDate: in(96 * time.Hour)}},
+		"no due date":                   {task: domain.Task{Priority: domain.TaskPriorityLow}},
+		"done":                          {task: domain.Task{Priority: domain.TaskPriorityLow, Status: domain.TaskStatusDone, DueDate: in(time.Hour)}},
+		"opted out":                     {task: domain.Task{Priority: domain.TaskPriorityLow, NoEscalation: true, DueDate: in(time.Hour)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, raised := policy.Target(&tc.task, now)
+			assert.Equal(t, tc.raised, raised)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	assert.False(t, domain.EscalationPolicy{}.Enabled())
+	onlyHigh := domain.EscalationPolicy{HighWithin: 24 * time.Hour}
+	_, raised := onlyHigh.Target(&domain.Task{Priority: domain.TaskPriorityLow, DueDate: in(48 * time.Hour)}, now)
+	assert.False(t, raised, "a zero window disables that step")
+}
+
+func TestAgingPolicy_Target(t *testing.T) {
+	policy := domain.AgingPolicy{MediumAfter: 7 * 24 * time.Hour, HighAfter: 14 * 24 * time.Hour}
+	now := time.Date(2026, 3, 20, 9, 0, 0, 0, time.UTC)
+	daysAgo := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	for name, tc := range map[string]struct {
+		task   domain.Task
+		want   domain.TaskPriority
+		raised bool
+	}{
+		"low past the medium threshold":  {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, StatusChangedAt: daysAgo(8)}, want: domain.TaskPriorityMedium, raised: true},
+		"low past the high threshold":    {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, StatusChangedAt: daysAgo(15)}, want: domain.TaskPriorityHigh, raised: true},
+		"medium past the high threshold": {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, StatusChangedAt: daysAgo(15)}, want: domain.TaskPriorityHigh, raised: true},
+		"medium past the medium one":     {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, StatusChangedAt: daysAgo(8)}},
+		"fresh":                          {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, StatusChangedAt: daysAgo(2)}},
+		"in progress":                    {task: domain.Task{Status: domain.TaskStatusInProgress, Priority: domain.TaskPriorityLow, StatusChangedAt: daysAgo(30)}},
+		"opted out":                      {task: domain.Task{Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, NoEscalation: true, StatusChangedAt: daysAgo(30)}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, raised := policy.Target(&tc.task, now)
+			assert.Equal(t, tc.raised, raised)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	assert.False(t, domain.AgingPolicy{}.Enabled())
+	assert.False(t, domain.AgingPolicy{PointsPerDay: 2}.ScoresAge(), "score aging needs a threshold")
+}
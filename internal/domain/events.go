@@ -0,0 +1,97 @@
+package domain
+
+// Domain events are published on the event bus once the change they
+// describe has been persisted. Each family shares an interface, so a
+// subscriber may take one event type or all of a family.
+
+// TaskChange is any change to a task.
+type TaskChange interface {
+	EventName() string
+	EventTask() *Task
+}
+
+// TaskCreated is published for a new task, subtasks included.
+type TaskCreated struct{ Task *Task }
+
+// TaskUpdated is published for every change to a stored task.
+type TaskUpdated struct{ Task *Task }
+
+// TaskCompleted follows the TaskUpdated of a task whose status became done.
+type TaskCompleted struct{ Task *Task }
+
+// TaskMoved follows the TaskUpdated of a task that changed project.
+type TaskMoved struct{ Task *Task }
+
+// TaskDeleted is published when a task goes to the trash.
+type TaskDeleted struct{ Task *Task }
+
+func (TaskCreated) EventName() string   { return EventTaskCreated }
+func (TaskUpdated) EventName() string   { return EventTaskUpdated }
+func (TaskCompleted) EventName() string { return EventTaskCompleted }
+func (TaskMoved) EventName() string     { return EventTaskMoved }
+func (TaskDeleted) EventName() string   { return EventTaskDeleted }
+
+func (e TaskCreated) EventTask() *Task   { return e.Task }
+func (e TaskUpdated) EventTask() *Task   { return e.Task }
+func (e TaskCompleted) EventTask() *Task { return e.Task }
+func (e TaskMoved) EventTask() *Task     { return e.Task }
+func (e TaskDeleted) EventTask() *Task   { return e.Task }
+
+// ProjectChange is any change to a project.
+type ProjectChange interface {
+	EventName() string
+	EventProject() *Project
+}
+
+// ProjectCreated is published for a new project, duplicates included.
+type ProjectCreated struct{ Project *Project }
+
+// ProjectUpdated is published for every change to a stored project,
+// archiving included.
+type ProjectUpdated struct{ Project *Project }
+
+// ProjectDeleted is published when a project is deleted.
+type ProjectDeleted struct{ Project *Project }
+
+func (ProjectCreated) EventName() string { return EventProjectCreated }
+func (ProjectUpdated) EventName() string { return EventProjectUpdated }
+func (ProjectDeleted) EventName() string { return EventProjectDeleted }
+
+func (e ProjectCreated) EventProject() *Project { return e.Project }
+func (e ProjectUpdated) EventProject() *Project { return e.Project }
+func (e ProjectDeleted) EventProject() *Project { return e.Project }
+
+// TagChange is any change to a tag.
+type TagChange interface {
+	EventName() string
+	EventTag() *Tag
+}
+
+// TagCreated is published for a new tag.
+type TagCreated struct{ Tag *Tag }
+
+// TagUpdated is published for a renamed or recoloured tag.
+type TagUpdated struct{ Tag *Tag }
+
+// TagDeleted is published when a tag is deleted.
+type TagDeleted struct{ Tag *Tag }
+
+func (TagCreated) EventName() string { return EventTagCreated }
+func (TagUpdated) EventName() string { return EventTagUpdated }
+func (TagDeleted) EventName() string { return EventTagDeleted }
+
+func (e TagCreated) EventTag() *Tag { return e.Tag }
+func (e TagUpdated) EventTag() *Tag { return e.Tag }
+func (e TagDeleted) EventTag() *Tag { return e.Tag }
+
+// UserRegistered is published for a new account, with what it signed up
+// with: the referral code and source from the request, and the invite it
+// redeemed, if any.
+type UserRegistered struct {
+	User           *User
+	ReferralCode   string
+	ReferralSource string
+	Invite         *InviteCode
+}
+
+func (UserRegistered) EventName() string { return EventUserRegistered }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FeedbackCategory classifies a feedback report.
+type FeedbackCategory string
+
+const (
+	FeedbackBug      FeedbackCategory = "bug"
+	FeedbackIdea     FeedbackCategory = "idea"
+	FeedbackQuestion FeedbackCategory = "question"
+	FeedbackOther    FeedbackCategory = "other"
+)
+
+// FeedbackCategories lists the accepted feedback categories.
+var FeedbackCategories = []FeedbackCategory{FeedbackBug, FeedbackIdea, FeedbackQuestion, FeedbackOther}
+
+// Feedback is a bug report or suggestion sent from a client.
+type Feedback struct {
+	ID            uuid.UUID           `json:"id" db:"id"`
+	UserID        uuid.UUID           `json:"user_id" db:"user_id"`
+	Category      FeedbackCategory    `json:"category" db:"category"`
+	Message       string              `json:"message" db:"message"`
+	ClientVersion string              `json:"client_version,omitempty" db:"client_version"`
+	Diagnostics   FeedbackDiagnostics `json:"diagnostics" db:"diagnostics"`
+	UserAgent     string              `json:"user_agent,omitempty" db:"user_agent"`
+	// ForwardedAt is set once the report reached the issue tracker webhook;
+	// ForwardError holds the last failure while it has not.
+	ForwardedAt  *time.Time `json:"forwarded_at,omitempty" db:"forwarded_at"`
+	ForwardError string     `json:"forward_error,omitempty" db:"forward_error"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// FeedbackDiagnostics is the optional bundle a client attaches to help
+// reproduce a report. RequestIDs are X-Request-ID values of the client's
+// most recent API calls, which can be matched against the server logs.
+type FeedbackDiagnostics struct {
+	RequestIDs []string          `json:"request_ids,omitempty" validate:"max=50,dive,min=1,max=64"`
+	Platform   string            `json:"platform,omitempty" validate:"max=100"`
+	Extra      map[string]string `json:"extra,omitempty" validate:"max=20,dive,keys,max=50,endkeys,max=500"`
+}
+
+// Value implements driver.Valuer, storing the diagnostics as JSONB.
+func (d FeedbackDiagnostics) Value() (driver.Value, error) { return json.Marshal(d) }
+
+// Scan implements sql.Scanner.
+func (d *FeedbackDiagnostics) Scan(src any) error { return scanJSON(src, d) }
+
+// CreateFeedbackRequest is the payload for submitting feedback.
+type CreateFeedbackRequest struct {
+	Category      FeedbackCategory     `json:"category" validate:"required,oneof=bug idea question other"`
+	Message       string               `json:"message" validate:"required,min=1,max=10000"`
+	ClientVersion string               `json:"client_version" validate:"max=64"`
+	Diagnostics   *FeedbackDiagnostics `json:"diagnostics"`
+}
+
+// FeedbackFilter narrows the admin feedback list.
+type FeedbackFilter struct {
+	Category *FeedbackCategory
+	UserID   *uuid.UUID
+	Page     int
+	Limit    int
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Fields is a sparse fieldset: the fields of a resource a client asked for
+// with ?fields=. Field names are both the JSON keys and the columns the
+// repository selects. Nil means every field.
+type Fields []string
+
+// TaskFieldNames and ProjectFieldNames list the fields ?fields= accepts.
+var (
+	TaskFieldNames    = fieldNames(Task{})
+	ProjectFieldNames = fieldNames(Project{})
+)
+
+// ParseFields reads a comma-separated list of field names, each of which
+// must be one of allowed. id is always included, first, so clients can
+// still tell the items apart. An empty list yields nil: every field.
+func ParseFields(raw string, allowed []string) (Fields, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	fields := Fields{"id"}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || slices.Contains(fields, name) {
+			continue
+		}
+		if !slices.Contains(allowed, name) {
+			return nil, fmt.Errorf("unknown field %q: %w", name, ErrValidation)
+		}
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// Has reports whether the fieldset includes name.
+func (f Fields) Has(name string) bool {
+	return f == nil || slices.Contains(f, name)
+}
+
+// fieldNames returns the db tags of a row struct, which match its JSON keys.
+func fieldNames(row any) []string {
+	typ := reflect.TypeOf(row)
+	names := make([]string, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		if tag := typ.Field(i).Tag.Get("db"); tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFields_AlwaysIncludesID(t *testing.T) {
+	fields, err := domain.ParseFields("title, status,,due_date,title", domain.TaskFieldNames)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Fields{"id", "title", "status", "due_date"}, fields)
+	assert.True(t, fields.Has("status"))
+	assert.False(t, fields.Has("description"))
+
+	fields, err = domain.ParseFields("", domain.TaskFieldNames)
+	require.NoError(t, err)
+	assert.Nil(t, fields)
+	assert.True(t, fields.Has("description"), "nil means every field")
+
+	_, err = domain.ParseFields("title,password_hash", domain.TaskFieldNames)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestFieldNames_MatchJSONKeys(t *testing.T) {
+	assert.Contains(t, domain.TaskFieldNames, "blocked")
+	assert.Contains(t, domain.ProjectFieldNames, "task_count")
+	assert.NotContains(t, domain.ProjectFieldNames, "title")
+}
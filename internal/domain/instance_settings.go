@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Feature modules an admin can switch off for the whole instance.
+const (
+	ModuleAttachments   = "attachments"
+	ModuleBreakdown     = "breakdown"
+	ModuleSharing       = "sharing"
+	ModuleCalendarFeeds = "calendar_feeds"
+	ModuleAutomations   = "automations"
+	ModuleWebhooks      = "webhooks"
+	ModuleFeedback      = "feedback"
+)
+
+// Modules lists every module that can be switched off.
+var Modules = []string{
+	ModuleAttachments, ModuleBreakdown, ModuleSharing, ModuleCalendarFeeds,
+	ModuleAutomations, ModuleWebhooks, ModuleFeedback,
+}
+
+// Settings that can be overridden, as named in Overridden and Reset.
+const (
+	SettingSignupMode     = "signup_mode"
+	SettingInviteQuota    = "invite_quota"
+	SettingMaxUploadBytes = "max_upload_bytes"
+	SettingRevisionsKeep  = "revisions_keep"
+	SettingModules        = "modules"
+)
+
+// InstanceSettings are the settings in force on this instance: the
+// environment's values with any stored overrides on top.
+type InstanceSettings struct {
+	SignupMode     string          `json:"signup_mode"`
+	InviteQuota    int             `json:"invite_quota"`
+	MaxUploadBytes int64           `json:"max_upload_bytes"` // 0 for no limit
+	RevisionsKeep  int             `json:"revisions_keep"`
+	Modules        map[string]bool `json:"modules"`
+	// Mail is read-only: the backend is chosen in the environment.
+	Mail InstanceMail `json:"mail"`
+	// Overridden lists the settings stored in the database rather than
+	// taken from the environment.
+	Overridden []string   `json:"overridden"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+}
+
+// InstanceMail describes the outgoing mail backend.
+type InstanceMail struct {
+	Driver string `json:"driver"`
+	From   string `json:"from"`
+}
+
+// InstanceOverrides are the stored settings; nil fields keep the value from
+// the environment.
+type InstanceOverrides struct {
+	SignupMode      *string        `db:"signup_mode"`
+	InviteQuota     *int           `db:"invite_quota"`
+	MaxUploadBytes  *int64         `db:"max_upload_bytes"`
+	RevisionsKeep   *int           `db:"revisions_keep"`
+	DisabledModules pq.StringArray `db:"disabled_modules"` // nil leaves every module on
+	UpdatedAt       time.Time      `db:"updated_at"`
+}
+
+// UpdateInstanceSettingsRequest overrides settings; omitted ones are left
+// as they are. Modules switches modules on or off by name, and Reset drops
+// overrides so the environment's value applies again.
+type UpdateInstanceSettingsRequest struct {
+	SignupMode     *string         `json:"signup_mode" validate:"omitempty,oneof=open invite_only"`
+	InviteQuota    *int            `json:"invite_quota" validate:"omitempty,min=0,max=1000"`
+	MaxUploadBytes *int64          `json:"max_upload_bytes" validate:"omitempty,min=0"`
+	RevisionsKeep  *int            `json:"revisions_keep" validate:"omitempty,min=0,max=100"`
+	Modules        map[string]bool `json:"modules"`
+	Reset          []string        `json:"reset" validate:"max=5,dive,oneof=signup_mode invite_quota max_upload_bytes revisions_keep modules"`
+}
+
+// MailTestRequest asks for a test email; To defaults to the admin's address.
+type MailTestRequest struct {
+	To string `json:"to" validate:"omitempty,email"`
+}
+
+// MailTestResult reports whether the mail backend accepted a test email.
+type MailTestResult struct {
+	Driver    string `json:"driver"`
+	To        string `json:"to"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Signup modes.
+const (
+	SignupOpen       = "open"
+	SignupInviteOnly = "invite_only"
+)
+
+// InviteCode lets people sign up while registration is invite-only. Codes
+// made by regular users are single-use and count against their quota;
+// admins may make multi-use codes without limit.
+type InviteCode struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	Code      string     `json:"code" db:"code"`
+	CreatedBy uuid.UUID  `json:"created_by" db:"created_by"`
+	MaxUses   int        `json:"max_uses" db:"max_uses"`
+	Uses      int        `json:"uses" db:"uses"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Usable reports whether the code can still be redeemed at now.
+func (c *InviteCode) Usable(now time.Time) bool {
+	return c.RevokedAt == nil && c.Uses < c.MaxUses && (c.ExpiresAt == nil || now.Before(*c.ExpiresAt))
+}
+
+// CreateInviteRequest is the payload for creating an invite code.
+type CreateInviteRequest struct {
+	MaxUses       int `json:"max_uses" validate:"omitempty,min=1,max=1000"` // default 1; above 1 is admin-only
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=365"`
+}
+
+// InviteSummary lists a user's invite codes with their remaining quota.
+// Quota and Remaining are nil for admins, who have no limit.
+type InviteSummary struct {
+	Codes     []*InviteCode `json:"codes"`
+	Quota     *int          `json:"quota"`
+	Remaining *int          `json:"remaining"`
+}
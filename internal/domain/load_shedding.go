@@ -0,0 +1,35 @@
+package domain
+
+import "time"
+
+// SheddingThresholds decide when the API sheds low-priority requests
+// (analytics, exports) to keep task CRUD responsive. Either limit at zero is
+// not checked.
+type SheddingThresholds struct {
+	// MaxLatencyMs is the mean database round trip, in milliseconds, over
+	// recent health probes above which requests are shed.
+	MaxLatencyMs int `json:"max_latency_ms" validate:"min=0,max=60000"`
+	// MaxErrorPercent is the share of recent probes that failed above which
+	// requests are shed.
+	MaxErrorPercent int `json:"max_error_percent" validate:"min=0,max=100"`
+}
+
+// LoadStatus reports the health signals load shedding acts on.
+type LoadStatus struct {
+	Shedding bool `json:"shedding"`
+	// Since is when shedding started or last stopped.
+	Since        *time.Time         `json:"since,omitempty"`
+	LatencyMs    float64            `json:"latency_ms"`
+	ErrorPercent float64            `json:"error_percent"`
+	Samples      int                `json:"samples"`
+	Thresholds   SheddingThresholds `json:"thresholds"`
+}
+
+// CoalescingStats counts one kind of coalesced read on an instance: Calls
+// is how many were asked for, Queries how many reached the database, and
+// Coalesced how many shared a query already in flight.
+type CoalescingStats struct {
+	Calls     int64 `json:"calls"`
+	Queries   int64 `json:"queries"`
+	Coalesced int64 `json:"coalesced"`
+}
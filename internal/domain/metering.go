@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metered usage, recorded per user and UTC day.
+const (
+	MeterActiveUsers  = "active_users"  // 1 when the user logged in or changed a task that day
+	MeterTasksCreated = "tasks_created" // tasks created that day, deleted ones included
+	MeterStorageBytes = "storage_bytes" // uploaded attachment bytes held at the end of the day
+)
+
+// Meters lists every metric in reporting order.
+var Meters = []string{MeterActiveUsers, MeterTasksCreated, MeterStorageBytes}
+
+// MeteringEvent is one user's usage of a metric on a day. The Invoiced
+// fields are set by the billing module once it has billed the row.
+type MeteringEvent struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	Metric           string     `json:"metric" db:"metric"`
+	Day              time.Time  `json:"day" db:"day"`
+	Quantity         int64      `json:"quantity" db:"quantity"`
+	RecordedAt       time.Time  `json:"recorded_at" db:"recorded_at"`
+	InvoiceID        *string    `json:"invoice_id,omitempty" db:"invoice_id"`
+	InvoicedQuantity *int64     `json:"invoiced_quantity,omitempty" db:"invoiced_quantity"`
+	InvoicedAt       *time.Time `json:"invoiced_at,omitempty" db:"invoiced_at"`
+}
+
+// MeterReconciliation compares a metric's metered and invoiced usage over a
+// range of days. Daily quantities are summed, so active_users counts
+// user-days and storage_bytes byte-days.
+type MeterReconciliation struct {
+	Metric   string `json:"metric" db:"metric"`
+	Metered  int64  `json:"metered" db:"metered"`
+	Invoiced int64  `json:"invoiced" db:"invoiced"`
+	// Difference is metered minus invoiced usage.
+	Difference int64 `json:"difference" db:"-"`
+	// Uninvoiced counts rows the billing module has not billed yet.
+	Uninvoiced int `json:"uninvoiced_events" db:"uninvoiced"`
+	// Mismatched counts rows billed at another quantity than metered.
+	Mismatched int `json:"mismatched_events" db:"mismatched"`
+}
+
+// MeteringReconciliation is the admin report on metered against invoiced
+// usage, with the mismatched rows, oldest first.
+type MeteringReconciliation struct {
+	From        time.Time             `json:"from"`
+	To          time.Time             `json:"to"`
+	Metrics     []MeterReconciliation `json:"metrics"`
+	Mismatches  []*MeteringEvent      `json:"mismatches"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
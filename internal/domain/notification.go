@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationChannel is a delivery medium for notifications.
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "in_app"
+	NotificationChannelEmail NotificationChannel = "email"
+)
+
+// Notification event types.
+const (
+	EventTaskCreated   = "task.created"
+	EventTaskUpdated   = "task.updated"
+	EventTaskCompleted = "task.completed"
+	EventTaskOverdue   = "task.overdue"
+	EventTaskDeleted   = "task.deleted"
+	// EventTaskMoved accompanies task.updated when a task changes project.
+	EventTaskMoved = "task.moved"
+	// EventTaskReminder is sent when a reminder set on a task goes off.
+	EventTaskReminder = "task.reminder"
+	// EventTaskEffortExceeded nudges the owner of an in-progress task
+	// untouched for longer than its estimate.
+	EventTaskEffortExceeded = "task.effort_exceeded"
+
+	EventProjectCreated = "project.created"
+	EventProjectUpdated = "project.updated"
+	EventProjectDeleted = "project.deleted"
+
+	EventTagCreated = "tag.created"
+	EventTagUpdated = "tag.updated"
+	EventTagDeleted = "tag.deleted"
+
+	EventUserRegistered = "user.registered"
+
+	// EventAutomationNotify is sent by an automation rule's notify action.
+	EventAutomationNotify = "automation.notify"
+	// EventCircuitOpened tells an owner their rule or webhook was paused
+	// after repeated failures.
+	EventCircuitOpened = "circuit_breaker.opened"
+
+	// EventQuietHoursSummary is the summary delivered when do-not-disturb ends.
+	EventQuietHoursSummary = "notification.quiet_hours_summary"
+)
+
+// NotificationEvent is something that happened which a user may want to hear about.
+// Events are fed to the notifier, which batches them into Notifications.
+type NotificationEvent struct {
+	UserID   uuid.UUID
+	Type     string
+	Title    string
+	Body     string
+	EntityID *uuid.UUID
+	URL      string
+	// DedupKey collapses repeated events about the same thing within a batch
+	// window. Defaults to EntityID when empty.
+	DedupKey string
+}
+
+// Notification is a delivered (possibly summarised) notification.
+type Notification struct {
+	ID         uuid.UUID       `json:"id" db:"id"`
+	UserID     uuid.UUID       `json:"user_id" db:"user_id"`
+	Type       string          `json:"type" db:"type"`
+	Title      string          `json:"title" db:"title"`
+	Body       string          `json:"body" db:"body"`
+	Data       json.RawMessage `json:"data" db:"data"`
+	EventCount int             `json:"event_count" db:"event_count"`
+	ReadAt     *time.Time      `json:"read_at,omitempty" db:"read_at"`
+	// SnoozedUntil hides the notification until the given time, when the
+	// worker resurfaces it as unread.
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty" db:"snoozed_until"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+}
+
+// NotificationData is the structured payload stored alongside a notification.
+type NotificationData struct {
+	EntityIDs []uuid.UUID `json:"entity_ids,omitempty"`
+	URL       string      `json:"url,omitempty"`
+}
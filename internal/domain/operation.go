@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OperationResult describes the rows a destructive or bulk operation changed
+// or, for a dry run, would change. Affected maps entity type to row ids.
+type OperationResult struct {
+	Operation string                 `json:"operation"`
+	DryRun    bool                   `json:"dry_run"`
+	Affected  map[string][]uuid.UUID `json:"affected"`
+	Total     int                    `json:"total"`
+}
+
+// MoveTasksRequest is the payload for moving several tasks to a project at once.
+type MoveTasksRequest struct {
+	TaskIDs   []uuid.UUID `json:"task_ids" validate:"required,min=1,max=500"`
+	ProjectID *uuid.UUID  `json:"project_id"` // null moves the tasks out of any project
+}
+
+// OperationStatus is where an async operation is in its life.
+type OperationStatus string
+
+const (
+	OperationPending   OperationStatus = "pending"
+	OperationRunning   OperationStatus = "running"
+	OperationSucceeded OperationStatus = "succeeded"
+	OperationFailed    OperationStatus = "failed"
+	OperationCancelled OperationStatus = "cancelled"
+)
+
+// Finished reports whether the operation has stopped for good.
+func (s OperationStatus) Finished() bool {
+	return s == OperationSucceeded || s == OperationFailed || s == OperationCancelled
+}
+
+// Operation is a long-running request, such as an export or a hard delete,
+// run in the background and polled for its outcome.
+type Operation struct {
+	ID       uuid.UUID       `json:"id" db:"id"`
+	UserID   uuid.UUID       `json:"user_id" db:"user_id"`
+	Kind     string          `json:"kind" db:"kind"`
+	Status   OperationStatus `json:"status" db:"status"`
+	Progress int             `json:"progress" db:"progress"` // percent done
+	// Result is the JSON the synchronous endpoint would have returned.
+	Result json.RawMessage `json:"result,omitempty" db:"result"`
+	Error  *string         `json:"error,omitempty" db:"error"`
+	// HasFile is set when the result is a download, served from
+	// /operations/:id/file.
+	HasFile         bool       `json:"has_file" db:"has_file"`
+	CancelRequested bool       `json:"cancel_requested" db:"cancel_requested"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	StartedAt       *time.Time `json:"started_at,omitempty" db:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at,omitempty" db:"finished_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// Download is a file for the client to save, such as an export.
+type Download struct {
+	Name        string `db:"file_name"`
+	ContentType string `db:"file_type"`
+	Data        []byte `db:"file"`
+}
+
+// OperationOutput is what a finished operation hands back: a JSON result,
+// a file, or both.
+type OperationOutput struct {
+	Result any
+	File   *Download
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Personal access token scopes.
+const (
+	ScopeTasksRead     = "tasks:read"
+	ScopeTasksWrite    = "tasks:write"
+	ScopeAnalyticsRead = "analytics:read"
+)
+
+// PersonalAccessTokenPrefix starts every personal access token, telling
+// them apart from JWTs and making leaked ones easy to scan for.
+const PersonalAccessTokenPrefix = "tdp_"
+
+// PersonalAccessToken is a long-lived credential for scripts and
+// integrations, limited to its scopes. Only a digest of the token is kept.
+type PersonalAccessToken struct {
+	ID     uuid.UUID      `json:"id" db:"id"`
+	UserID uuid.UUID      `json:"user_id" db:"user_id"`
+	Name   string         `json:"name" db:"name"`
+	Scopes pq.StringArray `json:"scopes" db:"scopes"`
+	// Hint is the start of the token, for telling tokens apart in lists.
+	Hint       string     `json:"hint" db:"hint"`
+	TokenHash  string     `json:"-" db:"token_hash"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty" db:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Usable reports whether the token authenticates requests at now.
+func (t *PersonalAccessToken) Usable(now time.Time) bool {
+	return t.RevokedAt == nil && (t.ExpiresAt == nil || now.Before(*t.ExpiresAt))
+}
+
+// Allows reports whether the token grants scope. tasks:write includes
+// tasks:read.
+func (t *PersonalAccessToken) Allows(scope string) bool {
+	if scope == ScopeTasksRead && slices.Contains(t.Scopes, ScopeTasksWrite) {
+		return true
+	}
+	return slices.Contains(t.Scopes, scope)
+}
+
+// CreatedPersonalAccessToken is returned once on creation; it is the only
+// time the token itself is shown.
+type CreatedPersonalAccessToken struct {
+	*PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// CreatePersonalAccessTokenRequest is the payload for creating a personal
+// access token.
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=tasks:read tasks:write analytics:read"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"omitempty,min=1,max=365"` // default: never expires
+}
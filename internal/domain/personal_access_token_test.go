@@ -0,0 +1,28 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPersonalAccessToken_Allows(t *testing.T) {
+	write := &domain.PersonalAccessToken{Scopes: []string{domain.ScopeTasksWrite}}
+	assert.True(t, write.Allows(domain.ScopeTasksWrite))
+	assert.True(t, write.Allows(domain.ScopeTasksRead), "tasks:write includes tasks:read")
+	assert.False(t, write.Allows(domain.ScopeAnalyticsRead))
+
+	read := &domain.PersonalAccessToken{Scopes: []string{domain.ScopeTasksRead}}
+	assert.False(t, read.Allows(domain.ScopeTasksWrite))
+}
+
+func TestPersonalAccessToken_Usable(t *testing.T) {
+	now := time.Now()
+	later, earlier := now.Add(time.Hour), now.Add(-time.Hour)
+	assert.True(t, (&domain.PersonalAccessToken{}).Usable(now))
+	assert.True(t, (&domain.PersonalAccessToken{ExpiresAt: &later}).Usable(now))
+	assert.False(t, (&domain.PersonalAccessToken{ExpiresAt: &earlier}).Usable(now))
+	assert.False(t, (&domain.PersonalAccessToken{RevokedAt: &earlier}).Usable(now))
+}
@@ -15,6 +15,27 @@ const (
 	ProjectTypeSideProject ProjectType = "side_project"
 )
 
+// ProjectDeleteStrategy says what deleting a project does with its tasks.
+type ProjectDeleteStrategy string
+
+const (
+	// ProjectDeleteInbox keeps the tasks, without a project. The default.
+	ProjectDeleteInbox ProjectDeleteStrategy = "inbox"
+	// ProjectDeleteCascade moves the tasks to the trash with the project.
+	ProjectDeleteCascade ProjectDeleteStrategy = "cascade"
+	// ProjectDeleteBlock refuses to delete a project that still has tasks.
+	ProjectDeleteBlock ProjectDeleteStrategy = "block"
+)
+
+// Valid reports whether s is a known strategy.
+func (s ProjectDeleteStrategy) Valid() bool {
+	switch s {
+	case ProjectDeleteInbox, ProjectDeleteCascade, ProjectDeleteBlock:
+		return true
+	}
+	return false
+}
+
 // Project groups related tasks.
 type Project struct {
 	ID          uuid.UUID   `json:"id" db:"id"`
@@ -23,10 +44,56 @@ type Project struct {
 	Description string      `json:"description" db:"description"`
 	Type        ProjectType `json:"type" db:"type"`
 	Color       string      `json:"color" db:"color"` // hex color e.g. "#3B82F6"
+	Icon        string      `json:"icon" db:"icon"`   // emoji or one of ProjectIconKeys; empty for none
 	TaskCount   int         `json:"task_count" db:"task_count"`
+	Version     int64       `json:"version" db:"version"` // served as the ETag
 	CreatedAt   time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time   `json:"updated_at" db:"updated_at"`
 	DeletedAt   *time.Time  `json:"deleted_at,omitempty" db:"deleted_at"`
+	// ArchivedAt is set while the project is archived: it and its tasks are
+	// left out of default lists and analytics until it is unarchived.
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+// DuplicateProjectRequest is the payload for duplicating a project.
+type DuplicateProjectRequest struct {
+	Name string `json:"name" validate:"omitempty,min=1,max=100"` // default "<name> (copy)"
+	// IncludeTasks copies the project's open tasks with it, as todo.
+	IncludeTasks bool `json:"include_tasks"`
+	// ShiftDays moves the due, start and scheduled dates of copied tasks.
+	ShiftDays int `json:"shift_days" validate:"min=-3650,max=3650"`
+}
+
+// ProjectDuplicateResult reports what a duplication created.
+type ProjectDuplicateResult struct {
+	Project   *Project `json:"project"`
+	TaskCount int      `json:"task_count"`
+}
+
+// ProjectStats summarises the live tasks of a project, whoever created them.
+type ProjectStats struct {
+	ProjectID  uuid.UUID            `json:"project_id" db:"-"`
+	Total      int                  `json:"total" db:"total"`
+	ByStatus   ProjectStatusCount   `json:"by_status" db:"by_status"`
+	ByPriority ProjectPriorityCount `json:"by_priority" db:"by_priority"`
+	// CompletionRate is the share of tasks that are done, in percent.
+	CompletionRate         float64 `json:"completion_rate_percent" db:"completion_rate_percent"`
+	Overdue                int     `json:"overdue" db:"overdue"`
+	AvgCompletionTimeHours float64 `json:"avg_completion_time_hours" db:"avg_completion_time_hours"`
+}
+
+// ProjectStatusCount counts a project's tasks by status.
+type ProjectStatusCount struct {
+	Todo       int `json:"todo" db:"todo"`
+	InProgress int `json:"in_progress" db:"in_progress"`
+	Done       int `json:"done" db:"done"`
+}
+
+// ProjectPriorityCount counts a project's tasks by priority.
+type ProjectPriorityCount struct {
+	Low    int `json:"low" db:"low"`
+	Medium int `json:"medium" db:"medium"`
+	High   int `json:"high" db:"high"`
 }
 
 // CreateProjectRequest is the payload for creating a project.
@@ -35,6 +102,7 @@ type CreateProjectRequest struct {
 	Description string      `json:"description" validate:"max=500"`
 	Type        ProjectType `json:"type" validate:"required,oneof=personal work side_project"`
 	Color       string      `json:"color" validate:"omitempty,hexcolor"`
+	Icon        string      `json:"icon" validate:"omitempty,project_icon"`
 }
 
 // UpdateProjectRequest is the payload for updating a project.
@@ -43,4 +111,8 @@ type UpdateProjectRequest struct {
 	Description *string      `json:"description" validate:"omitempty,max=500"`
 	Type        *ProjectType `json:"type" validate:"omitempty,oneof=personal work side_project"`
 	Color       *string      `json:"color" validate:"omitempty,hexcolor"`
+	Icon        *string      `json:"icon" validate:"omitempty,project_icon"` // "" removes the icon
+	// IfVersion, from If-Match, rejects the update if the project has
+	// changed since.
+	IfVersion *int64 `json:"-"`
 }
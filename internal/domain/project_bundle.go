@@ -0,0 +1,59 @@
+package domain
+
+import "time"
+
+// ProjectBundleVersion is the schema version written by project exports.
+const ProjectBundleVersion = 1
+
+// ProjectBundle is a whole project in a portable, human-editable form, as
+// exported to and imported from YAML or JSON. Ids are left out so a bundle can be
+// imported into any account; tasks refer to tags by name and to each other
+// by key.
+type ProjectBundle struct {
+	Version int           `yaml:"version" json:"version" validate:"required,eq=1"`
+	Project BundleProject `yaml:"project" json:"project"`
+	Tags    []BundleTag   `yaml:"tags,omitempty" json:"tags,omitempty" validate:"max=200,dive"`
+	Tasks   []BundleTask  `yaml:"tasks,omitempty" json:"tasks,omitempty" validate:"max=1000,dive"`
+}
+
+// BundleProject holds the project's own settings.
+type BundleProject struct {
+	Name        string      `yaml:"name" json:"name" validate:"required,min=1,max=100"`
+	Description string      `yaml:"description,omitempty" json:"description,omitempty" validate:"max=500"`
+	Type        ProjectType `yaml:"type" json:"type" validate:"required,oneof=personal work side_project"`
+	Color       string      `yaml:"color,omitempty" json:"color,omitempty" validate:"omitempty,hexcolor"`
+	Icon        string      `yaml:"icon,omitempty" json:"icon,omitempty" validate:"omitempty,project_icon"`
+}
+
+// BundleTag is a tag used by the bundle's tasks. On import it is matched to
+// an existing tag of the same name, ignoring case, or created.
+type BundleTag struct {
+	Name  string `yaml:"name" json:"name" validate:"required,min=1,max=50"`
+	Color string `yaml:"color,omitempty" json:"color,omitempty" validate:"omitempty,hexcolor"`
+}
+
+// BundleTask is a task with its subtasks nested below it.
+type BundleTask struct {
+	// Key names the task for blocked_by references; export only sets it on
+	// tasks that block another one.
+	Key            string       `yaml:"key,omitempty" json:"key,omitempty" validate:"max=50"`
+	Title          string       `yaml:"title" json:"title" validate:"required,min=1,max=255"`
+	Description    string       `yaml:"description,omitempty" json:"description,omitempty" validate:"max=5000"`
+	Status         TaskStatus   `yaml:"status,omitempty" json:"status,omitempty" validate:"omitempty,task_status"`       // default todo
+	Priority       TaskPriority `yaml:"priority,omitempty" json:"priority,omitempty" validate:"omitempty,task_priority"` // default medium
+	EstimatedHours *float64     `yaml:"estimated_hours,omitempty" json:"estimated_hours,omitempty" validate:"omitempty,min=0,max=999"`
+	DueDate        *time.Time   `yaml:"due_date,omitempty" json:"due_date,omitempty"`
+	// DueExpr is re-resolved on import, e.g. "3 days after creation", and
+	// takes the place of due_date.
+	DueExpr   string       `yaml:"due_expr,omitempty" json:"due_expr,omitempty" validate:"max=100"`
+	Tags      []string     `yaml:"tags,omitempty" json:"tags,omitempty" validate:"max=50,dive,min=1,max=50"`
+	BlockedBy []string     `yaml:"blocked_by,omitempty" json:"blocked_by,omitempty" validate:"max=50"`
+	Subtasks  []BundleTask `yaml:"subtasks,omitempty" json:"subtasks,omitempty" validate:"max=1000,dive"`
+}
+
+// ProjectImportResult reports what an import created.
+type ProjectImportResult struct {
+	Project     *Project `json:"project"`
+	TaskCount   int      `json:"task_count"`
+	TagsCreated int      `json:"tags_created"`
+}
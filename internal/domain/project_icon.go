@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"slices"
+	"unicode/utf8"
+)
+
+// ProjectIconKeys are the named icons clients draw from their own icon set.
+// A project icon is one of these or a single emoji.
+var ProjectIconKeys = []string{
+	"book", "briefcase", "calendar", "camera", "cart", "code", "flag", "folder",
+	"gift", "globe", "heart", "home", "lightbulb", "music", "plane", "rocket",
+	"star", "target", "tools", "wallet",
+}
+
+// maxProjectIconBytes bounds an emoji icon; the longest ZWJ sequences in use
+// are well within it.
+const maxProjectIconBytes = 32
+
+// ValidProjectIcon reports whether s is an icon key or a single emoji,
+// including flags, keycaps, skin tones and ZWJ sequences.
+func ValidProjectIcon(s string) bool {
+	if slices.Contains(ProjectIconKeys, s) {
+		return true
+	}
+	if s == "" || len(s) > maxProjectIconBytes || !utf8.ValidString(s) {
+		return false
+	}
+
+	var pictographs, joiners, regional, keycapBases int
+	keycap := false
+	for _, r := range s {
+		switch {
+		case r == 0x200D: // zero width joiner
+			joiners++
+		case r == 0xFE0E || r == 0xFE0F, // variation selectors
+			r >= 0x1F3FB && r <= 0x1F3FF, // skin tones
+			r >= 0xE0020 && r <= 0xE007F: // tag sequences, as in subdivision flags
+		case r == 0x20E3:
+			keycap = true
+		case r >= 0x1F1E6 && r <= 0x1F1FF:
+			regional++
+		case r >= '0' && r <= '9', r == '#', r == '*':
+			keycapBases++
+		case isPictograph(r):
+			pictographs++
+		default:
+			return false
+		}
+	}
+	switch {
+	case regional > 0:
+		// A flag is exactly two regional indicators.
+		return regional == 2 && pictographs == 0 && keycapBases == 0
+	case keycapBases > 0:
+		return keycap && keycapBases == 1 && pictographs == 0
+	default:
+		// Joined pictographs draw as one emoji.
+		return !keycap && pictographs > 0 && pictographs-joiners == 1
+	}
+}
+
+func isPictograph(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF, // emoticons, symbols and pictographs
+		r >= 0x2600 && r <= 0x27BF, // miscellaneous symbols and dingbats
+		r >= 0x2300 && r <= 0x23FF, // technical, such as ⌚ and ⏰
+		r >= 0x2B00 && r <= 0x2BFF, // arrows and shapes, such as ⭐
+		r >= 0x2190 && r <= 0x21FF, r >= 0x25A0 && r <= 0x25FF,
+		r == 0x00A9, r == 0x00AE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r == 0x2934, r == 0x2935, r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	}
+	return false
+}
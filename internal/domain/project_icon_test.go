@@ -0,0 +1,37 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidProjectIcon(t *testing.T) {
+	valid := []string{
+		"rocket", "briefcase",
+		"🚀", "☕", "⭐", "❤️",
+		"👍🏽",         // skin tone
+		"👩‍💻",        // ZWJ sequence
+		"👨‍👩‍👧‍👦",    // family
+		"🇮🇩",         // flag
+		"🏴󠁧󠁢󠁳󠁣󠁴󠁿",    // subdivision flag
+		"1️⃣", "#️⃣", // keycaps
+	}
+	for _, icon := range valid {
+		assert.True(t, domain.ValidProjectIcon(icon), "%q", icon)
+	}
+
+	invalid := []string{
+		"", "Rocket", "spaceship", "a", "1", "#3B82F6",
+		"🚀🚀",   // two emoji
+		"🚀 ",   // trailing space
+		"🇮",    // half a flag
+		"🇮🇩🇯🇵", // two flags
+		"‍",    // a lone joiner
+		"✓ok",
+	}
+	for _, icon := range invalid {
+		assert.False(t, domain.ValidProjectIcon(icon), "%q", icon)
+	}
+}
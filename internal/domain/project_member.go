@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project member roles. The owner is the project's creator; members are the
+// users it has been shared with.
+const (
+	ProjectRoleOwner  = "owner"
+	ProjectRoleMember = "member"
+)
+
+// ProjectMember is a user with access to a shared project. Members see the
+// project's tasks, whoever created them, and may add and edit tasks in it;
+// renaming, deleting and sharing the project stay with the owner.
+type ProjectMember struct {
+	ProjectID uuid.UUID `json:"project_id" db:"project_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Email     string    `json:"email" db:"email"`
+	Name      string    `json:"name" db:"name"`
+	Role      string    `json:"role" db:"-"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"` // when the user joined
+}
+
+// AddProjectMemberRequest is the payload for sharing a project with a user.
+type AddProjectMemberRequest struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+}
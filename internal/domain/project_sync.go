@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project sync actions.
+const (
+	SyncCreated   = "created"
+	SyncUpdated   = "updated"
+	SyncUnchanged = "unchanged"
+)
+
+// DesiredProject is the complete desired state of a project for declarative
+// sync. Tasks are matched to existing ones by title among their siblings;
+// fields left out take their defaults, so the document is the whole truth.
+type DesiredProject struct {
+	Description string        `yaml:"description" json:"description" validate:"max=500"`
+	Type        ProjectType   `yaml:"type" json:"type" validate:"required,oneof=personal work side_project"`
+	Color       string        `yaml:"color" json:"color" validate:"omitempty,hexcolor"`
+	Icon        string        `yaml:"icon" json:"icon" validate:"omitempty,project_icon"`
+	Tasks       []DesiredTask `yaml:"tasks" json:"tasks" validate:"max=1000,dive"`
+}
+
+// DesiredTask is the desired state of one task and its subtasks.
+type DesiredTask struct {
+	Title          string        `yaml:"title" json:"title" validate:"required,min=1,max=255"`
+	Description    string        `yaml:"description" json:"description" validate:"max=5000"`
+	Status         TaskStatus    `yaml:"status" json:"status" validate:"omitempty,task_status"`       // default todo
+	Priority       TaskPriority  `yaml:"priority" json:"priority" validate:"omitempty,task_priority"` // default medium
+	EstimatedHours *float64      `yaml:"estimated_hours" json:"estimated_hours" validate:"omitempty,min=0,max=999"`
+	DueDate        *time.Time    `yaml:"due_date" json:"due_date"`
+	Tags           []string      `yaml:"tags" json:"tags" validate:"max=50,dive,min=1,max=50"`
+	Subtasks       []DesiredTask `yaml:"subtasks" json:"subtasks" validate:"max=1000,dive"`
+}
+
+// ProjectSyncResult is the diff a sync computed and, unless DryRun, applied.
+type ProjectSyncResult struct {
+	DryRun    bool              `json:"dry_run"`
+	Project   ProjectSyncChange `json:"project"`
+	Created   []TaskSyncChange  `json:"created"`
+	Updated   []TaskSyncChange  `json:"updated"`
+	Archived  []TaskSyncChange  `json:"archived"`
+	Unchanged int               `json:"unchanged"`
+}
+
+// ProjectSyncChange is what a sync did to the project itself.
+type ProjectSyncChange struct {
+	ID      *uuid.UUID  `json:"id,omitempty"` // nil when a dry run would create it
+	Name    string      `json:"name"`
+	Action  string      `json:"action"`
+	Changes TaskChanges `json:"changes,omitempty"`
+}
+
+// TaskSyncChange is one task a sync created, updated or archived. Path is the
+// task's title preceded by its parents', joined with " / ".
+type TaskSyncChange struct {
+	ID      *uuid.UUID  `json:"id,omitempty"` // nil when a dry run would create it
+	Path    string      `json:"path"`
+	Changes TaskChanges `json:"changes,omitempty"`
+}
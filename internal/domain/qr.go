@@ -0,0 +1,10 @@
+package domain
+
+// QRRequest asks for a QR code of arbitrary text, such as a share link, a
+// device pairing code or an otpauth:// enrollment URI. It is sent in the
+// body so that secrets stay out of URLs and access logs.
+type QRRequest struct {
+	Data string `json:"data" validate:"required,max=213"`
+	// Scale is the size of a module in pixels; 8 when left out.
+	Scale int `json:"scale" validate:"omitempty,min=1,max=20"`
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuietHours is a user's do-not-disturb schedule. Notifications produced while
+// it is active are held back and delivered as a single summary when it ends.
+type QuietHours struct {
+	UserID   uuid.UUID `json:"user_id" db:"user_id"`
+	Enabled  bool      `json:"enabled" db:"enabled"`
+	Timezone string    `json:"timezone" db:"timezone"` // IANA name, e.g. "Asia/Jakarta"
+	// StartTime and EndTime are local "HH:MM" clock times. EndTime before
+	// StartTime means the window spans midnight. Equal values disable the window.
+	StartTime string `json:"start_time" db:"start_time"`
+	EndTime   string `json:"end_time" db:"end_time"`
+	// QuietDays is a bitmask of whole days off (bit 0 = Sunday … bit 6 = Saturday).
+	QuietDays int       `json:"-" db:"quiet_days"`
+	Days      []string  `json:"quiet_days" db:"-"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// UpdateQuietHoursRequest is the payload for configuring do-not-disturb.
+type UpdateQuietHoursRequest struct {
+	Enabled   bool     `json:"enabled"`
+	Timezone  string   `json:"timezone" validate:"required,timezone"`
+	StartTime string   `json:"start_time" validate:"required,datetime=15:04"`
+	EndTime   string   `json:"end_time" validate:"required,datetime=15:04"`
+	QuietDays []string `json:"quiet_days" validate:"omitempty,dive,oneof=sunday monday tuesday wednesday thursday friday saturday"`
+}
+
+// ParseWeekdays converts lowercase weekday names to a QuietDays bitmask.
+func ParseWeekdays(days []string) (int, error) {
+	mask := 0
+	for _, d := range days {
+		found := false
+		for wd := time.Sunday; wd <= time.Saturday; wd++ {
+			if strings.EqualFold(d, wd.String()) {
+				mask |= 1 << wd
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown weekday %q", d)
+		}
+	}
+	return mask, nil
+}
+
+// WeekdayNames expands a QuietDays bitmask to lowercase weekday names.
+func WeekdayNames(mask int) []string {
+	names := []string{}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if mask&(1<<wd) != 0 {
+			names = append(names, strings.ToLower(wd.String()))
+		}
+	}
+	return names
+}
+
+// Active reports whether do-not-disturb is in effect at instant t.
+func (q *QuietHours) Active(t time.Time) bool {
+	if q == nil || !q.Enabled {
+		return false
+	}
+	local := t.In(q.location())
+
+	if q.QuietDays&(1<<local.Weekday()) != 0 {
+		return true
+	}
+
+	start, end, ok := q.window()
+	if !ok {
+		return false
+	}
+	m := local.Hour()*60 + local.Minute()
+	if start < end {
+		return m >= start && m < end
+	}
+	return m >= start || m < end
+}
+
+// EndsAt returns the first instant at or after t when do-not-disturb is no
+// longer active. It returns t itself when DND is not active.
+func (q *QuietHours) EndsAt(t time.Time) time.Time {
+	loc := q.location()
+	// A week of quiet days plus one overnight window bounds the search.
+	for i := 0; i < 16 && q.Active(t); i++ {
+		local := t.In(loc)
+		if q.QuietDays&(1<<local.Weekday()) != 0 {
+			t = midnightAfter(local)
+			continue
+		}
+		t = q.windowEnd(local)
+	}
+	return t
+}
+
+func (q *QuietHours) location() *time.Location {
+	if loc, err := time.LoadLocation(q.Timezone); err == nil && q.Timezone != "" {
+		return loc
+	}
+	return time.UTC
+}
+
+// window returns the start/end minutes-of-day; ok is false when the window is empty.
+func (q *QuietHours) window() (start, end int, ok bool) {
+	s, err1 := time.Parse("15:04", q.StartTime)
+	e, err2 := time.Parse("15:04", q.EndTime)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	start = s.Hour()*60 + s.Minute()
+	end = e.Hour()*60 + e.Minute()
+	return start, end, start != end
+}
+
+// windowEnd returns when the time window containing local closes.
+func (q *QuietHours) windowEnd(local time.Time) time.Time {
+	start, end, _ := q.window()
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	m := local.Hour()*60 + local.Minute()
+	if start > end && m >= start {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.Add(time.Duration(end) * time.Minute)
+}
+
+func midnightAfter(local time.Time) time.Time {
+	y, mo, d := local.Date()
+	return time.Date(y, mo, d+1, 0, 0, 0, 0, local.Location())
+}
+
+// DeferredNotification is a notification held back by do-not-disturb.
+type DeferredNotification struct {
+	ID           uuid.UUID           `db:"id"`
+	UserID       uuid.UUID           `db:"user_id"`
+	Channel      NotificationChannel `db:"channel"`
+	Type         string              `db:"type"`
+	Title        string              `db:"title"`
+	Body         string              `db:"body"`
+	Data         json.RawMessage     `db:"data"`
+	EventCount   int                 `db:"event_count"`
+	DeliverAfter time.Time           `db:"deliver_after"`
+	CreatedAt    time.Time           `db:"created_at"`
+}
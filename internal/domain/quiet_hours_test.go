@@ -0,0 +1,43 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuietHours_OvernightWindow(t *testing.T) {
+	q := &domain.QuietHours{Enabled: true, Timezone: "Asia/Jakarta", StartTime: "22:00", EndTime: "07:00"}
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+
+	late := time.Date(2026, 3, 4, 23, 30, 0, 0, jkt) // Wednesday
+	assert.True(t, q.Active(late))
+	assert.Equal(t, time.Date(2026, 3, 5, 7, 0, 0, 0, jkt), q.EndsAt(late).In(jkt))
+
+	early := time.Date(2026, 3, 5, 6, 59, 0, 0, jkt)
+	assert.True(t, q.Active(early))
+
+	noon := time.Date(2026, 3, 5, 12, 0, 0, 0, jkt)
+	assert.False(t, q.Active(noon))
+	assert.Equal(t, noon, q.EndsAt(noon))
+}
+
+func TestQuietHours_QuietDaysExtendWindow(t *testing.T) {
+	mask, err := domain.ParseWeekdays([]string{"saturday", "sunday"})
+	assert.NoError(t, err)
+	q := &domain.QuietHours{Enabled: true, Timezone: "UTC", StartTime: "22:00", EndTime: "07:00", QuietDays: mask}
+
+	friday := time.Date(2026, 3, 6, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 3, 9, 7, 0, 0, 0, time.UTC), q.EndsAt(friday),
+		"Friday night runs through the weekend until Monday morning")
+}
+
+func TestQuietHours_DisabledOrNil(t *testing.T) {
+	var q *domain.QuietHours
+	assert.False(t, q.Active(time.Now()))
+
+	q = &domain.QuietHours{Enabled: false, StartTime: "00:00", EndTime: "23:59"}
+	assert.False(t, q.Active(time.Now()))
+}
@@ -0,0 +1,20 @@
+package domain
+
+// How a user's ranker was chosen.
+const (
+	RankingSourceSetting    = "setting"
+	RankingSourceExperiment = "experiment"
+	RankingSourceDefault    = "default"
+)
+
+// RankingSetting is the ranker ordering a user's task list.
+type RankingSetting struct {
+	Ranker    string   `json:"ranker"`
+	Source    string   `json:"source"`
+	Available []string `json:"available"`
+}
+
+// SetRankingRequest picks a ranker; a null ranker clears the choice.
+type SetRankingRequest struct {
+	Ranker *string `json:"ranker" validate:"omitempty,max=64"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+// Kinds of "jump back in" lists served by GET /tasks/recent.
+const (
+	RecentViewed   = "viewed"
+	RecentModified = "modified"
+)
+
+// MaxRecentTasks bounds every recent list, and how many views are kept per user.
+const MaxRecentTasks = 50
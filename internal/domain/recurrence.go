@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecurrenceFrequency is the period a recurring task repeats on.
+type RecurrenceFrequency string
+
+const (
+	RecurDaily   RecurrenceFrequency = "daily"
+	RecurWeekly  RecurrenceFrequency = "weekly"
+	RecurMonthly RecurrenceFrequency = "monthly"
+	RecurYearly  RecurrenceFrequency = "yearly"
+)
+
+// RecurrenceFrequencies lists the supported frequencies.
+var RecurrenceFrequencies = []RecurrenceFrequency{RecurDaily, RecurWeekly, RecurMonthly, RecurYearly}
+
+// Recurrence makes a task repeat. Completing a recurring task records a
+// TaskOccurrence and reopens the task with its due date moved to the next
+// date of the series.
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency" validate:"required,oneof=daily weekly monthly yearly"`
+	Interval  int                 `json:"interval,omitempty" validate:"min=0,max=365"` // every n periods, default 1
+	// Anchor is the due date the series counts from, so a task due on the
+	// 31st stays on the last day of shorter months. It is set by the server
+	// whenever the recurrence or the due date is changed.
+	Anchor time.Time `json:"anchor"`
+	// Start is when the current occurrence began, for a task with a due
+	// expression: its series counts starts from Anchor instead, and each due
+	// date is the expression resolved from its start. Set by the server.
+	Start *time.Time `json:"start,omitempty"`
+}
+
+// Value implements driver.Valuer, storing the recurrence as JSONB.
+func (r Recurrence) Value() (driver.Value, error) { return json.Marshal(r) }
+
+// Scan implements sql.Scanner.
+func (r *Recurrence) Scan(src any) error { return scanJSON(src, r) }
+
+// Next returns the first date of the series strictly after after.
+func (r Recurrence) Next(after time.Time) time.Time {
+	n := r.Interval
+	if n < 1 {
+		n = 1
+	}
+	// Step from the anchor rather than the previous date so month-end
+	// clamping does not drift.
+	for k := n; ; k += n {
+		if next := r.step(k); next.After(after) {
+			return next
+		}
+	}
+}
+
+func (r Recurrence) step(k int) time.Time {
+	switch r.Frequency {
+	case RecurWeekly:
+		return r.Anchor.AddDate(0, 0, 7*k)
+	case RecurMonthly:
+		return addMonthsClamped(r.Anchor, k)
+	case RecurYearly:
+		return addMonthsClamped(r.Anchor, 12*k)
+	default:
+		return r.Anchor.AddDate(0, 0, k)
+	}
+}
+
+// addMonthsClamped adds months to t, moving to the last day of the target
+// month when it is shorter, where time.AddDate would overflow into the next.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month(), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	target := first.AddDate(0, months, 0)
+	lastDay := target.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return target.AddDate(0, 0, day-1)
+}
+
+// RollForward reopens a completed recurring task, moving its due date to
+// the next date of the series after both now and its current due date.
+func (t *Task) RollForward(now time.Time) {
+	after := now
+	if t.DueDate.After(after) {
+		after = *t.DueDate
+	}
+	next := t.Recurrence.Next(after)
+	t.DueDate = &next
+	t.Status = TaskStatusTodo
+	t.CompletedAt = nil
+}
+
+// TaskOccurrence records the completion of one occurrence of a recurring task.
+type TaskOccurrence struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TaskID      uuid.UUID `json:"task_id" db:"task_id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	DueDate     time.Time `json:"due_date" db:"due_date"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+	// OnTime is set when the occurrence was completed by its due date.
+	OnTime bool `json:"on_time" db:"on_time"`
+}
+
+// TaskOccurrenceStats summarises how reliably a recurring task is done on time.
+type TaskOccurrenceStats struct {
+	Completed int `json:"completed"`
+	OnTime    int `json:"on_time"`
+	Late      int `json:"late"`
+	// AdherenceRate is the share of occurrences completed on time, in percent.
+	AdherenceRate float64 `json:"adherence_rate_percent"`
+}
+
+// TaskOccurrenceHistory is the response of /tasks/{id}/occurrences.
+type TaskOccurrenceHistory struct {
+	Stats       TaskOccurrenceStats `json:"stats"`
+	Occurrences []*TaskOccurrence   `json:"occurrences"`
+}
@@ -0,0 +1,47 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrence_Next(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 9, 0, 0, 0, time.UTC) }
+	cases := map[string]struct {
+		r     domain.Recurrence
+		after time.Time
+		want  time.Time
+	}{
+		"daily":                    {domain.Recurrence{Frequency: domain.RecurDaily, Anchor: day(2026, 3, 1)}, day(2026, 3, 1), day(2026, 3, 2)},
+		"skips missed dates":       {domain.Recurrence{Frequency: domain.RecurDaily, Anchor: day(2026, 3, 1)}, day(2026, 3, 4).Add(time.Hour), day(2026, 3, 5)},
+		"every two weeks":          {domain.Recurrence{Frequency: domain.RecurWeekly, Interval: 2, Anchor: day(2026, 3, 2)}, day(2026, 3, 2), day(2026, 3, 16)},
+		"month end clamps":         {domain.Recurrence{Frequency: domain.RecurMonthly, Anchor: day(2026, 1, 31)}, day(2026, 1, 31), day(2026, 2, 28)},
+		"month end does not drift": {domain.Recurrence{Frequency: domain.RecurMonthly, Anchor: day(2026, 1, 31)}, day(2026, 2, 28), day(2026, 3, 31)},
+		"leap day":                 {domain.Recurrence{Frequency: domain.RecurYearly, Anchor: day(2028, 2, 29)}, day(2028, 2, 29), day(2029, 2, 28)},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.r.Next(tc.after))
+		})
+	}
+}
+
+func TestTask_RollForward(t *testing.T) {
+	due := time.Date(2026, 3, 10, 17, 0, 0, 0, time.UTC)
+	completed := due.Add(-48 * time.Hour)
+	task := &domain.Task{
+		Status:      domain.TaskStatusDone,
+		DueDate:     &due,
+		CompletedAt: &completed,
+		Recurrence:  &domain.Recurrence{Frequency: domain.RecurWeekly, Anchor: due},
+	}
+
+	task.RollForward(completed)
+
+	assert.Equal(t, domain.TaskStatusTodo, task.Status)
+	assert.Nil(t, task.CompletedAt)
+	assert.Equal(t, due.AddDate(0, 0, 7), *task.DueDate, "completing early moves on to the next date, not the current one")
+}
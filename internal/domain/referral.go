@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Referral sources: how the referrer was identified at signup.
+const (
+	ReferralSourceCode   = "code"   // referral_code in the register payload
+	ReferralSourceLink   = "link"   // ?ref= on the register URL
+	ReferralSourceInvite = "invite" // no referral code; the invite's creator is credited
+)
+
+// Referral statuses. A referral is qualified once the referred user
+// completes their first task, and credited once the billing module has
+// granted the referrer's reward.
+const (
+	ReferralSignedUp  = "signed_up"
+	ReferralQualified = "qualified"
+	ReferralCredited  = "credited"
+)
+
+// Referral attributes one signup to the user who brought it in. Each user is
+// referred at most once.
+type Referral struct {
+	ID           uuid.UUID  `json:"id" db:"id"`
+	ReferrerID   uuid.UUID  `json:"-" db:"referrer_id"`
+	ReferredID   uuid.UUID  `json:"-" db:"referred_id"`
+	ReferredName string     `json:"referred_name" db:"referred_name"`
+	Code         string     `json:"code" db:"code"`
+	Source       string     `json:"source" db:"source"`
+	Status       string     `json:"status" db:"status"`
+	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
+	QualifiedAt  *time.Time `json:"qualified_at,omitempty" db:"qualified_at"`
+	CreditedAt   *time.Time `json:"credited_at,omitempty" db:"credited_at"`
+}
+
+// ReferralCounts tallies a user's referrals by status.
+type ReferralCounts struct {
+	Total     int `json:"total"`
+	SignedUp  int `json:"signed_up"`
+	Qualified int `json:"qualified"`
+	Credited  int `json:"credited"`
+}
+
+// ReferralSummary is the user's referral code with the signups it brought in.
+type ReferralSummary struct {
+	Code      string         `json:"code"`
+	Counts    ReferralCounts `json:"counts"`
+	Referrals []*Referral    `json:"referrals"`
+}
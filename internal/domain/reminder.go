@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reminder statuses.
+const (
+	ReminderPending   = "pending"
+	ReminderFired     = "fired"
+	ReminderDismissed = "dismissed"
+)
+
+// Reminder notifies a task's owner at a set time. It is either absolute
+// (RemindAt) or relative to the task's due date (OffsetMinutes before it);
+// FireAt is when it goes off, and follows the due date for offset reminders.
+type Reminder struct {
+	ID            uuid.UUID  `json:"id" db:"id"`
+	TaskID        uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID        uuid.UUID  `json:"user_id" db:"user_id"`
+	RemindAt      *time.Time `json:"remind_at,omitempty" db:"remind_at"`
+	OffsetMinutes *int       `json:"offset_minutes,omitempty" db:"offset_minutes"`
+	// FireAt is nil while an offset reminder's task has no due date.
+	FireAt      *time.Time `json:"fire_at,omitempty" db:"fire_at"`
+	Status      string     `json:"status" db:"status"`
+	FiredAt     *time.Time `json:"fired_at,omitempty" db:"fired_at"`
+	DismissedAt *time.Time `json:"dismissed_at,omitempty" db:"dismissed_at"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// maxReminderOffset is how far before the due date a reminder can be, in
+// minutes: a year.
+const maxReminderOffset = 525600
+
+// CreateReminderRequest is the payload for adding a reminder to a task.
+// Exactly one of RemindAt, OffsetMinutes and Offset must be set.
+type CreateReminderRequest struct {
+	RemindAt      *time.Time `json:"remind_at" validate:"required_without_all=OffsetMinutes Offset,excluded_with=OffsetMinutes Offset"`
+	OffsetMinutes *int       `json:"offset_minutes" validate:"omitempty,min=0,max=525600,excluded_with=Offset"` // up to a year before the due date
+	// Offset is OffsetMinutes written as a duration before the due date,
+	// such as "-1d" or "-1h30m"; see ParseReminderOffset.
+	Offset string `json:"offset" validate:"max=20"`
+}
+
+// ParseReminderOffset reads a reminder offset such as "-1d", "-2h" or
+// "-1w2d" as minutes before the due date. Units are w, d, h and m; the
+// leading minus is optional, since reminders always come before the due date.
+func ParseReminderOffset(s string) (int, error) {
+	rest := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "-")
+	if rest == "" {
+		return 0, fmt.Errorf("offset %q is empty; use e.g. -1d, -2h or -30m", s)
+	}
+	minutes := 0
+	for rest != "" {
+		i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return 0, fmt.Errorf("offset %q must be amounts with units w, d, h or m, e.g. -1d", s)
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return 0, fmt.Errorf("offset %q is too large", s)
+		}
+		unit := map[byte]int{'w': 7 * 24 * 60, 'd': 24 * 60, 'h': 60, 'm': 1}[rest[i]]
+		if unit == 0 {
+			return 0, fmt.Errorf("offset %q must be amounts with units w, d, h or m, e.g. -1d", s)
+		}
+		minutes += n * unit
+		if minutes > maxReminderOffset {
+			return 0, fmt.Errorf("offset %q is more than a year", s)
+		}
+		rest = rest[i+1:]
+	}
+	return minutes, nil
+}
@@ -14,6 +14,15 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	Update(ctx context.Context, user *User) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	ListIDs(ctx context.Context) ([]uuid.UUID, error)
+	SetAdmin(ctx context.Context, id uuid.UUID, isAdmin bool) error
+	SetRanker(ctx context.Context, id uuid.UUID, ranker *string) error
+	FindByReferralCode(ctx context.Context, code string) (*User, error)
+	SetChangelogSeen(ctx context.Context, id uuid.UUID, at time.Time) error
+	SetTimezone(ctx context.Context, id uuid.UUID, tz string) error
+	// SetPasswordHash replaces the user's password digest, as when it is
+	// upgraded at login, without touching updated_at.
+	SetPasswordHash(ctx context.Context, id uuid.UUID, digest string) error
 }
 
 // RefreshTokenRepository defines data access for refresh tokens.
@@ -28,25 +37,606 @@ type RefreshTokenRepository interface {
 // TaskRepository defines data access for tasks.
 type TaskRepository interface {
 	Create(ctx context.Context, task *Task) error
+	// CreateBatch inserts all of the tasks in one transaction, or none, a
+	// chunk of rows per statement. A failed chunk is reported as a
+	// *TaskBatchError.
+	CreateBatch(ctx context.Context, tasks []*Task) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Task, error)
 	List(ctx context.Context, userID uuid.UUID, filter TaskFilter, page, limit int) ([]*Task, int, error)
-	Update(ctx context.Context, task *Task) error
-	Delete(ctx context.Context, id uuid.UUID) error
+	// Stream calls fn with every task matching filter, in List's order,
+	// without loading them all at once. An error from fn stops it and is
+	// returned as is.
+	Stream(ctx context.Context, userID uuid.UUID, filter TaskFilter, fn func(*Task) error) error
+	// Update and Delete write only while the task is at ifVersion, when
+	// given, and fail with ErrPreconditionFailed if it has moved on.
+	Update(ctx context.Context, task *Task, ifVersion *int64) error
+	Delete(ctx context.Context, id uuid.UUID, ifVersion *int64) error
 	CountByUserID(ctx context.Context, userID uuid.UUID) (int, error)
-	FindOverdue(ctx context.Context, userID uuid.UUID) ([]*Task, error)
+	// FindOverdue returns up to limit of the user's overdue tasks, most
+	// overdue first, starting after the cursor when one is given.
+	FindOverdue(ctx context.Context, userID uuid.UUID, after *OverdueCursor, limit int) ([]*Task, error)
+	FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*Task, error)
+	SetProject(ctx context.Context, ids []uuid.UUID, projectID *uuid.UUID) error
+	// ListModifiedSince returns tasks, including soft-deleted ones, updated
+	// after since, oldest change first.
+	ListModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*Task, error)
+	// FuzzyFind returns live, unarchived tasks whose title is at least threshold similar
+	// to q, best match first.
+	FuzzyFind(ctx context.Context, userID uuid.UUID, q string, threshold float64, limit int) ([]*TaskMatch, error)
+	ListRecentlyModified(ctx context.Context, userID uuid.UUID, limit int) ([]*Task, error)
+	// SetArchived archives the task at archivedAt, or unarchives it when nil.
+	SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error
+	SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error
+	// NextSortOrder returns the lowest sort_order among the user's tasks
+	// above after (or overall when after is nil), ignoring excludeID; nil
+	// when there is none.
+	NextSortOrder(ctx context.Context, userID, excludeID uuid.UUID, after *float64) (*float64, error)
+	SetSortOrder(ctx context.Context, id uuid.UUID, sortOrder float64) error
+	// RenumberSortOrder spaces the user's manual order out evenly without
+	// changing it.
+	RenumberSortOrder(ctx context.Context, userID uuid.UUID) error
+	// RecalculateScores recomputes the smart score of the user's pending
+	// (todo) tasks in one statement and returns how many changed. It leaves
+	// updated_at alone: a rescore is not an edit.
+	RecalculateScores(ctx context.Context, userID uuid.UUID) (int, error)
+	// ScoreStarted scores open tasks whose start date has passed but which
+	// still carry the zero score of a deferred task, and returns how many.
+	ScoreStarted(ctx context.Context) (int, error)
+	// ApplyAging sets the aging points of every open todo task to perDay
+	// for each day it has spent in todo beyond after, at most maxPoints
+	// (nil for no cap), rescoring those that changed.
+	ApplyAging(ctx context.Context, after time.Duration, perDay float64, maxPoints *float64) (int, error)
+	// FlagEffortExceeded sets EffortExceeded on in-progress tasks with no
+	// update or logged time for longer than their estimate, and returns the
+	// tasks it newly flagged.
+	FlagEffortExceeded(ctx context.Context, limit int) ([]*Task, error)
+	// ListScheduled returns the user's open tasks whose time slot overlaps
+	// [from, to), earliest first.
+	ListScheduled(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]*Task, error)
+	// ListAgenda returns the user's open, started tasks that are due before
+	// dueBefore or among the topScored highest smart scores, earliest due
+	// first and undated ones last.
+	ListAgenda(ctx context.Context, userID uuid.UUID, dueBefore time.Time, topScored, limit int) ([]*Task, error)
+	// ListSuitable returns the user's open, started, unblocked tasks that
+	// take no more than energy and can be done in taskContext; tasks without
+	// an energy or a context fit any. A nil energy or an empty context does
+	// not filter. Tasks in exactly that context come first, then those at
+	// exactly that energy, each by smart score.
+	ListSuitable(ctx context.Context, userID uuid.UUID, energy *TaskEnergy, taskContext string, limit int) ([]*Task, error)
+}
+
+// SmartViewRepository evaluates the built-in smart lists.
+type SmartViewRepository interface {
+	// Counts returns the number of tasks in every system view, keyed by view key.
+	Counts(ctx context.Context, userID uuid.UUID, w ViewWindow) (map[string]int, error)
+	List(ctx context.Context, userID uuid.UUID, key string, w ViewWindow, page, limit int) ([]*Task, int, error)
+	Badges(ctx context.Context, userID uuid.UUID, w ViewWindow) (*Badges, error)
+}
+
+// TaskViewRepository tracks which tasks a user opened most recently. It is
+// kept out of PostgreSQL since every task read produces a write.
+type TaskViewRepository interface {
+	Record(ctx context.Context, userID, taskID uuid.UUID, at time.Time) error
+	// ListRecent returns task ids, most recently viewed first.
+	ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error)
 }
 
 // ProjectRepository defines data access for projects.
 type ProjectRepository interface {
 	Create(ctx context.Context, project *Project) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Project, error)
+	// ListByUserID returns the unarchived projects the user owns or is a
+	// member of.
 	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*Project, error)
-	Update(ctx context.Context, project *Project) error
+	// ListFields is ListByUserID loading only fields, or every column when
+	// fields is nil; archived lists only the archived projects instead.
+	ListFields(ctx context.Context, userID uuid.UUID, fields Fields, archived bool) ([]*Project, error)
+	// Update writes only while the project is at ifVersion, when given,
+	// and fails with ErrPreconditionFailed if it has moved on.
+	Update(ctx context.Context, project *Project, ifVersion *int64) error
+	// Delete soft-deletes the project and, in the same transaction, deals
+	// with its live tasks as strategy says, returning how many it moved or
+	// deleted. ProjectDeleteBlock fails with ErrProjectNotEmpty while there
+	// are any; a non-nil ifVersion fails with ErrPreconditionFailed unless
+	// the project is still at that version.
+	Delete(ctx context.Context, id uuid.UUID, strategy ProjectDeleteStrategy, ifVersion *int64) (int, error)
+	SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error
+	// Duplicate creates project as a copy of sourceID and, when withTasks
+	// is set, copies the source's open tasks into it in the same
+	// transaction, returning how many. Copies get new ids, belong to the
+	// project's owner and start over as todo; their dates move by
+	// shiftDays. Subtasks, dependencies and the owner's tags come along
+	// between copied tasks.
+	Duplicate(ctx context.Context, sourceID uuid.UUID, project *Project, withTasks bool, shiftDays int) (int, error)
+	// Stats counts the project's live tasks in one query, judging overdue
+	// tasks in the time zone of userID.
+	Stats(ctx context.Context, projectID, userID uuid.UUID) (*ProjectStats, error)
+}
+
+// ProjectMemberRepository defines data access for the users a project is
+// shared with.
+type ProjectMemberRepository interface {
+	Add(ctx context.Context, member *ProjectMember) error
+	Remove(ctx context.Context, projectID, userID uuid.UUID) error
+	// ListByProjectID returns the project's members, with their email and
+	// name, in the order they joined.
+	ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]*ProjectMember, error)
+	CountByProjectID(ctx context.Context, projectID uuid.UUID) (int, error)
+	IsMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error)
+}
+
+// TagRepository defines data access for tags and their task assignments.
+type TagRepository interface {
+	Create(ctx context.Context, tag *Tag) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Tag, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*Tag, error)
+	// CountOwned returns how many of ids are tags owned by userID.
+	CountOwned(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error)
+	Update(ctx context.Context, tag *Tag) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*Tag, error)
+	// SetForTask replaces the task's tags with tagIDs.
+	SetForTask(ctx context.Context, taskID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
+// TaskDependencyRepository defines data access for task dependencies.
+type TaskDependencyRepository interface {
+	// Add records that taskID is blocked by blockedByID; adding an existing
+	// dependency is a no-op.
+	Add(ctx context.Context, taskID, blockedByID uuid.UUID) error
+	Remove(ctx context.Context, taskID, blockedByID uuid.UUID) error
+	ListBlockers(ctx context.Context, taskID uuid.UUID) ([]*Task, error)
+	ListBlocking(ctx context.Context, taskID uuid.UUID) ([]*Task, error)
+	// CountOpenBlockers returns how many live, unfinished tasks block taskID.
+	CountOpenBlockers(ctx context.Context, taskID uuid.UUID) (int, error)
+	// DependsOn reports whether taskID is blocked, directly or transitively,
+	// by otherID.
+	DependsOn(ctx context.Context, taskID, otherID uuid.UUID) (bool, error)
+}
+
+// DueDateRuleRepository defines data access for due-date defaulting rules.
+type DueDateRuleRepository interface {
+	Create(ctx context.Context, rule *DueDateRule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*DueDateRule, error)
+	// ListByUserID returns the user's rules in evaluation order.
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*DueDateRule, error)
+	Update(ctx context.Context, rule *DueDateRule) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+// AutomationRuleRepository defines data access for automation rules and their
+// execution log.
+type AutomationRuleRepository interface {
+	Create(ctx context.Context, rule *AutomationRule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*AutomationRule, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*AutomationRule, error)
+	// ListEnabled returns the user's enabled rules for trigger, oldest first.
+	ListEnabled(ctx context.Context, userID uuid.UUID, trigger string) ([]*AutomationRule, error)
+	// ListUsersWithTrigger returns every user with an enabled rule for trigger.
+	ListUsersWithTrigger(ctx context.Context, trigger string) ([]uuid.UUID, error)
+	Update(ctx context.Context, rule *AutomationRule) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// RecordFailure counts a failed run, opening the breaker until openUntil
+	// once failures reach threshold, and returns the new failure count.
+	RecordFailure(ctx context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error)
+	// ResetBreaker closes the breaker and clears the failure count.
+	ResetBreaker(ctx context.Context, id uuid.UUID) error
+
+	CreateExecution(ctx context.Context, e *AutomationExecution) error
+	UpdateExecution(ctx context.Context, e *AutomationExecution) error
+	// ListExecutions returns a rule's executions, newest first.
+	ListExecutions(ctx context.Context, ruleID uuid.UUID, limit int) ([]*AutomationExecution, error)
+	// HasExecuted reports whether the rule has run against the task since since.
+	HasExecuted(ctx context.Context, ruleID, taskID uuid.UUID, since time.Time) (bool, error)
+}
+
+// AttachmentRepository defines data access for task attachments.
+type AttachmentRepository interface {
+	Create(ctx context.Context, a *Attachment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Attachment, error)
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*Attachment, error)
+	MarkUploaded(ctx context.Context, id uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListPendingBefore returns uploads never confirmed that were started
+	// before before, oldest first.
+	ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]*Attachment, error)
+}
+
 // AnalyticsRepository defines data access for analytics queries.
 type AnalyticsRepository interface {
 	GetDashboard(ctx context.Context, userID uuid.UUID) (*AnalyticsDashboard, error)
 	GetDailyStats(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]DailyStats, error)
+	// EstimateAccuracy compares estimates with actual hours for tasks
+	// completed since then.
+	EstimateAccuracy(ctx context.Context, userID uuid.UUID, since time.Time) (*EstimateAccuracy, error)
+	// Query evaluates a structured analytics query. Unknown metrics or
+	// groupings are rejected with ErrValidation.
+	Query(ctx context.Context, userID uuid.UUID, q AnalyticsQuery) ([]AnalyticsRow, error)
+	// StreamRawEvents calls fn with every creation and completion from from
+	// up to to, in time order.
+	StreamRawEvents(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*AnalyticsRawEvent) error) error
+}
+
+// EmailSuppressionRepository defines data access for suppressed email addresses.
+type EmailSuppressionRepository interface {
+	Upsert(ctx context.Context, s *EmailSuppression) error
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+// NotificationRepository defines data access for in-app notifications.
+type NotificationRepository interface {
+	Create(ctx context.Context, n *Notification) error
+	ListByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*Notification, int, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, id, userID uuid.UUID) error
+	MarkAllRead(ctx context.Context, userID uuid.UUID) error
+	Snooze(ctx context.Context, id, userID uuid.UUID, until time.Time) error
+	ListSnoozedDue(ctx context.Context, before time.Time, limit int) ([]*Notification, error)
+	Resurface(ctx context.Context, ids []uuid.UUID) error
+}
+
+// QuietHoursRepository defines data access for do-not-disturb schedules.
+type QuietHoursRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*QuietHours, error)
+	Upsert(ctx context.Context, q *QuietHours) error
+}
+
+// DeferredNotificationRepository defines data access for notifications held back by do-not-disturb.
+type DeferredNotificationRepository interface {
+	Create(ctx context.Context, n *DeferredNotification) error
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*DeferredNotification, error)
+	DeleteByIDs(ctx context.Context, ids []uuid.UUID) error
+}
+
+// WebhookRepository defines data access for webhook subscriptions.
+type WebhookRepository interface {
+	Create(ctx context.Context, w *Webhook) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Webhook, error)
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]*Webhook, error)
+	ListSubscribed(ctx context.Context, userID uuid.UUID, event string) ([]*Webhook, error)
+	Update(ctx context.Context, w *Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// RecordFailure counts a failed delivery attempt, opening the breaker
+	// until openUntil once failures reach threshold, and returns the new
+	// failure count.
+	RecordFailure(ctx context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error)
+	// ResetBreaker closes the breaker and clears the failure count.
+	ResetBreaker(ctx context.Context, id uuid.UUID) error
+}
+
+// WebhookDeliveryRepository defines data access for stored webhook deliveries.
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, d *WebhookDelivery) error
+	FindByID(ctx context.Context, id uuid.UUID) (*WebhookDelivery, error)
+	ListByWebhookID(ctx context.Context, webhookID uuid.UUID, page, limit int) ([]*WebhookDelivery, int, error)
+	RecordAttempt(ctx context.Context, d *WebhookDelivery) error
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TaskEventRepository defines data access for the task audit log.
+type TaskEventRepository interface {
+	Create(ctx context.Context, e *TaskEvent) error
+	// FindLatest returns the newest event for the task recorded at or before asOf.
+	FindLatest(ctx context.Context, taskID uuid.UUID, asOf time.Time) (*TaskEvent, error)
+	// ListActivity returns one page of the task's events, newest first,
+	// leaving out updates that changed no tracked field, and their total.
+	ListActivity(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*TaskEvent, int, error)
+}
+
+// RetentionRepository defines data access for per-user retention overrides.
+type RetentionRepository interface {
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]RetentionOverride, error)
+	Upsert(ctx context.Context, o *RetentionOverride) error
+	Delete(ctx context.Context, userID uuid.UUID, entityType string) error
+}
+
+// MaintenanceRepository defines database housekeeping operations run by operators.
+// List* methods are read-only and return ids grouped by entity type; the
+// matching write methods act on exactly those ids.
+type MaintenanceRepository interface {
+	ReindexTasks(ctx context.Context) error
+	ListTrash(ctx context.Context, deletedBefore time.Time) (map[string][]uuid.UUID, error)
+	ListExpiredTrash(ctx context.Context, asOf time.Time, defaults RetentionPolicy) (map[string][]uuid.UUID, error)
+	PurgeTrash(ctx context.Context, affected map[string][]uuid.UUID) error
+	ListUserData(ctx context.Context, userID uuid.UUID) (map[string][]uuid.UUID, error)
+	HardDeleteUser(ctx context.Context, userID uuid.UUID) error
+	// Ping runs a trivial query, for measuring database health.
+	Ping(ctx context.Context) error
+}
+
+// TaskLinkRepository defines data access for links attached to tasks.
+type TaskLinkRepository interface {
+	Create(ctx context.Context, link *TaskLink) error
+	FindByID(ctx context.Context, id uuid.UUID) (*TaskLink, error)
+	// ListByTaskID returns the task's links in the order they were added.
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*TaskLink, error)
+	CountByTaskID(ctx context.Context, taskID uuid.UUID) (int, error)
+	Update(ctx context.Context, link *TaskLink) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// ReminderRepository defines data access for task reminders.
+type ReminderRepository interface {
+	Create(ctx context.Context, r *Reminder) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Reminder, error)
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*Reminder, error)
+	CountPending(ctx context.Context, taskID uuid.UUID) (int, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// ListDue returns pending reminders with fire_at at or before now, oldest first.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*Reminder, error)
+	// MarkFired moves a pending reminder to fired. It returns ErrNotFound when
+	// the reminder is no longer pending, so concurrent workers fire it once.
+	MarkFired(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Dismiss moves a reminder to dismissed; dismissing twice keeps the first time.
+	Dismiss(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Reschedule recomputes fire_at of the task's pending offset reminders
+	// from dueDate, unscheduling them when dueDate is nil.
+	Reschedule(ctx context.Context, taskID uuid.UUID, dueDate *time.Time) error
+}
+
+// TimeEntryRepository defines data access for time tracked on tasks.
+type TimeEntryRepository interface {
+	// Create inserts an entry; a second running entry for the same user
+	// fails with ErrAlreadyExists.
+	Create(ctx context.Context, e *TimeEntry) error
+	// FindRunning returns the user's running entry, or ErrNotFound.
+	FindRunning(ctx context.Context, userID uuid.UUID) (*TimeEntry, error)
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*TimeEntry, error)
+	// Stop ends a running entry at the given time and adds its duration to
+	// the task's tracked_seconds in the same statement. It returns
+	// ErrNotFound when the entry is not running.
+	Stop(ctx context.Context, id uuid.UUID, at time.Time) (*TimeEntry, error)
+}
+
+// InviteRepository defines data access for signup invite codes.
+type InviteRepository interface {
+	Create(ctx context.Context, c *InviteCode) error
+	FindByID(ctx context.Context, id uuid.UUID) (*InviteCode, error)
+	ListByCreator(ctx context.Context, userID uuid.UUID) ([]*InviteCode, error)
+	// CountCharged counts the user's codes that use up quota: every code
+	// except revoked ones that were never redeemed.
+	CountCharged(ctx context.Context, userID uuid.UUID) (int, error)
+	// Redeem atomically takes one use of a usable code, returning
+	// ErrNotFound when the code is unknown, expired, revoked or used up.
+	Redeem(ctx context.Context, code string, now time.Time) (*InviteCode, error)
+	// Release gives back a use taken by Redeem.
+	Release(ctx context.Context, id uuid.UUID) error
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// ReferralRepository defines data access for signup referrals.
+type ReferralRepository interface {
+	// Create records a referral; a second one for the same referred user
+	// fails with ErrAlreadyExists.
+	Create(ctx context.Context, r *Referral) error
+	// ListByReferrer returns the user's referrals, newest first, with the
+	// referred user's name filled in.
+	ListByReferrer(ctx context.Context, referrerID uuid.UUID) ([]*Referral, error)
+	// Qualify moves the referred user's referral from signed_up to
+	// qualified, returning ErrNotFound when there is none to move.
+	Qualify(ctx context.Context, referredID uuid.UUID, at time.Time) (*Referral, error)
+	// ListQualified returns qualified referrals still waiting for credit,
+	// oldest first.
+	ListQualified(ctx context.Context, limit int) ([]*Referral, error)
+	MarkCredited(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// ChangelogRepository defines data access for product changelog entries.
+type ChangelogRepository interface {
+	// Create inserts an entry; a duplicate version fails with ErrAlreadyExists.
+	Create(ctx context.Context, e *ChangelogEntry) error
+	// ListPublished returns entries published at or before now, newest first.
+	ListPublished(ctx context.Context, now time.Time, limit int) ([]*ChangelogEntry, error)
+}
+
+// FeedbackRepository defines data access for user feedback reports.
+type FeedbackRepository interface {
+	Create(ctx context.Context, f *Feedback) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Feedback, error)
+	// List returns reports matching the filter, newest first, with the
+	// total count for pagination.
+	List(ctx context.Context, filter FeedbackFilter) ([]*Feedback, int, error)
+	// RecordForward stores the outcome of forwarding a report: forwardErr
+	// empty marks it forwarded at the given time.
+	RecordForward(ctx context.Context, id uuid.UUID, at time.Time, forwardErr string) error
+}
+
+// ClientErrorRepository defines data access for client error telemetry.
+type ClientErrorRepository interface {
+	CreateBatch(ctx context.Context, errs []*ClientError) error
+	FindByID(ctx context.Context, id uuid.UUID) (*ClientError, error)
+	// List returns reports matching the filter, newest first, with the
+	// total count for pagination.
+	List(ctx context.Context, filter ClientErrorFilter) ([]*ClientError, int, error)
+	// Groups returns fingerprints reported since the given time, most
+	// frequent first.
+	Groups(ctx context.Context, since time.Time, limit int) ([]*ClientErrorGroup, error)
+	DeleteOlderThan(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TaskOccurrenceRepository defines data access for the completed
+// occurrences of recurring tasks.
+type TaskOccurrenceRepository interface {
+	Create(ctx context.Context, o *TaskOccurrence) error
+	// ListByTaskID returns a task's occurrences, most recent first, with the
+	// total count for pagination.
+	ListByTaskID(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*TaskOccurrence, int, error)
+	StatsByTaskID(ctx context.Context, taskID uuid.UUID) (*TaskOccurrenceStats, error)
+}
+
+// BusinessCalendarRepository defines data access for users' working weeks.
+type BusinessCalendarRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*BusinessCalendar, error)
+	Upsert(ctx context.Context, c *BusinessCalendar) error
+}
+
+// DayOffRepository defines data access for user-defined days off.
+type DayOffRepository interface {
+	Create(ctx context.Context, d *DayOff) error
+	FindByID(ctx context.Context, id uuid.UUID) (*DayOff, error)
+	// ListByUserID returns the user's days off from the given date on
+	// (YYYY-MM-DD, empty for all), in date order.
+	ListByUserID(ctx context.Context, userID uuid.UUID, from string) ([]*DayOff, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// TaskRevisionRepository defines data access for earlier task descriptions.
+type TaskRevisionRepository interface {
+	// Create stores a revision and deletes the task's oldest ones beyond keep.
+	Create(ctx context.Context, rev *TaskRevision, keep int) error
+	FindByID(ctx context.Context, id uuid.UUID) (*TaskRevision, error)
+	// ListByTaskID returns a task's revisions, most recent first.
+	ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*TaskRevision, error)
+}
+
+// TaskEscalationRepository defines data access for the audit trail of
+// automatic priority escalations.
+type TaskEscalationRepository interface {
+	Create(ctx context.Context, e *TaskEscalation) error
+	// ListByTaskID returns a task's escalations, most recent first, with the
+	// total count for pagination.
+	ListByTaskID(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*TaskEscalation, int, error)
+	// ListCandidates returns open tasks to escalate: low ones due by
+	// mediumBy and low or medium ones due by highBy. A nil bound disables
+	// that step.
+	ListCandidates(ctx context.Context, mediumBy, highBy *time.Time, limit int) ([]*Task, error)
+	// ListAgingCandidates returns todo tasks to raise for their age: low
+	// ones in todo since mediumSince and low or medium ones since
+	// highSince. A nil bound disables that step.
+	ListAgingCandidates(ctx context.Context, mediumSince, highSince *time.Time, limit int) ([]*Task, error)
+}
+
+// CalendarFeedRepository stores each user's iCalendar feed token.
+type CalendarFeedRepository interface {
+	// Upsert replaces the user's feed, invalidating the old token.
+	Upsert(ctx context.Context, feed *CalendarFeed) error
+	FindByToken(ctx context.Context, token string) (*CalendarFeed, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*CalendarFeed, error)
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// MeteringRepository records daily usage for the billing module and
+// reconciles it with what was invoiced. Days are UTC dates; rows already
+// invoiced are left as they are.
+type MeteringRepository interface {
+	// RecordActivity upserts the active_users and tasks_created rows of day
+	// and returns how many were written.
+	RecordActivity(ctx context.Context, day, at time.Time) (int, error)
+	// RecordStorage upserts day's storage_bytes rows with the bytes each
+	// user holds now and returns how many were written.
+	RecordStorage(ctx context.Context, day, at time.Time) (int, error)
+	// Reconcile totals every metric over the days from through to.
+	Reconcile(ctx context.Context, from, to time.Time) ([]MeterReconciliation, error)
+	ListMismatched(ctx context.Context, from, to time.Time, limit int) ([]*MeteringEvent, error)
+}
+
+// InstanceSettingsRepository stores the instance's setting overrides.
+type InstanceSettingsRepository interface {
+	// Get returns ErrNotFound while nothing has been overridden.
+	Get(ctx context.Context) (*InstanceOverrides, error)
+	Save(ctx context.Context, o *InstanceOverrides) error
+}
+
+// BackupRepository records backups and reads and replaces the data they
+// hold. Dump and Restore cover the same tables in the same order, parents
+// before the tables referencing them.
+type BackupRepository interface {
+	Create(ctx context.Context, b *Backup) error
+	// Finish saves a backup's outcome.
+	Finish(ctx context.Context, b *Backup) error
+	FindByID(ctx context.Context, id uuid.UUID) (*Backup, error)
+	List(ctx context.Context) ([]*Backup, error)
+	// ListPrunable returns the succeeded scheduled backups beyond the
+	// newest keep, and the unfinished or failed ones created before cutoff.
+	ListPrunable(ctx context.Context, keep int, cutoff time.Time) ([]*Backup, error)
+	MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Columns returns the current columns of every table Dump covers.
+	Columns(ctx context.Context) (map[string][]string, error)
+	// Dump reads every table from one consistent snapshot.
+	Dump(ctx context.Context) ([]BackupTable, error)
+	// Restore replaces the contents of every table with the given rows in
+	// one transaction.
+	Restore(ctx context.Context, tables []BackupTable) error
+}
+
+// ScheduleClaimRepository records which instance runs each slot of a
+// scheduled task; it satisfies jobs.Claimer.
+type ScheduleClaimRepository interface {
+	// Claim records holder as running task for slot and reports true,
+	// unless the task already ran for slot or a later one.
+	Claim(ctx context.Context, task string, slot time.Time, holder string) (bool, error)
+}
+
+// TwoFactorRepository defines data access for TOTP enrollments and their
+// recovery codes.
+type TwoFactorRepository interface {
+	// Find returns the user's enrollment, pending or enabled.
+	Find(ctx context.Context, userID uuid.UUID) (*TwoFactor, error)
+	// SavePending stores a new unverified secret, replacing a pending one;
+	// ErrAlreadyExists when two-factor authentication is already enabled.
+	SavePending(ctx context.Context, tf *TwoFactor) error
+	// Enable marks the enrollment verified at step and replaces the
+	// recovery codes with codeHashes, in one transaction.
+	Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes []string, at time.Time) error
+	// AcceptStep records a right code of step, clearing the failures; false
+	// when a code of that step or a later one was already accepted.
+	AcceptStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+	// UseRecoveryCode spends the unused recovery code with codeHash,
+	// clearing the failures; false when there is none.
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, at time.Time) (bool, error)
+	// RecordFailure counts a wrong code.
+	RecordFailure(ctx context.Context, userID uuid.UUID, at time.Time) error
+	// Delete removes the enrollment and its recovery codes.
+	Delete(ctx context.Context, userID uuid.UUID) error
+}
+
+// PersonalAccessTokenRepository defines data access for personal access
+// tokens.
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, t *PersonalAccessToken) error
+	// FindByHash returns the token with the given digest, ErrNotFound if
+	// there is none.
+	FindByHash(ctx context.Context, tokenHash string) (*PersonalAccessToken, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*PersonalAccessToken, error)
+	// CountActive counts the user's tokens that are neither revoked nor
+	// expired at now.
+	CountActive(ctx context.Context, userID uuid.UUID, now time.Time) (int, error)
+	// Touch records a use of the token.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Revoke revokes one of the user's tokens, ErrNotFound if they have no
+	// token with that ID.
+	Revoke(ctx context.Context, id, userID uuid.UUID, at time.Time) error
+}
+
+// BrandingRepository stores the workspace branding.
+type BrandingRepository interface {
+	// Get returns ErrNotFound while no branding has been saved.
+	Get(ctx context.Context) (*WorkspaceBranding, error)
+	Save(ctx context.Context, b *WorkspaceBranding) error
+}
+
+// OperationRepository stores async operations and their outcomes.
+type OperationRepository interface {
+	Create(ctx context.Context, op *Operation) error
+	// FindByID returns the operation without its file.
+	FindByID(ctx context.Context, id uuid.UUID) (*Operation, error)
+	FindFile(ctx context.Context, id uuid.UUID) (*Download, error)
+	// Start moves a pending operation to running; ErrNotFound when it is
+	// no longer pending.
+	Start(ctx context.Context, id uuid.UUID) error
+	// Heartbeat records progress on a running operation and returns
+	// whether its cancellation has been requested.
+	Heartbeat(ctx context.Context, id uuid.UUID, progress int) (bool, error)
+	// Finish stores the final status, outcome and file of an operation.
+	Finish(ctx context.Context, op *Operation, file *Download) error
+	// RequestCancel flags an unfinished operation for cancellation; one
+	// still pending is cancelled outright.
+	RequestCancel(ctx context.Context, id uuid.UUID) error
+	// FailStale fails unfinished operations not updated since before, whose
+	// instance has presumably gone away, and returns how many.
+	FailStale(ctx context.Context, before time.Time) (int, error)
+	// DeleteFinishedBefore removes operations finished before the cutoff,
+	// files included, and returns how many.
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error)
 }
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entity types covered by the soft-delete retention policy.
+const (
+	RetentionTasks    = "tasks"
+	RetentionProjects = "projects"
+)
+
+// RetentionPolicy maps entity type to the number of days soft-deleted rows
+// are kept before the purge job hard-deletes them.
+type RetentionPolicy map[string]int
+
+// RetentionOverride replaces the default retention window of one entity type
+// for a single user's data.
+type RetentionOverride struct {
+	UserID        uuid.UUID `json:"user_id" db:"user_id"`
+	EntityType    string    `json:"entity_type" db:"entity_type"`
+	RetentionDays int       `json:"retention_days" db:"retention_days"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SetRetentionOverrideRequest is the payload for overriding a user's retention window.
+type SetRetentionOverrideRequest struct {
+	EntityType    string `json:"entity_type" validate:"required,oneof=tasks projects"`
+	RetentionDays int    `json:"retention_days" validate:"required,min=1,max=3650"`
+}
+
+// UserRetention is the effective policy for one user: the defaults merged
+// with that user's overrides.
+type UserRetention struct {
+	UserID    uuid.UUID           `json:"user_id"`
+	Effective RetentionPolicy     `json:"effective"`
+	Overrides []RetentionOverride `json:"overrides"`
+}
+
+// RetentionReport previews the next scheduled purge run.
+type RetentionReport struct {
+	Defaults  RetentionPolicy  `json:"defaults"`
+	NextRunAt time.Time        `json:"next_run_at"`
+	Purge     *OperationResult `json:"purge"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tag is a user-defined label that can be attached to any number of tasks.
+type Tag struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	Name      string    `json:"name" db:"name"`
+	Color     string    `json:"color" db:"color"`
+	TaskCount int       `json:"task_count" db:"task_count"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateTagRequest is the payload for creating a tag. Names are unique per
+// user, ignoring case.
+type CreateTagRequest struct {
+	Name  string `json:"name" validate:"required,min=1,max=50"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// UpdateTagRequest is the payload for updating a tag.
+type UpdateTagRequest struct {
+	Name  *string `json:"name" validate:"omitempty,min=1,max=50"`
+	Color *string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// SetTaskTagsRequest replaces the tags on a task; an empty list clears them.
+type SetTaskTagsRequest struct {
+	TagIDs []uuid.UUID `json:"tag_ids" validate:"max=50"`
+}
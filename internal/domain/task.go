@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,32 +30,174 @@ type Task struct {
 	ID             uuid.UUID    `json:"id" db:"id"`
 	UserID         uuid.UUID    `json:"user_id" db:"user_id"`
 	ProjectID      *uuid.UUID   `json:"project_id,omitempty" db:"project_id"`
+	ParentID       *uuid.UUID   `json:"parent_id,omitempty" db:"parent_id"`
 	Title          string       `json:"title" db:"title"`
 	Description    string       `json:"description" db:"description"`
 	Status         TaskStatus   `json:"status" db:"status"`
 	Priority       TaskPriority `json:"priority" db:"priority"`
 	EstimatedHours *float64     `json:"estimated_hours,omitempty" db:"estimated_hours"`
+	// Energy and Context describe when the task suits: the focus it takes
+	// and where it can be done (e.g. "phone" for @phone), both optional.
+	Energy         *TaskEnergy  `json:"energy,omitempty" db:"energy"`
+	Context        *string      `json:"context,omitempty" db:"context"`
 	DueDate        *time.Time   `json:"due_date,omitempty" db:"due_date"`
+	// DueExpr is a symbolic due date, such as "end of month", that DueDate
+	// was resolved from; see ParseDueExpr.
+	DueExpr        *string      `json:"due_expr,omitempty" db:"due_expr"`
+	// StartDate defers the task: until then it is left out of default lists
+	// and the Today view, and scores nothing.
+	StartDate      *time.Time   `json:"start_date,omitempty" db:"start_date"`
+	// ScheduledAt plans the task for a time slot of ScheduledDuration
+	// minutes, independent of its due date.
+	ScheduledAt       *time.Time `json:"scheduled_at,omitempty" db:"scheduled_at"`
+	ScheduledDuration *int       `json:"scheduled_duration,omitempty" db:"scheduled_duration"`
 	CompletedAt    *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+	// StatusChangedAt is when the task last changed status, or was created;
+	// aging counts time in todo from it.
+	StatusChangedAt time.Time   `json:"status_changed_at" db:"status_changed_at"`
+	Recurrence     *Recurrence  `json:"recurrence,omitempty" db:"recurrence"`
+	// NoEscalation opts the task out of priority auto-escalation.
+	NoEscalation   bool         `json:"no_escalation" db:"no_escalation"`
 	SmartScore     float64      `json:"smart_score" db:"smart_score"`
+	// AgePoints is the score the aging job has added for time spent in
+	// todo; it counts only while the task is in todo.
+	AgePoints      float64      `json:"age_points" db:"age_points"`
+	// SortOrder is the task's place in the user's manual order, ascending.
+	SortOrder      float64      `json:"sort_order" db:"sort_order"`
+	// TrackedSeconds is the total of the task's stopped time entries.
+	TrackedSeconds int64        `json:"tracked_seconds" db:"tracked_seconds"`
+	// ActualHours is how long the task took, set by hand or, on completion,
+	// from TrackedSeconds; compared with EstimatedHours in analytics.
+	ActualHours    *float64     `json:"actual_hours,omitempty" db:"actual_hours"`
+	// EffortExceeded flags an in-progress task left untouched for longer
+	// than its estimate; the next update or logged time clears it.
+	EffortExceeded bool         `json:"effort_exceeded" db:"effort_exceeded"`
 	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
 	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
 	DeletedAt      *time.Time   `json:"deleted_at,omitempty" db:"deleted_at"`
+	// ArchivedAt hides the task from lists without deleting it.
+	ArchivedAt     *time.Time   `json:"archived_at,omitempty" db:"archived_at"`
+	// Pinned keeps the task above all others in lists, whatever its score.
+	Pinned         bool         `json:"pinned" db:"pinned"`
+	// Version goes up by one on every write and is served as the ETag.
+	Version        int64        `json:"version" db:"version"`
+	// Blocked reports open blockers; only set by queries that compute it.
+	Blocked        bool         `json:"blocked" db:"blocked"`
+	// SubtasksTotal and SubtasksDone count the task's live subtasks and
+	// the done ones among them; only set by task lists.
+	SubtasksTotal  int          `json:"subtasks_total" db:"subtasks_total"`
+	SubtasksDone   int          `json:"subtasks_done" db:"subtasks_done"`
+	// RolledUpEstimate sums the estimates of those subtasks that have one,
+	// nil when none has. SubtaskProgress is the percentage of it done, or
+	// of the subtasks themselves without estimates; nil without subtasks.
+	// Both only look one level down and are only set by task lists.
+	RolledUpEstimate *float64   `json:"rolled_up_estimate,omitempty" db:"rolled_up_estimate"`
+	SubtaskProgress  *float64   `json:"subtask_progress,omitempty" db:"subtask_progress"`
 }
 
-// IsOverdue returns true when a task has passed its due date and is not done.
-func (t *Task) IsOverdue() bool {
+// IsOverdue returns true when a task has passed its due date and is not
+// done, for a user in loc.
+func (t *Task) IsOverdue(loc *time.Location) bool {
+	return t.IsOverdueAt(time.Now(), loc)
+}
+
+// IsOverdueAt is IsOverdue evaluated at now.
+func (t *Task) IsOverdueAt(now time.Time, loc *time.Location) bool {
+	if t.DueDate == nil || t.Status == TaskStatusDone {
+		return false
+	}
+	return now.After(t.Deadline(loc))
+}
+
+// DefaultScheduledDuration is the length of a time slot booked without one,
+// in minutes.
+const DefaultScheduledDuration = 60
+
+// ScheduledEnd returns when the task's time slot ends; ok is false when it
+// has none.
+func (t *Task) ScheduledEnd() (end time.Time, ok bool) {
+	if t.ScheduledAt == nil {
+		return time.Time{}, false
+	}
+	minutes := DefaultScheduledDuration
+	if t.ScheduledDuration != nil {
+		minutes = *t.ScheduledDuration
+	}
+	return t.ScheduledAt.Add(time.Duration(minutes) * time.Minute), true
+}
+
+// IsDeferredAt reports whether the task's start date is still ahead of now.
+func (t *Task) IsDeferredAt(now time.Time) bool {
+	return t.StartDate != nil && t.StartDate.After(now)
+}
+
+// IsDueTodayAt reports whether an unfinished task is due on the local day
+// of now in loc.
+func (t *Task) IsDueTodayAt(now time.Time, loc *time.Location) bool {
 	if t.DueDate == nil || t.Status == TaskStatusDone {
 		return false
 	}
-	return time.Now().After(*t.DueDate)
+	w := NewViewWindow(now, loc)
+	return !t.DueDate.Before(w.DayStart) && t.DueDate.Before(w.DayEnd)
+}
+
+// Deadline returns the instant the due date lapses for a user in loc. A due
+// date at exactly local midnight is date-only: it is due all of that day,
+// until the next local midnight. Otherwise the due date itself is the
+// deadline. The task must have a due date.
+func (t *Task) Deadline(loc *time.Location) time.Time {
+	local := t.DueDate.In(loc)
+	y, m, d := local.Date()
+	if local.Equal(time.Date(y, m, d, 0, 0, 0, 0, loc)) {
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return *t.DueDate
+}
+
+// OverdueFor returns how long the task has been overdue at now, for a user
+// in loc, as measured by elapsed; nil elapsed counts every hour. Measured in
+// working hours only, a task whose deadline passed after work on Friday is
+// not yet overdue at the start of work on Monday. Zero means not overdue.
+func (t *Task) OverdueFor(now time.Time, loc *time.Location, elapsed func(from, to time.Time) time.Duration) time.Duration {
+	if !t.IsOverdueAt(now, loc) {
+		return 0
+	}
+	if elapsed == nil {
+		return now.Sub(t.Deadline(loc))
+	}
+	return elapsed(t.Deadline(loc), now)
+}
+
+// RemainingHours is the estimate less the time already tracked, never
+// below zero. ok is false when the task has no estimate.
+func (t *Task) RemainingHours() (hours float64, ok bool) {
+	if t.EstimatedHours == nil {
+		return 0, false
+	}
+	return math.Max(0, *t.EstimatedHours-float64(t.TrackedSeconds)/3600), true
+}
+
+// WorkloadHours is the work left on the task: for a task whose subtasks
+// carry estimates, the part of RolledUpEstimate not yet done, otherwise
+// RemainingHours. ok is false when there is no estimate either way.
+func (t *Task) WorkloadHours() (hours float64, ok bool) {
+	if t.RolledUpEstimate != nil && t.SubtaskProgress != nil {
+		return math.Max(0, *t.RolledUpEstimate*(1-*t.SubtaskProgress/100)), true
+	}
+	return t.RemainingHours()
 }
 
 // CalculateSmartScore computes a priority score based on multiple factors.
 // Higher score = higher urgency.
+// The repository's RecalculateScores mirrors it in SQL.
 func (t *Task) CalculateSmartScore() float64 {
 	score := 0.0
 
+	// Deferred tasks only compete once they start
+	if t.IsDeferredAt(time.Now()) {
+		return score
+	}
+
 	// Base score from manual priority
 	switch t.Priority {
 	case TaskPriorityHigh:
@@ -96,26 +239,89 @@ func (t *Task) CalculateSmartScore() float64 {
 		score += 5
 	}
 
+	// Aging — neglected todo tasks creep up
+	if t.Status == TaskStatusTodo {
+		score += t.AgePoints
+	}
+
 	return score
 }
 
-// TaskFilter holds filter criteria for listing tasks.
+// Task list orders: the user's ranker, or their drag-and-drop order.
+const (
+	TaskSortRanked = "ranked"
+	TaskSortManual = "manual"
+	// TaskSortCreated lists oldest first, for exports; GET /tasks does not
+	// accept it.
+	TaskSortCreated = "created"
+)
+
+// TaskSortGap is the spacing between neighbours in a freshly numbered
+// manual order, leaving room to drop tasks in between.
+const TaskSortGap = 1024
+
+// TaskSortValues lists the accepted sort query values.
+var TaskSortValues = []string{TaskSortRanked, TaskSortManual}
+
+// TaskFilter holds filter criteria for listing tasks. Each list matches any
+// of its values; the Exclude lists leave out tasks matching any of theirs.
 type TaskFilter struct {
-	Status    *TaskStatus  `form:"status"`
-	Priority  *TaskPriority `form:"priority"`
-	ProjectID *uuid.UUID   `form:"project_id"`
+	Statuses   []TaskStatus   `form:"status"`
+	Priorities []TaskPriority `form:"priority"`
+	ProjectIDs []uuid.UUID    `form:"project_id"`
+	ExcludeStatuses   []TaskStatus   `form:"-status"`
+	ExcludePriorities []TaskPriority `form:"-priority"`
+	ExcludeProjectIDs []uuid.UUID    `form:"-project_id"` // tasks without a project are kept
+	Energy    *TaskEnergy  `form:"energy"`
+	Context   string       `form:"context"` // normalized, without the @
+	ParentID  *uuid.UUID   `form:"parent_id"`
+	TagIDs    []uuid.UUID  `form:"tag"` // tasks carrying all of these tags
 	Overdue   *bool        `form:"overdue"`
+	// DueAfter and DueBefore bound due_date, CreatedAfter and CreatedBefore
+	// created_at, all inclusive.
+	DueAfter      *time.Time `form:"due_after"`
+	DueBefore     *time.Time `form:"due_before"`
+	CreatedAfter  *time.Time `form:"created_after"`
+	CreatedBefore *time.Time `form:"created_before"`
+	IncludeDeferred bool   `form:"include_deferred"` // also list tasks whose start date is ahead
+	// StartAfter and StartBefore bound start_date, inclusive; either one
+	// lists deferred tasks too and leaves out tasks without a start date.
+	StartAfter  *time.Time `form:"scheduled_after"`
+	StartBefore *time.Time `form:"scheduled_before"`
 	Search    string       `form:"search"`
+	Archived  *bool        `form:"archived"` // nil or false hides archived tasks; true lists only them
+	Pinned    *bool        `form:"pinned"`   // only pinned tasks when true, only unpinned when false
+	Sort      string       `form:"sort"`     // TaskSortManual orders by sort_order; anything else ranks
+	// Fields limits the columns loaded; the rest are left zero.
+	Fields    Fields       `form:"fields"`
 }
 
 // CreateTaskRequest is the payload for creating a task.
 type CreateTaskRequest struct {
 	ProjectID      *uuid.UUID   `json:"project_id"`
+	ParentID       *uuid.UUID   `json:"parent_id"`
 	Title          string       `json:"title" validate:"required,min=1,max=255"`
 	Description    string       `json:"description" validate:"max=5000"`
-	Priority       TaskPriority `json:"priority" validate:"required,oneof=low medium high"`
+	Priority       TaskPriority `json:"priority" validate:"required,task_priority"`
 	EstimatedHours *float64     `json:"estimated_hours" validate:"omitempty,min=0,max=999"`
+	Energy         *TaskEnergy  `json:"energy" validate:"omitempty,task_energy"`
+	Context        *string      `json:"context" validate:"omitempty,max=50"` // e.g. "@phone"; stored without the @
 	DueDate        *time.Time   `json:"due_date"`
+	// DueExpr sets the due date symbolically instead, e.g. "end of month".
+	DueExpr        *string      `json:"due_expr" validate:"omitempty,max=100"`
+	StartDate      *time.Time   `json:"start_date"` // hide the task until then; not after the due date
+	// ScheduledAt books a time slot of ScheduledDuration minutes (default
+	// 60). Overlapping another open task's slot is refused unless AllowOverlap.
+	ScheduledAt       *time.Time `json:"scheduled_at"`
+	ScheduledDuration *int       `json:"scheduled_duration" validate:"omitempty,min=5,max=1440"`
+	AllowOverlap      bool       `json:"allow_overlap"`
+	Recurrence     *Recurrence  `json:"recurrence"` // requires a due date
+	NoEscalation   bool         `json:"no_escalation"`
+}
+
+// PositionTaskRequest places a task just after another in the manual order.
+type PositionTaskRequest struct {
+	AfterID *uuid.UUID `json:"after_id"` // null moves the task to the top
 }
 
 // UpdateTaskRequest is the payload for updating a task.
@@ -123,8 +329,66 @@ type UpdateTaskRequest struct {
 	ProjectID      *uuid.UUID   `json:"project_id"`
 	Title          *string      `json:"title" validate:"omitempty,min=1,max=255"`
 	Description    *string      `json:"description" validate:"omitempty,max=5000"`
-	Status         *TaskStatus  `json:"status" validate:"omitempty,oneof=todo in_progress done"`
-	Priority       *TaskPriority `json:"priority" validate:"omitempty,oneof=low medium high"`
+	Status         *TaskStatus  `json:"status" validate:"omitempty,task_status"`
+	Priority       *TaskPriority `json:"priority" validate:"omitempty,task_priority"`
 	EstimatedHours *float64     `json:"estimated_hours" validate:"omitempty,min=0,max=999"`
-	DueDate        *time.Time   `json:"due_date"`
+	ActualHours    *float64     `json:"actual_hours" validate:"omitempty,min=0,max=9999"`
+	Energy         *TaskEnergy  `json:"energy" validate:"omitempty,task_energy"`
+	Context        *string      `json:"context" validate:"omitempty,max=50"`
+	DueDate        *time.Time   `json:"due_date"` // replaces any due expression
+	// DueExpr re-resolves the due date from the task's creation, or from the
+	// start of the current occurrence of a recurring task; "" drops the
+	// expression and keeps the date.
+	DueExpr        *string      `json:"due_expr" validate:"omitempty,max=100"`
+	StartDate      *time.Time   `json:"start_date"`
+	ScheduledAt       *time.Time `json:"scheduled_at"`
+	ScheduledDuration *int       `json:"scheduled_duration" validate:"omitempty,min=5,max=1440"`
+	AllowOverlap      bool       `json:"allow_overlap"`
+	Recurrence     *Recurrence  `json:"recurrence"`
+	NoEscalation   *bool        `json:"no_escalation"`
+	// ClearEstimatedHours, ClearActualHours, ClearDueDate, ClearStartDate,
+	// ClearRecurrence, ClearEnergy and ClearContext remove the value, since a null means "leave
+	// unchanged".
+	ClearEstimatedHours bool `json:"clear_estimated_hours"`
+	ClearActualHours    bool `json:"clear_actual_hours"`
+	ClearDueDate        bool `json:"clear_due_date"`
+	ClearStartDate      bool `json:"clear_start_date"`
+	ClearRecurrence     bool `json:"clear_recurrence"`
+	ClearEnergy         bool `json:"clear_energy"`
+	ClearContext        bool `json:"clear_context"`
+	// ClearSchedule removes the time slot.
+	ClearSchedule       bool `json:"clear_schedule"`
+	// IfVersion, from If-Match, rejects the update if the task has changed
+	// since.
+	IfVersion *int64 `json:"-"`
+}
+
+// TaskChangeSet is the response to a modified_since poll. Deleted tasks are
+// included with deleted_at set so clients can drop them.
+type TaskChangeSet struct {
+	Tasks []*Task `json:"tasks"`
+	// ServerTime is the modified_since value to send on the next poll.
+	ServerTime time.Time `json:"server_time"`
+	// HasMore is set when the result was capped; poll again immediately.
+	HasMore bool `json:"has_more"`
+}
+
+// TaskMatch is a fuzzy title search hit. Similarity is the pg_trgm score
+// between 0 and 1.
+type TaskMatch struct {
+	Task
+	Similarity float64 `json:"similarity" db:"similarity"`
+}
+
+// OverdueCursor resumes a scan of overdue tasks after the last one returned.
+// Overdue tasks are ordered by due date, then id.
+type OverdueCursor struct {
+	DueDate time.Time
+	ID      uuid.UUID
+}
+
+// OverdueCursorAfter returns the cursor continuing after task, which must
+// have a due date.
+func OverdueCursorAfter(task *Task) *OverdueCursor {
+	return &OverdueCursor{DueDate: *task.DueDate, ID: task.ID}
 }
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FieldChange is the old and new value of one modified field.
+type FieldChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// TaskChanges maps a task's JSON field name to how it changed. Unchanged
+// fields are absent.
+type TaskChanges map[string]FieldChange
+
+// TaskWithChanges is a task together with the changes an update made to it.
+type TaskWithChanges struct {
+	*Task
+	Changes TaskChanges `json:"changes"`
+}
+
+// DiffTasks compares the user-editable fields of two versions of a task.
+// Derived fields (smart_score, completed_at, timestamps) are not reported.
+func DiffTasks(before, after *Task) TaskChanges {
+	changes := TaskChanges{}
+	if !equalUUIDPtr(before.ProjectID, after.ProjectID) {
+		changes["project_id"] = FieldChange{Old: uuidValue(before.ProjectID), New: uuidValue(after.ProjectID)}
+	}
+	if before.Title != after.Title {
+		changes["title"] = FieldChange{Old: before.Title, New: after.Title}
+	}
+	if before.Description != after.Description {
+		changes["description"] = FieldChange{Old: before.Description, New: after.Description}
+	}
+	if before.Status != after.Status {
+		changes["status"] = FieldChange{Old: before.Status, New: after.Status}
+	}
+	if before.Priority != after.Priority {
+		changes["priority"] = FieldChange{Old: before.Priority, New: after.Priority}
+	}
+	if !equalFloatPtr(before.EstimatedHours, after.EstimatedHours) {
+		changes["estimated_hours"] = FieldChange{Old: floatValue(before.EstimatedHours), New: floatValue(after.EstimatedHours)}
+	}
+	if !equalFloatPtr(before.ActualHours, after.ActualHours) {
+		changes["actual_hours"] = FieldChange{Old: floatValue(before.ActualHours), New: floatValue(after.ActualHours)}
+	}
+	if !equalEnergyPtr(before.Energy, after.Energy) {
+		changes["energy"] = FieldChange{Old: energyValue(before.Energy), New: energyValue(after.Energy)}
+	}
+	if !equalStringPtr(before.Context, after.Context) {
+		changes["context"] = FieldChange{Old: stringValue(before.Context), New: stringValue(after.Context)}
+	}
+	if !equalTimePtr(before.DueDate, after.DueDate) {
+		changes["due_date"] = FieldChange{Old: timeValue(before.DueDate), New: timeValue(after.DueDate)}
+	}
+	if !equalStringPtr(before.DueExpr, after.DueExpr) {
+		changes["due_expr"] = FieldChange{Old: stringValue(before.DueExpr), New: stringValue(after.DueExpr)}
+	}
+	if !equalTimePtr(before.StartDate, after.StartDate) {
+		changes["start_date"] = FieldChange{Old: timeValue(before.StartDate), New: timeValue(after.StartDate)}
+	}
+	if !equalTimePtr(before.ScheduledAt, after.ScheduledAt) {
+		changes["scheduled_at"] = FieldChange{Old: timeValue(before.ScheduledAt), New: timeValue(after.ScheduledAt)}
+	}
+	if !equalIntPtr(before.ScheduledDuration, after.ScheduledDuration) {
+		changes["scheduled_duration"] = FieldChange{Old: intValue(before.ScheduledDuration), New: intValue(after.ScheduledDuration)}
+	}
+	if !equalRecurrencePtr(before.Recurrence, after.Recurrence) {
+		changes["recurrence"] = FieldChange{Old: recurrenceValue(before.Recurrence), New: recurrenceValue(after.Recurrence)}
+	}
+	if before.NoEscalation != after.NoEscalation {
+		changes["no_escalation"] = FieldChange{Old: before.NoEscalation, New: after.NoEscalation}
+	}
+	return changes
+}
+
+func equalUUIDPtr(a, b *uuid.UUID) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func equalIntPtr(a, b *int) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func equalStringPtr(a, b *string) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func equalEnergyPtr(a, b *TaskEnergy) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func equalTimePtr(a, b *time.Time) bool {
+	return (a == nil) == (b == nil) && (a == nil || a.Equal(*b))
+}
+
+// equalRecurrencePtr ignores the anchor and start, which the server sets.
+func equalRecurrencePtr(a, b *Recurrence) bool {
+	return (a == nil) == (b == nil) && (a == nil || (a.Frequency == b.Frequency && a.Interval == b.Interval))
+}
+
+// The *Value helpers turn nil pointers into untyped nil so they encode as null.
+
+func uuidValue(p *uuid.UUID) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func floatValue(p *float64) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func intValue(p *int) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func stringValue(p *string) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func energyValue(p *TaskEnergy) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func timeValue(p *time.Time) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
+
+func recurrenceValue(p *Recurrence) any {
+	if p == nil {
+		return nil
+	}
+	return *p
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TaskCSVColumns lists the columns a CSV import reads, matched by header
+// name case-insensitively. Only title is required.
+var TaskCSVColumns = []string{"title", "description", "priority", "due_date", "project"}
+
+// TaskCSVRow is one data row of a CSV import. Line is its line in the file,
+// counting the header as line 1.
+type TaskCSVRow struct {
+	Line        int
+	Title       string
+	Description string
+	Priority    string
+	DueDate     string
+	Project     string
+}
+
+// ParseTaskCSV reads a CSV file whose first row names its columns. Columns
+// outside TaskCSVColumns are returned as unmapped; blank rows are skipped.
+func ParseTaskCSV(r io.Reader) (rows []TaskCSVRow, unmapped []string, err error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("file is empty")
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	index := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := index[name]; dup {
+			return nil, nil, fmt.Errorf("column %q appears more than once", name)
+		}
+		index[name] = i
+		if !slices.Contains(TaskCSVColumns, name) {
+			unmapped = append(unmapped, name)
+		}
+	}
+	if _, ok := index["title"]; !ok {
+		return nil, nil, fmt.Errorf("header must include a title column; columns are %s", strings.Join(TaskCSVColumns, ", "))
+	}
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		field := func(name string) string {
+			if i, ok := index[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+		rows = append(rows, TaskCSVRow{
+			Line:        line,
+			Title:       field("title"),
+			Description: field("description"),
+			Priority:    field("priority"),
+			DueDate:     field("due_date"),
+			Project:     field("project"),
+		})
+	}
+	return rows, unmapped, nil
+}
+
+// Due parses the row's due date: an RFC3339 time, or a YYYY-MM-DD date that
+// is due all of that day in loc. It is nil when the column is blank.
+func (r TaskCSVRow) Due(loc *time.Location) (*time.Time, error) {
+	if r.DueDate == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, r.DueDate); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation(todoTxtDate, r.DueDate, loc)
+	if err != nil {
+		return nil, fmt.Errorf("due_date %q is not YYYY-MM-DD or RFC3339", r.DueDate)
+	}
+	return &t, nil
+}
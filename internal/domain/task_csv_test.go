@@ -0,0 +1,31 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskCSV(t *testing.T) {
+	rows, unmapped, err := domain.ParseTaskCSV(strings.NewReader("\ufeffTitle, Due_Date\nPay rent, 2026-03-05\nShort row\n"))
+	require.NoError(t, err)
+	assert.Empty(t, unmapped)
+	assert.Equal(t, []domain.TaskCSVRow{
+		{Line: 2, Title: "Pay rent", DueDate: "2026-03-05"},
+		{Line: 3, Title: "Short row"},
+	}, rows)
+
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	due, err := rows[0].Due(jkt)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, jkt), *due, "a date is due all day in the user's zone")
+
+	for _, data := range []string{"", "name,priority\nPay rent,high\n", "title,title\na,b\n", "title\n\"unterminated\n"} {
+		_, _, err := domain.ParseTaskCSV(strings.NewReader(data))
+		assert.Error(t, err, data)
+	}
+}
@@ -0,0 +1,14 @@
+package domain
+
+import "github.com/google/uuid"
+
+// TaskDependencies lists the tasks blocking a task and the tasks it blocks.
+type TaskDependencies struct {
+	BlockedBy []*Task `json:"blocked_by"`
+	Blocking  []*Task `json:"blocking"`
+}
+
+// AddDependencyRequest marks a task as blocked by another of the user's tasks.
+type AddDependencyRequest struct {
+	BlockedByID uuid.UUID `json:"blocked_by_id" validate:"required"`
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TaskEnergy is how much focus a task takes.
+type TaskEnergy string
+
+const (
+	TaskEnergyLow    TaskEnergy = "low"
+	TaskEnergyMedium TaskEnergy = "medium"
+	TaskEnergyHigh   TaskEnergy = "high"
+)
+
+// TaskStatusValues, TaskPriorityValues and TaskEnergyValues list the
+// canonical enum values. Energy levels are in ascending order.
+var (
+	TaskStatusValues   = []TaskStatus{TaskStatusTodo, TaskStatusInProgress, TaskStatusDone}
+	TaskPriorityValues = []TaskPriority{TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh}
+	TaskEnergyValues   = []TaskEnergy{TaskEnergyLow, TaskEnergyMedium, TaskEnergyHigh}
+)
+
+// Aliases accepted on input in addition to the canonical values. Keys are
+// normalized: lowercase, with spaces and dashes folded to underscores.
+var (
+	taskStatusAliases = map[string]TaskStatus{
+		"to_do":      TaskStatusTodo,
+		"open":       TaskStatusTodo,
+		"inprogress": TaskStatusInProgress,
+		"doing":      TaskStatusInProgress,
+		"wip":        TaskStatusInProgress,
+		"complete":   TaskStatusDone,
+		"completed":  TaskStatusDone,
+		"closed":     TaskStatusDone,
+	}
+	taskPriorityAliases = map[string]TaskPriority{
+		"p3":     TaskPriorityLow,
+		"p2":     TaskPriorityMedium,
+		"med":    TaskPriorityMedium,
+		"normal": TaskPriorityMedium,
+		"p1":     TaskPriorityHigh,
+		"urgent": TaskPriorityHigh,
+	}
+	taskEnergyAliases = map[string]TaskEnergy{
+		"med": TaskEnergyMedium,
+		"lo":  TaskEnergyLow,
+		"hi":  TaskEnergyHigh,
+	}
+)
+
+// ParseTaskStatus converts user input to a TaskStatus, case-insensitively and
+// accepting aliases such as "wip" or "completed". Unknown values wrap ErrValidation.
+func ParseTaskStatus(s string) (TaskStatus, error) {
+	key := normalizeEnum(s)
+	for _, v := range TaskStatusValues {
+		if key == string(v) {
+			return v, nil
+		}
+	}
+	if v, ok := taskStatusAliases[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown task status %q: %w", s, ErrValidation)
+}
+
+// ParseTaskPriority converts user input to a TaskPriority, case-insensitively
+// and accepting aliases such as "p1" (high). Unknown values wrap ErrValidation.
+func ParseTaskPriority(s string) (TaskPriority, error) {
+	key := normalizeEnum(s)
+	for _, v := range TaskPriorityValues {
+		if key == string(v) {
+			return v, nil
+		}
+	}
+	if v, ok := taskPriorityAliases[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown task priority %q: %w", s, ErrValidation)
+}
+
+// ParseTaskEnergy converts user input to a TaskEnergy, case-insensitively
+// and accepting aliases such as "med". Unknown values wrap ErrValidation.
+func ParseTaskEnergy(s string) (TaskEnergy, error) {
+	key := normalizeEnum(s)
+	for _, v := range TaskEnergyValues {
+		if key == string(v) {
+			return v, nil
+		}
+	}
+	if v, ok := taskEnergyAliases[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown task energy %q: %w", s, ErrValidation)
+}
+
+// NormalizeTaskContext folds a context such as "@Phone" to "phone": trimmed,
+// lowercase and without the leading @.
+func NormalizeTaskContext(s string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(s), "@"))
+}
+
+// UnmarshalText normalizes JSON and form input. Unknown values are kept as
+// given so that validation reports them against the field.
+func (s *TaskStatus) UnmarshalText(b []byte) error {
+	if v, err := ParseTaskStatus(string(b)); err == nil {
+		*s = v
+	} else {
+		*s = TaskStatus(b)
+	}
+	return nil
+}
+
+// UnmarshalText normalizes JSON and form input. Unknown values are kept as
+// given so that validation reports them against the field.
+func (p *TaskPriority) UnmarshalText(b []byte) error {
+	if v, err := ParseTaskPriority(string(b)); err == nil {
+		*p = v
+	} else {
+		*p = TaskPriority(b)
+	}
+	return nil
+}
+
+// UnmarshalText normalizes JSON and form input. Unknown values are kept as
+// given so that validation reports them against the field.
+func (e *TaskEnergy) UnmarshalText(b []byte) error {
+	if v, err := ParseTaskEnergy(string(b)); err == nil {
+		*e = v
+	} else {
+		*e = TaskEnergy(b)
+	}
+	return nil
+}
+
+// Valid reports whether s is a canonical status.
+func (s TaskStatus) Valid() bool {
+	for _, v := range TaskStatusValues {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether p is a canonical priority.
+func (p TaskPriority) Valid() bool {
+	for _, v := range TaskPriorityValues {
+		if p == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Valid reports whether e is a canonical energy level.
+func (e TaskEnergy) Valid() bool {
+	return e.Level() > 0
+}
+
+// Level ranks e from 1 (low) to 3 (high), or 0 when it is not canonical.
+func (e TaskEnergy) Level() int {
+	for i, v := range TaskEnergyValues {
+		if e == v {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func normalizeEnum(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	return strings.NewReplacer("-", "_", " ", "_").Replace(s)
+}
@@ -0,0 +1,56 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskPriority_CaseInsensitiveWithAliases(t *testing.T) {
+	cases := map[string]domain.TaskPriority{
+		"high":   domain.TaskPriorityHigh,
+		"HIGH":   domain.TaskPriorityHigh,
+		" p1 ":   domain.TaskPriorityHigh,
+		"Medium": domain.TaskPriorityMedium,
+		"p2":     domain.TaskPriorityMedium,
+		"p3":     domain.TaskPriorityLow,
+	}
+	for in, want := range cases {
+		got, err := domain.ParseTaskPriority(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := domain.ParseTaskPriority("p0")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestParseTaskStatus_CaseInsensitiveWithAliases(t *testing.T) {
+	cases := map[string]domain.TaskStatus{
+		"TODO":        domain.TaskStatusTodo,
+		"to-do":       domain.TaskStatusTodo,
+		"In Progress": domain.TaskStatusInProgress,
+		"wip":         domain.TaskStatusInProgress,
+		"Completed":   domain.TaskStatusDone,
+	}
+	for in, want := range cases {
+		got, err := domain.ParseTaskStatus(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := domain.ParseTaskStatus("archived")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestTaskEnums_UnmarshalJSONNormalizesKnownAndKeepsUnknown(t *testing.T) {
+	var req domain.UpdateTaskRequest
+	require.NoError(t, json.Unmarshal([]byte(`{"status":"DONE","priority":"bogus"}`), &req))
+
+	assert.Equal(t, domain.TaskStatusDone, *req.Status)
+	assert.Equal(t, domain.TaskPriority("bogus"), *req.Priority)
+	assert.False(t, req.Priority.Valid())
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskEvent is one entry in the task audit log: the event name, the full
+// task as it was persisted by that change, and the fields it changed.
+type TaskEvent struct {
+	ID       uuid.UUID       `json:"id" db:"id"`
+	TaskID   uuid.UUID       `json:"task_id" db:"task_id"`
+	UserID   uuid.UUID       `json:"user_id" db:"user_id"`
+	Event    string          `json:"event" db:"event"`
+	Snapshot json.RawMessage `json:"snapshot" db:"snapshot"`
+	// Changes is an encoded TaskChanges, or JSON null for events recorded
+	// before changes were tracked.
+	Changes   json.RawMessage `json:"changes" db:"changes"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// TaskActivity is one entry of a task's activity feed: who did what, when.
+// Changes is nil when the event predates change tracking.
+type TaskActivity struct {
+	ID        uuid.UUID   `json:"id"`
+	Event     string      `json:"event"`
+	UserID    uuid.UUID   `json:"user_id"`
+	Changes   TaskChanges `json:"changes"`
+	CreatedAt time.Time   `json:"created_at"`
+}
@@ -0,0 +1,63 @@
+package domain
+
+import "fmt"
+
+// TaskExchangeFormat is a third-party task file format accepted by
+// /tasks/export and /tasks/import.
+type TaskExchangeFormat string
+
+const (
+	TaskFormatTodoTxt     TaskExchangeFormat = "todotxt"
+	TaskFormatTaskWarrior TaskExchangeFormat = "taskwarrior"
+	// TaskFormatCSV is import-only: a spreadsheet with TaskCSVColumns.
+	TaskFormatCSV TaskExchangeFormat = "csv"
+)
+
+// TaskExchangeFormats lists the supported exchange formats.
+var TaskExchangeFormats = []TaskExchangeFormat{TaskFormatTodoTxt, TaskFormatTaskWarrior}
+
+// TaskImportFormats lists the formats /tasks/import accepts.
+var TaskImportFormats = []TaskExchangeFormat{TaskFormatTodoTxt, TaskFormatTaskWarrior, TaskFormatCSV}
+
+// TaskImportResult summarises what an import created.
+type TaskImportResult struct {
+	Imported        int `json:"imported"`
+	ProjectsCreated int `json:"projects_created"`
+	TagsCreated     int `json:"tags_created"`
+	// Skipped counts entries with no equivalent, such as deleted tasks.
+	Skipped int `json:"skipped,omitempty"`
+	// UnmappedFields names the attributes in the file that were not imported.
+	UnmappedFields []string `json:"unmapped_fields,omitempty"`
+}
+
+// TaskImportRowError is a rejected row of an import. Row is its line in the
+// file.
+type TaskImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TaskImportErrors lists every rejected row of an import. It matches
+// ErrValidation.
+type TaskImportErrors []TaskImportRowError
+
+func (e TaskImportErrors) Error() string {
+	return fmt.Sprintf("%d rows rejected, first at row %d: %s", len(e), e[0].Row, e[0].Message)
+}
+
+func (e TaskImportErrors) Unwrap() error { return ErrValidation }
+
+// TaskBatchError reports the chunk of a batch insert that failed, as the
+// half-open range [Start, End) of the batch. The batch is rolled back as a
+// whole, so none of it was stored.
+type TaskBatchError struct {
+	Start, End int
+	Err        error
+}
+
+func (e *TaskBatchError) Error() string {
+	return fmt.Sprintf("tasks %d-%d: %v", e.Start+1, e.End, e.Err)
+}
+
+func (e *TaskBatchError) Unwrap() error { return e.Err }
@@ -0,0 +1,17 @@
+package domain
+
+// TaskHints are suggestions for a new task derived from the user's similar
+// past tasks. Clients may offer them; nothing is applied automatically.
+type TaskHints struct {
+	Priority       *TaskPriority `json:"priority,omitempty"`
+	EstimatedHours *float64      `json:"estimated_hours,omitempty"`
+	// BasedOn is how many similar tasks the hints were drawn from.
+	BasedOn int `json:"based_on"`
+}
+
+// TaskWithHints is a newly created task with optional hints. Hints is omitted
+// when there is too little history to go on.
+type TaskWithHints struct {
+	*Task
+	Hints *TaskHints `json:"hints,omitempty"`
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskLink is an external URL attached to a task, such as a pull request,
+// a document or a ticket, with a label to show in its place.
+type TaskLink struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	TaskID    uuid.UUID `json:"task_id" db:"task_id"`
+	UserID    uuid.UUID `json:"user_id" db:"user_id"`
+	URL       string    `json:"url" db:"url"`
+	Label     string    `json:"label" db:"label"` // the URL's host when none was given
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// CreateTaskLinkRequest is the payload for attaching a link to a task.
+type CreateTaskLinkRequest struct {
+	URL   string `json:"url" validate:"required,url,max=2048"`
+	Label string `json:"label" validate:"max=200"`
+}
+
+// UpdateTaskLinkRequest is the payload for changing a link; omitted fields
+// are kept, and an empty label falls back to the URL's host.
+type UpdateTaskLinkRequest struct {
+	URL   *string `json:"url" validate:"omitempty,url,max=2048"`
+	Label *string `json:"label" validate:"omitempty,max=200"`
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskRevision is an earlier description of a task, kept when an update
+// replaced it so an accidental overwrite can be undone.
+type TaskRevision struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	TaskID      uuid.UUID `json:"task_id" db:"task_id"`
+	UserID      uuid.UUID `json:"user_id" db:"user_id"`
+	Description string    `json:"description" db:"description"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"` // when it was replaced
+}
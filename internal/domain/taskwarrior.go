@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TaskWarrior statuses, as written by `task export`.
+const (
+	TaskWarriorPending   = "pending"
+	TaskWarriorCompleted = "completed"
+	TaskWarriorDeleted   = "deleted"
+	TaskWarriorWaiting   = "waiting"
+	TaskWarriorRecurring = "recurring"
+)
+
+// taskWarriorDate is the compact UTC layout TaskWarrior uses for dates.
+const taskWarriorDate = "20060102T150405Z"
+
+// TaskWarriorTime is a TaskWarrior date. It is written in TaskWarrior's
+// compact form and read from that form or RFC 3339.
+type TaskWarriorTime struct {
+	time.Time
+}
+
+// NewTaskWarriorTime wraps t, returning nil for a nil t.
+func NewTaskWarriorTime(t *time.Time) *TaskWarriorTime {
+	if t == nil {
+		return nil
+	}
+	return &TaskWarriorTime{Time: t.UTC()}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t TaskWarriorTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.UTC().Format(taskWarriorDate))
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *TaskWarriorTime) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	for _, layout := range []string{taskWarriorDate, time.RFC3339} {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			t.Time = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a TaskWarrior date", s)
+}
+
+// TaskWarriorUUIDs is the depends list of a task. TaskWarrior 2.6 writes an
+// array; older versions a comma-separated string. Both are read.
+type TaskWarriorUUIDs []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *TaskWarriorUUIDs) UnmarshalJSON(b []byte) error {
+	var list []string
+	if err := json.Unmarshal(b, &list); err == nil {
+		*u = list
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.New("depends must be a list of UUIDs")
+	}
+	*u = nil
+	for _, id := range strings.Split(s, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			*u = append(*u, id)
+		}
+	}
+	return nil
+}
+
+// TaskWarriorAnnotation is a timestamped note on a TaskWarrior task.
+type TaskWarriorAnnotation struct {
+	Entry       *TaskWarriorTime `json:"entry,omitempty"`
+	Description string           `json:"description"`
+}
+
+// TaskWarriorTask is one task of TaskWarrior's JSON export format, see
+// https://taskwarrior.org/docs/design/task/. Attributes TaskWarrior does
+// not define, user defined attributes (UDAs), are kept in UDA.
+type TaskWarriorTask struct {
+	UUID        string                  `json:"uuid"`
+	Description string                  `json:"description"`
+	Status      string                  `json:"status"`
+	Entry       *TaskWarriorTime        `json:"entry,omitempty"`
+	Modified    *TaskWarriorTime        `json:"modified,omitempty"`
+	Start       *TaskWarriorTime        `json:"start,omitempty"`
+	End         *TaskWarriorTime        `json:"end,omitempty"`
+	Due         *TaskWarriorTime        `json:"due,omitempty"`
+	Wait        *TaskWarriorTime        `json:"wait,omitempty"`
+	Scheduled   *TaskWarriorTime        `json:"scheduled,omitempty"`
+	Until       *TaskWarriorTime        `json:"until,omitempty"`
+	Recur       string                  `json:"recur,omitempty"`
+	Project     string                  `json:"project,omitempty"`
+	Priority    string                  `json:"priority,omitempty"` // H, M or L
+	Tags        []string                `json:"tags,omitempty"`
+	Annotations []TaskWarriorAnnotation `json:"annotations,omitempty"`
+	Depends     TaskWarriorUUIDs        `json:"depends,omitempty"`
+	UDA         map[string]any          `json:"-"`
+}
+
+// taskWarriorAttributes are the attributes TaskWarrior itself writes;
+// anything else in an exported task is a UDA. id and urgency are computed
+// by TaskWarrior and not imported.
+var taskWarriorAttributes = map[string]bool{
+	"id": true, "uuid": true, "description": true, "status": true, "entry": true, "modified": true,
+	"start": true, "end": true, "due": true, "wait": true, "scheduled": true, "until": true,
+	"recur": true, "mask": true, "imask": true, "parent": true, "project": true, "priority": true,
+	"tags": true, "annotations": true, "depends": true, "urgency": true,
+}
+
+type taskWarriorTask TaskWarriorTask
+
+// UnmarshalJSON implements json.Unmarshaler, collecting UDAs.
+func (t *TaskWarriorTask) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*taskWarriorTask)(t)); err != nil {
+		return err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return err
+	}
+	t.UDA = nil
+	for k, v := range fields {
+		if !taskWarriorAttributes[k] {
+			if t.UDA == nil {
+				t.UDA = map[string]any{}
+			}
+			t.UDA[k] = v
+		}
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing UDAs next to the
+// standard attributes.
+func (t TaskWarriorTask) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(taskWarriorTask(t))
+	if err != nil || len(t.UDA) == 0 {
+		return b, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range t.UDA {
+		if !taskWarriorAttributes[k] {
+			fields[k] = v
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// ParseTaskWarrior reads the output of `task export`: a JSON array, or one
+// JSON object per line as older TaskWarrior versions write it.
+func ParseTaskWarrior(data []byte) ([]TaskWarriorTask, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var tasks []TaskWarriorTask
+		if err := json.Unmarshal(data, &tasks); err != nil {
+			return nil, err
+		}
+		return tasks, nil
+	}
+
+	var tasks []TaskWarriorTask
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var t TaskWarriorTask
+		if err := dec.Decode(&t); errors.Is(err, io.EOF) {
+			return tasks, nil
+		} else if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+}
@@ -0,0 +1,49 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskWarrior(t *testing.T) {
+	array := `[{"id":1,"uuid":"a1","description":"Pay rent","status":"pending","entry":"20260301T090000Z",
+		"due":"2026-03-05T00:00:00Z","tags":["home"],"depends":"b2,c3","estimate":1.5,"client":"ACME","urgency":8.2}]`
+	lines := `{"uuid":"a1","description":"Pay rent","status":"pending","entry":"20260301T090000Z"}
+{"uuid":"b2","description":"Call bank","status":"completed","depends":["a1"]}`
+
+	tasks, err := domain.ParseTaskWarrior([]byte(array))
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	tw := tasks[0]
+	assert.Equal(t, time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC), tw.Entry.Time)
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), tw.Due.Time)
+	assert.Equal(t, domain.TaskWarriorUUIDs{"b2", "c3"}, tw.Depends)
+	assert.Equal(t, map[string]any{"estimate": 1.5, "client": "ACME"}, tw.UDA, "id and urgency are not UDAs")
+
+	tasks, err = domain.ParseTaskWarrior([]byte(lines))
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, domain.TaskWarriorUUIDs{"a1"}, tasks[1].Depends)
+
+	_, err = domain.ParseTaskWarrior([]byte(`[{"uuid":"a1","entry":"yesterday"}]`))
+	assert.Error(t, err)
+}
+
+func TestTaskWarriorTask_MarshalJSON(t *testing.T) {
+	entry := time.Date(2026, 3, 1, 9, 30, 0, 0, time.FixedZone("WIB", 7*3600))
+	tw := domain.TaskWarriorTask{
+		UUID:        "a1",
+		Description: "Pay rent",
+		Status:      domain.TaskWarriorPending,
+		Entry:       domain.NewTaskWarriorTime(&entry),
+		UDA:         map[string]any{"estimate": 2.0, "status": "ignored"},
+	}
+	b, err := json.Marshal(tw)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"uuid":"a1","description":"Pay rent","status":"pending","entry":"20260301T023000Z","estimate":2}`, string(b))
+}
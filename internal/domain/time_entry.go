@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is a span of time tracked against a task. A running entry has
+// no StoppedAt; stopping it fixes DurationSeconds and adds it to the task's
+// TrackedSeconds.
+type TimeEntry struct {
+	ID              uuid.UUID  `json:"id" db:"id"`
+	TaskID          uuid.UUID  `json:"task_id" db:"task_id"`
+	UserID          uuid.UUID  `json:"user_id" db:"user_id"`
+	StartedAt       time.Time  `json:"started_at" db:"started_at"`
+	StoppedAt       *time.Time `json:"stopped_at,omitempty" db:"stopped_at"`
+	DurationSeconds *int64     `json:"duration_seconds,omitempty" db:"duration_seconds"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+}
+
+// IsRunning reports whether the entry's timer has not been stopped yet.
+func (e *TimeEntry) IsRunning() bool {
+	return e.StoppedAt == nil
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// todoTxtDate is the date layout used throughout todo.txt.
+const todoTxtDate = "2006-01-02"
+
+// TodoTxtItem is one line of a todo.txt file as described at
+// https://github.com/todotxt/todo.txt. Text is the description with the
+// +project, @context and key:value tokens taken out.
+type TodoTxtItem struct {
+	Done        bool
+	Priority    byte // 'A' to 'Z', 0 for none
+	CompletedOn *time.Time
+	CreatedOn   *time.Time
+	Text        string
+	Projects    []string
+	Contexts    []string
+	Tags        map[string]string
+}
+
+// ParseTodoTxtLine parses a single non-blank todo.txt line. Any text is a
+// valid task; only malformed dates are errors.
+func ParseTodoTxtLine(line string) (TodoTxtItem, error) {
+	item := TodoTxtItem{Tags: map[string]string{}}
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		item.Done = true
+		fields = fields[1:]
+	}
+	if len(fields) > 0 && isTodoTxtPriority(fields[0]) {
+		item.Priority = fields[0][1]
+		fields = fields[1:]
+	}
+	// A done task has its completion date, then optionally its creation
+	// date; an open task only has a creation date.
+	dates := []**time.Time{&item.CreatedOn}
+	if item.Done {
+		dates = []**time.Time{&item.CompletedOn, &item.CreatedOn}
+	}
+	for _, dst := range dates {
+		if len(fields) == 0 {
+			break
+		}
+		d, err := time.Parse(todoTxtDate, fields[0])
+		if err != nil {
+			break
+		}
+		*dst = &d
+		fields = fields[1:]
+	}
+
+	var words []string
+	for _, f := range fields {
+		switch {
+		case len(f) > 1 && f[0] == '+':
+			item.Projects = append(item.Projects, f[1:])
+		case len(f) > 1 && f[0] == '@':
+			item.Contexts = append(item.Contexts, f[1:])
+		default:
+			if k, v, ok := todoTxtTag(f); ok {
+				item.Tags[k] = v
+				continue
+			}
+			words = append(words, f)
+		}
+	}
+	item.Text = strings.Join(words, " ")
+
+	if due, ok := item.Tags["due"]; ok {
+		if _, err := time.Parse(todoTxtDate, due); err != nil {
+			return item, fmt.Errorf("due:%s is not a YYYY-MM-DD date", due)
+		}
+	}
+	if pri, ok := item.Tags["pri"]; ok && item.Priority == 0 {
+		if len(pri) != 1 || pri[0] < 'A' || pri[0] > 'Z' {
+			return item, fmt.Errorf("pri:%s is not a letter from A to Z", pri)
+		}
+		item.Priority = pri[0]
+		delete(item.Tags, "pri")
+	}
+	return item, nil
+}
+
+// Due returns the due:YYYY-MM-DD tag as midnight UTC, or nil.
+func (i TodoTxtItem) Due() *time.Time {
+	d, err := time.Parse(todoTxtDate, i.Tags["due"])
+	if err != nil {
+		return nil
+	}
+	return &d
+}
+
+// String formats the item as a todo.txt line. Done tasks keep their
+// priority as a pri: tag, since the format drops the (A) marker on
+// completion. Tags are written in key order.
+func (i TodoTxtItem) String() string {
+	var parts []string
+	if i.Done {
+		parts = append(parts, "x")
+	} else if i.Priority != 0 {
+		parts = append(parts, "("+string(i.Priority)+")")
+	}
+	if i.Done && i.CompletedOn != nil {
+		parts = append(parts, i.CompletedOn.Format(todoTxtDate))
+	}
+	// A creation date without a completion date would be read back as the
+	// completion date.
+	if i.CreatedOn != nil && (!i.Done || i.CompletedOn != nil) {
+		parts = append(parts, i.CreatedOn.Format(todoTxtDate))
+	}
+	if text := strings.Join(strings.Fields(i.Text), " "); text != "" {
+		parts = append(parts, text)
+	}
+	for _, p := range i.Projects {
+		parts = append(parts, "+"+TodoTxtToken(p))
+	}
+	for _, c := range i.Contexts {
+		parts = append(parts, "@"+TodoTxtToken(c))
+	}
+
+	tags := make(map[string]string, len(i.Tags)+1)
+	for k, v := range i.Tags {
+		tags[k] = v
+	}
+	if i.Done && i.Priority != 0 {
+		tags["pri"] = string(i.Priority)
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		parts = append(parts, k+":"+TodoTxtToken(tags[k]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// TodoTxtToken makes a name usable as a todo.txt token, which cannot
+// contain spaces: they become underscores.
+func TodoTxtToken(name string) string {
+	return strings.Join(strings.Fields(name), "_")
+}
+
+func isTodoTxtPriority(f string) bool {
+	return len(f) == 3 && f[0] == '(' && f[1] >= 'A' && f[1] <= 'Z' && f[2] == ')'
+}
+
+// todoTxtTag splits a key:value token. URLs such as https://example.com are
+// not tags.
+func todoTxtTag(f string) (key, value string, ok bool) {
+	key, value, ok = strings.Cut(f, ":")
+	if !ok || key == "" || value == "" || strings.Contains(value, ":") || strings.HasPrefix(value, "//") {
+		return "", "", false
+	}
+	return key, value, true
+}
@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTodoTxtLine(t *testing.T) {
+	item, err := domain.ParseTodoTxtLine("(A) 2026-03-01 Call mom +Family @phone due:2026-03-05 see https://example.com")
+	require.NoError(t, err)
+
+	assert.False(t, item.Done)
+	assert.Equal(t, byte('A'), item.Priority)
+	require.NotNil(t, item.CreatedOn)
+	assert.Equal(t, "2026-03-01", item.CreatedOn.Format("2006-01-02"))
+	assert.Equal(t, "Call mom see https://example.com", item.Text)
+	assert.Equal(t, []string{"Family"}, item.Projects)
+	assert.Equal(t, []string{"phone"}, item.Contexts)
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), *item.Due())
+}
+
+func TestParseTodoTxtLine_Done(t *testing.T) {
+	item, err := domain.ParseTodoTxtLine("x 2026-03-02 2026-03-01 Call mom pri:B")
+	require.NoError(t, err)
+
+	assert.True(t, item.Done)
+	assert.Equal(t, byte('B'), item.Priority)
+	assert.Equal(t, "2026-03-02", item.CompletedOn.Format("2006-01-02"))
+	assert.Equal(t, "2026-03-01", item.CreatedOn.Format("2006-01-02"))
+	assert.Empty(t, item.Tags)
+}
+
+func TestParseTodoTxtLine_RejectsBadTags(t *testing.T) {
+	for _, line := range []string{"Pay rent due:tomorrow", "Pay rent pri:AA"} {
+		_, err := domain.ParseTodoTxtLine(line)
+		assert.Error(t, err, line)
+	}
+}
+
+func TestTodoTxtItem_StringRoundTrip(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	completed := created.AddDate(0, 0, 1)
+	item := domain.TodoTxtItem{
+		Done:        true,
+		Priority:    'A',
+		CompletedOn: &completed,
+		CreatedOn:   &created,
+		Text:        "File taxes",
+		Projects:    []string{"Home admin"},
+		Contexts:    []string{"desk"},
+		Tags:        map[string]string{"due": "2026-04-15"},
+	}
+
+	line := item.String()
+	assert.Equal(t, "x 2026-03-02 2026-03-01 File taxes +Home_admin @desk due:2026-04-15 pri:A", line)
+
+	back, err := domain.ParseTodoTxtLine(line)
+	require.NoError(t, err)
+	assert.Equal(t, item.Priority, back.Priority)
+	assert.Equal(t, item.Text, back.Text)
+	assert.Equal(t, []string{"Home_admin"}, back.Projects)
+	assert.Equal(t, map[string]string{"due": "2026-04-15"}, back.Tags)
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TwoFactor is a user's TOTP enrollment. It is pending from setup until a
+// first code is verified, and only an enabled one is asked for at login.
+type TwoFactor struct {
+	UserID    uuid.UUID  `db:"user_id"`
+	Secret    string     `db:"secret"`
+	EnabledAt *time.Time `db:"enabled_at"`
+	// LastStep is the time step of the last code accepted; codes of that
+	// step or earlier are refused, so none can be used twice.
+	LastStep int64 `db:"last_step"`
+	// FailedAttempts counts wrong codes since the last right one.
+	FailedAttempts int        `db:"failed_attempts"`
+	LastFailedAt   *time.Time `db:"last_failed_at"`
+	CreatedAt      time.Time  `db:"created_at"`
+}
+
+// Enabled reports whether the enrollment has been verified.
+func (t *TwoFactor) Enabled() bool {
+	return t.EnabledAt != nil
+}
+
+// TwoFactorSetup is returned by POST /auth/2fa/setup: the secret to enter
+// in an authenticator app, directly or through the otpauth URI.
+type TwoFactorSetup struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// TwoFactorRecoveryCodes are shown once, when two-factor authentication is
+// enabled. Each one stands in for a code from the app a single time.
+type TwoFactorRecoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// TwoFactorCodeRequest carries a code from the authenticator app or, where
+// accepted, a recovery code.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// TwoFactorLoginRequest is the second login step: the challenge token the
+// first step returned and a code from the app or a recovery code.
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required,max=32"`
+	DeviceID       string `json:"device_id" validate:"required,max=255"`
+}
@@ -8,13 +8,47 @@ import (
 
 // User represents the user entity in the domain.
 type User struct {
-	ID        uuid.UUID  `json:"id" db:"id"`
-	Name      string     `json:"name" db:"name"`
-	Email     string     `json:"email" db:"email"`
-	Password  string     `json:"-" db:"password_hash"`
-	CreatedAt time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID       uuid.UUID `json:"id" db:"id"`
+	Name     string    `json:"name" db:"name"`
+	Email    string    `json:"email" db:"email"`
+	Password string    `json:"-" db:"password_hash"`
+	IsAdmin  bool      `json:"is_admin" db:"is_admin"`
+	Ranker   *string   `json:"ranker,omitempty" db:"ranker"`
+	// Timezone is the IANA zone the user's days are counted in, e.g. for
+	// "due today" and date-only due dates.
+	Timezone string `json:"timezone" db:"timezone"`
+	// ReferralCode is the user's own code for referring others.
+	ReferralCode string `json:"referral_code" db:"referral_code"`
+	// InviteCodeID is the invite the user signed up with, kept for attribution.
+	InviteCodeID *uuid.UUID `json:"-" db:"invite_code_id"`
+	// ChangelogSeenAt is when the user last marked the changelog as read.
+	ChangelogSeenAt *time.Time `json:"-" db:"changelog_seen_at"`
+	CreatedAt       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// Location returns the user's time zone, UTC when unset or unknown.
+func (u *User) Location() *time.Location {
+	return LoadLocation(u.Timezone)
+}
+
+// LoadLocation resolves an IANA zone name, falling back to UTC when it is
+// empty or unknown.
+func LoadLocation(name string) *time.Location {
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SetTimezoneRequest is the payload for PUT /me/timezone.
+type SetTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required,timezone"`
 }
 
 // RefreshToken represents a refresh token tied to a user and device.
@@ -33,6 +67,14 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=8,max=72"`
+	// InviteCode is required while signup is invite-only.
+	InviteCode string `json:"invite_code" validate:"max=64"`
+	// ReferralCode credits the user who shared it. Unknown codes are ignored.
+	ReferralCode string `json:"referral_code" validate:"max=64"`
+	// Timezone is an IANA zone name; empty means UTC.
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	// ReferralSource records where ReferralCode came from; set by the handler.
+	ReferralSource string `json:"-"`
 }
 
 // LoginRequest is the payload for user login.
@@ -42,11 +84,16 @@ type LoginRequest struct {
 	DeviceID string `json:"device_id" validate:"required,max=255"`
 }
 
-// AuthResponse is returned after a successful authentication.
+// AuthResponse is returned after a successful authentication. When the
+// user has two-factor authentication on, login instead answers with
+// TwoFactorRequired and a ChallengeToken for POST /auth/login/2fa, and
+// carries no tokens or user.
 type AuthResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
-	User         *User  `json:"user"`
+	AccessToken       string `json:"access_token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	User              *User  `json:"user,omitempty"`
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
 
 // RefreshTokenRequest is the payload for refreshing access tokens.
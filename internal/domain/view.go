@@ -0,0 +1,90 @@
+package domain
+
+import "time"
+
+// Keys of the built-in smart lists served at /views.
+const (
+	ViewToday             = "today"
+	ViewUpcoming          = "upcoming"
+	ViewOverdue           = "overdue"
+	ViewHighPriority      = "high_priority"
+	ViewRecentlyCompleted = "recently_completed"
+)
+
+// SmartView is a built-in saved filter over the user's tasks.
+type SmartView struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Count       int    `json:"count"`
+}
+
+// SystemViews lists the built-in views in sidebar order.
+var SystemViews = []SmartView{
+	{Key: ViewToday, Name: "Today", Description: "Open tasks due today"},
+	{Key: ViewUpcoming, Name: "Upcoming", Description: "Open tasks due in the next 7 days, after today"},
+	{Key: ViewOverdue, Name: "Overdue", Description: "Open tasks past their due date"},
+	{Key: ViewHighPriority, Name: "High Priority", Description: "Open high-priority tasks"},
+	{Key: ViewRecentlyCompleted, Name: "Recently Completed", Description: "Tasks completed in the last 7 days"},
+}
+
+// IsSystemView reports whether key names a built-in view.
+func IsSystemView(key string) bool {
+	for _, v := range SystemViews {
+		if v.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ViewWindow holds the instants the built-in views are evaluated against.
+// Day boundaries follow the caller's time zone.
+type ViewWindow struct {
+	Now            time.Time
+	DayStart       time.Time
+	DayEnd         time.Time
+	UpcomingEnd    time.Time
+	CompletedSince time.Time
+	Timezone       string // zone the days are counted in, for date-only due dates
+}
+
+// NewViewWindow computes the view window for now in loc.
+func NewViewWindow(now time.Time, loc *time.Location) ViewWindow {
+	local := now.In(loc)
+	dayStart := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+	return ViewWindow{
+		Now:            now,
+		DayStart:       dayStart,
+		DayEnd:         dayEnd,
+		UpcomingEnd:    dayEnd.AddDate(0, 0, 7),
+		CompletedSince: now.AddDate(0, 0, -7),
+		Timezone:       loc.String(),
+	}
+}
+
+// Agenda reasons say why a task is on the Today or Upcoming list.
+const (
+	AgendaOverdue   = "overdue"
+	AgendaDueToday  = "due_today"
+	AgendaUpcoming  = "upcoming"
+	AgendaHighScore = "high_score"
+)
+
+// AgendaTask is a task on the Today or Upcoming list, with the reason it is
+// there.
+type AgendaTask struct {
+	*Task
+	Reason string `json:"reason"`
+}
+
+// Badges are the counts behind app icon badges and sidebar chips.
+type Badges struct {
+	DueToday            int `json:"due_today" db:"due_today"`
+	Overdue             int `json:"overdue" db:"overdue"`
+	UnreadNotifications int `json:"unread_notifications" db:"unread_notifications"`
+	// PendingApprovals is always 0 until tasks gain an approval workflow; it
+	// is reported so clients can bind to a stable shape.
+	PendingApprovals int `json:"pending_approvals" db:"-"`
+}
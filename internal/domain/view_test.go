@@ -0,0 +1,23 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewViewWindow_DayBoundariesFollowTimezone(t *testing.T) {
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	// 20:00 UTC on March 4 is already 03:00 on March 5 in Jakarta.
+	now := time.Date(2026, 3, 4, 20, 0, 0, 0, time.UTC)
+
+	w := domain.NewViewWindow(now, jkt)
+
+	assert.Equal(t, time.Date(2026, 3, 5, 0, 0, 0, 0, jkt), w.DayStart)
+	assert.Equal(t, time.Date(2026, 3, 6, 0, 0, 0, 0, jkt), w.DayEnd)
+	assert.Equal(t, time.Date(2026, 3, 13, 0, 0, 0, 0, jkt), w.UpcomingEnd)
+	assert.Equal(t, now.AddDate(0, 0, -7), w.CompletedSince)
+	assert.Equal(t, "Asia/Jakarta", w.Timezone)
+}
@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Webhook payload schema versions. Subscribers pin one so that payload changes
+// never break an existing integration.
+const (
+	WebhookVersionV1     = "v1"
+	WebhookVersionV2     = "v2"
+	WebhookVersionLatest = WebhookVersionV2
+)
+
+// WebhookEvents lists the event types webhooks can subscribe to.
+var WebhookEvents = []string{EventTaskCreated, EventTaskUpdated, EventTaskCompleted, EventTaskDeleted}
+
+// WebhookDeliveryStatus is the outcome of the latest attempt to deliver a payload.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook is a user's subscription to task events at an external URL.
+type Webhook struct {
+	ID             uuid.UUID      `json:"id" db:"id"`
+	UserID         uuid.UUID      `json:"user_id" db:"user_id"`
+	URL            string         `json:"url" db:"url"`
+	Secret         string         `json:"-" db:"secret"`
+	Events         pq.StringArray `json:"events" db:"events"`
+	PayloadVersion string         `json:"payload_version" db:"payload_version"`
+	Active         bool           `json:"active" db:"active"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at" db:"updated_at"`
+	CircuitBreaker
+}
+
+// Subscribed reports whether the webhook wants the given event type.
+func (w *Webhook) Subscribed(event string) bool {
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedWebhook is returned once on creation; it is the only time the signing secret is shown.
+type CreatedWebhook struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery is a stored payload sent (or to be sent) to a webhook.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	WebhookID      uuid.UUID             `json:"webhook_id" db:"webhook_id"`
+	Event          string                `json:"event" db:"event"`
+	PayloadVersion string                `json:"payload_version" db:"payload_version"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	Error          string                `json:"error,omitempty" db:"error"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	RedeliveryOf   *uuid.UUID            `json:"redelivery_of,omitempty" db:"redelivery_of"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+}
+
+// CreateWebhookRequest is the payload for registering a webhook.
+type CreateWebhookRequest struct {
+	URL            string   `json:"url" validate:"required,url,max=2048"`
+	Events         []string `json:"events" validate:"required,min=1,dive,oneof=task.created task.updated task.completed task.deleted"`
+	PayloadVersion string   `json:"payload_version" validate:"omitempty,oneof=v1 v2"`
+}
+
+// UpdateWebhookRequest is the payload for updating a webhook.
+type UpdateWebhookRequest struct {
+	URL            *string  `json:"url" validate:"omitempty,url,max=2048"`
+	Events         []string `json:"events" validate:"omitempty,min=1,dive,oneof=task.created task.updated task.completed task.deleted"`
+	PayloadVersion *string  `json:"payload_version" validate:"omitempty,oneof=v1 v2"`
+	Active         *bool    `json:"active"`
+}
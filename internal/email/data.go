@@ -0,0 +1,89 @@
+package email
+
+import "time"
+
+// VerificationData is the payload for TemplateVerification.
+type VerificationData struct {
+	Name      string
+	VerifyURL string
+}
+
+// PasswordResetData is the payload for TemplatePasswordReset.
+type PasswordResetData struct {
+	Name      string
+	ResetURL  string
+	ExpiresIn time.Duration
+}
+
+// DigestTask is a single task line inside a digest email.
+type DigestTask struct {
+	Title    string
+	Priority string
+	DueDate  *time.Time
+	URL      string
+}
+
+// DigestData is the payload for TemplateDigest.
+type DigestData struct {
+	Name               string
+	Date               time.Time
+	DueToday           []DigestTask
+	Overdue            []DigestTask
+	CompletedYesterday int
+	DashboardURL       string
+}
+
+// ReminderData is the payload for TemplateReminder.
+type ReminderData struct {
+	Name      string
+	TaskTitle string
+	TaskURL   string
+	DueDate   *time.Time
+}
+
+// NotificationData is the payload for TemplateNotification.
+type NotificationData struct {
+	Name  string
+	Title string
+	Body  string
+	URL   string
+}
+
+// SampleData returns representative data for a template, used by the preview endpoint.
+func SampleData(name Template, baseURL string) any {
+	now := time.Now()
+	tomorrow := now.Add(24 * time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+
+	switch name {
+	case TemplateVerification:
+		return VerificationData{Name: "Budi Santoso", VerifyURL: baseURL + "/verify?token=sample"}
+	case TemplatePasswordReset:
+		return PasswordResetData{Name: "Budi Santoso", ResetURL: baseURL + "/reset?token=sample", ExpiresIn: time.Hour}
+	case TemplateDigest:
+		return DigestData{
+			Name: "Budi Santoso",
+			Date: now,
+			DueToday: []DigestTask{
+				{Title: "Review pull requests", Priority: "high", DueDate: &now, URL: baseURL + "/tasks/1"},
+				{Title: "Buy groceries", Priority: "low", DueDate: &now, URL: baseURL + "/tasks/2"},
+			},
+			Overdue: []DigestTask{
+				{Title: "Submit expense report", Priority: "medium", DueDate: &yesterday, URL: baseURL + "/tasks/3"},
+			},
+			CompletedYesterday: 4,
+			DashboardURL:       baseURL + "/dashboard",
+		}
+	case TemplateReminder:
+		return ReminderData{Name: "Budi Santoso", TaskTitle: "Implement OAuth2", TaskURL: baseURL + "/tasks/1", DueDate: &tomorrow}
+	case TemplateNotification:
+		return NotificationData{
+			Name:  "Budi Santoso",
+			Title: "15 tasks were updated",
+			Body:  "• Review pull requests\n• Buy groceries\n• Submit expense report\n…and 12 more",
+			URL:   baseURL + "/notifications",
+		}
+	default:
+		return nil
+	}
+}
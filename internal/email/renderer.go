@@ -0,0 +1,227 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+	"unicode/utf8"
+)
+
+//go:embed templates/*.html templates/*.txt templates/summaries/*.txt
+var templateFS embed.FS
+
+// Template identifies one of the embedded email templates.
+type Template string
+
+const (
+	TemplateVerification  Template = "verification"
+	TemplatePasswordReset Template = "password_reset"
+	TemplateDigest        Template = "digest"
+	TemplateReminder      Template = "reminder"
+	TemplateNotification  Template = "notification"
+)
+
+// Templates lists every template the renderer knows about.
+var Templates = []Template{
+	TemplateVerification,
+	TemplatePasswordReset,
+	TemplateDigest,
+	TemplateReminder,
+	TemplateNotification,
+}
+
+// Summary identifies one of the embedded plain-text summaries served by
+// API endpoints with format=text.
+type Summary string
+
+const (
+	SummaryDashboard Summary = "dashboard"
+	SummaryDaily     Summary = "daily"
+)
+
+// Summaries lists every summary the renderer knows about.
+var Summaries = []Summary{SummaryDashboard, SummaryDaily}
+
+// Branding holds the per-deployment look and feel applied to every email.
+type Branding struct {
+	ProductName  string
+	LogoURL      string
+	PrimaryColor string
+	AccentColor  string
+	FooterText   string
+}
+
+// Rendered is a fully rendered email ready to hand to a mailer. Text is the
+// plain-text alternative for screen readers and text-mode mail clients.
+type Rendered struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Renderer renders the embedded HTML and plain-text templates with
+// deployment branding.
+type Renderer struct {
+	mu        sync.RWMutex
+	branding  Branding
+	templates map[Template]*template.Template
+	text      map[Template]*texttemplate.Template
+	summaries map[Summary]*texttemplate.Template
+}
+
+// view is the root object passed to every template.
+type view struct {
+	Brand Branding
+	Year  int
+	Data  any
+}
+
+// NewRenderer parses all embedded templates. It fails fast on syntax errors
+// so a broken template is caught at startup rather than on first send.
+func NewRenderer(branding Branding) (*Renderer, error) {
+	funcs := template.FuncMap{
+		"date": func(t time.Time) string { return t.Format("Mon, 02 Jan 2006") },
+		"datetime": func(t time.Time) string {
+			return t.Format("Mon, 02 Jan 2006 15:04 MST")
+		},
+		"lines": func(s string) []string { return strings.Split(s, "\n") },
+	}
+	textFuncs := texttemplate.FuncMap{
+		"underline": func(s string) string { return strings.Repeat("=", utf8.RuneCountInString(s)) },
+		"decimal":   func(f float64) string { return fmt.Sprintf("%.1f", f) },
+	}
+	for k, v := range funcs {
+		textFuncs[k] = v
+	}
+
+	r := &Renderer{
+		branding:  branding,
+		templates: make(map[Template]*template.Template, len(Templates)),
+		text:      make(map[Template]*texttemplate.Template, len(Templates)),
+		summaries: make(map[Summary]*texttemplate.Template, len(Summaries)),
+	}
+	for _, name := range Templates {
+		t, err := template.New("layout.html").Funcs(funcs).ParseFS(
+			templateFS, "templates/layout.html", "templates/"+string(name)+".html",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("email: parse template %s: %w", name, err)
+		}
+		r.templates[name] = t
+
+		tt, err := texttemplate.New("layout.txt").Funcs(textFuncs).ParseFS(
+			templateFS, "templates/layout.txt", "templates/"+string(name)+".txt",
+		)
+		if err != nil {
+			return nil, fmt.Errorf("email: parse text template %s: %w", name, err)
+		}
+		r.text[name] = tt
+	}
+	for _, name := range Summaries {
+		file := string(name) + ".txt"
+		t, err := texttemplate.New(file).Funcs(textFuncs).ParseFS(templateFS, "templates/summaries/"+file)
+		if err != nil {
+			return nil, fmt.Errorf("email: parse summary %s: %w", name, err)
+		}
+		r.summaries[name] = t
+	}
+	return r, nil
+}
+
+// Branding returns the branding emails are rendered with.
+func (r *Renderer) Branding() Branding {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.branding
+}
+
+// SetBranding changes the branding of emails rendered from now on.
+func (r *Renderer) SetBranding(b Branding) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.branding = b
+}
+
+// Render executes the named template with data and returns the subject and
+// the HTML and plain-text bodies.
+func (r *Renderer) Render(name Template, data any) (*Rendered, error) {
+	t, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("email: unknown template %q", name)
+	}
+
+	v := view{Brand: r.Branding(), Year: time.Now().Year(), Data: data}
+
+	var subject bytes.Buffer
+	if err := t.ExecuteTemplate(&subject, "subject", v); err != nil {
+		return nil, fmt.Errorf("email: render subject %s: %w", name, err)
+	}
+
+	var body bytes.Buffer
+	if err := t.Execute(&body, v); err != nil {
+		return nil, fmt.Errorf("email: render body %s: %w", name, err)
+	}
+
+	var text bytes.Buffer
+	if err := r.text[name].Execute(&text, v); err != nil {
+		return nil, fmt.Errorf("email: render text body %s: %w", name, err)
+	}
+
+	return &Rendered{
+		// Subjects are plain text; undo the HTML escaping applied by html/template.
+		Subject: strings.TrimSpace(html.UnescapeString(subject.String())),
+		HTML:    body.String(),
+		Text:    tidyText(text.String()),
+	}, nil
+}
+
+// RenderSummary executes the named plain-text summary with data.
+func (r *Renderer) RenderSummary(name Summary, data any) (string, error) {
+	t, ok := r.summaries[name]
+	if !ok {
+		return "", fmt.Errorf("email: unknown summary %q", name)
+	}
+
+	var out bytes.Buffer
+	v := view{Brand: r.Branding(), Year: time.Now().Year(), Data: data}
+	if err := t.ExecuteTemplate(&out, "content", v); err != nil {
+		return "", fmt.Errorf("email: render summary %s: %w", name, err)
+	}
+	return tidyText(out.String()), nil
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// tidyText strips trailing spaces and collapses the blank lines templates
+// leave around their actions, so the text reads as it was laid out.
+func tidyText(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")) + "\n"
+}
+
+// Has reports whether a template with the given name exists.
+func (r *Renderer) Has(name Template) bool {
+	_, ok := r.templates[name]
+	return ok
+}
+
+// Names returns the available template names in sorted order.
+func (r *Renderer) Names() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
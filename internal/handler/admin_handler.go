@@ -0,0 +1,619 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminHandler exposes instance administration endpoints. Every destructive
+// endpoint accepts ?dry_run=true to preview the affected rows.
+type AdminHandler struct {
+	adminSvc     *service.AdminService
+	retentionSvc *service.RetentionService
+	operationSvc *service.OperationService
+	shedder      *service.LoadShedder
+	coalescer    *service.ReadCoalescer
+	brandingSvc  *service.BrandingService
+	meteringSvc  *service.MeteringService
+	settingsSvc  *service.InstanceSettingsService
+	backupSvc    *service.BackupService
+}
+
+// NewAdminHandler creates an AdminHandler.
+func NewAdminHandler(adminSvc *service.AdminService, retentionSvc *service.RetentionService, operationSvc *service.OperationService, shedder *service.LoadShedder, coalescer *service.ReadCoalescer, brandingSvc *service.BrandingService, meteringSvc *service.MeteringService, settingsSvc *service.InstanceSettingsService, backupSvc *service.BackupService) *AdminHandler {
+	return &AdminHandler{adminSvc: adminSvc, retentionSvc: retentionSvc, operationSvc: operationSvc, shedder: shedder, coalescer: coalescer, brandingSvc: brandingSvc, meteringSvc: meteringSvc, settingsSvc: settingsSvc, backupSvc: backupSvc}
+}
+
+// IsAdmin backs the RequireAdmin middleware.
+func (h *AdminHandler) IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	return h.adminSvc.IsAdmin(ctx, userID)
+}
+
+// ModuleEnabled backs the RequireModule middleware.
+func (h *AdminHandler) ModuleEnabled(module string) bool {
+	return h.settingsSvc.ModuleEnabled(module)
+}
+
+// PurgeTrash godoc
+// @Summary Permanently delete soft-deleted tasks and projects
+// @Description With Prefer: respond-async, a purge that is not a dry run runs as an async operation.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param older_than_days query int false "Only rows deleted at least this many days ago (default 0: all)"
+// @Param dry_run query bool false "Preview only"
+// @Param Prefer header string false "respond-async to purge in the background"
+// @Success 200 {object} response.Envelope{data=domain.OperationResult}
+// @Success 202 {object} response.Envelope{data=domain.Operation}
+// @Router /admin/trash/purge [post]
+func (h *AdminHandler) PurgeTrash(c *gin.Context) {
+	days := 0
+	if v := c.Query("older_than_days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			response.BadRequest(c, "INVALID_PARAM", "older_than_days must be a non-negative integer", nil)
+			return
+		}
+		days = n
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -days)
+	if prefersAsync(c) && !isDryRun(c) {
+		startOperation(c, h.operationSvc, service.OperationPurgeTrash, service.PurgeTrashPayload{DeletedBefore: cutoff})
+		return
+	}
+	result, err := h.adminSvc.PurgeTrash(c.Request.Context(), cutoff, isDryRun(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// DeleteUser godoc
+// @Summary Permanently delete a user and all their data
+// @Description With Prefer: respond-async, a deletion that is not a dry run runs as an async operation.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param dry_run query bool false "Preview only"
+// @Param Prefer header string false "respond-async to delete in the background"
+// @Success 200 {object} response.Envelope{data=domain.OperationResult}
+// @Success 202 {object} response.Envelope{data=domain.Operation}
+// @Router /admin/users/{id} [delete]
+func (h *AdminHandler) DeleteUser(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+		return
+	}
+	if prefersAsync(c) && !isDryRun(c) {
+		startOperation(c, h.operationSvc, service.OperationHardDeleteUser, service.HardDeleteUserPayload{UserID: id})
+		return
+	}
+
+	result, err := h.adminSvc.HardDeleteUser(c.Request.Context(), id, isDryRun(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// RetentionReport godoc
+// @Summary Preview what the next scheduled retention purge will remove
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.RetentionReport}
+// @Router /admin/retention/report [get]
+func (h *AdminHandler) RetentionReport(c *gin.Context) {
+	report, err := h.retentionSvc.Report(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, report)
+}
+
+// RunRetentionPurge godoc
+// @Summary Run the retention purge now
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param dry_run query bool false "Preview only"
+// @Success 200 {object} response.Envelope{data=domain.OperationResult}
+// @Router /admin/retention/purge [post]
+func (h *AdminHandler) RunRetentionPurge(c *gin.Context) {
+	result, err := h.retentionSvc.Purge(c.Request.Context(), isDryRun(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// GetUserRetention godoc
+// @Summary Get a user's effective retention windows
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User UUID"
+// @Success 200 {object} response.Envelope{data=domain.UserRetention}
+// @Router /admin/users/{id}/retention [get]
+func (h *AdminHandler) GetUserRetention(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+		return
+	}
+
+	retention, err := h.retentionSvc.GetUserRetention(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, retention)
+}
+
+// SetUserRetention godoc
+// @Summary Override a retention window for one user
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param body body domain.SetRetentionOverrideRequest true "Override payload"
+// @Success 200 {object} response.Envelope{data=domain.UserRetention}
+// @Failure 422 {object} response.Envelope
+// @Router /admin/users/{id}/retention [put]
+func (h *AdminHandler) SetUserRetention(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+		return
+	}
+
+	var req domain.SetRetentionOverrideRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	retention, err := h.retentionSvc.SetOverride(c.Request.Context(), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, retention)
+}
+
+// DeleteUserRetention godoc
+// @Summary Restore the default retention window for one user
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param entity_type path string true "tasks | projects"
+// @Success 200 {object} response.Envelope
+// @Router /admin/users/{id}/retention/{entity_type} [delete]
+func (h *AdminHandler) DeleteUserRetention(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+		return
+	}
+
+	if err := h.retentionSvc.DeleteOverride(c.Request.Context(), id, c.Param("entity_type")); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "retention override removed"})
+}
+
+// LoadShedding godoc
+// @Summary Show database health and load shedding
+// @Description Mean latency and error rate of this instance's recent database probes, whether it is shedding low-priority requests, and the thresholds in force.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.LoadStatus}
+// @Router /admin/load-shedding [get]
+func (h *AdminHandler) LoadShedding(c *gin.Context) {
+	response.OK(c, h.shedder.Status())
+}
+
+// SetLoadShedding godoc
+// @Summary Change the load shedding thresholds
+// @Description Takes effect at once on the instance that receives it and lasts until it restarts. 0 disables a check.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.SheddingThresholds true "Thresholds"
+// @Success 200 {object} response.Envelope{data=domain.LoadStatus}
+// @Failure 422 {object} response.Envelope
+// @Router /admin/load-shedding [put]
+func (h *AdminHandler) SetLoadShedding(c *gin.Context) {
+	var req domain.SheddingThresholds
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	response.OK(c, h.shedder.SetThresholds(req))
+}
+
+// ReadCoalescing godoc
+// @Summary Show how often hot reads were coalesced
+// @Description Per read (analytics.dashboard, views.badges), since this instance started: calls made, queries that reached the database, and calls that shared a query already in flight.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=map[string]domain.CoalescingStats}
+// @Router /admin/read-coalescing [get]
+func (h *AdminHandler) ReadCoalescing(c *gin.Context) {
+	response.OK(c, h.coalescer.Stats())
+}
+
+// Branding godoc
+// @Summary Get the workspace branding
+// @Description The name, logo and accent color this deployment shows in emails, calendar feeds and printed checklists, and the setup status of its custom email domain.
+// @Tags workspace
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.WorkspaceBranding}
+// @Router /workspace/branding [get]
+func (h *AdminHandler) Branding(c *gin.Context) {
+	response.OK(c, h.brandingSvc.Get())
+}
+
+// UpdateBranding godoc
+// @Summary Change the workspace branding
+// @Description Omitted fields are kept; an empty string restores the default. A new email domain or DKIM selector needs verifying before mail is sent from it.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.UpdateWorkspaceBrandingRequest true "Branding"
+// @Success 200 {object} response.Envelope{data=domain.WorkspaceBranding}
+// @Failure 422 {object} response.Envelope
+// @Router /admin/branding [patch]
+func (h *AdminHandler) UpdateBranding(c *gin.Context) {
+	var req domain.UpdateWorkspaceBrandingRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	branding, err := h.brandingSvc.Update(c.Request.Context(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, branding)
+}
+
+// VerifyDKIM godoc
+// @Summary Check the custom email domain's DKIM record
+// @Description Looks up the TXT record at dkim_record. Once it holds a public key, mail is sent from the custom domain.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.WorkspaceBranding}
+// @Failure 409 {object} response.Envelope
+// @Failure 503 {object} response.Envelope
+// @Router /admin/branding/dkim/verify [post]
+func (h *AdminHandler) VerifyDKIM(c *gin.Context) {
+	branding, err := h.brandingSvc.VerifyDKIM(c.Request.Context())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrValidation):
+			response.Conflict(c, "no custom email domain is set")
+		case errors.Is(err, domain.ErrUnavailable):
+			response.ServiceUnavailable(c, "the DNS lookup failed; try again later")
+		default:
+			h.handleError(c, err)
+		}
+		return
+	}
+	response.OK(c, branding)
+}
+
+// InstanceSettings godoc
+// @Summary Get the instance settings
+// @Description The settings in force, which stored overrides they come from, and the mail backend.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.InstanceSettings}
+// @Router /admin/instance-settings [get]
+func (h *AdminHandler) InstanceSettings(c *gin.Context) {
+	response.OK(c, h.settingsSvc.Get())
+}
+
+// UpdateInstanceSettings godoc
+// @Summary Change the instance settings
+// @Description Takes effect at once on the instance that receives it and on the others when they restart. reset drops overrides so the environment's value applies again.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.UpdateInstanceSettingsRequest true "Settings"
+// @Success 200 {object} response.Envelope{data=domain.InstanceSettings}
+// @Failure 422 {object} response.Envelope
+// @Router /admin/instance-settings [patch]
+func (h *AdminHandler) UpdateInstanceSettings(c *gin.Context) {
+	var req domain.UpdateInstanceSettingsRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	settings, err := h.settingsSvc.Update(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.UnprocessableEntity(c, []validator.ValidationError{{Field: "modules", Message: "must only name known modules"}})
+			return
+		}
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, settings)
+}
+
+// TestMail godoc
+// @Summary Send a test email
+// @Description Sends through the configured mail backend right away and reports whether it was accepted. Goes to your own address unless to is given.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.MailTestRequest false "Recipient"
+// @Success 200 {object} response.Envelope{data=domain.MailTestResult}
+// @Failure 422 {object} response.Envelope
+// @Router /admin/instance-settings/mail/test [post]
+func (h *AdminHandler) TestMail(c *gin.Context) {
+	var req domain.MailTestRequest
+	if c.Request.ContentLength != 0 {
+		if errs, err := validator.BindAndValidate(c, &req); err != nil {
+			response.InternalError(c)
+			return
+		} else if errs != nil {
+			response.UnprocessableEntity(c, errs)
+			return
+		}
+	}
+
+	result, err := h.settingsSvc.TestMail(c.Request.Context(), middleware.CurrentUserID(c), req.To)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, result)
+}
+
+// MeteringReconciliation godoc
+// @Summary Compare metered usage with invoiced amounts
+// @Description Per metric, the usage metered over the UTC days from through to against what the billing module invoiced for it, with the rows billed at another quantity.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "First day, YYYY-MM-DD (default: first of this month)"
+// @Param to query string false "Last day, YYYY-MM-DD (default: today)"
+// @Success 200 {object} response.Envelope{data=domain.MeteringReconciliation}
+// @Failure 400 {object} response.Envelope
+// @Router /admin/metering/reconciliation [get]
+func (h *AdminHandler) MeteringReconciliation(c *gin.Context) {
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	for _, p := range []struct {
+		name string
+		day  *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if raw := c.Query(p.name); raw != "" {
+			parsed, err := parseDate(raw)
+			if err != nil {
+				response.BadRequest(c, "INVALID_PARAM", p.name+" must be a date (YYYY-MM-DD)", nil)
+				return
+			}
+			*p.day = parsed
+		}
+	}
+
+	report, err := h.meteringSvc.Reconcile(c.Request.Context(), from, to)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.BadRequest(c, "INVALID_PARAM", "to must not be before from, and the range at most 366 days", nil)
+			return
+		}
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, report)
+}
+
+// ListBackups godoc
+// @Summary List backups
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.Backup}
+// @Router /admin/backups [get]
+func (h *AdminHandler) ListBackups(c *gin.Context) {
+	backups, err := h.backupSvc.List(c.Request.Context())
+	if err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+	response.OK(c, backups)
+}
+
+// CreateBackup godoc
+// @Summary Back the database up now
+// @Description Writes a logical backup of every table to object storage. With Prefer: respond-async it runs as an async operation.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param Prefer header string false "respond-async to back up in the background"
+// @Success 201 {object} response.Envelope{data=domain.Backup}
+// @Success 202 {object} response.Envelope{data=domain.Operation}
+// @Failure 503 {object} response.Envelope
+// @Router /admin/backups [post]
+func (h *AdminHandler) CreateBackup(c *gin.Context) {
+	if !h.backupSvc.Enabled() {
+		h.handleBackupError(c, domain.ErrFeatureDisabled)
+		return
+	}
+	if prefersAsync(c) {
+		startOperation(c, h.operationSvc, service.OperationBackup, struct{}{})
+		return
+	}
+
+	backup, err := h.backupSvc.Create(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+	response.Created(c, backup)
+}
+
+// GetBackup godoc
+// @Summary Get a backup
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Backup UUID"
+// @Success 200 {object} response.Envelope{data=domain.Backup}
+// @Router /admin/backups/{id} [get]
+func (h *AdminHandler) GetBackup(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid backup id", nil)
+		return
+	}
+
+	backup, err := h.backupSvc.Get(c.Request.Context(), id)
+	if err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+	response.OK(c, backup)
+}
+
+// DeleteBackup godoc
+// @Summary Delete a backup and its file
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Backup UUID"
+// @Success 200 {object} response.Envelope
+// @Router /admin/backups/{id} [delete]
+func (h *AdminHandler) DeleteBackup(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid backup id", nil)
+		return
+	}
+
+	if err := h.backupSvc.Delete(c.Request.Context(), id); err != nil {
+		h.handleBackupError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "backup deleted"})
+}
+
+// RestoreBackup godoc
+// @Summary Replace all data with a backup
+// @Description Every table is replaced in one transaction; sessions and async operations not in the backup are lost. confirm must repeat the backup id. The file is checked against its checksum and the current schema first, and the current data is backed up before it is replaced. A dry run only performs the checks.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Backup UUID"
+// @Param body body domain.RestoreBackupRequest false "Confirmation, not needed for a dry run"
+// @Param dry_run query bool false "Preview only"
+// @Success 200 {object} response.Envelope{data=domain.BackupRestoreResult}
+// @Failure 409 {object} response.Envelope
+// @Failure 422 {object} response.Envelope
+// @Router /admin/backups/{id}/restore [post]
+func (h *AdminHandler) RestoreBackup(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid backup id", nil)
+		return
+	}
+	var req domain.RestoreBackupRequest
+	if c.Request.ContentLength != 0 {
+		if errs, err := validator.BindAndValidate(c, &req); err != nil {
+			response.InternalError(c)
+			return
+		} else if errs != nil {
+			response.UnprocessableEntity(c, errs)
+			return
+		}
+	}
+
+	result, err := h.backupSvc.Restore(c.Request.Context(), id, middleware.CurrentUserID(c), req.Confirm, isDryRun(c))
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.UnprocessableEntity(c, []validator.ValidationError{{Field: "confirm", Message: "must repeat the id of a completed backup"}})
+			return
+		}
+		h.handleBackupError(c, err)
+		return
+	}
+	response.OK(c, result)
+}
+
+func (h *AdminHandler) handleBackupError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrFeatureDisabled):
+		response.ServiceUnavailable(c, "backups need object storage, which is not configured on this server")
+	case errors.Is(err, domain.ErrUnavailable):
+		response.ServiceUnavailable(c, "a backup or restore is already running; try again later")
+	case errors.Is(err, domain.ErrBackupMismatch):
+		response.Conflict(c, err.Error())
+	default:
+		h.handleError(c, err)
+	}
+}
+
+func (h *AdminHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "resource not found")
+	default:
+		response.InternalError(c)
+	}
+}
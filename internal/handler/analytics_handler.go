@@ -1,11 +1,17 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
 	"github.com/galihaleanda/todo-app/internal/middleware"
 	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
 	"github.com/galihaleanda/todo-app/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,26 +20,38 @@ import (
 // AnalyticsHandler exposes analytics endpoints.
 type AnalyticsHandler struct {
 	analyticsSvc *service.AnalyticsService
+	renderer     *email.Renderer
 }
 
-// NewAnalyticsHandler creates an AnalyticsHandler.
-func NewAnalyticsHandler(analyticsSvc *service.AnalyticsService) *AnalyticsHandler {
-	return &AnalyticsHandler{analyticsSvc: analyticsSvc}
+// NewAnalyticsHandler creates an AnalyticsHandler. renderer produces the
+// format=text summaries.
+func NewAnalyticsHandler(analyticsSvc *service.AnalyticsService, renderer *email.Renderer) *AnalyticsHandler {
+	return &AnalyticsHandler{analyticsSvc: analyticsSvc, renderer: renderer}
 }
 
 // Dashboard godoc
 // @Summary Get productivity dashboard
 // @Tags analytics
 // @Security BearerAuth
-// @Produce json
+// @Produce json,plain
+// @Param format query string false "json (default) or text for a plain-text summary"
 // @Success 200 {object} response.Envelope{data=domain.AnalyticsDashboard}
 // @Router /analytics/dashboard [get]
 func (h *AnalyticsHandler) Dashboard(c *gin.Context) {
+	text, ok := textFormat(c)
+	if !ok {
+		return
+	}
+
 	dash, err := h.analyticsSvc.GetDashboard(c.Request.Context(), middleware.CurrentUserID(c))
 	if err != nil {
 		response.InternalError(c)
 		return
 	}
+	if text {
+		h.respondText(c, email.SummaryDashboard, dash)
+		return
+	}
 	response.OK(c, dash)
 }
 
@@ -41,12 +59,18 @@ func (h *AnalyticsHandler) Dashboard(c *gin.Context) {
 // @Summary Get daily productivity stats for a custom date range
 // @Tags analytics
 // @Security BearerAuth
-// @Produce json
+// @Produce json,plain
 // @Param from query string true "Start date (YYYY-MM-DD)"
 // @Param to query string true "End date (YYYY-MM-DD)"
+// @Param format query string false "json (default) or text for a plain-text summary"
 // @Success 200 {object} response.Envelope{data=[]domain.DailyStats}
 // @Router /analytics/daily [get]
 func (h *AnalyticsHandler) DailyStats(c *gin.Context) {
+	text, ok := textFormat(c)
+	if !ok {
+		return
+	}
+
 	from, err := parseDate(c.Query("from"))
 	if err != nil {
 		response.BadRequest(c, "INVALID_DATE", "from must be YYYY-MM-DD", nil)
@@ -65,9 +89,137 @@ func (h *AnalyticsHandler) DailyStats(c *gin.Context) {
 		return
 	}
 
+	if text {
+		h.respondText(c, email.SummaryDaily, stats)
+		return
+	}
 	response.OK(c, stats)
 }
 
+// Estimates godoc
+// @Summary Compare estimates with actual hours
+// @Description Over tasks finished in the last days days that had an estimate and actual hours (set on completion, or else their tracked time): totals, the actual-to-estimate ratio, and how many came within 20% of the estimate, overall and per priority.
+// @Tags analytics
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Look-back in days (default 90, max 365)"
+// @Success 200 {object} response.Envelope{data=domain.EstimateAccuracy}
+// @Failure 400 {object} response.Envelope
+// @Router /analytics/estimates [get]
+func (h *AnalyticsHandler) Estimates(c *gin.Context) {
+	days := 90
+	if v := c.Query("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "days must be a number", nil)
+			return
+		}
+		days = n
+	}
+
+	acc, err := h.analyticsSvc.EstimateAccuracy(c.Request.Context(), middleware.CurrentUserID(c), days)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.BadRequest(c, "INVALID_PARAM", "days must be between 1 and 365", nil)
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, acc)
+}
+
+// Ask godoc
+// @Summary Answer a natural-language question about your tasks
+// @Description Supports counts of tasks finished, created or overdue and average completion time,
+// @Description optionally grouped per project, priority, status, day, week or month over a relative period.
+// @Tags analytics
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param tz query string false "IANA time zone for periods and day buckets (default the user's time zone)"
+// @Param body body domain.AskAnalyticsRequest true "Question"
+// @Success 200 {object} response.Envelope{data=domain.AnalyticsAnswer}
+// @Failure 400 {object} response.Envelope
+// @Router /analytics/ask [post]
+func (h *AnalyticsHandler) Ask(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	var req domain.AskAnalyticsRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	answer, err := h.analyticsSvc.Ask(c.Request.Context(), middleware.CurrentUserID(c), req.Question, loc)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.BadRequest(c, "UNSUPPORTED_QUESTION", err.Error(), nil)
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+
+	response.OK(c, answer)
+}
+
+// Raw godoc
+// @Summary Export the raw events behind analytics
+// @Description Streams every task created or completed in the range as newline-delimited JSON, one event per line in time order, for analysis outside the app. Tasks deleted or in archived projects are left out, as in the aggregates.
+// @Tags analytics
+// @Security BearerAuth
+// @Produce x-ndjson
+// @Param from query string true "Start date (YYYY-MM-DD)"
+// @Param to query string true "End date (YYYY-MM-DD), inclusive"
+// @Param format query string false "ndjson (default)"
+// @Success 200 {object} domain.AnalyticsRawEvent "one per line"
+// @Failure 400 {object} response.Envelope
+// @Router /me/analytics/raw [get]
+func (h *AnalyticsHandler) Raw(c *gin.Context) {
+	if f := c.Query("format"); f != "" && f != "ndjson" {
+		response.UnprocessableEntity(c, validator.Invalid("format", "must be one of: ndjson"))
+		return
+	}
+	from, err := parseDate(c.Query("from"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_DATE", "from must be YYYY-MM-DD", nil)
+		return
+	}
+	to, err := parseDate(c.Query("to"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_DATE", "to must be YYYY-MM-DD", nil)
+		return
+	}
+	to = to.AddDate(0, 0, 1)
+	if !to.After(from) {
+		response.BadRequest(c, "INVALID_RANGE", "to must not be before from", nil)
+		return
+	}
+
+	userID := middleware.CurrentUserID(c)
+	streamNDJSON(c, func(emit func(any) error) error {
+		return h.analyticsSvc.StreamRawEvents(c.Request.Context(), userID, from, to, func(e *domain.AnalyticsRawEvent) error {
+			return emit(e)
+		})
+	})
+}
+
+// respondText writes a plain-text summary rendered from data.
+func (h *AnalyticsHandler) respondText(c *gin.Context, name email.Summary, data any) {
+	body, err := h.renderer.RenderSummary(name, data)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(body))
+}
+
 // --- shared helpers ---
 
 func parseUUID(c *gin.Context, param string) (uuid.UUID, error) {
@@ -80,3 +232,24 @@ func parseDate(s string) (time.Time, error) {
 	}
 	return time.Parse("2006-01-02", s)
 }
+
+// textFormat reports whether the request asked for format=text. Any value
+// other than text or json is rejected, in which case ok is false and the
+// response has been written.
+func textFormat(c *gin.Context) (text, ok bool) {
+	switch c.Query("format") {
+	case "", "json":
+		return false, true
+	case "text":
+		return true, true
+	default:
+		response.UnprocessableEntity(c, validator.Invalid("format", "must be one of: json, text"))
+		return false, false
+	}
+}
+
+// isDryRun reports whether the request asked to preview a destructive or bulk
+// operation without applying it.
+func isDryRun(c *gin.Context) bool {
+	return c.Query("dry_run") == "true"
+}
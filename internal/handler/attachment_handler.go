@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AttachmentHandler exposes task attachment endpoints.
+type AttachmentHandler struct {
+	attachmentSvc *service.AttachmentService
+}
+
+// NewAttachmentHandler creates an AttachmentHandler.
+func NewAttachmentHandler(attachmentSvc *service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{attachmentSvc: attachmentSvc}
+}
+
+// Create godoc
+// @Summary Start an attachment upload
+// @Description Returns a pending attachment and a presigned request to PUT the file to, then confirm with /complete.
+// @Tags attachments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.CreateAttachmentRequest true "File details"
+// @Success 201 {object} response.Envelope{data=domain.AttachmentUpload}
+// @Failure 400 {object} response.Envelope "File too large or type not allowed"
+// @Failure 503 {object} response.Envelope
+// @Router /tasks/{id}/attachments [post]
+func (h *AttachmentHandler) Create(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.CreateAttachmentRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	upload, err := h.attachmentSvc.CreateUpload(c.Request.Context(), taskID, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, upload)
+}
+
+// List godoc
+// @Summary List a task's attachments
+// @Tags attachments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Envelope{data=[]domain.Attachment}
+// @Router /tasks/{id}/attachments [get]
+func (h *AttachmentHandler) List(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	attachments, err := h.attachmentSvc.List(c.Request.Context(), taskID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, attachments)
+}
+
+// Complete godoc
+// @Summary Confirm an attachment upload
+// @Tags attachments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {object} response.Envelope{data=domain.Attachment}
+// @Failure 400 {object} response.Envelope "File missing or size mismatch"
+// @Router /tasks/{id}/attachments/{attachmentID}/complete [post]
+func (h *AttachmentHandler) Complete(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	a, err := h.attachmentSvc.Complete(c.Request.Context(), taskID, id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, a)
+}
+
+// Download godoc
+// @Summary Get a presigned download URL for an attachment
+// @Tags attachments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {object} response.Envelope{data=domain.PresignedURL}
+// @Router /tasks/{id}/attachments/{attachmentID}/download [get]
+func (h *AttachmentHandler) Download(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	url, err := h.attachmentSvc.Download(c.Request.Context(), taskID, id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, url)
+}
+
+// Delete godoc
+// @Summary Delete an attachment and its file
+// @Tags attachments
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param attachmentID path string true "Attachment ID"
+// @Success 200 {object} response.Envelope
+// @Router /tasks/{id}/attachments/{attachmentID} [delete]
+func (h *AttachmentHandler) Delete(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentSvc.Delete(c.Request.Context(), taskID, id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "attachment deleted"})
+}
+
+func (h *AttachmentHandler) parseIDs(c *gin.Context) (taskID, id uuid.UUID, ok bool) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err = parseUUID(c, "attachmentID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid attachment id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return taskID, id, true
+}
+
+func (h *AttachmentHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	case errors.Is(err, domain.ErrFeatureDisabled):
+		response.ServiceUnavailable(c, "attachments are not enabled on this server")
+	default:
+		response.InternalError(c)
+	}
+}
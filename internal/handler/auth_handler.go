@@ -13,12 +13,13 @@ import (
 
 // AuthHandler exposes authentication endpoints.
 type AuthHandler struct {
-	authSvc *service.AuthService
+	authSvc      *service.AuthService
+	twoFactorSvc *service.TwoFactorService
 }
 
 // NewAuthHandler creates an AuthHandler.
-func NewAuthHandler(authSvc *service.AuthService) *AuthHandler {
-	return &AuthHandler{authSvc: authSvc}
+func NewAuthHandler(authSvc *service.AuthService, twoFactorSvc *service.TwoFactorService) *AuthHandler {
+	return &AuthHandler{authSvc: authSvc, twoFactorSvc: twoFactorSvc}
 }
 
 // Register godoc
@@ -26,8 +27,12 @@ func NewAuthHandler(authSvc *service.AuthService) *AuthHandler {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Description invite_code is required while SIGNUP_MODE is invite_only. A referral code may be sent as referral_code or as ?ref= on a shared signup link. Requests are rate limited per client IP.
 // @Param body body domain.RegisterRequest true "Registration payload"
+// @Param ref query string false "Referral code from a signup link"
 // @Success 201 {object} response.Envelope{data=domain.AuthResponse}
+// @Failure 403 {object} response.Envelope "Missing or unusable invite code"
+// @Failure 429 {object} response.Envelope "Too many signups from this address"
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req domain.RegisterRequest
@@ -38,12 +43,19 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		response.UnprocessableEntity(c, errs)
 		return
 	}
+	if ref := c.Query("ref"); req.ReferralCode == "" && ref != "" && len(ref) <= 64 {
+		req.ReferralCode, req.ReferralSource = ref, domain.ReferralSourceLink
+	}
 
 	authResp, err := h.authSvc.Register(c.Request.Context(), &req)
 	if err != nil {
 		switch {
 		case errors.Is(err, domain.ErrAlreadyExists):
 			response.Conflict(c, "email already registered")
+		case errors.Is(err, domain.ErrInviteRequired):
+			response.Forbidden(c, "signup is invite-only; an invite code is required")
+		case errors.Is(err, domain.ErrInviteInvalid):
+			response.Forbidden(c, "invite code is invalid, expired or used up")
 		default:
 			response.InternalError(c)
 		}
@@ -55,6 +67,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Authenticate a user
+// @Description With two-factor authentication on, the response carries two_factor_required and a challenge_token for POST /auth/login/2fa instead of tokens.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -85,6 +98,132 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	response.OK(c, authResp)
 }
 
+// LoginTwoFactor godoc
+// @Summary Complete a login with a two-factor code
+// @Description Exchanges the challenge token from POST /auth/login, valid for 5 minutes, and a code from the authenticator app or an unused recovery code for tokens. After 5 wrong codes in a row, codes are refused for 15 minutes.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorLoginRequest true "Challenge and code"
+// @Success 200 {object} response.Envelope{data=domain.AuthResponse}
+// @Failure 401 {object} response.Envelope
+// @Failure 429 {object} response.Envelope
+// @Router /auth/login/2fa [post]
+func (h *AuthHandler) LoginTwoFactor(c *gin.Context) {
+	var req domain.TwoFactorLoginRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	authResp, err := h.authSvc.LoginTwoFactor(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrTokenInvalid):
+			response.Unauthorized(c, "invalid or expired challenge token")
+		default:
+			h.handleTwoFactorError(c, err)
+		}
+		return
+	}
+
+	response.OK(c, authResp)
+}
+
+// SetupTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Returns a new TOTP secret and its otpauth URI for an authenticator app, replacing one not verified yet. Login is unchanged until POST /auth/2fa/verify.
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.TwoFactorSetup}
+// @Failure 409 {object} response.Envelope "Already enabled"
+// @Router /auth/2fa/setup [post]
+func (h *AuthHandler) SetupTwoFactor(c *gin.Context) {
+	setup, err := h.twoFactorSvc.Setup(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+	response.OK(c, setup)
+}
+
+// VerifyTwoFactor godoc
+// @Summary Turn two-factor authentication on
+// @Description Verifies a code from the secret given by POST /auth/2fa/setup and returns 10 single-use recovery codes, shown only this once.
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorCodeRequest true "Code from the authenticator app"
+// @Success 200 {object} response.Envelope{data=domain.TwoFactorRecoveryCodes}
+// @Failure 401 {object} response.Envelope
+// @Failure 404 {object} response.Envelope "No enrollment started"
+// @Failure 409 {object} response.Envelope "Already enabled"
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	codes, err := h.twoFactorSvc.Enable(c.Request.Context(), middleware.CurrentUserID(c), req.Code)
+	if err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+	response.OK(c, codes)
+}
+
+// DisableTwoFactor godoc
+// @Summary Turn two-factor authentication off
+// @Tags auth
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.TwoFactorCodeRequest true "Code from the authenticator app, or a recovery code"
+// @Failure 401 {object} response.Envelope
+// @Failure 429 {object} response.Envelope
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	var req domain.TwoFactorCodeRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	if err := h.twoFactorSvc.Disable(c.Request.Context(), middleware.CurrentUserID(c), req.Code); err != nil {
+		h.handleTwoFactorError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "two-factor authentication disabled"})
+}
+
+func (h *AuthHandler) handleTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidCredentials):
+		response.Unauthorized(c, "invalid two-factor code")
+	case errors.Is(err, domain.ErrTooManyAttempts):
+		response.TooManyRequests(c, "too many wrong two-factor codes; try again later", service.TwoFactorLockout)
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "two-factor authentication is already enabled")
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "no two-factor enrollment started; call POST /auth/2fa/setup first")
+	default:
+		response.InternalError(c)
+	}
+}
+
 // RefreshToken godoc
 // @Summary Rotate access and refresh tokens
 // @Tags auth
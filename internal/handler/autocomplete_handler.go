@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AutocompleteHandler exposes name lookups for client pickers.
+type AutocompleteHandler struct {
+	autocompleteSvc *service.AutocompleteService
+}
+
+// NewAutocompleteHandler creates an AutocompleteHandler.
+func NewAutocompleteHandler(autocompleteSvc *service.AutocompleteService) *AutocompleteHandler {
+	return &AutocompleteHandler{autocompleteSvc: autocompleteSvc}
+}
+
+// Complete godoc
+// @Summary Suggest names starting with a prefix
+// @Tags autocomplete
+// @Security BearerAuth
+// @Produce json
+// @Param type query string true "project | tag"
+// @Param q query string false "Case-insensitive prefix; empty returns the first names"
+// @Param limit query int false "Maximum results (default 10, max 25)"
+// @Success 200 {object} response.Envelope{data=[]domain.Suggestion}
+// @Router /autocomplete [get]
+func (h *AutocompleteHandler) Complete(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	suggestions, err := h.autocompleteSvc.Complete(
+		c.Request.Context(), middleware.CurrentUserID(c), c.Query("type"), c.Query("q"), limit,
+	)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.BadRequest(c, "INVALID_PARAM", "type must be a supported suggestion type", nil)
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+
+	response.OK(c, suggestions)
+}
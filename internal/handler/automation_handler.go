@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// AutomationHandler exposes CRUD for the user's automation rules and their execution log.
+type AutomationHandler struct {
+	automationSvc *service.AutomationService
+}
+
+// NewAutomationHandler creates an AutomationHandler.
+func NewAutomationHandler(automationSvc *service.AutomationService) *AutomationHandler {
+	return &AutomationHandler{automationSvc: automationSvc}
+}
+
+// Create godoc
+// @Summary Create an automation rule
+// @Tags automations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateAutomationRuleRequest true "Rule payload"
+// @Success 201 {object} response.Envelope{data=domain.AutomationRule}
+// @Router /automations [post]
+func (h *AutomationHandler) Create(c *gin.Context) {
+	var req domain.CreateAutomationRuleRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	rule, err := h.automationSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, rule)
+}
+
+// List godoc
+// @Summary List automation rules
+// @Tags automations
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.AutomationRule}
+// @Router /automations [get]
+func (h *AutomationHandler) List(c *gin.Context) {
+	rules, err := h.automationSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, rules)
+}
+
+// GetByID godoc
+// @Summary Get an automation rule
+// @Tags automations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Envelope{data=domain.AutomationRule}
+// @Router /automations/{id} [get]
+func (h *AutomationHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	rule, err := h.automationSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, rule)
+}
+
+// Update godoc
+// @Summary Update an automation rule
+// @Tags automations
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param body body domain.UpdateAutomationRuleRequest true "Fields to update"
+// @Success 200 {object} response.Envelope{data=domain.AutomationRule}
+// @Router /automations/{id} [patch]
+func (h *AutomationHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	var req domain.UpdateAutomationRuleRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	rule, err := h.automationSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, rule)
+}
+
+// Enable godoc
+// @Summary Re-enable an automation rule
+// @Description Turns the rule on and closes its circuit breaker, which opens after repeated failed runs.
+// @Tags automations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Envelope{data=domain.AutomationRule}
+// @Router /automations/{id}/enable [post]
+func (h *AutomationHandler) Enable(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	rule, err := h.automationSvc.Enable(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, rule)
+}
+
+// Delete godoc
+// @Summary Delete an automation rule
+// @Tags automations
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Envelope
+// @Router /automations/{id} [delete]
+func (h *AutomationHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	if err := h.automationSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "automation rule deleted"})
+}
+
+// Executions godoc
+// @Summary List an automation rule's recent executions
+// @Description Each time the rule fires it logs the task, the outcome and how many actions were applied.
+// @Tags automations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param limit query int false "Maximum executions to return (default and max 100)"
+// @Success 200 {object} response.Envelope{data=[]domain.AutomationExecution}
+// @Router /automations/{id}/executions [get]
+func (h *AutomationHandler) Executions(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 {
+			response.BadRequest(c, "INVALID_PARAM", "limit must be a positive integer", nil)
+			return
+		}
+	}
+
+	execs, err := h.automationSvc.Executions(c.Request.Context(), id, middleware.CurrentUserID(c), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, execs)
+}
+
+func (h *AutomationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "rule not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this rule")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BreakdownHandler exposes LLM-assisted task breakdown.
+type BreakdownHandler struct {
+	breakdownSvc *service.BreakdownService
+}
+
+// NewBreakdownHandler creates a BreakdownHandler.
+func NewBreakdownHandler(breakdownSvc *service.BreakdownService) *BreakdownHandler {
+	return &BreakdownHandler{breakdownSvc: breakdownSvc}
+}
+
+// Propose godoc
+// @Summary Suggest subtasks and an effort split for a task
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param max_subtasks query int false "At most this many suggestions (default 5, max 10)"
+// @Success 200 {object} response.Envelope{data=domain.TaskBreakdown}
+// @Failure 503 {object} response.Envelope
+// @Router /tasks/{id}/breakdown [post]
+func (h *BreakdownHandler) Propose(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	limit := 0
+	if v := c.Query("max_subtasks"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			response.BadRequest(c, "INVALID_PARAM", "max_subtasks must be a positive integer", nil)
+			return
+		}
+		limit = n
+	}
+
+	breakdown, err := h.breakdownSvc.Propose(c.Request.Context(), middleware.CurrentUserID(c), id, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, breakdown)
+}
+
+// Accept godoc
+// @Summary Create accepted breakdown suggestions as subtasks
+// @Tags tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.AcceptBreakdownRequest true "Suggestions to create, edited as needed"
+// @Success 201 {object} response.Envelope{data=[]domain.Task}
+// @Router /tasks/{id}/breakdown/accept [post]
+func (h *BreakdownHandler) Accept(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	var req domain.AcceptBreakdownRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	tasks, err := h.breakdownSvc.Accept(c.Request.Context(), middleware.CurrentUserID(c), id, &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, tasks)
+}
+
+func (h *BreakdownHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrFeatureDisabled):
+		response.ServiceUnavailable(c, "task breakdown is not enabled on this server")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// BusinessCalendarHandler exposes holiday calendars and the user's working
+// week and days off.
+type BusinessCalendarHandler struct {
+	calendarSvc *service.BusinessCalendarService
+}
+
+// NewBusinessCalendarHandler creates a BusinessCalendarHandler.
+func NewBusinessCalendarHandler(calendarSvc *service.BusinessCalendarService) *BusinessCalendarHandler {
+	return &BusinessCalendarHandler{calendarSvc: calendarSvc}
+}
+
+// Countries godoc
+// @Summary List the country holiday calendars
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]holiday.Calendar}
+// @Router /holidays [get]
+func (h *BusinessCalendarHandler) Countries(c *gin.Context) {
+	response.OK(c, h.calendarSvc.Countries())
+}
+
+// Holidays godoc
+// @Summary List a country's public holidays in a year
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Param country path string true "Country code, e.g. US"
+// @Param year query int false "Year, default the current one"
+// @Success 200 {object} response.Envelope{data=[]holiday.Holiday}
+// @Failure 404 {object} response.Envelope
+// @Router /holidays/{country} [get]
+func (h *BusinessCalendarHandler) Holidays(c *gin.Context) {
+	year := time.Now().Year()
+	if v := c.Query("year"); v != "" {
+		y, err := strconv.Atoi(v)
+		if err != nil || y < 1900 || y > 2200 {
+			response.BadRequest(c, "INVALID_PARAM", "year must be between 1900 and 2200", nil)
+			return
+		}
+		year = y
+	}
+
+	holidays, err := h.calendarSvc.Holidays(c.Param("country"), year)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, holidays)
+}
+
+// Get godoc
+// @Summary Get the user's working week
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.BusinessCalendar}
+// @Router /me/calendar [get]
+func (h *BusinessCalendarHandler) Get(c *gin.Context) {
+	cal, err := h.calendarSvc.Get(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, cal)
+}
+
+// Update godoc
+// @Summary Configure the user's working week
+// @Description Sets the weekend days, the country whose public holidays are observed, the working hours, and whether due dates falling on a day off move to the next business day. The shift applies to new tasks and to each new occurrence of a recurring task. working_time_only makes overdue time, workload and slot suggestions count only working hours.
+// @Tags calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.UpdateBusinessCalendarRequest true "Working week"
+// @Success 200 {object} response.Envelope{data=domain.BusinessCalendar}
+// @Failure 400 {object} response.Envelope
+// @Router /me/calendar [put]
+func (h *BusinessCalendarHandler) Update(c *gin.Context) {
+	var req domain.UpdateBusinessCalendarRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	cal, err := h.calendarSvc.Update(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, cal)
+}
+
+// ListDaysOff godoc
+// @Summary List the user's days off
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.DayOff}
+// @Router /me/days-off [get]
+func (h *BusinessCalendarHandler) ListDaysOff(c *gin.Context) {
+	days, err := h.calendarSvc.ListDaysOff(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, days)
+}
+
+// AddDayOff godoc
+// @Summary Add a day off
+// @Tags calendar
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateDayOffRequest true "Day off"
+// @Success 201 {object} response.Envelope{data=domain.DayOff}
+// @Failure 409 {object} response.Envelope "Date already a day off"
+// @Router /me/days-off [post]
+func (h *BusinessCalendarHandler) AddDayOff(c *gin.Context) {
+	var req domain.CreateDayOffRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	day, err := h.calendarSvc.AddDayOff(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, day)
+}
+
+// DeleteDayOff godoc
+// @Summary Remove a day off
+// @Tags calendar
+// @Security BearerAuth
+// @Param id path string true "Day off ID"
+// @Success 200 {object} response.Envelope
+// @Router /me/days-off/{id} [delete]
+func (h *BusinessCalendarHandler) DeleteDayOff(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid day off id", nil)
+		return
+	}
+
+	if err := h.calendarSvc.DeleteDayOff(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "day off removed"})
+}
+
+func (h *BusinessCalendarHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "that date is already a day off")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarFeedHandler manages and serves the user's iCalendar feed.
+type CalendarFeedHandler struct {
+	feedSvc *service.CalendarFeedService
+}
+
+// NewCalendarFeedHandler creates a CalendarFeedHandler.
+func NewCalendarFeedHandler(feedSvc *service.CalendarFeedService) *CalendarFeedHandler {
+	return &CalendarFeedHandler{feedSvc: feedSvc}
+}
+
+// Get godoc
+// @Summary Get the calendar feed URL
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.CalendarFeed}
+// @Failure 404 {object} response.Envelope "No feed; create one first"
+// @Router /me/calendar-feed [get]
+func (h *CalendarFeedHandler) Get(c *gin.Context) {
+	feed, err := h.feedSvc.Get(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, feed)
+}
+
+// Rotate godoc
+// @Summary Create or replace the calendar feed URL
+// @Description Issues a secret iCalendar URL that calendar apps can subscribe to. Any earlier URL stops working.
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 201 {object} response.Envelope{data=domain.CalendarFeed}
+// @Router /me/calendar-feed [post]
+func (h *CalendarFeedHandler) Rotate(c *gin.Context) {
+	feed, err := h.feedSvc.Rotate(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, feed)
+}
+
+// QR godoc
+// @Summary Get the calendar feed URL as a QR code
+// @Description A PNG QR code of the feed URL, for subscribing from a phone by scanning it.
+// @Tags calendar
+// @Security BearerAuth
+// @Produce image/png
+// @Param scale query int false "Pixels per module, 1-20" default(8)
+// @Success 200 {file} file
+// @Failure 404 {object} response.Envelope "No feed; create one first"
+// @Router /me/calendar-feed/qr [get]
+func (h *CalendarFeedHandler) QR(c *gin.Context) {
+	scale, ok := parseQRScale(c)
+	if !ok {
+		return
+	}
+	out, err := h.feedSvc.QR(c.Request.Context(), middleware.CurrentUserID(c), scale)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", out)
+}
+
+// Revoke godoc
+// @Summary Turn the calendar feed off
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Failure 404 {object} response.Envelope
+// @Router /me/calendar-feed [delete]
+func (h *CalendarFeedHandler) Revoke(c *gin.Context) {
+	if err := h.feedSvc.Revoke(c.Request.Context(), middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "calendar feed revoked"})
+}
+
+// Feed godoc
+// @Summary Subscribe to tasks as a calendar
+// @Description iCalendar feed of open tasks from a month ago to a year ahead: scheduled tasks as timed events over their slot, due dates as all-day events in the user's time zone. The secret token in the path is the only authentication.
+// @Tags calendar
+// @Produce text/calendar
+// @Param file path string true "Feed token followed by .ics"
+// @Success 200 {string} string "iCalendar feed"
+// @Failure 404 {object} response.Envelope
+// @Router /calendar-feeds/{file} [get]
+func (h *CalendarFeedHandler) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("file"), ".ics")
+	out, err := h.feedSvc.Render(c.Request.Context(), token, time.Now())
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(out))
+}
+
+func (h *CalendarFeedHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "calendar feed not found")
+	default:
+		response.InternalError(c)
+	}
+}
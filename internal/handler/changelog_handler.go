@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ChangelogHandler exposes the product changelog and its admin endpoint.
+type ChangelogHandler struct {
+	changelogSvc *service.ChangelogService
+}
+
+// NewChangelogHandler creates a ChangelogHandler.
+func NewChangelogHandler(changelogSvc *service.ChangelogService) *ChangelogHandler {
+	return &ChangelogHandler{changelogSvc: changelogSvc}
+}
+
+// Create godoc
+// @Summary Publish a changelog entry
+// @Description Adds release notes for a version. A future published_at schedules the entry.
+// @Tags admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateChangelogRequest true "Changelog entry"
+// @Success 201 {object} response.Envelope{data=domain.ChangelogEntry}
+// @Failure 409 {object} response.Envelope "Version already has an entry"
+// @Router /admin/changelog [post]
+func (h *ChangelogHandler) Create(c *gin.Context) {
+	var req domain.CreateChangelogRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	entry, err := h.changelogSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, entry)
+}
+
+// List godoc
+// @Summary Get the product changelog
+// @Description Published entries, newest version first, each flagged unread if it came out after the user last marked the changelog seen.
+// @Tags changelog
+// @Security BearerAuth
+// @Produce json
+// @Param since_version query string false "Only entries for versions after this one, e.g. the client's own version"
+// @Success 200 {object} response.Envelope{data=domain.ChangelogFeed}
+// @Router /changelog [get]
+func (h *ChangelogHandler) List(c *gin.Context) {
+	feed, err := h.changelogSvc.Feed(c.Request.Context(), middleware.CurrentUserID(c), c.Query("since_version"))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, feed)
+}
+
+// MarkSeen godoc
+// @Summary Mark the changelog as seen
+// @Tags changelog
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /changelog/seen [post]
+func (h *ChangelogHandler) MarkSeen(c *gin.Context) {
+	if err := h.changelogSvc.MarkSeen(c.Request.Context(), middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "changelog marked as seen"})
+}
+
+func (h *ChangelogHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "this version already has a changelog entry")
+	default:
+		response.InternalError(c)
+	}
+}
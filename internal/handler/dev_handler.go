@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// DevHandler exposes development-only tooling. It is only registered when APP_ENV=development.
+type DevHandler struct {
+	renderer *email.Renderer
+	baseURL  string
+}
+
+// NewDevHandler creates a DevHandler.
+func NewDevHandler(renderer *email.Renderer, baseURL string) *DevHandler {
+	return &DevHandler{renderer: renderer, baseURL: baseURL}
+}
+
+// ListEmailTemplates godoc
+// @Summary List available email templates (development only)
+// @Tags dev
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]string}
+// @Router /dev/emails [get]
+func (h *DevHandler) ListEmailTemplates(c *gin.Context) {
+	response.OK(c, h.renderer.Names())
+}
+
+// PreviewEmail godoc
+// @Summary Render an email template with sample data (development only)
+// @Tags dev
+// @Produce html,plain
+// @Param template path string true "Template name"
+// @Param format query string false "html (default) or text for the plain-text part"
+// @Success 200 {string} string "Rendered HTML"
+// @Router /dev/emails/{template} [get]
+func (h *DevHandler) PreviewEmail(c *gin.Context) {
+	name := email.Template(c.Param("template"))
+	if !h.renderer.Has(name) {
+		response.NotFound(c, "email template not found")
+		return
+	}
+
+	rendered, err := h.renderer.Render(name, email.SampleData(name, h.baseURL))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	c.Header("X-Email-Subject", rendered.Subject)
+	if c.Query("format") == "text" {
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(rendered.Text))
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(rendered.HTML))
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeDocument reads an uploaded document into generic JSON values. YAML
+// is passed through JSON, so its timestamps and numbers take the shapes a
+// JSON body would have.
+func decodeDocument(body []byte, isYAML bool) (any, error) {
+	if isYAML {
+		var doc any
+		if err := yaml.Unmarshal(body, &doc); err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		body = raw
+	}
+	var doc any
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// checkDocument holds body up against the schema of kind, responding with
+// 422 and every violation when it does not conform. A document that does not
+// parse is left to the caller's decoder to report.
+func checkDocument(c *gin.Context, kind string, body []byte, isYAML bool) bool {
+	doc, err := decodeDocument(body, isYAML)
+	if err != nil {
+		return true
+	}
+	result, err := service.ValidateDocument(kind, doc)
+	if err != nil {
+		response.InternalError(c)
+		return false
+	}
+	if result.Valid {
+		return true
+	}
+	errs := make([]validator.ValidationError, len(result.Errors))
+	for i, e := range result.Errors {
+		field := e.Path
+		if field == "" {
+			field = "body"
+		}
+		errs[i] = validator.ValidationError{Field: field, Message: e.Message}
+	}
+	response.UnprocessableEntity(c, errs)
+	return false
+}
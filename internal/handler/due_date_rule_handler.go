@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// DueDateRuleHandler exposes CRUD for the user's due-date rules.
+type DueDateRuleHandler struct {
+	ruleSvc *service.DueDateRuleService
+}
+
+// NewDueDateRuleHandler creates a DueDateRuleHandler.
+func NewDueDateRuleHandler(ruleSvc *service.DueDateRuleService) *DueDateRuleHandler {
+	return &DueDateRuleHandler{ruleSvc: ruleSvc}
+}
+
+// Create godoc
+// @Summary Create a due-date rule
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateDueDateRuleRequest true "Rule payload"
+// @Success 201 {object} response.Envelope{data=domain.DueDateRule}
+// @Router /me/rules [post]
+func (h *DueDateRuleHandler) Create(c *gin.Context) {
+	var req domain.CreateDueDateRuleRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	rule, err := h.ruleSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, rule)
+}
+
+// List godoc
+// @Summary List due-date rules in evaluation order
+// @Tags rules
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.DueDateRule}
+// @Router /me/rules [get]
+func (h *DueDateRuleHandler) List(c *gin.Context) {
+	rules, err := h.ruleSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, rules)
+}
+
+// GetByID godoc
+// @Summary Get a due-date rule
+// @Tags rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Envelope{data=domain.DueDateRule}
+// @Router /me/rules/{id} [get]
+func (h *DueDateRuleHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	rule, err := h.ruleSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, rule)
+}
+
+// Update godoc
+// @Summary Update a due-date rule
+// @Tags rules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param body body domain.UpdateDueDateRuleRequest true "Fields to update"
+// @Success 200 {object} response.Envelope{data=domain.DueDateRule}
+// @Router /me/rules/{id} [patch]
+func (h *DueDateRuleHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	var req domain.UpdateDueDateRuleRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	rule, err := h.ruleSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, rule)
+}
+
+// Delete godoc
+// @Summary Delete a due-date rule
+// @Tags rules
+// @Security BearerAuth
+// @Param id path string true "Rule ID"
+// @Success 200 {object} response.Envelope
+// @Router /me/rules/{id} [delete]
+func (h *DueDateRuleHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid rule id", nil)
+		return
+	}
+
+	if err := h.ruleSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "rule deleted"})
+}
+
+func (h *DueDateRuleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "rule not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this rule")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// EscalationHandler exposes the audit trail of automatic priority raises.
+type EscalationHandler struct {
+	escalationSvc *service.EscalationService
+}
+
+// NewEscalationHandler creates an EscalationHandler.
+func NewEscalationHandler(escalationSvc *service.EscalationService) *EscalationHandler {
+	return &EscalationHandler{escalationSvc: escalationSvc}
+}
+
+// Escalations godoc
+// @Summary List a task's automatic priority escalations
+// @Description Each time the escalation job raised the task's priority, as its due date neared or after it sat long in todo (reason aging), most recent first.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.TaskEscalation}
+// @Failure 404 {object} response.Envelope
+// @Router /tasks/{id}/escalations [get]
+func (h *EscalationHandler) Escalations(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	pag := pagination.FromContext(c)
+
+	escalations, total, err := h.escalationSvc.Escalations(c.Request.Context(), id, middleware.CurrentUserID(c), pag.Page, pag.Limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OKPaginated(c, escalations, pag.Page, pag.Limit, total)
+}
+
+func (h *EscalationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// setETag serves a row version as a strong ETag.
+func setETag(c *gin.Context, version int64) {
+	c.Header("ETag", `"`+strconv.FormatInt(version, 10)+`"`)
+}
+
+// parseIfMatch reads the If-Match header as a version for the service to
+// check, writing a 400 and returning false when it is not a single ETag
+// from setETag. It returns nil when the header is omitted or "*", which
+// matches any version.
+func parseIfMatch(c *gin.Context) (*int64, bool) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return nil, true
+	}
+	unquoted, err := strconv.Unquote(v)
+	if err == nil && v[0] == '"' {
+		if version, err := strconv.ParseInt(unquoted, 10, 64); err == nil {
+			return &version, true
+		}
+	}
+	response.BadRequest(c, "INVALID_PARAM", "If-Match must be a single ETag from a previous response", nil)
+	return nil, false
+}
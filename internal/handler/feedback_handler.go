@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FeedbackHandler exposes feedback intake and its admin endpoints.
+type FeedbackHandler struct {
+	feedbackSvc *service.FeedbackService
+}
+
+// NewFeedbackHandler creates a FeedbackHandler.
+func NewFeedbackHandler(feedbackSvc *service.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{feedbackSvc: feedbackSvc}
+}
+
+// Create godoc
+// @Summary Send feedback or a bug report
+// @Description Stores the report for admins and, when configured, forwards it to the issue tracker. diagnostics.request_ids takes X-Request-ID values of recent API calls.
+// @Tags feedback
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateFeedbackRequest true "Feedback"
+// @Success 201 {object} response.Envelope{data=domain.Feedback}
+// @Failure 422 {object} response.Envelope
+// @Router /feedback [post]
+func (h *FeedbackHandler) Create(c *gin.Context) {
+	var req domain.CreateFeedbackRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	f, err := h.feedbackSvc.Submit(c.Request.Context(), middleware.CurrentUserID(c), c.Request.UserAgent(), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, f)
+}
+
+// List godoc
+// @Summary List feedback reports
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param category query string false "bug | idea | question | other"
+// @Param user_id query string false "Only reports from this user"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.Feedback}
+// @Router /admin/feedback [get]
+func (h *FeedbackHandler) List(c *gin.Context) {
+	pag := pagination.FromContext(c)
+	filter := domain.FeedbackFilter{Page: pag.Page, Limit: pag.Limit}
+	if v := c.Query("category"); v != "" {
+		category := domain.FeedbackCategory(v)
+		if !slices.Contains(domain.FeedbackCategories, category) {
+			response.UnprocessableEntity(c, validator.Invalid("category", validator.EnumMessage(domain.FeedbackCategories)))
+			return
+		}
+		filter.Category = &category
+	}
+	if v := c.Query("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	reports, total, err := h.feedbackSvc.List(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OKPaginated(c, reports, pag.Page, pag.Limit, total)
+}
+
+// Get godoc
+// @Summary Get a feedback report
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Feedback UUID"
+// @Success 200 {object} response.Envelope{data=domain.Feedback}
+// @Failure 404 {object} response.Envelope
+// @Router /admin/feedback/{id} [get]
+func (h *FeedbackHandler) Get(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid feedback id", nil)
+		return
+	}
+
+	f, err := h.feedbackSvc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, f)
+}
+
+func (h *FeedbackHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "feedback not found")
+	default:
+		response.InternalError(c)
+	}
+}
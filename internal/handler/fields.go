@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// parseFields reads the ?fields= sparse fieldset, writing a 400 and
+// returning false when it names a field not in allowed.
+func parseFields(c *gin.Context, allowed []string) (domain.Fields, bool) {
+	fields, err := domain.ParseFields(c.Query("fields"), allowed)
+	if err != nil {
+		response.BadRequest(c, "INVALID_PARAM", err.Error(), validator.Invalid("fields", validator.EnumMessage(allowed)))
+		return nil, false
+	}
+	return fields, true
+}
+
+// sparse renders each item as a JSON object holding only fields. Fields a
+// row would omit when empty stay omitted. With nil fields the items are
+// returned as they are.
+func sparse[T any](items []T, fields domain.Fields) (any, error) {
+	if fields == nil {
+		return items, nil
+	}
+	out := make([]map[string]json.RawMessage, len(items))
+	for i, item := range items {
+		m, err := sparseItem(item, fields)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = m
+	}
+	return out, nil
+}
+
+// sparseItem renders one item as a JSON object holding only fields.
+func sparseItem(item any, fields domain.Fields) (map[string]json.RawMessage, error) {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &all); err != nil {
+		return nil, err
+	}
+	out := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		if v, ok := all[f]; ok {
+			out[f] = v
+		}
+	}
+	return out, nil
+}
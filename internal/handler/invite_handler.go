@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// InviteHandler exposes signup invite code endpoints.
+type InviteHandler struct {
+	inviteSvc *service.InviteService
+}
+
+// NewInviteHandler creates an InviteHandler.
+func NewInviteHandler(inviteSvc *service.InviteService) *InviteHandler {
+	return &InviteHandler{inviteSvc: inviteSvc}
+}
+
+// Create godoc
+// @Summary Create an invite code
+// @Description Regular users get single-use codes within their quota; admins may set max_uses.
+// @Tags invites
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateInviteRequest true "Invite options"
+// @Success 201 {object} response.Envelope{data=domain.InviteCode}
+// @Failure 403 {object} response.Envelope "Quota used up, or multi-use code requested by a non-admin"
+// @Router /invites [post]
+func (h *InviteHandler) Create(c *gin.Context) {
+	var req domain.CreateInviteRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	invite, err := h.inviteSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, invite)
+}
+
+// List godoc
+// @Summary List my invite codes
+// @Tags invites
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.InviteSummary}
+// @Router /invites [get]
+func (h *InviteHandler) List(c *gin.Context) {
+	summary, err := h.inviteSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, summary)
+}
+
+// Revoke godoc
+// @Summary Revoke an invite code
+// @Description The code can no longer be redeemed; accounts created with it keep their attribution.
+// @Tags invites
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Invite ID"
+// @Success 200 {object} response.Envelope
+// @Router /invites/{id} [delete]
+func (h *InviteHandler) Revoke(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid invite id", nil)
+		return
+	}
+
+	if err := h.inviteSvc.Revoke(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "invite revoked"})
+}
+
+func (h *InviteHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "invite not found")
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		response.Forbidden(c, "invite quota used up")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you cannot manage this invite")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxWebhookBody = 1 << 20 // 1 MiB
+
+// MailWebhookHandler receives bounce and complaint callbacks from email providers.
+type MailWebhookHandler struct {
+	mailSvc *service.MailService
+	secret  string
+	log     *logrus.Logger
+}
+
+// NewMailWebhookHandler creates a MailWebhookHandler. An empty secret disables the endpoint.
+func NewMailWebhookHandler(mailSvc *service.MailService, secret string, log *logrus.Logger) *MailWebhookHandler {
+	return &MailWebhookHandler{mailSvc: mailSvc, secret: secret, log: log}
+}
+
+// Bounce godoc
+// @Summary Receive provider bounce/complaint notifications
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param provider path string true "Provider (sendgrid|mailgun|ses)"
+// @Param token query string true "Shared webhook secret"
+// @Success 200 {object} response.Envelope
+// @Router /webhooks/mail/{provider} [post]
+func (h *MailWebhookHandler) Bounce(c *gin.Context) {
+	token := c.Query("token")
+	if h.secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) != 1 {
+		response.Unauthorized(c, "invalid webhook token")
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBody))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", "could not read request body", nil)
+		return
+	}
+
+	provider := c.Param("provider")
+	bounces, err := mailer.ParseBounces(provider, body)
+	if err != nil {
+		var confirm *mailer.SubscriptionConfirmation
+		if errors.As(err, &confirm) {
+			h.log.WithField("subscribe_url", confirm.URL).Warn("SNS subscription confirmation requested for bounce webhook")
+			response.OK(c, gin.H{"message": "subscription confirmation logged"})
+			return
+		}
+		response.BadRequest(c, "INVALID_PAYLOAD", err.Error(), nil)
+		return
+	}
+
+	if err := h.mailSvc.HandleBounces(c.Request.Context(), provider, bounces); err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	response.OK(c, gin.H{"processed": len(bounces)})
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ndjsonType is the media type of newline-delimited JSON.
+const ndjsonType = "application/x-ndjson"
+
+const (
+	// ndjsonFlushEvery is how many lines are written between flushes.
+	ndjsonFlushEvery = 100
+	// ndjsonWriteTimeout replaces the server's write timeout at every
+	// flush, so a stream may run long as long as it keeps moving.
+	ndjsonWriteTimeout = 30 * time.Second
+)
+
+// wantsNDJSON reports whether the client's Accept header asks for
+// newline-delimited JSON.
+func wantsNDJSON(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		if t, _, err := mime.ParseMediaType(strings.TrimSpace(part)); err == nil && t == ndjsonType {
+			return true
+		}
+	}
+	return false
+}
+
+// streamNDJSON responds with one JSON value per line, written by stream
+// through emit. A failure before the first line is answered as a normal
+// error response; once lines have gone out the status can no longer change,
+// so the stream ends with an {"error": ...} line instead.
+func streamNDJSON(c *gin.Context, stream func(emit func(v any) error) error) {
+	enc := json.NewEncoder(c.Writer)
+	rc := http.NewResponseController(c.Writer)
+	lines := 0
+	err := stream(func(v any) error {
+		if lines == 0 {
+			c.Header("Content-Type", ndjsonType)
+			c.Header("X-Content-Type-Options", "nosniff")
+			c.Status(http.StatusOK)
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		lines++
+		if lines%ndjsonFlushEvery == 0 {
+			_ = rc.SetWriteDeadline(time.Now().Add(ndjsonWriteTimeout))
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && lines == 0:
+		response.InternalError(c)
+	case err != nil:
+		_ = enc.Encode(gin.H{"error": response.ErrorBody{Code: "STREAM_FAILED", Message: "the stream was cut short"}})
+	case lines == 0:
+		c.Header("Content-Type", ndjsonType)
+		c.Status(http.StatusOK)
+	}
+	c.Writer.Flush()
+}
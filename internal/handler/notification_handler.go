@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationHandler exposes in-app notification endpoints.
+type NotificationHandler struct {
+	notificationSvc *service.NotificationService
+}
+
+// NewNotificationHandler creates a NotificationHandler.
+func NewNotificationHandler(notificationSvc *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{notificationSvc: notificationSvc}
+}
+
+// List godoc
+// @Summary List notifications
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.Notification}
+// @Router /notifications [get]
+func (h *NotificationHandler) List(c *gin.Context) {
+	pag := pagination.FromContext(c)
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, total, err := h.notificationSvc.List(
+		c.Request.Context(), middleware.CurrentUserID(c), unreadOnly, pag.Page, pag.Limit,
+	)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	response.OKPaginated(c, notifications, pag.Page, pag.Limit, total)
+}
+
+// UnreadCount godoc
+// @Summary Count unread notifications
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /notifications/unread-count [get]
+func (h *NotificationHandler) UnreadCount(c *gin.Context) {
+	count, err := h.notificationSvc.UnreadCount(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, gin.H{"unread": count})
+}
+
+// MarkRead godoc
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Notification UUID"
+// @Success 200 {object} response.Envelope
+// @Router /notifications/{id}/read [post]
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid notification id", nil)
+		return
+	}
+
+	if err := h.notificationSvc.MarkRead(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "notification marked as read"})
+}
+
+// MarkAllRead godoc
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope
+// @Router /notifications/read-all [post]
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	if err := h.notificationSvc.MarkAllRead(c.Request.Context(), middleware.CurrentUserID(c)); err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, gin.H{"message": "all notifications marked as read"})
+}
+
+// Snooze godoc
+// @Summary Snooze a notification
+// @Description Hides the notification until the given time, then resurfaces it as unread.
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Notification UUID"
+// @Param until query string true "RFC 3339 timestamp, e.g. 2026-01-02T09:00:00+07:00"
+// @Success 200 {object} response.Envelope
+// @Failure 400 {object} response.Envelope
+// @Router /notifications/{id}/snooze [post]
+func (h *NotificationHandler) Snooze(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid notification id", nil)
+		return
+	}
+
+	until, err := time.Parse(time.RFC3339, c.Query("until"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_UNTIL", "until must be an RFC 3339 timestamp", nil)
+		return
+	}
+
+	if err := h.notificationSvc.Snooze(c.Request.Context(), id, middleware.CurrentUserID(c), until); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "notification snoozed", "snoozed_until": until.UTC()})
+}
+
+// GetQuietHours godoc
+// @Summary Get the do-not-disturb schedule
+// @Tags notifications
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.QuietHours}
+// @Router /notifications/quiet-hours [get]
+func (h *NotificationHandler) GetQuietHours(c *gin.Context) {
+	q, err := h.notificationSvc.GetQuietHours(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, q)
+}
+
+// UpdateQuietHours godoc
+// @Summary Configure the do-not-disturb schedule
+// @Tags notifications
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.UpdateQuietHoursRequest true "Quiet hours payload"
+// @Success 200 {object} response.Envelope{data=domain.QuietHours}
+// @Failure 422 {object} response.Envelope
+// @Router /notifications/quiet-hours [put]
+func (h *NotificationHandler) UpdateQuietHours(c *gin.Context) {
+	var req domain.UpdateQuietHoursRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	q, err := h.notificationSvc.UpdateQuietHours(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, q)
+}
+
+func (h *NotificationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "notification not found")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
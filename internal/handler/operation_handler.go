@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OperationHandler exposes async operations, started by long endpoints when
+// the client sends Prefer: respond-async.
+type OperationHandler struct {
+	operationSvc *service.OperationService
+}
+
+// NewOperationHandler creates an OperationHandler.
+func NewOperationHandler(operationSvc *service.OperationService) *OperationHandler {
+	return &OperationHandler{operationSvc: operationSvc}
+}
+
+// Get godoc
+// @Summary Get an async operation
+// @Description Reports an operation's status and progress. Once it has succeeded, result holds what the synchronous endpoint would have returned, and has_file tells whether there is a file to download. Finished operations are kept for a day.
+// @Tags operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Operation UUID"
+// @Success 200 {object} response.Envelope{data=domain.Operation}
+// @Failure 404 {object} response.Envelope
+// @Router /operations/{id} [get]
+func (h *OperationHandler) Get(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid operation id", nil)
+		return
+	}
+
+	op, err := h.operationSvc.Get(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, op)
+}
+
+// File godoc
+// @Summary Download an async operation's file
+// @Tags operations
+// @Security BearerAuth
+// @Produce octet-stream
+// @Param id path string true "Operation UUID"
+// @Success 200 {file} file
+// @Failure 404 {object} response.Envelope "Unknown operation, or no file yet"
+// @Router /operations/{id}/file [get]
+func (h *OperationHandler) File(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid operation id", nil)
+		return
+	}
+
+	file, err := h.operationSvc.File(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// Cancel godoc
+// @Summary Cancel an async operation
+// @Description A pending operation is cancelled at once; a running one stops within a few seconds, keeping whatever it has already written. Cancelling a finished operation has no effect.
+// @Tags operations
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Operation UUID"
+// @Success 200 {object} response.Envelope{data=domain.Operation}
+// @Failure 404 {object} response.Envelope
+// @Router /operations/{id}/cancel [post]
+func (h *OperationHandler) Cancel(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid operation id", nil)
+		return
+	}
+
+	op, err := h.operationSvc.Cancel(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, op)
+}
+
+func (h *OperationHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "operation not found")
+	default:
+		response.InternalError(c)
+	}
+}
+
+// prefersAsync reports whether the client asked, with Prefer: respond-async
+// (RFC 7240), for the request to be run as an async operation.
+func prefersAsync(c *gin.Context) bool {
+	for _, header := range c.Request.Header.Values("Prefer") {
+		for _, pref := range strings.Split(header, ",") {
+			if strings.EqualFold(strings.TrimSpace(pref), "respond-async") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// startOperation starts an async operation for the current user and responds
+// with 202 and its location, or with the error.
+func startOperation(c *gin.Context, operationSvc *service.OperationService, kind string, payload any) {
+	op, err := operationSvc.Start(c.Request.Context(), middleware.CurrentUserID(c), kind, payload)
+	if err != nil {
+		if errors.Is(err, domain.ErrUnavailable) {
+			response.ServiceUnavailable(c, "too many operations are queued; try again later")
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+	c.Header("Location", operationLocation(op.ID))
+	c.Header("Preference-Applied", "respond-async")
+	response.Accepted(c, op)
+}
+
+func operationLocation(id uuid.UUID) string {
+	return "/api/v1/operations/" + id.String()
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// PersonalAccessTokenHandler exposes personal access token endpoints.
+type PersonalAccessTokenHandler struct {
+	tokenSvc *service.PersonalAccessTokenService
+}
+
+// NewPersonalAccessTokenHandler creates a PersonalAccessTokenHandler.
+func NewPersonalAccessTokenHandler(tokenSvc *service.PersonalAccessTokenService) *PersonalAccessTokenHandler {
+	return &PersonalAccessTokenHandler{tokenSvc: tokenSvc}
+}
+
+// Authenticate resolves a personal access token for middleware.Auth.
+func (h *PersonalAccessTokenHandler) Authenticate(ctx context.Context, token string) (*domain.PersonalAccessToken, error) {
+	return h.tokenSvc.Authenticate(ctx, token)
+}
+
+// Create godoc
+// @Summary Create a personal access token
+// @Description Returns the token once; only a digest is stored. Send it as "Authorization: Bearer tdp_..." on task and analytics endpoints its scopes cover. At most 50 usable tokens per user.
+// @Tags tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreatePersonalAccessTokenRequest true "Token name, scopes and lifetime"
+// @Success 201 {object} response.Envelope{data=domain.CreatedPersonalAccessToken}
+// @Failure 403 {object} response.Envelope "Token limit reached"
+// @Router /me/tokens [post]
+func (h *PersonalAccessTokenHandler) Create(c *gin.Context) {
+	var req domain.CreatePersonalAccessTokenRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	token, err := h.tokenSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, token)
+}
+
+// List godoc
+// @Summary List my personal access tokens
+// @Tags tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.PersonalAccessToken}
+// @Router /me/tokens [get]
+func (h *PersonalAccessTokenHandler) List(c *gin.Context) {
+	tokens, err := h.tokenSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tokens)
+}
+
+// Revoke godoc
+// @Summary Revoke a personal access token
+// @Description Requests made with the token are refused from now on; it stays listed as revoked.
+// @Tags tokens
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Token ID"
+// @Success 200 {object} response.Envelope
+// @Failure 404 {object} response.Envelope
+// @Router /me/tokens/{id} [delete]
+func (h *PersonalAccessTokenHandler) Revoke(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid token id", nil)
+		return
+	}
+
+	if err := h.tokenSvc.Revoke(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "token revoked"})
+}
+
+func (h *PersonalAccessTokenHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "token not found")
+	case errors.Is(err, domain.ErrQuotaExceeded):
+		response.Forbidden(c, "personal access token limit reached; revoke one first")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -1,7 +1,14 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/galihaleanda/todo-app/internal/middleware"
@@ -9,16 +16,21 @@ import (
 	"github.com/galihaleanda/todo-app/internal/validator"
 	"github.com/galihaleanda/todo-app/pkg/response"
 	"github.com/gin-gonic/gin"
+	"gopkg.in/yaml.v3"
 )
 
+// maxImportBytes caps the size of an uploaded project bundle.
+const maxImportBytes = 2 << 20
+
 // ProjectHandler exposes project CRUD endpoints.
 type ProjectHandler struct {
-	projectSvc *service.ProjectService
+	projectSvc  *service.ProjectService
+	transferSvc *service.ProjectTransferService
 }
 
 // NewProjectHandler creates a ProjectHandler.
-func NewProjectHandler(projectSvc *service.ProjectService) *ProjectHandler {
-	return &ProjectHandler{projectSvc: projectSvc}
+func NewProjectHandler(projectSvc *service.ProjectService, transferSvc *service.ProjectTransferService) *ProjectHandler {
+	return &ProjectHandler{projectSvc: projectSvc, transferSvc: transferSvc}
 }
 
 // Create godoc
@@ -54,15 +66,28 @@ func (h *ProjectHandler) Create(c *gin.Context) {
 // @Tags projects
 // @Security BearerAuth
 // @Produce json
+// @Param fields query string false "Comma-separated fields to return, e.g. id,name,color (default all; id is always included)"
+// @Param archived query bool false "List archived projects instead of the others"
 // @Success 200 {object} response.Envelope{data=[]domain.Project}
+// @Failure 400 {object} response.Envelope "Unknown field"
 // @Router /projects [get]
 func (h *ProjectHandler) List(c *gin.Context) {
-	projects, err := h.projectSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	fields, ok := parseFields(c, domain.ProjectFieldNames)
+	if !ok {
+		return
+	}
+
+	projects, err := h.projectSvc.ListFields(c.Request.Context(), middleware.CurrentUserID(c), fields, c.Query("archived") == "true")
 	if err != nil {
 		response.InternalError(c)
 		return
 	}
-	response.OK(c, projects)
+	data, err := sparse(projects, fields)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, data)
 }
 
 // GetByID godoc
@@ -72,6 +97,7 @@ func (h *ProjectHandler) List(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Project UUID"
 // @Success 200 {object} response.Envelope{data=domain.Project}
+// @Header 200 {string} ETag "The project's version, for If-Match"
 // @Router /projects/{id} [get]
 func (h *ProjectHandler) GetByID(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -85,10 +111,35 @@ func (h *ProjectHandler) GetByID(c *gin.Context) {
 		h.handleError(c, err)
 		return
 	}
+	setETag(c, project.Version)
 
 	response.OK(c, project)
 }
 
+// Stats godoc
+// @Summary Project statistics
+// @Description Counts the project's live tasks by status and priority, with its completion rate, overdue tasks and average time to complete. Archived tasks are left out.
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Success 200 {object} response.Envelope{data=domain.ProjectStats}
+// @Router /projects/{id}/stats [get]
+func (h *ProjectHandler) Stats(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+
+	stats, err := h.projectSvc.Stats(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, stats)
+}
+
 // Update godoc
 // @Summary Update a project
 // @Tags projects
@@ -97,7 +148,10 @@ func (h *ProjectHandler) GetByID(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Project UUID"
 // @Param body body domain.UpdateProjectRequest true "Update payload"
+// @Param If-Match header string false "ETag from a previous read; the update is rejected if the project has changed since"
 // @Success 200 {object} response.Envelope{data=domain.Project}
+// @Header 200 {string} ETag "The updated project's version"
+// @Failure 412 {object} response.Envelope "Project has changed since the If-Match version"
 // @Router /projects/{id} [patch]
 func (h *ProjectHandler) Update(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -105,6 +159,10 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
 		return
 	}
+	ifVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 
 	var req domain.UpdateProjectRequest
 	if errs, err := validator.BindAndValidate(c, &req); err != nil {
@@ -114,12 +172,14 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 		response.UnprocessableEntity(c, errs)
 		return
 	}
+	req.IfVersion = ifVersion
 
 	project, err := h.projectSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	setETag(c, project.Version)
 
 	response.OK(c, project)
 }
@@ -130,7 +190,11 @@ func (h *ProjectHandler) Update(c *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Project UUID"
+// @Param If-Match header string false "ETag from a previous read; the delete is rejected if the project has changed since"
+// @Param strategy query string false "What happens to the project's tasks: inbox (default) keeps them without a project, cascade deletes them too, block refuses while there are any" Enums(inbox, cascade, block)
 // @Success 200 {object} response.Envelope
+// @Failure 409 {object} response.Envelope "strategy=block and the project has tasks"
+// @Failure 412 {object} response.Envelope "Project has changed since the If-Match version"
 // @Router /projects/{id} [delete]
 func (h *ProjectHandler) Delete(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -138,13 +202,457 @@ func (h *ProjectHandler) Delete(c *gin.Context) {
 		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
 		return
 	}
+	ifVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
+
+	strategy := domain.ProjectDeleteStrategy(c.Query("strategy"))
+	tasks, err := h.projectSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c), ifVersion, strategy)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "project deleted", "tasks_affected": tasks})
+}
+
+// Duplicate godoc
+// @Summary Duplicate a project
+// @Description Creates a copy of a project you can access, owned by you. With include_tasks its open tasks are copied too, as todo with new ids, keeping subtasks, dependencies between copied tasks and your tags; shift_days moves their due, start and scheduled dates. Everything is copied in one transaction.
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Param body body domain.DuplicateProjectRequest false "Duplication options"
+// @Success 201 {object} response.Envelope{data=domain.ProjectDuplicateResult}
+// @Failure 404 {object} response.Envelope
+// @Router /projects/{id}/duplicate [post]
+func (h *ProjectHandler) Duplicate(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+	var req domain.DuplicateProjectRequest
+	if c.Request.ContentLength != 0 {
+		if errs, err := validator.BindAndValidate(c, &req); err != nil {
+			response.InternalError(c)
+			return
+		} else if errs != nil {
+			response.UnprocessableEntity(c, errs)
+			return
+		}
+	}
+
+	result, err := h.projectSvc.Duplicate(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, result)
+}
+
+// Export godoc
+// @Summary Export a project as YAML or JSON
+// @Description Downloads the project with its tasks, subtasks, tags and dependencies in the bundle schema accepted by /projects/import. Tasks in the trash are left out.
+// @Tags projects
+// @Security BearerAuth
+// @Produce application/yaml
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Param format query string false "Export format: yaml or json" default(yaml)
+// @Success 200 {object} domain.ProjectBundle
+// @Router /projects/{id}/export [get]
+func (h *ProjectHandler) Export(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+	format := c.DefaultQuery("format", "yaml")
+	if format != "yaml" && format != "json" {
+		response.BadRequest(c, "INVALID_PARAM", "unsupported format", validator.Invalid("format", "must be one of: yaml, json"))
+		return
+	}
+
+	bundle, err := h.transferSvc.Export(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	var out []byte
+	contentType := "application/yaml; charset=utf-8"
+	if format == "json" {
+		out, err = json.MarshalIndent(bundle, "", "  ")
+		contentType = "application/json; charset=utf-8"
+	} else {
+		out, err = yaml.Marshal(bundle)
+	}
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, exportFilename(bundle.Project.Name), format))
+	c.Data(http.StatusOK, contentType, out)
+}
+
+// Print godoc
+// @Summary Print a project's open tasks
+// @Description A PDF checklist of the project's open tasks, grouped by status, each with a box to tick and its due date in the user's time zone.
+// @Tags projects
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path string true "Project UUID"
+// @Param format query string false "Only pdf is supported" default(pdf)
+// @Success 200 {file} file
+// @Failure 404 {object} response.Envelope
+// @Router /projects/{id}/print [get]
+func (h *ProjectHandler) Print(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+	if format := c.DefaultQuery("format", "pdf"); format != "pdf" {
+		response.BadRequest(c, "INVALID_PARAM", "unsupported format", validator.Invalid("format", "must be one of: pdf"))
+		return
+	}
 
-	if err := h.projectSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+	project, out, err := h.transferSvc.Print(c.Request.Context(), id, middleware.CurrentUserID(c), time.Now())
+	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	response.OK(c, gin.H{"message": "project deleted"})
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s.pdf"`, exportFilename(project.Name)))
+	c.Data(http.StatusOK, "application/pdf", out)
+}
+
+// Import godoc
+// @Summary Import a project from YAML or JSON
+// @Description Creates a new project from a bundle produced by /projects/{id}/export, in either format. Tags are matched to existing ones by name or created.
+// @Tags projects
+// @Security BearerAuth
+// @Accept application/yaml
+// @Accept json
+// @Produce json
+// @Param body body domain.ProjectBundle true "Project bundle"
+// @Success 201 {object} response.Envelope{data=domain.ProjectImportResult}
+// @Failure 400 {object} response.Envelope "Malformed YAML or broken references"
+// @Router /projects/import [post]
+func (h *ProjectHandler) Import(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", fmt.Sprintf("bundle must be at most %d bytes", maxImportBytes), nil)
+		return
+	}
+	if !checkDocument(c, service.DocumentProjectBundle, body, true) {
+		return
+	}
+
+	// JSON is YAML too, so a JSON export decodes the same way.
+	var bundle domain.ProjectBundle
+	dec := yaml.NewDecoder(bytes.NewReader(body))
+	dec.KnownFields(true)
+	if err := dec.Decode(&bundle); err != nil {
+		response.BadRequest(c, "INVALID_YAML", "invalid YAML: "+err.Error(), nil)
+		return
+	}
+	if errs, err := validator.Validate(&bundle); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	result, err := h.transferSvc.Import(c.Request.Context(), middleware.CurrentUserID(c), &bundle)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, result)
+}
+
+// Sync godoc
+// @Summary Converge a project on a desired state
+// @Description Diffs the full desired state of the project with this name against what is stored, then creates, updates and archives tasks to match. The project is created if missing. Send JSON, or YAML with a yaml content type.
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param name path string true "Project name"
+// @Param dry_run query bool false "Compute the diff without applying it"
+// @Param body body domain.DesiredProject true "Desired project state"
+// @Success 200 {object} response.Envelope{data=domain.ProjectSyncResult}
+// @Failure 409 {object} response.Envelope "Name matches several projects, or a blocked task would be completed"
+// @Router /declarative/projects/{name} [put]
+func (h *ProjectHandler) Sync(c *gin.Context) {
+	name := c.Param("name")
+	if n := len([]rune(name)); n == 0 || n > 100 {
+		response.BadRequest(c, "INVALID_PARAM", "invalid project name", validator.Invalid("name", "must be between 1 and 100 characters"))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", fmt.Sprintf("document must be at most %d bytes", maxImportBytes), nil)
+		return
+	}
+	isYAML := strings.Contains(c.ContentType(), "yaml")
+	if !checkDocument(c, service.DocumentDesiredProject, body, isYAML) {
+		return
+	}
+
+	var desired domain.DesiredProject
+	if isYAML {
+		dec := yaml.NewDecoder(bytes.NewReader(body))
+		dec.KnownFields(true)
+		if err := dec.Decode(&desired); err != nil {
+			response.BadRequest(c, "INVALID_YAML", "invalid YAML: "+err.Error(), nil)
+			return
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&desired); err != nil {
+			response.BadRequest(c, "INVALID_BODY", "invalid JSON: "+err.Error(), nil)
+			return
+		}
+	}
+	if errs, err := validator.Validate(&desired); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	result, err := h.transferSvc.Sync(c.Request.Context(), middleware.CurrentUserID(c), name, &desired, isDryRun(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, result)
+}
+
+// Schema godoc
+// @Summary JSON Schema of an uploaded document
+// @Description The schema POST /projects/import (project_bundle) and PUT /declarative/projects/{name} (desired_project) check documents against. YAML documents are checked as the equivalent JSON.
+// @Tags projects
+// @Produce json
+// @Param name path string true "Document" Enums(project_bundle, desired_project)
+// @Success 200 {object} response.Envelope "JSON Schema document"
+// @Router /schemas/{name} [get]
+func (h *ProjectHandler) Schema(c *gin.Context) {
+	schema, err := service.DocumentSchema(c.Param("name"))
+	if err != nil {
+		response.NotFound(c, "no schema with this name")
+		return
+	}
+	response.OK(c, schema)
+}
+
+// Validate godoc
+// @Summary Check a document before importing it
+// @Description Checks a project bundle or desired project state against its schema without importing anything, listing every violation with the JSON Pointer of the value at fault. Send JSON, or YAML with a yaml content type.
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Accept application/yaml
+// @Produce json
+// @Param schema query string true "Document" Enums(project_bundle, desired_project)
+// @Param body body string true "Document"
+// @Success 200 {object} response.Envelope{data=service.DocumentValidation}
+// @Failure 400 {object} response.Envelope "Unknown schema, or a document that does not parse"
+// @Router /validate [post]
+func (h *ProjectHandler) Validate(c *gin.Context) {
+	kind := c.Query("schema")
+	if _, err := service.DocumentSchema(kind); err != nil {
+		response.BadRequest(c, "INVALID_PARAM", "unknown schema", validator.Invalid("schema", validator.EnumMessage(service.DocumentKinds)))
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", fmt.Sprintf("document must be at most %d bytes", maxImportBytes), nil)
+		return
+	}
+	doc, err := decodeDocument(body, strings.Contains(c.ContentType(), "yaml"))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", "document does not parse: "+err.Error(), nil)
+		return
+	}
+
+	result, err := service.ValidateDocument(kind, doc)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, result)
+}
+
+// exportFilename turns a project name into a safe download name.
+func exportFilename(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, name)
+	slug = strings.Trim(slug, "-")
+	if slug == "" {
+		return "project"
+	}
+	return slug
+}
+
+// Archive godoc
+// @Summary Archive a project
+// @Description Sets the project aside without deleting it: it and its tasks leave default lists, smart views and analytics, but still open by id and list with ?archived=true. Only the owner may archive. Archiving an archived project is a no-op.
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Success 200 {object} response.Envelope{data=domain.Project}
+// @Router /projects/{id}/archive [post]
+func (h *ProjectHandler) Archive(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+
+	project, err := h.projectSvc.Archive(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, project)
+}
+
+// Unarchive godoc
+// @Summary Unarchive a project
+// @Description Brings the project and its tasks back into lists and analytics.
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Success 200 {object} response.Envelope{data=domain.Project}
+// @Router /projects/{id}/unarchive [post]
+func (h *ProjectHandler) Unarchive(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+
+	project, err := h.projectSvc.Unarchive(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, project)
+}
+
+// ListMembers godoc
+// @Summary List who has access to a project
+// @Description The owner first, then the users the project is shared with, in the order they joined.
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Success 200 {object} response.Envelope{data=[]domain.ProjectMember}
+// @Router /projects/{id}/members [get]
+func (h *ProjectHandler) ListMembers(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+
+	members, err := h.projectSvc.ListMembers(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, members)
+}
+
+// AddMember godoc
+// @Summary Share a project with a user
+// @Description Gives the user registered under the email access to the project and every task in it. Only the owner may share a project.
+// @Tags projects
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Param body body domain.AddProjectMemberRequest true "Member"
+// @Success 201 {object} response.Envelope{data=domain.ProjectMember}
+// @Failure 400 {object} response.Envelope "No user has the email, or the project has too many members"
+// @Failure 409 {object} response.Envelope "The user is already a member"
+// @Router /projects/{id}/members [post]
+func (h *ProjectHandler) AddMember(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+
+	var req domain.AddProjectMemberRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	member, err := h.projectSvc.AddMember(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		response.Conflict(c, "the user is already a member of this project")
+		return
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, member)
+}
+
+// RemoveMember godoc
+// @Summary Stop sharing a project with a user
+// @Description The owner may remove any member; a member may remove themselves to leave the project. Tasks they created stay in it.
+// @Tags projects
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Project UUID"
+// @Param userID path string true "Member's user UUID"
+// @Success 200 {object} response.Envelope
+// @Router /projects/{id}/members/{userID} [delete]
+func (h *ProjectHandler) RemoveMember(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid project id", nil)
+		return
+	}
+	memberID, err := parseUUID(c, "userID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+		return
+	}
+
+	if err := h.projectSvc.RemoveMember(c.Request.Context(), id, memberID, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "member removed"})
 }
 
 func (h *ProjectHandler) handleError(c *gin.Context, err error) {
@@ -153,6 +661,18 @@ func (h *ProjectHandler) handleError(c *gin.Context, err error) {
 		response.NotFound(c, "project not found")
 	case errors.Is(err, domain.ErrForbidden):
 		response.Forbidden(c, "you do not have access to this project")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "more than one project has this name")
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		response.PreconditionFailed(c, "the project has changed since it was read; fetch it again and retry")
+	case errors.Is(err, domain.ErrTaskBlocked):
+		response.Conflict(c, "task cannot be completed while it is blocked by open tasks")
+	case errors.Is(err, domain.ErrProjectNotEmpty):
+		response.Conflict(c, "the project still has tasks; move or delete them, or pick another strategy")
+	case errors.Is(err, domain.ErrFeatureDisabled):
+		response.ServiceUnavailable(c, "project sharing is not enabled on this server")
 	default:
 		response.InternalError(c)
 	}
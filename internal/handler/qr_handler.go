@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// QRHandler renders QR codes for clients.
+type QRHandler struct {
+	qrSvc *service.QRService
+}
+
+// NewQRHandler creates a QRHandler.
+func NewQRHandler(qrSvc *service.QRService) *QRHandler {
+	return &QRHandler{qrSvc: qrSvc}
+}
+
+// Render godoc
+// @Summary Render text as a QR code
+// @Description A PNG QR code of up to 213 bytes of text: share links, device pairing codes, otpauth:// URIs for authenticator apps.
+// @Tags qr
+// @Security BearerAuth
+// @Accept json
+// @Produce image/png
+// @Param body body domain.QRRequest true "Text to encode"
+// @Success 200 {file} file
+// @Failure 422 {object} response.Envelope
+// @Router /qr [post]
+func (h *QRHandler) Render(c *gin.Context) {
+	var req domain.QRRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	out, err := h.qrSvc.Render(&req)
+	if err != nil {
+		if errors.Is(err, domain.ErrValidation) {
+			response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+	c.Header("Cache-Control", "private, no-store")
+	c.Data(http.StatusOK, "image/png", out)
+}
+
+// parseQRScale reads the optional scale query parameter, writing a 400 and
+// returning false when it is out of range. Zero means the default.
+func parseQRScale(c *gin.Context) (int, bool) {
+	v := c.Query("scale")
+	if v == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 || n > 20 {
+		response.BadRequest(c, "INVALID_PARAM", "scale must be between 1 and 20", nil)
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RankingHandler exposes the user's task list ranker choice.
+type RankingHandler struct {
+	rankingSvc *service.RankingService
+}
+
+// NewRankingHandler creates a RankingHandler.
+func NewRankingHandler(rankingSvc *service.RankingService) *RankingHandler {
+	return &RankingHandler{rankingSvc: rankingSvc}
+}
+
+// Get godoc
+// @Summary Get the ranker ordering the task list and why it was chosen
+// @Tags ranking
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.RankingSetting}
+// @Router /me/ranking [get]
+func (h *RankingHandler) Get(c *gin.Context) {
+	setting, err := h.rankingSvc.Resolve(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, setting)
+}
+
+// Update godoc
+// @Summary Choose the task list ranker (null returns to the default or experiment)
+// @Tags ranking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.SetRankingRequest true "Ranker choice"
+// @Success 200 {object} response.Envelope{data=domain.RankingSetting}
+// @Failure 400 {object} response.Envelope
+// @Router /me/ranking [put]
+func (h *RankingHandler) Update(c *gin.Context) {
+	var req domain.SetRankingRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	setting, err := h.rankingSvc.SetRanker(c.Request.Context(), middleware.CurrentUserID(c), req.Ranker)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, setting)
+}
+
+func (h *RankingHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "user not found")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
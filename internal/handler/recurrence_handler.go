@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// RecurrenceHandler exposes the completion history of recurring tasks.
+type RecurrenceHandler struct {
+	recurrenceSvc *service.RecurrenceService
+}
+
+// NewRecurrenceHandler creates a RecurrenceHandler.
+func NewRecurrenceHandler(recurrenceSvc *service.RecurrenceService) *RecurrenceHandler {
+	return &RecurrenceHandler{recurrenceSvc: recurrenceSvc}
+}
+
+// Occurrences godoc
+// @Summary List a recurring task's completed occurrences
+// @Description Each completion of a recurring task, most recent first, with whether it was done by its due date. stats covers all occurrences, not just the page.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=domain.TaskOccurrenceHistory}
+// @Failure 404 {object} response.Envelope
+// @Router /tasks/{id}/occurrences [get]
+func (h *RecurrenceHandler) Occurrences(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	pag := pagination.FromContext(c)
+
+	history, total, err := h.recurrenceSvc.Occurrences(c.Request.Context(), id, middleware.CurrentUserID(c), pag.Page, pag.Limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OKPaginated(c, history, pag.Page, pag.Limit, total)
+}
+
+func (h *RecurrenceHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ReferralHandler exposes the current user's referrals.
+type ReferralHandler struct {
+	referralSvc *service.ReferralService
+}
+
+// NewReferralHandler creates a ReferralHandler.
+func NewReferralHandler(referralSvc *service.ReferralService) *ReferralHandler {
+	return &ReferralHandler{referralSvc: referralSvc}
+}
+
+// Get godoc
+// @Summary Get my referrals
+// @Description Returns the user's referral code and the signups attributed to it, with counts by status.
+// @Tags referrals
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.ReferralSummary}
+// @Router /me/referrals [get]
+func (h *ReferralHandler) Get(c *gin.Context) {
+	summary, err := h.referralSvc.Summary(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, summary)
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReminderHandler exposes task reminder endpoints.
+type ReminderHandler struct {
+	reminderSvc *service.ReminderService
+}
+
+// NewReminderHandler creates a ReminderHandler.
+func NewReminderHandler(reminderSvc *service.ReminderService) *ReminderHandler {
+	return &ReminderHandler{reminderSvc: reminderSvc}
+}
+
+// Create godoc
+// @Summary Add a reminder to a task
+// @Description Set either remind_at (an absolute time) or offset_minutes (how long before the due date).
+// @Tags reminders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.CreateReminderRequest true "Reminder"
+// @Success 201 {object} response.Envelope{data=domain.Reminder}
+// @Failure 400 {object} response.Envelope "Time in the past, no due date, or too many reminders"
+// @Router /tasks/{id}/reminders [post]
+func (h *ReminderHandler) Create(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.CreateReminderRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	reminder, err := h.reminderSvc.Create(c.Request.Context(), taskID, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, reminder)
+}
+
+// List godoc
+// @Summary List a task's reminders
+// @Tags reminders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Envelope{data=[]domain.Reminder}
+// @Router /tasks/{id}/reminders [get]
+func (h *ReminderHandler) List(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	reminders, err := h.reminderSvc.List(c.Request.Context(), taskID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, reminders)
+}
+
+// Dismiss godoc
+// @Summary Dismiss a reminder
+// @Description A pending reminder is cancelled; a fired one is marked as seen.
+// @Tags reminders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Success 200 {object} response.Envelope{data=domain.Reminder}
+// @Router /tasks/{id}/reminders/{reminderID}/dismiss [post]
+func (h *ReminderHandler) Dismiss(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	reminder, err := h.reminderSvc.Dismiss(c.Request.Context(), taskID, id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, reminder)
+}
+
+// Delete godoc
+// @Summary Delete a reminder
+// @Tags reminders
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param reminderID path string true "Reminder ID"
+// @Success 200 {object} response.Envelope
+// @Router /tasks/{id}/reminders/{reminderID} [delete]
+func (h *ReminderHandler) Delete(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.reminderSvc.Delete(c.Request.Context(), taskID, id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "reminder deleted"})
+}
+
+func (h *ReminderHandler) parseIDs(c *gin.Context) (taskID, id uuid.UUID, ok bool) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err = parseUUID(c, "reminderID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid reminder id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return taskID, id, true
+}
+
+func (h *ReminderHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
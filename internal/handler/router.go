@@ -1,8 +1,14 @@
 package handler
 
 import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/galihaleanda/todo-app/internal/middleware"
 	pkgjwt "github.com/galihaleanda/todo-app/pkg/jwt"
+	"github.com/galihaleanda/todo-app/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
@@ -10,34 +16,133 @@ import (
 // Router wires all handlers to gin routes.
 type Router struct {
 	auth      *AuthHandler
+	tokens    *PersonalAccessTokenHandler
+	invites   *InviteHandler
+	referrals *ReferralHandler
+	user      *UserHandler
 	task      *TaskHandler
+	breakdown *BreakdownHandler
+	exchange  *TaskExchangeHandler
+	recurring *RecurrenceHandler
+	escalate  *EscalationHandler
+	deps      *TaskDependencyHandler
+	files     *AttachmentHandler
+	reminders *ReminderHandler
+	links     *TaskLinkHandler
+	revisions *TaskRevisionHandler
 	project   *ProjectHandler
+	tag       *TagHandler
 	analytics *AnalyticsHandler
+	notify    *NotificationHandler
+	complete  *AutocompleteHandler
+	views     *SmartViewHandler
+	ranking   *RankingHandler
+	rules     *DueDateRuleHandler
+	calendar  *BusinessCalendarHandler
+	schedule  *ScheduleHandler
+	feeds     *CalendarFeedHandler
+	qr        *QRHandler
+	automate  *AutomationHandler
+	webhook   *WebhookHandler
+	ops       *OperationHandler
+	admin     *AdminHandler
+	changelog *ChangelogHandler
+	feedback  *FeedbackHandler
+	telemetry *TelemetryHandler
+	dev       *DevHandler
+	mailHook  *MailWebhookHandler
+	signup    gin.HandlerFunc
+	errLimit  gin.HandlerFunc
+	shed      gin.HandlerFunc
 	jwt       *pkgjwt.Manager
 	log       *logrus.Logger
 }
 
 // NewRouter creates a Router with all dependencies.
+// dev may be nil, in which case development-only routes are not registered.
+// signupLimit runs in front of registration to throttle it, and
+// telemetryLimit in front of client error reports. shed runs in front of
+// low-priority routes (analytics, exports) to turn them away under load.
 func NewRouter(
 	auth *AuthHandler,
+	tokens *PersonalAccessTokenHandler,
+	invites *InviteHandler,
+	referrals *ReferralHandler,
+	user *UserHandler,
 	task *TaskHandler,
+	breakdown *BreakdownHandler,
+	exchange *TaskExchangeHandler,
+	recurring *RecurrenceHandler,
+	escalate *EscalationHandler,
+	deps *TaskDependencyHandler,
+	files *AttachmentHandler,
+	reminders *ReminderHandler,
+	links *TaskLinkHandler,
+	revisions *TaskRevisionHandler,
 	project *ProjectHandler,
+	tag *TagHandler,
 	analytics *AnalyticsHandler,
+	notify *NotificationHandler,
+	complete *AutocompleteHandler,
+	views *SmartViewHandler,
+	ranking *RankingHandler,
+	rules *DueDateRuleHandler,
+	calendar *BusinessCalendarHandler,
+	schedule *ScheduleHandler,
+	feeds *CalendarFeedHandler,
+	qr *QRHandler,
+	automate *AutomationHandler,
+	webhook *WebhookHandler,
+	ops *OperationHandler,
+	admin *AdminHandler,
+	changelog *ChangelogHandler,
+	feedback *FeedbackHandler,
+	telemetry *TelemetryHandler,
+	dev *DevHandler,
+	mailHook *MailWebhookHandler,
+	signupLimit gin.HandlerFunc,
+	telemetryLimit gin.HandlerFunc,
+	shed gin.HandlerFunc,
 	jwt *pkgjwt.Manager,
 	log *logrus.Logger,
 ) *Router {
-	return &Router{auth: auth, task: task, project: project, analytics: analytics, jwt: jwt, log: log}
+	return &Router{
+		auth: auth, tokens: tokens, invites: invites, referrals: referrals, user: user, task: task, breakdown: breakdown, exchange: exchange, recurring: recurring, escalate: escalate, deps: deps, files: files, reminders: reminders, links: links, revisions: revisions, project: project, tag: tag, analytics: analytics, notify: notify,
+		complete: complete, views: views, ranking: ranking, rules: rules, calendar: calendar, schedule: schedule, feeds: feeds, qr: qr, automate: automate, webhook: webhook, ops: ops, admin: admin, changelog: changelog, feedback: feedback, telemetry: telemetry, dev: dev, mailHook: mailHook, signup: signupLimit, errLimit: telemetryLimit, shed: shed, jwt: jwt, log: log,
+	}
+}
+
+// module turns a route off while an admin has switched its module off.
+func (r *Router) module(name string) gin.HandlerFunc {
+	return middleware.RequireModule(name, r.admin.ModuleEnabled)
 }
 
-// Setup registers all routes and returns the gin engine.
-func (r *Router) Setup() *gin.Engine {
+// Setup registers all routes and returns the gin engine. Only peers in
+// trustedProxies may set the client IP through X-Forwarded-For; with none,
+// the rate limits key on the connecting address.
+func (r *Router) Setup(trustedProxies []string) (*gin.Engine, error) {
 	engine := gin.New()
+	engine.HandleMethodNotAllowed = true
+	if err := engine.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, fmt.Errorf("router: TRUSTED_PROXIES: %w", err)
+	}
 
-	// Global middleware
+	// Global middleware; Recovery goes first so a panic in any of the
+	// others still gets an error envelope.
 	engine.Use(middleware.Recovery(r.log))
+	engine.Use(middleware.RequestID())
 	engine.Use(middleware.RequestLogger(r.log))
 	engine.Use(middleware.CORS())
 
+	// Unknown routes and methods answer in the same envelope as every
+	// other error instead of gin's plain-text defaults.
+	engine.NoRoute(func(c *gin.Context) {
+		response.NotFound(c, "no route matches "+c.Request.URL.Path)
+	})
+	engine.NoMethod(func(c *gin.Context) {
+		response.MethodNotAllowed(c, c.Request.Method+" is not allowed on "+c.Request.URL.Path)
+	})
+
 	v1 := engine.Group("/api/v1")
 
 	// Health check — no auth required
@@ -48,25 +153,116 @@ func (r *Router) Setup() *gin.Engine {
 	// Auth routes — public
 	authGroup := v1.Group("/auth")
 	{
-		authGroup.POST("/register", r.auth.Register)
+		authGroup.POST("/register", r.signup, r.auth.Register)
 		authGroup.POST("/login", r.auth.Login)
+		authGroup.POST("/login/2fa", r.auth.LoginTwoFactor)
 		authGroup.POST("/refresh", r.auth.RefreshToken)
 	}
 
+	// Provider callbacks — authenticated by shared secret, not JWT
+	v1.POST("/webhooks/mail/:provider", r.mailHook.Bounce)
+
+	// Client error reports — public so crashes before sign-in are captured;
+	// a valid access token attributes them to the user
+	v1.POST("/telemetry/errors", r.errLimit, middleware.OptionalAuth(r.jwt), r.telemetry.ReportErrors)
+
+	// Public so receivers can fetch verification keys and the event contract
+	// without an account
+	v1.GET("/webhooks/meta", r.webhook.Meta)
+	v1.GET("/events/catalog", r.webhook.Catalog)
+	v1.GET("/workspace/branding", r.admin.Branding)
+	v1.GET("/schemas/:name", r.project.Schema)
+
+	// Calendar subscriptions — authenticated by the secret token in the URL
+	v1.GET("/calendar-feeds/:file", r.module(domain.ModuleCalendarFeeds), r.shed, r.feeds.Feed)
+
+	// Development-only tooling
+	if r.dev != nil {
+		dev := v1.Group("/dev")
+		{
+			dev.GET("/emails", r.dev.ListEmailTemplates)
+			dev.GET("/emails/:template", r.dev.PreviewEmail)
+		}
+	}
+
 	// Protected routes
 	protected := v1.Group("")
-	protected.Use(middleware.Auth(r.jwt))
+	protected.Use(middleware.Auth(r.jwt, r.tokens.Authenticate, tokenScope))
 	{
 		protected.POST("/auth/logout", r.auth.Logout)
 
+		// Two-factor enrollment
+		protected.POST("/auth/2fa/setup", r.auth.SetupTwoFactor)
+		protected.POST("/auth/2fa/verify", r.auth.VerifyTwoFactor)
+		protected.POST("/auth/2fa/disable", r.auth.DisableTwoFactor)
+
+		// Personal access tokens
+		tokens := protected.Group("/me/tokens")
+		{
+			tokens.POST("", r.tokens.Create)
+			tokens.GET("", r.tokens.List)
+			tokens.DELETE("/:id", r.tokens.Revoke)
+		}
+
+		// Signup invites
+		invites := protected.Group("/invites")
+		{
+			invites.POST("", r.invites.Create)
+			invites.GET("", r.invites.List)
+			invites.DELETE("/:id", r.invites.Revoke)
+		}
+
 		// Tasks
 		tasks := protected.Group("/tasks")
 		{
 			tasks.POST("", r.task.Create)
+			tasks.POST("/move", r.task.Move)
 			tasks.GET("", r.task.List)
+			tasks.GET("/fuzzy", r.task.Fuzzy)
+			tasks.GET("/recent", r.task.Recent)
+			tasks.GET("/scheduled", r.task.Scheduled)
+			tasks.GET("/views/today", r.task.Today)
+			tasks.GET("/views/upcoming", r.task.Upcoming)
+			tasks.GET("/views/now", r.task.Now)
+			tasks.GET("/export", r.shed, r.exchange.Export)
+			tasks.POST("/import", r.exchange.Import)
 			tasks.GET("/:id", r.task.GetByID)
 			tasks.PATCH("/:id", r.task.Update)
 			tasks.DELETE("/:id", r.task.Delete)
+			tasks.PATCH("/:id/position", r.task.Position)
+			tasks.POST("/:id/archive", r.task.Archive)
+			tasks.POST("/:id/unarchive", r.task.Unarchive)
+			tasks.POST("/:id/pin", r.task.Pin)
+			tasks.POST("/:id/unpin", r.task.Unpin)
+			tasks.POST("/:id/timer/start", r.task.StartTimer)
+			tasks.POST("/:id/timer/stop", r.task.StopTimer)
+			tasks.GET("/:id/time-entries", r.task.ListTimeEntries)
+			tasks.GET("/:id/activity", r.task.Activity)
+			tasks.GET("/:id/occurrences", r.recurring.Occurrences)
+			tasks.GET("/:id/escalations", r.escalate.Escalations)
+			tasks.GET("/:id/schedule", r.schedule.Task)
+			tasks.POST("/:id/breakdown", r.module(domain.ModuleBreakdown), r.breakdown.Propose)
+			tasks.POST("/:id/breakdown/accept", r.module(domain.ModuleBreakdown), r.breakdown.Accept)
+			tasks.GET("/:id/dependencies", r.deps.List)
+			tasks.POST("/:id/dependencies", r.deps.Add)
+			tasks.DELETE("/:id/dependencies/:blockerID", r.deps.Remove)
+			tasks.POST("/:id/attachments", r.module(domain.ModuleAttachments), r.files.Create)
+			tasks.GET("/:id/attachments", r.module(domain.ModuleAttachments), r.files.List)
+			tasks.POST("/:id/attachments/:attachmentID/complete", r.module(domain.ModuleAttachments), r.files.Complete)
+			tasks.GET("/:id/attachments/:attachmentID/download", r.module(domain.ModuleAttachments), r.files.Download)
+			tasks.DELETE("/:id/attachments/:attachmentID", r.module(domain.ModuleAttachments), r.files.Delete)
+			tasks.POST("/:id/reminders", r.reminders.Create)
+			tasks.GET("/:id/reminders", r.reminders.List)
+			tasks.POST("/:id/reminders/:reminderID/dismiss", r.reminders.Dismiss)
+			tasks.DELETE("/:id/reminders/:reminderID", r.reminders.Delete)
+			tasks.POST("/:id/links", r.links.Create)
+			tasks.GET("/:id/links", r.links.List)
+			tasks.PATCH("/:id/links/:linkID", r.links.Update)
+			tasks.DELETE("/:id/links/:linkID", r.links.Delete)
+			tasks.GET("/:id/revisions", r.revisions.List)
+			tasks.POST("/:id/revisions/:revisionID/restore", r.revisions.Restore)
+			tasks.GET("/:id/tags", r.tag.ListForTask)
+			tasks.PUT("/:id/tags", r.tag.SetForTask)
 		}
 
 		// Projects
@@ -74,18 +270,209 @@ func (r *Router) Setup() *gin.Engine {
 		{
 			projects.POST("", r.project.Create)
 			projects.GET("", r.project.List)
+			projects.POST("/import", r.project.Import)
 			projects.GET("/:id", r.project.GetByID)
 			projects.PATCH("/:id", r.project.Update)
 			projects.DELETE("/:id", r.project.Delete)
+			projects.GET("/:id/stats", r.shed, r.project.Stats)
+			projects.POST("/:id/archive", r.project.Archive)
+			projects.POST("/:id/unarchive", r.project.Unarchive)
+			projects.GET("/:id/members", r.module(domain.ModuleSharing), r.project.ListMembers)
+			projects.POST("/:id/members", r.module(domain.ModuleSharing), r.project.AddMember)
+			projects.DELETE("/:id/members/:userID", r.module(domain.ModuleSharing), r.project.RemoveMember)
+			projects.POST("/:id/duplicate", r.project.Duplicate)
+			projects.GET("/:id/export", r.shed, r.project.Export)
+			projects.GET("/:id/print", r.shed, r.project.Print)
+		}
+
+		// Check an import document without importing it
+		protected.POST("/validate", r.project.Validate)
+
+		// Declarative sync
+		declarative := protected.Group("/declarative")
+		{
+			declarative.PUT("/projects/:name", r.project.Sync)
+		}
+
+		// Tags
+		tags := protected.Group("/tags")
+		{
+			tags.POST("", r.tag.Create)
+			tags.GET("", r.tag.List)
+			tags.GET("/:id", r.tag.GetByID)
+			tags.PATCH("/:id", r.tag.Update)
+			tags.DELETE("/:id", r.tag.Delete)
 		}
 
-		// Analytics
+		// Analytics — shed first when the database is degraded
 		analytics := protected.Group("/analytics")
+		analytics.Use(r.shed)
 		{
 			analytics.GET("/dashboard", r.analytics.Dashboard)
 			analytics.GET("/daily", r.analytics.DailyStats)
+			analytics.GET("/estimates", r.analytics.Estimates)
+			analytics.POST("/ask", r.analytics.Ask)
+		}
+
+		// Built-in smart lists
+		views := protected.Group("/views")
+		{
+			views.GET("", r.views.List)
+			views.GET("/:key", r.views.Tasks)
+		}
+
+		// Lightweight counts polled by mobile clients
+		protected.GET("/me/badges", r.views.Badges)
+
+		// Created and completed events behind analytics, for export
+		protected.GET("/me/analytics/raw", r.shed, r.analytics.Raw)
+
+		// Signups attributed to the user's referral code
+		protected.GET("/me/referrals", r.referrals.Get)
+
+		// Time zone for due today and overdue
+		protected.GET("/me/timezone", r.user.GetTimezone)
+		protected.PUT("/me/timezone", r.user.SetTimezone)
+
+		// Task list ordering
+		protected.GET("/me/ranking", r.ranking.Get)
+		protected.PUT("/me/ranking", r.ranking.Update)
+
+		// Due-date defaulting rules
+		rules := protected.Group("/me/rules")
+		{
+			rules.POST("", r.rules.Create)
+			rules.GET("", r.rules.List)
+			rules.GET("/:id", r.rules.GetByID)
+			rules.PATCH("/:id", r.rules.Update)
+			rules.DELETE("/:id", r.rules.Delete)
+		}
+
+		// Working week, days off and holiday calendars
+		protected.GET("/me/calendar", r.calendar.Get)
+		protected.PUT("/me/calendar", r.calendar.Update)
+		protected.GET("/me/days-off", r.calendar.ListDaysOff)
+		protected.POST("/me/days-off", r.calendar.AddDayOff)
+		protected.DELETE("/me/days-off/:id", r.calendar.DeleteDayOff)
+		protected.GET("/holidays", r.calendar.Countries)
+		protected.GET("/me/calendar-feed", r.module(domain.ModuleCalendarFeeds), r.feeds.Get)
+		protected.GET("/me/calendar-feed/qr", r.module(domain.ModuleCalendarFeeds), r.feeds.QR)
+		protected.POST("/qr", r.qr.Render)
+		protected.POST("/me/calendar-feed", r.module(domain.ModuleCalendarFeeds), r.feeds.Rotate)
+		protected.DELETE("/me/calendar-feed", r.module(domain.ModuleCalendarFeeds), r.feeds.Revoke)
+		protected.GET("/holidays/:country", r.calendar.Holidays)
+		protected.GET("/me/workload", r.schedule.Workload)
+
+		// Automation rules
+		automations := protected.Group("/automations", r.module(domain.ModuleAutomations))
+		{
+			automations.POST("", r.automate.Create)
+			automations.GET("", r.automate.List)
+			automations.GET("/:id", r.automate.GetByID)
+			automations.PATCH("/:id", r.automate.Update)
+			automations.DELETE("/:id", r.automate.Delete)
+			automations.GET("/:id/executions", r.automate.Executions)
+			automations.POST("/:id/enable", r.automate.Enable)
+		}
+
+		// Picker suggestions
+		protected.GET("/autocomplete", r.complete.Complete)
+
+		// Notifications
+		notifications := protected.Group("/notifications")
+		{
+			notifications.GET("", r.notify.List)
+			notifications.GET("/unread-count", r.notify.UnreadCount)
+			notifications.POST("/read-all", r.notify.MarkAllRead)
+			notifications.GET("/quiet-hours", r.notify.GetQuietHours)
+			notifications.PUT("/quiet-hours", r.notify.UpdateQuietHours)
+			notifications.POST("/:id/read", r.notify.MarkRead)
+			notifications.POST("/:id/snooze", r.notify.Snooze)
+		}
+
+		// Outgoing webhooks
+		webhooks := protected.Group("/webhooks", r.module(domain.ModuleWebhooks))
+		{
+			webhooks.POST("", r.webhook.Create)
+			webhooks.GET("", r.webhook.List)
+			webhooks.GET("/:id", r.webhook.GetByID)
+			webhooks.PATCH("/:id", r.webhook.Update)
+			webhooks.DELETE("/:id", r.webhook.Delete)
+			webhooks.POST("/:id/enable", r.webhook.Enable)
+			webhooks.GET("/:id/deliveries", r.webhook.ListDeliveries)
+			webhooks.GET("/:id/deliveries/:deliveryID", r.webhook.GetDelivery)
+			webhooks.POST("/:id/deliveries/:deliveryID/redeliver", r.webhook.Redeliver)
+		}
+
+		// Product changelog
+		protected.GET("/changelog", r.changelog.List)
+		protected.POST("/changelog/seen", r.changelog.MarkSeen)
+
+		// Feedback and bug reports
+		protected.POST("/feedback", r.module(domain.ModuleFeedback), r.feedback.Create)
+
+		// Async operations
+		operations := protected.Group("/operations")
+		{
+			operations.GET("/:id", r.ops.Get)
+			operations.GET("/:id/file", r.ops.File)
+			operations.POST("/:id/cancel", r.ops.Cancel)
+		}
+
+		// Instance administration
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireAdmin(r.admin.IsAdmin))
+		{
+			admin.POST("/trash/purge", r.admin.PurgeTrash)
+			admin.DELETE("/users/:id", r.admin.DeleteUser)
+			admin.GET("/retention/report", r.admin.RetentionReport)
+			admin.POST("/retention/purge", r.admin.RunRetentionPurge)
+			admin.GET("/users/:id/retention", r.admin.GetUserRetention)
+			admin.PUT("/users/:id/retention", r.admin.SetUserRetention)
+			admin.DELETE("/users/:id/retention/:entity_type", r.admin.DeleteUserRetention)
+			admin.POST("/changelog", r.changelog.Create)
+			admin.GET("/feedback", r.feedback.List)
+			admin.GET("/feedback/:id", r.feedback.Get)
+			admin.GET("/telemetry/errors", r.telemetry.ListErrors)
+			admin.GET("/telemetry/errors/groups", r.telemetry.ErrorGroups)
+			admin.GET("/telemetry/errors/:id", r.telemetry.GetError)
+			admin.GET("/instance-settings", r.admin.InstanceSettings)
+			admin.PATCH("/instance-settings", r.admin.UpdateInstanceSettings)
+			admin.POST("/instance-settings/mail/test", r.admin.TestMail)
+			admin.PATCH("/branding", r.admin.UpdateBranding)
+			admin.GET("/metering/reconciliation", r.admin.MeteringReconciliation)
+			admin.POST("/branding/dkim/verify", r.admin.VerifyDKIM)
+			admin.GET("/load-shedding", r.admin.LoadShedding)
+			admin.PUT("/load-shedding", r.admin.SetLoadShedding)
+			admin.GET("/read-coalescing", r.admin.ReadCoalescing)
+			admin.GET("/backups", r.admin.ListBackups)
+			admin.POST("/backups", r.admin.CreateBackup)
+			admin.GET("/backups/:id", r.admin.GetBackup)
+			admin.DELETE("/backups/:id", r.admin.DeleteBackup)
+			admin.POST("/backups/:id/restore", r.admin.RestoreBackup)
 		}
 	}
 
-	return engine
+	return engine, nil
+}
+
+// tokenScope maps a route to the scope a personal access token needs on it.
+// Tokens are for scripts working with tasks and reading analytics, so
+// account, sharing, webhook, operation and admin routes take a login.
+func tokenScope(method, route string) string {
+	route = strings.TrimPrefix(route, "/api/v1")
+	read := method == http.MethodGet
+	switch {
+	case strings.HasPrefix(route, "/analytics/"), route == "/me/analytics/raw":
+		return domain.ScopeAnalyticsRead
+	case route == "/tasks", strings.HasPrefix(route, "/tasks/"):
+		if read {
+			return domain.ScopeTasksRead
+		}
+		return domain.ScopeTasksWrite
+	case read && (route == "/projects" || route == "/projects/:id" || route == "/tags" ||
+		route == "/views" || route == "/views/:key"):
+		return domain.ScopeTasksRead
+	}
+	return ""
 }
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// ScheduleHandler exposes overdue time, workload and slot suggestions
+// measured against the user's working hours or the clock.
+type ScheduleHandler struct {
+	scheduleSvc *service.ScheduleService
+}
+
+// NewScheduleHandler creates a ScheduleHandler.
+func NewScheduleHandler(scheduleSvc *service.ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{scheduleSvc: scheduleSvc}
+}
+
+// Task godoc
+// @Summary Where a task stands against the user's time
+// @Description Deadline, whether and how long it is overdue, hours left before it, and slots to fit the remaining estimate (1 hour without one) that finish by the deadline. Hours count only working time when the user's calendar has working_time_only set.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param slots query int false "Slots to suggest (default 3, max 10)"
+// @Success 200 {object} response.Envelope{data=domain.TaskSchedule}
+// @Failure 404 {object} response.Envelope
+// @Router /tasks/{id}/schedule [get]
+func (h *ScheduleHandler) Task(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	slots := 3
+	if v := c.Query("slots"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 10 {
+			response.BadRequest(c, "INVALID_PARAM", "slots must be between 1 and 10", nil)
+			return
+		}
+		slots = n
+	}
+
+	sched, err := h.scheduleSvc.Schedule(c.Request.Context(), id, middleware.CurrentUserID(c), time.Now(), slots)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, sched)
+}
+
+// Workload godoc
+// @Summary Compare the work due with the time available
+// @Description Remaining estimates of open tasks due by to, overdue ones included, against the hours between from and to. Hours count only working time when the user's calendar has working_time_only set.
+// @Tags calendar
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "RFC3339 start, default now"
+// @Param to query string false "RFC3339 end, default 7 days after from"
+// @Success 200 {object} response.Envelope{data=domain.Workload}
+// @Failure 400 {object} response.Envelope
+// @Router /me/workload [get]
+func (h *ScheduleHandler) Workload(c *gin.Context) {
+	from := time.Now()
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "from must be an RFC3339 timestamp", nil)
+			return
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, 7)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "to must be an RFC3339 timestamp", nil)
+			return
+		}
+		to = t
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		response.BadRequest(c, "INVALID_PARAM", "the window can span at most a year", nil)
+		return
+	}
+
+	w, err := h.scheduleSvc.Workload(c.Request.Context(), middleware.CurrentUserID(c), from, to)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, w)
+}
+
+func (h *ScheduleHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
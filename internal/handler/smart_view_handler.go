@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// SmartViewHandler exposes the built-in smart lists.
+type SmartViewHandler struct {
+	viewSvc *service.SmartViewService
+}
+
+// NewSmartViewHandler creates a SmartViewHandler.
+func NewSmartViewHandler(viewSvc *service.SmartViewService) *SmartViewHandler {
+	return &SmartViewHandler{viewSvc: viewSvc}
+}
+
+// List godoc
+// @Summary List built-in smart views with their task counts
+// @Tags views
+// @Security BearerAuth
+// @Produce json
+// @Param tz query string false "IANA time zone for day boundaries (default the user's time zone)"
+// @Success 200 {object} response.Envelope{data=[]domain.SmartView}
+// @Router /views [get]
+func (h *SmartViewHandler) List(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+
+	views, err := h.viewSvc.List(c.Request.Context(), middleware.CurrentUserID(c), loc)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	response.OK(c, views)
+}
+
+// Tasks godoc
+// @Summary List the tasks in a built-in smart view
+// @Tags views
+// @Security BearerAuth
+// @Produce json
+// @Param key path string true "today | upcoming | overdue | high_priority | recently_completed"
+// @Param tz query string false "IANA time zone for day boundaries (default the user's time zone)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.Task}
+// @Router /views/{key} [get]
+func (h *SmartViewHandler) Tasks(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+	pag := pagination.FromContext(c)
+
+	tasks, total, err := h.viewSvc.Tasks(c.Request.Context(), middleware.CurrentUserID(c), c.Param("key"), loc, pag.Page, pag.Limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			response.NotFound(c, "view not found")
+			return
+		}
+		response.InternalError(c)
+		return
+	}
+
+	response.OKPaginated(c, tasks, pag.Page, pag.Limit, total)
+}
+
+// Badges godoc
+// @Summary Get badge counts (due today, overdue, unread notifications, pending approvals)
+// @Tags views
+// @Security BearerAuth
+// @Produce json
+// @Param tz query string false "IANA time zone for day boundaries (default the user's time zone)"
+// @Success 200 {object} response.Envelope{data=domain.Badges}
+// @Router /me/badges [get]
+func (h *SmartViewHandler) Badges(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+
+	badges, err := h.viewSvc.Badges(c.Request.Context(), middleware.CurrentUserID(c), loc)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	response.OK(c, badges)
+}
+
+// parseTimezone reads ?tz=, writing a 400 and returning false when invalid.
+// It returns a nil location when tz is omitted, leaving the service to use
+// the user's own time zone.
+func parseTimezone(c *gin.Context) (*time.Location, bool) {
+	tz := c.Query("tz")
+	if tz == "" {
+		return nil, true
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		response.BadRequest(c, "INVALID_PARAM", "tz must be an IANA time zone (e.g. Asia/Jakarta)", nil)
+		return nil, false
+	}
+	return loc, true
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// TagHandler exposes tag CRUD endpoints and tagging of tasks.
+type TagHandler struct {
+	tagSvc *service.TagService
+}
+
+// NewTagHandler creates a TagHandler.
+func NewTagHandler(tagSvc *service.TagService) *TagHandler {
+	return &TagHandler{tagSvc: tagSvc}
+}
+
+// Create godoc
+// @Summary Create a tag
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateTagRequest true "Tag payload"
+// @Success 201 {object} response.Envelope{data=domain.Tag}
+// @Failure 409 {object} response.Envelope
+// @Router /tags [post]
+func (h *TagHandler) Create(c *gin.Context) {
+	var req domain.CreateTagRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	tag, err := h.tagSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Created(c, tag)
+}
+
+// List godoc
+// @Summary List tags for current user
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.Tag}
+// @Router /tags [get]
+func (h *TagHandler) List(c *gin.Context) {
+	tags, err := h.tagSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, tags)
+}
+
+// GetByID godoc
+// @Summary Get a tag by ID
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Success 200 {object} response.Envelope{data=domain.Tag}
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid tag id", nil)
+		return
+	}
+
+	tag, err := h.tagSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tag)
+}
+
+// Update godoc
+// @Summary Update a tag
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tag ID"
+// @Param body body domain.UpdateTagRequest true "Fields to update"
+// @Success 200 {object} response.Envelope{data=domain.Tag}
+// @Router /tags/{id} [patch]
+func (h *TagHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid tag id", nil)
+		return
+	}
+
+	var req domain.UpdateTagRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	tag, err := h.tagSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tag)
+}
+
+// Delete godoc
+// @Summary Delete a tag and remove it from all tasks
+// @Tags tags
+// @Security BearerAuth
+// @Param id path string true "Tag ID"
+// @Success 200 {object} response.Envelope
+// @Router /tags/{id} [delete]
+func (h *TagHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid tag id", nil)
+		return
+	}
+
+	if err := h.tagSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "tag deleted"})
+}
+
+// ListForTask godoc
+// @Summary List the tags on a task
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Envelope{data=[]domain.Tag}
+// @Router /tasks/{id}/tags [get]
+func (h *TagHandler) ListForTask(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	tags, err := h.tagSvc.ListForTask(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tags)
+}
+
+// SetForTask godoc
+// @Summary Replace the tags on a task
+// @Tags tags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.SetTaskTagsRequest true "Tag IDs; empty clears them"
+// @Success 200 {object} response.Envelope{data=[]domain.Tag}
+// @Router /tasks/{id}/tags [put]
+func (h *TagHandler) SetForTask(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.SetTaskTagsRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	tags, err := h.tagSvc.SetForTask(c.Request.Context(), id, middleware.CurrentUserID(c), req.TagIDs)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tags)
+}
+
+func (h *TagHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this resource")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "a tag with this name already exists")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskDependencyHandler exposes the blocks / blocked-by endpoints of a task.
+type TaskDependencyHandler struct {
+	depSvc *service.TaskDependencyService
+}
+
+// NewTaskDependencyHandler creates a TaskDependencyHandler.
+func NewTaskDependencyHandler(depSvc *service.TaskDependencyService) *TaskDependencyHandler {
+	return &TaskDependencyHandler{depSvc: depSvc}
+}
+
+// List godoc
+// @Summary List a task's blockers and the tasks it blocks
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Envelope{data=domain.TaskDependencies}
+// @Router /tasks/{id}/dependencies [get]
+func (h *TaskDependencyHandler) List(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	deps, err := h.depSvc.List(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, deps)
+}
+
+// Add godoc
+// @Summary Mark a task as blocked by another task
+// @Tags tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.AddDependencyRequest true "Blocking task"
+// @Success 201 {object} response.Envelope{data=domain.TaskDependencies}
+// @Failure 400 {object} response.Envelope "Self-dependency or cycle"
+// @Router /tasks/{id}/dependencies [post]
+func (h *TaskDependencyHandler) Add(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.AddDependencyRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	deps, err := h.depSvc.Add(c.Request.Context(), id, middleware.CurrentUserID(c), req.BlockedByID)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, deps)
+}
+
+// Remove godoc
+// @Summary Remove a blocker from a task
+// @Tags tasks
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param blockerID path string true "Blocking task ID"
+// @Success 200 {object} response.Envelope
+// @Router /tasks/{id}/dependencies/{blockerID} [delete]
+func (h *TaskDependencyHandler) Remove(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	blockerID, err := parseUUID(c, "blockerID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid blocker id", nil)
+		return
+	}
+
+	if err := h.depSvc.Remove(c.Request.Context(), id, middleware.CurrentUserID(c), blockerID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "dependency removed"})
+}
+
+func (h *TaskDependencyHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskExchangeHandler exposes task export and import in third-party formats.
+type TaskExchangeHandler struct {
+	exchangeSvc  *service.TaskExchangeService
+	operationSvc *service.OperationService
+}
+
+// NewTaskExchangeHandler creates a TaskExchangeHandler.
+func NewTaskExchangeHandler(exchangeSvc *service.TaskExchangeService, operationSvc *service.OperationService) *TaskExchangeHandler {
+	return &TaskExchangeHandler{exchangeSvc: exchangeSvc, operationSvc: operationSvc}
+}
+
+// Export godoc
+// @Summary Export all tasks
+// @Description Downloads every task of the user in a third-party format. todotxt writes one todo.txt line per task with (A)-(C) priorities, +project, @tag contexts and due: dates; taskwarrior writes the JSON array `task import` reads. With Prefer: respond-async the export runs as an async operation whose file is served from /operations/{id}/file. A taskwarrior export asked for with Accept: application/x-ndjson is streamed instead, one task per line, oldest first.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce plain
+// @Produce json
+// @Produce x-ndjson
+// @Param format query string true "Export format" Enums(todotxt, taskwarrior)
+// @Param Prefer header string false "respond-async to export in the background"
+// @Success 200 {string} string "todo.txt file or TaskWarrior JSON"
+// @Success 202 {object} response.Envelope{data=domain.Operation}
+// @Failure 400 {object} response.Envelope
+// @Router /tasks/export [get]
+func (h *TaskExchangeHandler) Export(c *gin.Context) {
+	format, ok := exchangeFormat(c, domain.TaskExchangeFormats)
+	if !ok {
+		return
+	}
+
+	if prefersAsync(c) {
+		startOperation(c, h.operationSvc, service.OperationExportTasks, service.TaskExportPayload{Format: format})
+		return
+	}
+	if format == domain.TaskFormatTaskWarrior && wantsNDJSON(c) {
+		userID := middleware.CurrentUserID(c)
+		streamNDJSON(c, func(emit func(any) error) error {
+			return h.exchangeSvc.StreamTaskWarrior(c.Request.Context(), userID, func(tw domain.TaskWarriorTask) error {
+				return emit(tw)
+			})
+		})
+		return
+	}
+
+	file, err := h.exchangeSvc.Export(c.Request.Context(), middleware.CurrentUserID(c), format)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+file.Name+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// Import godoc
+// @Summary Import tasks
+// @Description Creates a task for every entry of a file in a third-party format. Projects and tags are matched by name or created. The whole file is checked before any task is created. csv takes a header row naming its columns (title, description, priority, due_date, project); rejected rows are all listed in the error details, and the tasks are inserted in one transaction. With Prefer: respond-async the import runs as an async operation; a file that does not parse then fails the operation rather than the request.
+// @Tags tasks
+// @Security BearerAuth
+// @Accept plain
+// @Accept json
+// @Accept text/csv
+// @Produce json
+// @Param format query string true "Import format" Enums(todotxt, taskwarrior, csv)
+// @Param body body string true "todo.txt file, `task export` output or CSV file"
+// @Param Prefer header string false "respond-async to import in the background"
+// @Success 201 {object} response.Envelope{data=domain.TaskImportResult}
+// @Success 202 {object} response.Envelope{data=domain.Operation}
+// @Failure 400 {object} response.Envelope "Unsupported format or malformed entry; for csv, details lists the rejected rows"
+// @Router /tasks/import [post]
+func (h *TaskExchangeHandler) Import(c *gin.Context) {
+	format, ok := exchangeFormat(c, domain.TaskImportFormats)
+	if !ok {
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", fmt.Sprintf("file must be at most %d bytes", maxImportBytes), nil)
+		return
+	}
+	if prefersAsync(c) {
+		startOperation(c, h.operationSvc, service.OperationImportTasks, service.TaskImportPayload{Format: format, Body: body})
+		return
+	}
+
+	var result *domain.TaskImportResult
+	switch format {
+	case domain.TaskFormatTodoTxt:
+		result, err = h.exchangeSvc.ImportTodoTxt(c.Request.Context(), middleware.CurrentUserID(c), string(body))
+	case domain.TaskFormatTaskWarrior:
+		tasks, parseErr := domain.ParseTaskWarrior(body)
+		if parseErr != nil {
+			response.BadRequest(c, "INVALID_JSON", "invalid TaskWarrior export: "+parseErr.Error(), nil)
+			return
+		}
+		result, err = h.exchangeSvc.ImportTaskWarrior(c.Request.Context(), middleware.CurrentUserID(c), tasks)
+	case domain.TaskFormatCSV:
+		rows, unmapped, parseErr := domain.ParseTaskCSV(bytes.NewReader(body))
+		if parseErr != nil {
+			response.BadRequest(c, "INVALID_CSV", "invalid CSV file: "+parseErr.Error(), nil)
+			return
+		}
+		result, err = h.exchangeSvc.ImportCSV(c.Request.Context(), middleware.CurrentUserID(c), rows, unmapped)
+	}
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, result)
+}
+
+// exchangeFormat reads the required format query parameter, responding with
+// 400 when it is missing or not one of formats.
+func exchangeFormat(c *gin.Context, formats []domain.TaskExchangeFormat) (domain.TaskExchangeFormat, bool) {
+	format := domain.TaskExchangeFormat(c.Query("format"))
+	if !slices.Contains(formats, format) {
+		response.BadRequest(c, "INVALID_PARAM", "unsupported format", validator.Invalid("format", validator.EnumMessage(formats)))
+		return "", false
+	}
+	return format, true
+}
+
+func (h *TaskExchangeHandler) handleError(c *gin.Context, err error) {
+	var rows domain.TaskImportErrors
+	switch {
+	case errors.As(err, &rows):
+		response.BadRequest(c, "INVALID_ROWS", rows.Error(), rows)
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task not found")
+	default:
+		response.InternalError(c)
+	}
+}
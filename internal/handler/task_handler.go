@@ -2,6 +2,9 @@ package handler
 
 import (
 	"errors"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/galihaleanda/todo-app/internal/middleware"
@@ -15,12 +18,20 @@ import (
 
 // TaskHandler exposes task CRUD endpoints.
 type TaskHandler struct {
-	taskSvc *service.TaskService
+	taskSvc    *service.TaskService
+	historySvc *service.TaskHistoryService
+	recentSvc  *service.RecentTaskService
+	rankingSvc *service.RankingService
 }
 
 // NewTaskHandler creates a TaskHandler.
-func NewTaskHandler(taskSvc *service.TaskService) *TaskHandler {
-	return &TaskHandler{taskSvc: taskSvc}
+func NewTaskHandler(
+	taskSvc *service.TaskService,
+	historySvc *service.TaskHistoryService,
+	recentSvc *service.RecentTaskService,
+	rankingSvc *service.RankingService,
+) *TaskHandler {
+	return &TaskHandler{taskSvc: taskSvc, historySvc: historySvc, recentSvc: recentSvc, rankingSvc: rankingSvc}
 }
 
 // Create godoc
@@ -30,7 +41,7 @@ func NewTaskHandler(taskSvc *service.TaskService) *TaskHandler {
 // @Accept json
 // @Produce json
 // @Param body body domain.CreateTaskRequest true "Task payload"
-// @Success 201 {object} response.Envelope{data=domain.Task}
+// @Success 201 {object} response.Envelope{data=domain.TaskWithHints}
 // @Router /tasks [post]
 func (h *TaskHandler) Create(c *gin.Context) {
 	var req domain.CreateTaskRequest
@@ -42,7 +53,7 @@ func (h *TaskHandler) Create(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	task, err := h.taskSvc.CreateWithHints(c.Request.Context(), middleware.CurrentUserID(c), &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -56,47 +67,373 @@ func (h *TaskHandler) Create(c *gin.Context) {
 // @Tags tasks
 // @Security BearerAuth
 // @Produce json
-// @Param status query string false "Filter by status (todo|in_progress|done)"
-// @Param priority query string false "Filter by priority (low|medium|high)"
-// @Param project_id query string false "Filter by project UUID"
+// @Produce x-ndjson
+// @Param status query []string false "Statuses to list, comma-separated (todo|in_progress|done, case-insensitive, aliases like wip)"
+// @Param -status query []string false "Statuses to leave out, comma-separated"
+// @Param priority query []string false "Priorities to list, comma-separated (low|medium|high, case-insensitive, aliases like p1)"
+// @Param -priority query []string false "Priorities to leave out, comma-separated"
+// @Param energy query string false "Filter by energy (low|medium|high)"
+// @Param context query string false "Filter by context, e.g. phone or @phone"
+// @Param project_id query []string false "Project UUIDs to list, comma-separated"
+// @Param -project_id query []string false "Project UUIDs to leave out, comma-separated; tasks without a project are kept"
+// @Param parent_id query string false "Only subtasks of this task UUID"
+// @Param tag query []string false "Tag UUIDs, repeated or comma-separated; tasks must carry all of them"
 // @Param overdue query bool false "Show only overdue tasks"
+// @Param due_after query string false "Only tasks due at or after this RFC3339 time"
+// @Param due_before query string false "Only tasks due at or before this RFC3339 time"
+// @Param created_after query string false "Only tasks created at or after this RFC3339 time"
+// @Param created_before query string false "Only tasks created at or before this RFC3339 time"
+// @Param archived query bool false "List archived tasks instead of live ones"
+// @Param pinned query bool false "Only pinned tasks when true, only unpinned ones when false"
+// @Param include_deferred query bool false "Also list tasks whose start date is still ahead"
+// @Param scheduled_after query string false "Only tasks starting at or after this RFC3339 time, deferred ones included"
+// @Param scheduled_before query string false "Only tasks starting at or before this RFC3339 time, deferred ones included"
+// @Param sort query string false "ranked (default, the user's ranker) or manual (drag-and-drop order)"
 // @Param search query string false "Full-text search"
 // @Param page query int false "Page number"
 // @Param limit query int false "Items per page"
+// @Param fields query string false "Comma-separated fields to return, e.g. id,title,status,due_date (default all; id is always included)"
+// @Param modified_since query string false "RFC3339; return only tasks changed since then (other filters and fields are ignored)"
+// @Param Accept header string false "application/x-ndjson to stream every matching task, one per line, ignoring page and limit"
 // @Success 200 {object} response.Envelope{data=[]domain.Task}
+// @Failure 400 {object} response.Envelope "Unknown field"
+// @Header 200 {string} X-Ranker "Ranker that ordered the page"
 // @Router /tasks [get]
 func (h *TaskHandler) List(c *gin.Context) {
 	userID := middleware.CurrentUserID(c)
+	if v := c.Query("modified_since"); v != "" {
+		h.listModifiedSince(c, userID, v)
+		return
+	}
 	pag := pagination.FromContext(c)
 
 	filter := domain.TaskFilter{}
-	if s := c.Query("status"); s != "" {
-		status := domain.TaskStatus(s)
-		filter.Status = &status
+	var ok bool
+	if filter.Statuses, ok = parseEnumQuery(c, "status", domain.ParseTaskStatus, domain.TaskStatusValues); !ok {
+		return
+	}
+	if filter.ExcludeStatuses, ok = parseEnumQuery(c, "-status", domain.ParseTaskStatus, domain.TaskStatusValues); !ok {
+		return
+	}
+	if filter.Priorities, ok = parseEnumQuery(c, "priority", domain.ParseTaskPriority, domain.TaskPriorityValues); !ok {
+		return
+	}
+	if filter.ExcludePriorities, ok = parseEnumQuery(c, "-priority", domain.ParseTaskPriority, domain.TaskPriorityValues); !ok {
+		return
 	}
-	if p := c.Query("priority"); p != "" {
-		priority := domain.TaskPriority(p)
-		filter.Priority = &priority
+	if filter.ProjectIDs, ok = parseUUIDQuery(c, "project_id"); !ok {
+		return
+	}
+	if filter.ExcludeProjectIDs, ok = parseUUIDQuery(c, "-project_id"); !ok {
+		return
 	}
-	if pid := c.Query("project_id"); pid != "" {
+	if v := c.Query("energy"); v != "" {
+		energy, err := domain.ParseTaskEnergy(v)
+		if err != nil {
+			response.UnprocessableEntity(c, validator.Invalid("energy", validator.EnumMessage(domain.TaskEnergyValues)))
+			return
+		}
+		filter.Energy = &energy
+	}
+	filter.Context = domain.NormalizeTaskContext(c.Query("context"))
+	if pid := c.Query("parent_id"); pid != "" {
 		id, err := uuid.Parse(pid)
 		if err == nil {
-			filter.ProjectID = &id
+			filter.ParentID = &id
+		}
+	}
+	for _, v := range c.QueryArray("tag") {
+		for _, raw := range strings.Split(v, ",") {
+			id, err := uuid.Parse(strings.TrimSpace(raw))
+			if err != nil {
+				response.BadRequest(c, "INVALID_PARAM", "tag must be a tag UUID", nil)
+				return
+			}
+			filter.TagIDs = append(filter.TagIDs, id)
 		}
 	}
 	if c.Query("overdue") == "true" {
 		t := true
 		filter.Overdue = &t
 	}
+	if filter.DueAfter, ok = parseTimeQuery(c, "due_after"); !ok {
+		return
+	}
+	if filter.DueBefore, ok = parseTimeQuery(c, "due_before"); !ok {
+		return
+	}
+	if filter.CreatedAfter, ok = parseTimeQuery(c, "created_after"); !ok {
+		return
+	}
+	if filter.CreatedBefore, ok = parseTimeQuery(c, "created_before"); !ok {
+		return
+	}
+	if c.Query("archived") == "true" {
+		t := true
+		filter.Archived = &t
+	}
+	if v := c.Query("pinned"); v != "" {
+		pinned, err := strconv.ParseBool(v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "pinned must be true or false", nil)
+			return
+		}
+		filter.Pinned = &pinned
+	}
+	filter.IncludeDeferred = c.Query("include_deferred") == "true"
+	if filter.StartAfter, ok = parseTimeQuery(c, "scheduled_after"); !ok {
+		return
+	}
+	if filter.StartBefore, ok = parseTimeQuery(c, "scheduled_before"); !ok {
+		return
+	}
 	filter.Search = c.Query("search")
+	if sort := c.Query("sort"); sort != "" {
+		if sort != domain.TaskSortRanked && sort != domain.TaskSortManual {
+			response.UnprocessableEntity(c, validator.Invalid("sort", validator.EnumMessage(domain.TaskSortValues)))
+			return
+		}
+		filter.Sort = sort
+	}
+	fields, ok := parseFields(c, domain.TaskFieldNames)
+	if !ok {
+		return
+	}
+	filter.Fields = fields
+
+	if wantsNDJSON(c) {
+		h.streamList(c, userID, filter)
+		return
+	}
+
+	tasks, total, ranker, err := h.rankingSvc.List(c.Request.Context(), userID, filter, pag.Page, pag.Limit)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	data, err := sparse(tasks, fields)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	c.Header("X-Ranker", ranker)
+	response.OKPaginated(c, data, pag.Page, pag.Limit, total)
+}
+
+// streamList writes every task matching filter as NDJSON. Rows come
+// straight from the database, so the smart score stands in for rankers
+// that order in memory.
+func (h *TaskHandler) streamList(c *gin.Context, userID uuid.UUID, filter domain.TaskFilter) {
+	streamNDJSON(c, func(emit func(any) error) error {
+		return h.taskSvc.Stream(c.Request.Context(), userID, filter, func(t *domain.Task) error {
+			if filter.Fields == nil {
+				return emit(t)
+			}
+			item, err := sparseItem(t, filter.Fields)
+			if err != nil {
+				return err
+			}
+			return emit(item)
+		})
+	})
+}
+
+func (h *TaskHandler) listModifiedSince(c *gin.Context, userID uuid.UUID, v string) {
+	since, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		response.BadRequest(c, "INVALID_PARAM", "modified_since must be an RFC3339 timestamp", nil)
+		return
+	}
 
-	tasks, total, err := h.taskSvc.List(c.Request.Context(), userID, filter, pag.Page, pag.Limit)
+	set, err := h.taskSvc.ListModifiedSince(c.Request.Context(), userID, since)
 	if err != nil {
 		response.InternalError(c)
 		return
 	}
 
-	response.OKPaginated(c, tasks, pag.Page, pag.Limit, total)
+	response.OK(c, set)
+}
+
+// Fuzzy godoc
+// @Summary Find tasks with titles similar to a query, tolerating typos
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param q query string true "Text to match against task titles"
+// @Param threshold query number false "Minimum similarity between 0 and 1 (default 0.3); lower finds more"
+// @Param limit query int false "Maximum results (default and max 20)"
+// @Success 200 {object} response.Envelope{data=[]domain.TaskMatch}
+// @Router /tasks/fuzzy [get]
+func (h *TaskHandler) Fuzzy(c *gin.Context) {
+	q := c.Query("q")
+	if q == "" {
+		response.BadRequest(c, "INVALID_PARAM", "q is required", nil)
+		return
+	}
+	threshold := service.DefaultFuzzyThreshold
+	if v := c.Query("threshold"); v != "" {
+		t, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "threshold must be a number between 0 and 1", nil)
+			return
+		}
+		threshold = t
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	matches, err := h.taskSvc.FuzzyFind(c.Request.Context(), middleware.CurrentUserID(c), q, threshold, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, matches)
+}
+
+// Recent godoc
+// @Summary List recently viewed or recently modified tasks
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param kind query string false "viewed (default) | modified"
+// @Param limit query int false "Maximum results (default and max 50)"
+// @Success 200 {object} response.Envelope{data=[]domain.Task}
+// @Router /tasks/recent [get]
+func (h *TaskHandler) Recent(c *gin.Context) {
+	kind := c.DefaultQuery("kind", domain.RecentViewed)
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	tasks, err := h.recentSvc.List(c.Request.Context(), middleware.CurrentUserID(c), kind, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, tasks)
+}
+
+// Today godoc
+// @Summary List what needs attention today
+// @Description Overdue tasks, then tasks due today, then the highest-scoring other open tasks, each marked with the reason it is listed. Tasks that have not started yet are left out.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param tz query string false "IANA time zone for day boundaries (default the user's time zone)"
+// @Success 200 {object} response.Envelope{data=[]domain.AgendaTask}
+// @Router /tasks/views/today [get]
+func (h *TaskHandler) Today(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.taskSvc.Today(c.Request.Context(), middleware.CurrentUserID(c), loc)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tasks)
+}
+
+// Upcoming godoc
+// @Summary List what is due this week
+// @Description Overdue tasks, then tasks due from today through the next 7 days, earliest first, each marked with the reason it is listed.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param tz query string false "IANA time zone for day boundaries (default the user's time zone)"
+// @Success 200 {object} response.Envelope{data=[]domain.AgendaTask}
+// @Router /tasks/views/upcoming [get]
+func (h *TaskHandler) Upcoming(c *gin.Context) {
+	loc, ok := parseTimezone(c)
+	if !ok {
+		return
+	}
+
+	tasks, err := h.taskSvc.Upcoming(c.Request.Context(), middleware.CurrentUserID(c), loc)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tasks)
+}
+
+// Now godoc
+// @Summary Suggest tasks for the current situation
+// @Description Open, started, unblocked tasks that take no more than the given energy and can be done in the given context; tasks that set neither fit anywhere. Tasks in exactly that context come first, then those at exactly that energy, each by smart score.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param energy query string false "Energy available (low|medium|high); default any"
+// @Param context query string false "Where you are, e.g. phone or @phone; default anywhere"
+// @Param limit query int false "Maximum tasks (default 20, max 100)"
+// @Success 200 {object} response.Envelope{data=[]domain.Task}
+// @Router /tasks/views/now [get]
+func (h *TaskHandler) Now(c *gin.Context) {
+	var energy *domain.TaskEnergy
+	if v := c.Query("energy"); v != "" {
+		e, err := domain.ParseTaskEnergy(v)
+		if err != nil {
+			response.UnprocessableEntity(c, validator.Invalid("energy", validator.EnumMessage(domain.TaskEnergyValues)))
+			return
+		}
+		energy = &e
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	tasks, err := h.taskSvc.Now(c.Request.Context(), middleware.CurrentUserID(c), energy, c.Query("context"), limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tasks)
+}
+
+// maxScheduledTasks caps the time slots listed at once.
+const maxScheduledTasks = 500
+
+// Scheduled godoc
+// @Summary List time-blocked tasks
+// @Description Open tasks whose scheduled slot overlaps the window, earliest first.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "RFC3339 start, default now"
+// @Param to query string false "RFC3339 end, default 7 days after from"
+// @Success 200 {object} response.Envelope{data=[]domain.Task}
+// @Failure 400 {object} response.Envelope
+// @Router /tasks/scheduled [get]
+func (h *TaskHandler) Scheduled(c *gin.Context) {
+	from := time.Now()
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "from must be an RFC3339 timestamp", nil)
+			return
+		}
+		from = t
+	}
+	to := from.AddDate(0, 0, 7)
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", "to must be an RFC3339 timestamp", nil)
+			return
+		}
+		to = t
+	}
+	if !to.After(from) || to.Sub(from) > 366*24*time.Hour {
+		response.BadRequest(c, "INVALID_PARAM", "to must be after from and at most a year later", nil)
+		return
+	}
+
+	tasks, err := h.taskSvc.ListScheduled(c.Request.Context(), middleware.CurrentUserID(c), from, to, maxScheduledTasks)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, tasks)
 }
 
 // GetByID godoc
@@ -105,7 +442,9 @@ func (h *TaskHandler) List(c *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Task UUID"
+// @Param as_of query string false "RFC3339 timestamp; return the task as it was at that moment"
 // @Success 200 {object} response.Envelope{data=domain.Task}
+// @Header 200 {string} ETag "The task's version, for If-Match; not sent with as_of"
 // @Router /tasks/{id} [get]
 func (h *TaskHandler) GetByID(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -114,7 +453,21 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	task, err := h.taskSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+	var task *domain.Task
+	if v := c.Query("as_of"); v != "" {
+		asOf, perr := time.Parse(time.RFC3339, v)
+		if perr != nil {
+			response.BadRequest(c, "INVALID_PARAM", "as_of must be an RFC3339 timestamp", nil)
+			return
+		}
+		task, err = h.historySvc.AsOf(c.Request.Context(), id, middleware.CurrentUserID(c), asOf)
+	} else {
+		task, err = h.taskSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+		if err == nil {
+			h.recentSvc.RecordView(c.Request.Context(), task.UserID, task.ID)
+			setETag(c, task.Version)
+		}
+	}
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -131,7 +484,12 @@ func (h *TaskHandler) GetByID(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Task UUID"
 // @Param body body domain.UpdateTaskRequest true "Update payload"
-// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Param include_changes query bool false "Add a changes object with each modified field's old and new value"
+// @Param If-Match header string false "ETag from a previous read; the update is rejected if the task has changed since"
+// @Success 200 {object} response.Envelope{data=domain.TaskWithChanges}
+// @Header 200 {string} ETag "The updated task's version"
+// @Failure 409 {object} response.Envelope "Task is blocked by open tasks"
+// @Failure 412 {object} response.Envelope "Task has changed since the If-Match version"
 // @Router /tasks/{id} [patch]
 func (h *TaskHandler) Update(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -139,6 +497,10 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
 		return
 	}
+	ifVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 
 	var req domain.UpdateTaskRequest
 	if errs, err := validator.BindAndValidate(c, &req); err != nil {
@@ -148,13 +510,19 @@ func (h *TaskHandler) Update(c *gin.Context) {
 		response.UnprocessableEntity(c, errs)
 		return
 	}
+	req.IfVersion = ifVersion
 
-	task, err := h.taskSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	task, changes, err := h.taskSvc.UpdateWithChanges(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
+	setETag(c, task.Version)
 
+	if c.Query("include_changes") == "true" {
+		response.OK(c, domain.TaskWithChanges{Task: task, Changes: changes})
+		return
+	}
 	response.OK(c, task)
 }
 
@@ -164,7 +532,9 @@ func (h *TaskHandler) Update(c *gin.Context) {
 // @Security BearerAuth
 // @Produce json
 // @Param id path string true "Task UUID"
+// @Param If-Match header string false "ETag from a previous read; the delete is rejected if the task has changed since"
 // @Success 200 {object} response.Envelope
+// @Failure 412 {object} response.Envelope "Task has changed since the If-Match version"
 // @Router /tasks/{id} [delete]
 func (h *TaskHandler) Delete(c *gin.Context) {
 	id, err := parseUUID(c, "id")
@@ -172,8 +542,12 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
 		return
 	}
+	ifVersion, ok := parseIfMatch(c)
+	if !ok {
+		return
+	}
 
-	if err := h.taskSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+	if err := h.taskSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c), ifVersion); err != nil {
 		h.handleError(c, err)
 		return
 	}
@@ -181,13 +555,352 @@ func (h *TaskHandler) Delete(c *gin.Context) {
 	response.OK(c, gin.H{"message": "task deleted"})
 }
 
+// Archive godoc
+// @Summary Archive a task
+// @Description Hides the task from lists, search and smart views without deleting it. Archiving an archived task is a no-op.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Router /tasks/{id}/archive [post]
+func (h *TaskHandler) Archive(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	task, err := h.taskSvc.Archive(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, task)
+}
+
+// Unarchive godoc
+// @Summary Unarchive a task
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Router /tasks/{id}/unarchive [post]
+func (h *TaskHandler) Unarchive(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	task, err := h.taskSvc.Unarchive(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, task)
+}
+
+// Pin godoc
+// @Summary Pin a task
+// @Description Keeps the task above all unpinned ones in task lists and smart views, whatever its score or manual position. Pinning a pinned task is a no-op.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Router /tasks/{id}/pin [post]
+func (h *TaskHandler) Pin(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	task, err := h.taskSvc.Pin(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, task)
+}
+
+// Unpin godoc
+// @Summary Unpin a task
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Router /tasks/{id}/unpin [post]
+func (h *TaskHandler) Unpin(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	task, err := h.taskSvc.Unpin(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, task)
+}
+
+// Position godoc
+// @Summary Move a task in the manual order
+// @Description Places the task just after after_id, or at the top when after_id is null. GET /tasks?sort=manual lists tasks in this order.
+// @Tags tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param body body domain.PositionTaskRequest true "Task to follow"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Router /tasks/{id}/position [patch]
+func (h *TaskHandler) Position(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.PositionTaskRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	task, err := h.taskSvc.Position(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, task)
+}
+
+// Move godoc
+// @Summary Move several tasks to a project
+// @Description With dry_run=true, returns the tasks that would move without changing anything.
+// @Tags tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.MoveTasksRequest true "Tasks and target project"
+// @Param dry_run query bool false "Preview only"
+// @Success 200 {object} response.Envelope{data=domain.OperationResult}
+// @Router /tasks/move [post]
+func (h *TaskHandler) Move(c *gin.Context) {
+	var req domain.MoveTasksRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	result, err := h.taskSvc.MoveTasks(c.Request.Context(), middleware.CurrentUserID(c), &req, isDryRun(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, result)
+}
+
+// StartTimer godoc
+// @Summary Start a timer on a task
+// @Description Starts tracking time against the task. Only one timer can run per user at a time.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 201 {object} response.Envelope{data=domain.TimeEntry}
+// @Failure 409 {object} response.Envelope "Another timer is already running"
+// @Router /tasks/{id}/timer/start [post]
+func (h *TaskHandler) StartTimer(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	entry, err := h.taskSvc.StartTimer(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Created(c, entry)
+}
+
+// StopTimer godoc
+// @Summary Stop the timer on a task
+// @Description Stops the running timer and adds the elapsed time to the task's tracked_seconds.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=domain.TimeEntry}
+// @Failure 409 {object} response.Envelope "No timer is running on this task"
+// @Router /tasks/{id}/timer/stop [post]
+func (h *TaskHandler) StopTimer(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	entry, err := h.taskSvc.StopTimer(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, entry)
+}
+
+// Activity godoc
+// @Summary List a task's activity
+// @Description Who changed what and when: creation, deletion and every update that changed a tracked field (project, title, description, status, priority, estimate, due date, recurrence, archived, pinned), newest first.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.TaskActivity}
+// @Router /tasks/{id}/activity [get]
+func (h *TaskHandler) Activity(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	pag := pagination.FromContext(c)
+
+	activity, total, err := h.historySvc.Activity(c.Request.Context(), id, middleware.CurrentUserID(c), pag.Page, pag.Limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OKPaginated(c, activity, pag.Page, pag.Limit, total)
+}
+
+// ListTimeEntries godoc
+// @Summary List a task's time entries
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=[]domain.TimeEntry}
+// @Router /tasks/{id}/time-entries [get]
+func (h *TaskHandler) ListTimeEntries(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	entries, err := h.taskSvc.ListTimeEntries(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, entries)
+}
+
 func (h *TaskHandler) handleError(c *gin.Context, err error) {
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
 		response.NotFound(c, "task not found")
 	case errors.Is(err, domain.ErrForbidden):
 		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrTaskBlocked):
+		response.Conflict(c, "task cannot be completed while it is blocked by open tasks")
+	case errors.Is(err, domain.ErrTimerRunning):
+		response.Conflict(c, "another timer is already running; stop it first")
+	case errors.Is(err, domain.ErrTimerNotRunning):
+		response.Conflict(c, "no timer is running on this task")
+	case errors.Is(err, domain.ErrScheduleConflict):
+		response.Conflict(c, "the time slot overlaps another scheduled task; send allow_overlap to book it anyway")
+	case errors.Is(err, domain.ErrPreconditionFailed):
+		response.PreconditionFailed(c, "the task has changed since it was read; fetch it again and retry")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
 	default:
 		response.InternalError(c)
 	}
 }
+
+// queryValues returns a query parameter's values, whether repeated or
+// comma-separated, with blanks dropped.
+func queryValues(c *gin.Context, key string) []string {
+	var out []string
+	for _, v := range c.QueryArray(key) {
+		for _, raw := range strings.Split(v, ",") {
+			if raw = strings.TrimSpace(raw); raw != "" {
+				out = append(out, raw)
+			}
+		}
+	}
+	return out
+}
+
+// parseEnumQuery parses every value of an enum query parameter, answering 422
+// and returning false when one is unknown.
+func parseEnumQuery[T ~string](c *gin.Context, key string, parse func(string) (T, error), valid []T) ([]T, bool) {
+	var out []T
+	for _, raw := range queryValues(c, key) {
+		v, err := parse(raw)
+		if err != nil {
+			response.UnprocessableEntity(c, validator.Invalid(key, validator.EnumMessage(valid)))
+			return nil, false
+		}
+		out = append(out, v)
+	}
+	return out, true
+}
+
+// parseUUIDQuery parses every value of a UUID query parameter, answering 400
+// and returning false when one is malformed.
+func parseUUIDQuery(c *gin.Context, key string) ([]uuid.UUID, bool) {
+	var out []uuid.UUID
+	for _, raw := range queryValues(c, key) {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			response.BadRequest(c, "INVALID_PARAM", key+" must be a list of UUIDs", nil)
+			return nil, false
+		}
+		out = append(out, id)
+	}
+	return out, true
+}
+
+// parseTimeQuery parses an optional RFC3339 query parameter, answering 400
+// and returning false when it is malformed.
+func parseTimeQuery(c *gin.Context, key string) (*time.Time, bool) {
+	v := c.Query(key)
+	if v == "" {
+		return nil, true
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		response.BadRequest(c, "INVALID_PARAM", key+" must be an RFC3339 timestamp", nil)
+		return nil, false
+	}
+	return &t, true
+}
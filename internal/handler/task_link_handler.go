@@ -0,0 +1,167 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TaskLinkHandler exposes endpoints for external links on tasks.
+type TaskLinkHandler struct {
+	linkSvc *service.TaskLinkService
+}
+
+// NewTaskLinkHandler creates a TaskLinkHandler.
+func NewTaskLinkHandler(linkSvc *service.TaskLinkService) *TaskLinkHandler {
+	return &TaskLinkHandler{linkSvc: linkSvc}
+}
+
+// Create godoc
+// @Summary Attach a link to a task
+// @Description Adds an http or https URL, such as a pull request or a ticket. The label defaults to the URL's host.
+// @Tags links
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param body body domain.CreateTaskLinkRequest true "Link"
+// @Success 201 {object} response.Envelope{data=domain.TaskLink}
+// @Failure 400 {object} response.Envelope "Not an http(s) URL, or too many links"
+// @Failure 409 {object} response.Envelope "The task already links to this URL"
+// @Router /tasks/{id}/links [post]
+func (h *TaskLinkHandler) Create(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	var req domain.CreateTaskLinkRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	link, err := h.linkSvc.Create(c.Request.Context(), taskID, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Created(c, link)
+}
+
+// List godoc
+// @Summary List a task's links
+// @Tags links
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Envelope{data=[]domain.TaskLink}
+// @Router /tasks/{id}/links [get]
+func (h *TaskLinkHandler) List(c *gin.Context) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	links, err := h.linkSvc.List(c.Request.Context(), taskID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, links)
+}
+
+// Update godoc
+// @Summary Change a link's URL or label
+// @Tags links
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param linkID path string true "Link ID"
+// @Param body body domain.UpdateTaskLinkRequest true "Fields to change"
+// @Success 200 {object} response.Envelope{data=domain.TaskLink}
+// @Router /tasks/{id}/links/{linkID} [patch]
+func (h *TaskLinkHandler) Update(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateTaskLinkRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	link, err := h.linkSvc.Update(c.Request.Context(), taskID, id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, link)
+}
+
+// Delete godoc
+// @Summary Remove a link from a task
+// @Tags links
+// @Security BearerAuth
+// @Param id path string true "Task ID"
+// @Param linkID path string true "Link ID"
+// @Success 200 {object} response.Envelope
+// @Router /tasks/{id}/links/{linkID} [delete]
+func (h *TaskLinkHandler) Delete(c *gin.Context) {
+	taskID, id, ok := h.parseIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := h.linkSvc.Delete(c.Request.Context(), taskID, id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, gin.H{"message": "link deleted"})
+}
+
+func (h *TaskLinkHandler) parseIDs(c *gin.Context) (taskID, id uuid.UUID, ok bool) {
+	taskID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err = parseUUID(c, "linkID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid link id", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return taskID, id, true
+}
+
+func (h *TaskLinkHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.Conflict(c, "the task already links to this URL")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
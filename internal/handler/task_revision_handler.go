@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// TaskRevisionHandler exposes the earlier descriptions of a task.
+type TaskRevisionHandler struct {
+	revisionSvc *service.TaskRevisionService
+}
+
+// NewTaskRevisionHandler creates a TaskRevisionHandler.
+func NewTaskRevisionHandler(revisionSvc *service.TaskRevisionService) *TaskRevisionHandler {
+	return &TaskRevisionHandler{revisionSvc: revisionSvc}
+}
+
+// List godoc
+// @Summary List a task's earlier descriptions
+// @Description The descriptions updates have replaced, most recent first; only the last few are kept.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Success 200 {object} response.Envelope{data=[]domain.TaskRevision}
+// @Failure 404 {object} response.Envelope
+// @Router /tasks/{id}/revisions [get]
+func (h *TaskRevisionHandler) List(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+
+	revisions, err := h.revisionSvc.List(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, revisions)
+}
+
+// Restore godoc
+// @Summary Restore an earlier description
+// @Description Sets the task's description back to the revision's. The description it replaces is kept as a new revision.
+// @Tags tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task UUID"
+// @Param revisionID path string true "Revision UUID"
+// @Success 200 {object} response.Envelope{data=domain.Task}
+// @Failure 404 {object} response.Envelope
+// @Router /tasks/{id}/revisions/{revisionID}/restore [post]
+func (h *TaskRevisionHandler) Restore(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid task id", nil)
+		return
+	}
+	revisionID, err := parseUUID(c, "revisionID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid revision id", nil)
+		return
+	}
+
+	task, err := h.revisionSvc.Restore(c.Request.Context(), id, revisionID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, task)
+}
+
+func (h *TaskRevisionHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "task or revision not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this task")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TelemetryHandler exposes client error ingestion and its admin triage endpoints.
+type TelemetryHandler struct {
+	telemetrySvc  *service.TelemetryService
+	maxBatchBytes int64
+}
+
+// NewTelemetryHandler creates a TelemetryHandler. Batches larger than
+// maxBatchBytes are rejected.
+func NewTelemetryHandler(telemetrySvc *service.TelemetryService, maxBatchBytes int64) *TelemetryHandler {
+	return &TelemetryHandler{telemetrySvc: telemetrySvc, maxBatchBytes: maxBatchBytes}
+}
+
+// ReportErrors godoc
+// @Summary Report client errors
+// @Description Accepts a batch of frontend errors. Works without signing in; a valid access token attributes the errors to the user. Emails, tokens and long numbers are scrubbed before storage.
+// @Tags telemetry
+// @Accept json
+// @Produce json
+// @Param body body domain.ClientErrorBatch true "Error batch"
+// @Success 202 {object} response.Envelope{data=domain.ClientErrorIngestResult}
+// @Failure 400 {object} response.Envelope "Batch too large or not JSON"
+// @Failure 422 {object} response.Envelope
+// @Failure 429 {object} response.Envelope
+// @Router /telemetry/errors [post]
+func (h *TelemetryHandler) ReportErrors(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, h.maxBatchBytes))
+	if err != nil {
+		response.BadRequest(c, "INVALID_BODY", fmt.Sprintf("batch must be at most %d bytes", h.maxBatchBytes), nil)
+		return
+	}
+
+	var batch domain.ClientErrorBatch
+	if err := json.Unmarshal(body, &batch); err != nil {
+		response.UnprocessableEntity(c, validator.Invalid("body", "invalid JSON: "+err.Error()))
+		return
+	}
+	if errs, err := validator.Validate(&batch); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	result, err := h.telemetrySvc.Ingest(c.Request.Context(), middleware.OptionalUserID(c), c.Request.UserAgent(), &batch)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.Accepted(c, result)
+}
+
+// ListErrors godoc
+// @Summary List client errors
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param fingerprint query string false "Only reports of this group"
+// @Param request_id query string false "Only reports tied to this server request ID"
+// @Param client_version query string false "Only reports from this client version"
+// @Param user_id query string false "Only reports from this user"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.ClientError}
+// @Router /admin/telemetry/errors [get]
+func (h *TelemetryHandler) ListErrors(c *gin.Context) {
+	pag := pagination.FromContext(c)
+	filter := domain.ClientErrorFilter{
+		Fingerprint:   c.Query("fingerprint"),
+		RequestID:     c.Query("request_id"),
+		ClientVersion: c.Query("client_version"),
+		Page:          pag.Page,
+		Limit:         pag.Limit,
+	}
+	if v := c.Query("user_id"); v != "" {
+		userID, err := uuid.Parse(v)
+		if err != nil {
+			response.BadRequest(c, "INVALID_ID", "invalid user id", nil)
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	reports, total, err := h.telemetrySvc.List(c.Request.Context(), filter)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OKPaginated(c, reports, pag.Page, pag.Limit, total)
+}
+
+// GetError godoc
+// @Summary Get a client error
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Client error UUID"
+// @Success 200 {object} response.Envelope{data=domain.ClientError}
+// @Failure 404 {object} response.Envelope
+// @Router /admin/telemetry/errors/{id} [get]
+func (h *TelemetryHandler) GetError(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid client error id", nil)
+		return
+	}
+
+	report, err := h.telemetrySvc.GetByID(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, report)
+}
+
+// ErrorGroups godoc
+// @Summary Group client errors by fingerprint
+// @Description The most frequent errors of the last days, with counts and affected users.
+// @Tags admin
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Look-back window in days (default 7, max 90)"
+// @Success 200 {object} response.Envelope{data=[]domain.ClientErrorGroup}
+// @Router /admin/telemetry/errors/groups [get]
+func (h *TelemetryHandler) ErrorGroups(c *gin.Context) {
+	var window time.Duration
+	if v := c.Query("days"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 || days > 90 {
+			response.BadRequest(c, "INVALID_PARAM", "days must be between 1 and 90", nil)
+			return
+		}
+		window = time.Duration(days) * 24 * time.Hour
+	}
+
+	groups, err := h.telemetrySvc.Groups(c.Request.Context(), window)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, groups)
+}
+
+func (h *TelemetryHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "client error not found")
+	default:
+		response.InternalError(c)
+	}
+}
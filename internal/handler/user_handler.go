@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// UserHandler exposes the user's own account settings.
+type UserHandler struct {
+	userSvc *service.UserService
+}
+
+// NewUserHandler creates a UserHandler.
+func NewUserHandler(userSvc *service.UserService) *UserHandler {
+	return &UserHandler{userSvc: userSvc}
+}
+
+// GetTimezone godoc
+// @Summary Get the time zone the user's days are counted in
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=domain.SetTimezoneRequest}
+// @Router /me/timezone [get]
+func (h *UserHandler) GetTimezone(c *gin.Context) {
+	tz, err := h.userSvc.Timezone(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, domain.SetTimezoneRequest{Timezone: tz})
+}
+
+// SetTimezone godoc
+// @Summary Set the user's time zone
+// @Description "Due today", smart views, overdue checks and date-only due dates (at local midnight, due all that day) follow this zone.
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.SetTimezoneRequest true "IANA time zone"
+// @Success 200 {object} response.Envelope{data=domain.SetTimezoneRequest}
+// @Failure 400 {object} response.Envelope
+// @Router /me/timezone [put]
+func (h *UserHandler) SetTimezone(c *gin.Context) {
+	var req domain.SetTimezoneRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	if err := h.userSvc.SetTimezone(c.Request.Context(), middleware.CurrentUserID(c), req.Timezone); err != nil {
+		h.handleError(c, err)
+		return
+	}
+	response.OK(c, req)
+}
+
+func (h *UserHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "user not found")
+	case errors.Is(err, domain.ErrValidation):
+		response.BadRequest(c, "VALIDATION_ERROR", err.Error(), nil)
+	default:
+		response.InternalError(c)
+	}
+}
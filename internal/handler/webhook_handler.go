@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/internal/validator"
+	"github.com/galihaleanda/todo-app/pkg/pagination"
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookHandler exposes webhook subscription and delivery endpoints.
+type WebhookHandler struct {
+	webhookSvc *service.WebhookService
+}
+
+// NewWebhookHandler creates a WebhookHandler.
+func NewWebhookHandler(webhookSvc *service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookSvc: webhookSvc}
+}
+
+// Meta godoc
+// @Summary Webhook verification metadata
+// @Description Public keys for verifying webhook signatures, the key rotation schedule and egress IPs.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} response.Envelope{data=service.WebhookMeta}
+// @Router /webhooks/meta [get]
+func (h *WebhookHandler) Meta(c *gin.Context) {
+	response.OK(c, h.webhookSvc.Meta())
+}
+
+// Catalog godoc
+// @Summary Event catalog
+// @Description Every event webhooks deliver, with the JSON Schema of its payload in each payload version.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} response.Envelope{data=service.EventCatalog}
+// @Router /events/catalog [get]
+func (h *WebhookHandler) Catalog(c *gin.Context) {
+	response.OK(c, h.webhookSvc.Catalog())
+}
+
+// Create godoc
+// @Summary Register a webhook
+// @Description The response contains the signing secret; it is not shown again.
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body domain.CreateWebhookRequest true "Webhook payload"
+// @Success 201 {object} response.Envelope{data=domain.CreatedWebhook}
+// @Failure 422 {object} response.Envelope
+// @Router /webhooks [post]
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req domain.CreateWebhookRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	webhook, err := h.webhookSvc.Create(c.Request.Context(), middleware.CurrentUserID(c), &req)
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+
+	response.Created(c, webhook)
+}
+
+// List godoc
+// @Summary List webhooks for current user
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Envelope{data=[]domain.Webhook}
+// @Router /webhooks [get]
+func (h *WebhookHandler) List(c *gin.Context) {
+	webhooks, err := h.webhookSvc.List(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		response.InternalError(c)
+		return
+	}
+	response.OK(c, webhooks)
+}
+
+// GetByID godoc
+// @Summary Get a webhook by ID
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Success 200 {object} response.Envelope{data=domain.Webhook}
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return
+	}
+
+	webhook, err := h.webhookSvc.GetByID(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, webhook)
+}
+
+// Enable godoc
+// @Summary Re-enable a webhook
+// @Description Activates the webhook and closes its circuit breaker, which opens after repeated failed deliveries.
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Success 200 {object} response.Envelope{data=domain.Webhook}
+// @Router /webhooks/{id}/enable [post]
+func (h *WebhookHandler) Enable(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return
+	}
+
+	webhook, err := h.webhookSvc.Enable(c.Request.Context(), id, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, webhook)
+}
+
+// Update godoc
+// @Summary Update a webhook
+// @Tags webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Param body body domain.UpdateWebhookRequest true "Update payload"
+// @Success 200 {object} response.Envelope{data=domain.Webhook}
+// @Router /webhooks/{id} [patch]
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return
+	}
+
+	var req domain.UpdateWebhookRequest
+	if errs, err := validator.BindAndValidate(c, &req); err != nil {
+		response.InternalError(c)
+		return
+	} else if errs != nil {
+		response.UnprocessableEntity(c, errs)
+		return
+	}
+
+	webhook, err := h.webhookSvc.Update(c.Request.Context(), id, middleware.CurrentUserID(c), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, webhook)
+}
+
+// Delete godoc
+// @Summary Delete a webhook
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Success 200 {object} response.Envelope
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return
+	}
+
+	if err := h.webhookSvc.Delete(c.Request.Context(), id, middleware.CurrentUserID(c)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, gin.H{"message": "webhook deleted"})
+}
+
+// ListDeliveries godoc
+// @Summary List stored deliveries of a webhook
+// @Description Deliveries, including their payloads, are kept for 30 days.
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Envelope{data=[]domain.WebhookDelivery}
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return
+	}
+
+	pag := pagination.FromContext(c)
+	deliveries, total, err := h.webhookSvc.ListDeliveries(
+		c.Request.Context(), id, middleware.CurrentUserID(c), pag.Page, pag.Limit,
+	)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OKPaginated(c, deliveries, pag.Page, pag.Limit, total)
+}
+
+// GetDelivery godoc
+// @Summary Get a stored webhook delivery
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Param deliveryID path string true "Delivery UUID"
+// @Success 200 {object} response.Envelope{data=domain.WebhookDelivery}
+// @Router /webhooks/{id}/deliveries/{deliveryID} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, deliveryID, ok := h.parseDeliveryPath(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookSvc.GetDelivery(c.Request.Context(), id, deliveryID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.OK(c, delivery)
+}
+
+// Redeliver godoc
+// @Summary Redeliver a stored webhook payload
+// @Description Queues the original payload again, unchanged, as a new delivery.
+// @Tags webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Webhook UUID"
+// @Param deliveryID path string true "Delivery UUID"
+// @Success 202 {object} response.Envelope{data=domain.WebhookDelivery}
+// @Router /webhooks/{id}/deliveries/{deliveryID}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, deliveryID, ok := h.parseDeliveryPath(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookSvc.Redeliver(c.Request.Context(), id, deliveryID, middleware.CurrentUserID(c))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	response.Accepted(c, delivery)
+}
+
+func (h *WebhookHandler) parseDeliveryPath(c *gin.Context) (id, deliveryID uuid.UUID, ok bool) {
+	webhookID, err := parseUUID(c, "id")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid webhook id", nil)
+		return id, deliveryID, false
+	}
+	dID, err := parseUUID(c, "deliveryID")
+	if err != nil {
+		response.BadRequest(c, "INVALID_ID", "invalid delivery id", nil)
+		return id, deliveryID, false
+	}
+	return webhookID, dID, true
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		response.NotFound(c, "webhook not found")
+	case errors.Is(err, domain.ErrForbidden):
+		response.Forbidden(c, "you do not have access to this webhook")
+	default:
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,157 @@
+package holiday
+
+import "time"
+
+// maxShiftDays bounds the search for a business day; a year of days off is
+// treated as a misconfiguration rather than searched past.
+const maxShiftDays = 366
+
+// BusinessDays decides which calendar days, and which hours of them,
+// someone works.
+type BusinessDays struct {
+	Location *time.Location    // nil means UTC
+	Weekend  int               // bitmask, bit 0 = Sunday … bit 6 = Saturday
+	Calendar *Calendar         // public holidays, nil for none
+	DaysOff  map[string]string // personal days off, YYYY-MM-DD to name
+	// DayStart and DayEnd are the working hours of a business day as local
+	// clock offsets from midnight. A zero DayEnd means until midnight, so the
+	// zero value works all of every business day.
+	DayStart time.Duration
+	DayEnd   time.Duration
+}
+
+// Closed reports whether the local day of t is not a business day, and why:
+// "weekend", or the name of the holiday or day off.
+func (b BusinessDays) Closed(t time.Time) (string, bool) {
+	local := t.In(b.location())
+	if b.Weekend&(1<<local.Weekday()) != 0 {
+		return "weekend", true
+	}
+	if name, ok := b.DaysOff[local.Format(dateLayout)]; ok {
+		return name, true
+	}
+	if b.Calendar != nil {
+		if name, ok := b.Calendar.Lookup(local); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Next returns t moved forward by whole days to the first business day,
+// keeping its local time of day. It returns t itself when that is one.
+func (b BusinessDays) Next(t time.Time) time.Time {
+	local := t.In(b.location())
+	for i := 0; i < maxShiftDays; i++ {
+		if _, closed := b.Closed(local); !closed {
+			return local.In(t.Location())
+		}
+		local = local.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// WorkingTime returns how much of [from, to) falls in working hours.
+func (b BusinessDays) WorkingTime(from, to time.Time) time.Duration {
+	var total time.Duration
+	for cursor := from; cursor.Before(to); {
+		start, end, ok := b.period(cursor)
+		if !ok || !start.Before(to) {
+			break
+		}
+		if end.After(to) {
+			end = to
+		}
+		total += end.Sub(start)
+		cursor = end
+	}
+	return total
+}
+
+// AddWorkingTime returns the instant d of working time after from. It
+// returns the zero time when the working hours run out first.
+func (b BusinessDays) AddWorkingTime(from time.Time, d time.Duration) time.Time {
+	for cursor := from; ; {
+		start, end, ok := b.period(cursor)
+		if !ok {
+			return time.Time{}
+		}
+		left := end.Sub(start)
+		if d <= left {
+			return start.Add(d)
+		}
+		d -= left
+		cursor = end
+	}
+}
+
+// Slot is a stretch of working time.
+type Slot struct {
+	Start, End time.Time
+}
+
+// Slots suggests up to n stretches of d working time from from on, ending
+// no later than before unless it is zero. Each starts when a working period
+// does, or at from within the current one. With set working hours, a d that
+// fits in a working day is kept within one; a longer one starts at the
+// beginning of a day and carries on over the following ones.
+func (b BusinessDays) Slots(from time.Time, d time.Duration, before time.Time, n int) []Slot {
+	var slots []Slot
+	for cursor := from; len(slots) < n; {
+		start, end, ok := b.period(cursor)
+		if !ok || (!before.IsZero() && !start.Before(before)) {
+			break
+		}
+		cursor = end
+		if b.DayEnd > 0 && d > end.Sub(start) && d <= b.dayLength() {
+			continue // would be split across days; a fuller one comes later
+		}
+		finish := b.AddWorkingTime(start, d)
+		if finish.IsZero() || (!before.IsZero() && finish.After(before)) {
+			continue
+		}
+		slots = append(slots, Slot{Start: start, End: finish})
+	}
+	return slots
+}
+
+// period returns the first working period that ends after t, starting no
+// earlier than t. ok is false when none begins within maxShiftDays.
+func (b BusinessDays) period(t time.Time) (start, end time.Time, ok bool) {
+	local := t.In(b.location())
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+	for i := 0; i < maxShiftDays; i++ {
+		if _, closed := b.Closed(day); !closed {
+			start, end = b.clock(day, b.DayStart), b.clock(day, b.DayEnd)
+			if b.DayEnd <= 0 {
+				end = day.AddDate(0, 0, 1)
+			}
+			if end.After(t) {
+				if start.Before(t) {
+					start = t
+				}
+				return start, end, true
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// clock returns the local time of day off on day, so working hours keep to
+// the wall clock across DST changes.
+func (b BusinessDays) clock(day time.Time, off time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), int(off/time.Hour), int(off%time.Hour/time.Minute), 0, 0, day.Location())
+}
+
+// dayLength is the working time of a full business day with set hours.
+func (b BusinessDays) dayLength() time.Duration {
+	return b.DayEnd - b.DayStart
+}
+
+func (b BusinessDays) location() *time.Location {
+	if b.Location == nil {
+		return time.UTC
+	}
+	return b.Location
+}
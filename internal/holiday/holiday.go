@@ -0,0 +1,229 @@
+package holiday
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed calendars.yaml
+var builtin []byte
+
+// dateLayout is the format of holiday and day-off dates.
+const dateLayout = "2006-01-02"
+
+// Rule is one holiday of a calendar. Exactly one of Date, Annual, Weekday
+// or Easter selects the day it falls on.
+type Rule struct {
+	Name   string `yaml:"name"`
+	Date   string `yaml:"date,omitempty"`   // YYYY-MM-DD, that year only
+	Annual string `yaml:"annual,omitempty"` // MM-DD, every year
+	// Weekday, Month and Nth give e.g. the third Monday of January; a
+	// negative Nth counts from the end of the month, -1 being the last.
+	Weekday string `yaml:"weekday,omitempty"`
+	Month   int    `yaml:"month,omitempty"`
+	Nth     int    `yaml:"nth,omitempty"`
+	Easter  *int   `yaml:"easter,omitempty"` // days after Western Easter Sunday
+}
+
+// Calendar is a country's public holidays.
+type Calendar struct {
+	Country  string `yaml:"-" json:"country"` // ISO 3166-1 alpha-2, upper case
+	Name     string `yaml:"name" json:"name"`
+	Holidays []Rule `yaml:"holidays" json:"-"`
+}
+
+// Holiday is a holiday on a concrete date.
+type Holiday struct {
+	Date string `json:"date"` // YYYY-MM-DD
+	Name string `json:"name"`
+}
+
+// Calendars holds the country calendars of the deployment, keyed by
+// upper-case country code.
+type Calendars map[string]*Calendar
+
+// Load returns the built-in calendars, with the countries of the YAML file
+// at path added or replaced. An empty path loads the built-ins only.
+func Load(path string) (Calendars, error) {
+	cals, err := Parse(builtin)
+	if err != nil {
+		return nil, fmt.Errorf("holiday: built-in calendars: %w", err)
+	}
+	if path == "" {
+		return cals, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("holiday: %w", err)
+	}
+	extra, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("holiday: %s: %w", path, err)
+	}
+	for code, c := range extra {
+		cals[code] = c
+	}
+	return cals, nil
+}
+
+// Parse reads calendars from YAML keyed by country code.
+func Parse(data []byte) (Calendars, error) {
+	var raw map[string]*Calendar
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	cals := make(Calendars, len(raw))
+	for code, c := range raw {
+		code = strings.ToUpper(code)
+		if len(code) != 2 || c == nil {
+			return nil, fmt.Errorf("%q: want a two-letter country code with a calendar", code)
+		}
+		for i, r := range c.Holidays {
+			if err := r.validate(); err != nil {
+				return nil, fmt.Errorf("%s: holiday %d: %w", code, i+1, err)
+			}
+		}
+		c.Country = code
+		cals[code] = c
+	}
+	return cals, nil
+}
+
+// Get returns the calendar of a country, matching the code case-insensitively.
+func (cs Calendars) Get(country string) (*Calendar, bool) {
+	c, ok := cs[strings.ToUpper(country)]
+	return c, ok
+}
+
+// List returns every calendar ordered by country code.
+func (cs Calendars) List() []*Calendar {
+	out := make([]*Calendar, 0, len(cs))
+	for _, c := range cs {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Country < out[j].Country })
+	return out
+}
+
+// InYear returns the holidays of year in date order.
+func (c *Calendar) InYear(year int) []Holiday {
+	out := []Holiday{}
+	for _, r := range c.Holidays {
+		if d, ok := r.on(year); ok {
+			out = append(out, Holiday{Date: d.Format(dateLayout), Name: r.Name})
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+	return out
+}
+
+// Lookup returns the name of the holiday on the calendar date of t.
+func (c *Calendar) Lookup(t time.Time) (string, bool) {
+	y, m, d := t.Date()
+	for _, r := range c.Holidays {
+		if day, ok := r.on(y); ok && day.Month() == m && day.Day() == d {
+			return r.Name, true
+		}
+	}
+	return "", false
+}
+
+func (r Rule) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	set := 0
+	for _, s := range []bool{r.Date != "", r.Annual != "", r.Weekday != "", r.Easter != nil} {
+		if s {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("%s: exactly one of date, annual, weekday or easter must be set", r.Name)
+	}
+	switch {
+	case r.Date != "":
+		if _, err := time.Parse(dateLayout, r.Date); err != nil {
+			return fmt.Errorf("%s: date must be YYYY-MM-DD", r.Name)
+		}
+	case r.Annual != "":
+		if _, err := time.Parse("01-02", r.Annual); err != nil {
+			return fmt.Errorf("%s: annual must be MM-DD", r.Name)
+		}
+	case r.Weekday != "":
+		if _, ok := parseWeekday(r.Weekday); !ok {
+			return fmt.Errorf("%s: unknown weekday %q", r.Name, r.Weekday)
+		}
+		if r.Month < 1 || r.Month > 12 || r.Nth == 0 || r.Nth < -5 || r.Nth > 5 {
+			return fmt.Errorf("%s: weekday needs a month 1-12 and nth 1-5 or -1 to -5", r.Name)
+		}
+	}
+	return nil
+}
+
+// on returns the day the rule falls on in year, if any.
+func (r Rule) on(year int) (time.Time, bool) {
+	switch {
+	case r.Date != "":
+		d, err := time.Parse(dateLayout, r.Date)
+		return d, err == nil && d.Year() == year
+	case r.Annual != "":
+		d, err := time.Parse("01-02", r.Annual)
+		if err != nil {
+			return time.Time{}, false
+		}
+		day := time.Date(year, d.Month(), d.Day(), 0, 0, 0, 0, time.UTC)
+		// 02-29 only exists in leap years.
+		return day, day.Month() == d.Month()
+	case r.Weekday != "":
+		wd, _ := parseWeekday(r.Weekday)
+		return nthWeekday(year, time.Month(r.Month), wd, r.Nth)
+	case r.Easter != nil:
+		return easter(year).AddDate(0, 0, *r.Easter), true
+	}
+	return time.Time{}, false
+}
+
+// nthWeekday returns the nth wd of month, counting from the end when n < 0.
+func nthWeekday(year int, month time.Month, wd time.Weekday, n int) (time.Time, bool) {
+	var day time.Time
+	if n > 0 {
+		first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		day = first.AddDate(0, 0, (int(wd)-int(first.Weekday())+7)%7+7*(n-1))
+	} else {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		day = last.AddDate(0, 0, -((int(last.Weekday())-int(wd)+7)%7 + 7*(-n-1)))
+	}
+	return day, day.Month() == month
+}
+
+// easter returns Western Easter Sunday of year (anonymous Gregorian algorithm).
+func easter(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.EqualFold(s, wd.String()) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,152 @@
+package holiday_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/holiday"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_BuiltinCalendars(t *testing.T) {
+	cals, err := holiday.Load("")
+	require.NoError(t, err)
+
+	us, ok := cals.Get("us")
+	require.True(t, ok)
+	assert.Equal(t, []holiday.Holiday{
+		{Date: "2026-01-01", Name: "New Year's Day"},
+		{Date: "2026-01-19", Name: "Birthday of Martin Luther King, Jr."},
+		{Date: "2026-02-16", Name: "Washington's Birthday"},
+		{Date: "2026-05-25", Name: "Memorial Day"},
+		{Date: "2026-06-19", Name: "Juneteenth National Independence Day"},
+		{Date: "2026-07-04", Name: "Independence Day"},
+		{Date: "2026-09-07", Name: "Labor Day"},
+		{Date: "2026-10-12", Name: "Columbus Day"},
+		{Date: "2026-11-11", Name: "Veterans Day"},
+		{Date: "2026-11-26", Name: "Thanksgiving Day"},
+		{Date: "2026-12-25", Name: "Christmas Day"},
+	}, us.InYear(2026))
+
+	gb, ok := cals.Get("GB")
+	require.True(t, ok)
+	name, ok := gb.Lookup(time.Date(2026, 4, 3, 9, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Good Friday", name, "Easter Sunday 2026 is April 5")
+	name, ok = gb.Lookup(time.Date(2025, 4, 21, 9, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "Easter Monday", name, "Easter Sunday 2025 is April 20")
+}
+
+func TestLoad_FileAddsAndReplacesCountries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "holidays.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+id:
+  name: Indonesia
+  holidays:
+    - { name: "Hari Kemerdekaan", annual: "08-17" }
+    - { name: "Idul Fitri", date: "2026-03-20" }
+US:
+  name: United States (company)
+  holidays:
+    - { name: "Founders Day", annual: "03-01" }
+`), 0o600))
+
+	cals, err := holiday.Load(path)
+	require.NoError(t, err)
+
+	id, ok := cals.Get("ID")
+	require.True(t, ok)
+	assert.Equal(t, "ID", id.Country)
+	assert.Len(t, id.InYear(2026), 2)
+	assert.Len(t, id.InYear(2027), 1, "a dated holiday only applies to its year")
+
+	us, _ := cals.Get("US")
+	assert.Equal(t, []holiday.Holiday{{Date: "2026-03-01", Name: "Founders Day"}}, us.InYear(2026))
+	assert.Len(t, cals.List(), 4)
+}
+
+func TestParse_RejectsInvalidRules(t *testing.T) {
+	for name, doc := range map[string]string{
+		"two selectors":  `XX: {name: X, holidays: [{name: A, annual: "01-01", easter: 1}]}`,
+		"no selector":    `XX: {name: X, holidays: [{name: A}]}`,
+		"bad date":       `XX: {name: X, holidays: [{name: A, date: "2026-13-01"}]}`,
+		"bad weekday":    `XX: {name: X, holidays: [{name: A, weekday: funday, month: 1, nth: 1}]}`,
+		"missing nth":    `XX: {name: X, holidays: [{name: A, weekday: monday, month: 1}]}`,
+		"long country":   `XYZ: {name: X, holidays: []}`,
+		"unnamed":        `XX: {name: X, holidays: [{annual: "01-01"}]}`,
+		"not a calendar": `XX: [1, 2]`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := holiday.Parse([]byte(doc))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBusinessDays_Next(t *testing.T) {
+	cals, err := holiday.Load("")
+	require.NoError(t, err)
+	us, _ := cals.Get("US")
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	days := holiday.BusinessDays{
+		Location: ny,
+		Weekend:  1<<time.Saturday | 1<<time.Sunday,
+		Calendar: us,
+		DaysOff:  map[string]string{"2026-11-27": "Vacation"},
+	}
+
+	// Thanksgiving on Thursday, a day off on Friday, then the weekend.
+	due := time.Date(2026, 11, 26, 17, 0, 0, 0, ny)
+	reason, closed := days.Closed(due)
+	assert.True(t, closed)
+	assert.Equal(t, "Thanksgiving Day", reason)
+	assert.Equal(t, time.Date(2026, 11, 30, 17, 0, 0, 0, ny), days.Next(due))
+
+	// Days are counted in the user's zone: 02:00 UTC Saturday is still Friday in New York.
+	friday := time.Date(2026, 10, 17, 2, 0, 0, 0, time.UTC)
+	assert.Equal(t, friday, days.Next(friday))
+
+	// The local time of day survives the DST change on November 1st.
+	sat := time.Date(2026, 10, 31, 9, 0, 0, 0, ny)
+	assert.Equal(t, time.Date(2026, 11, 2, 9, 0, 0, 0, ny), days.Next(sat).In(ny))
+}
+
+func TestBusinessDays_WorkingTime(t *testing.T) {
+	days := holiday.BusinessDays{
+		Location: time.UTC,
+		Weekend:  1<<time.Saturday | 1<<time.Sunday,
+		DayStart: 9 * time.Hour,
+		DayEnd:   17 * time.Hour,
+	}
+	friday5pm := time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC)
+	monday9am := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	assert.Zero(t, days.WorkingTime(friday5pm, monday9am), "no working time over the weekend")
+	assert.Equal(t, 90*time.Minute, days.WorkingTime(friday5pm, monday9am.Add(90*time.Minute)))
+	assert.Equal(t, 16*time.Hour, days.WorkingTime(friday5pm.Add(-8*time.Hour), monday9am.Add(8*time.Hour)))
+
+	assert.Equal(t, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC), days.AddWorkingTime(friday5pm.Add(-time.Hour), 2*time.Hour))
+
+	// Friday at 4pm: two hours don't fit today, so the slots start on Monday.
+	slots := days.Slots(friday5pm.Add(-time.Hour), 2*time.Hour, time.Time{}, 2)
+	assert.Equal(t, []holiday.Slot{
+		{Start: monday9am, End: monday9am.Add(2 * time.Hour)},
+		{Start: monday9am.AddDate(0, 0, 1), End: monday9am.AddDate(0, 0, 1).Add(2 * time.Hour)},
+	}, slots)
+
+	// Twelve hours run over into a second day, and must finish by the deadline.
+	slots = days.Slots(monday9am, 12*time.Hour, time.Date(2026, 10, 21, 13, 0, 0, 0, time.UTC), 5)
+	assert.Equal(t, []holiday.Slot{
+		{Start: monday9am, End: time.Date(2026, 10, 20, 13, 0, 0, 0, time.UTC)},
+		{Start: monday9am.AddDate(0, 0, 1), End: time.Date(2026, 10, 21, 13, 0, 0, 0, time.UTC)},
+	}, slots)
+
+	// The zero value counts every hour.
+	assert.Equal(t, monday9am.Sub(friday5pm), holiday.BusinessDays{}.WorkingTime(friday5pm, monday9am))
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"strings"
 
+	"github.com/galihaleanda/todo-app/internal/domain"
 	pkgjwt "github.com/galihaleanda/todo-app/pkg/jwt"
 	"github.com/galihaleanda/todo-app/pkg/response"
 	"github.com/gin-gonic/gin"
@@ -11,8 +14,18 @@ import (
 
 const userIDKey = "user_id"
 
-// Auth is a Gin middleware that validates Bearer access tokens.
-func Auth(jwtManager *pkgjwt.Manager) gin.HandlerFunc {
+// TokenAuthenticator looks up the personal access token a request carries,
+// returning domain.ErrTokenInvalid for unknown, revoked or expired ones.
+type TokenAuthenticator func(ctx context.Context, token string) (*domain.PersonalAccessToken, error)
+
+// TokenScope returns the scope a personal access token needs on a route,
+// given its method and gin full path, or "" where such tokens are refused.
+type TokenScope func(method, route string) string
+
+// Auth is a Gin middleware that validates Bearer access tokens. With tokens
+// set it also accepts personal access tokens, on routes scopeFor gives a
+// scope the token holds.
+func Auth(jwtManager *pkgjwt.Manager, tokens TokenAuthenticator, scopeFor TokenScope) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -28,6 +41,11 @@ func Auth(jwtManager *pkgjwt.Manager) gin.HandlerFunc {
 			return
 		}
 
+		if tokens != nil && strings.HasPrefix(parts[1], domain.PersonalAccessTokenPrefix) {
+			authenticateToken(c, parts[1], tokens, scopeFor)
+			return
+		}
+
 		claims, err := jwtManager.ParseAccessToken(parts[1])
 		if err != nil {
 			response.Unauthorized(c, "invalid or expired access token")
@@ -40,8 +58,95 @@ func Auth(jwtManager *pkgjwt.Manager) gin.HandlerFunc {
 	}
 }
 
+func authenticateToken(c *gin.Context, token string, tokens TokenAuthenticator, scopeFor TokenScope) {
+	pat, err := tokens(c.Request.Context(), token)
+	if errors.Is(err, domain.ErrTokenInvalid) {
+		response.Unauthorized(c, "invalid, expired or revoked personal access token")
+		c.Abort()
+		return
+	}
+	if err != nil {
+		response.InternalError(c)
+		c.Abort()
+		return
+	}
+
+	scope := scopeFor(c.Request.Method, c.FullPath())
+	if scope == "" {
+		response.Forbidden(c, "personal access tokens cannot be used on this endpoint")
+		c.Abort()
+		return
+	}
+	if !pat.Allows(scope) {
+		response.Forbidden(c, "personal access token lacks the "+scope+" scope")
+		c.Abort()
+		return
+	}
+
+	c.Set(userIDKey, pat.UserID)
+	c.Next()
+}
+
+// OptionalAuth identifies the user when a valid Bearer access token is sent
+// and lets the request through anonymously otherwise, for endpoints that
+// must work before sign-in. Read the user with OptionalUserID.
+func OptionalAuth(jwtManager *pkgjwt.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "bearer") {
+			if claims, err := jwtManager.ParseAccessToken(parts[1]); err == nil {
+				c.Set(userIDKey, claims.UserID)
+			}
+		}
+		c.Next()
+	}
+}
+
+// OptionalUserID returns the user set by Auth or OptionalAuth, or nil for
+// anonymous requests.
+func OptionalUserID(c *gin.Context) *uuid.UUID {
+	if v, ok := c.Get(userIDKey); ok {
+		id := v.(uuid.UUID)
+		return &id
+	}
+	return nil
+}
+
 // CurrentUserID extracts the authenticated user's UUID from the gin context.
 // Panics if called outside of an Auth-protected route — by design.
 func CurrentUserID(c *gin.Context) uuid.UUID {
 	return c.MustGet(userIDKey).(uuid.UUID)
 }
+
+// AdminChecker reports whether a user holds the admin role.
+type AdminChecker func(ctx context.Context, userID uuid.UUID) (bool, error)
+
+// RequireAdmin rejects requests from non-admin users. Must run after Auth.
+func RequireAdmin(isAdmin AdminChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := isAdmin(c.Request.Context(), CurrentUserID(c))
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			response.InternalError(c)
+			c.Abort()
+			return
+		}
+		if !ok {
+			response.Forbidden(c, "admin access required")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireModule answers 503 while an admin has switched module off.
+func RequireModule(module string, enabled func(module string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !enabled(module) {
+			response.ServiceUnavailable(c, "this feature is turned off on this server")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
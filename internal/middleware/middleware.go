@@ -1,12 +1,43 @@
 package middleware
 
 import (
+	"regexp"
 	"time"
 
+	"github.com/galihaleanda/todo-app/pkg/response"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// RequestIDHeader carries the ID of each request in both directions. Clients
+// can quote it in bug reports to find the matching log lines.
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "request_id"
+
+// validRequestID accepts IDs from clients and proxies that are safe to log.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID tags each request with an ID, reusing a well-formed one sent in
+// X-Request-ID and generating one otherwise, and echoes it in the response.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// CurrentRequestID returns the ID assigned by RequestID, if any.
+func CurrentRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
 // RequestLogger logs each HTTP request with relevant fields using logrus.
 func RequestLogger(log *logrus.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -20,6 +51,7 @@ func RequestLogger(log *logrus.Logger) gin.HandlerFunc {
 		statusCode := c.Writer.Status()
 
 		entry := log.WithFields(logrus.Fields{
+			"request_id": CurrentRequestID(c),
 			"status":     statusCode,
 			"method":     c.Request.Method,
 			"path":       path,
@@ -40,17 +72,16 @@ func RequestLogger(log *logrus.Logger) gin.HandlerFunc {
 	}
 }
 
-// Recovery wraps gin's default panic recovery and logs the error.
+// Recovery wraps gin's default panic recovery and logs the error. The
+// client gets the standard error envelope unless the handler had already
+// started its response.
 func Recovery(log *logrus.Logger) gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, err any) {
-		log.WithField("panic", err).Error("recovered from panic")
-		c.AbortWithStatusJSON(500, gin.H{
-			"success": false,
-			"error": gin.H{
-				"code":    "INTERNAL_ERROR",
-				"message": "an unexpected error occurred",
-			},
-		})
+		log.WithFields(logrus.Fields{"panic": err, "request_id": CurrentRequestID(c)}).Error("recovered from panic")
+		if !c.Writer.Written() {
+			response.InternalError(c)
+		}
+		c.Abort()
 	})
 }
 
@@ -59,7 +90,8 @@ func CORS() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Device-ID")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, X-Device-ID, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID")
 		c.Header("Access-Control-Max-Age", "86400")
 
 		if c.Request.Method == "OPTIONS" {
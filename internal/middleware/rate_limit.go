@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts the requests one client made in the current window.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// RateLimit allows each client IP at most limit requests per window, using
+// fixed windows held in memory, so every instance keeps its own counts. A
+// limit of 0 or less lets every request through.
+func RateLimit(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	var mu sync.Mutex
+	clients := map[string]*rateWindow{}
+	lastSweep := time.Now()
+
+	return func(c *gin.Context) {
+		now := time.Now()
+		ip := c.ClientIP()
+
+		mu.Lock()
+		if now.Sub(lastSweep) >= window {
+			for k, w := range clients {
+				if now.Sub(w.start) >= window {
+					delete(clients, k)
+				}
+			}
+			lastSweep = now
+		}
+		w, ok := clients[ip]
+		if !ok || now.Sub(w.start) >= window {
+			w = &rateWindow{start: now}
+			clients[ip] = w
+		}
+		w.count++
+		allowed, retryAfter := w.count <= limit, w.start.Add(window).Sub(now)
+		mu.Unlock()
+
+		if !allowed {
+			response.TooManyRequests(c, "too many requests, try again later", retryAfter)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// LoadShed turns requests away with 503 and Retry-After while overloaded
+// reports true, for routes that can wait while the database is degraded.
+func LoadShed(overloaded func() (bool, time.Duration)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if shed, retryAfter := overloaded(); shed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			response.ServiceUnavailable(c, "the service is under heavy load, try again later")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
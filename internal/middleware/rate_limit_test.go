@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitedEngine serves one rate-limited route, trusting X-Forwarded-For
+// only from trustedProxies as handler.Router.Setup does.
+func limitedEngine(t *testing.T, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	require.NoError(t, engine.SetTrustedProxies(trustedProxies))
+	engine.POST("/register", middleware.RateLimit(2, time.Hour), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	return engine
+}
+
+func register(engine *gin.Engine, peer, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodPost, "/register", nil)
+	req.RemoteAddr = peer + ":40000"
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	rec := httptest.NewRecorder()
+	engine.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestRateLimit_SpoofedForwardedForDoesNotResetCount(t *testing.T) {
+	engine := limitedEngine(t, nil)
+
+	assert.Equal(t, http.StatusCreated, register(engine, "203.0.113.7", "198.51.100.1"))
+	assert.Equal(t, http.StatusCreated, register(engine, "203.0.113.7", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, register(engine, "203.0.113.7", "198.51.100.3"),
+		"a new X-Forwarded-For from the same peer is the same client")
+	assert.Equal(t, http.StatusCreated, register(engine, "203.0.113.8", ""))
+}
+
+func TestRateLimit_TrustedProxyForwardsClientIP(t *testing.T) {
+	engine := limitedEngine(t, []string{"10.0.0.0/8"})
+
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusCreated, register(engine, "10.0.0.5", "198.51.100.1"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, register(engine, "10.0.0.5", "198.51.100.1"))
+	assert.Equal(t, http.StatusCreated, register(engine, "10.0.0.5", "198.51.100.2"),
+		"clients behind the proxy are counted apart")
+}
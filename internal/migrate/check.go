@@ -0,0 +1,222 @@
+package migrate
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Severities of a finding. Unsafe findings stop the migration unless the
+// operator allows them.
+const (
+	SeverityUnsafe  = "unsafe"
+	SeverityWarning = "warning"
+)
+
+// Rough throughput of the work a statement does while it holds its lock,
+// for estimating how long it blocks. Real numbers depend on the hardware;
+// these are on the cautious side of a small cloud database.
+const (
+	scanBytesPerSec    = 200 << 20 // sequential scan, as in validating a constraint
+	rewriteBytesPerSec = 50 << 20  // rewriting the table and its indexes
+	indexRowsPerSec    = 500_000   // building an index
+)
+
+// TableStats describes an existing table, from the planner statistics.
+type TableStats struct {
+	Rows  int64
+	Bytes int64 // including indexes and TOAST
+}
+
+// Stats holds the existing tables by name. A table it does not list is
+// taken to be created by a pending migration, and so empty.
+type Stats map[string]TableStats
+
+// Finding is a statement that blocks or breaks a live deployment.
+type Finding struct {
+	Migration string        `json:"migration"`
+	Statement string        `json:"statement"`
+	Table     string        `json:"table"`
+	Severity  string        `json:"severity"`
+	Reason    string        `json:"reason"`
+	Lock      string        `json:"lock,omitempty"`
+	Estimate  time.Duration `json:"estimate,omitempty"` // how long the lock is held
+}
+
+// Checker finds the statements of pending migrations that are unsafe to
+// run while the API serves traffic.
+type Checker struct {
+	// MaxLock is the longest a statement may block the table it locks
+	// before it counts as unsafe.
+	MaxLock time.Duration
+}
+
+var (
+	alterTable  = regexp.MustCompile(`^ALTER TABLE (?:IF EXISTS )?(?:ONLY )?(\w+)`)
+	createIndex = regexp.MustCompile(`^CREATE (?:UNIQUE )?INDEX (CONCURRENTLY )?(?:IF NOT EXISTS )?(?:\w+ )?ON (?:ONLY )?(\w+)`)
+	dropTable   = regexp.MustCompile(`^DROP TABLE (?:IF EXISTS )?([\w, ]+?)(?: CASCADE| RESTRICT)?$`)
+	wholeTable  = regexp.MustCompile(`^(TRUNCATE|VACUUM FULL|CLUSTER|REINDEX|LOCK)(?: TABLE)? (?:ONLY )?(\w+)`)
+
+	alterType     = regexp.MustCompile(`ALTER (?:COLUMN )?\w+ (?:SET DATA )?TYPE `)
+	setNotNull    = regexp.MustCompile(`ALTER (?:COLUMN )?\w+ SET NOT NULL`)
+	addColumn     = regexp.MustCompile(`ADD (?:COLUMN )?(?:IF NOT EXISTS )?\w+ [^,]*`)
+	addValidated  = regexp.MustCompile(`ADD (?:CONSTRAINT \w+ )?(?:FOREIGN KEY|CHECK)\b`)
+	addIndexed    = regexp.MustCompile(`ADD (?:CONSTRAINT \w+ )?(?:PRIMARY KEY|UNIQUE)\b`)
+	dropOrRename  = regexp.MustCompile(`\b(?:DROP|RENAME) (?:COLUMN |TO )?(?:IF EXISTS )?(\w+)`)
+	volatileValue = regexp.MustCompile(`(?i)\b(?:uuid_generate_v\d|gen_random_uuid|random|clock_timestamp|timeofday|nextval)\s*\(`)
+)
+
+// Check returns the findings of one migration, SQL parsed from the
+// statements against the existing tables in stats.
+func (c Checker) Check(m Migration, stats Stats) []Finding {
+	var out []Finding
+	for _, raw := range Statements(m.SQL) {
+		stmt := normalize(raw)
+		for _, f := range c.checkStatement(stmt, stats) {
+			f.Migration, f.Statement = m.Name, raw
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+func (c Checker) checkStatement(stmt string, stats Stats) []Finding {
+	upper := strings.ToUpper(stmt)
+
+	if m := createIndex.FindStringSubmatch(upper); m != nil {
+		table := strings.ToLower(m[2])
+		s, ok := stats[table]
+		if !ok || m[1] != "" {
+			return nil
+		}
+		return []Finding{c.locked(table, "SHARE (blocks writes)", perRows(s.Rows, indexRowsPerSec),
+			"builds the index without CONCURRENTLY")}
+	}
+
+	if m := dropTable.FindStringSubmatch(upper); m != nil {
+		var out []Finding
+		for _, name := range strings.Split(m[1], ",") {
+			table := strings.ToLower(strings.TrimSpace(name))
+			if _, ok := stats[table]; ok {
+				out = append(out, Finding{Table: table, Severity: SeverityUnsafe,
+					Reason: "drops a table instances still running the previous release use"})
+			}
+		}
+		return out
+	}
+
+	if m := wholeTable.FindStringSubmatch(upper); m != nil {
+		table := strings.ToLower(m[2])
+		s, ok := stats[table]
+		if !ok {
+			return nil
+		}
+		switch m[1] {
+		case "TRUNCATE":
+			if s.Rows == 0 {
+				return nil
+			}
+			return []Finding{{Table: table, Severity: SeverityUnsafe, Reason: "empties a table with rows"}}
+		case "LOCK":
+			return []Finding{{Table: table, Severity: SeverityUnsafe, Lock: "ACCESS EXCLUSIVE",
+				Reason: "locks the table explicitly until the migration commits"}}
+		}
+		return []Finding{c.locked(table, "ACCESS EXCLUSIVE", perBytes(s.Bytes, rewriteBytesPerSec),
+			m[1]+" rewrites the table")}
+	}
+
+	m := alterTable.FindStringSubmatch(upper)
+	if m == nil {
+		return nil
+	}
+	table := strings.ToLower(m[1])
+	s, ok := stats[table]
+	if !ok {
+		return nil
+	}
+	actions := upper[len(m[0]):]
+	var out []Finding
+	if alterType.MatchString(actions) {
+		out = append(out, c.locked(table, "ACCESS EXCLUSIVE", perBytes(s.Bytes, rewriteBytesPerSec),
+			"changes a column type, which rewrites the table unless the types are binary compatible"))
+	}
+	if setNotNull.MatchString(actions) {
+		out = append(out, c.locked(table, "ACCESS EXCLUSIVE", perBytes(s.Bytes, scanBytesPerSec),
+			"scans the table to check SET NOT NULL; add a NOT VALID CHECK (col IS NOT NULL) and validate it first"))
+	}
+	for _, add := range addColumn.FindAllString(actions, -1) {
+		if addValidated.MatchString(add) || addIndexed.MatchString(add) {
+			continue
+		}
+		hasDefault := strings.Contains(add, " DEFAULT ")
+		switch {
+		case hasDefault && volatileValue.MatchString(add):
+			out = append(out, c.locked(table, "ACCESS EXCLUSIVE", perBytes(s.Bytes, rewriteBytesPerSec),
+				"adds a column with a volatile default, which rewrites the table"))
+		case !hasDefault && strings.Contains(add, " NOT NULL") && s.Rows > 0:
+			out = append(out, Finding{Table: table, Severity: SeverityUnsafe,
+				Reason: "adds a NOT NULL column without a default, which fails on a table with rows"})
+		}
+	}
+	if addValidated.MatchString(actions) && !strings.Contains(actions, "NOT VALID") {
+		out = append(out, c.locked(table, "SHARE ROW EXCLUSIVE (blocks writes)", perBytes(s.Bytes, scanBytesPerSec),
+			"validates a new constraint against every row; add it NOT VALID, then VALIDATE CONSTRAINT"))
+	}
+	if addIndexed.MatchString(actions) && !strings.Contains(actions, "USING INDEX") {
+		out = append(out, c.locked(table, "ACCESS EXCLUSIVE", perRows(s.Rows, indexRowsPerSec),
+			"builds a unique index under the lock; build it CONCURRENTLY and add the constraint USING INDEX"))
+	}
+	for _, d := range dropOrRename.FindAllStringSubmatch(actions, -1) {
+		switch d[1] {
+		case "DEFAULT", "NOT", "CONSTRAINT", "IDENTITY", "EXPRESSION":
+			// Old code does not depend on these.
+			continue
+		}
+		out = append(out, Finding{Table: table, Severity: SeverityUnsafe,
+			Reason: "drops or renames a column or table instances still running the previous release use"})
+		break
+	}
+	return out
+}
+
+// locked is a finding for a statement holding lock on table for about
+// estimate, unsafe once that exceeds MaxLock.
+func (c Checker) locked(table, lock string, estimate time.Duration, reason string) Finding {
+	severity := SeverityWarning
+	if estimate > c.MaxLock {
+		severity = SeverityUnsafe
+	}
+	return Finding{Table: table, Severity: severity, Reason: reason, Lock: lock, Estimate: estimate}
+}
+
+// Unsafe reports whether any finding is unsafe.
+func Unsafe(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityUnsafe {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Finding) String() string {
+	s := fmt.Sprintf("%s %s: %s", f.Severity, f.Table, f.Reason)
+	if f.Lock != "" {
+		s += fmt.Sprintf(" (%s lock, about %s)", f.Lock, f.Estimate.Round(time.Millisecond))
+	}
+	return s
+}
+
+func perBytes(bytes, perSec int64) time.Duration {
+	return time.Duration(float64(bytes) / float64(perSec) * float64(time.Second))
+}
+
+func perRows(rows, perSec int64) time.Duration {
+	return time.Duration(float64(rows) / float64(perSec) * float64(time.Second))
+}
+
+// normalize collapses whitespace and drops quotes around identifiers.
+func normalize(stmt string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(stmt, `"`, "")), " ")
+}
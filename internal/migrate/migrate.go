@@ -0,0 +1,120 @@
+// Package migrate applies the schema migrations and, before it does, checks
+// the pending ones for statements that would hold long locks on, rewrite or
+// break tables a running deployment depends on.
+package migrate
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Migration is one numbered migration.
+type Migration struct {
+	Version int
+	Name    string // e.g. 058_add_projects_icon
+	SQL     string
+}
+
+var header = regexp.MustCompile(`^-- migrations/((\d+)_\w+)\.sql\s*$`)
+
+// Parse splits a schema file into its migrations, each starting at a
+// "-- migrations/NNN_name.sql" header. Versions must increase.
+func Parse(schema string) ([]Migration, error) {
+	var out []Migration
+	var body strings.Builder
+	flush := func() {
+		if len(out) > 0 {
+			out[len(out)-1].SQL = strings.TrimSpace(body.String())
+		}
+		body.Reset()
+	}
+	for i, line := range strings.Split(schema, "\n") {
+		m := header.FindStringSubmatch(line)
+		if m == nil {
+			if len(out) == 0 && strings.TrimSpace(line) != "" {
+				return nil, fmt.Errorf("migrate: line %d: SQL before the first migration header", i+1)
+			}
+			body.WriteString(line)
+			body.WriteByte('\n')
+			continue
+		}
+		flush()
+		version, _ := strconv.Atoi(m[2])
+		if len(out) > 0 && version <= out[len(out)-1].Version {
+			return nil, fmt.Errorf("migrate: line %d: migration %s does not follow %s", i+1, m[1], out[len(out)-1].Name)
+		}
+		out = append(out, Migration{Version: version, Name: m[1]})
+	}
+	flush()
+	return out, nil
+}
+
+// Statements splits SQL into statements at semicolons outside quotes,
+// dollar-quoted bodies and comments. Comments are dropped.
+func Statements(sql string) []string {
+	var out []string
+	var stmt strings.Builder
+	emit := func() {
+		if s := strings.TrimSpace(stmt.String()); s != "" {
+			out = append(out, s)
+		}
+		stmt.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end
+				stmt.WriteByte('\n')
+			}
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				i = len(sql)
+			} else {
+				i += end + 3
+			}
+			stmt.WriteByte(' ')
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(sql) && sql[end] != c {
+				end++
+			}
+			stmt.WriteString(sql[i:min(end+1, len(sql))])
+			i = end
+		case c == '$':
+			tag := dollarTag(sql[i:])
+			if tag == "" {
+				stmt.WriteByte(c)
+				continue
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				end = len(sql) - i - len(tag)
+			} else {
+				end += len(tag)
+			}
+			stmt.WriteString(sql[i : i+len(tag)+end])
+			i += len(tag) + end - 1
+		case c == ';':
+			emit()
+		default:
+			stmt.WriteByte(c)
+		}
+	}
+	emit()
+	return out
+}
+
+var dollarQuote = regexp.MustCompile(`^\$\w*\$`)
+
+// dollarTag returns the $tag$ opening s, or "" if s does not open one.
+func dollarTag(s string) string {
+	return dollarQuote.FindString(s)
+}
@@ -0,0 +1,112 @@
+package migrate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/migrate"
+	"github.com/galihaleanda/todo-app/migrations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_SchemaFile(t *testing.T) {
+	all, err := migrate.Parse(migrations.Schema)
+	require.NoError(t, err)
+	require.NotEmpty(t, all)
+
+	assert.Equal(t, 1, all[0].Version)
+	for i, m := range all {
+		assert.Equal(t, i+1, m.Version, "migrations are numbered without gaps")
+		assert.NotEmpty(t, m.SQL, m.Name)
+	}
+
+	// From scratch every table is new, so nothing is unsafe.
+	checker := migrate.Checker{MaxLock: time.Second}
+	for _, m := range all {
+		assert.Empty(t, checker.Check(m, migrate.Stats{}), m.Name)
+	}
+}
+
+func TestParse_RejectsOutOfOrder(t *testing.T) {
+	_, err := migrate.Parse("-- migrations/002_b.sql\nSELECT 1;\n-- migrations/001_a.sql\nSELECT 1;\n")
+	assert.Error(t, err)
+
+	_, err = migrate.Parse("SELECT 1;\n-- migrations/001_a.sql\n")
+	assert.Error(t, err, "SQL before the first header")
+}
+
+func TestStatements(t *testing.T) {
+	sql := `
+-- leading comment; with a semicolon
+CREATE TABLE a (note TEXT DEFAULT 'x; y');
+CREATE FUNCTION f() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = NOW(); RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+/* block; comment */ DROP TABLE b`
+
+	got := migrate.Statements(sql)
+	require.Len(t, got, 3)
+	assert.Equal(t, `CREATE TABLE a (note TEXT DEFAULT 'x; y')`, got[0])
+	assert.Contains(t, got[1], "RETURN NEW;\nEND;\n$$ LANGUAGE plpgsql")
+	assert.Equal(t, "DROP TABLE b", got[2])
+}
+
+func TestChecker(t *testing.T) {
+	stats := migrate.Stats{
+		"tasks": {Rows: 50_000_000, Bytes: 20 << 30},
+		"tags":  {Rows: 100, Bytes: 64 << 10},
+	}
+	checker := migrate.Checker{MaxLock: 2 * time.Second}
+
+	cases := []struct {
+		name     string
+		sql      string
+		severity string // "" for no finding
+	}{
+		{"index on a big table", "CREATE INDEX idx_tasks_x ON tasks(x);", migrate.SeverityUnsafe},
+		{"index concurrently", "CREATE INDEX CONCURRENTLY idx_tasks_x ON tasks(x);", ""},
+		{"index on a small table", "CREATE INDEX idx_tags_x ON tags(x);", migrate.SeverityWarning},
+		{"index on a new table", "CREATE INDEX idx_new_x ON new_table(x);", ""},
+		{"type change", "ALTER TABLE tasks ALTER COLUMN title TYPE TEXT;", migrate.SeverityUnsafe},
+		{"set not null", "ALTER TABLE tasks ALTER COLUMN title SET NOT NULL;", migrate.SeverityUnsafe},
+		{"constant default", "ALTER TABLE tasks ADD COLUMN IF NOT EXISTS icon VARCHAR(32) NOT NULL DEFAULT '';", ""},
+		{"volatile default", "ALTER TABLE tasks ADD COLUMN token UUID NOT NULL DEFAULT uuid_generate_v4();", migrate.SeverityUnsafe},
+		{"not null without default", "ALTER TABLE tags ADD COLUMN icon TEXT NOT NULL;", migrate.SeverityUnsafe},
+		{"foreign key", "ALTER TABLE tasks ADD CONSTRAINT fk_x FOREIGN KEY (x) REFERENCES tags(id);", migrate.SeverityUnsafe},
+		{"foreign key not valid", "ALTER TABLE tasks ADD CONSTRAINT fk_x FOREIGN KEY (x) REFERENCES tags(id) NOT VALID;", ""},
+		{"drop column", "ALTER TABLE tags DROP COLUMN IF EXISTS color;", migrate.SeverityUnsafe},
+		{"drop default", "ALTER TABLE tasks ALTER COLUMN title DROP DEFAULT;", ""},
+		{"drop constraint", "ALTER TABLE tasks DROP CONSTRAINT IF EXISTS tasks_status_check;", ""},
+		{"rename", "ALTER TABLE tags RENAME TO labels;", migrate.SeverityUnsafe},
+		{"drop table", "DROP TABLE IF EXISTS tags;", migrate.SeverityUnsafe},
+		{"truncate", "TRUNCATE tags;", migrate.SeverityUnsafe},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			findings := checker.Check(migrate.Migration{Name: "059_test", SQL: tc.sql}, stats)
+			if tc.severity == "" {
+				assert.Empty(t, findings)
+				return
+			}
+			require.Len(t, findings, 1)
+			assert.Equal(t, tc.severity, findings[0].Severity, findings[0].String())
+			assert.Equal(t, "059_test", findings[0].Migration)
+		})
+	}
+}
+
+func TestChecker_EstimatesFromStats(t *testing.T) {
+	m := migrate.Migration{Name: "059_test", SQL: "ALTER TABLE tasks ALTER COLUMN title TYPE TEXT;"}
+	checker := migrate.Checker{MaxLock: time.Hour}
+
+	small := checker.Check(m, migrate.Stats{"tasks": {Rows: 1000, Bytes: 1 << 20}})
+	big := checker.Check(m, migrate.Stats{"tasks": {Rows: 50_000_000, Bytes: 20 << 30}})
+	require.Len(t, small, 1)
+	require.Len(t, big, 1)
+	assert.Less(t, small[0].Estimate, big[0].Estimate)
+	assert.Equal(t, migrate.SeverityWarning, big[0].Severity, "under MaxLock it is only a warning")
+	assert.False(t, migrate.Unsafe(big))
+}
@@ -0,0 +1,187 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// ErrUntracked means the database holds the schema but no record of which
+// migrations built it, as when docker initdb applied the schema file. The
+// operator records them with Baseline before anything is applied.
+var ErrUntracked = errors.New("migrate: the schema exists but applied_migrations is empty; record the applied migrations with --baseline")
+
+var concurrently = regexp.MustCompile(`(?i)\bCONCURRENTLY\b`)
+
+// Runner applies migrations to a database, recording each in
+// applied_migrations.
+type Runner struct {
+	db *sqlx.DB
+}
+
+// NewRunner creates a Runner for db.
+func NewRunner(db *sqlx.DB) *Runner {
+	return &Runner{db: db}
+}
+
+func (r *Runner) ensureTable(ctx context.Context) error {
+	_, err := r.db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS applied_migrations (
+			version    INT         PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+		)`)
+	return err
+}
+
+// Pending returns the migrations of all not applied yet, in order.
+func (r *Runner) Pending(ctx context.Context, all []Migration) ([]Migration, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return nil, fmt.Errorf("migrate.Pending: %w", err)
+	}
+	var applied []int
+	if err := r.db.SelectContext(ctx, &applied, `SELECT version FROM applied_migrations`); err != nil {
+		return nil, fmt.Errorf("migrate.Pending: %w", err)
+	}
+	if len(applied) == 0 {
+		var untracked bool
+		if err := r.db.GetContext(ctx, &untracked, `SELECT to_regclass('users') IS NOT NULL`); err != nil {
+			return nil, fmt.Errorf("migrate.Pending: %w", err)
+		}
+		if untracked {
+			return nil, ErrUntracked
+		}
+	}
+
+	done := make(map[int]bool, len(applied))
+	for _, v := range applied {
+		done[v] = true
+	}
+	var pending []Migration
+	for _, m := range all {
+		if !done[m.Version] {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// Stats reads the size of every table in the current schema. The row
+// count is the planner's estimate, or one row per 8kB page for a table
+// never analysed.
+func (r *Runner) Stats(ctx context.Context) (Stats, error) {
+	var rows []struct {
+		Name  string `db:"name"`
+		Rows  int64  `db:"rows"`
+		Bytes int64  `db:"bytes"`
+	}
+	query := `
+		SELECT c.relname AS name,
+		       CASE WHEN c.reltuples >= 0 THEN c.reltuples::BIGINT
+		            ELSE pg_relation_size(c.oid) / 8192 END AS rows,
+		       pg_total_relation_size(c.oid) AS bytes
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND n.nspname = current_schema()`
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("migrate.Stats: %w", err)
+	}
+	stats := make(Stats, len(rows))
+	for _, row := range rows {
+		stats[row.Name] = TableStats{Rows: row.Rows, Bytes: row.Bytes}
+	}
+	return stats, nil
+}
+
+// Apply runs m and records it. A statement waiting longer than lockTimeout
+// for its lock fails the migration instead of queueing every query behind
+// it; zero waits indefinitely.
+//
+// A migration is applied in one transaction, except one building an index
+// CONCURRENTLY, which PostgreSQL refuses inside a transaction: its
+// statements run one by one, and a failure leaves the ones before applied.
+func (r *Runner) Apply(ctx context.Context, m Migration, lockTimeout time.Duration) error {
+	if err := r.ensureTable(ctx); err != nil {
+		return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+	}
+	timeout := fmt.Sprintf(`'%dms'`, lockTimeout.Milliseconds())
+
+	if concurrently.MatchString(m.SQL) {
+		conn, err := r.db.Connx(ctx)
+		if err != nil {
+			return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+		}
+		defer conn.Close()
+		if _, err := conn.ExecContext(ctx, `SET lock_timeout = `+timeout); err != nil {
+			return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+		}
+		// The setting belongs to the session, which goes back to the pool.
+		defer conn.ExecContext(context.WithoutCancel(ctx), `RESET lock_timeout`) //nolint:errcheck
+		for _, stmt := range Statements(m.SQL) {
+			if _, err := conn.ExecContext(ctx, stmt); err != nil {
+				return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+			}
+		}
+		if _, err := conn.ExecContext(ctx, `INSERT INTO applied_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+			return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+		}
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("migrate.Apply %s begin: %w", m.Name, err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `SET LOCAL lock_timeout = `+timeout); err != nil {
+		return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO applied_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+		return fmt.Errorf("migrate.Apply %s: %w", m.Name, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("migrate.Apply %s commit: %w", m.Name, err)
+	}
+	return nil
+}
+
+// Baseline records the migrations of all up to and including version as
+// applied without running them, and returns how many it recorded.
+func (r *Runner) Baseline(ctx context.Context, all []Migration, version int) (int, error) {
+	if err := r.ensureTable(ctx); err != nil {
+		return 0, fmt.Errorf("migrate.Baseline: %w", err)
+	}
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("migrate.Baseline begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	n := 0
+	for _, m := range all {
+		if m.Version > version {
+			break
+		}
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO applied_migrations (version, name) VALUES ($1, $2)
+			ON CONFLICT (version) DO NOTHING`, m.Version, m.Name)
+		if err != nil {
+			return 0, fmt.Errorf("migrate.Baseline: %w", err)
+		}
+		if rows, _ := res.RowsAffected(); rows > 0 {
+			n++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("migrate.Baseline commit: %w", err)
+	}
+	return n, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
@@ -27,9 +28,9 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 		SELECT
 			COUNT(*) AS total,
 			COUNT(*) FILTER (WHERE status = 'done') AS completed,
-			COUNT(*) FILTER (WHERE due_date < NOW() AND status != 'done') AS overdue
+			COUNT(*) FILTER (WHERE `+taskDeadlineSQL("due_date", userTimezoneSQL("$1"))+` < NOW() AND status != 'done') AS overdue
 		FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL`, userID,
+		WHERE user_id = $1 AND deleted_at IS NULL AND `+taskLiveProjectWhere+``, userID,
 	).Scan(&dash.TotalTasks, &dash.CompletedTasks, &dash.OverdueTasks)
 	if err != nil {
 		return nil, fmt.Errorf("analyticsRepository.GetDashboard totals: %w", err)
@@ -43,7 +44,7 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 	weekStart := time.Now().AddDate(0, 0, -7)
 	err = r.db.QueryRowContext(ctx, `
 		SELECT COUNT(*) FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL
+		WHERE user_id = $1 AND deleted_at IS NULL AND `+taskLiveProjectWhere+`
 		  AND status = 'done' AND completed_at >= $2`, userID, weekStart,
 	).Scan(&dash.CompletedThisWeek)
 	if err != nil {
@@ -54,7 +55,7 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 	err = r.db.QueryRowContext(ctx, `
 		SELECT COALESCE(AVG(EXTRACT(EPOCH FROM (completed_at - created_at)) / 3600), 0)
 		FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL AND status = 'done' AND completed_at IS NOT NULL`, userID,
+		WHERE user_id = $1 AND deleted_at IS NULL AND status = 'done' AND completed_at IS NOT NULL AND `+taskLiveProjectWhere+``, userID,
 	).Scan(&dash.AvgCompletionTimeHours)
 	if err != nil {
 		return nil, fmt.Errorf("analyticsRepository.GetDashboard avg time: %w", err)
@@ -64,7 +65,7 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 	err = r.db.QueryRowContext(ctx, `
 		SELECT TO_CHAR(completed_at, 'Day')
 		FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL AND status = 'done' AND completed_at IS NOT NULL
+		WHERE user_id = $1 AND deleted_at IS NULL AND status = 'done' AND completed_at IS NOT NULL AND `+taskLiveProjectWhere+`
 		GROUP BY TO_CHAR(completed_at, 'Day'), EXTRACT(DOW FROM completed_at)
 		ORDER BY COUNT(*) DESC
 		LIMIT 1`, userID,
@@ -81,12 +82,26 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 			COUNT(*) FILTER (WHERE priority = 'medium') AS medium,
 			COUNT(*) FILTER (WHERE priority = 'low') AS low
 		FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL AND status != 'done'`, userID,
+		WHERE user_id = $1 AND deleted_at IS NULL AND status != 'done' AND `+taskLiveProjectWhere+``, userID,
 	).Scan(&dash.HighPriorityPending, &dash.MediumPriorityPending, &dash.LowPriorityPending)
 	if err != nil {
 		return nil, fmt.Errorf("analyticsRepository.GetDashboard priority: %w", err)
 	}
 
+	// Recurring task adherence (last 30 days)
+	err = r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE o.on_time)
+		FROM task_occurrences o
+		JOIN tasks t ON t.id = o.task_id AND t.deleted_at IS NULL AND `+taskLiveProjectWhere+`
+		WHERE o.user_id = $1 AND o.completed_at >= $2`, userID, time.Now().AddDate(0, 0, -30),
+	).Scan(&dash.RecurringCompleted, &dash.RecurringOnTime)
+	if err != nil {
+		return nil, fmt.Errorf("analyticsRepository.GetDashboard recurrence: %w", err)
+	}
+	if dash.RecurringCompleted > 0 {
+		dash.RecurrenceAdherence = float64(dash.RecurringOnTime) / float64(dash.RecurringCompleted) * 100
+	}
+
 	// Weekly breakdown
 	daily, err := r.GetDailyStats(ctx, userID, weekStart, time.Now())
 	if err != nil {
@@ -97,6 +112,40 @@ func (r *analyticsRepository) GetDashboard(ctx context.Context, userID uuid.UUID
 	return dash, nil
 }
 
+func (r *analyticsRepository) StreamRawEvents(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*domain.AnalyticsRawEvent) error) error {
+	const columns = `id AS task_id, project_id, parent_id, priority, estimated_hours::float8 AS estimated_hours,
+		actual_hours::float8 AS actual_hours, due_date, recurrence IS NOT NULL AS recurring`
+	query := `
+		SELECT '` + domain.AnalyticsEventCreated + `' AS event, created_at AS at, ` + columns + `
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND ` + taskLiveProjectWhere + `
+		  AND created_at >= $2 AND created_at < $3
+		UNION ALL
+		SELECT '` + domain.AnalyticsEventCompleted + `', completed_at, ` + columns + `
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND ` + taskLiveProjectWhere + `
+		  AND status = 'done' AND completed_at >= $2 AND completed_at < $3
+		ORDER BY at, event DESC`
+	rows, err := r.db.QueryxContext(ctx, query, userID, from, to)
+	if err != nil {
+		return fmt.Errorf("analyticsRepository.StreamRawEvents: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var e domain.AnalyticsRawEvent
+		if err := rows.StructScan(&e); err != nil {
+			return fmt.Errorf("analyticsRepository.StreamRawEvents: %w", err)
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("analyticsRepository.StreamRawEvents: %w", err)
+	}
+	return nil
+}
+
 func (r *analyticsRepository) GetDailyStats(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]domain.DailyStats, error) {
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
@@ -105,7 +154,7 @@ func (r *analyticsRepository) GetDailyStats(ctx context.Context, userID uuid.UUI
 			COUNT(*) FILTER (WHERE DATE(created_at) = DATE(completed_at)) AS created,
 			COALESCE(AVG(EXTRACT(EPOCH FROM (completed_at - created_at)) / 3600) FILTER (WHERE status = 'done'), 0) AS avg_completion_time_hours
 		FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL
+		WHERE user_id = $1 AND deleted_at IS NULL AND `+taskLiveProjectWhere+`
 		  AND completed_at BETWEEN $2 AND $3
 		GROUP BY DATE(completed_at)
 		ORDER BY DATE(completed_at) ASC`, userID, from, to)
@@ -124,3 +173,121 @@ func (r *analyticsRepository) GetDailyStats(ctx context.Context, userID uuid.UUI
 	}
 	return stats, rows.Err()
 }
+
+// analyticsMetric is the SQL behind one domain.Metric* value.
+type analyticsMetric struct {
+	value  string // aggregate expression
+	column string // timestamp the range and time groupings apply to
+	where  string // extra condition
+}
+
+var analyticsMetrics = map[string]analyticsMetric{
+	domain.MetricTasksCompleted: {value: "COUNT(*)", column: "t.completed_at", where: "t.status = 'done'"},
+	domain.MetricTasksCreated:   {value: "COUNT(*)", column: "t.created_at", where: "TRUE"},
+	domain.MetricTasksOverdue:   {value: "COUNT(*)", column: "t.due_date", where: "t.status != 'done' AND " + taskDeadlineSQL("t.due_date", "$2::text") + " < NOW()"},
+	domain.MetricAvgCompletionHours: {
+		value:  "COALESCE(AVG(EXTRACT(EPOCH FROM (t.completed_at - t.created_at)) / 3600), 0)",
+		column: "t.completed_at",
+		where:  "t.status = 'done' AND t.completed_at IS NOT NULL",
+	},
+}
+
+// analyticsTimeFormats maps time groupings to date_trunc units and labels.
+var analyticsTimeFormats = map[string][2]string{
+	domain.GroupByDay:   {"day", "YYYY-MM-DD"},
+	domain.GroupByWeek:  {"week", `IYYY-"W"IW`},
+	domain.GroupByMonth: {"month", "YYYY-MM"},
+}
+
+func (r *analyticsRepository) Query(ctx context.Context, userID uuid.UUID, q domain.AnalyticsQuery) ([]domain.AnalyticsRow, error) {
+	metric, ok := analyticsMetrics[q.Metric]
+	if !ok {
+		return nil, fmt.Errorf("analyticsRepository.Query: unknown metric %q: %w", q.Metric, domain.ErrValidation)
+	}
+
+	// $1 user, $2 time zone; range bounds follow.
+	args := []any{userID, q.Timezone}
+	conditions := []string{"t.user_id = $1", "t.deleted_at IS NULL", taskLiveProjectWhere, metric.where}
+	if q.From != nil {
+		args = append(args, *q.From)
+		conditions = append(conditions, fmt.Sprintf("%s >= $%d", metric.column, len(args)))
+	}
+	if q.To != nil {
+		args = append(args, *q.To)
+		conditions = append(conditions, fmt.Sprintf("%s < $%d", metric.column, len(args)))
+	}
+
+	// Ungrouped queries still reference $2 so Postgres can infer its type.
+	group, join, order := "NULLIF($2::text, $2::text)", "", "value DESC"
+	switch q.GroupBy {
+	case "":
+	case domain.GroupByProject:
+		group, join = "COALESCE(p.name, 'No project')", "LEFT JOIN projects p ON p.id = t.project_id"
+	case domain.GroupByPriority:
+		group = "t.priority::text"
+	case domain.GroupByStatus:
+		group = "t.status::text"
+	default:
+		f, ok := analyticsTimeFormats[q.GroupBy]
+		if !ok {
+			return nil, fmt.Errorf("analyticsRepository.Query: unknown grouping %q: %w", q.GroupBy, domain.ErrValidation)
+		}
+		group = fmt.Sprintf("to_char(date_trunc('%s', %s AT TIME ZONE $2::text), '%s')", f[0], metric.column, f[1])
+		order = "grp"
+	}
+	query := fmt.Sprintf(`
+		SELECT COALESCE(%s, '') AS grp, %s AS value
+		FROM tasks t %s
+		WHERE %s
+		GROUP BY 1
+		ORDER BY %s`,
+		group, metric.value, join, strings.Join(conditions, " AND "), order)
+
+	rows := []domain.AnalyticsRow{}
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, fmt.Errorf("analyticsRepository.Query: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *analyticsRepository) EstimateAccuracy(ctx context.Context, userID uuid.UUID, since time.Time) (*domain.EstimateAccuracy, error) {
+	// Tracked time stands in for actual hours that were never set. The
+	// empty grouping set yields the overall row, with a NULL priority.
+	query := `
+		WITH done AS (
+			SELECT priority, estimated_hours::float8 AS est,
+			       COALESCE(actual_hours, NULLIF(tracked_seconds, 0) / 3600.0)::float8 AS act
+			FROM tasks
+			WHERE user_id = $1 AND deleted_at IS NULL AND status = 'done' AND ` + taskLiveProjectWhere + `
+			  AND completed_at >= $2 AND estimated_hours > 0
+		)
+		SELECT priority::text AS priority,
+			COUNT(*) AS tasks,
+			ROUND(COALESCE(SUM(est), 0)::numeric, 2)::float8 AS estimated_hours,
+			ROUND(COALESCE(SUM(act), 0)::numeric, 2)::float8 AS actual_hours,
+			ROUND(COALESCE(SUM(act) / NULLIF(SUM(est), 0), 0)::numeric, 2)::float8 AS ratio,
+			ROUND(COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY act / est), 0)::numeric, 2)::float8 AS median_ratio,
+			COUNT(*) FILTER (WHERE act BETWEEN est * 0.8 AND est * 1.2) AS on_target,
+			COUNT(*) FILTER (WHERE act > est * 1.2) AS underestimated,
+			COUNT(*) FILTER (WHERE act < est * 0.8) AS overestimated
+		FROM done
+		WHERE act IS NOT NULL
+		GROUP BY GROUPING SETS ((), (priority))`
+
+	var rows []struct {
+		Priority *string `db:"priority"`
+		domain.EstimateStats
+	}
+	if err := r.db.SelectContext(ctx, &rows, query, userID, since); err != nil {
+		return nil, fmt.Errorf("analyticsRepository.EstimateAccuracy: %w", err)
+	}
+	acc := &domain.EstimateAccuracy{Since: since, ByPriority: map[domain.TaskPriority]domain.EstimateStats{}}
+	for _, row := range rows {
+		if row.Priority == nil {
+			acc.EstimateStats = row.EstimateStats
+		} else {
+			acc.ByPriority[domain.TaskPriority(*row.Priority)] = row.EstimateStats
+		}
+	}
+	return acc, nil
+}
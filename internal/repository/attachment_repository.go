@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type attachmentRepository struct {
+	db *sqlx.DB
+}
+
+// NewAttachmentRepository creates a new PostgreSQL-backed AttachmentRepository.
+func NewAttachmentRepository(db *sqlx.DB) domain.AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) Create(ctx context.Context, a *domain.Attachment) error {
+	query := `
+		INSERT INTO attachments (id, task_id, user_id, filename, content_type, size_bytes, storage_key, status, created_at)
+		VALUES (:id, :task_id, :user_id, :filename, :content_type, :size_bytes, :storage_key, :status, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, a); err != nil {
+		return fmt.Errorf("attachmentRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *attachmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	var a domain.Attachment
+	if err := r.db.GetContext(ctx, &a, `SELECT * FROM attachments WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("attachmentRepository.FindByID: %w", err)
+	}
+	return &a, nil
+}
+
+func (r *attachmentRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+	query := `SELECT * FROM attachments WHERE task_id = $1 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &attachments, query, taskID); err != nil {
+		return nil, fmt.Errorf("attachmentRepository.ListByTaskID: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *attachmentRepository) MarkUploaded(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE attachments SET status = $2, uploaded_at = $3 WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, domain.AttachmentUploaded, at)
+	if err != nil {
+		return fmt.Errorf("attachmentRepository.MarkUploaded: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *attachmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("attachmentRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *attachmentRepository) ListPendingBefore(ctx context.Context, before time.Time, limit int) ([]*domain.Attachment, error) {
+	attachments := []*domain.Attachment{}
+	query := `
+		SELECT * FROM attachments
+		WHERE status = $1 AND created_at < $2
+		ORDER BY created_at
+		LIMIT $3`
+	if err := r.db.SelectContext(ctx, &attachments, query, domain.AttachmentPending, before, limit); err != nil {
+		return nil, fmt.Errorf("attachmentRepository.ListPendingBefore: %w", err)
+	}
+	return attachments, nil
+}
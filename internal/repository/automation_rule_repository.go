@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type automationRuleRepository struct {
+	db *sqlx.DB
+}
+
+// NewAutomationRuleRepository creates a new PostgreSQL-backed AutomationRuleRepository.
+func NewAutomationRuleRepository(db *sqlx.DB) domain.AutomationRuleRepository {
+	return &automationRuleRepository{db: db}
+}
+
+func (r *automationRuleRepository) Create(ctx context.Context, rule *domain.AutomationRule) error {
+	query := `
+		INSERT INTO automation_rules (id, user_id, name, enabled, trigger, condition, actions, async, created_at, updated_at)
+		VALUES (:id, :user_id, :name, :enabled, :trigger, :condition, :actions, :async, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, rule); err != nil {
+		return fmt.Errorf("automationRuleRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *automationRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.AutomationRule, error) {
+	var rule domain.AutomationRule
+	if err := r.db.GetContext(ctx, &rule, `SELECT * FROM automation_rules WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("automationRuleRepository.FindByID: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *automationRuleRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.AutomationRule, error) {
+	rules := []*domain.AutomationRule{}
+	query := `SELECT * FROM automation_rules WHERE user_id = $1 ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &rules, query, userID); err != nil {
+		return nil, fmt.Errorf("automationRuleRepository.ListByUserID: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *automationRuleRepository) ListEnabled(ctx context.Context, userID uuid.UUID, trigger string) ([]*domain.AutomationRule, error) {
+	rules := []*domain.AutomationRule{}
+	query := `SELECT * FROM automation_rules WHERE user_id = $1 AND trigger = $2 AND enabled ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &rules, query, userID, trigger); err != nil {
+		return nil, fmt.Errorf("automationRuleRepository.ListEnabled: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *automationRuleRepository) ListUsersWithTrigger(ctx context.Context, trigger string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	query := `SELECT DISTINCT user_id FROM automation_rules WHERE trigger = $1 AND enabled`
+	if err := r.db.SelectContext(ctx, &ids, query, trigger); err != nil {
+		return nil, fmt.Errorf("automationRuleRepository.ListUsersWithTrigger: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *automationRuleRepository) Update(ctx context.Context, rule *domain.AutomationRule) error {
+	query := `
+		UPDATE automation_rules SET
+			name       = :name,
+			enabled    = :enabled,
+			trigger    = :trigger,
+			condition  = :condition,
+			actions    = :actions,
+			async      = :async,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, rule)
+	if err != nil {
+		return fmt.Errorf("automationRuleRepository.Update: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *automationRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM automation_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("automationRuleRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+// RecordFailure bumps the failure count atomically so concurrent runs cannot
+// lose an increment.
+func (r *automationRuleRepository) RecordFailure(ctx context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error) {
+	query := `
+		UPDATE automation_rules SET
+			consecutive_failures = consecutive_failures + 1,
+			breaker_open_until = CASE WHEN consecutive_failures + 1 >= $2 THEN $3 ELSE breaker_open_until END
+		WHERE id = $1
+		RETURNING consecutive_failures`
+
+	var failures int
+	if err := r.db.GetContext(ctx, &failures, query, id, threshold, openUntil); err != nil {
+		return 0, fmt.Errorf("automationRuleRepository.RecordFailure: %w", mapDBError(err))
+	}
+	return failures, nil
+}
+
+func (r *automationRuleRepository) ResetBreaker(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE automation_rules SET consecutive_failures = 0, breaker_open_until = NULL WHERE id = $1`, id,
+	)
+	if err != nil {
+		return fmt.Errorf("automationRuleRepository.ResetBreaker: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *automationRuleRepository) CreateExecution(ctx context.Context, e *domain.AutomationExecution) error {
+	query := `
+		INSERT INTO automation_executions (id, rule_id, user_id, task_id, trigger, status, actions_applied, error, created_at)
+		VALUES (:id, :rule_id, :user_id, :task_id, :trigger, :status, :actions_applied, :error, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("automationRuleRepository.CreateExecution: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *automationRuleRepository) UpdateExecution(ctx context.Context, e *domain.AutomationExecution) error {
+	query := `
+		UPDATE automation_executions SET
+			status          = :status,
+			actions_applied = :actions_applied,
+			error           = :error
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, e)
+	if err != nil {
+		return fmt.Errorf("automationRuleRepository.UpdateExecution: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *automationRuleRepository) ListExecutions(ctx context.Context, ruleID uuid.UUID, limit int) ([]*domain.AutomationExecution, error) {
+	execs := []*domain.AutomationExecution{}
+	query := `SELECT * FROM automation_executions WHERE rule_id = $1 ORDER BY created_at DESC LIMIT $2`
+	if err := r.db.SelectContext(ctx, &execs, query, ruleID, limit); err != nil {
+		return nil, fmt.Errorf("automationRuleRepository.ListExecutions: %w", err)
+	}
+	return execs, nil
+}
+
+func (r *automationRuleRepository) HasExecuted(ctx context.Context, ruleID, taskID uuid.UUID, since time.Time) (bool, error) {
+	var found bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM automation_executions
+			WHERE rule_id = $1 AND task_id = $2 AND created_at >= $3
+		)`
+	if err := r.db.GetContext(ctx, &found, query, ruleID, taskID, since); err != nil {
+		return false, fmt.Errorf("automationRuleRepository.HasExecuted: %w", err)
+	}
+	return found, nil
+}
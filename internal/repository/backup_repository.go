@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// backupTables lists the tables a backup holds, in the order the
+// migrations create them, so every table comes after those it references.
+var backupTables = []string{
+	"users", "refresh_tokens", "projects", "tasks", "email_suppressions",
+	"notifications", "user_quiet_hours", "deferred_notifications", "webhooks",
+	"webhook_deliveries", "retention_overrides", "task_events", "tags",
+	"task_tags", "due_date_rules", "task_dependencies", "automation_rules",
+	"automation_executions", "attachments", "task_reminders", "time_entries",
+	"invite_codes", "referrals", "changelog_entries", "feedback",
+	"client_errors", "task_occurrences", "user_business_calendars",
+	"user_days_off", "task_escalations", "calendar_feeds", "task_revisions",
+	"task_links", "project_members", "workspace_branding", "metering_events",
+	"instance_settings", "user_two_factor", "two_factor_recovery_codes",
+	"personal_access_tokens",
+}
+
+// backupSkipped lists the tables left out of backups. A restore still
+// empties operations, whose rows belong to the users it replaces; backups
+// and the scheduler's claims are kept as they are.
+var backupSkipped = []string{"operations", "backups", "scheduled_task_claims"}
+
+// backupDeferred names the columns referencing a row that may be restored
+// later: a table created after this one, or a row of the same table. They
+// are restored as NULL and filled in once every row is back.
+var backupDeferred = map[string]string{
+	"users":              "invite_code_id",
+	"tasks":              "parent_id",
+	"webhook_deliveries": "redelivery_of",
+}
+
+// restoreChunk is the number of rows restored per statement.
+const restoreChunk = 500
+
+type backupRepository struct {
+	db *sqlx.DB
+}
+
+// NewBackupRepository creates a new PostgreSQL-backed BackupRepository.
+func NewBackupRepository(db *sqlx.DB) domain.BackupRepository {
+	return &backupRepository{db: db}
+}
+
+func (r *backupRepository) Create(ctx context.Context, b *domain.Backup) error {
+	query := `
+		INSERT INTO backups (id, kind, status, storage_key, created_by, created_at)
+		VALUES (:id, :kind, :status, :storage_key, :created_by, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, b); err != nil {
+		return fmt.Errorf("backupRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *backupRepository) Finish(ctx context.Context, b *domain.Backup) error {
+	query := `
+		UPDATE backups SET
+			status      = :status,
+			size_bytes  = :size_bytes,
+			checksum    = :checksum,
+			row_count   = :row_count,
+			error       = :error,
+			finished_at = :finished_at
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, b)
+	if err != nil {
+		return fmt.Errorf("backupRepository.Finish: %w", err)
+	}
+	if err := checkRowsAffected(res); err != nil {
+		return fmt.Errorf("backupRepository.Finish: %w", err)
+	}
+	return nil
+}
+
+func (r *backupRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Backup, error) {
+	var b domain.Backup
+	if err := r.db.GetContext(ctx, &b, `SELECT * FROM backups WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("backupRepository.FindByID: %w", err)
+	}
+	return &b, nil
+}
+
+func (r *backupRepository) List(ctx context.Context) ([]*domain.Backup, error) {
+	var backups []*domain.Backup
+	if err := r.db.SelectContext(ctx, &backups, `SELECT * FROM backups ORDER BY created_at DESC`); err != nil {
+		return nil, fmt.Errorf("backupRepository.List: %w", err)
+	}
+	return backups, nil
+}
+
+func (r *backupRepository) ListPrunable(ctx context.Context, keep int, cutoff time.Time) ([]*domain.Backup, error) {
+	query := `
+		SELECT * FROM backups
+		WHERE (kind = $1 AND status = $2 AND id NOT IN (
+				SELECT id FROM backups WHERE kind = $1 AND status = $2
+				ORDER BY created_at DESC LIMIT $3))
+		   OR (status <> $2 AND created_at < $4)
+		ORDER BY created_at`
+
+	var backups []*domain.Backup
+	if err := r.db.SelectContext(ctx, &backups, query, domain.BackupScheduled, domain.BackupSucceeded, keep, cutoff); err != nil {
+		return nil, fmt.Errorf("backupRepository.ListPrunable: %w", err)
+	}
+	return backups, nil
+}
+
+func (r *backupRepository) MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE backups SET restored_at = $2 WHERE id = $1`, id, at)
+	if err != nil {
+		return fmt.Errorf("backupRepository.MarkRestored: %w", err)
+	}
+	if err := checkRowsAffected(res); err != nil {
+		return fmt.Errorf("backupRepository.MarkRestored: %w", err)
+	}
+	return nil
+}
+
+func (r *backupRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM backups WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("backupRepository.Delete: %w", err)
+	}
+	if err := checkRowsAffected(res); err != nil {
+		return fmt.Errorf("backupRepository.Delete: %w", err)
+	}
+	return nil
+}
+
+func (r *backupRepository) Columns(ctx context.Context) (map[string][]string, error) {
+	columns, err := backupColumns(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("backupRepository.Columns: %w", err)
+	}
+	return columns, nil
+}
+
+func (r *backupRepository) Dump(ctx context.Context) ([]domain.BackupTable, error) {
+	// Every table is read from the same snapshot, so the backup is
+	// consistent without blocking writers.
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("backupRepository.Dump begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	columns, err := backupColumns(ctx, tx)
+	if err != nil {
+		return nil, fmt.Errorf("backupRepository.Dump: %w", err)
+	}
+	tables := make([]domain.BackupTable, 0, len(backupTables))
+	for _, name := range backupTables {
+		rows := []json.RawMessage{}
+		if err := tx.SelectContext(ctx, &rows, fmt.Sprintf(`SELECT row_to_json(t) FROM %s t`, name)); err != nil {
+			return nil, fmt.Errorf("backupRepository.Dump %s: %w", name, err)
+		}
+		tables = append(tables, domain.BackupTable{Name: name, Columns: columns[name], Rows: rows})
+	}
+	return tables, nil
+}
+
+func (r *backupRepository) Restore(ctx context.Context, tables []domain.BackupTable) error {
+	for _, t := range tables {
+		if !slices.Contains(backupTables, t.Name) {
+			return fmt.Errorf("backupRepository.Restore: unknown table %q: %w", t.Name, domain.ErrValidation)
+		}
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("backupRepository.Restore begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	truncate := append(slices.Clone(backupTables), "operations")
+	if _, err := tx.ExecContext(ctx, `TRUNCATE `+strings.Join(truncate, ", ")); err != nil {
+		return fmt.Errorf("backupRepository.Restore truncate: %w", err)
+	}
+
+	for _, t := range tables {
+		cols := make([]string, len(t.Columns))
+		values := make([]string, len(t.Columns))
+		for i, col := range t.Columns {
+			cols[i] = pq.QuoteIdentifier(col)
+			values[i] = cols[i]
+			if backupDeferred[t.Name] == col {
+				values[i] = "NULL"
+			}
+		}
+		query := fmt.Sprintf(`INSERT INTO %[1]s (%[2]s) SELECT %[3]s FROM json_populate_recordset(NULL::%[1]s, $1::json)`,
+			t.Name, strings.Join(cols, ", "), strings.Join(values, ", "))
+		if err := execChunked(ctx, tx, query, t.Rows); err != nil {
+			return fmt.Errorf("backupRepository.Restore %s: %w", t.Name, err)
+		}
+	}
+
+	for _, t := range tables {
+		col, ok := backupDeferred[t.Name]
+		if !ok || len(t.Rows) == 0 {
+			continue
+		}
+		// Filling the column in is not a change of the row's own, so
+		// triggers such as the version bump on tasks stay out of it.
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s DISABLE TRIGGER USER`, t.Name)); err != nil {
+			return fmt.Errorf("backupRepository.Restore %s: %w", t.Name, err)
+		}
+		query := fmt.Sprintf(`UPDATE %[1]s t SET %[2]s = d.%[2]s FROM json_populate_recordset(NULL::%[1]s, $1::json) d WHERE t.id = d.id AND d.%[2]s IS NOT NULL`,
+			t.Name, pq.QuoteIdentifier(col))
+		if err := execChunked(ctx, tx, query, t.Rows); err != nil {
+			return fmt.Errorf("backupRepository.Restore %s.%s: %w", t.Name, col, err)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`ALTER TABLE %s ENABLE TRIGGER USER`, t.Name)); err != nil {
+			return fmt.Errorf("backupRepository.Restore %s: %w", t.Name, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("backupRepository.Restore commit: %w", err)
+	}
+	return nil
+}
+
+// execChunked runs query with each chunk of rows as a JSON array in $1.
+func execChunked(ctx context.Context, tx *sqlx.Tx, query string, rows []json.RawMessage) error {
+	for start := 0; start < len(rows); start += restoreChunk {
+		chunk := rows[start:min(start+restoreChunk, len(rows))]
+		var b strings.Builder
+		b.WriteByte('[')
+		for i, row := range chunk {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.Write(row)
+		}
+		b.WriteByte(']')
+		if _, err := tx.ExecContext(ctx, query, b.String()); err != nil {
+			return mapDBError(err)
+		}
+	}
+	return nil
+}
+
+// backupColumns reads the columns of every backed-up table in order.
+func backupColumns(ctx context.Context, q sqlx.QueryerContext) (map[string][]string, error) {
+	var rows []struct {
+		Table  string `db:"table_name"`
+		Column string `db:"column_name"`
+	}
+	query := `
+		SELECT table_name, column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ANY($1)
+		ORDER BY table_name, ordinal_position`
+	if err := sqlx.SelectContext(ctx, q, &rows, query, pq.Array(backupTables)); err != nil {
+		return nil, err
+	}
+	columns := make(map[string][]string, len(backupTables))
+	for _, row := range rows {
+		columns[row.Table] = append(columns[row.Table], row.Column)
+	}
+	return columns, nil
+}
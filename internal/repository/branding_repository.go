@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type brandingRepository struct {
+	db *sqlx.DB
+}
+
+// NewBrandingRepository creates a new PostgreSQL-backed BrandingRepository.
+func NewBrandingRepository(db *sqlx.DB) domain.BrandingRepository {
+	return &brandingRepository{db: db}
+}
+
+func (r *brandingRepository) Get(ctx context.Context) (*domain.WorkspaceBranding, error) {
+	var b domain.WorkspaceBranding
+	query := `
+		SELECT name, logo_url, accent_color, email_from_domain, dkim_selector,
+		       dkim_status, dkim_checked_at, updated_at
+		FROM workspace_branding`
+	if err := r.db.GetContext(ctx, &b, query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("brandingRepository.Get: %w", err)
+	}
+	return &b, nil
+}
+
+func (r *brandingRepository) Save(ctx context.Context, b *domain.WorkspaceBranding) error {
+	query := `
+		INSERT INTO workspace_branding (name, logo_url, accent_color, email_from_domain, dkim_selector,
+		                                dkim_status, dkim_checked_at, updated_at)
+		VALUES (:name, :logo_url, :accent_color, :email_from_domain, :dkim_selector,
+		        :dkim_status, :dkim_checked_at, :updated_at)
+		ON CONFLICT (id) DO UPDATE SET
+			name              = EXCLUDED.name,
+			logo_url          = EXCLUDED.logo_url,
+			accent_color      = EXCLUDED.accent_color,
+			email_from_domain = EXCLUDED.email_from_domain,
+			dkim_selector     = EXCLUDED.dkim_selector,
+			dkim_status       = EXCLUDED.dkim_status,
+			dkim_checked_at   = EXCLUDED.dkim_checked_at,
+			updated_at        = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, b); err != nil {
+		return fmt.Errorf("brandingRepository.Save: %w", mapDBError(err))
+	}
+	return nil
+}
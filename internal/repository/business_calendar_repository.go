@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type businessCalendarRepository struct {
+	db *sqlx.DB
+}
+
+// NewBusinessCalendarRepository creates a new PostgreSQL-backed BusinessCalendarRepository.
+func NewBusinessCalendarRepository(db *sqlx.DB) domain.BusinessCalendarRepository {
+	return &businessCalendarRepository{db: db}
+}
+
+func (r *businessCalendarRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.BusinessCalendar, error) {
+	var c domain.BusinessCalendar
+	if err := r.db.GetContext(ctx, &c, `SELECT * FROM user_business_calendars WHERE user_id = $1`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("businessCalendarRepository.FindByUserID: %w", err)
+	}
+	c.Weekend = domain.WeekdayNames(c.WeekendDays)
+	return &c, nil
+}
+
+func (r *businessCalendarRepository) Upsert(ctx context.Context, c *domain.BusinessCalendar) error {
+	query := `
+		INSERT INTO user_business_calendars (user_id, country, timezone, weekend_days, shift_due_dates,
+			work_start, work_end, working_time_only, updated_at)
+		VALUES (:user_id, :country, :timezone, :weekend_days, :shift_due_dates,
+			:work_start, :work_end, :working_time_only, :updated_at)
+		ON CONFLICT (user_id) DO UPDATE SET
+			country           = EXCLUDED.country,
+			timezone          = EXCLUDED.timezone,
+			weekend_days      = EXCLUDED.weekend_days,
+			shift_due_dates   = EXCLUDED.shift_due_dates,
+			work_start        = EXCLUDED.work_start,
+			work_end          = EXCLUDED.work_end,
+			working_time_only = EXCLUDED.working_time_only,
+			updated_at        = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, c); err != nil {
+		return fmt.Errorf("businessCalendarRepository.Upsert: %w", mapDBError(err))
+	}
+	return nil
+}
+
+type dayOffRepository struct {
+	db *sqlx.DB
+}
+
+// NewDayOffRepository creates a new PostgreSQL-backed DayOffRepository.
+func NewDayOffRepository(db *sqlx.DB) domain.DayOffRepository {
+	return &dayOffRepository{db: db}
+}
+
+// dayOffColumns renders the DATE column in the YYYY-MM-DD form DayOff carries.
+const dayOffColumns = `id, user_id, to_char(date, 'YYYY-MM-DD') AS date, name, created_at`
+
+func (r *dayOffRepository) Create(ctx context.Context, d *domain.DayOff) error {
+	query := `
+		INSERT INTO user_days_off (id, user_id, date, name, created_at)
+		VALUES (:id, :user_id, :date, :name, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, d); err != nil {
+		return fmt.Errorf("dayOffRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *dayOffRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.DayOff, error) {
+	var d domain.DayOff
+	if err := r.db.GetContext(ctx, &d, `SELECT `+dayOffColumns+` FROM user_days_off WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("dayOffRepository.FindByID: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *dayOffRepository) ListByUserID(ctx context.Context, userID uuid.UUID, from string) ([]*domain.DayOff, error) {
+	days := []*domain.DayOff{}
+	query := `
+		SELECT ` + dayOffColumns + ` FROM user_days_off
+		WHERE user_id = $1 AND ($2 = '' OR date >= NULLIF($2, '')::date)
+		ORDER BY date`
+
+	if err := r.db.SelectContext(ctx, &days, query, userID, from); err != nil {
+		return nil, fmt.Errorf("dayOffRepository.ListByUserID: %w", err)
+	}
+	return days, nil
+}
+
+func (r *dayOffRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM user_days_off WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("dayOffRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type calendarFeedRepository struct {
+	db *sqlx.DB
+}
+
+// NewCalendarFeedRepository creates a new PostgreSQL-backed CalendarFeedRepository.
+func NewCalendarFeedRepository(db *sqlx.DB) domain.CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+func (r *calendarFeedRepository) Upsert(ctx context.Context, feed *domain.CalendarFeed) error {
+	query := `
+		INSERT INTO calendar_feeds (user_id, token, created_at)
+		VALUES (:user_id, :token, :created_at)
+		ON CONFLICT (user_id) DO UPDATE SET
+			token      = EXCLUDED.token,
+			created_at = EXCLUDED.created_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, feed); err != nil {
+		return fmt.Errorf("calendarFeedRepository.Upsert: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *calendarFeedRepository) FindByToken(ctx context.Context, token string) (*domain.CalendarFeed, error) {
+	return r.find(ctx, "FindByToken", `SELECT * FROM calendar_feeds WHERE token = $1`, token)
+}
+
+func (r *calendarFeedRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	return r.find(ctx, "FindByUserID", `SELECT * FROM calendar_feeds WHERE user_id = $1`, userID)
+}
+
+func (r *calendarFeedRepository) find(ctx context.Context, method, query string, arg any) (*domain.CalendarFeed, error) {
+	var feed domain.CalendarFeed
+	if err := r.db.GetContext(ctx, &feed, query, arg); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("calendarFeedRepository.%s: %w", method, err)
+	}
+	return &feed, nil
+}
+
+func (r *calendarFeedRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("calendarFeedRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
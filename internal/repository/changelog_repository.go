@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type changelogRepository struct {
+	db *sqlx.DB
+}
+
+// NewChangelogRepository creates a new PostgreSQL-backed ChangelogRepository.
+func NewChangelogRepository(db *sqlx.DB) domain.ChangelogRepository {
+	return &changelogRepository{db: db}
+}
+
+func (r *changelogRepository) Create(ctx context.Context, e *domain.ChangelogEntry) error {
+	query := `
+		INSERT INTO changelog_entries (id, version, title, body, published_at, created_by, created_at)
+		VALUES (:id, :version, :title, :body, :published_at, :created_by, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("changelogRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *changelogRepository) ListPublished(ctx context.Context, now time.Time, limit int) ([]*domain.ChangelogEntry, error) {
+	entries := []*domain.ChangelogEntry{}
+	query := `SELECT * FROM changelog_entries WHERE published_at <= $1 ORDER BY published_at DESC LIMIT $2`
+	if err := r.db.SelectContext(ctx, &entries, query, now, limit); err != nil {
+		return nil, fmt.Errorf("changelogRepository.ListPublished: %w", err)
+	}
+	return entries, nil
+}
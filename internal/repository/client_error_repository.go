@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type clientErrorRepository struct {
+	db *sqlx.DB
+}
+
+// NewClientErrorRepository creates a new PostgreSQL-backed ClientErrorRepository.
+func NewClientErrorRepository(db *sqlx.DB) domain.ClientErrorRepository {
+	return &clientErrorRepository{db: db}
+}
+
+func (r *clientErrorRepository) CreateBatch(ctx context.Context, errs []*domain.ClientError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	query := `
+		INSERT INTO client_errors
+			(id, user_id, fingerprint, kind, message, stack, url, request_id,
+			 client_version, platform, user_agent, context, occurred_at, received_at)
+		VALUES
+			(:id, :user_id, :fingerprint, :kind, :message, :stack, :url, :request_id,
+			 :client_version, :platform, :user_agent, :context, :occurred_at, :received_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, errs); err != nil {
+		return fmt.Errorf("clientErrorRepository.CreateBatch: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *clientErrorRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.ClientError, error) {
+	var e domain.ClientError
+	if err := r.db.GetContext(ctx, &e, `SELECT * FROM client_errors WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("clientErrorRepository.FindByID: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *clientErrorRepository) List(ctx context.Context, filter domain.ClientErrorFilter) ([]*domain.ClientError, int, error) {
+	conds := []string{"TRUE"}
+	args := []any{}
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+	if filter.Fingerprint != "" {
+		add("fingerprint = $%d", filter.Fingerprint)
+	}
+	if filter.RequestID != "" {
+		add("request_id = $%d", filter.RequestID)
+	}
+	if filter.ClientVersion != "" {
+		add("client_version = $%d", filter.ClientVersion)
+	}
+	if filter.UserID != nil {
+		add("user_id = $%d", *filter.UserID)
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM client_errors WHERE "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("clientErrorRepository.List count: %w", err)
+	}
+
+	reports := []*domain.ClientError{}
+	query := fmt.Sprintf(
+		"SELECT * FROM client_errors WHERE %s ORDER BY received_at DESC LIMIT $%d OFFSET $%d", where, len(args)+1, len(args)+2,
+	)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err := r.db.SelectContext(ctx, &reports, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("clientErrorRepository.List select: %w", err)
+	}
+	return reports, total, nil
+}
+
+func (r *clientErrorRepository) Groups(ctx context.Context, since time.Time, limit int) ([]*domain.ClientErrorGroup, error) {
+	groups := []*domain.ClientErrorGroup{}
+	query := `
+		SELECT
+			fingerprint,
+			(array_agg(kind ORDER BY received_at DESC))[1]           AS kind,
+			(array_agg(message ORDER BY received_at DESC))[1]        AS message,
+			COUNT(*)                                                 AS count,
+			COUNT(DISTINCT user_id)                                  AS users,
+			(array_agg(client_version ORDER BY received_at DESC))[1] AS client_version,
+			MIN(received_at)                                         AS first_seen,
+			MAX(received_at)                                         AS last_seen
+		FROM client_errors
+		WHERE received_at >= $1
+		GROUP BY fingerprint
+		ORDER BY count DESC, last_seen DESC
+		LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &groups, query, since, limit); err != nil {
+		return nil, fmt.Errorf("clientErrorRepository.Groups: %w", err)
+	}
+	return groups, nil
+}
+
+func (r *clientErrorRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM client_errors WHERE received_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("clientErrorRepository.DeleteOlderThan: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type dueDateRuleRepository struct {
+	db *sqlx.DB
+}
+
+// NewDueDateRuleRepository creates a new PostgreSQL-backed DueDateRuleRepository.
+func NewDueDateRuleRepository(db *sqlx.DB) domain.DueDateRuleRepository {
+	return &dueDateRuleRepository{db: db}
+}
+
+func (r *dueDateRuleRepository) Create(ctx context.Context, rule *domain.DueDateRule) error {
+	query := `
+		INSERT INTO due_date_rules (id, user_id, name, enabled, position, timezone, condition, action, created_at, updated_at)
+		VALUES (:id, :user_id, :name, :enabled, :position, :timezone, :condition, :action, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, rule); err != nil {
+		return fmt.Errorf("dueDateRuleRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *dueDateRuleRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.DueDateRule, error) {
+	var rule domain.DueDateRule
+	if err := r.db.GetContext(ctx, &rule, `SELECT * FROM due_date_rules WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("dueDateRuleRepository.FindByID: %w", err)
+	}
+	return &rule, nil
+}
+
+func (r *dueDateRuleRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.DueDateRule, error) {
+	rules := []*domain.DueDateRule{}
+	query := `SELECT * FROM due_date_rules WHERE user_id = $1 ORDER BY position, created_at`
+	if err := r.db.SelectContext(ctx, &rules, query, userID); err != nil {
+		return nil, fmt.Errorf("dueDateRuleRepository.ListByUserID: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *dueDateRuleRepository) Update(ctx context.Context, rule *domain.DueDateRule) error {
+	query := `
+		UPDATE due_date_rules SET
+			name       = :name,
+			enabled    = :enabled,
+			position   = :position,
+			timezone   = :timezone,
+			condition  = :condition,
+			action     = :action,
+			updated_at = :updated_at
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, rule)
+	if err != nil {
+		return fmt.Errorf("dueDateRuleRepository.Update: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *dueDateRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM due_date_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("dueDateRuleRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
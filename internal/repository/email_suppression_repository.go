@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type emailSuppressionRepository struct {
+	db *sqlx.DB
+}
+
+// NewEmailSuppressionRepository creates a new PostgreSQL-backed EmailSuppressionRepository.
+func NewEmailSuppressionRepository(db *sqlx.DB) domain.EmailSuppressionRepository {
+	return &emailSuppressionRepository{db: db}
+}
+
+func (r *emailSuppressionRepository) Upsert(ctx context.Context, s *domain.EmailSuppression) error {
+	s.Email = strings.ToLower(s.Email)
+	query := `
+		INSERT INTO email_suppressions (email, reason, type, provider, created_at)
+		VALUES (:email, :reason, :type, :provider, :created_at)
+		ON CONFLICT (email) DO UPDATE
+		SET reason = EXCLUDED.reason, type = EXCLUDED.type, provider = EXCLUDED.provider`
+
+	if _, err := r.db.NamedExecContext(ctx, query, s); err != nil {
+		return fmt.Errorf("emailSuppressionRepository.Upsert: %w", err)
+	}
+	return nil
+}
+
+func (r *emailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var exists bool
+	err := r.db.GetContext(ctx, &exists,
+		`SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE email = $1)`, strings.ToLower(email),
+	)
+	if err != nil {
+		return false, fmt.Errorf("emailSuppressionRepository.IsSuppressed: %w", err)
+	}
+	return exists, nil
+}
@@ -0,0 +1,92 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// fakeDB is a database/sql driver that records every statement and answers
+// from a script, for checking the SQL a repository sends and how it reads
+// the results without a PostgreSQL server.
+type fakeDB struct {
+	t     *testing.T
+	calls []fakeCall
+	// answer returns the rows a query returns, or the rows an exec
+	// affected in affected; nil rows is an empty result.
+	answer func(query string, args []any) (cols []string, rows [][]any, affected int64)
+}
+
+type fakeCall struct {
+	Query string
+	Args  []any
+}
+
+// newFakeDB opens a sqlx handle on a fakeDB using PostgreSQL bind vars.
+func newFakeDB(t *testing.T, answer func(query string, args []any) ([]string, [][]any, int64)) (*sqlx.DB, *fakeDB) {
+	f := &fakeDB{t: t, answer: answer}
+	db := sqlx.NewDb(sql.OpenDB(f), "postgres")
+	t.Cleanup(func() { db.Close() })
+	return db, f
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (f *fakeDB) record(query string, named []driver.NamedValue) ([]string, [][]any, int64) {
+	args := make([]any, len(named))
+	for i, v := range named {
+		args[i] = v.Value
+	}
+	f.calls = append(f.calls, fakeCall{Query: query, Args: args})
+	return f.answer(query, args)
+}
+
+type fakeConn struct{ f *fakeDB }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("fakedb: no prepare") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return fakeTx{}, nil }
+
+func (c fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	return fakeTx{}, nil
+}
+
+func (c fakeConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	cols, rows, _ := c.f.record(query, args)
+	return &fakeRows{cols: cols, rows: rows}, nil
+}
+
+func (c fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	_, _, n := c.f.record(query, args)
+	return driver.RowsAffected(n), nil
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+type fakeRows struct {
+	cols []string
+	rows [][]any
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	for i, v := range r.rows[0] {
+		dest[i] = v
+	}
+	r.rows = r.rows[1:]
+	return nil
+}
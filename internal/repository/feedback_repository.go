@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type feedbackRepository struct {
+	db *sqlx.DB
+}
+
+// NewFeedbackRepository creates a new PostgreSQL-backed FeedbackRepository.
+func NewFeedbackRepository(db *sqlx.DB) domain.FeedbackRepository {
+	return &feedbackRepository{db: db}
+}
+
+func (r *feedbackRepository) Create(ctx context.Context, f *domain.Feedback) error {
+	query := `
+		INSERT INTO feedback (id, user_id, category, message, client_version, diagnostics, user_agent, created_at)
+		VALUES (:id, :user_id, :category, :message, :client_version, :diagnostics, :user_agent, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, f); err != nil {
+		return fmt.Errorf("feedbackRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *feedbackRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Feedback, error) {
+	var f domain.Feedback
+	if err := r.db.GetContext(ctx, &f, `SELECT * FROM feedback WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("feedbackRepository.FindByID: %w", err)
+	}
+	return &f, nil
+}
+
+func (r *feedbackRepository) List(ctx context.Context, filter domain.FeedbackFilter) ([]*domain.Feedback, int, error) {
+	conds := []string{"TRUE"}
+	args := []any{}
+	if filter.Category != nil {
+		args = append(args, *filter.Category)
+		conds = append(conds, fmt.Sprintf("category = $%d", len(args)))
+	}
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conds = append(conds, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	where := strings.Join(conds, " AND ")
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM feedback WHERE "+where, args...); err != nil {
+		return nil, 0, fmt.Errorf("feedbackRepository.List count: %w", err)
+	}
+
+	reports := []*domain.Feedback{}
+	query := fmt.Sprintf(
+		"SELECT * FROM feedback WHERE %s ORDER BY created_at DESC LIMIT $%d OFFSET $%d", where, len(args)+1, len(args)+2,
+	)
+	args = append(args, filter.Limit, (filter.Page-1)*filter.Limit)
+	if err := r.db.SelectContext(ctx, &reports, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("feedbackRepository.List select: %w", err)
+	}
+	return reports, total, nil
+}
+
+func (r *feedbackRepository) RecordForward(ctx context.Context, id uuid.UUID, at time.Time, forwardErr string) error {
+	query := `
+		UPDATE feedback SET
+			forwarded_at = CASE WHEN $3 = '' THEN $2 ELSE forwarded_at END,
+			forward_error = $3
+		WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, query, id, at, forwardErr)
+	if err != nil {
+		return fmt.Errorf("feedbackRepository.RecordForward: %w", err)
+	}
+	return checkRowsAffected(res)
+}
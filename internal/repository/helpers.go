@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 )
 
@@ -34,3 +37,34 @@ func checkRowsAffected(res sql.Result) error {
 	}
 	return nil
 }
+
+// staleOrMissing explains a write to table made conditional on a version
+// that matched no row: ErrPreconditionFailed while the row is still live,
+// so it must be at another version, ErrNotFound otherwise.
+func staleOrMissing(ctx context.Context, q sqlx.QueryerContext, table string, id uuid.UUID) error {
+	var live bool
+	query := `SELECT EXISTS (SELECT 1 FROM ` + table + ` WHERE id = $1 AND deleted_at IS NULL)`
+	if err := sqlx.GetContext(ctx, q, &live, query, id); err != nil {
+		return fmt.Errorf("%s version check: %w", table, err)
+	}
+	if live {
+		return domain.ErrPreconditionFailed
+	}
+	return domain.ErrNotFound
+}
+
+// taskDeadlineSQL is the instant a due date lapses, matching
+// domain.Task.Deadline: a due date at local midnight in tz is date-only and
+// lasts until the next local midnight. col is the due_date column and tz a
+// SQL text expression naming the zone.
+func taskDeadlineSQL(col, tz string) string {
+	return fmt.Sprintf(
+		"(CASE WHEN (%[1]s AT TIME ZONE %[2]s)::time = '00:00' THEN ((%[1]s AT TIME ZONE %[2]s) + INTERVAL '1 day') AT TIME ZONE %[2]s ELSE %[1]s END)",
+		col, tz,
+	)
+}
+
+// userTimezoneSQL looks up the time zone of the user in the userCol column.
+func userTimezoneSQL(userCol string) string {
+	return fmt.Sprintf("(SELECT u.timezone FROM users u WHERE u.id = %s)", userCol)
+}
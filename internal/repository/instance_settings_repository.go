@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type instanceSettingsRepository struct {
+	db *sqlx.DB
+}
+
+// NewInstanceSettingsRepository creates a new PostgreSQL-backed InstanceSettingsRepository.
+func NewInstanceSettingsRepository(db *sqlx.DB) domain.InstanceSettingsRepository {
+	return &instanceSettingsRepository{db: db}
+}
+
+func (r *instanceSettingsRepository) Get(ctx context.Context) (*domain.InstanceOverrides, error) {
+	var o domain.InstanceOverrides
+	query := `
+		SELECT signup_mode, invite_quota, max_upload_bytes, revisions_keep, disabled_modules, updated_at
+		FROM instance_settings`
+	if err := r.db.GetContext(ctx, &o, query); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("instanceSettingsRepository.Get: %w", err)
+	}
+	return &o, nil
+}
+
+func (r *instanceSettingsRepository) Save(ctx context.Context, o *domain.InstanceOverrides) error {
+	query := `
+		INSERT INTO instance_settings (signup_mode, invite_quota, max_upload_bytes, revisions_keep, disabled_modules, updated_at)
+		VALUES (:signup_mode, :invite_quota, :max_upload_bytes, :revisions_keep, :disabled_modules, :updated_at)
+		ON CONFLICT (id) DO UPDATE SET
+			signup_mode      = EXCLUDED.signup_mode,
+			invite_quota     = EXCLUDED.invite_quota,
+			max_upload_bytes = EXCLUDED.max_upload_bytes,
+			revisions_keep   = EXCLUDED.revisions_keep,
+			disabled_modules = EXCLUDED.disabled_modules,
+			updated_at       = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, o); err != nil {
+		return fmt.Errorf("instanceSettingsRepository.Save: %w", mapDBError(err))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type inviteRepository struct {
+	db *sqlx.DB
+}
+
+// NewInviteRepository creates a new PostgreSQL-backed InviteRepository.
+func NewInviteRepository(db *sqlx.DB) domain.InviteRepository {
+	return &inviteRepository{db: db}
+}
+
+func (r *inviteRepository) Create(ctx context.Context, c *domain.InviteCode) error {
+	query := `
+		INSERT INTO invite_codes (id, code, created_by, max_uses, uses, expires_at, created_at)
+		VALUES (:id, :code, :created_by, :max_uses, :uses, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, c); err != nil {
+		return fmt.Errorf("inviteRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *inviteRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.InviteCode, error) {
+	var c domain.InviteCode
+	if err := r.db.GetContext(ctx, &c, `SELECT * FROM invite_codes WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("inviteRepository.FindByID: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *inviteRepository) ListByCreator(ctx context.Context, userID uuid.UUID) ([]*domain.InviteCode, error) {
+	codes := []*domain.InviteCode{}
+	query := `SELECT * FROM invite_codes WHERE created_by = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &codes, query, userID); err != nil {
+		return nil, fmt.Errorf("inviteRepository.ListByCreator: %w", err)
+	}
+	return codes, nil
+}
+
+func (r *inviteRepository) CountCharged(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM invite_codes WHERE created_by = $1 AND (revoked_at IS NULL OR uses > 0)`
+	if err := r.db.GetContext(ctx, &n, query, userID); err != nil {
+		return 0, fmt.Errorf("inviteRepository.CountCharged: %w", err)
+	}
+	return n, nil
+}
+
+func (r *inviteRepository) Redeem(ctx context.Context, code string, now time.Time) (*domain.InviteCode, error) {
+	query := `
+		UPDATE invite_codes SET uses = uses + 1
+		WHERE code = $1 AND revoked_at IS NULL AND uses < max_uses
+		  AND (expires_at IS NULL OR expires_at > $2)
+		RETURNING *`
+
+	var c domain.InviteCode
+	if err := r.db.GetContext(ctx, &c, query, code, now); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("inviteRepository.Redeem: %w", err)
+	}
+	return &c, nil
+}
+
+func (r *inviteRepository) Release(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE invite_codes SET uses = uses - 1 WHERE id = $1 AND uses > 0`, id)
+	if err != nil {
+		return fmt.Errorf("inviteRepository.Release: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *inviteRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE invite_codes SET revoked_at = COALESCE(revoked_at, $2) WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, at)
+	if err != nil {
+		return fmt.Errorf("inviteRepository.Revoke: %w", err)
+	}
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// trashTables lists the soft-deletable tables, children before parents.
+var trashTables = []string{"tasks", "projects"}
+
+type maintenanceRepository struct {
+	db *sqlx.DB
+}
+
+// NewMaintenanceRepository creates a new PostgreSQL-backed MaintenanceRepository.
+func NewMaintenanceRepository(db *sqlx.DB) domain.MaintenanceRepository {
+	return &maintenanceRepository{db: db}
+}
+
+// ReindexTasks rebuilds every index on tasks, including those backing task
+// search, and refreshes planner statistics.
+func (r *maintenanceRepository) ReindexTasks(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `REINDEX TABLE tasks`); err != nil {
+		return fmt.Errorf("maintenanceRepository.ReindexTasks reindex: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, `ANALYZE tasks`); err != nil {
+		return fmt.Errorf("maintenanceRepository.ReindexTasks analyze: %w", err)
+	}
+	return nil
+}
+
+func (r *maintenanceRepository) Ping(ctx context.Context) error {
+	var one int
+	if err := r.db.GetContext(ctx, &one, `SELECT 1`); err != nil {
+		return fmt.Errorf("maintenanceRepository.Ping: %w", err)
+	}
+	return nil
+}
+
+func (r *maintenanceRepository) ListTrash(ctx context.Context, deletedBefore time.Time) (map[string][]uuid.UUID, error) {
+	affected := make(map[string][]uuid.UUID, len(trashTables))
+	for _, table := range trashTables {
+		var ids []uuid.UUID
+		query := fmt.Sprintf(`SELECT id FROM %s WHERE deleted_at IS NOT NULL AND deleted_at < $1 ORDER BY deleted_at`, table)
+		if err := r.db.SelectContext(ctx, &ids, query, deletedBefore); err != nil {
+			return nil, fmt.Errorf("maintenanceRepository.ListTrash %s: %w", table, err)
+		}
+		affected[table] = ids
+	}
+	return affected, nil
+}
+
+// ListExpiredTrash returns soft-deleted rows whose retention window has run
+// out by asOf. A user's retention_overrides row replaces the default for that
+// entity type; tables missing from defaults are never expired.
+func (r *maintenanceRepository) ListExpiredTrash(ctx context.Context, asOf time.Time, defaults domain.RetentionPolicy) (map[string][]uuid.UUID, error) {
+	affected := make(map[string][]uuid.UUID, len(trashTables))
+	for _, table := range trashTables {
+		days, ok := defaults[table]
+		if !ok {
+			continue
+		}
+		var ids []uuid.UUID
+		query := fmt.Sprintf(`
+			SELECT t.id FROM %s t
+			LEFT JOIN retention_overrides o ON o.user_id = t.user_id AND o.entity_type = $3
+			WHERE t.deleted_at IS NOT NULL
+			  AND t.deleted_at < $1 - make_interval(days => COALESCE(o.retention_days, $2::int))
+			ORDER BY t.deleted_at`, table)
+		if err := r.db.SelectContext(ctx, &ids, query, asOf, days, table); err != nil {
+			return nil, fmt.Errorf("maintenanceRepository.ListExpiredTrash %s: %w", table, err)
+		}
+		affected[table] = ids
+	}
+	return affected, nil
+}
+
+// PurgeTrash hard-deletes the given rows. Rows restored since they were listed
+// are left alone.
+func (r *maintenanceRepository) PurgeTrash(ctx context.Context, affected map[string][]uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("maintenanceRepository.PurgeTrash begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, table := range trashTables {
+		ids := affected[table]
+		if len(ids) == 0 {
+			continue
+		}
+		query := fmt.Sprintf(`DELETE FROM %s WHERE id = ANY($1) AND deleted_at IS NOT NULL`, table)
+		if _, err := tx.ExecContext(ctx, query, pq.Array(ids)); err != nil {
+			return fmt.Errorf("maintenanceRepository.PurgeTrash %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("maintenanceRepository.PurgeTrash commit: %w", err)
+	}
+	return nil
+}
+
+// ListUserData returns the user and every row that is removed with it,
+// including soft-deleted ones.
+func (r *maintenanceRepository) ListUserData(ctx context.Context, userID uuid.UUID) (map[string][]uuid.UUID, error) {
+	var exists bool
+	if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM users WHERE id = $1)`, userID); err != nil {
+		return nil, fmt.Errorf("maintenanceRepository.ListUserData: %w", err)
+	}
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	affected := map[string][]uuid.UUID{"users": {userID}}
+	for _, table := range []string{"projects", "tasks", "refresh_tokens", "notifications", "webhooks"} {
+		var ids []uuid.UUID
+		query := fmt.Sprintf(`SELECT id FROM %s WHERE user_id = $1 ORDER BY created_at`, table)
+		if err := r.db.SelectContext(ctx, &ids, query, userID); err != nil {
+			return nil, fmt.Errorf("maintenanceRepository.ListUserData %s: %w", table, err)
+		}
+		affected[table] = ids
+	}
+	return affected, nil
+}
+
+// HardDeleteUser removes the user row; dependent rows go with it via ON DELETE CASCADE.
+func (r *maintenanceRepository) HardDeleteUser(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("maintenanceRepository.HardDeleteUser: %w", err)
+	}
+	return checkRowsAffected(res)
+}
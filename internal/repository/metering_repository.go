@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+// meteringDay formats a day for DATE parameters, so the database's time
+// zone plays no part.
+const meteringDay = "2006-01-02"
+
+type meteringRepository struct {
+	db *sqlx.DB
+}
+
+// NewMeteringRepository creates a new PostgreSQL-backed MeteringRepository.
+func NewMeteringRepository(db *sqlx.DB) domain.MeteringRepository {
+	return &meteringRepository{db: db}
+}
+
+// meteringUpsert ends the INSERTs below: a day's rows are recomputed until
+// they are invoiced.
+const meteringUpsert = `
+		ON CONFLICT (user_id, metric, day) DO UPDATE SET
+			quantity    = EXCLUDED.quantity,
+			recorded_at = EXCLUDED.recorded_at
+		WHERE metering_events.invoiced_at IS NULL`
+
+func (r *meteringRepository) RecordActivity(ctx context.Context, day, at time.Time) (int, error) {
+	query := `
+		INSERT INTO metering_events (user_id, metric, day, quantity, recorded_at)
+		SELECT u.id, m.metric, $1::date, m.quantity, $4
+		FROM users u
+		CROSS JOIN LATERAL (VALUES
+			($5, CASE WHEN EXISTS (SELECT 1 FROM refresh_tokens rt WHERE rt.user_id = u.id AND rt.created_at >= $2 AND rt.created_at < $3)
+			            OR EXISTS (SELECT 1 FROM task_events e WHERE e.user_id = u.id AND e.created_at >= $2 AND e.created_at < $3)
+			          THEN 1 ELSE 0 END),
+			($6, (SELECT COUNT(*) FROM tasks t WHERE t.user_id = u.id AND t.created_at >= $2 AND t.created_at < $3))
+		) AS m(metric, quantity)
+		WHERE m.quantity > 0` + meteringUpsert
+
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	res, err := r.db.ExecContext(ctx, query, start.Format(meteringDay), start, start.AddDate(0, 0, 1), at,
+		domain.MeterActiveUsers, domain.MeterTasksCreated)
+	if err != nil {
+		return 0, fmt.Errorf("meteringRepository.RecordActivity: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func (r *meteringRepository) RecordStorage(ctx context.Context, day, at time.Time) (int, error) {
+	// Users who emptied their storage since the last run get a zero, so the
+	// day does not keep an earlier reading.
+	query := `
+		INSERT INTO metering_events (user_id, metric, day, quantity, recorded_at)
+		SELECT u.id, $2, $1::date, COALESCE(SUM(a.size_bytes), 0), $3
+		FROM users u
+		LEFT JOIN attachments a ON a.user_id = u.id AND a.status = $4
+		GROUP BY u.id
+		HAVING COALESCE(SUM(a.size_bytes), 0) > 0
+		    OR EXISTS (SELECT 1 FROM metering_events m WHERE m.user_id = u.id AND m.metric = $2 AND m.day = $1::date)` + meteringUpsert
+
+	res, err := r.db.ExecContext(ctx, query, day.Format(meteringDay), domain.MeterStorageBytes, at, domain.AttachmentUploaded)
+	if err != nil {
+		return 0, fmt.Errorf("meteringRepository.RecordStorage: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+func (r *meteringRepository) Reconcile(ctx context.Context, from, to time.Time) ([]domain.MeterReconciliation, error) {
+	rows := []domain.MeterReconciliation{}
+	query := `
+		SELECT metric,
+		       SUM(quantity)                                                                        AS metered,
+		       COALESCE(SUM(invoiced_quantity), 0)                                                  AS invoiced,
+		       COUNT(*) FILTER (WHERE invoiced_at IS NULL)                                          AS uninvoiced,
+		       COUNT(*) FILTER (WHERE invoiced_at IS NOT NULL AND invoiced_quantity IS DISTINCT FROM quantity) AS mismatched
+		FROM metering_events
+		WHERE day BETWEEN $1::date AND $2::date
+		GROUP BY metric`
+	if err := r.db.SelectContext(ctx, &rows, query, from.Format(meteringDay), to.Format(meteringDay)); err != nil {
+		return nil, fmt.Errorf("meteringRepository.Reconcile: %w", err)
+	}
+	return rows, nil
+}
+
+func (r *meteringRepository) ListMismatched(ctx context.Context, from, to time.Time, limit int) ([]*domain.MeteringEvent, error) {
+	events := []*domain.MeteringEvent{}
+	query := `
+		SELECT * FROM metering_events
+		WHERE day BETWEEN $1::date AND $2::date
+		  AND invoiced_at IS NOT NULL AND invoiced_quantity IS DISTINCT FROM quantity
+		ORDER BY day, metric, user_id
+		LIMIT $3`
+	if err := r.db.SelectContext(ctx, &events, query, from.Format(meteringDay), to.Format(meteringDay), limit); err != nil {
+		return nil, fmt.Errorf("meteringRepository.ListMismatched: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type notificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewNotificationRepository creates a new PostgreSQL-backed NotificationRepository.
+func NewNotificationRepository(db *sqlx.DB) domain.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, n *domain.Notification) error {
+	if len(n.Data) == 0 {
+		n.Data = []byte("{}")
+	}
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, body, data, event_count, read_at, created_at)
+		VALUES (:id, :user_id, :type, :title, :body, :data, :event_count, :read_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, n); err != nil {
+		return fmt.Errorf("notificationRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *notificationRepository) ListByUserID(
+	ctx context.Context,
+	userID uuid.UUID,
+	unreadOnly bool,
+	page, limit int,
+) ([]*domain.Notification, int, error) {
+	where := "user_id = $1 AND snoozed_until IS NULL"
+	if unreadOnly {
+		where += " AND read_at IS NULL"
+	}
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, "SELECT COUNT(*) FROM notifications WHERE "+where, userID); err != nil {
+		return nil, 0, fmt.Errorf("notificationRepository.ListByUserID count: %w", err)
+	}
+
+	var notifications []*domain.Notification
+	query := fmt.Sprintf(
+		"SELECT * FROM notifications WHERE %s ORDER BY created_at DESC LIMIT $2 OFFSET $3", where,
+	)
+	if err := r.db.SelectContext(ctx, &notifications, query, userID, limit, (page-1)*limit); err != nil {
+		return nil, 0, fmt.Errorf("notificationRepository.ListByUserID select: %w", err)
+	}
+	return notifications, total, nil
+}
+
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL AND snoozed_until IS NULL`, userID,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("notificationRepository.CountUnread: %w", err)
+	}
+	return count, nil
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 AND user_id = $2`, id, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("notificationRepository.MarkRead: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL AND snoozed_until IS NULL`, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("notificationRepository.MarkAllRead: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) Snooze(ctx context.Context, id, userID uuid.UUID, until time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET snoozed_until = $3 WHERE id = $1 AND user_id = $2`, id, userID, until,
+	)
+	if err != nil {
+		return fmt.Errorf("notificationRepository.Snooze: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *notificationRepository) ListSnoozedDue(ctx context.Context, before time.Time, limit int) ([]*domain.Notification, error) {
+	var notifications []*domain.Notification
+	query := `
+		SELECT * FROM notifications
+		WHERE snoozed_until IS NOT NULL AND snoozed_until <= $1
+		ORDER BY snoozed_until
+		LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &notifications, query, before, limit); err != nil {
+		return nil, fmt.Errorf("notificationRepository.ListSnoozedDue: %w", err)
+	}
+	return notifications, nil
+}
+
+// Resurface clears the snooze and moves the notifications back to the top of
+// the feed as unread.
+func (r *notificationRepository) Resurface(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE notifications SET snoozed_until = NULL, read_at = NULL, created_at = NOW() WHERE id = ANY($1)`,
+		pq.Array(ids),
+	)
+	if err != nil {
+		return fmt.Errorf("notificationRepository.Resurface: %w", err)
+	}
+	return nil
+}
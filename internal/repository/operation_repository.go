@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type operationRepository struct {
+	db *sqlx.DB
+}
+
+// NewOperationRepository creates a new PostgreSQL-backed OperationRepository.
+func NewOperationRepository(db *sqlx.DB) domain.OperationRepository {
+	return &operationRepository{db: db}
+}
+
+// operationColumns selects an operation without loading its file.
+const operationColumns = `
+	id, user_id, kind, status, progress, result, error, file IS NOT NULL AS has_file,
+	cancel_requested, created_at, started_at, finished_at, updated_at`
+
+func (r *operationRepository) Create(ctx context.Context, op *domain.Operation) error {
+	query := `
+		INSERT INTO operations (id, user_id, kind, status, created_at, updated_at)
+		VALUES (:id, :user_id, :kind, :status, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, op); err != nil {
+		return fmt.Errorf("operationRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *operationRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Operation, error) {
+	var op domain.Operation
+	query := `SELECT ` + operationColumns + ` FROM operations WHERE id = $1`
+	if err := r.db.GetContext(ctx, &op, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("operationRepository.FindByID: %w", err)
+	}
+	return &op, nil
+}
+
+func (r *operationRepository) FindFile(ctx context.Context, id uuid.UUID) (*domain.Download, error) {
+	var file domain.Download
+	query := `SELECT file_name, file_type, file FROM operations WHERE id = $1 AND file IS NOT NULL`
+	if err := r.db.GetContext(ctx, &file, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("operationRepository.FindFile: %w", err)
+	}
+	return &file, nil
+}
+
+func (r *operationRepository) Start(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE operations SET status = 'running', started_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("operationRepository.Start: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *operationRepository) Heartbeat(ctx context.Context, id uuid.UUID, progress int) (bool, error) {
+	query := `
+		UPDATE operations SET progress = GREATEST(progress, $2), updated_at = NOW()
+		WHERE id = $1
+		RETURNING cancel_requested`
+
+	var cancel bool
+	if err := r.db.GetContext(ctx, &cancel, query, id, progress); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrNotFound
+		}
+		return false, fmt.Errorf("operationRepository.Heartbeat: %w", err)
+	}
+	return cancel, nil
+}
+
+func (r *operationRepository) Finish(ctx context.Context, op *domain.Operation, file *domain.Download) error {
+	if file == nil {
+		file = &domain.Download{}
+	}
+	var result any
+	if op.Result != nil {
+		result = []byte(op.Result)
+	}
+	query := `
+		UPDATE operations SET
+			status = $2, progress = $3, result = $4, error = $5,
+			file = $6, file_name = NULLIF($7, ''), file_type = NULLIF($8, ''),
+			finished_at = $9, updated_at = $9
+		WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, query,
+		op.ID, op.Status, op.Progress, result, op.Error, file.Data, file.Name, file.ContentType, op.FinishedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("operationRepository.Finish: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *operationRepository) RequestCancel(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE operations SET
+			cancel_requested = TRUE,
+			status = CASE WHEN status = 'pending' THEN 'cancelled' ELSE status END,
+			finished_at = CASE WHEN status = 'pending' THEN NOW() ELSE finished_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'running')`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("operationRepository.RequestCancel: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *operationRepository) FailStale(ctx context.Context, before time.Time) (int, error) {
+	query := `
+		UPDATE operations SET status = 'failed', error = 'the operation was interrupted', finished_at = NOW(), updated_at = NOW()
+		WHERE status IN ('pending', 'running') AND updated_at < $1`
+	res, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("operationRepository.FailStale: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("operationRepository.FailStale: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *operationRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM operations WHERE finished_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("operationRepository.DeleteFinishedBefore: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("operationRepository.DeleteFinishedBefore: %w", err)
+	}
+	return int(n), nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type personalAccessTokenRepository struct {
+	db *sqlx.DB
+}
+
+// NewPersonalAccessTokenRepository creates a new PostgreSQL-backed PersonalAccessTokenRepository.
+func NewPersonalAccessTokenRepository(db *sqlx.DB) domain.PersonalAccessTokenRepository {
+	return &personalAccessTokenRepository{db: db}
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, t *domain.PersonalAccessToken) error {
+	query := `
+		INSERT INTO personal_access_tokens (id, user_id, name, scopes, hint, token_hash, expires_at, created_at)
+		VALUES (:id, :user_id, :name, :scopes, :hint, :token_hash, :expires_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, t); err != nil {
+		return fmt.Errorf("personalAccessTokenRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *personalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	var t domain.PersonalAccessToken
+	if err := r.db.GetContext(ctx, &t, `SELECT * FROM personal_access_tokens WHERE token_hash = $1`, tokenHash); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("personalAccessTokenRepository.FindByHash: %w", err)
+	}
+	return &t, nil
+}
+
+func (r *personalAccessTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*domain.PersonalAccessToken, error) {
+	tokens := []*domain.PersonalAccessToken{}
+	query := `SELECT * FROM personal_access_tokens WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &tokens, query, userID); err != nil {
+		return nil, fmt.Errorf("personalAccessTokenRepository.ListByUser: %w", err)
+	}
+	return tokens, nil
+}
+
+func (r *personalAccessTokenRepository) CountActive(ctx context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	var n int
+	query := `
+		SELECT COUNT(*) FROM personal_access_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > $2)`
+	if err := r.db.GetContext(ctx, &n, query, userID, now); err != nil {
+		return 0, fmt.Errorf("personalAccessTokenRepository.CountActive: %w", err)
+	}
+	return n, nil
+}
+
+func (r *personalAccessTokenRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `UPDATE personal_access_tokens SET last_used_at = $2 WHERE id = $1`, id, at); err != nil {
+		return fmt.Errorf("personalAccessTokenRepository.Touch: %w", err)
+	}
+	return nil
+}
+
+func (r *personalAccessTokenRepository) Revoke(ctx context.Context, id, userID uuid.UUID, at time.Time) error {
+	query := `UPDATE personal_access_tokens SET revoked_at = COALESCE(revoked_at, $3) WHERE id = $1 AND user_id = $2`
+	res, err := r.db.ExecContext(ctx, query, id, userID, at)
+	if err != nil {
+		return fmt.Errorf("personalAccessTokenRepository.Revoke: %w", err)
+	}
+	return checkRowsAffected(res)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type projectMemberRepository struct {
+	db *sqlx.DB
+}
+
+// NewProjectMemberRepository creates a new PostgreSQL-backed ProjectMemberRepository.
+func NewProjectMemberRepository(db *sqlx.DB) domain.ProjectMemberRepository {
+	return &projectMemberRepository{db: db}
+}
+
+func (r *projectMemberRepository) Add(ctx context.Context, member *domain.ProjectMember) error {
+	query := `
+		INSERT INTO project_members (project_id, user_id, created_at)
+		VALUES (:project_id, :user_id, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, member); err != nil {
+		return fmt.Errorf("projectMemberRepository.Add: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *projectMemberRepository) Remove(ctx context.Context, projectID, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	if err != nil {
+		return fmt.Errorf("projectMemberRepository.Remove: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *projectMemberRepository) ListByProjectID(ctx context.Context, projectID uuid.UUID) ([]*domain.ProjectMember, error) {
+	members := []*domain.ProjectMember{}
+	query := `
+		SELECT m.project_id, m.user_id, u.email, u.name, m.created_at
+		FROM project_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.project_id = $1
+		ORDER BY m.created_at, m.user_id`
+	if err := r.db.SelectContext(ctx, &members, query, projectID); err != nil {
+		return nil, fmt.Errorf("projectMemberRepository.ListByProjectID: %w", err)
+	}
+	return members, nil
+}
+
+func (r *projectMemberRepository) CountByProjectID(ctx context.Context, projectID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.GetContext(ctx, &n, `SELECT COUNT(*) FROM project_members WHERE project_id = $1`, projectID); err != nil {
+		return 0, fmt.Errorf("projectMemberRepository.CountByProjectID: %w", err)
+	}
+	return n, nil
+}
+
+func (r *projectMemberRepository) IsMember(ctx context.Context, projectID, userID uuid.UUID) (bool, error) {
+	var ok bool
+	query := `SELECT EXISTS (SELECT 1 FROM project_members WHERE project_id = $1 AND user_id = $2)`
+	if err := r.db.GetContext(ctx, &ok, query, projectID, userID); err != nil {
+		return false, fmt.Errorf("projectMemberRepository.IsMember: %w", err)
+	}
+	return ok, nil
+}
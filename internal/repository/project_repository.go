@@ -5,10 +5,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type projectRepository struct {
@@ -22,12 +25,13 @@ func NewProjectRepository(db *sqlx.DB) domain.ProjectRepository {
 
 func (r *projectRepository) Create(ctx context.Context, project *domain.Project) error {
 	query := `
-		INSERT INTO projects (id, user_id, name, description, type, color, created_at, updated_at)
-		VALUES (:id, :user_id, :name, :description, :type, :color, :created_at, :updated_at)`
+		INSERT INTO projects (id, user_id, name, description, type, color, icon, created_at, updated_at)
+		VALUES (:id, :user_id, :name, :description, :type, :color, :icon, :created_at, :updated_at)`
 
 	if _, err := r.db.NamedExecContext(ctx, query, project); err != nil {
 		return fmt.Errorf("projectRepository.Create: %w", mapDBError(err))
 	}
+	project.Version = 1 // the column default
 	return nil
 }
 
@@ -50,39 +54,232 @@ func (r *projectRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain
 }
 
 func (r *projectRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Project, error) {
-	var projects []*domain.Project
-	query := `
-		SELECT p.*, COUNT(t.id) AS task_count
-		FROM projects p
-		LEFT JOIN tasks t ON t.project_id = p.id AND t.deleted_at IS NULL
-		WHERE p.user_id = $1 AND p.deleted_at IS NULL
-		GROUP BY p.id
-		ORDER BY p.created_at DESC`
+	return r.ListFields(ctx, userID, nil, false)
+}
+
+func (r *projectRepository) ListFields(ctx context.Context, userID uuid.UUID, fields domain.Fields, archived bool) ([]*domain.Project, error) {
+	// The task count join is only paid for when it is asked for.
+	cols, join := "p.*, COUNT(t.id) AS task_count", true
+	if fields != nil {
+		selected := make([]string, 0, len(fields))
+		join = false
+		for _, f := range fields {
+			if f == "task_count" {
+				selected, join = append(selected, "COUNT(t.id) AS task_count"), true
+				continue
+			}
+			selected = append(selected, "p."+pq.QuoteIdentifier(f))
+		}
+		cols = strings.Join(selected, ", ")
+	}
+	query := `SELECT ` + cols + ` FROM projects p`
+	if join {
+		query += ` LEFT JOIN tasks t ON t.project_id = p.id AND t.deleted_at IS NULL`
+	}
+	query += ` WHERE (p.user_id = $1 OR p.id IN (SELECT project_id FROM project_members WHERE user_id = $1))
+		AND p.deleted_at IS NULL`
+	if archived {
+		query += ` AND p.archived_at IS NOT NULL`
+	} else {
+		query += ` AND p.archived_at IS NULL`
+	}
+	if join {
+		query += ` GROUP BY p.id`
+	}
+	query += ` ORDER BY p.created_at DESC`
 
+	var projects []*domain.Project
 	if err := r.db.SelectContext(ctx, &projects, query, userID); err != nil {
-		return nil, fmt.Errorf("projectRepository.ListByUserID: %w", err)
+		return nil, fmt.Errorf("projectRepository.ListFields: %w", err)
 	}
 	return projects, nil
 }
 
-func (r *projectRepository) Update(ctx context.Context, project *domain.Project) error {
+func (r *projectRepository) Update(ctx context.Context, project *domain.Project, ifVersion *int64) error {
 	query := `
 		UPDATE projects
-		SET name = :name, description = :description, type = :type, color = :color, updated_at = :updated_at
+		SET name = :name, description = :description, type = :type, color = :color, icon = :icon, updated_at = :updated_at
 		WHERE id = :id AND deleted_at IS NULL`
 
-	res, err := r.db.NamedExecContext(ctx, query, project)
+	query, args, err := sqlx.Named(query, project)
 	if err != nil {
+		return fmt.Errorf("projectRepository.Update: %w", err)
+	}
+	if ifVersion != nil {
+		query += ` AND version = ?`
+		args = append(args, *ifVersion)
+	}
+	query += ` RETURNING version`
+	if err := r.db.GetContext(ctx, &project.Version, r.db.Rebind(query), args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if ifVersion != nil {
+				return staleOrMissing(ctx, r.db, "projects", project.ID)
+			}
+			return domain.ErrNotFound
+		}
 		return fmt.Errorf("projectRepository.Update: %w", mapDBError(err))
 	}
-	return checkRowsAffected(res)
+	return nil
 }
 
-func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE projects SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.ExecContext(ctx, query, id)
+func (r *projectRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	query := `UPDATE projects SET archived_at = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, archivedAt)
 	if err != nil {
-		return fmt.Errorf("projectRepository.Delete: %w", err)
+		return fmt.Errorf("projectRepository.SetArchived: %w", err)
 	}
 	return checkRowsAffected(res)
 }
+
+func (r *projectRepository) Stats(ctx context.Context, projectID, userID uuid.UUID) (*domain.ProjectStats, error) {
+	query := `
+		SELECT
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = 'todo') AS "by_status.todo",
+			COUNT(*) FILTER (WHERE status = 'in_progress') AS "by_status.in_progress",
+			COUNT(*) FILTER (WHERE status = 'done') AS "by_status.done",
+			COUNT(*) FILTER (WHERE priority = 'low') AS "by_priority.low",
+			COUNT(*) FILTER (WHERE priority = 'medium') AS "by_priority.medium",
+			COUNT(*) FILTER (WHERE priority = 'high') AS "by_priority.high",
+			COALESCE(100.0 * COUNT(*) FILTER (WHERE status = 'done') / NULLIF(COUNT(*), 0), 0)::float8 AS completion_rate_percent,
+			COUNT(*) FILTER (WHERE ` + taskDeadlineSQL("due_date", userTimezoneSQL("$2")) + ` < NOW() AND status != 'done') AS overdue,
+			COALESCE(AVG(EXTRACT(EPOCH FROM (completed_at - created_at)) / 3600)
+				FILTER (WHERE status = 'done' AND completed_at IS NOT NULL), 0)::float8 AS avg_completion_time_hours
+		FROM tasks
+		WHERE project_id = $1 AND deleted_at IS NULL AND archived_at IS NULL`
+
+	stats := domain.ProjectStats{ProjectID: projectID}
+	if err := r.db.GetContext(ctx, &stats, query, projectID, userID); err != nil {
+		return nil, fmt.Errorf("projectRepository.Stats: %w", err)
+	}
+	return &stats, nil
+}
+
+func (r *projectRepository) Delete(ctx context.Context, id uuid.UUID, strategy domain.ProjectDeleteStrategy, ifVersion *int64) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("projectRepository.Delete begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// The row lock conflicts with the key-share lock a task insert takes on
+	// its project, so no task can join the project until the delete is done.
+	// It also holds off other writes while the version is compared.
+	var version int64
+	if err := tx.GetContext(ctx, &version, `SELECT version FROM projects WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrNotFound
+		}
+		return 0, fmt.Errorf("projectRepository.Delete: %w", err)
+	}
+	if ifVersion != nil && *ifVersion != version {
+		return 0, domain.ErrPreconditionFailed
+	}
+
+	var tasks int64
+	switch strategy {
+	case domain.ProjectDeleteBlock:
+		var open bool
+		if err := tx.GetContext(ctx, &open, `SELECT EXISTS (SELECT 1 FROM tasks WHERE project_id = $1 AND deleted_at IS NULL)`, id); err != nil {
+			return 0, fmt.Errorf("projectRepository.Delete: %w", err)
+		}
+		if open {
+			return 0, domain.ErrProjectNotEmpty
+		}
+	case domain.ProjectDeleteCascade:
+		res, err := tx.ExecContext(ctx, `UPDATE tasks SET deleted_at = NOW(), updated_at = NOW() WHERE project_id = $1 AND deleted_at IS NULL`, id)
+		if err != nil {
+			return 0, fmt.Errorf("projectRepository.Delete tasks: %w", err)
+		}
+		tasks, _ = res.RowsAffected()
+	default:
+		res, err := tx.ExecContext(ctx, `UPDATE tasks SET project_id = NULL, updated_at = NOW() WHERE project_id = $1 AND deleted_at IS NULL`, id)
+		if err != nil {
+			return 0, fmt.Errorf("projectRepository.Delete tasks: %w", err)
+		}
+		tasks, _ = res.RowsAffected()
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE projects SET deleted_at = NOW() WHERE id = $1`, id); err != nil {
+		return 0, fmt.Errorf("projectRepository.Delete: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("projectRepository.Delete commit: %w", err)
+	}
+	return int(tasks), nil
+}
+
+func (r *projectRepository) Duplicate(ctx context.Context, sourceID uuid.UUID, project *domain.Project, withTasks bool, shiftDays int) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `
+		INSERT INTO projects (id, user_id, name, description, type, color, icon, created_at, updated_at)
+		VALUES (:id, :user_id, :name, :description, :type, :color, :icon, :created_at, :updated_at)`
+	if _, err := tx.NamedExecContext(ctx, query, project); err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate: %w", mapDBError(err))
+	}
+	project.Version = 1 // the column default
+	if !withTasks {
+		if err := tx.Commit(); err != nil {
+			return 0, fmt.Errorf("projectRepository.Duplicate commit: %w", err)
+		}
+		return 0, nil
+	}
+
+	// copies maps each open task to the id of its copy. A subtask whose
+	// parent is not copied becomes a top-level task.
+	if _, err := tx.ExecContext(ctx, `
+		CREATE TEMP TABLE project_copies ON COMMIT DROP AS
+		SELECT id AS source_id, uuid_generate_v4() AS copy_id
+		FROM tasks
+		WHERE project_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status <> 'done'`, sourceID); err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate copies: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO tasks (
+			id, user_id, project_id, parent_id, title, description, status, priority,
+			estimated_hours, energy, context, due_date, due_expr, start_date,
+			scheduled_at, scheduled_duration, recurrence, no_escalation,
+			smart_score, sort_order, created_at, updated_at, status_changed_at)
+		SELECT c.copy_id, $2, $3, p.copy_id, t.title, t.description, 'todo', t.priority,
+			t.estimated_hours, t.energy, t.context,
+			t.due_date + make_interval(days => $4), t.due_expr,
+			t.start_date + make_interval(days => $4),
+			t.scheduled_at + make_interval(days => $4), t.scheduled_duration,
+			t.recurrence, t.no_escalation, t.smart_score, t.sort_order, NOW(), NOW(), NOW()
+		FROM tasks t
+		JOIN project_copies c ON c.source_id = t.id
+		LEFT JOIN project_copies p ON p.source_id = t.parent_id
+		WHERE t.project_id = $1`, sourceID, project.UserID, project.ID, shiftDays)
+	if err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate tasks: %w", mapDBError(err))
+	}
+	tasks, _ := res.RowsAffected()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO task_tags (task_id, tag_id)
+		SELECT c.copy_id, tt.tag_id
+		FROM task_tags tt
+		JOIN project_copies c ON c.source_id = tt.task_id
+		JOIN tags g ON g.id = tt.tag_id AND g.user_id = $1`, project.UserID); err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate tags: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO task_dependencies (task_id, blocked_by_id)
+		SELECT c.copy_id, b.copy_id
+		FROM task_dependencies d
+		JOIN project_copies c ON c.source_id = d.task_id
+		JOIN project_copies b ON b.source_id = d.blocked_by_id`); err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate dependencies: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("projectRepository.Duplicate commit: %w", err)
+	}
+	return int(tasks), nil
+}
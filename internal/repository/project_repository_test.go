@@ -0,0 +1,46 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectRepository_Update_RejectsStaleConcurrentWrite(t *testing.T) {
+	version := int64(2)
+	db, _ := newFakeDB(t, versionedRow(&version))
+	repo := repository.NewProjectRepository(db)
+	ctx := context.Background()
+
+	read := int64(2)
+	id := uuid.New()
+	require.NoError(t, repo.Update(ctx, &domain.Project{ID: id, Name: "Home"}, &read))
+	assert.ErrorIs(t, repo.Update(ctx, &domain.Project{ID: id, Name: "House"}, &read), domain.ErrPreconditionFailed)
+	assert.Equal(t, int64(3), version)
+}
+
+func TestProjectRepository_Delete_ComparesVersionUnderLock(t *testing.T) {
+	db, fake := newFakeDB(t, func(query string, _ []any) ([]string, [][]any, int64) {
+		if strings.Contains(query, "FOR UPDATE") {
+			return []string{"version"}, [][]any{{int64(7)}}, 0
+		}
+		return nil, nil, 1
+	})
+	repo := repository.NewProjectRepository(db)
+	ctx := context.Background()
+
+	stale := int64(6)
+	_, err := repo.Delete(ctx, uuid.New(), domain.ProjectDeleteInbox, &stale)
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	require.Len(t, fake.calls, 1, "nothing is written after a stale version")
+
+	current := int64(7)
+	_, err = repo.Delete(ctx, uuid.New(), domain.ProjectDeleteInbox, &current)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type quietHoursRepository struct {
+	db *sqlx.DB
+}
+
+// NewQuietHoursRepository creates a new PostgreSQL-backed QuietHoursRepository.
+func NewQuietHoursRepository(db *sqlx.DB) domain.QuietHoursRepository {
+	return &quietHoursRepository{db: db}
+}
+
+func (r *quietHoursRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error) {
+	var q domain.QuietHours
+	if err := r.db.GetContext(ctx, &q, `SELECT * FROM user_quiet_hours WHERE user_id = $1`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("quietHoursRepository.FindByUserID: %w", err)
+	}
+	q.Days = domain.WeekdayNames(q.QuietDays)
+	return &q, nil
+}
+
+func (r *quietHoursRepository) Upsert(ctx context.Context, q *domain.QuietHours) error {
+	query := `
+		INSERT INTO user_quiet_hours (user_id, enabled, timezone, start_time, end_time, quiet_days, updated_at)
+		VALUES (:user_id, :enabled, :timezone, :start_time, :end_time, :quiet_days, :updated_at)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled    = EXCLUDED.enabled,
+			timezone   = EXCLUDED.timezone,
+			start_time = EXCLUDED.start_time,
+			end_time   = EXCLUDED.end_time,
+			quiet_days = EXCLUDED.quiet_days,
+			updated_at = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, q); err != nil {
+		return fmt.Errorf("quietHoursRepository.Upsert: %w", mapDBError(err))
+	}
+	return nil
+}
+
+type deferredNotificationRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeferredNotificationRepository creates a new PostgreSQL-backed DeferredNotificationRepository.
+func NewDeferredNotificationRepository(db *sqlx.DB) domain.DeferredNotificationRepository {
+	return &deferredNotificationRepository{db: db}
+}
+
+func (r *deferredNotificationRepository) Create(ctx context.Context, n *domain.DeferredNotification) error {
+	if len(n.Data) == 0 {
+		n.Data = []byte("{}")
+	}
+	query := `
+		INSERT INTO deferred_notifications
+			(id, user_id, channel, type, title, body, data, event_count, deliver_after, created_at)
+		VALUES
+			(:id, :user_id, :channel, :type, :title, :body, :data, :event_count, :deliver_after, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, n); err != nil {
+		return fmt.Errorf("deferredNotificationRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *deferredNotificationRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*domain.DeferredNotification, error) {
+	var items []*domain.DeferredNotification
+	query := `
+		SELECT * FROM deferred_notifications
+		WHERE deliver_after <= $1
+		ORDER BY user_id, channel, created_at
+		LIMIT $2`
+
+	if err := r.db.SelectContext(ctx, &items, query, before, limit); err != nil {
+		return nil, fmt.Errorf("deferredNotificationRepository.ListDue: %w", err)
+	}
+	return items, nil
+}
+
+func (r *deferredNotificationRepository) DeleteByIDs(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM deferred_notifications WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+		return fmt.Errorf("deferredNotificationRepository.DeleteByIDs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type referralRepository struct {
+	db *sqlx.DB
+}
+
+// NewReferralRepository creates a new PostgreSQL-backed ReferralRepository.
+func NewReferralRepository(db *sqlx.DB) domain.ReferralRepository {
+	return &referralRepository{db: db}
+}
+
+func (r *referralRepository) Create(ctx context.Context, ref *domain.Referral) error {
+	query := `
+		INSERT INTO referrals (id, referrer_id, referred_id, code, source, status, created_at)
+		VALUES (:id, :referrer_id, :referred_id, :code, :source, :status, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, ref); err != nil {
+		return fmt.Errorf("referralRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *referralRepository) ListByReferrer(ctx context.Context, referrerID uuid.UUID) ([]*domain.Referral, error) {
+	referrals := []*domain.Referral{}
+	query := `
+		SELECT r.*, u.name AS referred_name
+		FROM referrals r
+		JOIN users u ON u.id = r.referred_id
+		WHERE r.referrer_id = $1
+		ORDER BY r.created_at DESC`
+	if err := r.db.SelectContext(ctx, &referrals, query, referrerID); err != nil {
+		return nil, fmt.Errorf("referralRepository.ListByReferrer: %w", err)
+	}
+	return referrals, nil
+}
+
+func (r *referralRepository) Qualify(ctx context.Context, referredID uuid.UUID, at time.Time) (*domain.Referral, error) {
+	query := `
+		UPDATE referrals SET status = $2, qualified_at = $3
+		WHERE referred_id = $1 AND status = $4
+		RETURNING *`
+
+	var ref domain.Referral
+	if err := r.db.GetContext(ctx, &ref, query, referredID, domain.ReferralQualified, at, domain.ReferralSignedUp); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("referralRepository.Qualify: %w", err)
+	}
+	return &ref, nil
+}
+
+func (r *referralRepository) ListQualified(ctx context.Context, limit int) ([]*domain.Referral, error) {
+	referrals := []*domain.Referral{}
+	query := `SELECT * FROM referrals WHERE status = $1 ORDER BY qualified_at LIMIT $2`
+	if err := r.db.SelectContext(ctx, &referrals, query, domain.ReferralQualified, limit); err != nil {
+		return nil, fmt.Errorf("referralRepository.ListQualified: %w", err)
+	}
+	return referrals, nil
+}
+
+func (r *referralRepository) MarkCredited(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE referrals SET status = $2, credited_at = $3 WHERE id = $1 AND status = $4`
+	res, err := r.db.ExecContext(ctx, query, id, domain.ReferralCredited, at, domain.ReferralQualified)
+	if err != nil {
+		return fmt.Errorf("referralRepository.MarkCredited: %w", err)
+	}
+	return checkRowsAffected(res)
+}
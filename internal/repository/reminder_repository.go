@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type reminderRepository struct {
+	db *sqlx.DB
+}
+
+// NewReminderRepository creates a new PostgreSQL-backed ReminderRepository.
+func NewReminderRepository(db *sqlx.DB) domain.ReminderRepository {
+	return &reminderRepository{db: db}
+}
+
+func (r *reminderRepository) Create(ctx context.Context, rem *domain.Reminder) error {
+	query := `
+		INSERT INTO task_reminders (id, task_id, user_id, remind_at, offset_minutes, fire_at, status, created_at)
+		VALUES (:id, :task_id, :user_id, :remind_at, :offset_minutes, :fire_at, :status, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, rem); err != nil {
+		return fmt.Errorf("reminderRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *reminderRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Reminder, error) {
+	var rem domain.Reminder
+	if err := r.db.GetContext(ctx, &rem, `SELECT * FROM task_reminders WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("reminderRepository.FindByID: %w", err)
+	}
+	return &rem, nil
+}
+
+func (r *reminderRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.Reminder, error) {
+	reminders := []*domain.Reminder{}
+	query := `SELECT * FROM task_reminders WHERE task_id = $1 ORDER BY fire_at NULLS LAST, created_at`
+	if err := r.db.SelectContext(ctx, &reminders, query, taskID); err != nil {
+		return nil, fmt.Errorf("reminderRepository.ListByTaskID: %w", err)
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepository) CountPending(ctx context.Context, taskID uuid.UUID) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM task_reminders WHERE task_id = $1 AND status = $2`
+	if err := r.db.GetContext(ctx, &n, query, taskID, domain.ReminderPending); err != nil {
+		return 0, fmt.Errorf("reminderRepository.CountPending: %w", err)
+	}
+	return n, nil
+}
+
+func (r *reminderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM task_reminders WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("reminderRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *reminderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*domain.Reminder, error) {
+	reminders := []*domain.Reminder{}
+	query := `
+		SELECT * FROM task_reminders
+		WHERE status = $1 AND fire_at <= $2
+		ORDER BY fire_at
+		LIMIT $3`
+	if err := r.db.SelectContext(ctx, &reminders, query, domain.ReminderPending, now, limit); err != nil {
+		return nil, fmt.Errorf("reminderRepository.ListDue: %w", err)
+	}
+	return reminders, nil
+}
+
+func (r *reminderRepository) MarkFired(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE task_reminders SET status = $2, fired_at = $3 WHERE id = $1 AND status = $4`
+	res, err := r.db.ExecContext(ctx, query, id, domain.ReminderFired, at, domain.ReminderPending)
+	if err != nil {
+		return fmt.Errorf("reminderRepository.MarkFired: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *reminderRepository) Dismiss(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `UPDATE task_reminders SET status = $2, dismissed_at = COALESCE(dismissed_at, $3) WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id, domain.ReminderDismissed, at)
+	if err != nil {
+		return fmt.Errorf("reminderRepository.Dismiss: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *reminderRepository) Reschedule(ctx context.Context, taskID uuid.UUID, dueDate *time.Time) error {
+	query := `
+		UPDATE task_reminders
+		SET fire_at = $2::timestamptz - make_interval(mins => offset_minutes)
+		WHERE task_id = $1 AND status = $3 AND offset_minutes IS NOT NULL`
+	if _, err := r.db.ExecContext(ctx, query, taskID, dueDate, domain.ReminderPending); err != nil {
+		return fmt.Errorf("reminderRepository.Reschedule: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type retentionRepository struct {
+	db *sqlx.DB
+}
+
+// NewRetentionRepository creates a new PostgreSQL-backed RetentionRepository.
+func NewRetentionRepository(db *sqlx.DB) domain.RetentionRepository {
+	return &retentionRepository{db: db}
+}
+
+func (r *retentionRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.RetentionOverride, error) {
+	var overrides []domain.RetentionOverride
+	query := `SELECT * FROM retention_overrides WHERE user_id = $1 ORDER BY entity_type`
+	if err := r.db.SelectContext(ctx, &overrides, query, userID); err != nil {
+		return nil, fmt.Errorf("retentionRepository.ListByUserID: %w", err)
+	}
+	return overrides, nil
+}
+
+func (r *retentionRepository) Upsert(ctx context.Context, o *domain.RetentionOverride) error {
+	query := `
+		INSERT INTO retention_overrides (user_id, entity_type, retention_days, updated_at)
+		VALUES (:user_id, :entity_type, :retention_days, :updated_at)
+		ON CONFLICT (user_id, entity_type) DO UPDATE SET
+			retention_days = EXCLUDED.retention_days,
+			updated_at     = EXCLUDED.updated_at`
+
+	if _, err := r.db.NamedExecContext(ctx, query, o); err != nil {
+		return fmt.Errorf("retentionRepository.Upsert: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *retentionRepository) Delete(ctx context.Context, userID uuid.UUID, entityType string) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM retention_overrides WHERE user_id = $1 AND entity_type = $2`, userID, entityType)
+	if err != nil {
+		return fmt.Errorf("retentionRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
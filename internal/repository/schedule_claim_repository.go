@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/jmoiron/sqlx"
+)
+
+type scheduleClaimRepository struct {
+	db *sqlx.DB
+}
+
+// NewScheduleClaimRepository creates a new PostgreSQL-backed
+// ScheduleClaimRepository.
+func NewScheduleClaimRepository(db *sqlx.DB) domain.ScheduleClaimRepository {
+	return &scheduleClaimRepository{db: db}
+}
+
+func (r *scheduleClaimRepository) Claim(ctx context.Context, task string, slot time.Time, holder string) (bool, error) {
+	// The row lock taken by the upsert serialises instances claiming the
+	// same task; the loser sees the winner's slot and updates nothing.
+	query := `
+		INSERT INTO scheduled_task_claims (task, slot, holder)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (task) DO UPDATE SET
+			slot       = EXCLUDED.slot,
+			holder     = EXCLUDED.holder,
+			claimed_at = NOW()
+		WHERE scheduled_task_claims.slot < EXCLUDED.slot`
+
+	res, err := r.db.ExecContext(ctx, query, task, slot, holder)
+	if err != nil {
+		return false, fmt.Errorf("scheduleClaimRepository.Claim: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("scheduleClaimRepository.Claim: %w", err)
+	}
+	return n == 1, nil
+}
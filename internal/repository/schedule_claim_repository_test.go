@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claimsTable answers the claim upsert like scheduled_task_claims: a row
+// is written for a new task or a later slot, and left alone otherwise.
+func claimsTable() func(string, []any) ([]string, [][]any, int64) {
+	slots := map[string]time.Time{}
+	return func(_ string, args []any) ([]string, [][]any, int64) {
+		task, slot := args[0].(string), args[1].(time.Time)
+		if held, ok := slots[task]; ok && !held.Before(slot) {
+			return nil, nil, 0
+		}
+		slots[task] = slot
+		return nil, nil, 1
+	}
+}
+
+func TestScheduleClaimRepository_Claim_OneHolderPerSlot(t *testing.T) {
+	db, fake := newFakeDB(t, claimsTable())
+	repo := repository.NewScheduleClaimRepository(db)
+	ctx := context.Background()
+	slot := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+
+	won, err := repo.Claim(ctx, "digest", slot, "api-1")
+	require.NoError(t, err)
+	assert.True(t, won)
+
+	won, err = repo.Claim(ctx, "digest", slot, "api-2")
+	require.NoError(t, err)
+	assert.False(t, won, "the slot is taken")
+
+	won, err = repo.Claim(ctx, "digest", slot.Add(-time.Hour), "api-2")
+	require.NoError(t, err)
+	assert.False(t, won, "a late tick for an earlier slot does not run")
+
+	won, err = repo.Claim(ctx, "digest", slot.Add(time.Hour), "api-2")
+	require.NoError(t, err)
+	assert.True(t, won, "the next slot is up for grabs")
+
+	won, err = repo.Claim(ctx, "reminders", slot, "api-2")
+	require.NoError(t, err)
+	assert.True(t, won, "tasks are claimed apart")
+
+	require.Len(t, fake.calls, 5)
+	query := strings.Join(strings.Fields(fake.calls[0].Query), " ")
+	assert.Contains(t, query, "ON CONFLICT (task) DO UPDATE")
+	assert.Contains(t, query, "WHERE scheduled_task_claims.slot < EXCLUDED.slot")
+	assert.Equal(t, []any{"digest", slot, "api-1"}, fake.calls[0].Args)
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// viewPredicates define each system view in terms of the ViewWindow tokens
+// bound by viewBinder.
+var viewPredicates = map[string]string{
+	domain.ViewToday:             "status != 'done' AND due_date >= :day_start AND due_date < :day_end AND (start_date IS NULL OR start_date <= :now)",
+	domain.ViewUpcoming:          "status != 'done' AND due_date >= :day_end AND due_date < :upcoming_end",
+	domain.ViewOverdue:           "status != 'done' AND " + taskDeadlineSQL("due_date", ":tz::text") + " < :now",
+	domain.ViewHighPriority:      "status != 'done' AND priority = 'high'",
+	domain.ViewRecentlyCompleted: "status = 'done' AND completed_at >= :completed_since",
+}
+
+// viewOrder sorts each view the way its list is read.
+var viewOrder = map[string]string{
+	domain.ViewToday:             "due_date, smart_score DESC",
+	domain.ViewUpcoming:          "due_date, smart_score DESC",
+	domain.ViewOverdue:           "due_date, smart_score DESC",
+	domain.ViewHighPriority:      "smart_score DESC, created_at DESC",
+	domain.ViewRecentlyCompleted: "completed_at DESC",
+}
+
+type smartViewRepository struct {
+	db *sqlx.DB
+}
+
+// NewSmartViewRepository creates a new PostgreSQL-backed SmartViewRepository.
+func NewSmartViewRepository(db *sqlx.DB) domain.SmartViewRepository {
+	return &smartViewRepository{db: db}
+}
+
+// viewBinder turns window tokens into positional parameters, numbering only
+// the tokens a query uses: PostgreSQL rejects parameters it cannot type.
+type viewBinder struct {
+	values map[string]any
+	index  map[string]int
+	args   []any
+}
+
+func newViewBinder(userID uuid.UUID, w domain.ViewWindow) *viewBinder {
+	return &viewBinder{
+		values: map[string]any{
+			":now":             w.Now,
+			":day_start":       w.DayStart,
+			":day_end":         w.DayEnd,
+			":upcoming_end":    w.UpcomingEnd,
+			":completed_since": w.CompletedSince,
+			":tz":              w.Timezone,
+		},
+		index: map[string]int{},
+		args:  []any{userID}, // $1
+	}
+}
+
+func (b *viewBinder) bind(predicate string) string {
+	// Longest tokens first so :day_end never matches inside a longer name.
+	for _, token := range []string{":completed_since", ":upcoming_end", ":day_start", ":day_end", ":now", ":tz"} {
+		if !strings.Contains(predicate, token) {
+			continue
+		}
+		n, ok := b.index[token]
+		if !ok {
+			b.args = append(b.args, b.values[token])
+			n = len(b.args)
+			b.index[token] = n
+		}
+		predicate = strings.ReplaceAll(predicate, token, fmt.Sprintf("$%d", n))
+	}
+	return predicate
+}
+
+func (r *smartViewRepository) Counts(ctx context.Context, userID uuid.UUID, w domain.ViewWindow) (map[string]int, error) {
+	// One scan of the user's tasks, one FILTERed count per view.
+	b := newViewBinder(userID, w)
+	columns := make([]string, 0, len(domain.SystemViews))
+	for _, v := range domain.SystemViews {
+		columns = append(columns, fmt.Sprintf("COUNT(*) FILTER (WHERE %s) AS %s", b.bind(viewPredicates[v.Key]), v.Key))
+	}
+	query := fmt.Sprintf(`SELECT %s FROM tasks WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND %s`, strings.Join(columns, ", "), taskLiveProjectWhere)
+
+	row := map[string]any{}
+	if err := r.db.QueryRowxContext(ctx, query, b.args...).MapScan(row); err != nil {
+		return nil, fmt.Errorf("smartViewRepository.Counts: %w", err)
+	}
+	counts := make(map[string]int, len(row))
+	for k, v := range row {
+		n, _ := v.(int64)
+		counts[k] = int(n)
+	}
+	return counts, nil
+}
+
+func (r *smartViewRepository) List(
+	ctx context.Context,
+	userID uuid.UUID,
+	key string,
+	w domain.ViewWindow,
+	page, limit int,
+) ([]*domain.Task, int, error) {
+	predicate, ok := viewPredicates[key]
+	if !ok {
+		return nil, 0, domain.ErrNotFound
+	}
+	b := newViewBinder(userID, w)
+	where := "user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND " + taskLiveProjectWhere + " AND " + b.bind(predicate)
+	args := b.args
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks WHERE %s", where)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("smartViewRepository.List count: %w", err)
+	}
+
+	listQuery := fmt.Sprintf(
+		"SELECT * FROM tasks WHERE %s ORDER BY pinned DESC, %s LIMIT $%d OFFSET $%d",
+		where, viewOrder[key], len(args)+1, len(args)+2,
+	)
+	tasks := []*domain.Task{}
+	if err := r.db.SelectContext(ctx, &tasks, listQuery, append(args, limit, (page-1)*limit)...); err != nil {
+		return nil, 0, fmt.Errorf("smartViewRepository.List select: %w", err)
+	}
+	return tasks, total, nil
+}
+
+func (r *smartViewRepository) Badges(ctx context.Context, userID uuid.UUID, w domain.ViewWindow) (*domain.Badges, error) {
+	b := newViewBinder(userID, w)
+	query := fmt.Sprintf(`
+		SELECT
+			(SELECT COUNT(*) FILTER (WHERE %s) FROM tasks t WHERE t.user_id = $1 AND t.deleted_at IS NULL AND t.archived_at IS NULL AND `+taskLiveProjectWhere+`) AS due_today,
+			(SELECT COUNT(*) FILTER (WHERE %s) FROM tasks t WHERE t.user_id = $1 AND t.deleted_at IS NULL AND t.archived_at IS NULL AND `+taskLiveProjectWhere+`) AS overdue,
+			(SELECT COUNT(*) FROM notifications n
+			 WHERE n.user_id = $1 AND n.read_at IS NULL AND n.snoozed_until IS NULL) AS unread_notifications`,
+		b.bind(viewPredicates[domain.ViewToday]), b.bind(viewPredicates[domain.ViewOverdue]))
+
+	var badges domain.Badges
+	if err := r.db.GetContext(ctx, &badges, query, b.args...); err != nil {
+		return nil, fmt.Errorf("smartViewRepository.Badges: %w", err)
+	}
+	return &badges, nil
+}
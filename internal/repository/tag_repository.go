@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type tagRepository struct {
+	db *sqlx.DB
+}
+
+// NewTagRepository creates a new PostgreSQL-backed TagRepository.
+func NewTagRepository(db *sqlx.DB) domain.TagRepository {
+	return &tagRepository{db: db}
+}
+
+// tagColumns selects a tag with the number of live tasks carrying it.
+const tagColumns = `
+	SELECT g.*, COUNT(t.id) AS task_count
+	FROM tags g
+	LEFT JOIN task_tags tt ON tt.tag_id = g.id
+	LEFT JOIN tasks t ON t.id = tt.task_id AND t.deleted_at IS NULL`
+
+func (r *tagRepository) Create(ctx context.Context, tag *domain.Tag) error {
+	query := `
+		INSERT INTO tags (id, user_id, name, color, created_at, updated_at)
+		VALUES (:id, :user_id, :name, :color, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, tag); err != nil {
+		return fmt.Errorf("tagRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Tag, error) {
+	var tag domain.Tag
+	query := tagColumns + ` WHERE g.id = $1 GROUP BY g.id`
+	if err := r.db.GetContext(ctx, &tag, query, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("tagRepository.FindByID: %w", err)
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Tag, error) {
+	tags := []*domain.Tag{}
+	query := tagColumns + ` WHERE g.user_id = $1 GROUP BY g.id ORDER BY LOWER(g.name)`
+	if err := r.db.SelectContext(ctx, &tags, query, userID); err != nil {
+		return nil, fmt.Errorf("tagRepository.ListByUserID: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) CountOwned(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	var n int
+	query := `SELECT COUNT(*) FROM tags WHERE user_id = $1 AND id = ANY($2)`
+	if err := r.db.GetContext(ctx, &n, query, userID, pq.Array(ids)); err != nil {
+		return 0, fmt.Errorf("tagRepository.CountOwned: %w", err)
+	}
+	return n, nil
+}
+
+func (r *tagRepository) Update(ctx context.Context, tag *domain.Tag) error {
+	query := `UPDATE tags SET name = :name, color = :color, updated_at = :updated_at WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, query, tag)
+	if err != nil {
+		return fmt.Errorf("tagRepository.Update: %w", mapDBError(err))
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM tags WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("tagRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *tagRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.Tag, error) {
+	tags := []*domain.Tag{}
+	query := tagColumns + `
+		WHERE g.id IN (SELECT tag_id FROM task_tags WHERE task_id = $1)
+		GROUP BY g.id
+		ORDER BY LOWER(g.name)`
+	if err := r.db.SelectContext(ctx, &tags, query, taskID); err != nil {
+		return nil, fmt.Errorf("tagRepository.ListByTaskID: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *tagRepository) SetForTask(ctx context.Context, taskID uuid.UUID, tagIDs []uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("tagRepository.SetForTask begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_tags WHERE task_id = $1`, taskID); err != nil {
+		return fmt.Errorf("tagRepository.SetForTask clear: %w", err)
+	}
+	if len(tagIDs) > 0 {
+		query := `
+			INSERT INTO task_tags (task_id, tag_id)
+			SELECT $1, UNNEST($2::uuid[])
+			ON CONFLICT DO NOTHING`
+		if _, err := tx.ExecContext(ctx, query, taskID, pq.Array(tagIDs)); err != nil {
+			return fmt.Errorf("tagRepository.SetForTask insert: %w", mapDBError(err))
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE tasks SET updated_at = NOW() WHERE id = $1`, taskID); err != nil {
+		return fmt.Errorf("tagRepository.SetForTask touch: %w", err)
+	}
+	return tx.Commit()
+}
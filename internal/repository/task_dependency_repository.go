@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskDependencyRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskDependencyRepository creates a new PostgreSQL-backed TaskDependencyRepository.
+func NewTaskDependencyRepository(db *sqlx.DB) domain.TaskDependencyRepository {
+	return &taskDependencyRepository{db: db}
+}
+
+func (r *taskDependencyRepository) Add(ctx context.Context, taskID, blockedByID uuid.UUID) error {
+	query := `
+		INSERT INTO task_dependencies (task_id, blocked_by_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, taskID, blockedByID); err != nil {
+		return fmt.Errorf("taskDependencyRepository.Add: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskDependencyRepository) Remove(ctx context.Context, taskID, blockedByID uuid.UUID) error {
+	query := `DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2`
+	res, err := r.db.ExecContext(ctx, query, taskID, blockedByID)
+	if err != nil {
+		return fmt.Errorf("taskDependencyRepository.Remove: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *taskDependencyRepository) ListBlockers(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	query := `
+		SELECT tasks.*, ` + taskBlockedColumn + `
+		FROM tasks
+		JOIN task_dependencies dep ON dep.blocked_by_id = tasks.id
+		WHERE dep.task_id = $1 AND tasks.deleted_at IS NULL
+		ORDER BY tasks.status = 'done', tasks.smart_score DESC`
+	if err := r.db.SelectContext(ctx, &tasks, query, taskID); err != nil {
+		return nil, fmt.Errorf("taskDependencyRepository.ListBlockers: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskDependencyRepository) ListBlocking(ctx context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	query := `
+		SELECT tasks.*, ` + taskBlockedColumn + `
+		FROM tasks
+		JOIN task_dependencies dep ON dep.task_id = tasks.id
+		WHERE dep.blocked_by_id = $1 AND tasks.deleted_at IS NULL
+		ORDER BY tasks.status = 'done', tasks.smart_score DESC`
+	if err := r.db.SelectContext(ctx, &tasks, query, taskID); err != nil {
+		return nil, fmt.Errorf("taskDependencyRepository.ListBlocking: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskDependencyRepository) CountOpenBlockers(ctx context.Context, taskID uuid.UUID) (int, error) {
+	var n int
+	query := `
+		SELECT COUNT(*)
+		FROM task_dependencies dep
+		JOIN tasks b ON b.id = dep.blocked_by_id
+		WHERE dep.task_id = $1 AND b.status != 'done' AND b.deleted_at IS NULL`
+	if err := r.db.GetContext(ctx, &n, query, taskID); err != nil {
+		return 0, fmt.Errorf("taskDependencyRepository.CountOpenBlockers: %w", err)
+	}
+	return n, nil
+}
+
+func (r *taskDependencyRepository) DependsOn(ctx context.Context, taskID, otherID uuid.UUID) (bool, error) {
+	var found bool
+	query := `
+		WITH RECURSIVE chain (id) AS (
+			SELECT blocked_by_id FROM task_dependencies WHERE task_id = $1
+			UNION
+			SELECT dep.blocked_by_id FROM task_dependencies dep JOIN chain ON dep.task_id = chain.id
+		)
+		SELECT EXISTS (SELECT 1 FROM chain WHERE id = $2)`
+	if err := r.db.GetContext(ctx, &found, query, taskID, otherID); err != nil {
+		return false, fmt.Errorf("taskDependencyRepository.DependsOn: %w", err)
+	}
+	return found, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskEscalationRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskEscalationRepository creates a new PostgreSQL-backed TaskEscalationRepository.
+func NewTaskEscalationRepository(db *sqlx.DB) domain.TaskEscalationRepository {
+	return &taskEscalationRepository{db: db}
+}
+
+func (r *taskEscalationRepository) Create(ctx context.Context, e *domain.TaskEscalation) error {
+	query := `
+		INSERT INTO task_escalations (id, task_id, user_id, from_priority, to_priority, reason, due_date, escalated_at)
+		VALUES (:id, :task_id, :user_id, :from_priority, :to_priority, :reason, :due_date, :escalated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("taskEscalationRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskEscalationRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*domain.TaskEscalation, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM task_escalations WHERE task_id = $1`, taskID); err != nil {
+		return nil, 0, fmt.Errorf("taskEscalationRepository.ListByTaskID count: %w", err)
+	}
+
+	escalations := []*domain.TaskEscalation{}
+	query := `
+		SELECT * FROM task_escalations
+		WHERE task_id = $1
+		ORDER BY escalated_at DESC
+		LIMIT $2 OFFSET $3`
+	if err := r.db.SelectContext(ctx, &escalations, query, taskID, limit, (page-1)*limit); err != nil {
+		return nil, 0, fmt.Errorf("taskEscalationRepository.ListByTaskID: %w", err)
+	}
+	return escalations, total, nil
+}
+
+func (r *taskEscalationRepository) ListCandidates(ctx context.Context, mediumBy, highBy *time.Time, limit int) ([]*domain.Task, error) {
+	// The target mirrors EscalationPolicy.Target; a task already escalated
+	// to it for its current due date is left alone.
+	query := `
+		SELECT t.* FROM tasks t
+		WHERE t.deleted_at IS NULL AND t.archived_at IS NULL
+		  AND t.status != 'done' AND NOT t.no_escalation AND t.due_date IS NOT NULL
+		  AND ((t.priority = 'low' AND t.due_date <= $1) OR (t.priority != 'high' AND t.due_date <= $2))
+		  AND NOT EXISTS (
+			SELECT 1 FROM task_escalations e
+			WHERE e.task_id = t.id AND e.due_date = t.due_date
+			  AND e.to_priority = CASE WHEN t.due_date <= $2 THEN 'high'::task_priority ELSE 'medium'::task_priority END
+		  )
+		ORDER BY t.due_date
+		LIMIT $3`
+
+	tasks := []*domain.Task{}
+	if err := r.db.SelectContext(ctx, &tasks, query, mediumBy, highBy, limit); err != nil {
+		return nil, fmt.Errorf("taskEscalationRepository.ListCandidates: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskEscalationRepository) ListAgingCandidates(ctx context.Context, mediumSince, highSince *time.Time, limit int) ([]*domain.Task, error) {
+	// The target mirrors AgingPolicy.Target; a task already raised to it
+	// since it last changed status is left alone.
+	query := `
+		SELECT t.* FROM tasks t
+		WHERE t.deleted_at IS NULL AND t.archived_at IS NULL
+		  AND t.status = 'todo' AND NOT t.no_escalation
+		  AND ((t.priority = 'low' AND t.status_changed_at <= $1) OR (t.priority != 'high' AND t.status_changed_at <= $2))
+		  AND NOT EXISTS (
+			SELECT 1 FROM task_escalations e
+			WHERE e.task_id = t.id AND e.reason = 'aging' AND e.escalated_at >= t.status_changed_at
+			  AND e.to_priority = CASE WHEN t.status_changed_at <= $2 THEN 'high'::task_priority ELSE 'medium'::task_priority END
+		  )
+		ORDER BY t.status_changed_at
+		LIMIT $3`
+
+	tasks := []*domain.Task{}
+	if err := r.db.SelectContext(ctx, &tasks, query, mediumSince, highSince, limit); err != nil {
+		return nil, fmt.Errorf("taskEscalationRepository.ListAgingCandidates: %w", err)
+	}
+	return tasks, nil
+}
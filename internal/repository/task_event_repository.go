@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskEventRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskEventRepository creates a new PostgreSQL-backed TaskEventRepository.
+func NewTaskEventRepository(db *sqlx.DB) domain.TaskEventRepository {
+	return &taskEventRepository{db: db}
+}
+
+func (r *taskEventRepository) Create(ctx context.Context, e *domain.TaskEvent) error {
+	query := `
+		INSERT INTO task_events (id, task_id, user_id, event, snapshot, changes, created_at)
+		VALUES (:id, :task_id, :user_id, :event, :snapshot, :changes, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("taskEventRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskEventRepository) FindLatest(ctx context.Context, taskID uuid.UUID, asOf time.Time) (*domain.TaskEvent, error) {
+	var e domain.TaskEvent
+	query := `
+		SELECT * FROM task_events
+		WHERE task_id = $1 AND created_at <= $2
+		ORDER BY created_at DESC
+		LIMIT 1`
+	if err := r.db.GetContext(ctx, &e, query, taskID, asOf); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("taskEventRepository.FindLatest: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *taskEventRepository) ListActivity(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*domain.TaskEvent, int, error) {
+	const where = `task_id = $1 AND (event != $2 OR changes != '{}'::jsonb)`
+
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM task_events WHERE `+where, taskID, domain.EventTaskUpdated); err != nil {
+		return nil, 0, fmt.Errorf("taskEventRepository.ListActivity count: %w", err)
+	}
+
+	events := []*domain.TaskEvent{}
+	query := `SELECT * FROM task_events WHERE ` + where + ` ORDER BY created_at DESC LIMIT $3 OFFSET $4`
+	if err := r.db.SelectContext(ctx, &events, query, taskID, domain.EventTaskUpdated, limit, (page-1)*limit); err != nil {
+		return nil, 0, fmt.Errorf("taskEventRepository.ListActivity select: %w", err)
+	}
+	return events, total, nil
+}
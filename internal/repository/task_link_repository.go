@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskLinkRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskLinkRepository creates a new PostgreSQL-backed TaskLinkRepository.
+func NewTaskLinkRepository(db *sqlx.DB) domain.TaskLinkRepository {
+	return &taskLinkRepository{db: db}
+}
+
+func (r *taskLinkRepository) Create(ctx context.Context, link *domain.TaskLink) error {
+	query := `
+		INSERT INTO task_links (id, task_id, user_id, url, label, created_at, updated_at)
+		VALUES (:id, :task_id, :user_id, :url, :label, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, link); err != nil {
+		return fmt.Errorf("taskLinkRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskLinkRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.TaskLink, error) {
+	var link domain.TaskLink
+	if err := r.db.GetContext(ctx, &link, `SELECT * FROM task_links WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("taskLinkRepository.FindByID: %w", err)
+	}
+	return &link, nil
+}
+
+func (r *taskLinkRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TaskLink, error) {
+	links := []*domain.TaskLink{}
+	query := `SELECT * FROM task_links WHERE task_id = $1 ORDER BY created_at, id`
+	if err := r.db.SelectContext(ctx, &links, query, taskID); err != nil {
+		return nil, fmt.Errorf("taskLinkRepository.ListByTaskID: %w", err)
+	}
+	return links, nil
+}
+
+func (r *taskLinkRepository) CountByTaskID(ctx context.Context, taskID uuid.UUID) (int, error) {
+	var n int
+	if err := r.db.GetContext(ctx, &n, `SELECT COUNT(*) FROM task_links WHERE task_id = $1`, taskID); err != nil {
+		return 0, fmt.Errorf("taskLinkRepository.CountByTaskID: %w", err)
+	}
+	return n, nil
+}
+
+func (r *taskLinkRepository) Update(ctx context.Context, link *domain.TaskLink) error {
+	query := `UPDATE task_links SET url = :url, label = :label, updated_at = :updated_at WHERE id = :id`
+	res, err := r.db.NamedExecContext(ctx, query, link)
+	if err != nil {
+		return fmt.Errorf("taskLinkRepository.Update: %w", mapDBError(err))
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *taskLinkRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM task_links WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("taskLinkRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
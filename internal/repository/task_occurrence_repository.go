@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskOccurrenceRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskOccurrenceRepository creates a new PostgreSQL-backed TaskOccurrenceRepository.
+func NewTaskOccurrenceRepository(db *sqlx.DB) domain.TaskOccurrenceRepository {
+	return &taskOccurrenceRepository{db: db}
+}
+
+func (r *taskOccurrenceRepository) Create(ctx context.Context, o *domain.TaskOccurrence) error {
+	query := `
+		INSERT INTO task_occurrences (id, task_id, user_id, due_date, completed_at, on_time)
+		VALUES (:id, :task_id, :user_id, :due_date, :completed_at, :on_time)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, o); err != nil {
+		return fmt.Errorf("taskOccurrenceRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskOccurrenceRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID, page, limit int) ([]*domain.TaskOccurrence, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total, `SELECT COUNT(*) FROM task_occurrences WHERE task_id = $1`, taskID); err != nil {
+		return nil, 0, fmt.Errorf("taskOccurrenceRepository.ListByTaskID count: %w", err)
+	}
+
+	occurrences := []*domain.TaskOccurrence{}
+	query := `
+		SELECT * FROM task_occurrences
+		WHERE task_id = $1
+		ORDER BY completed_at DESC
+		LIMIT $2 OFFSET $3`
+	if err := r.db.SelectContext(ctx, &occurrences, query, taskID, limit, (page-1)*limit); err != nil {
+		return nil, 0, fmt.Errorf("taskOccurrenceRepository.ListByTaskID: %w", err)
+	}
+	return occurrences, total, nil
+}
+
+func (r *taskOccurrenceRepository) StatsByTaskID(ctx context.Context, taskID uuid.UUID) (*domain.TaskOccurrenceStats, error) {
+	var stats domain.TaskOccurrenceStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE on_time)
+		FROM task_occurrences
+		WHERE task_id = $1`, taskID,
+	).Scan(&stats.Completed, &stats.OnTime)
+	if err != nil {
+		return nil, fmt.Errorf("taskOccurrenceRepository.StatsByTaskID: %w", err)
+	}
+	stats.Late = stats.Completed - stats.OnTime
+	if stats.Completed > 0 {
+		stats.AdherenceRate = float64(stats.OnTime) / float64(stats.Completed) * 100
+	}
+	return &stats, nil
+}
@@ -5,11 +5,15 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type taskRepository struct {
@@ -21,27 +25,113 @@ func NewTaskRepository(db *sqlx.DB) domain.TaskRepository {
 	return &taskRepository{db: db}
 }
 
-func (r *taskRepository) Create(ctx context.Context, task *domain.Task) error {
-	query := `
+// taskInsertQuery inserts one task from its named fields.
+const taskInsertQuery = `
 		INSERT INTO tasks (
-			id, user_id, project_id, title, description,
-			status, priority, estimated_hours, due_date,
-			completed_at, smart_score, created_at, updated_at
+			id, user_id, project_id, parent_id, title, description,
+			status, priority, estimated_hours, energy, context, due_date, due_expr, start_date,
+			scheduled_at, scheduled_duration, recurrence, no_escalation,
+			completed_at, smart_score, sort_order, created_at, updated_at
 		) VALUES (
-			:id, :user_id, :project_id, :title, :description,
-			:status, :priority, :estimated_hours, :due_date,
-			:completed_at, :smart_score, :created_at, :updated_at
+			:id, :user_id, :project_id, :parent_id, :title, :description,
+			:status, :priority, :estimated_hours, :energy, :context, :due_date, :due_expr, :start_date,
+			:scheduled_at, :scheduled_duration, :recurrence, :no_escalation,
+			:completed_at, :smart_score, :sort_order, :created_at, :updated_at
 		)`
 
-	if _, err := r.db.NamedExecContext(ctx, query, task); err != nil {
+func (r *taskRepository) Create(ctx context.Context, task *domain.Task) error {
+	if _, err := r.db.NamedExecContext(ctx, taskInsertQuery, task); err != nil {
 		return fmt.Errorf("taskRepository.Create: %w", mapDBError(err))
 	}
+	task.Version = 1 // the column default
 	return nil
 }
 
+// taskInsertChunk is how many tasks CreateBatch sends per INSERT, keeping
+// each statement well under PostgreSQL's 65535 bind parameters.
+const taskInsertChunk = 500
+
+func (r *taskRepository) CreateBatch(ctx context.Context, tasks []*domain.Task) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("taskRepository.CreateBatch begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for start := 0; start < len(tasks); start += taskInsertChunk {
+		end := min(start+taskInsertChunk, len(tasks))
+		// sqlx expands the VALUES row once per task in the slice.
+		if _, err := tx.NamedExecContext(ctx, taskInsertQuery, tasks[start:end]); err != nil {
+			return fmt.Errorf("taskRepository.CreateBatch: %w", &domain.TaskBatchError{Start: start, End: end, Err: mapDBError(err)})
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("taskRepository.CreateBatch commit: %w", err)
+	}
+	for _, task := range tasks {
+		task.Version = 1
+	}
+	return nil
+}
+
+// taskBlockedColumn computes Task.Blocked for a query selecting from tasks.
+const taskBlockedColumn = `EXISTS (
+	SELECT 1 FROM task_dependencies dep
+	JOIN tasks b ON b.id = dep.blocked_by_id
+	WHERE dep.task_id = tasks.id AND b.status != 'done' AND b.deleted_at IS NULL
+) AS blocked`
+
+// taskProgressJoin counts each listed task's live subtasks, and how many of
+// them are done, and rolls up their estimates, in the same pass as the list:
+// the lateral subquery runs once per row through idx_tasks_parent_id.
+// Progress is weighted by estimate when any subtask has one.
+const taskProgressJoin = `LEFT JOIN LATERAL (
+	SELECT COUNT(*) AS subtasks_total, COUNT(*) FILTER (WHERE sub.status = 'done') AS subtasks_done,
+	       SUM(sub.estimated_hours)::float8 AS rolled_up_estimate,
+	       CASE WHEN SUM(sub.estimated_hours) > 0
+	                THEN (100 * COALESCE(SUM(sub.estimated_hours) FILTER (WHERE sub.status = 'done'), 0) / SUM(sub.estimated_hours))::float8
+	            WHEN COUNT(*) > 0
+	                THEN 100.0 * COUNT(*) FILTER (WHERE sub.status = 'done') / COUNT(*)
+	       END AS subtask_progress
+	FROM tasks sub
+	WHERE sub.parent_id = tasks.id AND sub.deleted_at IS NULL AND sub.archived_at IS NULL
+) progress ON true`
+
+// taskProgressFields are the fields taskProgressJoin provides.
+var taskProgressFields = []string{"subtasks_total", "subtasks_done", "rolled_up_estimate", "subtask_progress"}
+
+// taskSelectList returns the select list loading fields from tasks, every
+// column when fields is nil. It reads from taskListFrom.
+func taskSelectList(fields domain.Fields) string {
+	if fields == nil {
+		return "tasks.*, " + taskBlockedColumn + ", progress.*"
+	}
+	cols := make([]string, len(fields))
+	for i, f := range fields {
+		switch f {
+		case "blocked":
+			cols[i] = taskBlockedColumn
+		case "subtasks_total", "subtasks_done", "rolled_up_estimate", "subtask_progress":
+			cols[i] = "progress." + f
+		default:
+			cols[i] = "tasks." + pq.QuoteIdentifier(f)
+		}
+	}
+	return strings.Join(cols, ", ")
+}
+
+// taskListFrom returns the FROM clause for taskSelectList, joining in the
+// subtask rollup only when fields asks for it.
+func taskListFrom(fields domain.Fields) string {
+	if slices.ContainsFunc(taskProgressFields, fields.Has) {
+		return "tasks " + taskProgressJoin
+	}
+	return "tasks"
+}
+
 func (r *taskRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
 	var task domain.Task
-	query := `SELECT * FROM tasks WHERE id = $1 AND deleted_at IS NULL`
+	query := `SELECT tasks.*, ` + taskBlockedColumn + ` FROM tasks WHERE id = $1 AND deleted_at IS NULL`
 	if err := r.db.GetContext(ctx, &task, query, id); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
@@ -57,27 +147,182 @@ func (r *taskRepository) List(
 	filter domain.TaskFilter,
 	page, limit int,
 ) ([]*domain.Task, int, error) {
+	where, args := taskListWhere(userID, filter)
+
+	// Count total
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks WHERE %s", where)
+	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("taskRepository.List count: %w", err)
+	}
+
+	// Fetch page
+	offset := (page - 1) * limit
+	listQuery := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s ORDER BY %s LIMIT $%d OFFSET $%d",
+		taskSelectList(filter.Fields), taskListFrom(filter.Fields), where, taskListOrder(filter.Sort), len(args)+1, len(args)+2,
+	)
+	args = append(args, limit, offset)
+
+	var tasks []*domain.Task
+	if err := r.db.SelectContext(ctx, &tasks, listQuery, args...); err != nil {
+		return nil, 0, fmt.Errorf("taskRepository.List select: %w", err)
+	}
+
+	return tasks, total, nil
+}
+
+func (r *taskRepository) Stream(ctx context.Context, userID uuid.UUID, filter domain.TaskFilter, fn func(*domain.Task) error) error {
+	where, args := taskListWhere(userID, filter)
+	query := fmt.Sprintf(
+		"SELECT %s FROM %s WHERE %s ORDER BY %s",
+		taskSelectList(filter.Fields), taskListFrom(filter.Fields), where, taskListOrder(filter.Sort),
+	)
+	// Rows are scanned as they arrive rather than collected, so memory does
+	// not grow with the number of tasks.
+	rows, err := r.db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("taskRepository.Stream: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var task domain.Task
+		if err := rows.StructScan(&task); err != nil {
+			return fmt.Errorf("taskRepository.Stream: %w", err)
+		}
+		if err := fn(&task); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("taskRepository.Stream: %w", err)
+	}
+	return nil
+}
+
+// taskSharedWhere matches the tasks $1 may see in a project: their own, and
+// any task in a project they own or are a member of.
+const taskSharedWhere = `(user_id = $1
+	OR project_id IN (SELECT id FROM projects WHERE user_id = $1)
+	OR project_id IN (SELECT project_id FROM project_members WHERE user_id = $1))`
+
+// taskLiveProjectWhere leaves out tasks in archived projects. Default lists
+// and analytics apply it; asking for a project by id still shows its tasks.
+const taskLiveProjectWhere = `(project_id IS NULL OR project_id NOT IN (SELECT id FROM projects WHERE archived_at IS NOT NULL))`
+
+// taskListWhere builds the WHERE clause of List and Stream for filter, with
+// its arguments; userID is always $1. Listing one project shows every task in
+// it the user may see, including those other members created.
+func taskListWhere(userID uuid.UUID, filter domain.TaskFilter) (string, []any) {
 	args := []any{userID}
 	conditions := []string{"user_id = $1", "deleted_at IS NULL"}
+	if len(filter.ProjectIDs) > 0 {
+		conditions[0] = taskSharedWhere
+	} else {
+		conditions = append(conditions, taskLiveProjectWhere)
+	}
 	argIdx := 2
 
-	if filter.Status != nil {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIdx))
-		args = append(args, *filter.Status)
+	// Value lists bind as one array parameter each, so the SQL text does not
+	// depend on how many values were given.
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status = ANY($%d::task_status[])", argIdx))
+		args = append(args, enumArray(filter.Statuses))
+		argIdx++
+	}
+	if len(filter.ExcludeStatuses) > 0 {
+		conditions = append(conditions, fmt.Sprintf("status != ALL($%d::task_status[])", argIdx))
+		args = append(args, enumArray(filter.ExcludeStatuses))
+		argIdx++
+	}
+	if len(filter.Priorities) > 0 {
+		conditions = append(conditions, fmt.Sprintf("priority = ANY($%d::task_priority[])", argIdx))
+		args = append(args, enumArray(filter.Priorities))
+		argIdx++
+	}
+	if len(filter.ExcludePriorities) > 0 {
+		conditions = append(conditions, fmt.Sprintf("priority != ALL($%d::task_priority[])", argIdx))
+		args = append(args, enumArray(filter.ExcludePriorities))
+		argIdx++
+	}
+	if len(filter.ProjectIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("project_id = ANY($%d)", argIdx))
+		args = append(args, pq.Array(filter.ProjectIDs))
+		argIdx++
+	}
+	if len(filter.ExcludeProjectIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf("(project_id IS NULL OR project_id != ALL($%d))", argIdx))
+		args = append(args, pq.Array(filter.ExcludeProjectIDs))
+		argIdx++
+	}
+	if filter.Energy != nil {
+		conditions = append(conditions, fmt.Sprintf("energy = $%d", argIdx))
+		args = append(args, *filter.Energy)
 		argIdx++
 	}
-	if filter.Priority != nil {
-		conditions = append(conditions, fmt.Sprintf("priority = $%d", argIdx))
-		args = append(args, *filter.Priority)
+	if filter.Context != "" {
+		conditions = append(conditions, fmt.Sprintf("context = $%d", argIdx))
+		args = append(args, filter.Context)
 		argIdx++
 	}
-	if filter.ProjectID != nil {
-		conditions = append(conditions, fmt.Sprintf("project_id = $%d", argIdx))
-		args = append(args, *filter.ProjectID)
+	if filter.ParentID != nil {
+		conditions = append(conditions, fmt.Sprintf("parent_id = $%d", argIdx))
+		args = append(args, *filter.ParentID)
 		argIdx++
 	}
+	if len(filter.TagIDs) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"id IN (SELECT task_id FROM task_tags WHERE tag_id = ANY($%d) GROUP BY task_id HAVING COUNT(*) = $%d)",
+			argIdx, argIdx+1,
+		))
+		args = append(args, pq.Array(filter.TagIDs), len(filter.TagIDs))
+		argIdx += 2
+	}
 	if filter.Overdue != nil && *filter.Overdue {
-		conditions = append(conditions, "due_date < NOW() AND status != 'done'")
+		conditions = append(conditions, taskDeadlineSQL("due_date", userTimezoneSQL("$1"))+" < NOW() AND status != 'done'")
+	}
+	if filter.DueAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date >= $%d", argIdx))
+		args = append(args, *filter.DueAfter)
+		argIdx++
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("due_date <= $%d", argIdx))
+		args = append(args, *filter.DueBefore)
+		argIdx++
+	}
+	if filter.CreatedAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", argIdx))
+		args = append(args, *filter.CreatedAfter)
+		argIdx++
+	}
+	if filter.CreatedBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", argIdx))
+		args = append(args, *filter.CreatedBefore)
+		argIdx++
+	}
+	if filter.StartAfter != nil {
+		conditions = append(conditions, fmt.Sprintf("start_date >= $%d", argIdx))
+		args = append(args, *filter.StartAfter)
+		argIdx++
+	}
+	if filter.StartBefore != nil {
+		conditions = append(conditions, fmt.Sprintf("start_date <= $%d", argIdx))
+		args = append(args, *filter.StartBefore)
+		argIdx++
+	}
+	if !filter.IncludeDeferred && filter.StartAfter == nil && filter.StartBefore == nil {
+		conditions = append(conditions, "(start_date IS NULL OR start_date <= NOW())")
+	}
+	if filter.Archived != nil && *filter.Archived {
+		conditions = append(conditions, "archived_at IS NOT NULL")
+	} else {
+		conditions = append(conditions, "archived_at IS NULL")
+	}
+	if filter.Pinned != nil {
+		conditions = append(conditions, fmt.Sprintf("pinned = $%d", argIdx))
+		args = append(args, *filter.Pinned)
+		argIdx++
 	}
 	if filter.Search != "" {
 		conditions = append(conditions, fmt.Sprintf(
@@ -88,32 +333,32 @@ func (r *taskRepository) List(
 		argIdx += 2
 	}
 
-	where := strings.Join(conditions, " AND ")
+	return strings.Join(conditions, " AND "), args
+}
 
-	// Count total
-	var total int
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM tasks WHERE %s", where)
-	if err := r.db.GetContext(ctx, &total, countQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("taskRepository.List count: %w", err)
+// enumArray binds enum values as a text array, to be cast to the enum type.
+func enumArray[T ~string](values []T) pq.StringArray {
+	out := make(pq.StringArray, len(values))
+	for i, v := range values {
+		out[i] = string(v)
 	}
+	return out
+}
 
-	// Fetch page
-	offset := (page - 1) * limit
-	listQuery := fmt.Sprintf(
-		"SELECT * FROM tasks WHERE %s ORDER BY smart_score DESC, created_at DESC LIMIT $%d OFFSET $%d",
-		where, argIdx, argIdx+1,
-	)
-	args = append(args, limit, offset)
-
-	var tasks []*domain.Task
-	if err := r.db.SelectContext(ctx, &tasks, listQuery, args...); err != nil {
-		return nil, 0, fmt.Errorf("taskRepository.List select: %w", err)
+// taskListOrder returns the ORDER BY of List and Stream for a sort; pinned
+// tasks lead in either ranked or manual order.
+func taskListOrder(sort string) string {
+	switch sort {
+	case domain.TaskSortManual:
+		return "pinned DESC, sort_order ASC, created_at ASC"
+	case domain.TaskSortCreated:
+		return "created_at ASC, id ASC"
+	default:
+		return "pinned DESC, smart_score DESC, created_at DESC"
 	}
-
-	return tasks, total, nil
 }
 
-func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
+func (r *taskRepository) Update(ctx context.Context, task *domain.Task, ifVersion *int64) error {
 	query := `
 		UPDATE tasks SET
 			project_id     = :project_id,
@@ -122,26 +367,66 @@ func (r *taskRepository) Update(ctx context.Context, task *domain.Task) error {
 			status         = :status,
 			priority       = :priority,
 			estimated_hours = :estimated_hours,
+			actual_hours   = :actual_hours,
+			energy         = :energy,
+			context        = :context,
 			due_date       = :due_date,
+			due_expr       = :due_expr,
+			start_date     = :start_date,
+			scheduled_at   = :scheduled_at,
+			scheduled_duration = :scheduled_duration,
+			recurrence     = :recurrence,
+			no_escalation  = :no_escalation,
 			completed_at   = :completed_at,
+			status_changed_at = :status_changed_at,
 			smart_score    = :smart_score,
+			age_points     = :age_points,
+			effort_exceeded = FALSE,
 			updated_at     = :updated_at
 		WHERE id = :id AND deleted_at IS NULL`
 
-	res, err := r.db.NamedExecContext(ctx, query, task)
+	query, args, err := sqlx.Named(query, task)
 	if err != nil {
+		return fmt.Errorf("taskRepository.Update: %w", err)
+	}
+	// Comparing the version in the same statement keeps two writers holding
+	// the same version from both getting through.
+	if ifVersion != nil {
+		query += ` AND version = ?`
+		args = append(args, *ifVersion)
+	}
+	query += ` RETURNING version`
+	// The trigger bumps version; read it back so the caller's copy stays
+	// current.
+	if err := r.db.GetContext(ctx, &task.Version, r.db.Rebind(query), args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if ifVersion != nil {
+				return staleOrMissing(ctx, r.db, "tasks", task.ID)
+			}
+			return domain.ErrNotFound
+		}
 		return fmt.Errorf("taskRepository.Update: %w", mapDBError(err))
 	}
-	return checkRowsAffected(res)
+	// Any update restarts the clock the effort nudge measures.
+	task.EffortExceeded = false
+	return nil
 }
 
-func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	query := `UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
-	res, err := r.db.ExecContext(ctx, query, id)
+func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	query := `
+		UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint IS NULL OR version = $2)`
+	res, err := r.db.ExecContext(ctx, query, id, ifVersion)
 	if err != nil {
 		return fmt.Errorf("taskRepository.Delete: %w", err)
 	}
-	return checkRowsAffected(res)
+	if err := checkRowsAffected(res); err != nil {
+		if errors.Is(err, domain.ErrNotFound) && ifVersion != nil {
+			return staleOrMissing(ctx, r.db, "tasks", id)
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *taskRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
@@ -155,16 +440,318 @@ func (r *taskRepository) CountByUserID(ctx context.Context, userID uuid.UUID) (i
 	return count, nil
 }
 
-func (r *taskRepository) FindOverdue(ctx context.Context, userID uuid.UUID) ([]*domain.Task, error) {
+func (r *taskRepository) FindOverdue(ctx context.Context, userID uuid.UUID, after *domain.OverdueCursor, limit int) ([]*domain.Task, error) {
 	var tasks []*domain.Task
+	// A deadline never falls before its due date, so due_date < NOW() lets
+	// idx_tasks_overdue bound the scan before the exact check.
 	query := `
 		SELECT * FROM tasks
-		WHERE user_id = $1 AND deleted_at IS NULL
-		  AND status != 'done' AND due_date < NOW()
-		ORDER BY due_date ASC`
+		WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+		  AND status != 'done' AND due_date IS NOT NULL AND due_date < NOW()
+		  AND ` + taskDeadlineSQL("due_date", userTimezoneSQL("$1")) + ` < NOW()`
+	args := []any{userID, limit}
+	if after != nil {
+		query += ` AND (due_date, id) > ($3, $4)`
+		args = append(args, after.DueDate, after.ID)
+	}
+	query += ` ORDER BY due_date ASC, id ASC LIMIT $2`
 
-	if err := r.db.SelectContext(ctx, &tasks, query, userID); err != nil {
+	if err := r.db.SelectContext(ctx, &tasks, query, args...); err != nil {
 		return nil, fmt.Errorf("taskRepository.FindOverdue: %w", err)
 	}
 	return tasks, nil
 }
+
+func (r *taskRepository) FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	query := `SELECT * FROM tasks WHERE user_id = $1 AND id = ANY($2) AND deleted_at IS NULL ORDER BY created_at`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, pq.Array(ids)); err != nil {
+		return nil, fmt.Errorf("taskRepository.FindByIDs: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) SetProject(ctx context.Context, ids []uuid.UUID, projectID *uuid.UUID) error {
+	query := `UPDATE tasks SET project_id = $2, updated_at = NOW() WHERE id = ANY($1) AND deleted_at IS NULL`
+	if _, err := r.db.ExecContext(ctx, query, pq.Array(ids), projectID); err != nil {
+		return fmt.Errorf("taskRepository.SetProject: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *taskRepository) ListModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	query := `
+		SELECT * FROM tasks
+		WHERE user_id = $1 AND updated_at > $2
+		ORDER BY updated_at, id
+		LIMIT $3`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, since, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.ListModifiedSince: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) FuzzyFind(ctx context.Context, userID uuid.UUID, q string, threshold float64, limit int) ([]*domain.TaskMatch, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("taskRepository.FuzzyFind begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// The % operator uses idx_tasks_title_trgm but reads its cutoff from this
+	// setting; SET LOCAL scope keeps it to this query.
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('pg_trgm.similarity_threshold', $1, true)`,
+		strconv.FormatFloat(threshold, 'f', -1, 64)); err != nil {
+		return nil, fmt.Errorf("taskRepository.FuzzyFind threshold: %w", err)
+	}
+
+	matches := []*domain.TaskMatch{}
+	query := `
+		SELECT *, similarity(title, $2) AS similarity
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND title % $2
+		ORDER BY similarity DESC, updated_at DESC
+		LIMIT $3`
+	if err := tx.SelectContext(ctx, &matches, query, userID, q, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.FuzzyFind: %w", err)
+	}
+	return matches, nil
+}
+
+func (r *taskRepository) ListRecentlyModified(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.Task, error) {
+	tasks := []*domain.Task{}
+	query := `
+		SELECT * FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+		ORDER BY updated_at DESC
+		LIMIT $2`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.ListRecentlyModified: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	query := `UPDATE tasks SET archived_at = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, archivedAt)
+	if err != nil {
+		return fmt.Errorf("taskRepository.SetArchived: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *taskRepository) SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+	query := `UPDATE tasks SET pinned = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, pinned)
+	if err != nil {
+		return fmt.Errorf("taskRepository.SetPinned: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *taskRepository) NextSortOrder(ctx context.Context, userID, excludeID uuid.UUID, after *float64) (*float64, error) {
+	var next sql.NullFloat64
+	query := `
+		SELECT MIN(sort_order) FROM tasks
+		WHERE user_id = $1 AND id != $2 AND deleted_at IS NULL
+		  AND ($3::double precision IS NULL OR sort_order > $3)`
+	if err := r.db.GetContext(ctx, &next, query, userID, excludeID, after); err != nil {
+		return nil, fmt.Errorf("taskRepository.NextSortOrder: %w", err)
+	}
+	if !next.Valid {
+		return nil, nil
+	}
+	return &next.Float64, nil
+}
+
+func (r *taskRepository) SetSortOrder(ctx context.Context, id uuid.UUID, sortOrder float64) error {
+	query := `UPDATE tasks SET sort_order = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query, id, sortOrder)
+	if err != nil {
+		return fmt.Errorf("taskRepository.SetSortOrder: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *taskRepository) RenumberSortOrder(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE tasks t SET sort_order = o.position * $2, updated_at = NOW()
+		FROM (
+			SELECT id, ROW_NUMBER() OVER (ORDER BY sort_order, created_at) AS position
+			FROM tasks WHERE user_id = $1 AND deleted_at IS NULL
+		) o
+		WHERE t.id = o.id`
+	if _, err := r.db.ExecContext(ctx, query, userID, domain.TaskSortGap); err != nil {
+		return fmt.Errorf("taskRepository.RenumberSortOrder: %w", err)
+	}
+	return nil
+}
+
+// smartScoreExpr computes domain.Task.CalculateSmartScore in SQL; keep the
+// two in step.
+var smartScoreExpr = smartScoreSQL("age_points")
+
+// smartScoreSQL is smartScoreExpr taking the aging points from agePoints.
+func smartScoreSQL(agePoints string) string {
+	return `
+	CASE WHEN start_date > NOW() THEN 0 ELSE
+	CASE priority WHEN 'high' THEN 30 WHEN 'medium' THEN 20 ELSE 10 END
+	+ CASE
+		WHEN due_date IS NULL THEN 0
+		WHEN due_date < NOW() THEN 50 + EXTRACT(EPOCH FROM NOW() - due_date) / 3600 / 24 * 5
+		WHEN due_date <= NOW() + INTERVAL '24 hours' THEN 50
+		WHEN due_date <= NOW() + INTERVAL '72 hours' THEN 40
+		WHEN due_date <= NOW() + INTERVAL '168 hours' THEN 25
+		WHEN due_date <= NOW() + INTERVAL '720 hours' THEN 10
+		ELSE 0
+	END
+	+ CASE WHEN status = 'in_progress' THEN 15 ELSE 0 END
+	+ CASE WHEN estimated_hours <= 1 THEN 5 ELSE 0 END
+	+ CASE WHEN status = 'todo' THEN ` + agePoints + ` ELSE 0 END
+	END`
+}
+
+func (r *taskRepository) RecalculateScores(ctx context.Context, userID uuid.UUID) (int, error) {
+	// updated_at stays as it is: FlagEffortExceeded measures work from it,
+	// and modified_since polls would take every rescore for an edit.
+	query := `
+		UPDATE tasks t SET smart_score = s.score
+		FROM (
+			SELECT id, ROUND((` + smartScoreExpr + `)::numeric, 2) AS score
+			FROM tasks WHERE user_id = $1 AND deleted_at IS NULL AND status = $2
+		) s
+		WHERE t.id = s.id AND t.smart_score != s.score`
+	res, err := r.db.ExecContext(ctx, query, userID, domain.TaskStatusTodo)
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.RecalculateScores: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.RecalculateScores: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *taskRepository) ApplyAging(ctx context.Context, after time.Duration, perDay float64, maxPoints *float64) (int, error) {
+	// SET sees the stored age_points, so the score takes the new ones from
+	// the subquery. Aging is upkeep, not an edit, so updated_at stays.
+	query := `
+		UPDATE tasks SET age_points = a.points,
+			smart_score = ROUND((` + smartScoreSQL("a.points") + `)::numeric, 2)
+		FROM (
+			SELECT id, ROUND(LEAST($3, GREATEST(0, EXTRACT(EPOCH FROM NOW() - status_changed_at) - $1) / 86400 * $2)::numeric, 2) AS points
+			FROM tasks
+			WHERE status = 'todo' AND deleted_at IS NULL AND archived_at IS NULL
+		) a
+		WHERE tasks.id = a.id AND tasks.age_points != a.points`
+	res, err := r.db.ExecContext(ctx, query, after.Seconds(), perDay, maxPoints)
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.ApplyAging: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.ApplyAging: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *taskRepository) ScoreStarted(ctx context.Context) (int, error) {
+	// Every active task scores at least 10 for its priority, so zero marks
+	// one that was deferred when last scored. Like RecalculateScores, this
+	// leaves updated_at alone.
+	query := `
+		UPDATE tasks SET smart_score = ROUND((` + smartScoreExpr + `)::numeric, 2)
+		WHERE start_date <= NOW() AND smart_score = 0 AND status != 'done' AND deleted_at IS NULL`
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.ScoreStarted: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("taskRepository.ScoreStarted: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *taskRepository) FlagEffortExceeded(ctx context.Context, limit int) ([]*domain.Task, error) {
+	// A running timer counts as work in progress, as does time logged within
+	// the estimate.
+	query := `
+		UPDATE tasks SET effort_exceeded = TRUE
+		WHERE id IN (
+			SELECT id FROM tasks
+			WHERE status = 'in_progress' AND NOT effort_exceeded AND estimated_hours > 0
+			  AND deleted_at IS NULL AND archived_at IS NULL
+			  AND updated_at < NOW() - estimated_hours * INTERVAL '1 hour'
+			  AND NOT EXISTS (
+				SELECT 1 FROM time_entries te
+				WHERE te.task_id = tasks.id
+				  AND (te.stopped_at IS NULL OR te.stopped_at > NOW() - tasks.estimated_hours * INTERVAL '1 hour')
+			  )
+			ORDER BY updated_at
+			LIMIT $1
+		)
+		RETURNING *`
+	var tasks []*domain.Task
+	if err := r.db.SelectContext(ctx, &tasks, query, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.FlagEffortExceeded: %w", err)
+	}
+	return tasks, nil
+}
+
+// taskAgendaWhere selects the open, started tasks an agenda draws from.
+const taskAgendaWhere = `user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status != 'done'
+	AND (start_date IS NULL OR start_date <= NOW()) AND ` + taskLiveProjectWhere
+
+func (r *taskRepository) ListAgenda(ctx context.Context, userID uuid.UUID, dueBefore time.Time, topScored, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	query := `
+		SELECT tasks.*, ` + taskBlockedColumn + `
+		FROM tasks
+		WHERE ` + taskAgendaWhere + `
+		  AND (due_date < $2 OR id IN (
+			SELECT id FROM tasks WHERE ` + taskAgendaWhere + ` ORDER BY smart_score DESC LIMIT $3
+		  ))
+		ORDER BY due_date NULLS LAST, smart_score DESC
+		LIMIT $4`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, dueBefore, topScored, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.ListAgenda: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListSuitable(ctx context.Context, userID uuid.UUID, energy *domain.TaskEnergy, taskContext string, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	query := `
+		SELECT * FROM (
+			SELECT tasks.*, ` + taskBlockedColumn + `
+			FROM tasks
+			WHERE ` + taskAgendaWhere + `
+			  AND ($2::task_energy IS NULL OR energy IS NULL OR energy <= $2::task_energy)
+			  AND ($3 = '' OR context IS NULL OR context = $3)
+		) t
+		WHERE NOT blocked
+		ORDER BY ($3 != '' AND context = $3) DESC, ($2::task_energy IS NOT NULL AND energy = $2::task_energy) DESC,
+		         smart_score DESC, id
+		LIMIT $4`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, energy, taskContext, limit); err != nil {
+		return nil, fmt.Errorf("taskRepository.ListSuitable: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *taskRepository) ListScheduled(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	query := `
+		SELECT * FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL AND status != 'done'
+		  AND scheduled_at < $3
+		  AND scheduled_at + COALESCE(scheduled_duration, $5) * INTERVAL '1 minute' > $2
+		ORDER BY scheduled_at
+		LIMIT $4`
+	if err := r.db.SelectContext(ctx, &tasks, query, userID, from, to, limit, domain.DefaultScheduledDuration); err != nil {
+		return nil, fmt.Errorf("taskRepository.ListScheduled: %w", err)
+	}
+	return tasks, nil
+}
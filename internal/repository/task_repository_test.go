@@ -0,0 +1,132 @@
+package repository_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// versionedRow answers like a single live row at *version that the
+// version trigger bumps on every matching UPDATE.
+func versionedRow(version *int64) func(string, []any) ([]string, [][]any, int64) {
+	return func(query string, args []any) ([]string, [][]any, int64) {
+		switch {
+		case strings.Contains(query, "SELECT EXISTS"):
+			return []string{"exists"}, [][]any{{true}}, 0
+		case strings.Contains(query, "RETURNING version"):
+			if strings.Contains(query, "AND version = ") && args[len(args)-1] != *version {
+				return []string{"version"}, nil, 0
+			}
+			*version++
+			return []string{"version"}, [][]any{{*version}}, 0
+		case strings.Contains(query, "SET deleted_at"):
+			if args[1] != nil && args[1] != *version {
+				return nil, nil, 0
+			}
+			return nil, nil, 1
+		}
+		return nil, nil, 0
+	}
+}
+
+func TestTaskRepository_Update_RejectsStaleConcurrentWrite(t *testing.T) {
+	version := int64(3)
+	db, fake := newFakeDB(t, versionedRow(&version))
+	repo := repository.NewTaskRepository(db)
+	ctx := context.Background()
+
+	// Two devices read the task at version 3 and both send If-Match: 3.
+	read := int64(3)
+	first := &domain.Task{ID: uuid.New(), Title: "From the phone"}
+	second := &domain.Task{ID: first.ID, Title: "From the laptop"}
+
+	require.NoError(t, repo.Update(ctx, first, &read))
+	assert.Equal(t, int64(4), first.Version)
+	assert.ErrorIs(t, repo.Update(ctx, second, &read), domain.ErrPreconditionFailed)
+	assert.Equal(t, int64(4), version, "the second write must not land")
+
+	assert.Contains(t, fake.calls[0].Query, "AND version = $")
+}
+
+func TestTaskRepository_Update_WithoutIfVersionIsUnconditional(t *testing.T) {
+	version := int64(3)
+	db, fake := newFakeDB(t, versionedRow(&version))
+	repo := repository.NewTaskRepository(db)
+
+	require.NoError(t, repo.Update(context.Background(), &domain.Task{ID: uuid.New()}, nil))
+	assert.NotContains(t, fake.calls[0].Query, "AND version = ")
+}
+
+func TestTaskRepository_Delete_RejectsStaleVersion(t *testing.T) {
+	version := int64(5)
+	db, _ := newFakeDB(t, versionedRow(&version))
+	repo := repository.NewTaskRepository(db)
+	ctx := context.Background()
+
+	stale, current := int64(4), int64(5)
+	assert.ErrorIs(t, repo.Delete(ctx, uuid.New(), &stale), domain.ErrPreconditionFailed)
+	assert.NoError(t, repo.Delete(ctx, uuid.New(), &current))
+	assert.NoError(t, repo.Delete(ctx, uuid.New(), nil))
+}
+
+func TestTaskRepository_Update_MissingTaskIsNotFound(t *testing.T) {
+	db, _ := newFakeDB(t, func(query string, _ []any) ([]string, [][]any, int64) {
+		if strings.Contains(query, "SELECT EXISTS") {
+			return []string{"exists"}, [][]any{{false}}, 0
+		}
+		return []string{"version"}, nil, 0
+	})
+	repo := repository.NewTaskRepository(db)
+
+	v := int64(1)
+	assert.ErrorIs(t, repo.Update(context.Background(), &domain.Task{ID: uuid.New()}, &v), domain.ErrNotFound)
+}
+
+func TestTaskRepository_RecalculateScores_RescoresOnlyTodoTasks(t *testing.T) {
+	db, fake := newFakeDB(t, func(string, []any) ([]string, [][]any, int64) { return nil, nil, 2 })
+	repo := repository.NewTaskRepository(db)
+	userID := uuid.New()
+
+	n, err := repo.RecalculateScores(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	require.Len(t, fake.calls, 1)
+	call := fake.calls[0]
+	assert.Equal(t, []any{userID.String(), string(domain.TaskStatusTodo)}, call.Args,
+		"in-progress and done tasks keep their scores")
+	assert.Contains(t, call.Query, "status = $2")
+	assert.NotContains(t, call.Query, "updated_at", "a rescore must not look like an edit")
+}
+
+func TestTaskRepository_ApplyAging_LeavesUpdatedAtAlone(t *testing.T) {
+	db, fake := newFakeDB(t, func(string, []any) ([]string, [][]any, int64) { return nil, nil, 3 })
+	repo := repository.NewTaskRepository(db)
+
+	n, err := repo.ApplyAging(context.Background(), 72*time.Hour, 1.5, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	require.Len(t, fake.calls, 1)
+	assert.NotContains(t, fake.calls[0].Query, "updated_at", "aging must not show up as an edit to sync clients")
+	assert.Contains(t, fake.calls[0].Query, "status = 'todo'")
+}
+
+func TestTaskRepository_ScoreStarted_LeavesUpdatedAtAlone(t *testing.T) {
+	db, fake := newFakeDB(t, func(string, []any) ([]string, [][]any, int64) { return nil, nil, 1 })
+	repo := repository.NewTaskRepository(db)
+
+	n, err := repo.ScoreStarted(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	require.Len(t, fake.calls, 1)
+	assert.NotContains(t, fake.calls[0].Query, "updated_at", "a scheduler rescore is not an edit")
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type taskRevisionRepository struct {
+	db *sqlx.DB
+}
+
+// NewTaskRevisionRepository creates a new PostgreSQL-backed TaskRevisionRepository.
+func NewTaskRevisionRepository(db *sqlx.DB) domain.TaskRevisionRepository {
+	return &taskRevisionRepository{db: db}
+}
+
+func (r *taskRevisionRepository) Create(ctx context.Context, rev *domain.TaskRevision, keep int) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("taskRevisionRepository.Create: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query := `
+		INSERT INTO task_revisions (id, task_id, user_id, description, created_at)
+		VALUES (:id, :task_id, :user_id, :description, :created_at)`
+	if _, err := tx.NamedExecContext(ctx, query, rev); err != nil {
+		return fmt.Errorf("taskRevisionRepository.Create: %w", mapDBError(err))
+	}
+
+	prune := `
+		DELETE FROM task_revisions
+		WHERE task_id = $1 AND id NOT IN (
+			SELECT id FROM task_revisions WHERE task_id = $1
+			ORDER BY created_at DESC LIMIT $2
+		)`
+	if _, err := tx.ExecContext(ctx, prune, rev.TaskID, keep); err != nil {
+		return fmt.Errorf("taskRevisionRepository.Create prune: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *taskRevisionRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.TaskRevision, error) {
+	var rev domain.TaskRevision
+	if err := r.db.GetContext(ctx, &rev, `SELECT * FROM task_revisions WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("taskRevisionRepository.FindByID: %w", err)
+	}
+	return &rev, nil
+}
+
+func (r *taskRevisionRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TaskRevision, error) {
+	revisions := []*domain.TaskRevision{}
+	query := `SELECT * FROM task_revisions WHERE task_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &revisions, query, taskID); err != nil {
+		return nil, fmt.Errorf("taskRevisionRepository.ListByTaskID: %w", err)
+	}
+	return revisions, nil
+}
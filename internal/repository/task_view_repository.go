@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// taskViewTTL expires the view list of users who have been inactive for a while.
+const taskViewTTL = 30 * 24 * time.Hour
+
+type taskViewRepository struct {
+	rdb *redis.Client
+}
+
+// NewTaskViewRepository creates a Redis-backed TaskViewRepository. Each user
+// has one sorted set of task ids scored by view time, trimmed to
+// domain.MaxRecentTasks entries.
+func NewTaskViewRepository(rdb *redis.Client) domain.TaskViewRepository {
+	return &taskViewRepository{rdb: rdb}
+}
+
+func taskViewKey(userID uuid.UUID) string {
+	return "task_views:" + userID.String()
+}
+
+func (r *taskViewRepository) Record(ctx context.Context, userID, taskID uuid.UUID, at time.Time) error {
+	key := taskViewKey(userID)
+	_, err := r.rdb.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: taskID.String()})
+		p.ZRemRangeByRank(ctx, key, 0, -domain.MaxRecentTasks-1)
+		p.Expire(ctx, key, taskViewTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("taskViewRepository.Record: %w", err)
+	}
+	return nil
+}
+
+func (r *taskViewRepository) ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	members, err := r.rdb.ZRevRange(ctx, taskViewKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("taskViewRepository.ListRecent: %w", err)
+	}
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		if id, err := uuid.Parse(m); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+type memoryTaskViewRepository struct {
+	mu    sync.Mutex
+	views map[uuid.UUID]map[uuid.UUID]time.Time
+}
+
+// NewMemoryTaskViewRepository creates a process-local TaskViewRepository for
+// deployments without Redis. Views are lost on restart and not shared
+// between instances.
+func NewMemoryTaskViewRepository() domain.TaskViewRepository {
+	return &memoryTaskViewRepository{views: make(map[uuid.UUID]map[uuid.UUID]time.Time)}
+}
+
+func (r *memoryTaskViewRepository) Record(_ context.Context, userID, taskID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	views := r.views[userID]
+	if views == nil {
+		views = make(map[uuid.UUID]time.Time)
+		r.views[userID] = views
+	}
+	views[taskID] = at
+	if len(views) > domain.MaxRecentTasks {
+		var oldest uuid.UUID
+		for id, t := range views {
+			if oldest == uuid.Nil || t.Before(views[oldest]) {
+				oldest = id
+			}
+		}
+		delete(views, oldest)
+	}
+	return nil
+}
+
+func (r *memoryTaskViewRepository) ListRecent(_ context.Context, userID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	views := r.views[userID]
+	ids := make([]uuid.UUID, 0, len(views))
+	for id := range views {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return views[ids[i]].After(views[ids[j]]) })
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type timeEntryRepository struct {
+	db *sqlx.DB
+}
+
+// NewTimeEntryRepository creates a new PostgreSQL-backed TimeEntryRepository.
+func NewTimeEntryRepository(db *sqlx.DB) domain.TimeEntryRepository {
+	return &timeEntryRepository{db: db}
+}
+
+func (r *timeEntryRepository) Create(ctx context.Context, e *domain.TimeEntry) error {
+	query := `
+		INSERT INTO time_entries (id, task_id, user_id, started_at, created_at)
+		VALUES (:id, :task_id, :user_id, :started_at, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, e); err != nil {
+		return fmt.Errorf("timeEntryRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *timeEntryRepository) FindRunning(ctx context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	var e domain.TimeEntry
+	query := `SELECT * FROM time_entries WHERE user_id = $1 AND stopped_at IS NULL`
+	if err := r.db.GetContext(ctx, &e, query, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("timeEntryRepository.FindRunning: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *timeEntryRepository) ListByTaskID(ctx context.Context, taskID uuid.UUID) ([]*domain.TimeEntry, error) {
+	entries := []*domain.TimeEntry{}
+	query := `SELECT * FROM time_entries WHERE task_id = $1 ORDER BY started_at DESC`
+	if err := r.db.SelectContext(ctx, &entries, query, taskID); err != nil {
+		return nil, fmt.Errorf("timeEntryRepository.ListByTaskID: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *timeEntryRepository) Stop(ctx context.Context, id uuid.UUID, at time.Time) (*domain.TimeEntry, error) {
+	query := `
+		WITH stopped AS (
+			UPDATE time_entries
+			SET stopped_at = $2,
+			    duration_seconds = GREATEST(0, FLOOR(EXTRACT(EPOCH FROM ($2::timestamptz - started_at))))::bigint
+			WHERE id = $1 AND stopped_at IS NULL
+			RETURNING *
+		), rollup AS (
+			UPDATE tasks SET tracked_seconds = tracked_seconds + stopped.duration_seconds, effort_exceeded = FALSE
+			FROM stopped WHERE tasks.id = stopped.task_id
+		)
+		SELECT * FROM stopped`
+
+	var e domain.TimeEntry
+	if err := r.db.GetContext(ctx, &e, query, id, at); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("timeEntryRepository.Stop: %w", err)
+	}
+	return &e, nil
+}
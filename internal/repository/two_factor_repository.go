@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type twoFactorRepository struct {
+	db *sqlx.DB
+}
+
+// NewTwoFactorRepository creates a new PostgreSQL-backed TwoFactorRepository.
+func NewTwoFactorRepository(db *sqlx.DB) domain.TwoFactorRepository {
+	return &twoFactorRepository{db: db}
+}
+
+func (r *twoFactorRepository) Find(ctx context.Context, userID uuid.UUID) (*domain.TwoFactor, error) {
+	var tf domain.TwoFactor
+	if err := r.db.GetContext(ctx, &tf, `SELECT * FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("twoFactorRepository.Find: %w", err)
+	}
+	return &tf, nil
+}
+
+func (r *twoFactorRepository) SavePending(ctx context.Context, tf *domain.TwoFactor) error {
+	// An enabled enrollment is left alone: replacing its secret would need
+	// a code from the current one.
+	res, err := r.db.NamedExecContext(ctx, `
+		INSERT INTO user_two_factor (user_id, secret, created_at)
+		VALUES (:user_id, :secret, :created_at)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret, created_at = EXCLUDED.created_at,
+		    last_step = 0, failed_attempts = 0, last_failed_at = NULL
+		WHERE user_two_factor.enabled_at IS NULL`, tf)
+	if err != nil {
+		return fmt.Errorf("twoFactorRepository.SavePending: %w", mapDBError(err))
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrAlreadyExists
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Enable(ctx context.Context, userID uuid.UUID, step int64, codeHashes []string, at time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("twoFactorRepository.Enable begin: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE user_two_factor SET enabled_at = $2, last_step = $3, failed_attempts = 0, last_failed_at = NULL
+		WHERE user_id = $1 AND enabled_at IS NULL`, userID, at, step)
+	if err != nil {
+		return fmt.Errorf("twoFactorRepository.Enable: %w", err)
+	}
+	if err := checkRowsAffected(res); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM two_factor_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("twoFactorRepository.Enable: %w", err)
+	}
+	for _, h := range codeHashes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO two_factor_recovery_codes (user_id, code_hash, created_at) VALUES ($1, $2, $3)`,
+			userID, h, at,
+		); err != nil {
+			return fmt.Errorf("twoFactorRepository.Enable: %w", mapDBError(err))
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("twoFactorRepository.Enable commit: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) AcceptStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE user_two_factor SET last_step = $2, failed_attempts = 0, last_failed_at = NULL
+		WHERE user_id = $1 AND last_step < $2`, userID, step)
+	if err != nil {
+		return false, fmt.Errorf("twoFactorRepository.AcceptStep: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *twoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string, at time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH spent AS (
+			UPDATE two_factor_recovery_codes SET used_at = $3
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			RETURNING user_id
+		)
+		UPDATE user_two_factor SET failed_attempts = 0, last_failed_at = NULL
+		WHERE user_id IN (SELECT user_id FROM spent)`, userID, codeHash, at)
+	if err != nil {
+		return false, fmt.Errorf("twoFactorRepository.UseRecoveryCode: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return n == 1, nil
+}
+
+func (r *twoFactorRepository) RecordFailure(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE user_two_factor SET failed_attempts = failed_attempts + 1, last_failed_at = $2
+		WHERE user_id = $1`, userID, at)
+	if err != nil {
+		return fmt.Errorf("twoFactorRepository.RecordFailure: %w", err)
+	}
+	return nil
+}
+
+func (r *twoFactorRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	// Recovery codes go in the same statement.
+	_, err := r.db.ExecContext(ctx, `
+		WITH codes AS (DELETE FROM two_factor_recovery_codes WHERE user_id = $1)
+		DELETE FROM user_two_factor WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("twoFactorRepository.Delete: %w", err)
+	}
+	return nil
+}
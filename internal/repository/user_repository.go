@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/google/uuid"
@@ -22,8 +23,8 @@ func NewUserRepository(db *sqlx.DB) domain.UserRepository {
 
 func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (id, name, email, password_hash, created_at, updated_at)
-		VALUES (:id, :name, :email, :password_hash, :created_at, :updated_at)`
+		INSERT INTO users (id, name, email, password_hash, timezone, referral_code, invite_code_id, created_at, updated_at)
+		VALUES (:id, :name, :email, :password_hash, :timezone, :referral_code, :invite_code_id, :created_at, :updated_at)`
 
 	if _, err := r.db.NamedExecContext(ctx, query, user); err != nil {
 		return fmt.Errorf("userRepository.Create: %w", mapDBError(err))
@@ -55,6 +56,28 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain
 	return &user, nil
 }
 
+func (r *userRepository) FindByReferralCode(ctx context.Context, code string) (*domain.User, error) {
+	var user domain.User
+	query := `SELECT * FROM users WHERE referral_code = $1 AND deleted_at IS NULL`
+	if err := r.db.GetContext(ctx, &user, query, code); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("userRepository.FindByReferralCode: %w", err)
+	}
+	return &user, nil
+}
+
+func (r *userRepository) SetChangelogSeen(ctx context.Context, id uuid.UUID, at time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET changelog_seen_at = $2 WHERE id = $1 AND deleted_at IS NULL`, id, at,
+	)
+	if err != nil {
+		return fmt.Errorf("userRepository.SetChangelogSeen: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
 func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
@@ -76,3 +99,51 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 	return checkRowsAffected(res)
 }
+
+func (r *userRepository) ListIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, `SELECT id FROM users WHERE deleted_at IS NULL ORDER BY created_at`); err != nil {
+		return nil, fmt.Errorf("userRepository.ListIDs: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *userRepository) SetAdmin(ctx context.Context, id uuid.UUID, isAdmin bool) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET is_admin = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, isAdmin,
+	)
+	if err != nil {
+		return fmt.Errorf("userRepository.SetAdmin: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *userRepository) SetRanker(ctx context.Context, id uuid.UUID, ranker *string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET ranker = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, ranker,
+	)
+	if err != nil {
+		return fmt.Errorf("userRepository.SetRanker: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *userRepository) SetPasswordHash(ctx context.Context, id uuid.UUID, digest string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash = $2 WHERE id = $1 AND deleted_at IS NULL`, id, digest,
+	)
+	if err != nil {
+		return fmt.Errorf("userRepository.SetPasswordHash: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *userRepository) SetTimezone(ctx context.Context, id uuid.UUID, tz string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET timezone = $2, updated_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id, tz,
+	)
+	if err != nil {
+		return fmt.Errorf("userRepository.SetTimezone: %w", err)
+	}
+	return checkRowsAffected(res)
+}
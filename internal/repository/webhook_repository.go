@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type webhookRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository creates a new PostgreSQL-backed WebhookRepository.
+func NewWebhookRepository(db *sqlx.DB) domain.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, w *domain.Webhook) error {
+	query := `
+		INSERT INTO webhooks (id, user_id, url, secret, events, payload_version, active, created_at, updated_at)
+		VALUES (:id, :user_id, :url, :secret, :events, :payload_version, :active, :created_at, :updated_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, w); err != nil {
+		return fmt.Errorf("webhookRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	var w domain.Webhook
+	if err := r.db.GetContext(ctx, &w, `SELECT * FROM webhooks WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("webhookRepository.FindByID: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *webhookRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Webhook, error) {
+	var webhooks []*domain.Webhook
+	query := `SELECT * FROM webhooks WHERE user_id = $1 ORDER BY created_at DESC`
+	if err := r.db.SelectContext(ctx, &webhooks, query, userID); err != nil {
+		return nil, fmt.Errorf("webhookRepository.ListByUserID: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) ListSubscribed(ctx context.Context, userID uuid.UUID, event string) ([]*domain.Webhook, error) {
+	var webhooks []*domain.Webhook
+	query := `SELECT * FROM webhooks WHERE user_id = $1 AND active AND $2 = ANY(events)`
+	if err := r.db.SelectContext(ctx, &webhooks, query, userID, event); err != nil {
+		return nil, fmt.Errorf("webhookRepository.ListSubscribed: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, w *domain.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = :url, events = :events, payload_version = :payload_version, active = :active, updated_at = :updated_at
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, w)
+	if err != nil {
+		return fmt.Errorf("webhookRepository.Update: %w", mapDBError(err))
+	}
+	return checkRowsAffected(res)
+}
+
+// Delete removes the webhook together with its stored deliveries.
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("webhookRepository.Delete: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+// RecordFailure bumps the failure count atomically so concurrent deliveries
+// cannot lose an increment.
+func (r *webhookRepository) RecordFailure(ctx context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error) {
+	query := `
+		UPDATE webhooks SET
+			consecutive_failures = consecutive_failures + 1,
+			breaker_open_until = CASE WHEN consecutive_failures + 1 >= $2 THEN $3 ELSE breaker_open_until END
+		WHERE id = $1
+		RETURNING consecutive_failures`
+
+	var failures int
+	if err := r.db.GetContext(ctx, &failures, query, id, threshold, openUntil); err != nil {
+		return 0, fmt.Errorf("webhookRepository.RecordFailure: %w", mapDBError(err))
+	}
+	return failures, nil
+}
+
+func (r *webhookRepository) ResetBreaker(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE webhooks SET consecutive_failures = 0, breaker_open_until = NULL WHERE id = $1`, id,
+	)
+	if err != nil {
+		return fmt.Errorf("webhookRepository.ResetBreaker: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+type webhookDeliveryRepository struct {
+	db *sqlx.DB
+}
+
+// NewWebhookDeliveryRepository creates a new PostgreSQL-backed WebhookDeliveryRepository.
+func NewWebhookDeliveryRepository(db *sqlx.DB) domain.WebhookDeliveryRepository {
+	return &webhookDeliveryRepository{db: db}
+}
+
+func (r *webhookDeliveryRepository) Create(ctx context.Context, d *domain.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries
+			(id, webhook_id, event, payload_version, payload, status, attempts, redelivery_of, created_at)
+		VALUES
+			(:id, :webhook_id, :event, :payload_version, :payload, :status, :attempts, :redelivery_of, :created_at)`
+
+	if _, err := r.db.NamedExecContext(ctx, query, d); err != nil {
+		return fmt.Errorf("webhookDeliveryRepository.Create: %w", mapDBError(err))
+	}
+	return nil
+}
+
+func (r *webhookDeliveryRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	if err := r.db.GetContext(ctx, &d, `SELECT * FROM webhook_deliveries WHERE id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, fmt.Errorf("webhookDeliveryRepository.FindByID: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *webhookDeliveryRepository) ListByWebhookID(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	page, limit int,
+) ([]*domain.WebhookDelivery, int, error) {
+	var total int
+	if err := r.db.GetContext(ctx, &total,
+		`SELECT COUNT(*) FROM webhook_deliveries WHERE webhook_id = $1`, webhookID,
+	); err != nil {
+		return nil, 0, fmt.Errorf("webhookDeliveryRepository.ListByWebhookID count: %w", err)
+	}
+
+	var deliveries []*domain.WebhookDelivery
+	query := `
+		SELECT * FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3`
+	if err := r.db.SelectContext(ctx, &deliveries, query, webhookID, limit, (page-1)*limit); err != nil {
+		return nil, 0, fmt.Errorf("webhookDeliveryRepository.ListByWebhookID select: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+func (r *webhookDeliveryRepository) RecordAttempt(ctx context.Context, d *domain.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = :status, response_status = :response_status, error = :error,
+		    attempts = :attempts, last_attempt_at = :last_attempt_at
+		WHERE id = :id`
+
+	res, err := r.db.NamedExecContext(ctx, query, d)
+	if err != nil {
+		return fmt.Errorf("webhookDeliveryRepository.RecordAttempt: %w", err)
+	}
+	return checkRowsAffected(res)
+}
+
+func (r *webhookDeliveryRepository) DeleteOlderThan(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM webhook_deliveries WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("webhookDeliveryRepository.DeleteOlderThan: %w", err)
+	}
+	return res.RowsAffected()
+}
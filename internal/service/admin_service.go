@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/hash"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminService implements operator tasks run outside the HTTP API.
+type AdminService struct {
+	userRepo         domain.UserRepository
+	refreshTokenRepo domain.RefreshTokenRepository
+	maintenanceRepo  domain.MaintenanceRepository
+	taskSvc          *TaskService
+	hasher           *hash.Hasher
+	log              *logrus.Logger
+}
+
+// NewAdminService constructs an AdminService with its dependencies.
+func NewAdminService(
+	userRepo domain.UserRepository,
+	refreshTokenRepo domain.RefreshTokenRepository,
+	maintenanceRepo domain.MaintenanceRepository,
+	taskSvc *TaskService,
+	hasher *hash.Hasher,
+	log *logrus.Logger,
+) *AdminService {
+	return &AdminService{
+		userRepo:         userRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		maintenanceRepo:  maintenanceRepo,
+		taskSvc:          taskSvc,
+		hasher:           hasher,
+		log:              log,
+	}
+}
+
+// FindUser resolves a user by UUID or email address.
+func (s *AdminService) FindUser(ctx context.Context, ref string) (*domain.User, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return s.userRepo.FindByID(ctx, id)
+	}
+	return s.userRepo.FindByEmail(ctx, strings.TrimSpace(ref))
+}
+
+// RevokeTokens deletes every refresh token of the user, signing them out on all devices
+// once their current access token expires.
+func (s *AdminService) RevokeTokens(ctx context.Context, userID uuid.UUID) error {
+	if err := s.refreshTokenRepo.DeleteByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("adminService.RevokeTokens: %w", err)
+	}
+	s.log.WithField("user_id", userID).Warn("admin revoked all refresh tokens")
+	return nil
+}
+
+// ResetPassword sets a new password and revokes existing sessions. When
+// password is empty a random one is generated. The password set is returned.
+func (s *AdminService) ResetPassword(ctx context.Context, userID uuid.UUID, password string) (string, error) {
+	if password == "" {
+		generated, err := randomPassword()
+		if err != nil {
+			return "", fmt.Errorf("adminService.ResetPassword: %w", err)
+		}
+		password = generated
+	}
+	if len(password) < 8 || len(password) > 72 {
+		return "", fmt.Errorf("adminService.ResetPassword: password must be 8-72 characters: %w", domain.ErrValidation)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if user.Password, err = s.hasher.Hash(password); err != nil {
+		return "", fmt.Errorf("adminService.ResetPassword hash password: %w", err)
+	}
+	user.UpdatedAt = time.Now()
+
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return "", fmt.Errorf("adminService.ResetPassword: %w", err)
+	}
+	if err := s.RevokeTokens(ctx, userID); err != nil {
+		return "", err
+	}
+
+	s.log.WithField("user_id", userID).Warn("admin reset password")
+	return password, nil
+}
+
+// RecalcScores recomputes smart scores for one user, or for every user when
+// userID is nil. It returns how many users were processed.
+func (s *AdminService) RecalcScores(ctx context.Context, userID *uuid.UUID) (int, error) {
+	var ids []uuid.UUID
+	if userID != nil {
+		ids = []uuid.UUID{*userID}
+	} else {
+		all, err := s.userRepo.ListIDs(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("adminService.RecalcScores: %w", err)
+		}
+		ids = all
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := s.taskSvc.RefreshSmartScores(ctx, id); err != nil {
+			errs = append(errs, fmt.Errorf("user %s: %w", id, err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return len(ids) - len(errs), fmt.Errorf("adminService.RecalcScores: %w", err)
+	}
+	return len(ids), nil
+}
+
+// IsAdmin reports whether the user holds the admin role.
+func (s *AdminService) IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsAdmin, nil
+}
+
+// SetAdmin grants or revokes the admin role.
+func (s *AdminService) SetAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool) error {
+	if err := s.userRepo.SetAdmin(ctx, userID, isAdmin); err != nil {
+		return fmt.Errorf("adminService.SetAdmin: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{"user_id": userID, "is_admin": isAdmin}).Warn("admin role changed")
+	return nil
+}
+
+// PurgeTrash hard-deletes tasks and projects soft-deleted before the cutoff.
+func (s *AdminService) PurgeTrash(ctx context.Context, deletedBefore time.Time, dryRun bool) (*domain.OperationResult, error) {
+	return operation{
+		name: "purge_trash",
+		plan: func(ctx context.Context) (map[string][]uuid.UUID, error) {
+			return s.maintenanceRepo.ListTrash(ctx, deletedBefore)
+		},
+		apply: s.maintenanceRepo.PurgeTrash,
+	}.run(ctx, dryRun)
+}
+
+// HardDeleteUser permanently removes a user and everything they own.
+func (s *AdminService) HardDeleteUser(ctx context.Context, userID uuid.UUID, dryRun bool) (*domain.OperationResult, error) {
+	result, err := operation{
+		name: "hard_delete_user",
+		plan: func(ctx context.Context) (map[string][]uuid.UUID, error) {
+			return s.maintenanceRepo.ListUserData(ctx, userID)
+		},
+		apply: func(ctx context.Context, _ map[string][]uuid.UUID) error {
+			return s.maintenanceRepo.HardDeleteUser(ctx, userID)
+		},
+	}.run(ctx, dryRun)
+	if err == nil && !dryRun {
+		s.log.WithFields(logrus.Fields{"user_id": userID, "rows": result.Total}).Warn("admin hard-deleted user")
+	}
+	return result, err
+}
+
+// PurgeTrashPayload starts an OperationPurgeTrash operation.
+type PurgeTrashPayload struct {
+	DeletedBefore time.Time `json:"deleted_before"`
+}
+
+// RunPurgeTrash is the OperationRunner for OperationPurgeTrash.
+func (s *AdminService) RunPurgeTrash(ctx context.Context, _ uuid.UUID, payload json.RawMessage, _ func(int)) (*domain.OperationOutput, error) {
+	var p PurgeTrashPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("adminService.RunPurgeTrash: %w", err)
+	}
+	result, err := s.PurgeTrash(ctx, p.DeletedBefore, false)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OperationOutput{Result: result}, nil
+}
+
+// HardDeleteUserPayload starts an OperationHardDeleteUser operation.
+type HardDeleteUserPayload struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// RunHardDeleteUser is the OperationRunner for OperationHardDeleteUser.
+func (s *AdminService) RunHardDeleteUser(ctx context.Context, _ uuid.UUID, payload json.RawMessage, _ func(int)) (*domain.OperationOutput, error) {
+	var p HardDeleteUserPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("adminService.RunHardDeleteUser: %w", err)
+	}
+	result, err := s.HardDeleteUser(ctx, p.UserID, false)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OperationOutput{Result: result}, nil
+}
+
+// ReindexSearch rebuilds the task indexes used by search.
+func (s *AdminService) ReindexSearch(ctx context.Context) error {
+	if err := s.maintenanceRepo.ReindexTasks(ctx); err != nil {
+		return fmt.Errorf("adminService.ReindexSearch: %w", err)
+	}
+	return nil
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+)
+
+// Natural-language analytics questions are matched against fixed phrase
+// templates; anything not recognised is rejected rather than guessed at.
+// The result is a domain.AnalyticsQuery, never SQL.
+
+// analyticsQuestionHelp is returned when a question cannot be understood.
+const analyticsQuestionHelp = `ask about tasks finished, created or overdue, or average completion time, ` +
+	`optionally "per project|priority|status|day|week|month" and a period such as "today", "last week", ` +
+	`"this month" or "last 30 days"`
+
+var (
+	metricPatterns = []struct {
+		re     *regexp.Regexp
+		metric string
+	}{
+		// Order matters: "how long did completed tasks take" is about time.
+		{regexp.MustCompile(`\b(how long|average|avg|mean)\b`), domain.MetricAvgCompletionHours},
+		{regexp.MustCompile(`\b(overdue|late|past due)\b`), domain.MetricTasksOverdue},
+		{regexp.MustCompile(`\b(creat\w*|add\w*|new)\b`), domain.MetricTasksCreated},
+		{regexp.MustCompile(`\b(finish\w*|complet\w*|done|clos\w*)\b`), domain.MetricTasksCompleted},
+	}
+
+	groupPattern   = regexp.MustCompile(`\b(?:per|by|each|for each|grouped by|broken down by)\s+(project|priorit|status|day|week|month)\w*`)
+	groupWords     = map[string]string{"priorit": domain.GroupByPriority}
+	groupAdverbs   = regexp.MustCompile(`\b(daily|weekly|monthly)\b`)
+	adverbGroups   = map[string]string{"daily": domain.GroupByDay, "weekly": domain.GroupByWeek, "monthly": domain.GroupByMonth}
+	lastNPattern   = regexp.MustCompile(`\b(?:last|past)\s+(\d{1,3})\s+(day|week|month)s?\b`)
+	periodPatterns = regexp.MustCompile(`\b(today|yesterday|this week|last week|this month|last month|this year|last year)\b`)
+)
+
+// ParseAnalyticsQuestion turns a question into a structured query. now sets
+// both the reference time for relative periods and the time zone used for
+// calendar boundaries.
+func ParseAnalyticsQuestion(question string, now time.Time) (*domain.AnalyticsQuery, error) {
+	text := strings.ToLower(strings.Join(strings.Fields(question), " "))
+
+	q := &domain.AnalyticsQuery{Timezone: now.Location().String()}
+	for _, p := range metricPatterns {
+		if p.re.MatchString(text) {
+			q.Metric = p.metric
+			break
+		}
+	}
+	if q.Metric == "" {
+		return nil, fmt.Errorf("unrecognised question, %s: %w", analyticsQuestionHelp, domain.ErrValidation)
+	}
+
+	if m := groupPattern.FindStringSubmatch(text); m != nil {
+		q.GroupBy = m[1]
+		if g, ok := groupWords[m[1]]; ok {
+			q.GroupBy = g
+		}
+	} else if m := groupAdverbs.FindString(text); m != "" {
+		q.GroupBy = adverbGroups[m]
+	}
+
+	from, to, ok := parsePeriod(text, now)
+	if ok {
+		q.From, q.To = &from, &to
+	}
+	return q, nil
+}
+
+// parsePeriod finds a relative period in text. Weeks start on Monday.
+func parsePeriod(text string, now time.Time) (from, to time.Time, ok bool) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	monthStart := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, now.Location())
+	yearStart := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+
+	if m := lastNPattern.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "day":
+			return tomorrow.AddDate(0, 0, -n), tomorrow, true
+		case "week":
+			return tomorrow.AddDate(0, 0, -7*n), tomorrow, true
+		default:
+			return tomorrow.AddDate(0, -n, 0), tomorrow, true
+		}
+	}
+
+	switch periodPatterns.FindString(text) {
+	case "today":
+		return today, tomorrow, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), today, true
+	case "this week":
+		return weekStart, weekStart.AddDate(0, 0, 7), true
+	case "last week":
+		return weekStart.AddDate(0, 0, -7), weekStart, true
+	case "this month":
+		return monthStart, monthStart.AddDate(0, 1, 0), true
+	case "last month":
+		return monthStart.AddDate(0, -1, 0), monthStart, true
+	case "this year":
+		return yearStart, yearStart.AddDate(1, 0, 0), true
+	case "last year":
+		return yearStart.AddDate(-1, 0, 0), yearStart, true
+	}
+	return time.Time{}, time.Time{}, false
+}
@@ -0,0 +1,55 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAnalyticsQuestion(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	// A Wednesday.
+	now := time.Date(2026, 10, 14, 15, 0, 0, 0, jakarta)
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, jakarta) }
+
+	tests := []struct {
+		question string
+		metric   string
+		groupBy  string
+		from, to time.Time
+	}{
+		{"How many tasks did I finish last month per project?", domain.MetricTasksCompleted, domain.GroupByProject, day(9, 1), day(10, 1)},
+		{"tasks created this week by priority", domain.MetricTasksCreated, domain.GroupByPriority, day(10, 12), day(10, 19)},
+		{"average time to complete tasks in the last 30 days, weekly", domain.MetricAvgCompletionHours, domain.GroupByWeek, day(9, 15), day(10, 15)},
+		{"how many overdue tasks broken down by priorities", domain.MetricTasksOverdue, domain.GroupByPriority, time.Time{}, time.Time{}},
+		{"what did I complete yesterday", domain.MetricTasksCompleted, "", day(10, 13), day(10, 14)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.question, func(t *testing.T) {
+			q, err := service.ParseAnalyticsQuestion(tt.question, now)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.metric, q.Metric)
+			assert.Equal(t, tt.groupBy, q.GroupBy)
+			assert.Equal(t, "Asia/Jakarta", q.Timezone)
+			if tt.from.IsZero() {
+				assert.Nil(t, q.From)
+				assert.Nil(t, q.To)
+				return
+			}
+			require.NotNil(t, q.From)
+			assert.True(t, tt.from.Equal(*q.From), "from = %s", q.From)
+			assert.True(t, tt.to.Equal(*q.To), "to = %s", q.To)
+		})
+	}
+}
+
+func TestParseAnalyticsQuestion_RejectsUnknownQuestions(t *testing.T) {
+	_, err := service.ParseAnalyticsQuestion("drop table tasks", time.Now())
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
@@ -12,16 +12,26 @@ import (
 // AnalyticsService handles analytics use cases.
 type AnalyticsService struct {
 	analyticsRepo domain.AnalyticsRepository
+	userRepo      domain.UserRepository
+	coalescer     *ReadCoalescer
 }
 
 // NewAnalyticsService constructs an AnalyticsService with its dependencies.
-func NewAnalyticsService(analyticsRepo domain.AnalyticsRepository) *AnalyticsService {
-	return &AnalyticsService{analyticsRepo: analyticsRepo}
+func NewAnalyticsService(analyticsRepo domain.AnalyticsRepository, userRepo domain.UserRepository) *AnalyticsService {
+	return &AnalyticsService{analyticsRepo: analyticsRepo, userRepo: userRepo}
+}
+
+// UseCoalescer shares each dashboard query between concurrent requests for
+// the same user.
+func (s *AnalyticsService) UseCoalescer(c *ReadCoalescer) {
+	s.coalescer = c
 }
 
 // GetDashboard returns the full productivity dashboard for a user.
 func (s *AnalyticsService) GetDashboard(ctx context.Context, userID uuid.UUID) (*domain.AnalyticsDashboard, error) {
-	dash, err := s.analyticsRepo.GetDashboard(ctx, userID)
+	dash, err := coalesce(ctx, s.coalescer, coalesceDashboard, userID.String(), func(ctx context.Context) (*domain.AnalyticsDashboard, error) {
+		return s.analyticsRepo.GetDashboard(ctx, userID)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("analyticsService.GetDashboard: %w", err)
 	}
@@ -43,3 +53,53 @@ func (s *AnalyticsService) GetDailyStats(ctx context.Context, userID uuid.UUID,
 	}
 	return stats, nil
 }
+
+// maxEstimateDays bounds the look-back of EstimateAccuracy.
+const maxEstimateDays = 365
+
+// EstimateAccuracy compares the estimates of the tasks the user finished in
+// the last days days with the hours they actually took.
+func (s *AnalyticsService) EstimateAccuracy(ctx context.Context, userID uuid.UUID, days int) (*domain.EstimateAccuracy, error) {
+	if days < 1 || days > maxEstimateDays {
+		return nil, fmt.Errorf("analyticsService.EstimateAccuracy: days must be between 1 and %d: %w", maxEstimateDays, domain.ErrValidation)
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	acc, err := s.analyticsRepo.EstimateAccuracy(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("analyticsService.EstimateAccuracy: %w", err)
+	}
+	return acc, nil
+}
+
+// StreamRawEvents calls fn with each task the user created or completed
+// from from up to to, in time order. Rows are passed on as they are read,
+// so the range is not bounded as the aggregates' are.
+func (s *AnalyticsService) StreamRawEvents(ctx context.Context, userID uuid.UUID, from, to time.Time, fn func(*domain.AnalyticsRawEvent) error) error {
+	if !to.After(from) {
+		return fmt.Errorf("analyticsService.StreamRawEvents: to must be after from: %w", domain.ErrValidation)
+	}
+	return s.analyticsRepo.StreamRawEvents(ctx, userID, from, to, fn)
+}
+
+// Ask answers a constrained natural-language question, such as "how many
+// tasks did I finish last month per project?", in the given time zone, or
+// the user's own when loc is nil.
+func (s *AnalyticsService) Ask(ctx context.Context, userID uuid.UUID, question string, loc *time.Location) (*domain.AnalyticsAnswer, error) {
+	loc, err := userLocation(ctx, s.userRepo, userID, loc)
+	if err != nil {
+		return nil, fmt.Errorf("analyticsService.Ask: %w", err)
+	}
+	q, err := ParseAnalyticsQuestion(question, time.Now().In(loc))
+	if err != nil {
+		return nil, fmt.Errorf("analyticsService.Ask: %w", err)
+	}
+
+	rows, err := s.analyticsRepo.Query(ctx, userID, *q)
+	if err != nil {
+		return nil, fmt.Errorf("analyticsService.Ask: %w", err)
+	}
+	if q.GroupBy == "" && len(rows) == 0 {
+		rows = []domain.AnalyticsRow{{Value: 0}}
+	}
+	return &domain.AnalyticsAnswer{Question: question, Query: *q, Rows: rows}, nil
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// pendingUploadTTL is how long an unconfirmed upload is kept before
+// PrunePending removes it.
+const pendingUploadTTL = 24 * time.Hour
+
+// AttachmentPolicy limits what may be attached to a task.
+type AttachmentPolicy struct {
+	MaxBytes     int64
+	AllowedTypes []string // media types; "image/*" allows a whole family, empty allows any
+	URLExpiry    time.Duration
+}
+
+// AttachmentService manages task attachments. Files go straight between the
+// client and object storage via presigned URLs; the service only records
+// them and checks the upload landed as announced.
+type AttachmentService struct {
+	attachmentRepo domain.AttachmentRepository
+	taskSvc        *TaskService
+	store          storage.Storage
+	policy         AttachmentPolicy
+	maxBytes       atomic.Int64 // policy.MaxBytes, changeable at runtime
+	log            *logrus.Logger
+}
+
+// NewAttachmentService constructs an AttachmentService. store may be nil, in
+// which case every call fails with ErrFeatureDisabled.
+func NewAttachmentService(
+	attachmentRepo domain.AttachmentRepository,
+	taskSvc *TaskService,
+	store storage.Storage,
+	policy AttachmentPolicy,
+	log *logrus.Logger,
+) *AttachmentService {
+	s := &AttachmentService{attachmentRepo: attachmentRepo, taskSvc: taskSvc, store: store, policy: policy, log: log}
+	s.maxBytes.Store(policy.MaxBytes)
+	return s
+}
+
+// MaxBytes is the largest upload accepted; 0 for no limit.
+func (s *AttachmentService) MaxBytes() int64 {
+	return s.maxBytes.Load()
+}
+
+// SetMaxBytes changes the largest upload accepted from the next upload on.
+func (s *AttachmentService) SetMaxBytes(n int64) {
+	s.maxBytes.Store(n)
+}
+
+// CreateUpload records a pending attachment and returns a presigned URL the
+// client PUTs the file to. The upload must then be confirmed with Complete.
+func (s *AttachmentService) CreateUpload(ctx context.Context, taskID, userID uuid.UUID, req *domain.CreateAttachmentRequest) (*domain.AttachmentUpload, error) {
+	if s.store == nil {
+		return nil, domain.ErrFeatureDisabled
+	}
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	contentType, err := s.checkType(req.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("attachmentService.CreateUpload: %w", err)
+	}
+	if maxBytes := s.MaxBytes(); maxBytes > 0 && req.SizeBytes > maxBytes {
+		return nil, fmt.Errorf("attachmentService.CreateUpload: file exceeds %d bytes: %w", maxBytes, domain.ErrValidation)
+	}
+	filename := sanitizeFilename(req.Filename)
+	if filename == "" {
+		return nil, fmt.Errorf("attachmentService.CreateUpload: invalid filename: %w", domain.ErrValidation)
+	}
+
+	id := uuid.New()
+	a := &domain.Attachment{
+		ID:          id,
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   req.SizeBytes,
+		StorageKey:  fmt.Sprintf("attachments/%s/%s/%s", userID, taskID, id),
+		Status:      domain.AttachmentPending,
+		CreatedAt:   time.Now(),
+	}
+	upload, err := s.store.PresignUpload(ctx, a.StorageKey, a.ContentType, a.SizeBytes, s.policy.URLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("attachmentService.CreateUpload: %w", err)
+	}
+	if err := s.attachmentRepo.Create(ctx, a); err != nil {
+		return nil, fmt.Errorf("attachmentService.CreateUpload: %w", err)
+	}
+	return &domain.AttachmentUpload{Attachment: a, Upload: toPresignedURL(upload)}, nil
+}
+
+// Complete confirms an upload: the object must exist with the announced size.
+func (s *AttachmentService) Complete(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.Attachment, error) {
+	a, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == domain.AttachmentUploaded {
+		return a, nil
+	}
+
+	info, err := s.store.Stat(ctx, a.StorageKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, fmt.Errorf("attachmentService.Complete: file has not been uploaded: %w", domain.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("attachmentService.Complete: %w", err)
+	}
+	if info.Size != a.SizeBytes {
+		return nil, fmt.Errorf("attachmentService.Complete: uploaded %d bytes, expected %d: %w", info.Size, a.SizeBytes, domain.ErrValidation)
+	}
+
+	now := time.Now()
+	if err := s.attachmentRepo.MarkUploaded(ctx, a.ID, now); err != nil {
+		return nil, fmt.Errorf("attachmentService.Complete: %w", err)
+	}
+	a.Status, a.UploadedAt = domain.AttachmentUploaded, &now
+	return a, nil
+}
+
+// List returns a task's attachments, including pending ones, oldest first.
+func (s *AttachmentService) List(ctx context.Context, taskID, userID uuid.UUID) ([]*domain.Attachment, error) {
+	if s.store == nil {
+		return nil, domain.ErrFeatureDisabled
+	}
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	attachments, err := s.attachmentRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("attachmentService.List: %w", err)
+	}
+	return attachments, nil
+}
+
+// Download returns a presigned URL for an uploaded attachment.
+func (s *AttachmentService) Download(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.PresignedURL, error) {
+	a, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status != domain.AttachmentUploaded {
+		return nil, fmt.Errorf("attachmentService.Download: upload not completed: %w", domain.ErrValidation)
+	}
+	req, err := s.store.PresignDownload(ctx, a.StorageKey, a.Filename, s.policy.URLExpiry)
+	if err != nil {
+		return nil, fmt.Errorf("attachmentService.Download: %w", err)
+	}
+	return toPresignedURL(req), nil
+}
+
+// Delete removes an attachment and its stored file.
+func (s *AttachmentService) Delete(ctx context.Context, taskID, id, userID uuid.UUID) error {
+	a, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.store.Delete(ctx, a.StorageKey); err != nil {
+		return fmt.Errorf("attachmentService.Delete: %w", err)
+	}
+	if err := s.attachmentRepo.Delete(ctx, a.ID); err != nil {
+		return fmt.Errorf("attachmentService.Delete: %w", err)
+	}
+	return nil
+}
+
+// PrunePending deletes uploads that were never confirmed, along with any
+// file that did arrive. Intended to be run by the scheduler.
+func (s *AttachmentService) PrunePending(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	stale, err := s.attachmentRepo.ListPendingBefore(ctx, time.Now().Add(-pendingUploadTTL), 500)
+	if err != nil {
+		return fmt.Errorf("attachmentService.PrunePending: %w", err)
+	}
+	for _, a := range stale {
+		if err := s.store.Delete(ctx, a.StorageKey); err != nil {
+			s.log.WithError(err).WithField("attachment_id", a.ID).Warn("failed to delete abandoned upload")
+			continue
+		}
+		if err := s.attachmentRepo.Delete(ctx, a.ID); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("attachmentService.PrunePending: %w", err)
+		}
+	}
+	return nil
+}
+
+// get loads an attachment of the user's task. Attachments of other tasks are
+// reported as not found, so ids cannot be probed through another task.
+func (s *AttachmentService) get(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.Attachment, error) {
+	if s.store == nil {
+		return nil, domain.ErrFeatureDisabled
+	}
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	a, err := s.attachmentRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.TaskID != taskID {
+		return nil, domain.ErrNotFound
+	}
+	return a, nil
+}
+
+// checkType normalises contentType and checks it against the policy.
+func (s *AttachmentService) checkType(contentType string) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return "", fmt.Errorf("invalid content type %q: %w", contentType, domain.ErrValidation)
+	}
+	if len(s.policy.AllowedTypes) == 0 {
+		return mediaType, nil
+	}
+	family, _, _ := strings.Cut(mediaType, "/")
+	for _, allowed := range s.policy.AllowedTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == mediaType || allowed == family+"/*" {
+			return mediaType, nil
+		}
+	}
+	return "", fmt.Errorf("content type %s is not allowed: %w", mediaType, domain.ErrValidation)
+}
+
+// sanitizeFilename keeps the base name and drops control characters, so the
+// name is safe to echo back in a Content-Disposition header.
+func sanitizeFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "." || name == "/" {
+		return ""
+	}
+	return name
+}
+
+func toPresignedURL(req *storage.PresignedRequest) *domain.PresignedURL {
+	return &domain.PresignedURL{Method: req.Method, URL: req.URL, Headers: req.Headers, ExpiresAt: req.ExpiresAt}
+}
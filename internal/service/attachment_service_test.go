@@ -0,0 +1,152 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAttachmentRepo struct {
+	domain.AttachmentRepository
+	byID map[uuid.UUID]*domain.Attachment
+}
+
+func (f *fakeAttachmentRepo) Create(_ context.Context, a *domain.Attachment) error {
+	if f.byID == nil {
+		f.byID = map[uuid.UUID]*domain.Attachment{}
+	}
+	f.byID[a.ID] = a
+	return nil
+}
+
+func (f *fakeAttachmentRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.Attachment, error) {
+	if a, ok := f.byID[id]; ok {
+		return a, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeAttachmentRepo) MarkUploaded(_ context.Context, id uuid.UUID, at time.Time) error {
+	f.byID[id].Status = domain.AttachmentUploaded
+	return nil
+}
+
+// fakeStore holds object sizes by key.
+type fakeStore struct {
+	objects map[string]int64
+}
+
+func (f *fakeStore) PresignUpload(_ context.Context, key, contentType string, size int64, ttl time.Duration) (*storage.PresignedRequest, error) {
+	return &storage.PresignedRequest{Method: "PUT", URL: "https://store.test/" + key, Headers: map[string]string{"Content-Type": contentType}}, nil
+}
+
+func (f *fakeStore) PresignDownload(_ context.Context, key, filename string, ttl time.Duration) (*storage.PresignedRequest, error) {
+	return &storage.PresignedRequest{Method: "GET", URL: "https://store.test/" + key}, nil
+}
+
+func (f *fakeStore) Stat(_ context.Context, key string) (*storage.ObjectInfo, error) {
+	size, ok := f.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return &storage.ObjectInfo{Size: size}, nil
+}
+
+func (f *fakeStore) Put(_ context.Context, key, contentType string, body []byte) error {
+	f.objects[key] = int64(len(body))
+	return nil
+}
+
+func (f *fakeStore) Get(_ context.Context, key string) ([]byte, error) {
+	if _, ok := f.objects[key]; !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return nil, nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, key string) error {
+	delete(f.objects, key)
+	return nil
+}
+
+func newAttachmentFixture(t *testing.T, store storage.Storage) (*service.AttachmentService, *fakeAttachmentRepo, uuid.UUID, uuid.UUID) {
+	t.Helper()
+	userID, taskID := uuid.New(), uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID}, nil)
+	repo := &fakeAttachmentRepo{}
+	policy := service.AttachmentPolicy{MaxBytes: 1000, AllowedTypes: []string{"image/*", "application/pdf"}, URLExpiry: time.Minute}
+	svc := service.NewAttachmentService(repo, newTaskService(taskRepo, &mockProjectRepo{}), store, policy, logrus.New())
+	return svc, repo, userID, taskID
+}
+
+func TestAttachmentService_CreateUploadValidates(t *testing.T) {
+	svc, _, userID, taskID := newAttachmentFixture(t, &fakeStore{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  domain.CreateAttachmentRequest
+		ok   bool
+	}{
+		{"allowed family", domain.CreateAttachmentRequest{Filename: "a.png", ContentType: "image/png", SizeBytes: 10}, true},
+		{"allowed exact with params", domain.CreateAttachmentRequest{Filename: "a.pdf", ContentType: "Application/PDF; x=y", SizeBytes: 10}, true},
+		{"type not allowed", domain.CreateAttachmentRequest{Filename: "a.exe", ContentType: "application/octet-stream", SizeBytes: 10}, false},
+		{"malformed type", domain.CreateAttachmentRequest{Filename: "a.png", ContentType: "image/", SizeBytes: 10}, false},
+		{"too large", domain.CreateAttachmentRequest{Filename: "a.png", ContentType: "image/png", SizeBytes: 1001}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateUpload(ctx, taskID, userID, &tt.req)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrValidation)
+			}
+		})
+	}
+}
+
+func TestAttachmentService_CompleteChecksUploadedSize(t *testing.T) {
+	store := &fakeStore{objects: map[string]int64{}}
+	svc, repo, userID, taskID := newAttachmentFixture(t, store)
+	ctx := context.Background()
+
+	up, err := svc.CreateUpload(ctx, taskID, userID, &domain.CreateAttachmentRequest{
+		Filename: `C:\Users\me\scan.pdf`, ContentType: "application/pdf", SizeBytes: 500,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "scan.pdf", up.Attachment.Filename)
+	assert.Equal(t, domain.AttachmentPending, up.Attachment.Status)
+	id := up.Attachment.ID
+
+	_, err = svc.Complete(ctx, taskID, id, userID)
+	assert.ErrorIs(t, err, domain.ErrValidation, "nothing uploaded yet")
+
+	store.objects[repo.byID[id].StorageKey] = 499
+	_, err = svc.Complete(ctx, taskID, id, userID)
+	assert.ErrorIs(t, err, domain.ErrValidation, "size mismatch")
+
+	store.objects[repo.byID[id].StorageKey] = 500
+	a, err := svc.Complete(ctx, taskID, id, userID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.AttachmentUploaded, a.Status)
+
+	_, err = svc.Download(ctx, taskID, id, userID)
+	assert.NoError(t, err)
+}
+
+func TestAttachmentService_DisabledWithoutStorage(t *testing.T) {
+	svc, _, userID, taskID := newAttachmentFixture(t, nil)
+	_, err := svc.List(context.Background(), taskID, userID)
+	assert.ErrorIs(t, err, domain.ErrFeatureDisabled)
+}
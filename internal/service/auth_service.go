@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
 	"github.com/galihaleanda/todo-app/pkg/hash"
 	pkgjwt "github.com/galihaleanda/todo-app/pkg/jwt"
 	"github.com/google/uuid"
@@ -16,7 +17,11 @@ import (
 type AuthService struct {
 	userRepo         domain.UserRepository
 	refreshTokenRepo domain.RefreshTokenRepository
+	invites          *InviteService
+	hasher           *hash.Hasher
 	jwtManager       *pkgjwt.Manager
+	twoFactor        *TwoFactorService
+	events           *eventbus.Bus
 	log              *logrus.Logger
 }
 
@@ -24,18 +29,36 @@ type AuthService struct {
 func NewAuthService(
 	userRepo domain.UserRepository,
 	refreshTokenRepo domain.RefreshTokenRepository,
+	invites *InviteService,
+	hasher *hash.Hasher,
 	jwtManager *pkgjwt.Manager,
 	log *logrus.Logger,
 ) *AuthService {
 	return &AuthService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		invites:          invites,
+		hasher:           hasher,
 		jwtManager:       jwtManager,
 		log:              log,
 	}
 }
 
-// Register creates a new user account.
+// UseTwoFactor adds the second login step for users who enabled
+// two-factor authentication. Must be called before serving requests.
+func (s *AuthService) UseTwoFactor(tf *TwoFactorService) {
+	s.twoFactor = tf
+}
+
+// UseEvents makes the service publish UserRegistered on bus.
+// Must be called before serving requests.
+func (s *AuthService) UseEvents(bus *eventbus.Bus) {
+	s.events = bus
+}
+
+// Register creates a new user account, redeeming the invite code it was
+// given; one is required while signup is invite-only. The signup is
+// published with its referral code and invite for attribution.
 func (s *AuthService) Register(ctx context.Context, req *domain.RegisterRequest) (*domain.AuthResponse, error) {
 	// Check uniqueness
 	existing, err := s.userRepo.FindByEmail(ctx, req.Email)
@@ -46,30 +69,57 @@ func (s *AuthService) Register(ctx context.Context, req *domain.RegisterRequest)
 		return nil, domain.ErrAlreadyExists
 	}
 
-	passwordHash, err := hash.Password(req.Password)
+	passwordHash, err := s.hasher.Hash(req.Password)
 	if err != nil {
 		return nil, fmt.Errorf("authService.Register hash password: %w", err)
 	}
 
+	invite, err := s.invites.Redeem(ctx, req.InviteCode)
+	if err != nil {
+		return nil, err
+	}
+
+	referralCode, err := generateReferralCode()
+	if err != nil {
+		s.invites.Release(ctx, invite)
+		return nil, fmt.Errorf("authService.Register: %w", err)
+	}
+
 	now := time.Now()
 	user := &domain.User{
-		ID:        uuid.New(),
-		Name:      req.Name,
-		Email:     req.Email,
-		Password:  passwordHash,
-		CreatedAt: now,
-		UpdatedAt: now,
+		ID:           uuid.New(),
+		Name:         req.Name,
+		Email:        req.Email,
+		Password:     passwordHash,
+		Timezone:     domain.LoadLocation(req.Timezone).String(),
+		ReferralCode: referralCode,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if invite != nil {
+		user.InviteCodeID = &invite.ID
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
+		s.invites.Release(ctx, invite)
 		return nil, fmt.Errorf("authService.Register create user: %w", err)
 	}
 
+	s.events.Publish(ctx, domain.UserRegistered{
+		User:           user,
+		ReferralCode:   req.ReferralCode,
+		ReferralSource: req.ReferralSource,
+		Invite:         invite,
+	})
+
 	s.log.WithField("user_id", user.ID).Info("new user registered")
 	return s.buildAuthResponse(ctx, user, "register-device")
 }
 
-// Login authenticates a user and returns tokens.
+// Login authenticates a user and returns tokens, or for a user with
+// two-factor authentication on, a challenge token for LoginTwoFactor. A
+// password digest made with an outdated algorithm or parameters is
+// replaced on the way.
 func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest, userAgent string) (*domain.AuthResponse, error) {
 	user, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
@@ -79,13 +129,63 @@ func (s *AuthService) Login(ctx context.Context, req *domain.LoginRequest, userA
 		return nil, fmt.Errorf("authService.Login FindByEmail: %w", err)
 	}
 
-	if err := hash.CheckPassword(req.Password, user.Password); err != nil {
+	rehash, err := s.hasher.Verify(req.Password, user.Password)
+	if err != nil {
 		return nil, domain.ErrInvalidCredentials
 	}
+	if rehash {
+		s.upgradePassword(ctx, user.ID, req.Password)
+	}
 
+	if s.twoFactor != nil {
+		required, err := s.twoFactor.Required(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("authService.Login: %w", err)
+		}
+		if required {
+			challenge, err := s.jwtManager.GenerateChallengeToken(user.ID)
+			if err != nil {
+				return nil, fmt.Errorf("authService.Login: %w", err)
+			}
+			return &domain.AuthResponse{TwoFactorRequired: true, ChallengeToken: challenge}, nil
+		}
+	}
+
+	return s.buildAuthResponse(ctx, user, req.DeviceID)
+}
+
+// LoginTwoFactor completes a login that returned a challenge, given a code
+// from the user's authenticator app or a recovery code.
+func (s *AuthService) LoginTwoFactor(ctx context.Context, req *domain.TwoFactorLoginRequest) (*domain.AuthResponse, error) {
+	claims, err := s.jwtManager.ParseChallengeToken(req.ChallengeToken)
+	if err != nil || s.twoFactor == nil {
+		return nil, domain.ErrTokenInvalid
+	}
+	if err := s.twoFactor.Check(ctx, claims.UserID, req.Code); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("authService.LoginTwoFactor FindByID: %w", err)
+	}
 	return s.buildAuthResponse(ctx, user, req.DeviceID)
 }
 
+// upgradePassword stores a digest of plain made with the current settings.
+// A failure is only logged: the old digest still works, and the next login
+// tries again.
+func (s *AuthService) upgradePassword(ctx context.Context, userID uuid.UUID, plain string) {
+	digest, err := s.hasher.Hash(plain)
+	if err == nil {
+		err = s.userRepo.SetPasswordHash(ctx, userID, digest)
+	}
+	if err != nil {
+		s.log.WithError(err).WithField("user_id", userID).Warn("failed to upgrade password digest")
+		return
+	}
+	s.log.WithField("user_id", userID).Info("password digest upgraded")
+}
+
 // RefreshTokens rotates the refresh token and issues a new access token.
 func (s *AuthService) RefreshTokens(ctx context.Context, req *domain.RefreshTokenRequest) (*domain.AuthResponse, error) {
 	claims, err := s.jwtManager.ParseRefreshToken(req.RefreshToken)
@@ -0,0 +1,77 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/hash"
+	pkgjwt "github.com/galihaleanda/todo-app/pkg/jwt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLoginUserRepo struct {
+	domain.UserRepository
+	user     *domain.User
+	rewrites int
+}
+
+func (f *fakeLoginUserRepo) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	if f.user.Email != email {
+		return nil, domain.ErrNotFound
+	}
+	return f.user, nil
+}
+
+func (f *fakeLoginUserRepo) SetPasswordHash(_ context.Context, id uuid.UUID, digest string) error {
+	if id != f.user.ID {
+		return domain.ErrNotFound
+	}
+	f.user.Password = digest
+	f.rewrites++
+	return nil
+}
+
+type fakeRefreshTokenRepo struct {
+	domain.RefreshTokenRepository
+}
+
+func (fakeRefreshTokenRepo) Create(context.Context, *domain.RefreshToken) error { return nil }
+
+func TestAuthService_Login_UpgradesBcryptDigest(t *testing.T) {
+	fast := hash.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+	legacy, err := hash.New(hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4})
+	require.NoError(t, err)
+	current, err := hash.New(hash.Config{Algorithm: hash.Argon2id, Argon2: fast, BcryptCost: 4})
+	require.NoError(t, err)
+
+	digest, err := legacy.Hash("hunter22")
+	require.NoError(t, err)
+	users := &fakeLoginUserRepo{user: &domain.User{ID: uuid.New(), Email: "ana@example.com", Password: digest}}
+
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	jwt := pkgjwt.New("access-secret", "refresh-secret", time.Minute, time.Hour)
+	svc := service.NewAuthService(users, fakeRefreshTokenRepo{}, nil, current, jwt, log)
+	ctx := context.Background()
+
+	_, err = svc.Login(ctx, &domain.LoginRequest{Email: "ana@example.com", Password: "wrong"}, "")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	assert.Equal(t, digest, users.user.Password, "a failed login changes nothing")
+
+	resp, err := svc.Login(ctx, &domain.LoginRequest{Email: "ana@example.com", Password: "hunter22"}, "")
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.AccessToken)
+	assert.Equal(t, 1, users.rewrites)
+	assert.True(t, strings.HasPrefix(users.user.Password, "$argon2id$"), users.user.Password)
+
+	_, err = svc.Login(ctx, &domain.LoginRequest{Email: "ana@example.com", Password: "hunter22"}, "")
+	require.NoError(t, err, "the upgraded digest verifies")
+	assert.Equal(t, 1, users.rewrites, "a current digest is not rewritten")
+}
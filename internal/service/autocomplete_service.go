@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/cache"
+	"github.com/google/uuid"
+)
+
+// Autocomplete limits.
+const (
+	DefaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 25
+	autocompleteCacheTTL     = 5 * time.Minute
+)
+
+// SuggestionSource loads every candidate of one autocomplete type for a user.
+type SuggestionSource func(ctx context.Context, userID uuid.UUID) ([]domain.Suggestion, error)
+
+type suggestionKey struct {
+	userID uuid.UUID
+	kind   string
+}
+
+// AutocompleteService answers prefix lookups for pickers. Each user's
+// candidates are loaded once and cached, so per-keystroke requests do not hit
+// the database; the owning services invalidate the cache on change.
+type AutocompleteService struct {
+	sources map[string]SuggestionSource
+	cache   *cache.TTL[suggestionKey, []domain.Suggestion]
+}
+
+// NewAutocompleteService constructs an AutocompleteService with project and
+// tag suggestions registered.
+func NewAutocompleteService(projectRepo domain.ProjectRepository, tagRepo domain.TagRepository) *AutocompleteService {
+	s := &AutocompleteService{
+		sources: map[string]SuggestionSource{},
+		cache:   cache.NewTTL[suggestionKey, []domain.Suggestion](autocompleteCacheTTL),
+	}
+	s.Register(domain.AutocompleteProject, func(ctx context.Context, userID uuid.UUID) ([]domain.Suggestion, error) {
+		projects, err := projectRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]domain.Suggestion, len(projects))
+		for i, p := range projects {
+			out[i] = domain.Suggestion{ID: p.ID, Name: p.Name}
+		}
+		return out, nil
+	})
+	s.Register(domain.AutocompleteTag, func(ctx context.Context, userID uuid.UUID) ([]domain.Suggestion, error) {
+		tags, err := tagRepo.ListByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]domain.Suggestion, len(tags))
+		for i, t := range tags {
+			out[i] = domain.Suggestion{ID: t.ID, Name: t.Name}
+		}
+		return out, nil
+	})
+	return s
+}
+
+// Register adds a suggestion type. Must be called before serving requests.
+func (s *AutocompleteService) Register(kind string, source SuggestionSource) {
+	s.sources[kind] = source
+}
+
+// Complete returns up to limit suggestions of the given type whose names
+// start with q, case-insensitively. Exact matches come first, then shorter names.
+func (s *AutocompleteService) Complete(ctx context.Context, userID uuid.UUID, kind, q string, limit int) ([]domain.Suggestion, error) {
+	source, ok := s.sources[kind]
+	if !ok {
+		return nil, fmt.Errorf("autocompleteService.Complete: unsupported type %q: %w", kind, domain.ErrValidation)
+	}
+	if limit <= 0 {
+		limit = DefaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+
+	key := suggestionKey{userID: userID, kind: kind}
+	all, ok := s.cache.Get(key)
+	if !ok {
+		loaded, err := source(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("autocompleteService.Complete: %w", err)
+		}
+		all = loaded
+		s.cache.Set(key, all)
+	}
+
+	prefix := strings.ToLower(strings.TrimSpace(q))
+	matches := []domain.Suggestion{}
+	for _, sg := range all {
+		if strings.HasPrefix(strings.ToLower(sg.Name), prefix) {
+			matches = append(matches, sg)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := strings.ToLower(matches[i].Name), strings.ToLower(matches[j].Name)
+		if (a == prefix) != (b == prefix) {
+			return a == prefix
+		}
+		if len(a) != len(b) {
+			return len(a) < len(b)
+		}
+		return a < b
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// Invalidate drops a user's cached suggestions of one type.
+func (s *AutocompleteService) Invalidate(userID uuid.UUID, kind string) {
+	s.cache.Delete(suggestionKey{userID: userID, kind: kind})
+}
+
+// ProjectChanged subscribes to project events, dropping cached suggestions.
+func (s *AutocompleteService) ProjectChanged(_ context.Context, e domain.ProjectChange) error {
+	s.Invalidate(e.EventProject().UserID, domain.AutocompleteProject)
+	return nil
+}
+
+// TagChanged subscribes to tag events, dropping cached suggestions.
+func (s *AutocompleteService) TagChanged(_ context.Context, e domain.TagChange) error {
+	s.Invalidate(e.EventTag().UserID, domain.AutocompleteTag)
+	return nil
+}
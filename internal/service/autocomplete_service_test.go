@@ -0,0 +1,80 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutocompleteService_PrefixMatchRankedAndCached(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{
+		{ID: uuid.New(), UserID: userID, Name: "Work stuff"},
+		{ID: uuid.New(), UserID: userID, Name: "Home"},
+		{ID: uuid.New(), UserID: userID, Name: "work"},
+		{ID: uuid.New(), UserID: userID, Name: "Workshop"},
+	}, nil).Once()
+	svc := service.NewAutocompleteService(projectRepo, &fakeTagRepo{})
+	ctx := context.Background()
+
+	got, err := svc.Complete(ctx, userID, domain.AutocompleteProject, "WOR", 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "work", got[0].Name)
+	assert.Equal(t, "Workshop", got[1].Name)
+
+	// Served from cache: the mock only allows one load.
+	got, err = svc.Complete(ctx, userID, domain.AutocompleteProject, "h", 0)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+	projectRepo.AssertNumberOfCalls(t, "ListByUserID", 1)
+}
+
+func TestAutocompleteService_ProjectChangeInvalidatesCache(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{}, nil)
+	svc := service.NewAutocompleteService(projectRepo, &fakeTagRepo{})
+	ctx := context.Background()
+
+	_, err := svc.Complete(ctx, userID, domain.AutocompleteProject, "", 0)
+	require.NoError(t, err)
+	svc.ProjectChanged(ctx, domain.ProjectCreated{Project: &domain.Project{UserID: userID}})
+	_, err = svc.Complete(ctx, userID, domain.AutocompleteProject, "", 0)
+	require.NoError(t, err)
+
+	projectRepo.AssertNumberOfCalls(t, "ListByUserID", 2)
+}
+
+func TestAutocompleteService_UnknownTypeIsValidationError(t *testing.T) {
+	svc := service.NewAutocompleteService(&mockProjectRepo{}, &fakeTagRepo{})
+
+	_, err := svc.Complete(context.Background(), uuid.New(), "colour", "a", 0)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestAutocompleteService_TagChangeInvalidatesCache(t *testing.T) {
+	userID := uuid.New()
+	tags := &fakeTagRepo{}
+	svc := service.NewAutocompleteService(&mockProjectRepo{}, tags)
+	ctx := context.Background()
+
+	tag := &domain.Tag{ID: uuid.New(), UserID: userID, Name: "urgent"}
+	tags.tags = []*domain.Tag{tag}
+	got, err := svc.Complete(ctx, userID, domain.AutocompleteTag, "ur", 0)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Suggestion{{ID: tag.ID, Name: "urgent"}}, got)
+
+	tags.tags = nil
+	svc.TagChanged(ctx, domain.TagDeleted{Tag: tag})
+	got, err = svc.Complete(ctx, userID, domain.AutocompleteTag, "ur", 0)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
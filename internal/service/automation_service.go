@@ -0,0 +1,486 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// JobRunAutomation is the job type that runs an async automation rule.
+const JobRunAutomation = "automation.run"
+
+// maxAutomationExecutions caps how much of the execution log one request returns.
+const maxAutomationExecutions = 100
+
+// Notifier accepts notification events for delivery.
+type Notifier interface {
+	Notify(event domain.NotificationEvent)
+}
+
+type runAutomationJob struct {
+	ExecutionID uuid.UUID `json:"execution_id"`
+	RuleID      uuid.UUID `json:"rule_id"`
+	TaskID      uuid.UUID `json:"task_id"`
+}
+
+// automationRunKey marks a context as belonging to a rule's actions. Task
+// events published from it do not fire further rules, so rules cannot
+// trigger each other in a loop.
+type automationRunKey struct{}
+
+// AutomationService manages a user's automation rules and runs them when
+// their trigger fires: task events for completed and moved tasks, and a
+// periodic scan for overdue ones.
+type AutomationService struct {
+	ruleRepo    domain.AutomationRuleRepository
+	projectRepo domain.ProjectRepository
+	taskSvc     *TaskService
+	tagSvc      *TagService
+	notifier    Notifier
+	queue       *jobs.Queue
+	breaker     CircuitBreakerPolicy
+	log         *logrus.Logger
+}
+
+// NewAutomationService constructs an AutomationService and registers its job handler on queue.
+func NewAutomationService(
+	ruleRepo domain.AutomationRuleRepository,
+	projectRepo domain.ProjectRepository,
+	taskSvc *TaskService,
+	tagSvc *TagService,
+	notifier Notifier,
+	queue *jobs.Queue,
+	breaker CircuitBreakerPolicy,
+	log *logrus.Logger,
+) *AutomationService {
+	s := &AutomationService{
+		ruleRepo:    ruleRepo,
+		projectRepo: projectRepo,
+		taskSvc:     taskSvc,
+		tagSvc:      tagSvc,
+		notifier:    notifier,
+		queue:       queue,
+		breaker:     breaker,
+		log:         log,
+	}
+	queue.Register(JobRunAutomation, s.handleRunJob)
+	return s
+}
+
+// Create adds a rule for the authenticated user.
+func (s *AutomationService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateAutomationRuleRequest) (*domain.AutomationRule, error) {
+	now := time.Now()
+	rule := &domain.AutomationRule{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		Trigger:   req.Trigger,
+		Condition: req.Condition,
+		Actions:   req.Actions,
+		Async:     req.Async,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.validateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("automationService.Create: %w", err)
+	}
+	return rule, nil
+}
+
+// GetByID retrieves a rule, enforcing ownership.
+func (s *AutomationService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.AutomationRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	return rule, nil
+}
+
+// List returns the user's rules, oldest first.
+func (s *AutomationService) List(ctx context.Context, userID uuid.UUID) ([]*domain.AutomationRule, error) {
+	rules, err := s.ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("automationService.List: %w", err)
+	}
+	return rules, nil
+}
+
+// Update applies partial updates to a rule, enforcing ownership.
+func (s *AutomationService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateAutomationRuleRequest) (*domain.AutomationRule, error) {
+	rule, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Trigger != nil {
+		rule.Trigger = *req.Trigger
+	}
+	if req.Condition != nil {
+		rule.Condition = *req.Condition
+	}
+	if req.Actions != nil {
+		rule.Actions = req.Actions
+	}
+	if req.Async != nil {
+		rule.Async = *req.Async
+	}
+	if err := s.validateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("automationService.Update: %w", err)
+	}
+	return rule, nil
+}
+
+// Enable turns a rule back on and closes its circuit breaker, enforcing
+// ownership.
+func (s *AutomationService) Enable(ctx context.Context, id, userID uuid.UUID) (*domain.AutomationRule, error) {
+	rule, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ruleRepo.ResetBreaker(ctx, rule.ID); err != nil {
+		return nil, fmt.Errorf("automationService.Enable: %w", err)
+	}
+	rule.CircuitBreaker = domain.CircuitBreaker{}
+	if !rule.Enabled {
+		rule.Enabled = true
+		rule.UpdatedAt = time.Now()
+		if err := s.ruleRepo.Update(ctx, rule); err != nil {
+			return nil, fmt.Errorf("automationService.Enable: %w", err)
+		}
+	}
+	return rule, nil
+}
+
+// Delete removes a rule and its execution log, enforcing ownership.
+func (s *AutomationService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("automationService.Delete: %w", err)
+	}
+	return nil
+}
+
+// Executions returns the rule's most recent executions, newest first.
+func (s *AutomationService) Executions(ctx context.Context, id, userID uuid.UUID, limit int) ([]*domain.AutomationExecution, error) {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > maxAutomationExecutions {
+		limit = maxAutomationExecutions
+	}
+	execs, err := s.ruleRepo.ListExecutions(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("automationService.Executions: %w", err)
+	}
+	return execs, nil
+}
+
+// TaskChanged subscribes to task events, firing completed and moved rules.
+func (s *AutomationService) TaskChanged(ctx context.Context, e domain.TaskChange) error {
+	event, task := e.EventName(), e.EventTask()
+	if event != domain.AutomationTriggerCompleted && event != domain.AutomationTriggerMoved {
+		return nil
+	}
+	if ctx.Value(automationRunKey{}) != nil {
+		return nil
+	}
+	rules, err := s.ruleRepo.ListEnabled(ctx, task.UserID, event)
+	if err != nil {
+		return fmt.Errorf("automationService.TaskChanged: %w", err)
+	}
+	for _, rule := range rules {
+		if rule.Condition.Matches(task) {
+			s.fire(ctx, rule, task)
+		}
+	}
+	return nil
+}
+
+// RunOverdue fires overdue rules for every task that has become overdue
+// since the rule last ran against it. Intended to be run by the scheduler.
+func (s *AutomationService) RunOverdue(ctx context.Context) error {
+	users, err := s.ruleRepo.ListUsersWithTrigger(ctx, domain.AutomationTriggerOverdue)
+	if err != nil {
+		return fmt.Errorf("automationService.RunOverdue: %w", err)
+	}
+	for _, userID := range users {
+		if err := s.runOverdueForUser(ctx, userID); err != nil {
+			s.log.WithError(err).WithField("user_id", userID).Error("failed to run overdue automation rules")
+		}
+	}
+	return nil
+}
+
+func (s *AutomationService) runOverdueForUser(ctx context.Context, userID uuid.UUID) error {
+	rules, err := s.ruleRepo.ListEnabled(ctx, userID, domain.AutomationTriggerOverdue)
+	if err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+	return s.taskSvc.EachOverdue(ctx, userID, func(tasks []*domain.Task) error {
+		for _, task := range tasks {
+			for _, rule := range rules {
+				if !rule.Condition.Matches(task) {
+					continue
+				}
+				// A task fires each rule once per due date; moving the due
+				// date and missing it again fires it afresh.
+				done, err := s.ruleRepo.HasExecuted(ctx, rule.ID, task.ID, *task.DueDate)
+				if err != nil {
+					return err
+				}
+				if !done {
+					s.fire(ctx, rule, task)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// fire runs the rule now, or queues it for an async rule. Either way the
+// attempt is recorded in the execution log; rules whose breaker is open are
+// logged as skipped.
+func (s *AutomationService) fire(ctx context.Context, rule *domain.AutomationRule, task *domain.Task) {
+	e := &domain.AutomationExecution{
+		ID:        uuid.New(),
+		RuleID:    rule.ID,
+		UserID:    rule.UserID,
+		TaskID:    task.ID,
+		Trigger:   rule.Trigger,
+		Status:    domain.AutomationExecQueued,
+		CreatedAt: time.Now(),
+	}
+	entry := s.log.WithFields(logrus.Fields{"rule_id": rule.ID, "task_id": task.ID})
+
+	if rule.IsOpen(e.CreatedAt) {
+		setSkipped(e, rule)
+		if err := s.ruleRepo.CreateExecution(ctx, e); err != nil {
+			entry.WithError(err).Error("failed to record automation execution")
+		}
+		return
+	}
+
+	if rule.Async {
+		if err := s.ruleRepo.CreateExecution(ctx, e); err != nil {
+			entry.WithError(err).Error("failed to record automation execution")
+			return
+		}
+		err := s.queue.Enqueue(ctx, JobRunAutomation, runAutomationJob{ExecutionID: e.ID, RuleID: rule.ID, TaskID: task.ID})
+		if err != nil {
+			s.finish(ctx, e, 0, err)
+		}
+		return
+	}
+
+	applied, err := s.apply(ctx, rule, task)
+	s.finishNew(ctx, e, applied, err)
+	s.recordBreaker(ctx, rule, err)
+}
+
+func (s *AutomationService) handleRunJob(ctx context.Context, payload json.RawMessage) error {
+	var job runAutomationJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("%w: decode automation job: %w", jobs.ErrPermanent, err)
+	}
+	rule, err := s.ruleRepo.FindByID(ctx, job.RuleID)
+	if err != nil {
+		// The rule was deleted while queued; its log went with it.
+		return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+	}
+	e := &domain.AutomationExecution{ID: job.ExecutionID}
+	if rule.IsOpen(time.Now()) {
+		// The breaker tripped while this run sat in the queue.
+		setSkipped(e, rule)
+		if err := s.ruleRepo.UpdateExecution(ctx, e); err != nil && !errors.Is(err, domain.ErrNotFound) {
+			s.log.WithError(err).WithField("execution_id", e.ID).Error("failed to record automation execution")
+		}
+		return fmt.Errorf("%w: rule %s circuit breaker is open", jobs.ErrPermanent, rule.ID)
+	}
+
+	task, err := s.taskSvc.GetByID(ctx, job.TaskID, rule.UserID)
+	if err != nil {
+		s.finish(ctx, e, 0, err)
+		return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+	}
+	applied, err := s.apply(ctx, rule, task)
+	s.finish(ctx, e, applied, err)
+	s.recordBreaker(ctx, rule, err)
+	if err != nil {
+		// Actions are not idempotent (notify), so a partly applied rule is
+		// not retried.
+		return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+	}
+	return nil
+}
+
+// apply runs the rule's actions in order, stopping at the first failure, and
+// returns how many succeeded.
+func (s *AutomationService) apply(ctx context.Context, rule *domain.AutomationRule, task *domain.Task) (int, error) {
+	ctx = context.WithValue(ctx, automationRunKey{}, rule.ID)
+	// Work on a copy: task may be the event payload other subscribers see.
+	t := *task
+	task = &t
+	for i, action := range rule.Actions {
+		if err := s.applyAction(ctx, rule, task, action); err != nil {
+			return i, fmt.Errorf("action %d (%s): %w", i+1, action.Type, err)
+		}
+	}
+	return len(rule.Actions), nil
+}
+
+func (s *AutomationService) applyAction(ctx context.Context, rule *domain.AutomationRule, task *domain.Task, action domain.AutomationAction) error {
+	switch action.Type {
+	case domain.AutomationSetPriority:
+		updated, err := s.taskSvc.Update(ctx, task.ID, rule.UserID, &domain.UpdateTaskRequest{Priority: action.Priority})
+		if err == nil {
+			*task = *updated
+		}
+		return err
+	case domain.AutomationMoveProject:
+		updated, err := s.taskSvc.Update(ctx, task.ID, rule.UserID, &domain.UpdateTaskRequest{ProjectID: action.ProjectID})
+		if err == nil {
+			*task = *updated
+		}
+		return err
+	case domain.AutomationAddTag:
+		tags, err := s.tagSvc.ListForTask(ctx, task.ID, rule.UserID)
+		if err != nil {
+			return err
+		}
+		ids := []uuid.UUID{*action.TagID}
+		for _, t := range tags {
+			ids = append(ids, t.ID)
+		}
+		_, err = s.tagSvc.SetForTask(ctx, task.ID, rule.UserID, ids)
+		return err
+	case domain.AutomationNotify:
+		body := action.Message
+		if body == "" {
+			body = rule.Name
+		}
+		s.notifier.Notify(domain.NotificationEvent{
+			UserID:   rule.UserID,
+			Type:     domain.EventAutomationNotify,
+			Title:    task.Title,
+			Body:     body,
+			EntityID: &task.ID,
+			DedupKey: rule.ID.String() + ":" + task.ID.String(),
+		})
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q", action.Type)
+	}
+}
+
+// finishNew records a synchronous execution.
+func (s *AutomationService) finishNew(ctx context.Context, e *domain.AutomationExecution, applied int, err error) {
+	setOutcome(e, applied, err)
+	if err := s.ruleRepo.CreateExecution(ctx, e); err != nil {
+		s.log.WithError(err).WithField("rule_id", e.RuleID).Error("failed to record automation execution")
+	}
+}
+
+// finish completes a queued execution.
+func (s *AutomationService) finish(ctx context.Context, e *domain.AutomationExecution, applied int, err error) {
+	setOutcome(e, applied, err)
+	if err := s.ruleRepo.UpdateExecution(ctx, e); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		s.log.WithError(err).WithField("execution_id", e.ID).Error("failed to record automation execution")
+	}
+}
+
+func setOutcome(e *domain.AutomationExecution, applied int, err error) {
+	e.Applied = applied
+	e.Status = domain.AutomationExecSucceeded
+	if err != nil {
+		msg := err.Error()
+		e.Status, e.Error = domain.AutomationExecFailed, &msg
+	}
+}
+
+func setSkipped(e *domain.AutomationExecution, rule *domain.AutomationRule) {
+	msg := "circuit breaker open until " + rule.OpenUntil.UTC().Format(time.RFC3339)
+	e.Status, e.Error = domain.AutomationExecSkipped, &msg
+}
+
+// recordBreaker updates the rule's circuit breaker after a run and tells the
+// owner when it trips.
+func (s *AutomationService) recordBreaker(ctx context.Context, rule *domain.AutomationRule, runErr error) {
+	openUntil, err := s.breaker.record(ctx, s.ruleRepo, rule.ID, rule.CircuitBreaker, runErr)
+	if err != nil {
+		s.log.WithError(err).WithField("rule_id", rule.ID).Error("failed to update automation circuit breaker")
+		return
+	}
+	if openUntil == nil {
+		return
+	}
+	s.log.WithFields(logrus.Fields{"rule_id": rule.ID, "open_until": *openUntil}).Warn("automation circuit breaker opened")
+	s.notifier.Notify(domain.NotificationEvent{
+		UserID: rule.UserID,
+		Type:   domain.EventCircuitOpened,
+		Title:  fmt.Sprintf("Automation %q paused after %d failed runs", rule.Name, s.breaker.Threshold),
+		Body: fmt.Sprintf("The rule is skipped until %s. Check its execution log, then re-enable it.",
+			openUntil.UTC().Format(time.RFC1123)),
+		EntityID: &rule.ID,
+		DedupKey: "breaker:" + rule.ID.String(),
+	})
+}
+
+// validateRule checks what struct tags cannot: projects and tags named by
+// the rule must belong to its owner.
+func (s *AutomationService) validateRule(ctx context.Context, rule *domain.AutomationRule) error {
+	projectIDs := []*uuid.UUID{rule.Condition.ProjectID}
+	for _, a := range rule.Actions {
+		switch a.Type {
+		case domain.AutomationMoveProject:
+			projectIDs = append(projectIDs, a.ProjectID)
+		case domain.AutomationAddTag:
+			if _, err := s.tagSvc.GetByID(ctx, *a.TagID, rule.UserID); err != nil {
+				return fmt.Errorf("automationService: tag %s: %w", a.TagID, err)
+			}
+		}
+	}
+	for _, id := range projectIDs {
+		if id == nil {
+			continue
+		}
+		project, err := s.projectRepo.FindByID(ctx, *id)
+		if err != nil {
+			return fmt.Errorf("automationService: project %s: %w", id, err)
+		}
+		if project.UserID != rule.UserID {
+			return domain.ErrForbidden
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAutomationRepo struct {
+	domain.AutomationRuleRepository
+	rules []*domain.AutomationRule
+	execs []*domain.AutomationExecution
+}
+
+func (f *fakeAutomationRepo) ListEnabled(_ context.Context, userID uuid.UUID, trigger string) ([]*domain.AutomationRule, error) {
+	var out []*domain.AutomationRule
+	for _, r := range f.rules {
+		if r.UserID == userID && r.Trigger == trigger && r.Enabled {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAutomationRepo) ListUsersWithTrigger(_ context.Context, trigger string) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	for _, r := range f.rules {
+		if r.Trigger == trigger && r.Enabled {
+			out = append(out, r.UserID)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAutomationRepo) CreateExecution(_ context.Context, e *domain.AutomationExecution) error {
+	f.execs = append(f.execs, e)
+	return nil
+}
+
+func (f *fakeAutomationRepo) HasExecuted(_ context.Context, ruleID, taskID uuid.UUID, since time.Time) (bool, error) {
+	for _, e := range f.execs {
+		if e.RuleID == ruleID && e.TaskID == taskID && !e.CreatedAt.Before(since) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeAutomationRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.AutomationRule, error) {
+	for _, r := range f.rules {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeAutomationRepo) RecordFailure(_ context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error) {
+	r, err := f.FindByID(context.Background(), id)
+	if err != nil {
+		return 0, err
+	}
+	r.Failures++
+	if r.Failures >= threshold {
+		r.OpenUntil = &openUntil
+	}
+	return r.Failures, nil
+}
+
+func (f *fakeAutomationRepo) ResetBreaker(_ context.Context, id uuid.UUID) error {
+	r, err := f.FindByID(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	r.CircuitBreaker = domain.CircuitBreaker{}
+	return nil
+}
+
+type fakeNotifier struct{ events []domain.NotificationEvent }
+
+func (f *fakeNotifier) Notify(e domain.NotificationEvent) { f.events = append(f.events, e) }
+
+func newAutomationService(rules *fakeAutomationRepo, taskSvc *service.TaskService, notifier service.Notifier) *service.AutomationService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	tagSvc := service.NewTagService(&fakeTagRepo{}, taskSvc, log)
+	return service.NewAutomationService(rules, &mockProjectRepo{}, taskSvc, tagSvc, notifier, jobs.New(jobs.Config{}, log),
+		service.CircuitBreakerPolicy{Threshold: 2, Cooloff: time.Hour}, log)
+}
+
+func TestAutomationService_CompletedTriggerRunsActions(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	low := domain.TaskPriorityLow
+	rules := &fakeAutomationRepo{rules: []*domain.AutomationRule{
+		{
+			ID: uuid.New(), UserID: userID, Name: "archive chores", Enabled: true,
+			Trigger:   domain.AutomationTriggerCompleted,
+			Condition: domain.AutomationCondition{TitleContains: "chore"},
+			Actions: domain.AutomationActions{
+				{Type: domain.AutomationSetPriority, Priority: &low},
+				{Type: domain.AutomationNotify, Message: "chore done"},
+			},
+		},
+	}}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, Title: "Chore: dishes", Priority: domain.TaskPriorityHigh}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	taskSvc := newTaskService(taskRepo, &mockProjectRepo{})
+	notifier := &fakeNotifier{}
+	svc := newAutomationService(rules, taskSvc, notifier)
+	bus := eventbus.New(eventbus.Config{}, logrus.New())
+	taskSvc.UseEvents(bus)
+	eventbus.Subscribe(bus, "automation", svc.TaskChanged)
+
+	done := domain.TaskStatusDone
+	_, err := taskSvc.Update(context.Background(), taskID, userID, &domain.UpdateTaskRequest{Status: &done})
+	require.NoError(t, err)
+
+	require.Len(t, rules.execs, 1)
+	assert.Equal(t, domain.AutomationExecSucceeded, rules.execs[0].Status)
+	assert.Equal(t, 2, rules.execs[0].Applied)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "chore done", notifier.events[0].Body)
+
+	// The rule's own update must not fire rules again.
+	taskRepo.AssertNumberOfCalls(t, "Update", 2)
+	updated := taskRepo.Calls[len(taskRepo.Calls)-1].Arguments.Get(1).(*domain.Task)
+	assert.Equal(t, domain.TaskPriorityLow, updated.Priority)
+}
+
+func TestAutomationService_RunOverdueFiresOncePerDueDate(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	due := time.Now().Add(-time.Hour)
+	rule := &domain.AutomationRule{
+		ID: uuid.New(), UserID: userID, Name: "nag", Enabled: true,
+		Trigger: domain.AutomationTriggerOverdue,
+		Actions: domain.AutomationActions{{Type: domain.AutomationNotify}},
+	}
+	rules := &fakeAutomationRepo{rules: []*domain.AutomationRule{rule}}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindOverdue", mock.Anything, userID, mock.Anything, mock.Anything).Return([]*domain.Task{{ID: taskID, UserID: userID, Title: "Pay rent", DueDate: &due}}, nil)
+	notifier := &fakeNotifier{}
+	svc := newAutomationService(rules, newTaskService(taskRepo, &mockProjectRepo{}), notifier)
+	ctx := context.Background()
+
+	require.NoError(t, svc.RunOverdue(ctx))
+	require.NoError(t, svc.RunOverdue(ctx))
+
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, "nag", notifier.events[0].Body, "message defaults to the rule name")
+	assert.Len(t, rules.execs, 1)
+}
+
+func TestAutomationService_AsyncRuleIsQueued(t *testing.T) {
+	userID := uuid.New()
+	rules := &fakeAutomationRepo{rules: []*domain.AutomationRule{
+		{
+			ID: uuid.New(), UserID: userID, Enabled: true, Async: true,
+			Trigger: domain.AutomationTriggerMoved,
+			Actions: domain.AutomationActions{{Type: domain.AutomationNotify}},
+		},
+	}}
+	notifier := &fakeNotifier{}
+	svc := newAutomationService(rules, newTaskService(&mockTaskRepo{}, &mockProjectRepo{}), notifier)
+
+	svc.TaskChanged(context.Background(), domain.TaskMoved{Task: &domain.Task{ID: uuid.New(), UserID: userID}})
+
+	require.Len(t, rules.execs, 1)
+	assert.Equal(t, domain.AutomationExecQueued, rules.execs[0].Status)
+	assert.Empty(t, notifier.events)
+}
+
+func TestAutomationService_BreakerPausesFailingRule(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	high := domain.TaskPriorityHigh
+	rule := &domain.AutomationRule{
+		ID: uuid.New(), UserID: userID, Name: "escalate", Enabled: true,
+		Trigger: domain.AutomationTriggerMoved,
+		Actions: domain.AutomationActions{{Type: domain.AutomationSetPriority, Priority: &high}},
+	}
+	rules := &fakeAutomationRepo{rules: []*domain.AutomationRule{rule}}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(nil, domain.ErrNotFound)
+	notifier := &fakeNotifier{}
+	svc := newAutomationService(rules, newTaskService(taskRepo, &mockProjectRepo{}), notifier)
+	ctx := context.Background()
+	task := &domain.Task{ID: taskID, UserID: userID}
+
+	for i := 0; i < 3; i++ {
+		svc.TaskChanged(ctx, domain.TaskMoved{Task: task})
+	}
+
+	require.Len(t, rules.execs, 3)
+	assert.Equal(t, domain.AutomationExecFailed, rules.execs[1].Status)
+	assert.Equal(t, domain.AutomationExecSkipped, rules.execs[2].Status, "open breaker skips the rule")
+	taskRepo.AssertNumberOfCalls(t, "FindByID", 2)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, domain.EventCircuitOpened, notifier.events[0].Type)
+
+	enabled, err := svc.Enable(ctx, rule.ID, userID)
+	require.NoError(t, err)
+	assert.Zero(t, enabled.Failures)
+	assert.Nil(t, rule.OpenUntil)
+}
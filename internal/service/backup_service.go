@@ -0,0 +1,405 @@
+package service
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	backupFormat  = "todoapp-backup"
+	backupVersion = 1
+	// backupStaleAfter is how long an unfinished or failed backup is kept
+	// before pruning removes it.
+	backupStaleAfter = 24 * time.Hour
+)
+
+// backupHeader is the first line of a backup file.
+type backupHeader struct {
+	Format    string              `json:"format"`
+	Version   int                 `json:"version"`
+	CreatedAt time.Time           `json:"created_at"`
+	Tables    []backupTableHeader `json:"tables"`
+}
+
+type backupTableHeader struct {
+	Name    string   `json:"table"`
+	Columns []string `json:"columns"`
+	Rows    int      `json:"rows"`
+}
+
+// backupLine is every later line: one row of one table.
+type backupLine struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// BackupService takes logical backups of the whole database into object
+// storage and restores them. A backup is a gzipped file of JSON lines: a
+// header naming every table and its columns, then one line per row.
+type BackupService struct {
+	repo  domain.BackupRepository
+	store storage.Storage
+	keep  int
+	log   *logrus.Logger
+
+	reloaders []func(ctx context.Context) error
+	// running is held while a backup or restore runs; they never overlap.
+	running sync.Mutex
+}
+
+// NewBackupService constructs a BackupService keeping the newest keep
+// scheduled backups. A nil store disables backups.
+func NewBackupService(repo domain.BackupRepository, store storage.Storage, keep int, log *logrus.Logger) *BackupService {
+	return &BackupService{repo: repo, store: store, keep: keep, log: log}
+}
+
+// UseReloaders registers functions that reread state cached in memory, such
+// as the instance settings, after a restore. Must be called before serving
+// requests.
+func (s *BackupService) UseReloaders(fns ...func(ctx context.Context) error) {
+	s.reloaders = append(s.reloaders, fns...)
+}
+
+// Enabled reports whether object storage is configured for backups.
+func (s *BackupService) Enabled() bool {
+	return s.store != nil
+}
+
+// Create takes a manual backup now.
+func (s *BackupService) Create(ctx context.Context, userID uuid.UUID) (*domain.Backup, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("backupService.Create: %w", domain.ErrFeatureDisabled)
+	}
+	if !s.running.TryLock() {
+		return nil, fmt.Errorf("backupService.Create: a backup or restore is running: %w", domain.ErrUnavailable)
+	}
+	defer s.running.Unlock()
+
+	b, err := s.backup(ctx, domain.BackupManual, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Create: %w", err)
+	}
+	return b, nil
+}
+
+// List returns every backup, newest first.
+func (s *BackupService) List(ctx context.Context) ([]*domain.Backup, error) {
+	backups, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.List: %w", err)
+	}
+	return backups, nil
+}
+
+// Get returns one backup.
+func (s *BackupService) Get(ctx context.Context, id uuid.UUID) (*domain.Backup, error) {
+	b, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Get: %w", err)
+	}
+	return b, nil
+}
+
+// Delete removes a backup and its file.
+func (s *BackupService) Delete(ctx context.Context, id uuid.UUID) error {
+	b, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("backupService.Delete: %w", err)
+	}
+	if err := s.remove(ctx, b); err != nil {
+		return fmt.Errorf("backupService.Delete: %w", err)
+	}
+	return nil
+}
+
+// Restore replaces every table with the contents of a backup. confirm must
+// repeat the backup's id. Before anything changes it checks the file
+// against its checksum and the schema it was taken on against the current
+// one, then backs the current data up, so a restore can itself be undone.
+// A dry run stops after the checks and reports what would be restored.
+func (s *BackupService) Restore(ctx context.Context, id, userID uuid.UUID, confirm string, dryRun bool) (*domain.BackupRestoreResult, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", domain.ErrFeatureDisabled)
+	}
+	if !dryRun && confirm != id.String() {
+		return nil, fmt.Errorf("backupService.Restore: confirm must repeat the backup id: %w", domain.ErrValidation)
+	}
+	b, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	if b.Status != domain.BackupSucceeded {
+		return nil, fmt.Errorf("backupService.Restore: backup is %s: %w", b.Status, domain.ErrValidation)
+	}
+	if !s.running.TryLock() {
+		return nil, fmt.Errorf("backupService.Restore: a backup or restore is running: %w", domain.ErrUnavailable)
+	}
+	defer s.running.Unlock()
+
+	body, err := s.store.Get(ctx, b.StorageKey)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	if sum := sha256.Sum256(body); hex.EncodeToString(sum[:]) != b.Checksum {
+		return nil, fmt.Errorf("backupService.Restore: checksum differs: %w", domain.ErrBackupMismatch)
+	}
+	tables, err := decodeBackup(body)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	current, err := s.repo.Columns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	if differ := schemaDiff(tables, current); len(differ) > 0 {
+		return nil, fmt.Errorf("backupService.Restore: tables %s differ: %w", strings.Join(differ, ", "), domain.ErrBackupMismatch)
+	}
+
+	result := &domain.BackupRestoreResult{BackupID: id, DryRun: dryRun, Tables: make(map[string]int, len(tables))}
+	for _, t := range tables {
+		result.Tables[t.Name] = len(t.Rows)
+		result.Total += len(t.Rows)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	safety, err := s.backup(ctx, domain.BackupPreRestore, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("backupService.Restore: backing up current data: %w", err)
+	}
+	result.SafetyBackupID = &safety.ID
+	if err := s.repo.Restore(ctx, tables); err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	if err := s.repo.MarkRestored(ctx, id, time.Now()); err != nil {
+		return nil, fmt.Errorf("backupService.Restore: %w", err)
+	}
+	for _, reload := range s.reloaders {
+		if err := reload(ctx); err != nil {
+			s.log.WithError(err).Error("failed to reload state after restore")
+		}
+	}
+	s.log.WithFields(logrus.Fields{"backup_id": id, "rows": result.Total, "safety_backup_id": safety.ID}).Warn("admin restored backup")
+	return result, nil
+}
+
+// Run takes a scheduled backup and prunes the old ones. It runs on a
+// schedule when storage is configured.
+func (s *BackupService) Run(ctx context.Context) error {
+	if s.store == nil {
+		return nil
+	}
+	if !s.running.TryLock() {
+		s.log.Info("skipping scheduled backup: a backup or restore is running")
+		return nil
+	}
+	b, err := s.backup(ctx, domain.BackupScheduled, nil)
+	s.running.Unlock()
+	if err != nil {
+		return fmt.Errorf("backupService.Run: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{"backup_id": b.ID, "rows": b.RowCount, "bytes": b.SizeBytes}).Info("took scheduled backup")
+	return s.Prune(ctx)
+}
+
+// Prune removes the scheduled backups beyond the newest keep, and failed or
+// abandoned ones after a day. Manual and pre-restore backups stay until an
+// admin deletes them.
+func (s *BackupService) Prune(ctx context.Context) error {
+	backups, err := s.repo.ListPrunable(ctx, s.keep, time.Now().Add(-backupStaleAfter))
+	if err != nil {
+		return fmt.Errorf("backupService.Prune: %w", err)
+	}
+	for _, b := range backups {
+		if err := s.remove(ctx, b); err != nil {
+			return fmt.Errorf("backupService.Prune: %w", err)
+		}
+	}
+	if len(backups) > 0 {
+		s.log.WithField("backups", len(backups)).Info("pruned backups")
+	}
+	return nil
+}
+
+// RunBackup is the OperationRunner for OperationBackup.
+func (s *BackupService) RunBackup(ctx context.Context, userID uuid.UUID, _ json.RawMessage, _ func(int)) (*domain.OperationOutput, error) {
+	b, err := s.Create(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OperationOutput{Result: b}, nil
+}
+
+// backup dumps the database into a new backup of kind. The caller holds
+// running.
+func (s *BackupService) backup(ctx context.Context, kind string, userID *uuid.UUID) (*domain.Backup, error) {
+	now := time.Now()
+	id := uuid.New()
+	b := &domain.Backup{
+		ID:         id,
+		Kind:       kind,
+		Status:     domain.BackupRunning,
+		StorageKey: fmt.Sprintf("backups/%s-%s.ndjson.gz", now.UTC().Format("20060102T150405Z"), id),
+		CreatedBy:  userID,
+		CreatedAt:  now,
+	}
+	if err := s.repo.Create(ctx, b); err != nil {
+		return nil, err
+	}
+
+	body, rows, err := s.dump(ctx, now)
+	if err == nil {
+		err = s.store.Put(ctx, b.StorageKey, "application/gzip", body)
+	}
+	finished := time.Now()
+	b.FinishedAt = &finished
+	if err != nil {
+		msg := err.Error()
+		b.Status, b.Error = domain.BackupFailed, &msg
+		// The failure is recorded with a fresh context: ctx may be why it
+		// failed.
+		if ferr := s.repo.Finish(context.WithoutCancel(ctx), b); ferr != nil {
+			s.log.WithError(ferr).WithField("backup_id", id).Error("failed to record failed backup")
+		}
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	b.Status = domain.BackupSucceeded
+	b.SizeBytes = int64(len(body))
+	b.Checksum = hex.EncodeToString(sum[:])
+	b.RowCount = int64(rows)
+	if err := s.repo.Finish(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// dump reads the database and encodes it, returning the file and the number
+// of rows in it.
+func (s *BackupService) dump(ctx context.Context, now time.Time) ([]byte, int, error) {
+	tables, err := s.repo.Dump(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	header := backupHeader{Format: backupFormat, Version: backupVersion, CreatedAt: now.UTC()}
+	rows := 0
+	for _, t := range tables {
+		header.Tables = append(header.Tables, backupTableHeader{Name: t.Name, Columns: t.Columns, Rows: len(t.Rows)})
+		rows += len(t.Rows)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(header); err != nil {
+		return nil, 0, err
+	}
+	for _, t := range tables {
+		for _, row := range t.Rows {
+			if err := enc.Encode(backupLine{Table: t.Name, Row: row}); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, 0, err
+	}
+	return buf.Bytes(), rows, nil
+}
+
+// decodeBackup reads a backup file back into its tables, in the order the
+// header lists them.
+func decodeBackup(body []byte) ([]domain.BackupTable, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("reading backup: %v: %w", err, domain.ErrBackupMismatch)
+	}
+	dec := json.NewDecoder(zr)
+
+	var header backupHeader
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("reading backup header: %v: %w", err, domain.ErrBackupMismatch)
+	}
+	if header.Format != backupFormat || header.Version != backupVersion {
+		return nil, fmt.Errorf("backup format %s v%d is not supported: %w", header.Format, header.Version, domain.ErrBackupMismatch)
+	}
+
+	tables := make([]domain.BackupTable, len(header.Tables))
+	index := make(map[string]int, len(header.Tables))
+	for i, t := range header.Tables {
+		tables[i] = domain.BackupTable{Name: t.Name, Columns: t.Columns, Rows: make([]json.RawMessage, 0, t.Rows)}
+		index[t.Name] = i
+	}
+	for {
+		var line backupLine
+		if err := dec.Decode(&line); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("reading backup: %v: %w", err, domain.ErrBackupMismatch)
+		}
+		i, ok := index[line.Table]
+		if !ok {
+			return nil, fmt.Errorf("backup row for undeclared table %q: %w", line.Table, domain.ErrBackupMismatch)
+		}
+		tables[i].Rows = append(tables[i].Rows, line.Row)
+	}
+	for i, t := range header.Tables {
+		if len(tables[i].Rows) != t.Rows {
+			return nil, fmt.Errorf("backup holds %d of %d %s rows: %w", len(tables[i].Rows), t.Rows, t.Name, domain.ErrBackupMismatch)
+		}
+	}
+	return tables, nil
+}
+
+// schemaDiff returns the tables whose columns differ between a backup and
+// the current schema, including tables only one of them has.
+func schemaDiff(tables []domain.BackupTable, current map[string][]string) []string {
+	var differ []string
+	seen := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		seen[t.Name] = true
+		want, got := slices.Clone(t.Columns), slices.Clone(current[t.Name])
+		sort.Strings(want)
+		sort.Strings(got)
+		if !slices.Equal(want, got) {
+			differ = append(differ, t.Name)
+		}
+	}
+	for name := range current {
+		if !seen[name] {
+			differ = append(differ, name)
+		}
+	}
+	sort.Strings(differ)
+	return differ
+}
+
+// remove deletes a backup's file, then its row.
+func (s *BackupService) remove(ctx context.Context, b *domain.Backup) error {
+	if s.store != nil {
+		if err := s.store.Delete(ctx, b.StorageKey); err != nil && !errors.Is(err, storage.ErrObjectNotFound) {
+			return err
+		}
+	}
+	return s.repo.Delete(ctx, b.ID)
+}
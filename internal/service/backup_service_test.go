@@ -0,0 +1,225 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBackupRepo holds backups in memory and dumps a fixed set of tables.
+type fakeBackupRepo struct {
+	domain.BackupRepository
+	backups  map[uuid.UUID]*domain.Backup
+	tables   []domain.BackupTable
+	columns  map[string][]string
+	restored []domain.BackupTable
+}
+
+func newFakeBackupRepo() *fakeBackupRepo {
+	tables := []domain.BackupTable{
+		{Name: "users", Columns: []string{"id", "email"}, Rows: []json.RawMessage{
+			json.RawMessage(`{"id":"u1","email":"a@example.com"}`),
+			json.RawMessage(`{"id":"u2","email":"b@example.com"}`),
+		}},
+		{Name: "tasks", Columns: []string{"id", "user_id", "title"}, Rows: []json.RawMessage{
+			json.RawMessage(`{"id":"t1","user_id":"u1","title":"Write the report"}`),
+		}},
+	}
+	return &fakeBackupRepo{
+		backups: map[uuid.UUID]*domain.Backup{},
+		tables:  tables,
+		columns: map[string][]string{"users": {"email", "id"}, "tasks": {"id", "title", "user_id"}},
+	}
+}
+
+func (r *fakeBackupRepo) Create(ctx context.Context, b *domain.Backup) error {
+	saved := *b
+	r.backups[b.ID] = &saved
+	return nil
+}
+
+func (r *fakeBackupRepo) Finish(ctx context.Context, b *domain.Backup) error {
+	saved := *b
+	r.backups[b.ID] = &saved
+	return nil
+}
+
+func (r *fakeBackupRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Backup, error) {
+	b, ok := r.backups[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	found := *b
+	return &found, nil
+}
+
+func (r *fakeBackupRepo) ListPrunable(ctx context.Context, keep int, cutoff time.Time) ([]*domain.Backup, error) {
+	var scheduled []*domain.Backup
+	for _, b := range r.backups {
+		if b.Kind == domain.BackupScheduled && b.Status == domain.BackupSucceeded {
+			scheduled = append(scheduled, b)
+		}
+	}
+	sort.Slice(scheduled, func(i, j int) bool { return scheduled[i].CreatedAt.After(scheduled[j].CreatedAt) })
+	if len(scheduled) <= keep {
+		return nil, nil
+	}
+	return scheduled[keep:], nil
+}
+
+func (r *fakeBackupRepo) MarkRestored(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.backups[id].RestoredAt = &at
+	return nil
+}
+
+func (r *fakeBackupRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.backups, id)
+	return nil
+}
+
+func (r *fakeBackupRepo) Columns(ctx context.Context) (map[string][]string, error) {
+	return r.columns, nil
+}
+
+func (r *fakeBackupRepo) Dump(ctx context.Context) ([]domain.BackupTable, error) {
+	return r.tables, nil
+}
+
+func (r *fakeBackupRepo) Restore(ctx context.Context, tables []domain.BackupTable) error {
+	r.restored = tables
+	return nil
+}
+
+func (r *fakeBackupRepo) count(kind string) int {
+	n := 0
+	for _, b := range r.backups {
+		if b.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// memStore holds object bodies by key.
+type memStore struct {
+	storage.Storage
+	objects map[string][]byte
+}
+
+func (m *memStore) Put(_ context.Context, key, contentType string, body []byte) error {
+	m.objects[key] = body
+	return nil
+}
+
+func (m *memStore) Get(_ context.Context, key string) ([]byte, error) {
+	body, ok := m.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return body, nil
+}
+
+func (m *memStore) Delete(_ context.Context, key string) error {
+	delete(m.objects, key)
+	return nil
+}
+
+func TestBackupService_DisabledWithoutStorage(t *testing.T) {
+	svc := service.NewBackupService(newFakeBackupRepo(), nil, 7, logrus.New())
+	assert.False(t, svc.Enabled())
+
+	_, err := svc.Create(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, domain.ErrFeatureDisabled)
+	assert.NoError(t, svc.Run(context.Background()))
+}
+
+func TestBackupService_CreateAndRestore(t *testing.T) {
+	repo := newFakeBackupRepo()
+	store := &memStore{objects: map[string][]byte{}}
+	svc := service.NewBackupService(repo, store, 7, logrus.New())
+	reloaded := 0
+	svc.UseReloaders(func(ctx context.Context) error { reloaded++; return nil })
+	ctx := context.Background()
+	adminID := uuid.New()
+
+	backup, err := svc.Create(ctx, adminID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.BackupSucceeded, backup.Status)
+	assert.Equal(t, domain.BackupManual, backup.Kind)
+	assert.EqualValues(t, 3, backup.RowCount)
+	assert.Len(t, backup.Checksum, 64)
+	require.Contains(t, store.objects, backup.StorageKey)
+	assert.EqualValues(t, len(store.objects[backup.StorageKey]), backup.SizeBytes)
+
+	_, err = svc.Restore(ctx, backup.ID, adminID, "", false)
+	assert.ErrorIs(t, err, domain.ErrValidation, "a restore must be confirmed")
+
+	preview, err := svc.Restore(ctx, backup.ID, adminID, "", true)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"users": 2, "tasks": 1}, preview.Tables)
+	assert.Equal(t, 3, preview.Total)
+	assert.Nil(t, repo.restored, "a dry run restores nothing")
+
+	result, err := svc.Restore(ctx, backup.ID, adminID, backup.ID.String(), false)
+	require.NoError(t, err)
+	require.NotNil(t, result.SafetyBackupID)
+	assert.Equal(t, domain.BackupPreRestore, repo.backups[*result.SafetyBackupID].Kind)
+	require.Len(t, repo.restored, 2)
+	for i, table := range repo.tables {
+		assert.Equal(t, table.Name, repo.restored[i].Name)
+		assert.Equal(t, table.Columns, repo.restored[i].Columns)
+		require.Len(t, repo.restored[i].Rows, len(table.Rows))
+		for j, row := range table.Rows {
+			assert.JSONEq(t, string(row), string(repo.restored[i].Rows[j]))
+		}
+	}
+	assert.NotNil(t, repo.backups[backup.ID].RestoredAt)
+	assert.Equal(t, 1, reloaded)
+}
+
+func TestBackupService_RestoreRefusesMismatches(t *testing.T) {
+	repo := newFakeBackupRepo()
+	store := &memStore{objects: map[string][]byte{}}
+	svc := service.NewBackupService(repo, store, 7, logrus.New())
+	ctx := context.Background()
+
+	backup, err := svc.Create(ctx, uuid.New())
+	require.NoError(t, err)
+
+	repo.columns["tasks"] = append(repo.columns["tasks"], "priority")
+	_, err = svc.Restore(ctx, backup.ID, uuid.New(), backup.ID.String(), false)
+	assert.ErrorIs(t, err, domain.ErrBackupMismatch, "a backup of another schema is refused")
+
+	store.objects[backup.StorageKey] = append(store.objects[backup.StorageKey], 0)
+	_, err = svc.Restore(ctx, backup.ID, uuid.New(), backup.ID.String(), true)
+	assert.ErrorIs(t, err, domain.ErrBackupMismatch, "a changed file is refused")
+	assert.Nil(t, repo.restored)
+	assert.Zero(t, repo.count(domain.BackupPreRestore))
+}
+
+func TestBackupService_RunPrunesScheduledBackups(t *testing.T) {
+	repo := newFakeBackupRepo()
+	store := &memStore{objects: map[string][]byte{}}
+	svc := service.NewBackupService(repo, store, 2, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.Create(ctx, uuid.New())
+	require.NoError(t, err)
+	for i := 0; i < 4; i++ {
+		require.NoError(t, svc.Run(ctx))
+	}
+
+	assert.Equal(t, 2, repo.count(domain.BackupScheduled))
+	assert.Equal(t, 1, repo.count(domain.BackupManual), "manual backups are not pruned")
+	assert.Len(t, store.objects, 3)
+}
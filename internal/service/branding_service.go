@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultDKIMSelector is the selector assumed when a custom email domain is
+// set without one.
+const DefaultDKIMSelector = "todoapp"
+
+// TXTResolver looks up DNS TXT records; *net.Resolver satisfies it.
+type TXTResolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// BrandingService keeps the workspace branding: stored overrides on top of
+// the deployment's configured defaults, applied to outgoing emails as soon
+// as they change.
+type BrandingService struct {
+	repo     domain.BrandingRepository
+	renderer *email.Renderer
+	defaults email.Branding
+	mailFrom string
+	resolver TXTResolver
+	log      *logrus.Logger
+
+	mu     sync.RWMutex
+	stored domain.WorkspaceBranding
+}
+
+// NewBrandingService constructs a BrandingService. The renderer's current
+// branding and mailFrom, the configured sender address, are the defaults
+// every empty field falls back to.
+func NewBrandingService(repo domain.BrandingRepository, renderer *email.Renderer, mailFrom string, resolver TXTResolver, log *logrus.Logger) *BrandingService {
+	return &BrandingService{
+		repo:     repo,
+		renderer: renderer,
+		defaults: renderer.Branding(),
+		mailFrom: mailFrom,
+		resolver: resolver,
+		log:      log,
+		stored:   domain.WorkspaceBranding{DKIMStatus: domain.DKIMUnconfigured},
+	}
+}
+
+// Load reads the stored branding and applies it. Call it at startup and
+// whenever the stored branding is replaced underneath, as by a restore.
+func (s *BrandingService) Load(ctx context.Context) error {
+	b, err := s.repo.Get(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		s.apply(domain.WorkspaceBranding{DKIMStatus: domain.DKIMUnconfigured})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("brandingService.Load: %w", err)
+	}
+	s.apply(*b)
+	return nil
+}
+
+// Get returns the branding in effect, defaults filled in.
+func (s *BrandingService) Get() *domain.WorkspaceBranding {
+	s.mu.RLock()
+	b := s.stored
+	s.mu.RUnlock()
+
+	if b.Name == "" {
+		b.Name = s.defaults.ProductName
+	}
+	if b.LogoURL == "" {
+		b.LogoURL = s.defaults.LogoURL
+	}
+	if b.AccentColor == "" {
+		b.AccentColor = s.defaults.AccentColor
+	}
+	if b.EmailFromDomain != "" {
+		b.DKIMRecord = b.DKIMSelector + "._domainkey." + b.EmailFromDomain
+	}
+	return &b
+}
+
+// Update changes the stored branding. Setting a different email domain or
+// selector starts DKIM verification over; clearing the domain turns it off.
+func (s *BrandingService) Update(ctx context.Context, req *domain.UpdateWorkspaceBrandingRequest) (*domain.WorkspaceBranding, error) {
+	s.mu.RLock()
+	b := s.stored
+	s.mu.RUnlock()
+
+	oldDomain, oldSelector := b.EmailFromDomain, b.DKIMSelector
+	if req.Name != nil {
+		b.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.LogoURL != nil {
+		b.LogoURL = *req.LogoURL
+	}
+	if req.AccentColor != nil {
+		b.AccentColor = *req.AccentColor
+	}
+	if req.EmailFromDomain != nil {
+		b.EmailFromDomain = strings.ToLower(strings.TrimSuffix(*req.EmailFromDomain, "."))
+	}
+	if req.DKIMSelector != nil {
+		b.DKIMSelector = strings.ToLower(*req.DKIMSelector)
+	}
+	switch {
+	case b.EmailFromDomain == "":
+		b.DKIMSelector = ""
+		b.DKIMStatus = domain.DKIMUnconfigured
+		b.DKIMCheckedAt = nil
+	case b.DKIMSelector == "":
+		b.DKIMSelector = DefaultDKIMSelector
+		fallthrough
+	default:
+		if b.EmailFromDomain != oldDomain || b.DKIMSelector != oldSelector {
+			b.DKIMStatus = domain.DKIMPending
+			b.DKIMCheckedAt = nil
+		}
+	}
+	b.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, &b); err != nil {
+		return nil, fmt.Errorf("brandingService.Update: %w", err)
+	}
+	s.apply(b)
+	return s.Get(), nil
+}
+
+// VerifyDKIM looks up the selector's public key in DNS and records whether
+// it is published. Mail is only sent from the custom domain once it is.
+func (s *BrandingService) VerifyDKIM(ctx context.Context) (*domain.WorkspaceBranding, error) {
+	s.mu.RLock()
+	b := s.stored
+	s.mu.RUnlock()
+	if b.EmailFromDomain == "" {
+		return nil, fmt.Errorf("brandingService.VerifyDKIM: no custom email domain is set: %w", domain.ErrValidation)
+	}
+
+	records, err := s.resolver.LookupTXT(ctx, b.DKIMSelector+"._domainkey."+b.EmailFromDomain)
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound:
+		b.DKIMStatus = domain.DKIMPending
+	case err != nil:
+		return nil, fmt.Errorf("brandingService.VerifyDKIM: %v: %w", err, domain.ErrUnavailable)
+	case hasDKIMKey(records):
+		b.DKIMStatus = domain.DKIMVerified
+	default:
+		b.DKIMStatus = domain.DKIMFailed
+	}
+	now := time.Now()
+	b.DKIMCheckedAt = &now
+
+	if err := s.repo.Save(ctx, &b); err != nil {
+		return nil, fmt.Errorf("brandingService.VerifyDKIM: %w", err)
+	}
+	s.apply(b)
+	return s.Get(), nil
+}
+
+// hasDKIMKey reports whether one of the TXT records is a DKIM key record
+// with a public key; revoked keys have an empty p= tag.
+func hasDKIMKey(records []string) bool {
+	for _, r := range records {
+		for _, tag := range strings.Split(r, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(tag), "=")
+			if ok && strings.TrimSpace(name) == "p" && strings.TrimSpace(value) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProductName is the workspace name, for callers that may run without
+// branding configured.
+func (s *BrandingService) ProductName() string {
+	if s == nil {
+		return ""
+	}
+	return s.Get().Name
+}
+
+// Sender returns the From address and name outgoing mail should use, or
+// empty strings to keep the configured sender: the custom domain is used
+// only once its DKIM key is verified.
+func (s *BrandingService) Sender() (from, name string) {
+	if s == nil {
+		return "", ""
+	}
+	b := s.Get()
+	if b.Name != s.defaults.ProductName {
+		name = b.Name
+	}
+	if b.DKIMStatus == domain.DKIMVerified {
+		local, _, _ := strings.Cut(s.mailFrom, "@")
+		from = local + "@" + b.EmailFromDomain
+	}
+	return from, name
+}
+
+func (s *BrandingService) apply(b domain.WorkspaceBranding) {
+	s.mu.Lock()
+	s.stored = b
+	s.mu.Unlock()
+
+	eff := s.Get()
+	branding := s.defaults
+	branding.ProductName = eff.Name
+	branding.LogoURL = eff.LogoURL
+	branding.AccentColor = eff.AccentColor
+	s.renderer.SetBranding(branding)
+}
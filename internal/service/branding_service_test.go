@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBrandingRepo struct {
+	domain.BrandingRepository
+	saved *domain.WorkspaceBranding
+}
+
+func (r *fakeBrandingRepo) Get(ctx context.Context) (*domain.WorkspaceBranding, error) {
+	if r.saved == nil {
+		return nil, domain.ErrNotFound
+	}
+	b := *r.saved
+	return &b, nil
+}
+
+func (r *fakeBrandingRepo) Save(ctx context.Context, b *domain.WorkspaceBranding) error {
+	saved := *b
+	r.saved = &saved
+	return nil
+}
+
+type fakeResolver map[string][]string
+
+func (f fakeResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, ok := f[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func TestBrandingService(t *testing.T) {
+	renderer, err := email.NewRenderer(email.Branding{ProductName: "Todo App", AccentColor: "#F59E0B"})
+	require.NoError(t, err)
+	resolver := fakeResolver{}
+	repo := &fakeBrandingRepo{}
+	svc := service.NewBrandingService(repo, renderer, "no-reply@localhost", resolver, logrus.New())
+	ctx := context.Background()
+	str := func(s string) *string { return &s }
+
+	require.NoError(t, svc.Load(ctx))
+	got := svc.Get()
+	assert.Equal(t, "Todo App", got.Name)
+	assert.Equal(t, domain.DKIMUnconfigured, got.DKIMStatus)
+	from, name := svc.Sender()
+	assert.Empty(t, from)
+	assert.Empty(t, name)
+
+	got, err = svc.Update(ctx, &domain.UpdateWorkspaceBrandingRequest{
+		Name:            str("Acme"),
+		AccentColor:     str("#123456"),
+		EmailFromDomain: str("Mail.Acme.test."),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", got.Name)
+	assert.Equal(t, "mail.acme.test", got.EmailFromDomain)
+	assert.Equal(t, domain.DKIMPending, got.DKIMStatus)
+	assert.Equal(t, "todoapp._domainkey.mail.acme.test", got.DKIMRecord)
+	assert.Equal(t, "Acme", renderer.Branding().ProductName)
+	assert.Equal(t, "#123456", renderer.Branding().AccentColor)
+
+	from, name = svc.Sender()
+	assert.Empty(t, from, "an unverified domain is not used")
+	assert.Equal(t, "Acme", name)
+
+	got, err = svc.VerifyDKIM(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DKIMPending, got.DKIMStatus)
+	assert.NotNil(t, got.DKIMCheckedAt)
+
+	resolver["todoapp._domainkey.mail.acme.test"] = []string{"v=DKIM1; k=rsa; p="}
+	got, err = svc.VerifyDKIM(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DKIMFailed, got.DKIMStatus, "a revoked key does not verify")
+
+	resolver["todoapp._domainkey.mail.acme.test"] = []string{"v=DKIM1; k=rsa; p=MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQ"}
+	got, err = svc.VerifyDKIM(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, domain.DKIMVerified, got.DKIMStatus)
+	from, _ = svc.Sender()
+	assert.Equal(t, "no-reply@mail.acme.test", from)
+
+	// A fresh instance picks the stored branding up.
+	other := service.NewBrandingService(repo, renderer, "no-reply@localhost", resolver, logrus.New())
+	require.NoError(t, other.Load(ctx))
+	assert.Equal(t, domain.DKIMVerified, other.Get().DKIMStatus)
+
+	got, err = svc.Update(ctx, &domain.UpdateWorkspaceBrandingRequest{Name: str(""), EmailFromDomain: str("")})
+	require.NoError(t, err)
+	assert.Equal(t, "Todo App", got.Name, "an empty name restores the default")
+	assert.Equal(t, domain.DKIMUnconfigured, got.DKIMStatus)
+	assert.Empty(t, got.DKIMSelector)
+
+	_, err = svc.VerifyDKIM(ctx)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
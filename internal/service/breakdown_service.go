@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/llm"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Breakdown limits.
+const (
+	DefaultBreakdownSubtasks = 5
+	maxBreakdownSubtasks     = 10
+)
+
+const breakdownSystemPrompt = `You help people split a task into concrete subtasks.
+Reply with JSON only, in the form {"subtasks":[{"title":"...","description":"...","estimated_hours":1.5}]}.
+Titles are short imperative phrases. Do not repeat the parent task as a subtask.`
+
+// BreakdownService proposes subtasks for a task using an LLM and creates the
+// ones the user accepts. It is disabled when no provider is configured.
+type BreakdownService struct {
+	taskSvc  *TaskService
+	provider llm.Provider
+	log      *logrus.Logger
+}
+
+// NewBreakdownService constructs a BreakdownService. provider may be nil.
+func NewBreakdownService(taskSvc *TaskService, provider llm.Provider, log *logrus.Logger) *BreakdownService {
+	return &BreakdownService{taskSvc: taskSvc, provider: provider, log: log}
+}
+
+// Propose asks the provider for a breakdown of the task into at most limit
+// subtasks. Nothing is stored.
+func (s *BreakdownService) Propose(ctx context.Context, userID, taskID uuid.UUID, limit int) (*domain.TaskBreakdown, error) {
+	if s.provider == nil {
+		return nil, fmt.Errorf("breakdownService.Propose: no LLM provider configured: %w", domain.ErrFeatureDisabled)
+	}
+	task, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = DefaultBreakdownSubtasks
+	}
+	if limit > maxBreakdownSubtasks {
+		limit = maxBreakdownSubtasks
+	}
+
+	reply, err := s.provider.Complete(ctx, breakdownSystemPrompt, breakdownPrompt(task, limit))
+	if err != nil {
+		return nil, fmt.Errorf("breakdownService.Propose: %w", err)
+	}
+	subtasks, err := parseBreakdown(reply, limit)
+	if err != nil {
+		s.log.WithError(err).WithField("task_id", taskID).Warn("unusable breakdown reply")
+		return nil, fmt.Errorf("breakdownService.Propose: %w", err)
+	}
+
+	return &domain.TaskBreakdown{
+		TaskID:              task.ID,
+		Provider:            s.provider.Name(),
+		Subtasks:            subtasks,
+		TotalEstimatedHours: splitEffort(subtasks, task.EstimatedHours),
+	}, nil
+}
+
+// Accept creates the given suggestions as subtasks of the task, inheriting
+// its priority and project.
+func (s *BreakdownService) Accept(ctx context.Context, userID, taskID uuid.UUID, req *domain.AcceptBreakdownRequest) ([]*domain.Task, error) {
+	parent, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]*domain.Task, 0, len(req.Subtasks))
+	for _, sg := range req.Subtasks {
+		task, err := s.taskSvc.Create(ctx, userID, &domain.CreateTaskRequest{
+			ProjectID:      parent.ProjectID,
+			ParentID:       &parent.ID,
+			Title:          sg.Title,
+			Description:    sg.Description,
+			Priority:       parent.Priority,
+			EstimatedHours: sg.EstimatedHours,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("breakdownService.Accept: %w", err)
+		}
+		created = append(created, task)
+	}
+	return created, nil
+}
+
+func breakdownPrompt(task *domain.Task, limit int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Split this task into at most %d subtasks.\n\nTitle: %s\n", limit, task.Title)
+	if task.Description != "" {
+		fmt.Fprintf(&b, "Description: %s\n", task.Description)
+	}
+	if task.EstimatedHours != nil {
+		fmt.Fprintf(&b, "Total estimate: %g hours; split it across the subtasks.\n", *task.EstimatedHours)
+	}
+	if task.DueDate != nil {
+		fmt.Fprintf(&b, "Due: %s\n", task.DueDate.Format("2006-01-02"))
+	}
+	return b.String()
+}
+
+// parseBreakdown extracts suggestions from a model reply, tolerating prose or
+// code fences around the JSON and dropping entries without a title.
+func parseBreakdown(reply string, limit int) ([]domain.SubtaskSuggestion, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("breakdown reply contains no JSON object")
+	}
+	var out struct {
+		Subtasks []domain.SubtaskSuggestion `json:"subtasks"`
+	}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), &out); err != nil {
+		return nil, fmt.Errorf("breakdown reply: %w", err)
+	}
+
+	subtasks := make([]domain.SubtaskSuggestion, 0, len(out.Subtasks))
+	for _, sg := range out.Subtasks {
+		sg.Title = strings.TrimSpace(sg.Title)
+		if sg.Title == "" {
+			continue
+		}
+		if len(sg.Title) > 255 {
+			sg.Title = sg.Title[:255]
+		}
+		if sg.EstimatedHours != nil && *sg.EstimatedHours <= 0 {
+			sg.EstimatedHours = nil
+		}
+		subtasks = append(subtasks, sg)
+		if len(subtasks) == limit {
+			break
+		}
+	}
+	if len(subtasks) == 0 {
+		return nil, fmt.Errorf("breakdown reply has no subtasks")
+	}
+	return subtasks, nil
+}
+
+// splitEffort makes subtask estimates add up to the parent's estimate, if it
+// has one, rounding to quarter hours. It returns the resulting total.
+func splitEffort(subtasks []domain.SubtaskSuggestion, parent *float64) float64 {
+	sum := 0.0
+	for _, sg := range subtasks {
+		if sg.EstimatedHours != nil {
+			sum += *sg.EstimatedHours
+		}
+	}
+	if parent == nil || *parent <= 0 {
+		return sum
+	}
+
+	total := 0.0
+	for i := range subtasks {
+		var h float64
+		switch {
+		case sum == 0:
+			h = *parent / float64(len(subtasks))
+		case subtasks[i].EstimatedHours == nil:
+			continue
+		default:
+			h = *parent * *subtasks[i].EstimatedHours / sum
+		}
+		h = math.Max(0.25, math.Round(h*4)/4)
+		subtasks[i].EstimatedHours = &h
+		total += h
+	}
+	return total
+}
@@ -0,0 +1,71 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLLM struct{ reply string }
+
+func (f fakeLLM) Name() string { return "fake" }
+
+func (f fakeLLM) Complete(context.Context, string, string) (string, error) { return f.reply, nil }
+
+func TestBreakdownService_ProposeScalesEffortToParent(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	estimate := 4.0
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).
+		Return(&domain.Task{ID: taskID, UserID: userID, Title: "Ship release", EstimatedHours: &estimate}, nil)
+
+	reply := "Sure!\n```json\n" + `{"subtasks":[{"title":"Write changelog","estimated_hours":1},` +
+		`{"title":"  "},{"title":"Tag and publish","estimated_hours":3}]}` + "\n```"
+	svc := service.NewBreakdownService(newTaskService(taskRepo, &mockProjectRepo{}), fakeLLM{reply: reply}, logrus.New())
+
+	got, err := svc.Propose(context.Background(), userID, taskID, 0)
+	require.NoError(t, err)
+
+	require.Len(t, got.Subtasks, 2)
+	assert.Equal(t, "fake", got.Provider)
+	assert.Equal(t, "Write changelog", got.Subtasks[0].Title)
+	assert.Equal(t, 1.0, *got.Subtasks[0].EstimatedHours)
+	assert.Equal(t, 3.0, *got.Subtasks[1].EstimatedHours)
+	assert.Equal(t, 4.0, got.TotalEstimatedHours)
+}
+
+func TestBreakdownService_DisabledWithoutProvider(t *testing.T) {
+	svc := service.NewBreakdownService(newTaskService(&mockTaskRepo{}, &mockProjectRepo{}), nil, logrus.New())
+
+	_, err := svc.Propose(context.Background(), uuid.New(), uuid.New(), 0)
+	assert.ErrorIs(t, err, domain.ErrFeatureDisabled)
+}
+
+func TestBreakdownService_AcceptCreatesSubtasks(t *testing.T) {
+	userID, taskID, projectID := uuid.New(), uuid.New(), uuid.New()
+	parent := &domain.Task{ID: taskID, UserID: userID, ProjectID: &projectID, Priority: domain.TaskPriorityHigh}
+	taskRepo, projectRepo := &mockTaskRepo{}, &mockProjectRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(parent, nil)
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID}, nil)
+	svc := service.NewBreakdownService(newTaskService(taskRepo, projectRepo), nil, logrus.New())
+
+	tasks, err := svc.Accept(context.Background(), userID, taskID, &domain.AcceptBreakdownRequest{
+		Subtasks: []domain.SubtaskSuggestion{{Title: "First"}, {Title: "Second"}},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, tasks, 2)
+	for _, task := range tasks {
+		assert.Equal(t, &taskID, task.ParentID)
+		assert.Equal(t, &projectID, task.ProjectID)
+		assert.Equal(t, domain.TaskPriorityHigh, task.Priority)
+	}
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/holiday"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// BusinessCalendarService manages users' working weeks and days off, and
+// moves due dates that fall on a day off to the next business day for users
+// who ask for it.
+type BusinessCalendarService struct {
+	calendarRepo domain.BusinessCalendarRepository
+	dayOffRepo   domain.DayOffRepository
+	holidays     holiday.Calendars
+	log          *logrus.Logger
+}
+
+// NewBusinessCalendarService constructs a BusinessCalendarService with the
+// country calendars of the deployment.
+func NewBusinessCalendarService(calendarRepo domain.BusinessCalendarRepository, dayOffRepo domain.DayOffRepository, holidays holiday.Calendars, log *logrus.Logger) *BusinessCalendarService {
+	return &BusinessCalendarService{calendarRepo: calendarRepo, dayOffRepo: dayOffRepo, holidays: holidays, log: log}
+}
+
+// Countries lists the holiday calendars users can choose from.
+func (s *BusinessCalendarService) Countries() []*holiday.Calendar {
+	return s.holidays.List()
+}
+
+// Holidays returns a country's public holidays in year.
+func (s *BusinessCalendarService) Holidays(country string, year int) ([]holiday.Holiday, error) {
+	cal, ok := s.holidays.Get(country)
+	if !ok {
+		return nil, fmt.Errorf("businessCalendarService.Holidays: %w", domain.ErrNotFound)
+	}
+	return cal.InYear(year), nil
+}
+
+// Get returns the user's working week, or a Monday-to-Friday, nine-to-five
+// default in UTC when none has been saved yet.
+func (s *BusinessCalendarService) Get(ctx context.Context, userID uuid.UUID) (*domain.BusinessCalendar, error) {
+	c, err := s.calendarRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.BusinessCalendar{
+			UserID:      userID,
+			Timezone:    "UTC",
+			WeekendDays: domain.DefaultWeekendDays,
+			Weekend:     domain.WeekdayNames(domain.DefaultWeekendDays),
+			WorkStart:   domain.DefaultWorkStart,
+			WorkEnd:     domain.DefaultWorkEnd,
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("businessCalendarService.Get: %w", err)
+	}
+	return c, nil
+}
+
+// Update replaces the user's working week.
+func (s *BusinessCalendarService) Update(ctx context.Context, userID uuid.UUID, req *domain.UpdateBusinessCalendarRequest) (*domain.BusinessCalendar, error) {
+	mask, err := domain.ParseWeekdays(req.WeekendDays)
+	if err != nil {
+		return nil, fmt.Errorf("businessCalendarService.Update: %v: %w", err, domain.ErrValidation)
+	}
+
+	var country *string
+	if req.Country != nil && *req.Country != "" {
+		cal, ok := s.holidays.Get(*req.Country)
+		if !ok {
+			return nil, fmt.Errorf("businessCalendarService.Update: no holiday calendar for %q: %w", *req.Country, domain.ErrValidation)
+		}
+		country = &cal.Country
+	}
+
+	c := &domain.BusinessCalendar{
+		UserID:          userID,
+		Country:         country,
+		Timezone:        req.Timezone,
+		WeekendDays:     mask,
+		ShiftDueDates:   req.ShiftDueDates,
+		WorkStart:       req.WorkStart,
+		WorkEnd:         req.WorkEnd,
+		WorkingTimeOnly: req.WorkingTimeOnly,
+		UpdatedAt:       time.Now().UTC(),
+	}
+	if c.WorkStart == "" {
+		c.WorkStart = domain.DefaultWorkStart
+	}
+	if c.WorkEnd == "" {
+		c.WorkEnd = domain.DefaultWorkEnd
+	}
+	if _, _, ok := c.WorkingHours(); !ok {
+		return nil, fmt.Errorf("businessCalendarService.Update: work_end must be after work_start: %w", domain.ErrValidation)
+	}
+	if err := s.calendarRepo.Upsert(ctx, c); err != nil {
+		return nil, fmt.Errorf("businessCalendarService.Update: %w", err)
+	}
+	c.Weekend = domain.WeekdayNames(mask)
+	return c, nil
+}
+
+// ListDaysOff returns the user's days off in date order.
+func (s *BusinessCalendarService) ListDaysOff(ctx context.Context, userID uuid.UUID) ([]*domain.DayOff, error) {
+	days, err := s.dayOffRepo.ListByUserID(ctx, userID, "")
+	if err != nil {
+		return nil, fmt.Errorf("businessCalendarService.ListDaysOff: %w", err)
+	}
+	return days, nil
+}
+
+// AddDayOff adds a day off for the user. A date can only be added once.
+func (s *BusinessCalendarService) AddDayOff(ctx context.Context, userID uuid.UUID, req *domain.CreateDayOffRequest) (*domain.DayOff, error) {
+	d := &domain.DayOff{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Date:      req.Date,
+		Name:      strings.TrimSpace(req.Name),
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.dayOffRepo.Create(ctx, d); err != nil {
+		return nil, fmt.Errorf("businessCalendarService.AddDayOff: %w", err)
+	}
+	return d, nil
+}
+
+// DeleteDayOff removes one of the user's days off.
+func (s *BusinessCalendarService) DeleteDayOff(ctx context.Context, id, userID uuid.UUID) error {
+	d, err := s.dayOffRepo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("businessCalendarService.DeleteDayOff: %w", err)
+	}
+	if d.UserID != userID {
+		return fmt.Errorf("businessCalendarService.DeleteDayOff: %w", domain.ErrNotFound)
+	}
+	if err := s.dayOffRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("businessCalendarService.DeleteDayOff: %w", err)
+	}
+	return nil
+}
+
+// AdjustDueDate implements DueDateAdjuster: for users who opted in, a due
+// date on a weekend, public holiday or day off moves to the next business
+// day at the same time of day.
+func (s *BusinessCalendarService) AdjustDueDate(ctx context.Context, userID uuid.UUID, due time.Time) (time.Time, error) {
+	c, err := s.calendarRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return due, nil
+	}
+	if err != nil {
+		return due, fmt.Errorf("businessCalendarService.AdjustDueDate: %w", err)
+	}
+	if !c.ShiftDueDates {
+		return due, nil
+	}
+
+	days, err := s.businessDays(ctx, c, due)
+	if err != nil {
+		return due, fmt.Errorf("businessCalendarService.AdjustDueDate: %w", err)
+	}
+	shifted := days.Next(due)
+	if !shifted.Equal(due) {
+		s.log.WithFields(logrus.Fields{"user_id": userID, "due": due, "shifted": shifted}).Debug("due date moved to next business day")
+	}
+	return shifted, nil
+}
+
+// Clock returns what counts as time for the user from the local date of
+// from on: with WorkingTimeOnly, their working hours on business days;
+// otherwise every hour. workingOnly reports which.
+func (s *BusinessCalendarService) Clock(ctx context.Context, userID uuid.UUID, from time.Time) (clock holiday.BusinessDays, workingOnly bool, err error) {
+	c, err := s.Get(ctx, userID)
+	if err != nil {
+		return clock, false, err
+	}
+	if !c.WorkingTimeOnly {
+		return holiday.BusinessDays{}, false, nil
+	}
+	clock, err = s.businessDays(ctx, c, from)
+	if err != nil {
+		return clock, false, fmt.Errorf("businessCalendarService.Clock: %w", err)
+	}
+	clock.DayStart, clock.DayEnd, _ = c.WorkingHours()
+	return clock, true, nil
+}
+
+// businessDays builds the user's working days from the local date of from on.
+func (s *BusinessCalendarService) businessDays(ctx context.Context, c *domain.BusinessCalendar, from time.Time) (holiday.BusinessDays, error) {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	days := holiday.BusinessDays{Location: loc, Weekend: c.WeekendDays}
+	if c.Country != nil {
+		// A calendar dropped from the deployment since it was chosen is
+		// ignored rather than failing every task write.
+		days.Calendar, _ = s.holidays.Get(*c.Country)
+	}
+
+	off, err := s.dayOffRepo.ListByUserID(ctx, c.UserID, from.In(loc).Format("2006-01-02"))
+	if err != nil {
+		return days, err
+	}
+	days.DaysOff = make(map[string]string, len(off))
+	for _, d := range off {
+		days.DaysOff[d.Date] = d.Name
+	}
+	return days, nil
+}
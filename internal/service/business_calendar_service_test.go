@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/holiday"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBusinessCalendarRepo struct {
+	domain.BusinessCalendarRepository
+	calendar *domain.BusinessCalendar
+}
+
+func (f *fakeBusinessCalendarRepo) FindByUserID(_ context.Context, _ uuid.UUID) (*domain.BusinessCalendar, error) {
+	if f.calendar == nil {
+		return nil, domain.ErrNotFound
+	}
+	return f.calendar, nil
+}
+
+func (f *fakeBusinessCalendarRepo) Upsert(_ context.Context, c *domain.BusinessCalendar) error {
+	f.calendar = c
+	return nil
+}
+
+type fakeDayOffRepo struct {
+	domain.DayOffRepository
+	days []*domain.DayOff
+}
+
+func (f *fakeDayOffRepo) ListByUserID(_ context.Context, _ uuid.UUID, from string) ([]*domain.DayOff, error) {
+	var out []*domain.DayOff
+	for _, d := range f.days {
+		if d.Date >= from {
+			out = append(out, d)
+		}
+	}
+	return out, nil
+}
+
+func newBusinessCalendarService(t *testing.T, cal *domain.BusinessCalendar, days ...*domain.DayOff) *service.BusinessCalendarService {
+	t.Helper()
+	holidays, err := holiday.Load("")
+	require.NoError(t, err)
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewBusinessCalendarService(&fakeBusinessCalendarRepo{calendar: cal}, &fakeDayOffRepo{days: days}, holidays, log)
+}
+
+func TestBusinessCalendarService_AdjustDueDate(t *testing.T) {
+	userID := uuid.New()
+	us := "US"
+	christmas := time.Date(2026, 12, 25, 17, 0, 0, 0, time.UTC) // a Friday
+
+	t.Run("opted out", func(t *testing.T) {
+		svc := newBusinessCalendarService(t, &domain.BusinessCalendar{
+			UserID: userID, Country: &us, Timezone: "UTC", WeekendDays: domain.DefaultWeekendDays,
+		})
+		due, err := svc.AdjustDueDate(context.Background(), userID, christmas)
+		require.NoError(t, err)
+		assert.Equal(t, christmas, due)
+	})
+
+	t.Run("no calendar saved", func(t *testing.T) {
+		svc := newBusinessCalendarService(t, nil)
+		due, err := svc.AdjustDueDate(context.Background(), userID, christmas)
+		require.NoError(t, err)
+		assert.Equal(t, christmas, due)
+	})
+
+	t.Run("holiday, weekend and day off", func(t *testing.T) {
+		svc := newBusinessCalendarService(t, &domain.BusinessCalendar{
+			UserID: userID, Country: &us, Timezone: "UTC", WeekendDays: domain.DefaultWeekendDays, ShiftDueDates: true,
+		}, &domain.DayOff{UserID: userID, Date: "2026-12-28", Name: "Vacation"})
+		due, err := svc.AdjustDueDate(context.Background(), userID, christmas)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 12, 29, 17, 0, 0, 0, time.UTC), due)
+	})
+
+	t.Run("custom weekend", func(t *testing.T) {
+		svc := newBusinessCalendarService(t, &domain.BusinessCalendar{
+			UserID: userID, Timezone: "Asia/Riyadh", WeekendDays: 1<<time.Friday | 1<<time.Saturday, ShiftDueDates: true,
+		})
+		due, err := svc.AdjustDueDate(context.Background(), userID, christmas)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 12, 27, 17, 0, 0, 0, time.UTC), due, "Sunday is a working day")
+	})
+}
+
+func TestBusinessCalendarService_UpdateRejectsUnknownCountry(t *testing.T) {
+	svc := newBusinessCalendarService(t, nil)
+	xx := "XX"
+	_, err := svc.Update(context.Background(), uuid.New(), &domain.UpdateBusinessCalendarRequest{Country: &xx, Timezone: "UTC"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	gb := "gb"
+	cal, err := svc.Update(context.Background(), uuid.New(), &domain.UpdateBusinessCalendarRequest{
+		Country: &gb, Timezone: "Europe/London", WeekendDays: []string{"saturday", "sunday"}, ShiftDueDates: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "GB", *cal.Country)
+	assert.Equal(t, domain.DefaultWeekendDays, cal.WeekendDays)
+}
+
+func TestTaskService_ShiftsDueDatesOffDaysOff(t *testing.T) {
+	userID := uuid.New()
+	calendarSvc := newBusinessCalendarService(t, &domain.BusinessCalendar{
+		UserID: userID, Timezone: "UTC", WeekendDays: domain.DefaultWeekendDays, ShiftDueDates: true,
+	})
+	saturday := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	t.Run("on create, keeping the series anchor", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+		svc.UseDueDateAdjuster(calendarSvc)
+
+		task, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{
+			Title: "Pay rent", Priority: domain.TaskPriorityHigh, DueDate: &saturday,
+			Recurrence: &domain.Recurrence{Frequency: domain.RecurMonthly},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, monday, *task.DueDate)
+		assert.Equal(t, saturday, task.Recurrence.Anchor)
+	})
+
+	t.Run("on the next occurrence", func(t *testing.T) {
+		// A daily task due on the coming Friday, completed early.
+		friday := time.Now().UTC().AddDate(0, 0, 1)
+		for friday.Weekday() != time.Friday {
+			friday = friday.AddDate(0, 0, 1)
+		}
+		task := &domain.Task{
+			ID: uuid.New(), UserID: userID, Title: "Standup notes", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium,
+			DueDate: &friday, Recurrence: &domain.Recurrence{Frequency: domain.RecurDaily, Anchor: friday},
+		}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+		svc.UseDueDateAdjuster(calendarSvc)
+
+		done := domain.TaskStatusDone
+		updated, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Status: &done})
+		require.NoError(t, err)
+		assert.Equal(t, friday.AddDate(0, 0, 3), *updated.DueDate, "Saturday's occurrence moves to Monday")
+	})
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/ical"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// feedPast and feedAhead bound the window of a calendar feed.
+	feedPast  = 30 * 24 * time.Hour
+	feedAhead = 366 * 24 * time.Hour
+	// maxFeedEvents caps each kind of event in a feed.
+	maxFeedEvents = 1000
+)
+
+// CalendarFeedService publishes a user's tasks as an iCalendar feed that
+// calendar apps subscribe to by URL: scheduled tasks as timed events over
+// their slot, and due dates as all-day events.
+type CalendarFeedService struct {
+	feedRepo domain.CalendarFeedRepository
+	taskSvc  *TaskService
+	baseURL  string
+	branding *BrandingService
+	log      *logrus.Logger
+}
+
+// NewCalendarFeedService constructs a CalendarFeedService. baseURL is the
+// public address of the API that feed URLs point at.
+func NewCalendarFeedService(feedRepo domain.CalendarFeedRepository, taskSvc *TaskService, baseURL string, log *logrus.Logger) *CalendarFeedService {
+	return &CalendarFeedService{feedRepo: feedRepo, taskSvc: taskSvc, baseURL: strings.TrimRight(baseURL, "/"), log: log}
+}
+
+// UseBranding names feeds after the workspace. Must be called before
+// serving requests.
+func (s *CalendarFeedService) UseBranding(b *BrandingService) {
+	s.branding = b
+}
+
+// Rotate issues the user a new feed URL, replacing any earlier one.
+func (s *CalendarFeedService) Rotate(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	token, err := newFeedToken()
+	if err != nil {
+		return nil, fmt.Errorf("calendarFeedService.Rotate: %w", err)
+	}
+	feed := &domain.CalendarFeed{UserID: userID, Token: token, CreatedAt: time.Now()}
+	if err := s.feedRepo.Upsert(ctx, feed); err != nil {
+		return nil, fmt.Errorf("calendarFeedService.Rotate: %w", err)
+	}
+	feed.URL = s.feedURL(token)
+	return feed, nil
+}
+
+// Get returns the user's feed with its URL.
+func (s *CalendarFeedService) Get(ctx context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	feed, err := s.feedRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	feed.URL = s.feedURL(feed.Token)
+	return feed, nil
+}
+
+// QR returns a PNG QR code of the user's feed URL, so that a phone can
+// subscribe by scanning it.
+func (s *CalendarFeedService) QR(ctx context.Context, userID uuid.UUID, scale int) ([]byte, error) {
+	feed, err := s.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	out, err := renderQR(feed.URL, scale)
+	if err != nil {
+		return nil, fmt.Errorf("calendarFeedService.QR: %w", err)
+	}
+	return out, nil
+}
+
+// Revoke turns the user's feed off.
+func (s *CalendarFeedService) Revoke(ctx context.Context, userID uuid.UUID) error {
+	return s.feedRepo.Delete(ctx, userID)
+}
+
+// Render returns the feed a token gives access to, covering open tasks from
+// a month before now to a year after. Unknown tokens are ErrNotFound.
+func (s *CalendarFeedService) Render(ctx context.Context, token string, now time.Time) (string, error) {
+	feed, err := s.feedRepo.FindByToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+	from, to := now.Add(-feedPast), now.Add(feedAhead)
+	scheduled, err := s.taskSvc.ListScheduled(ctx, feed.UserID, from, to, maxFeedEvents)
+	if err != nil {
+		return "", fmt.Errorf("calendarFeedService.Render: %w", err)
+	}
+	due, _, err := s.taskSvc.List(ctx, feed.UserID, domain.TaskFilter{DueBefore: &to, IncludeDeferred: true}, 1, maxFeedEvents)
+	if err != nil {
+		return "", fmt.Errorf("calendarFeedService.Render: %w", err)
+	}
+	loc := s.taskSvc.location(ctx, feed.UserID)
+
+	cal := &ical.Calendar{ProdID: "-//todo-app//tasks//EN", Name: "Tasks"}
+	if name := s.branding.ProductName(); name != "" {
+		cal.Name = name + " tasks"
+	}
+	for _, t := range scheduled {
+		end, _ := t.ScheduledEnd()
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         t.ID.String() + "-slot@todo-app",
+			Summary:     t.Title,
+			Description: t.Description,
+			Start:       *t.ScheduledAt,
+			End:         end,
+			Updated:     t.UpdatedAt,
+		})
+	}
+	for _, t := range due {
+		if t.DueDate == nil || t.Status == domain.TaskStatusDone || t.DueDate.Before(from) {
+			continue
+		}
+		local := t.DueDate.In(loc)
+		day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		cal.Events = append(cal.Events, ical.Event{
+			UID:         t.ID.String() + "-due@todo-app",
+			Summary:     "Due: " + t.Title,
+			Description: t.Description,
+			Start:       day,
+			End:         day.AddDate(0, 0, 1),
+			AllDay:      true,
+			Updated:     t.UpdatedAt,
+		})
+	}
+	return cal.String(), nil
+}
+
+func (s *CalendarFeedService) feedURL(token string) string {
+	return s.baseURL + "/api/v1/calendar-feeds/" + token + ".ics"
+}
+
+func newFeedToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate feed token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
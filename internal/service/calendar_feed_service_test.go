@@ -0,0 +1,75 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCalendarFeedRepo struct {
+	feeds map[uuid.UUID]*domain.CalendarFeed
+}
+
+func (f *fakeCalendarFeedRepo) Upsert(_ context.Context, feed *domain.CalendarFeed) error {
+	f.feeds[feed.UserID] = feed
+	return nil
+}
+
+func (f *fakeCalendarFeedRepo) FindByToken(_ context.Context, token string) (*domain.CalendarFeed, error) {
+	for _, feed := range f.feeds {
+		if feed.Token == token {
+			return feed, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeCalendarFeedRepo) FindByUserID(_ context.Context, userID uuid.UUID) (*domain.CalendarFeed, error) {
+	if feed, ok := f.feeds[userID]; ok {
+		return feed, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeCalendarFeedRepo) Delete(_ context.Context, userID uuid.UUID) error {
+	delete(f.feeds, userID)
+	return nil
+}
+
+func TestCalendarFeedService_Render(t *testing.T) {
+	userID := uuid.New()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	slot := now.Add(time.Hour)
+	ninety := 90
+	due := time.Date(2026, 10, 23, 17, 0, 0, 0, time.UTC)
+	scheduled := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Write proposal, draft 2", ScheduledAt: &slot, ScheduledDuration: &ninety}
+	dueTask := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Pay rent", Status: domain.TaskStatusTodo, DueDate: &due}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("ListScheduled", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.Task{scheduled}, nil)
+	taskRepo.On("List", mock.Anything, userID, mock.Anything, 1, mock.Anything).Return([]*domain.Task{dueTask}, 1, nil)
+	feeds := &fakeCalendarFeedRepo{feeds: map[uuid.UUID]*domain.CalendarFeed{}}
+	svc := service.NewCalendarFeedService(feeds, newTaskService(taskRepo, &mockProjectRepo{}), "https://todo.example.com/", logrus.New())
+
+	feed, err := svc.Rotate(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, "https://todo.example.com/api/v1/calendar-feeds/"+feed.Token+".ics", feed.URL)
+
+	ics, err := svc.Render(context.Background(), feed.Token, now)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "DTSTART:20261019T090000Z\r\nDTEND:20261019T103000Z\r\nSUMMARY:Write proposal\\, draft 2\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20261023\r\nDTEND;VALUE=DATE:20261024\r\nSUMMARY:Due: Pay rent\r\n")
+
+	_, err = svc.Render(context.Background(), "nope", now)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxChangelogEntries caps how many published entries a feed looks at.
+const maxChangelogEntries = 200
+
+// ChangelogService publishes product release notes and tracks which ones
+// each user has yet to see.
+type ChangelogService struct {
+	changelogRepo domain.ChangelogRepository
+	userRepo      domain.UserRepository
+	log           *logrus.Logger
+}
+
+// NewChangelogService constructs a ChangelogService.
+func NewChangelogService(changelogRepo domain.ChangelogRepository, userRepo domain.UserRepository, log *logrus.Logger) *ChangelogService {
+	return &ChangelogService{changelogRepo: changelogRepo, userRepo: userRepo, log: log}
+}
+
+// Create publishes an entry for a release. The version is stored as
+// MAJOR.MINOR.PATCH and must be new.
+func (s *ChangelogService) Create(ctx context.Context, adminID uuid.UUID, req *domain.CreateChangelogRequest) (*domain.ChangelogEntry, error) {
+	version, err := domain.ParseVersion(req.Version)
+	if err != nil {
+		return nil, fmt.Errorf("changelogService.Create: %s: %w", err, domain.ErrValidation)
+	}
+
+	now := time.Now()
+	entry := &domain.ChangelogEntry{
+		ID:          uuid.New(),
+		Version:     version.String(),
+		Title:       req.Title,
+		Body:        req.Body,
+		PublishedAt: now,
+		CreatedBy:   &adminID,
+		CreatedAt:   now,
+	}
+	if req.PublishedAt != nil {
+		entry.PublishedAt = *req.PublishedAt
+	}
+
+	if err := s.changelogRepo.Create(ctx, entry); err != nil {
+		return nil, fmt.Errorf("changelogService.Create: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{"version": entry.Version, "admin_id": adminID}).Info("changelog entry published")
+	return entry, nil
+}
+
+// Feed returns the published entries newer than sinceVersion (all of them
+// when it is empty), newest version first, flagging the ones the user has
+// not seen. UnreadCount covers the whole changelog, not just this page.
+func (s *ChangelogService) Feed(ctx context.Context, userID uuid.UUID, sinceVersion string) (*domain.ChangelogFeed, error) {
+	var since *domain.Version
+	if sinceVersion != "" {
+		v, err := domain.ParseVersion(sinceVersion)
+		if err != nil {
+			return nil, fmt.Errorf("changelogService.Feed: %s: %w", err, domain.ErrValidation)
+		}
+		since = &v
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("changelogService.Feed: %w", err)
+	}
+	entries, err := s.changelogRepo.ListPublished(ctx, time.Now(), maxChangelogEntries)
+	if err != nil {
+		return nil, fmt.Errorf("changelogService.Feed: %w", err)
+	}
+
+	// Releases from before the user signed up are not news to them.
+	seen := user.CreatedAt
+	if user.ChangelogSeenAt != nil {
+		seen = *user.ChangelogSeenAt
+	}
+
+	versions := make(map[*domain.ChangelogEntry]domain.Version, len(entries))
+	for _, e := range entries {
+		// Stored versions were normalised by Create.
+		versions[e], _ = domain.ParseVersion(e.Version)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return versions[entries[i]].After(versions[entries[j]]) })
+
+	feed := &domain.ChangelogFeed{Entries: []*domain.ChangelogEntry{}}
+	if len(entries) > 0 {
+		feed.LatestVersion = entries[0].Version
+	}
+	for _, e := range entries {
+		e.Unread = e.PublishedAt.After(seen)
+		if e.Unread {
+			feed.UnreadCount++
+		}
+		if since == nil || versions[e].After(*since) {
+			feed.Entries = append(feed.Entries, e)
+		}
+	}
+	return feed, nil
+}
+
+// MarkSeen clears the user's unread flags for everything published so far.
+func (s *ChangelogService) MarkSeen(ctx context.Context, userID uuid.UUID) error {
+	if err := s.userRepo.SetChangelogSeen(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("changelogService.MarkSeen: %w", err)
+	}
+	return nil
+}
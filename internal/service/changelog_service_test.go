@@ -0,0 +1,92 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeChangelogRepo struct {
+	domain.ChangelogRepository
+	entries []*domain.ChangelogEntry
+}
+
+func (f *fakeChangelogRepo) Create(_ context.Context, e *domain.ChangelogEntry) error {
+	for _, existing := range f.entries {
+		if existing.Version == e.Version {
+			return domain.ErrAlreadyExists
+		}
+	}
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeChangelogRepo) ListPublished(_ context.Context, now time.Time, _ int) ([]*domain.ChangelogEntry, error) {
+	var out []*domain.ChangelogEntry
+	for _, e := range f.entries {
+		if !e.PublishedAt.After(now) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+type changelogUserRepo struct {
+	domain.UserRepository
+	user *domain.User
+}
+
+func (f *changelogUserRepo) FindByID(_ context.Context, _ uuid.UUID) (*domain.User, error) {
+	return f.user, nil
+}
+
+func (f *changelogUserRepo) SetChangelogSeen(_ context.Context, _ uuid.UUID, at time.Time) error {
+	f.user.ChangelogSeenAt = &at
+	return nil
+}
+
+func TestChangelogService_Feed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	user := &domain.User{ID: uuid.New(), CreatedAt: now.Add(-48 * time.Hour)}
+	repo := &fakeChangelogRepo{}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewChangelogService(repo, &changelogUserRepo{user: user}, log)
+
+	publish := func(version string, at time.Time) {
+		_, err := svc.Create(ctx, uuid.New(), &domain.CreateChangelogRequest{Version: version, Title: version, PublishedAt: &at})
+		require.NoError(t, err)
+	}
+	publish("1.9.0", now.Add(-72*time.Hour)) // before signup
+	publish("v1.10", now.Add(-time.Hour))
+	publish("1.9.5", now.Add(-2*time.Hour))
+	publish("2.0.0", now.Add(time.Hour)) // scheduled
+
+	_, err := svc.Create(ctx, uuid.New(), &domain.CreateChangelogRequest{Version: "1.10.0", Title: "again"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists, "versions are normalised before the uniqueness check")
+
+	feed, err := svc.Feed(ctx, user.ID, "1.9")
+	require.NoError(t, err)
+	require.Len(t, feed.Entries, 2)
+	assert.Equal(t, "1.10.0", feed.Entries[0].Version, "newest version first")
+	assert.Equal(t, "1.9.5", feed.Entries[1].Version)
+	assert.Equal(t, "1.10.0", feed.LatestVersion)
+	assert.Equal(t, 2, feed.UnreadCount, "the pre-signup entry is not unread")
+
+	require.NoError(t, svc.MarkSeen(ctx, user.ID))
+	feed, err = svc.Feed(ctx, user.ID, "")
+	require.NoError(t, err)
+	assert.Len(t, feed.Entries, 3)
+	assert.Zero(t, feed.UnreadCount)
+
+	_, err = svc.Feed(ctx, user.ID, "latest")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// CircuitBreakerPolicy decides when repeated failures pause an automation rule
+// or webhook, so a broken one stops burning worker time on doomed runs and
+// retries. A zero Threshold disables the breakers.
+type CircuitBreakerPolicy struct {
+	Threshold int           // consecutive failures that open the breaker
+	Cooloff   time.Duration // how long it stays open before a trial run
+}
+
+// breakerStore is implemented by the repositories of things with a breaker.
+type breakerStore interface {
+	RecordFailure(ctx context.Context, id uuid.UUID, threshold int, openUntil time.Time) (int, error)
+	ResetBreaker(ctx context.Context, id uuid.UUID) error
+}
+
+// record updates the breaker after a run. It returns when the breaker opens
+// if this failure tripped it, or nil. Failed trial runs after the cool-off
+// reopen the breaker without reporting it again, so owners hear about it once.
+func (p CircuitBreakerPolicy) record(ctx context.Context, store breakerStore, id uuid.UUID, b domain.CircuitBreaker, runErr error) (*time.Time, error) {
+	if p.Threshold <= 0 {
+		return nil, nil
+	}
+	if runErr == nil {
+		if b.Failures == 0 {
+			return nil, nil
+		}
+		return nil, store.ResetBreaker(ctx, id)
+	}
+
+	openUntil := time.Now().Add(p.Cooloff)
+	failures, err := store.RecordFailure(ctx, id, p.Threshold, openUntil)
+	if err != nil {
+		return nil, err
+	}
+	if failures != p.Threshold {
+		return nil, nil
+	}
+	return &openUntil, nil
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jsonschema"
+)
+
+// Documents users upload, checked against a published JSON Schema before
+// anything is imported.
+const (
+	// DocumentProjectBundle is a project bundle for POST /projects/import.
+	DocumentProjectBundle = "project_bundle"
+	// DocumentDesiredProject is a desired state for declarative sync.
+	DocumentDesiredProject = "desired_project"
+)
+
+// DocumentKinds lists the documents with a schema.
+var DocumentKinds = []string{DocumentProjectBundle, DocumentDesiredProject}
+
+// documentSchemas are built once from the types the documents decode into,
+// so a schema always accepts what the import accepts.
+var documentSchemas = buildDocumentSchemas()
+
+func buildDocumentSchemas() map[string]*jsonschema.Schema {
+	r := &jsonschema.Reflector{Enums: schemaReflector.Enums, Strict: true}
+	bundle := r.Reflect(domain.ProjectBundle{})
+	bundle.Title = "Project bundle"
+	desired := r.Reflect(domain.DesiredProject{})
+	desired.Title = "Desired project state"
+	return map[string]*jsonschema.Schema{
+		DocumentProjectBundle:  bundle,
+		DocumentDesiredProject: desired,
+	}
+}
+
+// DocumentSchema returns the JSON Schema of a kind of document.
+func DocumentSchema(kind string) (*jsonschema.Schema, error) {
+	schema, ok := documentSchemas[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown document %q: %w", kind, domain.ErrNotFound)
+	}
+	return schema, nil
+}
+
+// DocumentValidation is the outcome of checking a document against its
+// schema.
+type DocumentValidation struct {
+	Schema string             `json:"schema"`
+	Valid  bool               `json:"valid"`
+	Errors []jsonschema.Error `json:"errors"`
+}
+
+// ValidateDocument checks doc, decoded from JSON or YAML into an any with
+// JSON types, against the schema of kind, listing every violation.
+func ValidateDocument(kind string, doc any) (*DocumentValidation, error) {
+	schema, err := DocumentSchema(kind)
+	if err != nil {
+		return nil, err
+	}
+	errs := schema.Validate(doc)
+	if errs == nil {
+		errs = []jsonschema.Error{}
+	}
+	return &DocumentValidation{Schema: kind, Valid: len(errs) == 0, Errors: errs}, nil
+}
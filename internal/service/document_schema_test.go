@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/jsonschema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func decodeJSON(t *testing.T, raw string) any {
+	t.Helper()
+	var doc any
+	require.NoError(t, json.Unmarshal([]byte(raw), &doc))
+	return doc
+}
+
+func TestValidateDocument_ProjectBundle(t *testing.T) {
+	valid := decodeJSON(t, `{
+		"version": 1,
+		"project": {"name": "Move house", "type": "personal", "color": "#22C55E"},
+		"tags": [{"name": "errand"}],
+		"tasks": [{
+			"key": "boxes", "title": "Buy boxes", "priority": "high", "due_date": "2024-05-01T09:00:00Z",
+			"subtasks": [{"title": "Measure rooms", "estimated_hours": 1.5}]
+		}, {"title": "Pack books", "blocked_by": ["boxes"], "description": null}]
+	}`)
+	result, err := service.ValidateDocument(service.DocumentProjectBundle, valid)
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "%v", result.Errors)
+	assert.NotNil(t, result.Errors)
+
+	invalid := decodeJSON(t, `{
+		"version": 2,
+		"project": {"type": "hobby", "colour": "#fff"},
+		"tasks": [{"title": "ok", "subtasks": [{"title": "", "status": "blocked", "due_date": "tomorrow"}]}]
+	}`)
+	result, err = service.ValidateDocument(service.DocumentProjectBundle, invalid)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	paths := map[string]string{}
+	for _, e := range result.Errors {
+		paths[e.Path] = e.Message
+	}
+	assert.Contains(t, paths, "/version")
+	assert.Equal(t, "this field is required", paths["/project/name"])
+	assert.Equal(t, "unknown field", paths["/project/colour"])
+	assert.Equal(t, "must be one of: personal, work, side_project", paths["/project/type"])
+	assert.Equal(t, "must not be empty", paths["/tasks/0/subtasks/0/title"], "subtasks are checked at any depth")
+	assert.Contains(t, paths, "/tasks/0/subtasks/0/status")
+	assert.Contains(t, paths, "/tasks/0/subtasks/0/due_date")
+}
+
+func TestValidateDocument_DesiredProject(t *testing.T) {
+	result, err := service.ValidateDocument(service.DocumentDesiredProject, decodeJSON(t, `{"tasks": [{"title": "Write"}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []jsonschema.Error{{Path: "/type", Message: "this field is required"}}, result.Errors)
+
+	result, err = service.ValidateDocument(service.DocumentDesiredProject, decodeJSON(t, `{"type": "work", "color": ""}`))
+	require.NoError(t, err)
+	assert.True(t, result.Valid, "an empty color is left out: %v", result.Errors)
+
+	_, err = service.ValidateDocument("backup", decodeJSON(t, `{}`))
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
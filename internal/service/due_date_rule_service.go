@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// DueDateRuleService manages a user's due-date rules and applies them to new
+// tasks created without a due date.
+type DueDateRuleService struct {
+	ruleRepo    domain.DueDateRuleRepository
+	projectRepo domain.ProjectRepository
+	log         *logrus.Logger
+}
+
+// NewDueDateRuleService constructs a DueDateRuleService with its dependencies.
+func NewDueDateRuleService(ruleRepo domain.DueDateRuleRepository, projectRepo domain.ProjectRepository, log *logrus.Logger) *DueDateRuleService {
+	return &DueDateRuleService{ruleRepo: ruleRepo, projectRepo: projectRepo, log: log}
+}
+
+// Create adds a rule for the authenticated user.
+func (s *DueDateRuleService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateDueDateRuleRequest) (*domain.DueDateRule, error) {
+	now := time.Now()
+	rule := &domain.DueDateRule{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		Position:  req.Position,
+		Timezone:  req.Timezone,
+		Condition: req.Condition,
+		Action:    req.Action,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := validateDueDateRule(rule); err != nil {
+		return nil, fmt.Errorf("dueDateRuleService.Create: %w", err)
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("dueDateRuleService.Create: %w", err)
+	}
+	return rule, nil
+}
+
+// GetByID retrieves a rule, enforcing ownership.
+func (s *DueDateRuleService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.DueDateRule, error) {
+	rule, err := s.ruleRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	return rule, nil
+}
+
+// List returns the user's rules in evaluation order.
+func (s *DueDateRuleService) List(ctx context.Context, userID uuid.UUID) ([]*domain.DueDateRule, error) {
+	rules, err := s.ruleRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("dueDateRuleService.List: %w", err)
+	}
+	return rules, nil
+}
+
+// Update applies partial updates to a rule, enforcing ownership.
+func (s *DueDateRuleService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateDueDateRuleRequest) (*domain.DueDateRule, error) {
+	rule, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if req.Position != nil {
+		rule.Position = *req.Position
+	}
+	if req.Timezone != nil {
+		rule.Timezone = *req.Timezone
+	}
+	if req.Condition != nil {
+		rule.Condition = *req.Condition
+	}
+	if req.Action != nil {
+		rule.Action = *req.Action
+	}
+	if err := validateDueDateRule(rule); err != nil {
+		return nil, fmt.Errorf("dueDateRuleService.Update: %w", err)
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, fmt.Errorf("dueDateRuleService.Update: %w", err)
+	}
+	return rule, nil
+}
+
+// Delete removes a rule, enforcing ownership.
+func (s *DueDateRuleService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	if _, err := s.GetByID(ctx, id, userID); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("dueDateRuleService.Delete: %w", err)
+	}
+	return nil
+}
+
+// ApplyDefaults implements TaskDefaulter: a task without a due date gets the
+// one from the first enabled rule it matches.
+func (s *DueDateRuleService) ApplyDefaults(ctx context.Context, task *domain.Task) error {
+	if task.DueDate != nil {
+		return nil
+	}
+	rules, err := s.ruleRepo.ListByUserID(ctx, task.UserID)
+	if err != nil {
+		return fmt.Errorf("dueDateRuleService.ApplyDefaults: %w", err)
+	}
+
+	var projectName string
+	projectLoaded := false
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if rule.Condition.ProjectName != "" && !projectLoaded && task.ProjectID != nil {
+			project, err := s.projectRepo.FindByID(ctx, *task.ProjectID)
+			if err != nil {
+				return fmt.Errorf("dueDateRuleService.ApplyDefaults: %w", err)
+			}
+			projectName, projectLoaded = project.Name, true
+		}
+
+		loc, err := time.LoadLocation(rule.Timezone)
+		if err != nil {
+			continue
+		}
+		now := time.Now().In(loc)
+		if !rule.Condition.Matches(task, projectName, now) {
+			continue
+		}
+		due, err := rule.Action.DueDate(now)
+		if err != nil {
+			return fmt.Errorf("dueDateRuleService.ApplyDefaults: rule %s: %w", rule.ID, err)
+		}
+
+		task.DueDate = &due
+		s.log.WithFields(logrus.Fields{"task_id": task.ID, "rule_id": rule.ID}).Debug("due date defaulted by rule")
+		return nil
+	}
+	return nil
+}
+
+// validateDueDateRule checks what struct tags cannot: the action's fields
+// must agree with each other.
+func validateDueDateRule(rule *domain.DueDateRule) error {
+	if rule.Action.Due == domain.DueInDays && rule.Action.Days < 1 {
+		return fmt.Errorf("action.days must be at least 1 for in_days: %w", domain.ErrValidation)
+	}
+	if _, err := rule.Action.DueDate(time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", err.Error(), domain.ErrValidation)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeDueDateRuleRepo struct {
+	domain.DueDateRuleRepository
+	rules []*domain.DueDateRule
+}
+
+func (f *fakeDueDateRuleRepo) ListByUserID(context.Context, uuid.UUID) ([]*domain.DueDateRule, error) {
+	return f.rules, nil
+}
+
+func TestTaskService_CreateAppliesFirstMatchingDueDateRule(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	rules := &fakeDueDateRuleRepo{rules: []*domain.DueDateRule{
+		{Name: "disabled", Enabled: false, Timezone: "UTC", Action: domain.DueDateAction{Due: domain.DueToday}},
+		{Name: "errands", Enabled: true, Timezone: "UTC",
+			Condition: domain.DueDateCondition{ProjectName: "Errands"},
+			Action:    domain.DueDateAction{Due: domain.DueNextWeekday, Weekday: "saturday", At: "10:00"}},
+		{Name: "fallback", Enabled: true, Timezone: "UTC", Action: domain.DueDateAction{Due: domain.DueTomorrow}},
+	}}
+
+	taskRepo, projectRepo := &mockTaskRepo{}, &mockProjectRepo{}
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID, Name: "errands"}, nil)
+	svc := newTaskService(taskRepo, projectRepo)
+	svc.UseDefaulter(service.NewDueDateRuleService(rules, projectRepo, logrus.New()))
+	ctx := context.Background()
+
+	task, err := svc.Create(ctx, userID, &domain.CreateTaskRequest{Title: "Buy milk", Priority: domain.TaskPriorityLow, ProjectID: &projectID})
+	require.NoError(t, err)
+	require.NotNil(t, task.DueDate)
+	assert.Equal(t, time.Saturday, task.DueDate.Weekday())
+	assert.Equal(t, 10, task.DueDate.Hour())
+
+	// An explicit due date is never overridden.
+	due := time.Now().Add(time.Hour)
+	task, err = svc.Create(ctx, userID, &domain.CreateTaskRequest{Title: "Call", Priority: domain.TaskPriorityLow, DueDate: &due})
+	require.NoError(t, err)
+	assert.Equal(t, due, *task.DueDate)
+}
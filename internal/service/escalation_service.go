@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// escalationBatchSize caps the tasks escalated per run; the rest wait for
+// the next one.
+const escalationBatchSize = 500
+
+// EscalationService raises the priority of open tasks as their due date
+// nears, and of tasks left too long in todo, recording each raise. Tasks
+// with NoEscalation set are left alone.
+type EscalationService struct {
+	escalationRepo domain.TaskEscalationRepository
+	taskSvc        *TaskService
+	policy         domain.EscalationPolicy
+	aging          domain.AgingPolicy
+	log            *logrus.Logger
+}
+
+// NewEscalationService constructs an EscalationService applying policy.
+func NewEscalationService(escalationRepo domain.TaskEscalationRepository, taskSvc *TaskService, policy domain.EscalationPolicy, log *logrus.Logger) *EscalationService {
+	return &EscalationService{escalationRepo: escalationRepo, taskSvc: taskSvc, policy: policy, log: log}
+}
+
+// UseAging sets the aging policy RunAging applies. Must be called before
+// serving requests.
+func (s *EscalationService) UseAging(p domain.AgingPolicy) {
+	s.aging = p
+}
+
+// Run escalates the tasks the policy currently applies to. Each raise goes
+// through TaskService, so it shows in the task's activity and reaches
+// webhooks and automations like any other update. Run it periodically.
+func (s *EscalationService) Run(ctx context.Context) error {
+	if !s.policy.Enabled() {
+		return nil
+	}
+	now := time.Now()
+	tasks, err := s.escalationRepo.ListCandidates(ctx, bound(now, s.policy.MediumWithin), bound(now, s.policy.HighWithin), escalationBatchSize)
+	if err != nil {
+		return fmt.Errorf("escalationService.Run: %w", err)
+	}
+
+	escalated := 0
+	for _, task := range tasks {
+		target, ok := s.policy.Target(task, now)
+		if !ok {
+			continue
+		}
+		if err := s.escalate(ctx, task, target, domain.EscalationDueDate, task.DueDate, now); err != nil {
+			s.log.WithError(err).WithField("task_id", task.ID).Error("failed to escalate task priority")
+			continue
+		}
+		escalated++
+	}
+	if escalated > 0 {
+		s.log.WithField("tasks", escalated).Info("task priorities escalated")
+	}
+	return nil
+}
+
+// RunAging applies the aging policy: it adds score to tasks sitting in todo
+// and raises the priority of those in todo past its thresholds. Raises are
+// recorded and pass through TaskService like those of Run. Run it nightly.
+func (s *EscalationService) RunAging(ctx context.Context) error {
+	if err := s.taskSvc.ApplyAging(ctx, s.aging); err != nil {
+		return fmt.Errorf("escalationService.RunAging: %w", err)
+	}
+	if s.aging.MediumAfter <= 0 && s.aging.HighAfter <= 0 {
+		return nil
+	}
+	now := time.Now()
+	tasks, err := s.escalationRepo.ListAgingCandidates(ctx, since(now, s.aging.MediumAfter), since(now, s.aging.HighAfter), escalationBatchSize)
+	if err != nil {
+		return fmt.Errorf("escalationService.RunAging: %w", err)
+	}
+
+	raised := 0
+	for _, task := range tasks {
+		target, ok := s.aging.Target(task, now)
+		if !ok {
+			continue
+		}
+		if err := s.escalate(ctx, task, target, domain.EscalationAging, nil, now); err != nil {
+			s.log.WithError(err).WithField("task_id", task.ID).Error("failed to raise priority of aging task")
+			continue
+		}
+		raised++
+	}
+	if raised > 0 {
+		s.log.WithField("tasks", raised).Info("aging task priorities raised")
+	}
+	return nil
+}
+
+func (s *EscalationService) escalate(ctx context.Context, task *domain.Task, target domain.TaskPriority, reason string, dueDate *time.Time, now time.Time) error {
+	escalation := &domain.TaskEscalation{
+		ID:           uuid.New(),
+		TaskID:       task.ID,
+		UserID:       task.UserID,
+		FromPriority: task.Priority,
+		ToPriority:   target,
+		Reason:       reason,
+		DueDate:      dueDate,
+		EscalatedAt:  now,
+	}
+	if _, err := s.taskSvc.Update(ctx, task.ID, task.UserID, &domain.UpdateTaskRequest{Priority: &target}); err != nil {
+		return err
+	}
+	return s.escalationRepo.Create(ctx, escalation)
+}
+
+// Escalations returns a page of a task's automatic priority raises, most
+// recent first. It enforces ownership.
+func (s *EscalationService) Escalations(ctx context.Context, taskID, userID uuid.UUID, page, limit int) ([]*domain.TaskEscalation, int, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, 0, err
+	}
+	escalations, total, err := s.escalationRepo.ListByTaskID(ctx, taskID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("escalationService.Escalations: %w", err)
+	}
+	return escalations, total, nil
+}
+
+// bound is now+d, or nil when d disables the step.
+func bound(now time.Time, d time.Duration) *time.Time {
+	if d <= 0 {
+		return nil
+	}
+	t := now.Add(d)
+	return &t
+}
+
+// since is now-d, or nil when d disables the step.
+func since(now time.Time, d time.Duration) *time.Time {
+	if d <= 0 {
+		return nil
+	}
+	t := now.Add(-d)
+	return &t
+}
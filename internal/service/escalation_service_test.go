@@ -0,0 +1,110 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEscalationRepo struct {
+	domain.TaskEscalationRepository
+	candidates       []*domain.Task
+	mediumBy, highBy *time.Time
+	created          []*domain.TaskEscalation
+}
+
+func (f *fakeEscalationRepo) ListCandidates(_ context.Context, mediumBy, highBy *time.Time, _ int) ([]*domain.Task, error) {
+	f.mediumBy, f.highBy = mediumBy, highBy
+	return f.candidates, nil
+}
+
+func (f *fakeEscalationRepo) ListAgingCandidates(_ context.Context, mediumSince, highSince *time.Time, _ int) ([]*domain.Task, error) {
+	f.mediumBy, f.highBy = mediumSince, highSince
+	return f.candidates, nil
+}
+
+func (f *fakeEscalationRepo) Create(_ context.Context, e *domain.TaskEscalation) error {
+	f.created = append(f.created, e)
+	return nil
+}
+
+func TestEscalationService_Run(t *testing.T) {
+	due := time.Now().Add(6 * time.Hour)
+	userID := uuid.New()
+	low := &domain.Task{ID: uuid.New(), UserID: userID, Title: "File taxes", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, DueDate: &due}
+	optedOut := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Someday", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, DueDate: &due, NoEscalation: true}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, low.ID).Return(low, nil)
+	taskRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.ID == low.ID && t.Priority == domain.TaskPriorityHigh
+	}), mock.Anything).Return(nil)
+	escalationRepo := &fakeEscalationRepo{candidates: []*domain.Task{low, optedOut}}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewEscalationService(escalationRepo, newTaskService(taskRepo, &mockProjectRepo{}), domain.EscalationPolicy{HighWithin: 24 * time.Hour}, log)
+
+	require.NoError(t, svc.Run(context.Background()))
+
+	taskRepo.AssertNumberOfCalls(t, "Update", 1)
+	assert.Nil(t, escalationRepo.mediumBy, "the medium step is disabled")
+	require.NotNil(t, escalationRepo.highBy)
+	require.Len(t, escalationRepo.created, 1)
+	e := escalationRepo.created[0]
+	assert.Equal(t, low.ID, e.TaskID)
+	assert.Equal(t, domain.TaskPriorityLow, e.FromPriority)
+	assert.Equal(t, domain.TaskPriorityHigh, e.ToPriority)
+	assert.Equal(t, domain.EscalationDueDate, e.Reason)
+	require.NotNil(t, e.DueDate)
+	assert.Equal(t, due, *e.DueDate)
+}
+
+func TestEscalationService_RunDisabled(t *testing.T) {
+	escalationRepo := &fakeEscalationRepo{}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewEscalationService(escalationRepo, newTaskService(&mockTaskRepo{}, &mockProjectRepo{}), domain.EscalationPolicy{}, log)
+
+	require.NoError(t, svc.Run(context.Background()))
+	assert.Nil(t, escalationRepo.highBy)
+	assert.Empty(t, escalationRepo.created)
+}
+
+func TestEscalationService_RunAging(t *testing.T) {
+	userID := uuid.New()
+	neglected := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Clean garage", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, StatusChangedAt: time.Now().Add(-20 * 24 * time.Hour)}
+	recent := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Call mum", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, StatusChangedAt: time.Now().Add(-24 * time.Hour)}
+
+	taskRepo := &mockTaskRepo{}
+	maxPoints := 20.0
+	taskRepo.On("ApplyAging", mock.Anything, 7*24*time.Hour, 2.0, &maxPoints).Return(3, nil)
+	taskRepo.On("FindByID", mock.Anything, neglected.ID).Return(neglected, nil)
+	taskRepo.On("Update", mock.Anything, mock.MatchedBy(func(t *domain.Task) bool {
+		return t.ID == neglected.ID && t.Priority == domain.TaskPriorityMedium
+	}), mock.Anything).Return(nil)
+	escalationRepo := &fakeEscalationRepo{candidates: []*domain.Task{neglected, recent}}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewEscalationService(escalationRepo, newTaskService(taskRepo, &mockProjectRepo{}), domain.EscalationPolicy{}, log)
+	svc.UseAging(domain.AgingPolicy{After: 7 * 24 * time.Hour, PointsPerDay: 2, MaxPoints: 20, MediumAfter: 14 * 24 * time.Hour})
+
+	require.NoError(t, svc.RunAging(context.Background()))
+
+	taskRepo.AssertCalled(t, "ApplyAging", mock.Anything, 7*24*time.Hour, 2.0, &maxPoints)
+	taskRepo.AssertNumberOfCalls(t, "Update", 1)
+	assert.Nil(t, escalationRepo.highBy, "the high step is disabled")
+	require.Len(t, escalationRepo.created, 1)
+	e := escalationRepo.created[0]
+	assert.Equal(t, neglected.ID, e.TaskID)
+	assert.Equal(t, domain.TaskPriorityMedium, e.ToPriority)
+	assert.Equal(t, domain.EscalationAging, e.Reason)
+	assert.Nil(t, e.DueDate)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"maps"
+	"reflect"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jsonschema"
+)
+
+// EventCatalog lists the events webhooks deliver, with the JSON Schema of
+// each payload version, so integrators can validate against the contract.
+type EventCatalog struct {
+	PayloadVersions []string    `json:"payload_versions"`
+	LatestVersion   string      `json:"latest_version"`
+	Events          []EventType `json:"events"`
+}
+
+// EventType is one event of the catalog.
+type EventType struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// Payloads holds the payload schema by payload version.
+	Payloads map[string]*jsonschema.Schema `json:"payloads"`
+}
+
+var eventDescriptions = map[string]string{
+	domain.EventTaskCreated:   "A task was created.",
+	domain.EventTaskUpdated:   "A task's fields changed.",
+	domain.EventTaskCompleted: "A task was marked done; task.updated is delivered as well.",
+	domain.EventTaskDeleted:   "A task was deleted.",
+}
+
+// schemaReflector describes domain types, including the values their
+// string enumerations take.
+var schemaReflector = &jsonschema.Reflector{Enums: map[reflect.Type][]any{
+	reflect.TypeOf(domain.TaskStatus("")):          enumValues(domain.TaskStatusValues),
+	reflect.TypeOf(domain.TaskPriority("")):        enumValues(domain.TaskPriorityValues),
+	reflect.TypeOf(domain.TaskEnergy("")):          enumValues(domain.TaskEnergyValues),
+	reflect.TypeOf(domain.RecurrenceFrequency("")): enumValues(domain.RecurrenceFrequencies),
+}}
+
+func enumValues[T any](values []T) []any {
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = v
+	}
+	return out
+}
+
+// eventCatalog is built once; payload schemas only change with the code.
+var eventCatalog = buildEventCatalog()
+
+func buildEventCatalog() *EventCatalog {
+	versions := []string{domain.WebhookVersionV1, domain.WebhookVersionV2}
+	payloads := map[string]*jsonschema.Schema{
+		domain.WebhookVersionV1: schemaReflector.Reflect(webhookPayloadV1{}),
+		domain.WebhookVersionV2: schemaReflector.Reflect(webhookPayloadV2{}),
+	}
+	// typeField names the payload property that carries the event type.
+	typeField := map[string]string{domain.WebhookVersionV1: "event", domain.WebhookVersionV2: "type"}
+
+	catalog := &EventCatalog{PayloadVersions: versions, LatestVersion: domain.WebhookVersionLatest}
+	for _, event := range domain.WebhookEvents {
+		et := EventType{Type: event, Description: eventDescriptions[event], Payloads: map[string]*jsonschema.Schema{}}
+		for _, version := range versions {
+			schema := *payloads[version]
+			schema.Title = event + " " + version
+			schema.Properties = maps.Clone(schema.Properties)
+			schema.Properties[typeField[version]] = &jsonschema.Schema{Type: jsonschema.Types{"string"}, Const: event}
+			et.Payloads[version] = &schema
+		}
+		catalog.Events = append(catalog.Events, et)
+	}
+	return catalog
+}
+
+// Catalog returns the catalog of webhook events and their payload schemas.
+func (s *WebhookService) Catalog() *EventCatalog {
+	return eventCatalog
+}
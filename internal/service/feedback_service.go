@@ -0,0 +1,254 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// JobForwardFeedback is the job type used to POST a stored report to the
+// issue tracker webhook.
+const JobForwardFeedback = "feedback.forward"
+
+const (
+	feedbackTitleLength = 80
+	feedbackErrorLimit  = 1024
+)
+
+// FeedbackForwarding configures the optional issue tracker webhook that new
+// reports are POSTed to. An empty URL disables forwarding.
+type FeedbackForwarding struct {
+	URL     string
+	Token   string // sent as a bearer token when set
+	Timeout time.Duration
+}
+
+type forwardFeedbackJob struct {
+	FeedbackID uuid.UUID `json:"feedback_id"`
+}
+
+// feedbackForwardPayload is what the issue tracker webhook receives. Title
+// and Body are ready-made for trackers that just open an issue from them.
+type feedbackForwardPayload struct {
+	Title    string           `json:"title"`
+	Body     string           `json:"body"`
+	Feedback *domain.Feedback `json:"feedback"`
+	Reporter feedbackReporter `json:"reporter"`
+}
+
+type feedbackReporter struct {
+	ID    uuid.UUID `json:"id"`
+	Email string    `json:"email,omitempty"`
+	Name  string    `json:"name,omitempty"`
+}
+
+// FeedbackService stores bug reports and suggestions from clients, lets
+// admins browse them and forwards them to an issue tracker.
+type FeedbackService struct {
+	feedbackRepo domain.FeedbackRepository
+	userRepo     domain.UserRepository
+	queue        *jobs.Queue
+	forward      FeedbackForwarding
+	client       *http.Client
+	log          *logrus.Logger
+}
+
+// NewFeedbackService constructs a FeedbackService and registers its job
+// handler on queue.
+func NewFeedbackService(
+	feedbackRepo domain.FeedbackRepository,
+	userRepo domain.UserRepository,
+	queue *jobs.Queue,
+	forward FeedbackForwarding,
+	log *logrus.Logger,
+) *FeedbackService {
+	s := &FeedbackService{
+		feedbackRepo: feedbackRepo,
+		userRepo:     userRepo,
+		queue:        queue,
+		forward:      forward,
+		client:       &http.Client{Timeout: forward.Timeout},
+		log:          log,
+	}
+	queue.Register(JobForwardFeedback, s.handleForwardJob)
+	return s
+}
+
+// Submit stores a report from the user and, when forwarding is configured,
+// queues it for the issue tracker. A report that cannot be queued is still
+// kept for admins.
+func (s *FeedbackService) Submit(ctx context.Context, userID uuid.UUID, userAgent string, req *domain.CreateFeedbackRequest) (*domain.Feedback, error) {
+	message := strings.TrimSpace(req.Message)
+	if message == "" {
+		return nil, fmt.Errorf("feedbackService.Submit: message must not be blank: %w", domain.ErrValidation)
+	}
+
+	f := &domain.Feedback{
+		ID:            uuid.New(),
+		UserID:        userID,
+		Category:      req.Category,
+		Message:       message,
+		ClientVersion: strings.TrimSpace(req.ClientVersion),
+		UserAgent:     userAgent,
+		CreatedAt:     time.Now(),
+	}
+	if req.Diagnostics != nil {
+		f.Diagnostics = *req.Diagnostics
+	}
+	if err := s.feedbackRepo.Create(ctx, f); err != nil {
+		return nil, fmt.Errorf("feedbackService.Submit: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"feedback_id": f.ID, "user_id": userID, "category": f.Category}).Info("feedback received")
+	if s.forward.URL != "" {
+		if err := s.queue.Enqueue(ctx, JobForwardFeedback, forwardFeedbackJob{FeedbackID: f.ID}); err != nil {
+			s.log.WithError(err).WithField("feedback_id", f.ID).Warn("failed to queue feedback forwarding")
+		}
+	}
+	return f, nil
+}
+
+// List returns reports for admins, newest first.
+func (s *FeedbackService) List(ctx context.Context, filter domain.FeedbackFilter) ([]*domain.Feedback, int, error) {
+	reports, total, err := s.feedbackRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("feedbackService.List: %w", err)
+	}
+	return reports, total, nil
+}
+
+// GetByID returns one report for admins.
+func (s *FeedbackService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Feedback, error) {
+	f, err := s.feedbackRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("feedbackService.GetByID: %w", err)
+	}
+	return f, nil
+}
+
+// handleForwardJob POSTs a stored report to the issue tracker webhook and
+// records the outcome. Client errors other than 408/429 are not retried.
+func (s *FeedbackService) handleForwardJob(ctx context.Context, payload json.RawMessage) error {
+	var job forwardFeedbackJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("%w: decode feedback job: %w", jobs.ErrPermanent, err)
+	}
+
+	f, err := s.feedbackRepo.FindByID(ctx, job.FeedbackID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+		}
+		return err
+	}
+	if f.ForwardedAt != nil {
+		return nil
+	}
+
+	reporter := feedbackReporter{ID: f.UserID}
+	if user, err := s.userRepo.FindByID(ctx, f.UserID); err == nil {
+		reporter.Email, reporter.Name = user.Email, user.Name
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	sendErr := s.post(ctx, &feedbackForwardPayload{
+		Title:    feedbackTitle(f),
+		Body:     feedbackBody(f),
+		Feedback: f,
+		Reporter: reporter,
+	})
+
+	forwardErr := ""
+	if sendErr != nil {
+		forwardErr = sendErr.Error()
+		if len(forwardErr) > feedbackErrorLimit {
+			forwardErr = forwardErr[:feedbackErrorLimit]
+		}
+	}
+	if err := s.feedbackRepo.RecordForward(ctx, f.ID, time.Now(), forwardErr); err != nil {
+		s.log.WithError(err).WithField("feedback_id", f.ID).Error("failed to record feedback forwarding")
+	}
+
+	if sendErr != nil {
+		s.log.WithError(sendErr).WithField("feedback_id", f.ID).Warn("feedback forwarding failed")
+		return sendErr
+	}
+	s.log.WithField("feedback_id", f.ID).Info("feedback forwarded")
+	return nil
+}
+
+func (s *FeedbackService) post(ctx context.Context, payload *feedbackForwardPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: encode feedback: %w", jobs.ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.forward.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: build request: %w", jobs.ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-app-feedback/1")
+	if s.forward.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.forward.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post feedback: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusRequestTimeout, resp.StatusCode == http.StatusTooManyRequests, resp.StatusCode >= 500:
+		return fmt.Errorf("issue tracker responded %d", resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: issue tracker responded %d", jobs.ErrPermanent, resp.StatusCode)
+	}
+}
+
+// feedbackTitle is the category and the start of the message's first line,
+// e.g. "[bug] Reminders fire twice after changing time zone".
+func feedbackTitle(f *domain.Feedback) string {
+	line, _, _ := strings.Cut(f.Message, "\n")
+	line = strings.TrimSpace(line)
+	if r := []rune(line); len(r) > feedbackTitleLength {
+		line = strings.TrimSpace(string(r[:feedbackTitleLength-1])) + "…"
+	}
+	return fmt.Sprintf("[%s] %s", f.Category, line)
+}
+
+// feedbackBody is the message followed by the diagnostics, in Markdown.
+func feedbackBody(f *domain.Feedback) string {
+	var b strings.Builder
+	b.WriteString(f.Message)
+	b.WriteString("\n\n---\n")
+	fmt.Fprintf(&b, "- Feedback ID: %s\n", f.ID)
+	if f.ClientVersion != "" {
+		fmt.Fprintf(&b, "- Client version: %s\n", f.ClientVersion)
+	}
+	if d := f.Diagnostics; d.Platform != "" {
+		fmt.Fprintf(&b, "- Platform: %s\n", d.Platform)
+	}
+	if f.UserAgent != "" {
+		fmt.Fprintf(&b, "- User agent: %s\n", f.UserAgent)
+	}
+	if ids := f.Diagnostics.RequestIDs; len(ids) > 0 {
+		fmt.Fprintf(&b, "- Request IDs: %s\n", strings.Join(ids, ", "))
+	}
+	return b.String()
+}
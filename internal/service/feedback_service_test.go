@@ -0,0 +1,128 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeFeedbackRepo struct {
+	domain.FeedbackRepository
+	mu        sync.Mutex
+	reports   []*domain.Feedback
+	forwarded chan string // receives the forward error of every RecordForward
+}
+
+func (f *fakeFeedbackRepo) Create(_ context.Context, fb *domain.Feedback) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reports = append(f.reports, fb)
+	return nil
+}
+
+func (f *fakeFeedbackRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.Feedback, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, fb := range f.reports {
+		if fb.ID == id {
+			copied := *fb
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeFeedbackRepo) RecordForward(_ context.Context, _ uuid.UUID, _ time.Time, forwardErr string) error {
+	f.forwarded <- forwardErr
+	return nil
+}
+
+func newFeedbackService(t *testing.T, repo *fakeFeedbackRepo, forward service.FeedbackForwarding) *service.FeedbackService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	queue := jobs.New(jobs.Config{BufferSize: 10}, log)
+	svc := service.NewFeedbackService(repo, fakeUserRepo{}, queue, forward, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		queue.Stop(context.Background())
+	})
+	return svc
+}
+
+func TestFeedbackService_Submit_ForwardsToIssueTracker(t *testing.T) {
+	type received struct {
+		auth    string
+		payload map[string]any
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		got <- received{auth: r.Header.Get("Authorization"), payload: p}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	repo := &fakeFeedbackRepo{forwarded: make(chan string, 1)}
+	svc := newFeedbackService(t, repo, service.FeedbackForwarding{URL: srv.URL, Token: "s3cret", Timeout: time.Second})
+
+	f, err := svc.Submit(context.Background(), uuid.New(), "TodoApp/2.3 (iOS)", &domain.CreateFeedbackRequest{
+		Category:      domain.FeedbackBug,
+		Message:       "  Reminders fire twice\nafter I change time zone.  ",
+		ClientVersion: "2.3.0",
+		Diagnostics:   &domain.FeedbackDiagnostics{RequestIDs: []string{"req-1", "req-2"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Reminders fire twice\nafter I change time zone.", f.Message)
+	assert.Equal(t, "TodoApp/2.3 (iOS)", f.UserAgent)
+
+	select {
+	case r := <-got:
+		assert.Equal(t, "Bearer s3cret", r.auth)
+		assert.Equal(t, "[bug] Reminders fire twice", r.payload["title"])
+		assert.Contains(t, r.payload["body"], "Request IDs: req-1, req-2")
+		assert.Equal(t, f.ID.String(), r.payload["feedback"].(map[string]any)["id"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("feedback was not forwarded")
+	}
+	select {
+	case forwardErr := <-repo.forwarded:
+		assert.Empty(t, forwardErr)
+	case <-time.After(2 * time.Second):
+		t.Fatal("forwarding outcome was not recorded")
+	}
+}
+
+func TestFeedbackService_Submit_StoresWithoutForwarding(t *testing.T) {
+	repo := &fakeFeedbackRepo{forwarded: make(chan string, 1)}
+	svc := newFeedbackService(t, repo, service.FeedbackForwarding{})
+
+	_, err := svc.Submit(context.Background(), uuid.New(), "", &domain.CreateFeedbackRequest{Category: domain.FeedbackIdea, Message: "   "})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	f, err := svc.Submit(context.Background(), uuid.New(), "", &domain.CreateFeedbackRequest{Category: domain.FeedbackIdea, Message: "Dark mode please"})
+	require.NoError(t, err)
+	assert.Len(t, repo.reports, 1)
+	assert.Empty(t, f.Diagnostics.RequestIDs)
+	assert.Nil(t, f.ForwardedAt)
+	select {
+	case <-repo.forwarded:
+		t.Fatal("nothing should be forwarded without a URL")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
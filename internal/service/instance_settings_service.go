@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// mailTestTimeout bounds the test email, which is sent while the admin waits.
+const mailTestTimeout = 15 * time.Second
+
+// InstanceSettingsService lets admins change instance settings without a
+// redeploy. Stored overrides replace the environment's values in the
+// services that use them as soon as they are saved, and on startup.
+type InstanceSettingsService struct {
+	repo        domain.InstanceSettingsRepository
+	userRepo    domain.UserRepository
+	invites     *InviteService
+	attachments *AttachmentService
+	revisions   *TaskRevisionService
+	mailer      mailer.Mailer
+	mail        domain.InstanceMail
+	log         *logrus.Logger
+
+	// env holds the values from the environment, read from the services
+	// before any override is applied.
+	env    domain.InstanceSettings
+	mu     sync.RWMutex
+	stored domain.InstanceOverrides
+}
+
+// NewInstanceSettingsService constructs an InstanceSettingsService over the
+// services whose settings it manages. mailFrom is the configured sender.
+func NewInstanceSettingsService(
+	repo domain.InstanceSettingsRepository,
+	userRepo domain.UserRepository,
+	invites *InviteService,
+	attachments *AttachmentService,
+	revisions *TaskRevisionService,
+	m mailer.Mailer,
+	mailFrom string,
+	log *logrus.Logger,
+) *InstanceSettingsService {
+	policy := invites.Policy()
+	return &InstanceSettingsService{
+		repo: repo, userRepo: userRepo, invites: invites, attachments: attachments, revisions: revisions,
+		mailer: m, mail: domain.InstanceMail{Driver: m.Name(), From: mailFrom}, log: log,
+		env: domain.InstanceSettings{
+			SignupMode:     policy.Mode,
+			InviteQuota:    policy.InviteQuota,
+			MaxUploadBytes: attachments.MaxBytes(),
+			RevisionsKeep:  revisions.Keep(),
+		},
+	}
+}
+
+// Load reads the stored overrides and applies them. Call it at startup and
+// whenever the stored overrides are replaced underneath, as by a restore.
+func (s *InstanceSettingsService) Load(ctx context.Context) error {
+	o, err := s.repo.Get(ctx)
+	if errors.Is(err, domain.ErrNotFound) {
+		s.apply(domain.InstanceOverrides{})
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("instanceSettingsService.Load: %w", err)
+	}
+	s.apply(*o)
+	return nil
+}
+
+// Get returns the settings in force.
+func (s *InstanceSettingsService) Get() *domain.InstanceSettings {
+	s.mu.RLock()
+	o := s.stored
+	s.mu.RUnlock()
+
+	settings := s.env
+	settings.Mail = s.mail
+	settings.Overridden = []string{}
+	if o.SignupMode != nil {
+		settings.SignupMode = *o.SignupMode
+		settings.Overridden = append(settings.Overridden, domain.SettingSignupMode)
+	}
+	if o.InviteQuota != nil {
+		settings.InviteQuota = *o.InviteQuota
+		settings.Overridden = append(settings.Overridden, domain.SettingInviteQuota)
+	}
+	if o.MaxUploadBytes != nil {
+		settings.MaxUploadBytes = *o.MaxUploadBytes
+		settings.Overridden = append(settings.Overridden, domain.SettingMaxUploadBytes)
+	}
+	if o.RevisionsKeep != nil {
+		settings.RevisionsKeep = *o.RevisionsKeep
+		settings.Overridden = append(settings.Overridden, domain.SettingRevisionsKeep)
+	}
+	if o.DisabledModules != nil {
+		settings.Overridden = append(settings.Overridden, domain.SettingModules)
+	}
+	settings.Modules = make(map[string]bool, len(domain.Modules))
+	for _, m := range domain.Modules {
+		settings.Modules[m] = !slices.Contains(o.DisabledModules, m)
+	}
+	if !o.UpdatedAt.IsZero() {
+		settings.UpdatedAt = &o.UpdatedAt
+	}
+	return &settings
+}
+
+// Update stores the given overrides, drops those listed in Reset, and
+// applies the result at once.
+func (s *InstanceSettingsService) Update(ctx context.Context, req *domain.UpdateInstanceSettingsRequest) (*domain.InstanceSettings, error) {
+	for name := range req.Modules {
+		if !slices.Contains(domain.Modules, name) {
+			return nil, fmt.Errorf("instanceSettingsService.Update: unknown module %q: %w", name, domain.ErrValidation)
+		}
+	}
+
+	s.mu.RLock()
+	o := s.stored
+	s.mu.RUnlock()
+
+	for _, name := range req.Reset {
+		switch name {
+		case domain.SettingSignupMode:
+			o.SignupMode = nil
+		case domain.SettingInviteQuota:
+			o.InviteQuota = nil
+		case domain.SettingMaxUploadBytes:
+			o.MaxUploadBytes = nil
+		case domain.SettingRevisionsKeep:
+			o.RevisionsKeep = nil
+		case domain.SettingModules:
+			o.DisabledModules = nil
+		}
+	}
+	if req.SignupMode != nil {
+		o.SignupMode = req.SignupMode
+	}
+	if req.InviteQuota != nil {
+		o.InviteQuota = req.InviteQuota
+	}
+	if req.MaxUploadBytes != nil {
+		o.MaxUploadBytes = req.MaxUploadBytes
+	}
+	if req.RevisionsKeep != nil {
+		o.RevisionsKeep = req.RevisionsKeep
+	}
+	if len(req.Modules) > 0 {
+		disabled := []string{}
+		for _, m := range domain.Modules {
+			on, set := req.Modules[m]
+			if (set && !on) || (!set && slices.Contains(o.DisabledModules, m)) {
+				disabled = append(disabled, m)
+			}
+		}
+		o.DisabledModules = disabled
+	}
+	o.UpdatedAt = time.Now()
+
+	if err := s.repo.Save(ctx, &o); err != nil {
+		return nil, fmt.Errorf("instanceSettingsService.Update: %w", err)
+	}
+	s.apply(o)
+	return s.Get(), nil
+}
+
+// ModuleEnabled reports whether a module is switched on.
+func (s *InstanceSettingsService) ModuleEnabled(module string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return !slices.Contains(s.stored.DisabledModules, module)
+}
+
+// TestMail sends a test email through the configured backend right away,
+// bypassing the job queue, to check the mail settings. It goes to the
+// admin's own address unless to is given. A rejected send is reported in
+// the result rather than as an error.
+func (s *InstanceSettingsService) TestMail(ctx context.Context, userID uuid.UUID, to string) (*domain.MailTestResult, error) {
+	if to == "" {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("instanceSettingsService.TestMail: %w", err)
+		}
+		to = user.Email
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, mailTestTimeout)
+	defer cancel()
+	result := &domain.MailTestResult{Driver: s.mail.Driver, To: to}
+	err := s.mailer.Send(ctx, &mailer.Message{
+		To:      to,
+		Subject: "Test email",
+		Text:    "This is a test email sent from the instance settings. If you can read it, outgoing mail works.",
+		HTML:    "<p>This is a test email sent from the instance settings. If you can read it, outgoing mail works.</p>",
+	})
+	if err != nil {
+		s.log.WithError(err).WithField("driver", s.mail.Driver).Warn("test email failed")
+		result.Error = err.Error()
+		return result, nil
+	}
+	result.Delivered = true
+	return result, nil
+}
+
+func (s *InstanceSettingsService) apply(o domain.InstanceOverrides) {
+	s.mu.Lock()
+	s.stored = o
+	s.mu.Unlock()
+
+	settings := s.Get()
+	s.invites.SetPolicy(SignupPolicy{Mode: settings.SignupMode, InviteQuota: settings.InviteQuota})
+	s.attachments.SetMaxBytes(settings.MaxUploadBytes)
+	s.revisions.SetKeep(settings.RevisionsKeep)
+}
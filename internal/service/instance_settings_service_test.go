@@ -0,0 +1,116 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInstanceSettingsRepo struct {
+	domain.InstanceSettingsRepository
+	saved *domain.InstanceOverrides
+}
+
+func (r *fakeInstanceSettingsRepo) Get(ctx context.Context) (*domain.InstanceOverrides, error) {
+	if r.saved == nil {
+		return nil, domain.ErrNotFound
+	}
+	o := *r.saved
+	return &o, nil
+}
+
+func (r *fakeInstanceSettingsRepo) Save(ctx context.Context, o *domain.InstanceOverrides) error {
+	saved := *o
+	r.saved = &saved
+	return nil
+}
+
+type fakeMailer struct {
+	sent []*mailer.Message
+	err  error
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+func (m *fakeMailer) Name() string { return "fake" }
+
+func TestInstanceSettingsService_Update(t *testing.T) {
+	log := logrus.New()
+	invites := service.NewInviteService(nil, nil, service.SignupPolicy{Mode: domain.SignupOpen, InviteQuota: 5}, log)
+	attachments := service.NewAttachmentService(nil, nil, nil, service.AttachmentPolicy{MaxBytes: 10 << 20}, log)
+	revisions := service.NewTaskRevisionService(nil, nil, 10, log)
+	repo := &fakeInstanceSettingsRepo{}
+	svc := service.NewInstanceSettingsService(repo, fakeUserRepo{}, invites, attachments, revisions, &fakeMailer{}, "no-reply@localhost", log)
+	ctx := context.Background()
+	require.NoError(t, svc.Load(ctx))
+
+	got := svc.Get()
+	assert.Equal(t, domain.SignupOpen, got.SignupMode)
+	assert.Empty(t, got.Overridden)
+	assert.True(t, got.Modules[domain.ModuleWebhooks])
+
+	inviteOnly, quota := domain.SignupInviteOnly, 0
+	got, err := svc.Update(ctx, &domain.UpdateInstanceSettingsRequest{
+		SignupMode:  &inviteOnly,
+		InviteQuota: &quota,
+		Modules:     map[string]bool{domain.ModuleWebhooks: false},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{domain.SettingSignupMode, domain.SettingInviteQuota, domain.SettingModules}, got.Overridden)
+	assert.Equal(t, service.SignupPolicy{Mode: domain.SignupInviteOnly, InviteQuota: 0}, invites.Policy(), "applied at once")
+	assert.False(t, svc.ModuleEnabled(domain.ModuleWebhooks))
+	assert.True(t, svc.ModuleEnabled(domain.ModuleAttachments))
+
+	// Switching another module off keeps webhooks off.
+	_, err = svc.Update(ctx, &domain.UpdateInstanceSettingsRequest{Modules: map[string]bool{domain.ModuleFeedback: false}})
+	require.NoError(t, err)
+	assert.False(t, svc.ModuleEnabled(domain.ModuleWebhooks))
+	assert.False(t, svc.ModuleEnabled(domain.ModuleFeedback))
+
+	// A fresh instance picks the overrides up.
+	other := service.NewInstanceSettingsService(repo, fakeUserRepo{}, service.NewInviteService(nil, nil, service.SignupPolicy{Mode: domain.SignupOpen, InviteQuota: 5}, log),
+		attachments, revisions, &fakeMailer{}, "no-reply@localhost", log)
+	require.NoError(t, other.Load(ctx))
+	assert.Equal(t, domain.SignupInviteOnly, other.Get().SignupMode)
+
+	got, err = svc.Update(ctx, &domain.UpdateInstanceSettingsRequest{Reset: []string{domain.SettingSignupMode, domain.SettingModules}})
+	require.NoError(t, err)
+	assert.Equal(t, domain.SignupOpen, got.SignupMode, "reset restores the environment's value")
+	assert.Equal(t, 0, got.InviteQuota)
+	assert.True(t, svc.ModuleEnabled(domain.ModuleWebhooks))
+
+	_, err = svc.Update(ctx, &domain.UpdateInstanceSettingsRequest{Modules: map[string]bool{"chat": true}})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestInstanceSettingsService_TestMail(t *testing.T) {
+	log := logrus.New()
+	m := &fakeMailer{}
+	svc := service.NewInstanceSettingsService(&fakeInstanceSettingsRepo{}, fakeUserRepo{},
+		service.NewInviteService(nil, nil, service.SignupPolicy{Mode: domain.SignupOpen}, log),
+		service.NewAttachmentService(nil, nil, nil, service.AttachmentPolicy{}, log),
+		service.NewTaskRevisionService(nil, nil, 0, log), m, "no-reply@localhost", log)
+
+	result, err := svc.TestMail(context.Background(), uuid.New(), "admin@example.com")
+	require.NoError(t, err)
+	assert.True(t, result.Delivered)
+	require.Len(t, m.sent, 1)
+	assert.Equal(t, "admin@example.com", m.sent[0].To)
+
+	m.err = errors.New("535 authentication failed")
+	result, err = svc.TestMail(context.Background(), uuid.New(), "admin@example.com")
+	require.NoError(t, err)
+	assert.False(t, result.Delivered)
+	assert.Equal(t, "535 authentication failed", result.Error)
+}
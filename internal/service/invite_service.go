@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// inviteAlphabet leaves out characters that are easily confused (0/O, 1/I).
+	inviteAlphabet   = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLength = 12
+	// inviteGroup is how many characters are shown between dashes.
+	inviteGroup = 4
+)
+
+// SignupPolicy controls who may register and how many invites users get.
+type SignupPolicy struct {
+	Mode        string // domain.SignupOpen or domain.SignupInviteOnly
+	InviteQuota int    // codes each non-admin user may create; 0 allows none
+}
+
+// InviteService manages invite codes and redeems them at signup.
+type InviteService struct {
+	inviteRepo domain.InviteRepository
+	userRepo   domain.UserRepository
+	log        *logrus.Logger
+
+	mu     sync.RWMutex
+	policy SignupPolicy
+}
+
+// NewInviteService constructs an InviteService.
+func NewInviteService(inviteRepo domain.InviteRepository, userRepo domain.UserRepository, policy SignupPolicy, log *logrus.Logger) *InviteService {
+	return &InviteService{inviteRepo: inviteRepo, userRepo: userRepo, policy: policy, log: log}
+}
+
+// Policy returns the signup policy in force.
+func (s *InviteService) Policy() SignupPolicy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.policy
+}
+
+// SetPolicy replaces the signup policy; it applies to the next signup or
+// invite.
+func (s *InviteService) SetPolicy(p SignupPolicy) {
+	s.mu.Lock()
+	s.policy = p
+	s.mu.Unlock()
+}
+
+// Create makes a new invite code. Non-admins are limited to single-use
+// codes within their quota.
+func (s *InviteService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateInviteRequest) (*domain.InviteCode, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("inviteService.Create: %w", err)
+	}
+	maxUses := req.MaxUses
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if !user.IsAdmin {
+		if maxUses > 1 {
+			return nil, fmt.Errorf("inviteService.Create: only admins can create multi-use codes: %w", domain.ErrForbidden)
+		}
+		n, err := s.inviteRepo.CountCharged(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("inviteService.Create: %w", err)
+		}
+		if n >= s.Policy().InviteQuota {
+			return nil, fmt.Errorf("inviteService.Create: %w", domain.ErrQuotaExceeded)
+		}
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		return nil, fmt.Errorf("inviteService.Create: %w", err)
+	}
+	now := time.Now()
+	invite := &domain.InviteCode{
+		ID:        uuid.New(),
+		Code:      code,
+		CreatedBy: userID,
+		MaxUses:   maxUses,
+		CreatedAt: now,
+	}
+	if req.ExpiresInDays > 0 {
+		expires := now.AddDate(0, 0, req.ExpiresInDays)
+		invite.ExpiresAt = &expires
+	}
+	if err := s.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, fmt.Errorf("inviteService.Create: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"invite_id": invite.ID, "user_id": userID, "max_uses": maxUses}).Info("invite code created")
+	return invite, nil
+}
+
+// List returns the user's invite codes, newest first, with their quota.
+func (s *InviteService) List(ctx context.Context, userID uuid.UUID) (*domain.InviteSummary, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("inviteService.List: %w", err)
+	}
+	codes, err := s.inviteRepo.ListByCreator(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("inviteService.List: %w", err)
+	}
+	summary := &domain.InviteSummary{Codes: codes}
+	if !user.IsAdmin {
+		n, err := s.inviteRepo.CountCharged(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("inviteService.List: %w", err)
+		}
+		quota := s.Policy().InviteQuota
+		remaining := quota - n
+		if remaining < 0 {
+			remaining = 0
+		}
+		summary.Quota, summary.Remaining = &quota, &remaining
+	}
+	return summary, nil
+}
+
+// Revoke stops a code from being redeemed again. Revoking a code nobody has
+// used gives its quota back.
+func (s *InviteService) Revoke(ctx context.Context, id, userID uuid.UUID) error {
+	invite, err := s.inviteRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if invite.CreatedBy != userID {
+		return domain.ErrForbidden
+	}
+	if err := s.inviteRepo.Revoke(ctx, invite.ID, time.Now()); err != nil {
+		return fmt.Errorf("inviteService.Revoke: %w", err)
+	}
+	return nil
+}
+
+// Redeem takes a use of the code a new user signs up with. An empty code is
+// accepted, returning nil, unless signup is invite-only.
+func (s *InviteService) Redeem(ctx context.Context, code string) (*domain.InviteCode, error) {
+	code = normalizeInviteCode(code)
+	if code == "" {
+		if s.Policy().Mode == domain.SignupInviteOnly {
+			return nil, domain.ErrInviteRequired
+		}
+		return nil, nil
+	}
+	invite, err := s.inviteRepo.Redeem(ctx, code, time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrInviteInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("inviteService.Redeem: %w", err)
+	}
+	return invite, nil
+}
+
+// Release gives back a use taken by Redeem when the signup did not go
+// through. Failures are logged; the code just ends up one use short.
+func (s *InviteService) Release(ctx context.Context, invite *domain.InviteCode) {
+	if invite == nil {
+		return
+	}
+	if err := s.inviteRepo.Release(ctx, invite.ID); err != nil {
+		s.log.WithError(err).WithField("invite_id", invite.ID).Warn("failed to release invite code")
+	}
+}
+
+// generateInviteCode returns a random code such as "K7QD-M2XP-9HTA".
+func generateInviteCode() (string, error) {
+	s, err := randomCode(inviteCodeLength)
+	if err != nil {
+		return "", fmt.Errorf("generate invite code: %w", err)
+	}
+	return normalizeInviteCode(s), nil
+}
+
+// randomCode returns n random characters from inviteAlphabet.
+func randomCode(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i, v := range b {
+		// 256 is a multiple of the alphabet size, so this is unbiased.
+		b[i] = inviteAlphabet[int(v)%len(inviteAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeInviteCode accepts codes typed in lower case or without dashes.
+func normalizeInviteCode(code string) string {
+	var plain strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		if r != '-' && r != ' ' {
+			plain.WriteRune(r)
+		}
+	}
+	s := plain.String()
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if i > 0 && i%inviteGroup == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeInviteRepo struct {
+	domain.InviteRepository
+	codes []*domain.InviteCode
+}
+
+func (f *fakeInviteRepo) Create(_ context.Context, c *domain.InviteCode) error {
+	f.codes = append(f.codes, c)
+	return nil
+}
+
+func (f *fakeInviteRepo) ListByCreator(_ context.Context, userID uuid.UUID) ([]*domain.InviteCode, error) {
+	var out []*domain.InviteCode
+	for _, c := range f.codes {
+		if c.CreatedBy == userID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeInviteRepo) CountCharged(_ context.Context, userID uuid.UUID) (int, error) {
+	n := 0
+	for _, c := range f.codes {
+		if c.CreatedBy == userID && (c.RevokedAt == nil || c.Uses > 0) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeInviteRepo) Redeem(_ context.Context, code string, now time.Time) (*domain.InviteCode, error) {
+	for _, c := range f.codes {
+		if c.Code == code && c.Usable(now) {
+			c.Uses++
+			return c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+type inviteUserRepo struct {
+	domain.UserRepository
+	admins map[uuid.UUID]bool
+}
+
+func (f inviteUserRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id, IsAdmin: f.admins[id]}, nil
+}
+
+func newInviteService(repo *fakeInviteRepo, admins map[uuid.UUID]bool, mode string) *service.InviteService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewInviteService(repo, inviteUserRepo{admins: admins}, service.SignupPolicy{Mode: mode, InviteQuota: 2}, log)
+}
+
+func TestInviteService_CreateEnforcesQuota(t *testing.T) {
+	user, admin := uuid.New(), uuid.New()
+	svc := newInviteService(&fakeInviteRepo{}, map[uuid.UUID]bool{admin: true}, domain.SignupInviteOnly)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		invite, err := svc.Create(ctx, user, &domain.CreateInviteRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, 1, invite.MaxUses)
+		assert.Regexp(t, `^[A-Z2-9]{4}-[A-Z2-9]{4}-[A-Z2-9]{4}$`, invite.Code)
+	}
+	_, err := svc.Create(ctx, user, &domain.CreateInviteRequest{})
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	_, err = svc.Create(ctx, uuid.New(), &domain.CreateInviteRequest{MaxUses: 10})
+	assert.ErrorIs(t, err, domain.ErrForbidden, "multi-use codes are admin-only")
+
+	for i := 0; i < 3; i++ {
+		_, err := svc.Create(ctx, admin, &domain.CreateInviteRequest{MaxUses: 10})
+		require.NoError(t, err, "admins have no quota")
+	}
+	summary, err := svc.List(ctx, user)
+	require.NoError(t, err)
+	assert.Len(t, summary.Codes, 2)
+	assert.Equal(t, 0, *summary.Remaining)
+	summary, err = svc.List(ctx, admin)
+	require.NoError(t, err)
+	assert.Nil(t, summary.Quota)
+}
+
+func TestInviteService_Redeem(t *testing.T) {
+	repo := &fakeInviteRepo{}
+	userID := uuid.New()
+	ctx := context.Background()
+
+	open := newInviteService(repo, nil, domain.SignupOpen)
+	invite, err := open.Redeem(ctx, "")
+	require.NoError(t, err)
+	assert.Nil(t, invite, "open signup needs no code")
+
+	closed := newInviteService(repo, nil, domain.SignupInviteOnly)
+	_, err = closed.Redeem(ctx, "  ")
+	assert.ErrorIs(t, err, domain.ErrInviteRequired)
+
+	created, err := closed.Create(ctx, userID, &domain.CreateInviteRequest{})
+	require.NoError(t, err)
+	typed := strings.ToLower(strings.ReplaceAll(created.Code, "-", ""))
+	invite, err = closed.Redeem(ctx, typed)
+	require.NoError(t, err)
+	assert.Equal(t, created.ID, invite.ID)
+
+	_, err = closed.Redeem(ctx, created.Code)
+	assert.ErrorIs(t, err, domain.ErrInviteInvalid, "single-use code is used up")
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// loadWindow is how many recent probes the health signals cover.
+	loadWindow = 12
+	// minLoadSamples keeps a single failed probe at startup from
+	// reading as a 100% error rate.
+	minLoadSamples = 3
+	// probeTimeout bounds a probe; one that runs out counts as failed.
+	probeTimeout = 2 * time.Second
+)
+
+// loadSample is the outcome of one health probe.
+type loadSample struct {
+	latency time.Duration
+	failed  bool
+}
+
+// LoadShedder probes the database and decides, from the latency and error
+// rate of recent probes, whether the API should shed low-priority requests.
+// Each instance probes and decides for itself, so thresholds set at runtime
+// apply to the instance that received them.
+type LoadShedder struct {
+	maintenanceRepo domain.MaintenanceRepository
+	retryAfter      time.Duration
+	log             *logrus.Logger
+
+	mu         sync.Mutex
+	thresholds domain.SheddingThresholds
+	samples    []loadSample // ring of the last loadWindow probes
+	next       int
+	shedding   bool
+	since      time.Time
+}
+
+// NewLoadShedder constructs a LoadShedder for probes every probeInterval.
+// Shed clients are told to retry after half a window of probes, about the
+// soonest that healthy ones can bring the signals back under the thresholds.
+func NewLoadShedder(maintenanceRepo domain.MaintenanceRepository, thresholds domain.SheddingThresholds, probeInterval time.Duration, log *logrus.Logger) *LoadShedder {
+	return &LoadShedder{maintenanceRepo: maintenanceRepo, thresholds: thresholds, retryAfter: probeInterval * loadWindow / 2, log: log}
+}
+
+// Probe times a trivial database query and re-evaluates shedding. A failed
+// query is recorded, not returned, so the job itself never fails. Intended
+// to be run by the scheduler.
+func (s *LoadShedder) Probe(ctx context.Context) error {
+	pctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	start := time.Now()
+	err := s.maintenanceRepo.Ping(pctx)
+	latency := time.Since(start)
+	cancel()
+	if err != nil && ctx.Err() != nil {
+		return nil // shutting down
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sample := loadSample{latency: latency, failed: err != nil}
+	if len(s.samples) < loadWindow {
+		s.samples = append(s.samples, sample)
+	} else {
+		s.samples[s.next] = sample
+	}
+	s.next = (s.next + 1) % loadWindow
+	s.evaluate()
+	return nil
+}
+
+// Shedding reports whether low-priority requests should be turned away, and
+// how long to tell their clients to wait.
+func (s *LoadShedder) Shedding() (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.shedding, s.retryAfter
+}
+
+// Status returns the current health signals and thresholds.
+func (s *LoadShedder) Status() *domain.LoadStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	latency, errorPercent := s.signals()
+	status := &domain.LoadStatus{
+		Shedding:     s.shedding,
+		LatencyMs:    float64(latency.Microseconds()) / 1000,
+		ErrorPercent: errorPercent,
+		Samples:      len(s.samples),
+		Thresholds:   s.thresholds,
+	}
+	if !s.since.IsZero() {
+		since := s.since
+		status.Since = &since
+	}
+	return status
+}
+
+// SetThresholds replaces the thresholds and applies them at once.
+func (s *LoadShedder) SetThresholds(t domain.SheddingThresholds) *domain.LoadStatus {
+	s.mu.Lock()
+	s.thresholds = t
+	s.evaluate()
+	s.mu.Unlock()
+	return s.Status()
+}
+
+// signals returns the mean latency of successful probes and the percentage
+// that failed. Callers hold mu.
+func (s *LoadShedder) signals() (time.Duration, float64) {
+	if len(s.samples) == 0 {
+		return 0, 0
+	}
+	var total time.Duration
+	ok, failed := 0, 0
+	for _, sample := range s.samples {
+		if sample.failed {
+			failed++
+			continue
+		}
+		total += sample.latency
+		ok++
+	}
+	var mean time.Duration
+	if ok > 0 {
+		mean = total / time.Duration(ok)
+	}
+	return mean, float64(failed) * 100 / float64(len(s.samples))
+}
+
+// evaluate turns shedding on or off from the current samples, logging each
+// change. Callers hold mu.
+func (s *LoadShedder) evaluate() {
+	shed := false
+	latency, errorPercent := s.signals()
+	if len(s.samples) >= minLoadSamples {
+		t := s.thresholds
+		shed = (t.MaxLatencyMs > 0 && latency > time.Duration(t.MaxLatencyMs)*time.Millisecond) ||
+			(t.MaxErrorPercent > 0 && errorPercent > float64(t.MaxErrorPercent))
+	}
+	if shed == s.shedding {
+		return
+	}
+	s.shedding, s.since = shed, time.Now()
+	entry := s.log.WithFields(logrus.Fields{"latency": latency.String(), "error_percent": errorPercent})
+	if shed {
+		entry.Warn("database degraded; shedding low-priority requests")
+	} else {
+		entry.Info("database recovered; no longer shedding requests")
+	}
+}
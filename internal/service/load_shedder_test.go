@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProbeRepo only answers health probes, after delay or with err.
+type fakeProbeRepo struct {
+	domain.MaintenanceRepository
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeProbeRepo) Ping(context.Context) error {
+	time.Sleep(f.delay)
+	return f.err
+}
+
+func TestLoadShedder_ShedsOnErrorsAndRecovers(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	db := &fakeProbeRepo{err: errors.New("connection refused")}
+	shedder := service.NewLoadShedder(db, domain.SheddingThresholds{MaxErrorPercent: 25}, 5*time.Second, log)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, shedder.Probe(ctx))
+	}
+	shed, _ := shedder.Shedding()
+	assert.False(t, shed, "too few probes to judge")
+
+	require.NoError(t, shedder.Probe(ctx))
+	shed, retryAfter := shedder.Shedding()
+	assert.True(t, shed)
+	assert.Equal(t, 30*time.Second, retryAfter)
+	assert.Equal(t, 100.0, shedder.Status().ErrorPercent)
+
+	// Healthy probes dilute the failures: 3 of 12 is 25%, not above it.
+	db.err = nil
+	for i := 0; i < 9; i++ {
+		require.NoError(t, shedder.Probe(ctx))
+	}
+	status := shedder.Status()
+	assert.False(t, status.Shedding)
+	assert.Equal(t, 12, status.Samples)
+	assert.Equal(t, 25.0, status.ErrorPercent)
+	require.NotNil(t, status.Since)
+}
+
+func TestLoadShedder_SetThresholdsAppliesAtOnce(t *testing.T) {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	db := &fakeProbeRepo{delay: 5 * time.Millisecond}
+	shedder := service.NewLoadShedder(db, domain.SheddingThresholds{MaxLatencyMs: 1000}, time.Second, log)
+	for i := 0; i < 3; i++ {
+		require.NoError(t, shedder.Probe(context.Background()))
+	}
+	shed, _ := shedder.Shedding()
+	assert.False(t, shed)
+
+	status := shedder.SetThresholds(domain.SheddingThresholds{MaxLatencyMs: 1})
+	assert.True(t, status.Shedding)
+	assert.GreaterOrEqual(t, status.LatencyMs, 5.0)
+
+	status = shedder.SetThresholds(domain.SheddingThresholds{})
+	assert.False(t, status.Shedding, "zero thresholds disable shedding")
+}
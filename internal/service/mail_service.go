@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/sirupsen/logrus"
+)
+
+// JobSendEmail is the job type used to deliver a rendered email.
+const JobSendEmail = "email.send"
+
+// MailService renders templated emails and delivers them through the job queue.
+type MailService struct {
+	mailer       mailer.Mailer
+	renderer     *email.Renderer
+	queue        *jobs.Queue
+	suppressions domain.EmailSuppressionRepository
+	branding     *BrandingService
+	log          *logrus.Logger
+}
+
+// NewMailService constructs a MailService and registers its job handler on queue.
+func NewMailService(
+	m mailer.Mailer,
+	renderer *email.Renderer,
+	queue *jobs.Queue,
+	suppressions domain.EmailSuppressionRepository,
+	log *logrus.Logger,
+) *MailService {
+	s := &MailService{mailer: m, renderer: renderer, queue: queue, suppressions: suppressions, log: log}
+	queue.Register(JobSendEmail, s.handleSendJob)
+	return s
+}
+
+// UseBranding sends mail as the workspace, from its custom domain once
+// verified. Must be called before serving requests.
+func (s *MailService) UseBranding(b *BrandingService) {
+	s.branding = b
+}
+
+// SendTemplate renders tmpl with data and queues it for delivery to the given address.
+// Suppressed addresses are skipped silently.
+func (s *MailService) SendTemplate(ctx context.Context, to string, tmpl email.Template, data any) error {
+	suppressed, err := s.suppressions.IsSuppressed(ctx, to)
+	if err != nil {
+		return fmt.Errorf("mailService.SendTemplate suppression check: %w", err)
+	}
+	if suppressed {
+		s.log.WithFields(logrus.Fields{"to": to, "template": tmpl}).Info("skipping email to suppressed address")
+		return nil
+	}
+
+	rendered, err := s.renderer.Render(tmpl, data)
+	if err != nil {
+		return fmt.Errorf("mailService.SendTemplate render: %w", err)
+	}
+
+	msg := &mailer.Message{To: to, Subject: rendered.Subject, HTML: rendered.HTML, Text: rendered.Text}
+	msg.From, msg.FromName = s.branding.Sender()
+	if err := s.queue.Enqueue(ctx, JobSendEmail, msg); err != nil {
+		return fmt.Errorf("mailService.SendTemplate enqueue: %w", err)
+	}
+	return nil
+}
+
+// handleSendJob delivers a queued message. Provider rejections are not retried.
+func (s *MailService) handleSendJob(ctx context.Context, payload json.RawMessage) error {
+	var msg mailer.Message
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		return fmt.Errorf("%w: decode email job: %w", jobs.ErrPermanent, err)
+	}
+
+	// Re-check: the address may have bounced while the job sat in the queue.
+	suppressed, err := s.suppressions.IsSuppressed(ctx, msg.To)
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return nil
+	}
+
+	if err := s.mailer.Send(ctx, &msg); err != nil {
+		if errors.Is(err, mailer.ErrPermanent) {
+			return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+		}
+		return err
+	}
+
+	s.log.WithFields(logrus.Fields{"to": msg.To, "driver": s.mailer.Name()}).Info("email sent")
+	return nil
+}
+
+// HandleBounces records provider-reported bounces so the addresses are no longer mailed.
+func (s *MailService) HandleBounces(ctx context.Context, provider string, bounces []mailer.Bounce) error {
+	for _, b := range bounces {
+		if b.Email == "" {
+			continue
+		}
+		err := s.suppressions.Upsert(ctx, &domain.EmailSuppression{
+			Email:     b.Email,
+			Reason:    b.Reason,
+			Type:      b.Type,
+			Provider:  provider,
+			CreatedAt: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("mailService.HandleBounces: %w", err)
+		}
+		s.log.WithFields(logrus.Fields{"email": b.Email, "type": b.Type, "provider": provider}).Warn("email address suppressed")
+	}
+	return nil
+}
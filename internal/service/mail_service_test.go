@@ -0,0 +1,175 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyMailer fails the first len(errs) sends with errs in turn, then
+// delivers.
+type flakyMailer struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	sent     []*mailer.Message
+}
+
+func (f *flakyMailer) Send(_ context.Context, msg *mailer.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func (f *flakyMailer) Name() string { return "fake" }
+
+func (f *flakyMailer) counts() (attempts, sent int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.attempts, len(f.sent)
+}
+
+type fakeSuppressionRepo struct {
+	mu         sync.Mutex
+	suppressed map[string]*domain.EmailSuppression
+	checks     int
+}
+
+func (f *fakeSuppressionRepo) Upsert(_ context.Context, s *domain.EmailSuppression) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.suppressed[s.Email] = s
+	return nil
+}
+
+func (f *fakeSuppressionRepo) IsSuppressed(_ context.Context, addr string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checks++
+	_, ok := f.suppressed[addr]
+	return ok, nil
+}
+
+func (f *fakeSuppressionRepo) checked() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.checks
+}
+
+type mailFixture struct {
+	svc          *service.MailService
+	queue        *jobs.Queue
+	mailer       *flakyMailer
+	suppressions *fakeSuppressionRepo
+	stopOnce     sync.Once
+}
+
+func newMailFixture(t *testing.T, errs ...error) *mailFixture {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	renderer, err := email.NewRenderer(email.Branding{ProductName: "Todo App"})
+	require.NoError(t, err)
+
+	f := &mailFixture{
+		queue:        jobs.New(jobs.Config{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}, log),
+		mailer:       &flakyMailer{errs: errs},
+		suppressions: &fakeSuppressionRepo{suppressed: map[string]*domain.EmailSuppression{}},
+	}
+	f.svc = service.NewMailService(f.mailer, renderer, f.queue, f.suppressions, log)
+	t.Cleanup(f.stop)
+	return f
+}
+
+// stop drains the queue and waits for the jobs in flight; Stop may only
+// run once.
+func (f *mailFixture) stop() {
+	f.stopOnce.Do(func() { f.queue.Stop(context.Background()) })
+}
+
+func (f *mailFixture) send(t *testing.T, to string) {
+	t.Helper()
+	data := email.VerificationData{Name: "Ana", VerifyURL: "https://todo.test/verify?token=abc"}
+	require.NoError(t, f.svc.SendTemplate(context.Background(), to, email.TemplateVerification, data))
+}
+
+func TestMailService_SuppressedAddressIsNeverSent(t *testing.T) {
+	f := newMailFixture(t)
+	ctx := context.Background()
+	require.NoError(t, f.svc.HandleBounces(ctx, mailer.DriverSendGrid, []mailer.Bounce{
+		{Email: "gone@example.com", Type: mailer.BounceTypeHard, Reason: "550 no such user"},
+		{Email: ""},
+	}))
+	f.queue.Start(ctx)
+
+	f.send(t, "gone@example.com")
+	f.stop()
+
+	attempts, _ := f.mailer.counts()
+	assert.Zero(t, attempts)
+	assert.Len(t, f.suppressions.suppressed, 1, "bounces without an address are ignored")
+}
+
+func TestMailService_AddressSuppressedWhileQueuedIsNotSent(t *testing.T) {
+	f := newMailFixture(t)
+	ctx := context.Background()
+
+	f.send(t, "ben@example.com")
+	require.NoError(t, f.svc.HandleBounces(ctx, mailer.DriverSES, []mailer.Bounce{
+		{Email: "ben@example.com", Type: mailer.BounceTypeComplaint},
+	}))
+	f.queue.Start(ctx)
+
+	require.Eventually(t, func() bool { return f.suppressions.checked() == 2 }, time.Second, time.Millisecond,
+		"the job checks the address again before sending")
+	f.stop()
+	attempts, _ := f.mailer.counts()
+	assert.Zero(t, attempts)
+}
+
+func TestMailService_PermanentFailureIsNotRetried(t *testing.T) {
+	f := newMailFixture(t, fmt.Errorf("%w: 550 mailbox unavailable", mailer.ErrPermanent))
+	f.queue.Start(context.Background())
+
+	f.send(t, "ana@example.com")
+	require.Eventually(t, func() bool { a, _ := f.mailer.counts(); return a == 1 }, time.Second, time.Millisecond)
+	// Well past every backoff a retry could wait.
+	time.Sleep(50 * time.Millisecond)
+
+	attempts, sent := f.mailer.counts()
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, sent)
+}
+
+func TestMailService_TransientFailureIsRetried(t *testing.T) {
+	f := newMailFixture(t, errors.New("connection reset"), errors.New("503 try later"))
+	f.queue.Start(context.Background())
+
+	f.send(t, "ana@example.com")
+	require.Eventually(t, func() bool { _, s := f.mailer.counts(); return s == 1 }, time.Second, time.Millisecond)
+
+	attempts, _ := f.mailer.counts()
+	assert.Equal(t, 3, attempts)
+	f.mailer.mu.Lock()
+	defer f.mailer.mu.Unlock()
+	assert.Equal(t, "ana@example.com", f.mailer.sent[0].To)
+	assert.NotEmpty(t, f.mailer.sent[0].Subject)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxReconcileDays bounds the range of one reconciliation report.
+	maxReconcileDays = 366
+	// maxReconcileMismatches caps the mismatched rows a report lists.
+	maxReconcileMismatches = 100
+)
+
+// MeteringService records the usage that usage-based pricing bills for and
+// reports how it compares with what the billing module invoiced.
+type MeteringService struct {
+	repo domain.MeteringRepository
+	log  *logrus.Logger
+}
+
+// NewMeteringService constructs a MeteringService.
+func NewMeteringService(repo domain.MeteringRepository, log *logrus.Logger) *MeteringService {
+	return &MeteringService{repo: repo, log: log}
+}
+
+// Run records today's usage so far, storage included, and settles
+// yesterday's activity, which the first run after midnight UTC completes.
+// It is idempotent, so it runs on a schedule.
+func (s *MeteringService) Run(ctx context.Context) error {
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	written := 0
+	for _, day := range []time.Time{today.AddDate(0, 0, -1), today} {
+		n, err := s.repo.RecordActivity(ctx, day, now)
+		if err != nil {
+			return fmt.Errorf("meteringService.Run: %w", err)
+		}
+		written += n
+	}
+	n, err := s.repo.RecordStorage(ctx, today, now)
+	if err != nil {
+		return fmt.Errorf("meteringService.Run: %w", err)
+	}
+	written += n
+	s.log.WithField("events", written).Debug("recorded metering events")
+	return nil
+}
+
+// Reconcile compares metered with invoiced usage for the days from through
+// to, listing every metric even when it has no rows.
+func (s *MeteringService) Reconcile(ctx context.Context, from, to time.Time) (*domain.MeteringReconciliation, error) {
+	if to.Before(from) {
+		return nil, fmt.Errorf("meteringService.Reconcile: to is before from: %w", domain.ErrValidation)
+	}
+	if to.Sub(from) >= maxReconcileDays*24*time.Hour {
+		return nil, fmt.Errorf("meteringService.Reconcile: range is over %d days: %w", maxReconcileDays, domain.ErrValidation)
+	}
+
+	rows, err := s.repo.Reconcile(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("meteringService.Reconcile: %w", err)
+	}
+	mismatches, err := s.repo.ListMismatched(ctx, from, to, maxReconcileMismatches)
+	if err != nil {
+		return nil, fmt.Errorf("meteringService.Reconcile: %w", err)
+	}
+
+	byMetric := make(map[string]domain.MeterReconciliation, len(rows))
+	for _, row := range rows {
+		byMetric[row.Metric] = row
+	}
+	report := &domain.MeteringReconciliation{From: from, To: to, Mismatches: mismatches, GeneratedAt: time.Now()}
+	for _, metric := range domain.Meters {
+		row := byMetric[metric]
+		row.Metric = metric
+		row.Difference = row.Metered - row.Invoiced
+		report.Metrics = append(report.Metrics, row)
+	}
+	return report, nil
+}
@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMeteringRepo struct {
+	domain.MeteringRepository
+	activityDays []time.Time
+	storageDays  []time.Time
+	rows         []domain.MeterReconciliation
+}
+
+func (r *fakeMeteringRepo) RecordActivity(ctx context.Context, day, at time.Time) (int, error) {
+	r.activityDays = append(r.activityDays, day)
+	return 2, nil
+}
+
+func (r *fakeMeteringRepo) RecordStorage(ctx context.Context, day, at time.Time) (int, error) {
+	r.storageDays = append(r.storageDays, day)
+	return 1, nil
+}
+
+func (r *fakeMeteringRepo) Reconcile(ctx context.Context, from, to time.Time) ([]domain.MeterReconciliation, error) {
+	return r.rows, nil
+}
+
+func (r *fakeMeteringRepo) ListMismatched(ctx context.Context, from, to time.Time, limit int) ([]*domain.MeteringEvent, error) {
+	return []*domain.MeteringEvent{}, nil
+}
+
+func TestMeteringService_Run(t *testing.T) {
+	repo := &fakeMeteringRepo{}
+	svc := service.NewMeteringService(repo, logrus.New())
+
+	require.NoError(t, svc.Run(context.Background()))
+	now := time.Now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []time.Time{today.AddDate(0, 0, -1), today}, repo.activityDays, "yesterday is settled")
+	assert.Equal(t, []time.Time{today}, repo.storageDays, "storage is only read for today")
+}
+
+func TestMeteringService_Reconcile(t *testing.T) {
+	repo := &fakeMeteringRepo{rows: []domain.MeterReconciliation{
+		{Metric: domain.MeterTasksCreated, Metered: 120, Invoiced: 100, Uninvoiced: 3, Mismatched: 1},
+	}}
+	svc := service.NewMeteringService(repo, logrus.New())
+	from := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+
+	report, err := svc.Reconcile(context.Background(), from, to)
+	require.NoError(t, err)
+	require.Len(t, report.Metrics, len(domain.Meters))
+	assert.Equal(t, domain.MeterActiveUsers, report.Metrics[0].Metric)
+	assert.Zero(t, report.Metrics[0].Metered)
+	assert.Equal(t, domain.MeterTasksCreated, report.Metrics[1].Metric)
+	assert.Equal(t, int64(20), report.Metrics[1].Difference)
+	assert.Equal(t, domain.MeterStorageBytes, report.Metrics[2].Metric)
+
+	_, err = svc.Reconcile(context.Background(), to, from)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = svc.Reconcile(context.Background(), from, from.AddDate(2, 0, 0))
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
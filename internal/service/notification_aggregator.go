@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxSummaryLines caps how many individual event titles a summary body lists.
+const maxSummaryLines = 5
+
+// NotificationRule controls how events of one type are batched and where they go.
+type NotificationRule struct {
+	// Window is how long to collect events before flushing a batch.
+	// Zero delivers every event immediately.
+	Window time.Duration
+	// MaxBatch flushes early once this many distinct events are buffered. Zero means no cap.
+	MaxBatch int
+	// Channels receiving this event type.
+	Channels []domain.NotificationChannel
+	// SummaryTitle formats the title of a coalesced notification; it receives the event count.
+	SummaryTitle string
+}
+
+// DefaultNotificationRules returns the built-in batching rules, using window for
+// event types that tend to arrive in bursts (bulk edits, imports).
+func DefaultNotificationRules(window time.Duration) map[string]NotificationRule {
+	inApp := []domain.NotificationChannel{domain.NotificationChannelInApp}
+	inAppAndEmail := []domain.NotificationChannel{domain.NotificationChannelInApp, domain.NotificationChannelEmail}
+
+	return map[string]NotificationRule{
+		domain.EventTaskCreated:   {Window: window, MaxBatch: 100, Channels: inApp, SummaryTitle: "%d tasks were created"},
+		domain.EventTaskUpdated:   {Window: window, MaxBatch: 100, Channels: inApp, SummaryTitle: "%d tasks were updated"},
+		domain.EventTaskCompleted: {Window: window, MaxBatch: 100, Channels: inApp, SummaryTitle: "%d tasks were completed"},
+		domain.EventTaskOverdue:   {Window: window, MaxBatch: 50, Channels: inAppAndEmail, SummaryTitle: "%d tasks are overdue"},
+		// Reminders are asked for at a specific time, so they are never held back.
+		domain.EventTaskReminder: {Channels: inAppAndEmail},
+	}
+}
+
+// DeliverFunc hands a finished notification to a channel.
+type DeliverFunc func(ctx context.Context, channel domain.NotificationChannel, n *domain.Notification)
+
+type batchKey struct {
+	userID    uuid.UUID
+	channel   domain.NotificationChannel
+	eventType string
+}
+
+type notificationBatch struct {
+	events []domain.NotificationEvent
+	index  map[string]int // dedup key -> position in events
+	timer  *time.Timer
+}
+
+// NotificationAggregator coalesces bursts of events into one notification per
+// user, channel and event type within the rule's time window.
+type NotificationAggregator struct {
+	mu          sync.Mutex
+	rules       map[string]NotificationRule
+	defaultRule NotificationRule
+	batches     map[batchKey]*notificationBatch
+	deliver     DeliverFunc
+	log         *logrus.Logger
+}
+
+// NewNotificationAggregator creates an aggregator. Event types without a rule
+// are delivered immediately to the in-app channel.
+func NewNotificationAggregator(rules map[string]NotificationRule, deliver DeliverFunc, log *logrus.Logger) *NotificationAggregator {
+	return &NotificationAggregator{
+		rules:       rules,
+		defaultRule: NotificationRule{Channels: []domain.NotificationChannel{domain.NotificationChannelInApp}},
+		batches:     make(map[batchKey]*notificationBatch),
+		deliver:     deliver,
+		log:         log,
+	}
+}
+
+// Add buffers an event on every channel configured for its type.
+func (a *NotificationAggregator) Add(event domain.NotificationEvent) {
+	rule, ok := a.rules[event.Type]
+	if !ok {
+		rule = a.defaultRule
+	}
+
+	for _, channel := range rule.Channels {
+		if rule.Window <= 0 {
+			a.deliver(context.Background(), channel, summarise(event.UserID, event.Type, rule, []domain.NotificationEvent{event}))
+			continue
+		}
+		a.add(batchKey{userID: event.UserID, channel: channel, eventType: event.Type}, rule, event)
+	}
+}
+
+func (a *NotificationAggregator) add(key batchKey, rule NotificationRule, event domain.NotificationEvent) {
+	a.mu.Lock()
+	batch, ok := a.batches[key]
+	if !ok {
+		b := &notificationBatch{index: make(map[string]int)}
+		b.timer = time.AfterFunc(rule.Window, func() { a.flushKey(context.Background(), key, b) })
+		a.batches[key] = b
+		batch = b
+	}
+
+	dedup := event.DedupKey
+	if dedup == "" && event.EntityID != nil {
+		dedup = event.EntityID.String()
+	}
+	if pos, seen := batch.index[dedup]; seen && dedup != "" {
+		// Same entity touched again inside the window — keep only the latest event.
+		batch.events[pos] = event
+	} else {
+		if dedup != "" {
+			batch.index[dedup] = len(batch.events)
+		}
+		batch.events = append(batch.events, event)
+	}
+
+	full := rule.MaxBatch > 0 && len(batch.events) >= rule.MaxBatch
+	a.mu.Unlock()
+
+	if full {
+		a.flushKey(context.Background(), key, batch)
+	}
+}
+
+// flushKey delivers and removes the batch for key. When want is non-nil the
+// batch is only flushed if it is still that batch, so a stale timer cannot
+// prematurely flush a newer batch for the same key.
+func (a *NotificationAggregator) flushKey(ctx context.Context, key batchKey, want *notificationBatch) {
+	a.mu.Lock()
+	batch, ok := a.batches[key]
+	if ok && want != nil && batch != want {
+		ok = false
+	}
+	if ok {
+		delete(a.batches, key)
+		batch.timer.Stop()
+	}
+	a.mu.Unlock()
+
+	if !ok || len(batch.events) == 0 {
+		return
+	}
+
+	rule, found := a.rules[key.eventType]
+	if !found {
+		rule = a.defaultRule
+	}
+	a.deliver(ctx, key.channel, summarise(key.userID, key.eventType, rule, batch.events))
+}
+
+// Flush delivers every pending batch immediately. Call during shutdown.
+func (a *NotificationAggregator) Flush(ctx context.Context) {
+	a.mu.Lock()
+	keys := make([]batchKey, 0, len(a.batches))
+	for k := range a.batches {
+		keys = append(keys, k)
+	}
+	a.mu.Unlock()
+
+	for _, k := range keys {
+		a.flushKey(ctx, k, nil)
+	}
+	if len(keys) > 0 {
+		a.log.WithField("batches", len(keys)).Info("flushed pending notification batches")
+	}
+}
+
+// summarise turns one or more events into a single notification.
+func summarise(userID uuid.UUID, eventType string, rule NotificationRule, events []domain.NotificationEvent) *domain.Notification {
+	data := domain.NotificationData{}
+	for _, e := range events {
+		if e.EntityID != nil {
+			data.EntityIDs = append(data.EntityIDs, *e.EntityID)
+		}
+	}
+
+	n := &domain.Notification{
+		ID:         uuid.New(),
+		UserID:     userID,
+		Type:       eventType,
+		EventCount: len(events),
+		CreatedAt:  time.Now(),
+	}
+
+	if len(events) == 1 {
+		n.Title = events[0].Title
+		n.Body = events[0].Body
+		data.URL = events[0].URL
+	} else {
+		title := rule.SummaryTitle
+		if title == "" {
+			title = "%d new notifications"
+		}
+		n.Title = fmt.Sprintf(title, len(events))
+
+		lines := make([]string, 0, maxSummaryLines+1)
+		for i, e := range events {
+			if i == maxSummaryLines {
+				lines = append(lines, fmt.Sprintf("…and %d more", len(events)-maxSummaryLines))
+				break
+			}
+			lines = append(lines, "• "+e.Title)
+		}
+		n.Body = strings.Join(lines, "\n")
+	}
+
+	n.Data, _ = json.Marshal(data)
+	return n
+}
@@ -0,0 +1,107 @@
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deliveryRecorder struct {
+	mu   sync.Mutex
+	sent []*domain.Notification
+}
+
+func (r *deliveryRecorder) deliver(_ context.Context, _ domain.NotificationChannel, n *domain.Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+}
+
+func (r *deliveryRecorder) delivered() []*domain.Notification {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]*domain.Notification(nil), r.sent...)
+}
+
+func newTestAggregator(rec *deliveryRecorder, window time.Duration, maxBatch int) *service.NotificationAggregator {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	rules := map[string]service.NotificationRule{
+		domain.EventTaskUpdated: {
+			Window:       window,
+			MaxBatch:     maxBatch,
+			Channels:     []domain.NotificationChannel{domain.NotificationChannelInApp},
+			SummaryTitle: "%d tasks were updated",
+		},
+	}
+	return service.NewNotificationAggregator(rules, rec.deliver, log)
+}
+
+func TestNotificationAggregator_CoalescesBurst(t *testing.T) {
+	rec := &deliveryRecorder{}
+	agg := newTestAggregator(rec, time.Hour, 0)
+	userID := uuid.New()
+
+	for i := 0; i < 15; i++ {
+		id := uuid.New()
+		agg.Add(domain.NotificationEvent{UserID: userID, Type: domain.EventTaskUpdated, Title: "task", EntityID: &id})
+	}
+	assert.Empty(t, rec.delivered(), "nothing should be delivered before the window closes")
+
+	agg.Flush(context.Background())
+
+	sent := rec.delivered()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "15 tasks were updated", sent[0].Title)
+	assert.Equal(t, 15, sent[0].EventCount)
+}
+
+func TestNotificationAggregator_DeduplicatesSameEntity(t *testing.T) {
+	rec := &deliveryRecorder{}
+	agg := newTestAggregator(rec, time.Hour, 0)
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	agg.Add(domain.NotificationEvent{UserID: userID, Type: domain.EventTaskUpdated, Title: "first", EntityID: &taskID})
+	agg.Add(domain.NotificationEvent{UserID: userID, Type: domain.EventTaskUpdated, Title: "second", EntityID: &taskID})
+	agg.Flush(context.Background())
+
+	sent := rec.delivered()
+	require.Len(t, sent, 1)
+	assert.Equal(t, 1, sent[0].EventCount)
+	assert.Equal(t, "second", sent[0].Title, "latest event for an entity wins")
+}
+
+func TestNotificationAggregator_FlushesOnMaxBatch(t *testing.T) {
+	rec := &deliveryRecorder{}
+	agg := newTestAggregator(rec, time.Hour, 3)
+	userID := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		id := uuid.New()
+		agg.Add(domain.NotificationEvent{UserID: userID, Type: domain.EventTaskUpdated, Title: "task", EntityID: &id})
+	}
+
+	sent := rec.delivered()
+	require.Len(t, sent, 1)
+	assert.Equal(t, 3, sent[0].EventCount)
+}
+
+func TestNotificationAggregator_UnknownTypeDeliversImmediately(t *testing.T) {
+	rec := &deliveryRecorder{}
+	agg := newTestAggregator(rec, time.Hour, 0)
+
+	agg.Add(domain.NotificationEvent{UserID: uuid.New(), Type: "something.else", Title: "hello"})
+
+	sent := rec.delivered()
+	require.Len(t, sent, 1)
+	assert.Equal(t, "hello", sent[0].Title)
+}
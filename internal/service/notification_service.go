@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/email"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// deferredBatchSize caps how many held-back notifications one FlushDeferred run releases.
+const deferredBatchSize = 500
+
+// NotificationService is the central notifier: producers hand it events, the
+// aggregator batches them, and finished notifications fan out to each channel.
+// Every channel passes through the user's do-not-disturb schedule first.
+type NotificationService struct {
+	notificationRepo domain.NotificationRepository
+	userRepo         domain.UserRepository
+	quietHoursRepo   domain.QuietHoursRepository
+	deferredRepo     domain.DeferredNotificationRepository
+	mailSvc          *MailService
+	aggregator       *NotificationAggregator
+	log              *logrus.Logger
+}
+
+// NewNotificationService constructs a NotificationService using the given batching rules.
+func NewNotificationService(
+	notificationRepo domain.NotificationRepository,
+	userRepo domain.UserRepository,
+	quietHoursRepo domain.QuietHoursRepository,
+	deferredRepo domain.DeferredNotificationRepository,
+	mailSvc *MailService,
+	rules map[string]NotificationRule,
+	log *logrus.Logger,
+) *NotificationService {
+	s := &NotificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		quietHoursRepo:   quietHoursRepo,
+		deferredRepo:     deferredRepo,
+		mailSvc:          mailSvc,
+		log:              log,
+	}
+	s.aggregator = NewNotificationAggregator(rules, s.deliver, log)
+	return s
+}
+
+// Notify submits an event for (possibly batched) delivery. It never blocks on I/O.
+func (s *NotificationService) Notify(event domain.NotificationEvent) {
+	s.aggregator.Add(event)
+}
+
+// Flush delivers all pending batches immediately. Call during shutdown.
+func (s *NotificationService) Flush(ctx context.Context) {
+	s.aggregator.Flush(ctx)
+}
+
+// List returns a page of in-app notifications for a user.
+func (s *NotificationService) List(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, limit int) ([]*domain.Notification, int, error) {
+	notifications, total, err := s.notificationRepo.ListByUserID(ctx, userID, unreadOnly, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("notificationService.List: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// UnreadCount returns the number of unread in-app notifications.
+func (s *NotificationService) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("notificationService.UnreadCount: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks a single notification as read, scoped to its owner.
+func (s *NotificationService) MarkRead(ctx context.Context, id, userID uuid.UUID) error {
+	return s.notificationRepo.MarkRead(ctx, id, userID)
+}
+
+// MarkAllRead marks every unread notification of the user as read.
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID uuid.UUID) error {
+	if err := s.notificationRepo.MarkAllRead(ctx, userID); err != nil {
+		return fmt.Errorf("notificationService.MarkAllRead: %w", err)
+	}
+	return nil
+}
+
+// Snooze hides a notification until the given time. It is excluded from the
+// feed and unread count meanwhile, and resurfaces as unread afterwards.
+func (s *NotificationService) Snooze(ctx context.Context, id, userID uuid.UUID, until time.Time) error {
+	if !until.After(time.Now()) {
+		return fmt.Errorf("notificationService.Snooze: until must be in the future: %w", domain.ErrValidation)
+	}
+	return s.notificationRepo.Snooze(ctx, id, userID, until.UTC())
+}
+
+// ResurfaceSnoozed brings back notifications whose snooze has expired. A
+// snooze ending inside the user's do-not-disturb window is pushed to its end.
+// It is meant to be run periodically by the job scheduler.
+func (s *NotificationService) ResurfaceSnoozed(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.notificationRepo.ListSnoozedDue(ctx, now.UTC(), deferredBatchSize)
+	if err != nil {
+		return fmt.Errorf("notificationService.ResurfaceSnoozed: %w", err)
+	}
+
+	schedules := make(map[uuid.UUID]*domain.QuietHours)
+	ids := make([]uuid.UUID, 0, len(due))
+	for _, n := range due {
+		q, ok := schedules[n.UserID]
+		if !ok {
+			q, _ = s.quietHoursRepo.FindByUserID(ctx, n.UserID)
+			schedules[n.UserID] = q
+		}
+		if q.Active(now) {
+			if err := s.notificationRepo.Snooze(ctx, n.ID, n.UserID, q.EndsAt(now).UTC()); err != nil {
+				s.log.WithError(err).WithField("notification_id", n.ID).Error("failed to extend snooze")
+			}
+			continue
+		}
+		ids = append(ids, n.ID)
+	}
+
+	if err := s.notificationRepo.Resurface(ctx, ids); err != nil {
+		return fmt.Errorf("notificationService.ResurfaceSnoozed: %w", err)
+	}
+	if len(ids) > 0 {
+		s.log.WithField("count", len(ids)).Info("resurfaced snoozed notifications")
+	}
+	return nil
+}
+
+// GetQuietHours returns the user's do-not-disturb schedule, or a disabled
+// default when none has been saved yet.
+func (s *NotificationService) GetQuietHours(ctx context.Context, userID uuid.UUID) (*domain.QuietHours, error) {
+	q, err := s.quietHoursRepo.FindByUserID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.QuietHours{
+			UserID: userID, Timezone: "UTC", StartTime: "22:00", EndTime: "07:00", Days: []string{},
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("notificationService.GetQuietHours: %w", err)
+	}
+	return q, nil
+}
+
+// UpdateQuietHours replaces the user's do-not-disturb schedule.
+func (s *NotificationService) UpdateQuietHours(ctx context.Context, userID uuid.UUID, req *domain.UpdateQuietHoursRequest) (*domain.QuietHours, error) {
+	mask, err := domain.ParseWeekdays(req.QuietDays)
+	if err != nil {
+		return nil, fmt.Errorf("notificationService.UpdateQuietHours: %w", domain.ErrValidation)
+	}
+
+	q := &domain.QuietHours{
+		UserID:    userID,
+		Enabled:   req.Enabled,
+		Timezone:  req.Timezone,
+		StartTime: req.StartTime,
+		EndTime:   req.EndTime,
+		QuietDays: mask,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := s.quietHoursRepo.Upsert(ctx, q); err != nil {
+		return nil, fmt.Errorf("notificationService.UpdateQuietHours: %w", err)
+	}
+	q.Days = domain.WeekdayNames(mask)
+	return q, nil
+}
+
+// FlushDeferred releases notifications whose do-not-disturb window has ended.
+// Everything held back for a user on one channel is delivered as one summary.
+// It is meant to be run periodically by the job scheduler.
+func (s *NotificationService) FlushDeferred(ctx context.Context) error {
+	due, err := s.deferredRepo.ListDue(ctx, time.Now().UTC(), deferredBatchSize)
+	if err != nil {
+		return fmt.Errorf("notificationService.FlushDeferred: %w", err)
+	}
+	if len(due) == 0 {
+		return nil
+	}
+
+	type groupKey struct {
+		userID  uuid.UUID
+		channel domain.NotificationChannel
+	}
+	groups := make(map[groupKey][]*domain.DeferredNotification)
+	var order []groupKey
+	ids := make([]uuid.UUID, 0, len(due))
+	for _, d := range due {
+		k := groupKey{d.UserID, d.Channel}
+		if _, ok := groups[k]; !ok {
+			order = append(order, k)
+		}
+		groups[k] = append(groups[k], d)
+		ids = append(ids, d.ID)
+	}
+
+	// Delete first so a slow delivery cannot cause the next run to send duplicates.
+	if err := s.deferredRepo.DeleteByIDs(ctx, ids); err != nil {
+		return fmt.Errorf("notificationService.FlushDeferred: %w", err)
+	}
+	for _, k := range order {
+		s.send(ctx, k.channel, morningSummary(groups[k]))
+	}
+	return nil
+}
+
+// deliver routes a finished notification through one channel, holding it back
+// when the recipient's do-not-disturb schedule is active.
+func (s *NotificationService) deliver(ctx context.Context, channel domain.NotificationChannel, n *domain.Notification) {
+	now := time.Now()
+	q, err := s.quietHoursRepo.FindByUserID(ctx, n.UserID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		// Fail open: a broken preference lookup should not swallow notifications.
+		s.log.WithError(err).WithField("user_id", n.UserID).Warn("failed to load quiet hours")
+	}
+	if q.Active(now) {
+		s.holdBack(ctx, channel, n, q.EndsAt(now))
+		return
+	}
+	s.send(ctx, channel, n)
+}
+
+func (s *NotificationService) holdBack(ctx context.Context, channel domain.NotificationChannel, n *domain.Notification, until time.Time) {
+	d := &domain.DeferredNotification{
+		ID:           uuid.New(),
+		UserID:       n.UserID,
+		Channel:      channel,
+		Type:         n.Type,
+		Title:        n.Title,
+		Body:         n.Body,
+		Data:         n.Data,
+		EventCount:   n.EventCount,
+		DeliverAfter: until.UTC(),
+		CreatedAt:    time.Now().UTC(),
+	}
+	entry := s.log.WithFields(logrus.Fields{"user_id": n.UserID, "type": n.Type, "channel": channel})
+	if err := s.deferredRepo.Create(ctx, d); err != nil {
+		entry.WithError(err).Error("failed to defer notification")
+		return
+	}
+	entry.WithField("deliver_after", d.DeliverAfter).Debug("notification deferred by do-not-disturb")
+}
+
+// send delivers a notification through one channel unconditionally. Failures
+// are logged rather than returned because delivery happens off the request path.
+func (s *NotificationService) send(ctx context.Context, channel domain.NotificationChannel, n *domain.Notification) {
+	var err error
+	switch channel {
+	case domain.NotificationChannelInApp:
+		err = s.notificationRepo.Create(ctx, n)
+	case domain.NotificationChannelEmail:
+		err = s.deliverEmail(ctx, n)
+	default:
+		err = fmt.Errorf("unknown channel %q", channel)
+	}
+
+	entry := s.log.WithFields(logrus.Fields{
+		"user_id": n.UserID, "type": n.Type, "channel": channel, "event_count": n.EventCount,
+	})
+	if err != nil {
+		entry.WithError(err).Error("failed to deliver notification")
+		return
+	}
+	entry.Debug("notification delivered")
+}
+
+func (s *NotificationService) deliverEmail(ctx context.Context, n *domain.Notification) error {
+	user, err := s.userRepo.FindByID(ctx, n.UserID)
+	if err != nil {
+		return fmt.Errorf("find user: %w", err)
+	}
+
+	var data domain.NotificationData
+	_ = json.Unmarshal(n.Data, &data)
+
+	return s.mailSvc.SendTemplate(ctx, user.Email, email.TemplateNotification, email.NotificationData{
+		Name:  user.Name,
+		Title: n.Title,
+		Body:  n.Body,
+		URL:   data.URL,
+	})
+}
+
+// morningSummary folds notifications held during do-not-disturb into one.
+// A single held notification is delivered unchanged.
+func morningSummary(items []*domain.DeferredNotification) *domain.Notification {
+	first := items[0]
+	n := &domain.Notification{
+		ID:         uuid.New(),
+		UserID:     first.UserID,
+		Type:       first.Type,
+		Title:      first.Title,
+		Body:       first.Body,
+		Data:       first.Data,
+		EventCount: first.EventCount,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if len(items) == 1 {
+		return n
+	}
+
+	var (
+		lines []string
+		data  domain.NotificationData
+	)
+	n.EventCount = 0
+	for i, d := range items {
+		n.EventCount += d.EventCount
+		if i < maxSummaryLines {
+			lines = append(lines, "• "+d.Title)
+		}
+		var item domain.NotificationData
+		if json.Unmarshal(d.Data, &item) == nil {
+			data.EntityIDs = append(data.EntityIDs, item.EntityIDs...)
+		}
+	}
+	if extra := len(items) - maxSummaryLines; extra > 0 {
+		lines = append(lines, fmt.Sprintf("…and %d more", extra))
+	}
+
+	n.Type = domain.EventQuietHoursSummary
+	n.Title = fmt.Sprintf("While you were away: %d notifications", len(items))
+	n.Body = strings.Join(lines, "\n")
+	n.Data, _ = json.Marshal(data)
+	return n
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// operation is a destructive or bulk change split into a read-only plan and
+// an apply step that acts on exactly the planned rows. Splitting it this way
+// is what makes dry runs exact: a dry run reports the plan and stops.
+type operation struct {
+	name  string
+	plan  func(ctx context.Context) (map[string][]uuid.UUID, error)
+	apply func(ctx context.Context, affected map[string][]uuid.UUID) error
+}
+
+// run plans op and, unless dryRun is set, applies it.
+func (op operation) run(ctx context.Context, dryRun bool) (*domain.OperationResult, error) {
+	affected, err := op.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.OperationResult{Operation: op.name, DryRun: dryRun, Affected: affected}
+	for _, ids := range affected {
+		result.Total += len(ids)
+	}
+	if dryRun || result.Total == 0 {
+		return result, nil
+	}
+
+	if err := op.apply(ctx, affected); err != nil {
+		return nil, fmt.Errorf("%s: %w", op.name, err)
+	}
+	return result, nil
+}
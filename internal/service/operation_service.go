@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// JobRunOperation is the job type that runs one async operation.
+const JobRunOperation = "operations.run"
+
+// Operation kinds.
+const (
+	OperationExportTasks    = "tasks.export"
+	OperationImportTasks    = "tasks.import"
+	OperationPurgeTrash     = "admin.purge_trash"
+	OperationHardDeleteUser = "admin.delete_user"
+	OperationBackup         = "admin.backup"
+)
+
+const (
+	// operationHeartbeat is how often a running operation saves its
+	// progress and checks whether it has been cancelled.
+	operationHeartbeat = 5 * time.Second
+	// operationStaleAfter fails unfinished operations that have not been
+	// updated for this long, such as those of an instance that went away.
+	operationStaleAfter = 10 * time.Minute
+	// operationRetention is how long finished operations and their files
+	// remain available.
+	operationRetention = 24 * time.Hour
+)
+
+// OperationRunner does the work of one kind of operation from the payload
+// it was started with. It should stop promptly once ctx is cancelled, and
+// may call report with the percentage done.
+type OperationRunner func(ctx context.Context, userID uuid.UUID, payload json.RawMessage, report func(percent int)) (*domain.OperationOutput, error)
+
+type runOperationJob struct {
+	OperationID uuid.UUID       `json:"operation_id"`
+	UserID      uuid.UUID       `json:"user_id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// OperationService runs long requests such as exports, imports and hard
+// deletes on the job queue, recording their progress and outcome for
+// clients to poll.
+type OperationService struct {
+	operationRepo domain.OperationRepository
+	queue         *jobs.Queue
+	timeout       time.Duration
+	log           *logrus.Logger
+	runners       map[string]OperationRunner
+
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc // operations running on this instance
+}
+
+// NewOperationService constructs an OperationService whose operations may
+// each run for up to timeout, and registers its job handler on queue.
+func NewOperationService(operationRepo domain.OperationRepository, queue *jobs.Queue, timeout time.Duration, log *logrus.Logger) *OperationService {
+	s := &OperationService{
+		operationRepo: operationRepo,
+		queue:         queue,
+		timeout:       timeout,
+		log:           log,
+		runners:       map[string]OperationRunner{},
+		running:       map[uuid.UUID]context.CancelFunc{},
+	}
+	queue.Register(JobRunOperation, s.handleRunJob)
+	return s
+}
+
+// Handle registers the runner for a kind of operation. Must be called before
+// serving requests.
+func (s *OperationService) Handle(kind string, run OperationRunner) {
+	s.runners[kind] = run
+}
+
+// Start records a pending operation and queues it to run with payload.
+func (s *OperationService) Start(ctx context.Context, userID uuid.UUID, kind string, payload any) (*domain.Operation, error) {
+	if _, ok := s.runners[kind]; !ok {
+		return nil, fmt.Errorf("operationService.Start: no runner for %q", kind)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("operationService.Start: %w", err)
+	}
+
+	now := time.Now()
+	op := &domain.Operation{ID: uuid.New(), UserID: userID, Kind: kind, Status: domain.OperationPending, CreatedAt: now, UpdatedAt: now}
+	if err := s.operationRepo.Create(ctx, op); err != nil {
+		return nil, fmt.Errorf("operationService.Start: %w", err)
+	}
+
+	job := runOperationJob{OperationID: op.ID, UserID: userID, Kind: kind, Payload: raw}
+	// A failed operation is not retried: it may have written half its work.
+	if err := s.queue.Enqueue(ctx, JobRunOperation, job, jobs.WithMaxAttempts(1), jobs.WithTimeout(s.timeout)); err != nil {
+		s.finish(ctx, op, nil, errors.New("the operation could not be queued"))
+		return nil, fmt.Errorf("operationService.Start: %v: %w", err, domain.ErrUnavailable)
+	}
+	s.log.WithFields(logrus.Fields{"operation_id": op.ID, "user_id": userID, "kind": kind}).Info("operation queued")
+	return op, nil
+}
+
+// Get returns one of the user's operations.
+func (s *OperationService) Get(ctx context.Context, id, userID uuid.UUID) (*domain.Operation, error) {
+	op, err := s.operationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op.UserID != userID {
+		return nil, domain.ErrNotFound
+	}
+	return op, nil
+}
+
+// File returns the download a finished operation produced.
+func (s *OperationService) File(ctx context.Context, id, userID uuid.UUID) (*domain.Download, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	file, err := s.operationRepo.FindFile(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Cancel asks the operation to stop. A pending one never starts; a running
+// one stops at its next check, keeping whatever it has already written.
+// Cancelling a finished operation changes nothing.
+func (s *OperationService) Cancel(ctx context.Context, id, userID uuid.UUID) (*domain.Operation, error) {
+	op, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if op.Status.Finished() {
+		return op, nil
+	}
+	if err := s.operationRepo.RequestCancel(ctx, id); err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("operationService.Cancel: %w", err)
+	}
+	// Operations elsewhere notice the flag on their next heartbeat.
+	s.mu.Lock()
+	if cancel, ok := s.running[id]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+	return s.Get(ctx, id, userID)
+}
+
+// Prune fails operations whose instance stopped heartbeating and removes
+// finished ones past their retention. Intended to be run by the scheduler.
+func (s *OperationService) Prune(ctx context.Context) error {
+	now := time.Now()
+	stale, err := s.operationRepo.FailStale(ctx, now.Add(-operationStaleAfter))
+	if err != nil {
+		return fmt.Errorf("operationService.Prune: %w", err)
+	}
+	if stale > 0 {
+		s.log.WithField("operations", stale).Warn("failed operations that stopped reporting")
+	}
+	if _, err := s.operationRepo.DeleteFinishedBefore(ctx, now.Add(-operationRetention)); err != nil {
+		return fmt.Errorf("operationService.Prune: %w", err)
+	}
+	return nil
+}
+
+// handleRunJob runs a queued operation and records how it ended.
+func (s *OperationService) handleRunJob(ctx context.Context, payload json.RawMessage) error {
+	var job runOperationJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("operationService.handleRunJob: %v: %w", err, jobs.ErrPermanent)
+	}
+	op := &domain.Operation{ID: job.OperationID, UserID: job.UserID, Kind: job.Kind}
+	run, ok := s.runners[job.Kind]
+	if !ok {
+		s.finish(ctx, op, nil, fmt.Errorf("no runner for %q", job.Kind))
+		return fmt.Errorf("operationService.handleRunJob: no runner for %q: %w", job.Kind, jobs.ErrPermanent)
+	}
+	if err := s.operationRepo.Start(ctx, op.ID); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil // cancelled, or given up on, while queued
+		}
+		return fmt.Errorf("operationService.handleRunJob: %w", err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[op.ID] = cancel
+	s.mu.Unlock()
+
+	var progress atomic.Int32
+	var cancelled atomic.Bool
+	watched := make(chan struct{})
+	go func() {
+		defer close(watched)
+		s.watch(runCtx, op.ID, &progress, func() {
+			cancelled.Store(true)
+			cancel()
+		})
+	}()
+
+	out, err := safeRunOperation(runCtx, run, job, func(percent int) {
+		progress.Store(int32(min(max(percent, 0), 100)))
+	})
+	if err != nil && runCtx.Err() != nil && ctx.Err() == nil {
+		cancelled.Store(true) // Cancel on this instance
+	}
+	cancel()
+	<-watched
+	s.mu.Lock()
+	delete(s.running, op.ID)
+	s.mu.Unlock()
+
+	op.Progress = int(progress.Load())
+	switch {
+	case err == nil:
+		s.finish(ctx, op, out, nil)
+	case cancelled.Load():
+		op.Status = domain.OperationCancelled
+		s.finish(ctx, op, nil, nil)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		s.finish(ctx, op, nil, errors.New("the operation timed out"))
+	case ctx.Err() != nil:
+		s.finish(ctx, op, nil, errors.New("the operation was interrupted by a server shutdown"))
+	default:
+		s.finish(ctx, op, nil, err)
+	}
+	return nil
+}
+
+// watch saves progress every heartbeat until ctx is done, calling cancel
+// when the operation's cancellation has been requested.
+func (s *OperationService) watch(ctx context.Context, id uuid.UUID, progress *atomic.Int32, cancel func()) {
+	ticker := time.NewTicker(operationHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			requested, err := s.operationRepo.Heartbeat(ctx, id, int(progress.Load()))
+			if err != nil {
+				if ctx.Err() == nil {
+					s.log.WithError(err).WithField("operation_id", id).Warn("operation heartbeat failed")
+				}
+				continue
+			}
+			if requested {
+				cancel()
+				return
+			}
+		}
+	}
+}
+
+// finish stores how the operation ended: succeeded with out when runErr is
+// nil, failed otherwise, unless op.Status is already set. Only validation
+// errors are shown to the client verbatim.
+func (s *OperationService) finish(ctx context.Context, op *domain.Operation, out *domain.OperationOutput, runErr error) {
+	now := time.Now()
+	op.FinishedAt = &now
+	var file *domain.Download
+	switch {
+	case op.Status == domain.OperationCancelled:
+	case runErr == nil:
+		op.Status, op.Progress = domain.OperationSucceeded, 100
+		if out != nil {
+			file = out.File
+			if out.Result != nil {
+				raw, err := json.Marshal(out.Result)
+				if err != nil {
+					runErr = err
+					break
+				}
+				op.Result = raw
+			}
+		}
+	}
+	if runErr != nil {
+		op.Status, op.Result, file = domain.OperationFailed, nil, nil
+		msg := "the operation failed"
+		if errors.Is(runErr, domain.ErrValidation) || errors.Is(runErr, domain.ErrNotFound) {
+			msg = runErr.Error()
+		}
+		op.Error = &msg
+		s.log.WithError(runErr).WithFields(logrus.Fields{"operation_id": op.ID, "kind": op.Kind}).Error("operation failed")
+	}
+
+	// Recorded even when the job's own context has run out.
+	fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	if err := s.operationRepo.Finish(fctx, op, file); err != nil {
+		s.log.WithError(err).WithField("operation_id", op.ID).Error("failed to record operation outcome")
+	}
+}
+
+// safeRunOperation turns a panicking runner into a failed operation.
+func safeRunOperation(ctx context.Context, run OperationRunner, job runOperationJob, report func(int)) (out *domain.OperationOutput, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("operation panic: %v", r)
+		}
+	}()
+	return run(ctx, job.UserID, job.Payload, report)
+}
@@ -0,0 +1,214 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOperationRepo struct {
+	domain.OperationRepository
+	mu    sync.Mutex
+	ops   map[uuid.UUID]*domain.Operation
+	files map[uuid.UUID]*domain.Download
+}
+
+func newFakeOperationRepo() *fakeOperationRepo {
+	return &fakeOperationRepo{ops: map[uuid.UUID]*domain.Operation{}, files: map[uuid.UUID]*domain.Download{}}
+}
+
+func (f *fakeOperationRepo) Create(_ context.Context, op *domain.Operation) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	copied := *op
+	f.ops[op.ID] = &copied
+	return nil
+}
+
+func (f *fakeOperationRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.Operation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op, ok := f.ops[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	copied := *op
+	copied.HasFile = f.files[id] != nil
+	return &copied, nil
+}
+
+func (f *fakeOperationRepo) FindFile(_ context.Context, id uuid.UUID) (*domain.Download, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if file := f.files[id]; file != nil {
+		return file, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeOperationRepo) Start(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op, ok := f.ops[id]
+	if !ok || op.Status != domain.OperationPending {
+		return domain.ErrNotFound
+	}
+	op.Status = domain.OperationRunning
+	return nil
+}
+
+func (f *fakeOperationRepo) Heartbeat(_ context.Context, id uuid.UUID, progress int) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op := f.ops[id]
+	op.Progress = max(op.Progress, progress)
+	return op.CancelRequested, nil
+}
+
+func (f *fakeOperationRepo) Finish(_ context.Context, op *domain.Operation, file *domain.Download) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stored := f.ops[op.ID]
+	stored.Status, stored.Progress, stored.Result, stored.Error = op.Status, op.Progress, op.Result, op.Error
+	f.files[op.ID] = file
+	return nil
+}
+
+func (f *fakeOperationRepo) RequestCancel(_ context.Context, id uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	op := f.ops[id]
+	op.CancelRequested = true
+	if op.Status == domain.OperationPending {
+		op.Status = domain.OperationCancelled
+	}
+	return nil
+}
+
+func newOperationService(t *testing.T, repo *fakeOperationRepo) *service.OperationService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	queue := jobs.New(jobs.Config{BufferSize: 10}, log)
+	svc := service.NewOperationService(repo, queue, time.Minute, log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	queue.Start(ctx)
+	t.Cleanup(func() {
+		cancel()
+		queue.Stop(context.Background())
+	})
+	return svc
+}
+
+// awaitOperation polls until the operation has finished.
+func awaitOperation(t *testing.T, svc *service.OperationService, op *domain.Operation) *domain.Operation {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		got, err := svc.Get(context.Background(), op.ID, op.UserID)
+		require.NoError(t, err)
+		if got.Status.Finished() {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", op.ID)
+	return nil
+}
+
+func TestOperationService_RunsToResultAndFile(t *testing.T) {
+	repo := newFakeOperationRepo()
+	svc := newOperationService(t, repo)
+	svc.Handle("test.export", func(_ context.Context, _ uuid.UUID, payload json.RawMessage, report func(int)) (*domain.OperationOutput, error) {
+		var p struct{ Name string }
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, err
+		}
+		report(50)
+		return &domain.OperationOutput{
+			Result: map[string]int{"tasks": 3},
+			File:   &domain.Download{Name: p.Name, ContentType: "text/plain", Data: []byte("x\n")},
+		}, nil
+	})
+
+	userID := uuid.New()
+	op, err := svc.Start(context.Background(), userID, "test.export", struct{ Name string }{"todo.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.OperationPending, op.Status)
+
+	done := awaitOperation(t, svc, op)
+	assert.Equal(t, domain.OperationSucceeded, done.Status)
+	assert.Equal(t, 100, done.Progress)
+	assert.JSONEq(t, `{"tasks":3}`, string(done.Result))
+	assert.True(t, done.HasFile)
+
+	file, err := svc.File(context.Background(), op.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "todo.txt", file.Name)
+
+	_, err = svc.Get(context.Background(), op.ID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound, "another user's operation")
+}
+
+func TestOperationService_OnlyValidationErrorsAreShown(t *testing.T) {
+	repo := newFakeOperationRepo()
+	svc := newOperationService(t, repo)
+	svc.Handle("test.import", func(_ context.Context, _ uuid.UUID, payload json.RawMessage, _ func(int)) (*domain.OperationOutput, error) {
+		if string(payload) == `"bad file"` {
+			return nil, fmt.Errorf("line 2: no title: %w", domain.ErrValidation)
+		}
+		return nil, errors.New("pq: connection reset")
+	})
+
+	userID := uuid.New()
+	op, err := svc.Start(context.Background(), userID, "test.import", "bad file")
+	require.NoError(t, err)
+	done := awaitOperation(t, svc, op)
+	assert.Equal(t, domain.OperationFailed, done.Status)
+	require.NotNil(t, done.Error)
+	assert.Contains(t, *done.Error, "no title")
+
+	op, err = svc.Start(context.Background(), userID, "test.import", "good file")
+	require.NoError(t, err)
+	done = awaitOperation(t, svc, op)
+	require.NotNil(t, done.Error)
+	assert.NotContains(t, *done.Error, "pq")
+}
+
+func TestOperationService_CancelStopsRunningOperation(t *testing.T) {
+	repo := newFakeOperationRepo()
+	svc := newOperationService(t, repo)
+	started := make(chan struct{})
+	svc.Handle("test.delete", func(ctx context.Context, _ uuid.UUID, _ json.RawMessage, _ func(int)) (*domain.OperationOutput, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	userID := uuid.New()
+	op, err := svc.Start(context.Background(), userID, "test.delete", nil)
+	require.NoError(t, err)
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("operation did not start")
+	}
+
+	_, err = svc.Cancel(context.Background(), op.ID, userID)
+	require.NoError(t, err)
+	done := awaitOperation(t, svc, op)
+	assert.Equal(t, domain.OperationCancelled, done.Status)
+	assert.Nil(t, done.Error)
+}
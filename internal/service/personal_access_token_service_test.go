@@ -0,0 +1,160 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakePersonalAccessTokenRepo struct {
+	domain.PersonalAccessTokenRepository
+	tokens  []*domain.PersonalAccessToken
+	touches int
+}
+
+func (f *fakePersonalAccessTokenRepo) Create(_ context.Context, t *domain.PersonalAccessToken) error {
+	f.tokens = append(f.tokens, t)
+	return nil
+}
+
+func (f *fakePersonalAccessTokenRepo) FindByHash(_ context.Context, hash string) (*domain.PersonalAccessToken, error) {
+	for _, t := range f.tokens {
+		if t.TokenHash == hash {
+			return t, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakePersonalAccessTokenRepo) CountActive(_ context.Context, userID uuid.UUID, now time.Time) (int, error) {
+	n := 0
+	for _, t := range f.tokens {
+		if t.UserID == userID && t.Usable(now) {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakePersonalAccessTokenRepo) Touch(_ context.Context, id uuid.UUID, at time.Time) error {
+	f.touches++
+	for _, t := range f.tokens {
+		if t.ID == id {
+			t.LastUsedAt = &at
+		}
+	}
+	return nil
+}
+
+func (f *fakePersonalAccessTokenRepo) Revoke(_ context.Context, id, userID uuid.UUID, at time.Time) error {
+	for _, t := range f.tokens {
+		if t.ID == id && t.UserID == userID {
+			t.RevokedAt = &at
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func newPersonalAccessTokenService(repo *fakePersonalAccessTokenRepo) *service.PersonalAccessTokenService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewPersonalAccessTokenService(repo, log)
+}
+
+func TestPersonalAccessTokenService_CreateStoresOnlyADigest(t *testing.T) {
+	repo := &fakePersonalAccessTokenRepo{}
+	svc := newPersonalAccessTokenService(repo)
+	ctx, userID := context.Background(), uuid.New()
+
+	created, err := svc.Create(ctx, userID, &domain.CreatePersonalAccessTokenRequest{
+		Name:          " ci ",
+		Scopes:        []string{domain.ScopeTasksRead, domain.ScopeTasksRead, domain.ScopeAnalyticsRead},
+		ExpiresInDays: 30,
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.Token, domain.PersonalAccessTokenPrefix))
+	assert.True(t, strings.HasPrefix(created.Token, created.Hint))
+	assert.Equal(t, "ci", created.Name)
+	assert.Equal(t, []string{domain.ScopeTasksRead, domain.ScopeAnalyticsRead}, []string(created.Scopes))
+	require.NotNil(t, created.ExpiresAt)
+
+	require.Len(t, repo.tokens, 1)
+	assert.NotEqual(t, created.Token, repo.tokens[0].TokenHash)
+	assert.Len(t, repo.tokens[0].TokenHash, 64)
+
+	pat, err := svc.Authenticate(ctx, created.Token)
+	require.NoError(t, err)
+	assert.Equal(t, userID, pat.UserID)
+}
+
+func TestPersonalAccessTokenService_AuthenticateRefusesUnusableTokens(t *testing.T) {
+	repo := &fakePersonalAccessTokenRepo{}
+	svc := newPersonalAccessTokenService(repo)
+	ctx, userID := context.Background(), uuid.New()
+	req := &domain.CreatePersonalAccessTokenRequest{Name: "script", Scopes: []string{domain.ScopeTasksWrite}}
+
+	_, err := svc.Authenticate(ctx, "tdp_unknown")
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+	_, err = svc.Authenticate(ctx, "eyJhbGciOiJIUzI1NiJ9")
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	revoked, err := svc.Create(ctx, userID, req)
+	require.NoError(t, err)
+	assert.ErrorIs(t, svc.Revoke(ctx, revoked.ID, uuid.New()), domain.ErrNotFound, "only the owner may revoke")
+	require.NoError(t, svc.Revoke(ctx, revoked.ID, userID))
+	_, err = svc.Authenticate(ctx, revoked.Token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+
+	expired, err := svc.Create(ctx, userID, req)
+	require.NoError(t, err)
+	past := time.Now().Add(-time.Second)
+	expired.ExpiresAt = &past
+	_, err = svc.Authenticate(ctx, expired.Token)
+	assert.ErrorIs(t, err, domain.ErrTokenInvalid)
+}
+
+func TestPersonalAccessTokenService_AuthenticateThrottlesLastUsed(t *testing.T) {
+	repo := &fakePersonalAccessTokenRepo{}
+	svc := newPersonalAccessTokenService(repo)
+	ctx := context.Background()
+
+	created, err := svc.Create(ctx, uuid.New(), &domain.CreatePersonalAccessTokenRequest{Name: "sync", Scopes: []string{domain.ScopeTasksRead}})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		_, err := svc.Authenticate(ctx, created.Token)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 1, repo.touches)
+	assert.NotNil(t, created.LastUsedAt)
+}
+
+func TestPersonalAccessTokenService_CreateEnforcesLimit(t *testing.T) {
+	repo := &fakePersonalAccessTokenRepo{}
+	svc := newPersonalAccessTokenService(repo)
+	ctx, userID := context.Background(), uuid.New()
+	req := &domain.CreatePersonalAccessTokenRequest{Name: "bulk", Scopes: []string{domain.ScopeTasksRead}}
+
+	var first *domain.CreatedPersonalAccessToken
+	for i := 0; i < 50; i++ {
+		created, err := svc.Create(ctx, userID, req)
+		require.NoError(t, err)
+		if first == nil {
+			first = created
+		}
+	}
+	_, err := svc.Create(ctx, userID, req)
+	assert.ErrorIs(t, err, domain.ErrQuotaExceeded)
+
+	require.NoError(t, svc.Revoke(ctx, first.ID, userID))
+	_, err = svc.Create(ctx, userID, req)
+	assert.NoError(t, err, "revoking a token frees its slot")
+}
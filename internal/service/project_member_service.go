@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxProjectMembers caps the users one project is shared with.
+const maxProjectMembers = 50
+
+// ListMembers returns everyone with access to the project: its owner first,
+// then its members in the order they joined.
+func (s *ProjectService) ListMembers(ctx context.Context, projectID, userID uuid.UUID) ([]*domain.ProjectMember, error) {
+	project, err := s.GetByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.memberRepo == nil {
+		return nil, domain.ErrFeatureDisabled
+	}
+	owner, err := s.userRepo.FindByID(ctx, project.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.ListMembers: %w", err)
+	}
+	members, err := s.memberRepo.ListByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.ListMembers: %w", err)
+	}
+	for _, m := range members {
+		m.Role = domain.ProjectRoleMember
+	}
+	return append([]*domain.ProjectMember{{
+		ProjectID: project.ID,
+		UserID:    owner.ID,
+		Email:     owner.Email,
+		Name:      owner.Name,
+		Role:      domain.ProjectRoleOwner,
+		CreatedAt: project.CreatedAt,
+	}}, members...), nil
+}
+
+// AddMember shares the project with the user registered under req.Email.
+// Only the owner may share a project.
+func (s *ProjectService) AddMember(ctx context.Context, projectID, userID uuid.UUID, req *domain.AddProjectMemberRequest) (*domain.ProjectMember, error) {
+	project, err := s.getOwned(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if s.memberRepo == nil {
+		return nil, domain.ErrFeatureDisabled
+	}
+	user, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(req.Email))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("projectService.AddMember: no user has the email %q: %w", req.Email, domain.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("projectService.AddMember: %w", err)
+	}
+	if user.ID == project.UserID {
+		return nil, fmt.Errorf("projectService.AddMember: the owner already has access: %w", domain.ErrValidation)
+	}
+
+	n, err := s.memberRepo.CountByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.AddMember: %w", err)
+	}
+	if n >= maxProjectMembers {
+		return nil, fmt.Errorf("projectService.AddMember: at most %d members per project: %w", maxProjectMembers, domain.ErrValidation)
+	}
+
+	member := &domain.ProjectMember{
+		ProjectID: projectID,
+		UserID:    user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		Role:      domain.ProjectRoleMember,
+		CreatedAt: time.Now(),
+	}
+	if err := s.memberRepo.Add(ctx, member); err != nil {
+		return nil, fmt.Errorf("projectService.AddMember: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{"project_id": projectID, "member_id": user.ID}).Info("project shared")
+	return member, nil
+}
+
+// RemoveMember takes memberID's access to the project away. The owner may
+// remove anyone; a member may only remove themselves, leaving the project.
+// Tasks the member created in the project stay in it.
+func (s *ProjectService) RemoveMember(ctx context.Context, projectID, memberID, userID uuid.UUID) error {
+	project, err := s.GetByID(ctx, projectID, userID)
+	if err != nil {
+		return err
+	}
+	if project.UserID != userID && memberID != userID {
+		return domain.ErrForbidden
+	}
+	if s.memberRepo == nil {
+		return domain.ErrFeatureDisabled
+	}
+	if err := s.memberRepo.Remove(ctx, projectID, memberID); err != nil {
+		return fmt.Errorf("projectService.RemoveMember: %w", err)
+	}
+	return nil
+}
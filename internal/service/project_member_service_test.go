@@ -0,0 +1,172 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProjectMemberRepo struct {
+	domain.ProjectMemberRepository
+	members []*domain.ProjectMember
+}
+
+func (f *fakeProjectMemberRepo) Add(_ context.Context, member *domain.ProjectMember) error {
+	for _, m := range f.members {
+		if m.ProjectID == member.ProjectID && m.UserID == member.UserID {
+			return domain.ErrAlreadyExists
+		}
+	}
+	f.members = append(f.members, member)
+	return nil
+}
+
+func (f *fakeProjectMemberRepo) Remove(_ context.Context, projectID, userID uuid.UUID) error {
+	for i, m := range f.members {
+		if m.ProjectID == projectID && m.UserID == userID {
+			f.members = append(f.members[:i], f.members[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (f *fakeProjectMemberRepo) CountByProjectID(_ context.Context, projectID uuid.UUID) (int, error) {
+	n := 0
+	for _, m := range f.members {
+		if m.ProjectID == projectID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeProjectMemberRepo) IsMember(_ context.Context, projectID, userID uuid.UUID) (bool, error) {
+	for _, m := range f.members {
+		if m.ProjectID == projectID && m.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memberUserRepo struct {
+	domain.UserRepository
+	users []*domain.User
+}
+
+func (f *memberUserRepo) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, u := range f.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestProjectService_Members(t *testing.T) {
+	owner := &domain.User{ID: uuid.New(), Email: "ana@example.com"}
+	partner := &domain.User{ID: uuid.New(), Email: "ben@example.com"}
+	project := &domain.Project{ID: uuid.New(), UserID: owner.ID, Name: "Household"}
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, project.ID).Return(project, nil)
+	members := &fakeProjectMemberRepo{}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewProjectService(projectRepo, log)
+	svc.UseMembers(members, &memberUserRepo{users: []*domain.User{owner, partner}})
+	ctx := context.Background()
+
+	_, err := svc.GetByID(ctx, project.ID, partner.ID)
+	assert.ErrorIs(t, err, domain.ErrForbidden, "not shared yet")
+
+	_, err = svc.AddMember(ctx, project.ID, partner.ID, &domain.AddProjectMemberRequest{Email: owner.Email})
+	assert.ErrorIs(t, err, domain.ErrForbidden, "only the owner shares")
+	_, err = svc.AddMember(ctx, project.ID, owner.ID, &domain.AddProjectMemberRequest{Email: "nobody@example.com"})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+	_, err = svc.AddMember(ctx, project.ID, owner.ID, &domain.AddProjectMemberRequest{Email: owner.Email})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	member, err := svc.AddMember(ctx, project.ID, owner.ID, &domain.AddProjectMemberRequest{Email: " ben@example.com "})
+	require.NoError(t, err)
+	assert.Equal(t, partner.ID, member.UserID)
+	assert.Equal(t, domain.ProjectRoleMember, member.Role)
+	_, err = svc.AddMember(ctx, project.ID, owner.ID, &domain.AddProjectMemberRequest{Email: partner.Email})
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	got, err := svc.GetByID(ctx, project.ID, partner.ID)
+	require.NoError(t, err)
+	assert.Equal(t, project.ID, got.ID)
+	_, err = svc.Update(ctx, project.ID, partner.ID, &domain.UpdateProjectRequest{})
+	assert.ErrorIs(t, err, domain.ErrForbidden, "members cannot edit the project itself")
+
+	assert.ErrorIs(t, svc.RemoveMember(ctx, project.ID, owner.ID, partner.ID), domain.ErrForbidden, "a member cannot remove others")
+	require.NoError(t, svc.RemoveMember(ctx, project.ID, partner.ID, partner.ID), "a member may leave")
+	_, err = svc.GetByID(ctx, project.ID, partner.ID)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestTaskService_GetByID_SharedProject(t *testing.T) {
+	ownerID, memberID := uuid.New(), uuid.New()
+	projectID := uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: ownerID, ProjectID: &projectID}
+	private := &domain.Task{ID: uuid.New(), UserID: ownerID}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("FindByID", mock.Anything, private.ID).Return(private, nil)
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: ownerID}, nil)
+	svc := newTaskService(taskRepo, projectRepo)
+	members := &fakeProjectMemberRepo{}
+	svc.UseMembers(members)
+	ctx := context.Background()
+
+	_, err := svc.GetByID(ctx, task.ID, memberID)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	members.members = append(members.members, &domain.ProjectMember{ProjectID: projectID, UserID: memberID})
+	got, err := svc.GetByID(ctx, task.ID, memberID)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, got.ID)
+
+	_, err = svc.GetByID(ctx, private.ID, memberID)
+	assert.ErrorIs(t, err, domain.ErrForbidden, "tasks outside the project stay private")
+}
+
+func TestTaskService_Update_MemberCannotPullTaskFromOwner(t *testing.T) {
+	ownerID, memberID := uuid.New(), uuid.New()
+	shared, memberOwn, ownerOther := uuid.New(), uuid.New(), uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: ownerID, ProjectID: &shared, Status: domain.TaskStatusTodo}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, shared).Return(&domain.Project{ID: shared, UserID: ownerID}, nil)
+	projectRepo.On("FindByID", mock.Anything, memberOwn).Return(&domain.Project{ID: memberOwn, UserID: memberID}, nil)
+	projectRepo.On("FindByID", mock.Anything, ownerOther).Return(&domain.Project{ID: ownerOther, UserID: ownerID}, nil)
+	svc := newTaskService(taskRepo, projectRepo)
+	svc.UseMembers(&fakeProjectMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: shared, UserID: memberID},
+		{ProjectID: ownerOther, UserID: memberID},
+	}})
+	ctx := context.Background()
+
+	_, err := svc.Update(ctx, task.ID, memberID, &domain.UpdateTaskRequest{ProjectID: &memberOwn})
+	assert.ErrorIs(t, err, domain.ErrForbidden, "the owner cannot open the member's project")
+	taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, shared, *task.ProjectID)
+
+	got, err := svc.Update(ctx, task.ID, memberID, &domain.UpdateTaskRequest{ProjectID: &ownerOther})
+	require.NoError(t, err, "both users can reach the target")
+	assert.Equal(t, ownerOther, *got.ProjectID)
+	assert.Equal(t, ownerID, got.UserID)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/pdf"
+	"github.com/google/uuid"
+)
+
+// Checklist layout, in points.
+const (
+	printMargin   = 56.0
+	printLine     = 20.0
+	printIndent   = 18.0
+	printBox      = 9.0
+	printDueCol   = 120.0 // width kept free on the right for due dates
+	printFontSize = 10.0  // body font size
+)
+
+// printGroups are the open statuses a checklist is divided into, in order.
+var printGroups = []struct {
+	status domain.TaskStatus
+	title  string
+}{
+	{domain.TaskStatusInProgress, "In progress"},
+	{domain.TaskStatusTodo, "To do"},
+}
+
+// Print renders the project's open tasks as a printable PDF checklist:
+// grouped by status, each task with a box to tick and its due date, and
+// subtasks indented under their parent. It enforces ownership.
+func (s *ProjectTransferService) Print(ctx context.Context, projectID, userID uuid.UUID, now time.Time) (*domain.Project, []byte, error) {
+	project, err := s.projectSvc.GetByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, err := s.projectTasks(ctx, projectID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("projectTransferService.Print: %w", err)
+	}
+	loc := s.taskSvc.location(ctx, userID)
+
+	p := &checklist{doc: pdf.New(), loc: loc}
+	p.newPage()
+	open := 0
+	for _, t := range tasks {
+		if t.Status != domain.TaskStatusDone {
+			open++
+		}
+	}
+	p.doc.Text(printMargin, p.y, pdf.Bold, 18, pdf.Truncate(project.Name, pdf.Bold, 18, pdf.PageWidth-2*printMargin))
+	p.y -= printLine
+	subtitle := fmt.Sprintf("%d open tasks · printed %s", open, now.In(loc).Format("Mon 2 Jan 2006 15:04"))
+	if name := s.branding.ProductName(); name != "" {
+		subtitle = name + " · " + subtitle
+	}
+	p.doc.Text(printMargin, p.y, pdf.Regular, 9, subtitle)
+	p.y -= 1.5 * printLine
+
+	for _, g := range printGroups {
+		var group []*domain.Task
+		for _, t := range tasks {
+			if t.Status == g.status {
+				group = append(group, t)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+		inGroup := make(map[uuid.UUID]bool, len(group))
+		for _, t := range group {
+			inGroup[t.ID] = true
+		}
+		children := map[uuid.UUID][]*domain.Task{}
+		var roots []*domain.Task
+		for _, t := range group {
+			if t.ParentID != nil && inGroup[*t.ParentID] {
+				children[*t.ParentID] = append(children[*t.ParentID], t)
+			} else {
+				roots = append(roots, t)
+			}
+		}
+
+		p.heading(g.title)
+		var walk func(ts []*domain.Task, depth int)
+		walk = func(ts []*domain.Task, depth int) {
+			for _, t := range ts {
+				p.task(t, depth)
+				walk(children[t.ID], depth+1)
+			}
+		}
+		walk(roots, 0)
+		p.y -= printLine / 2
+	}
+	if open == 0 {
+		p.doc.Text(printMargin, p.y, pdf.Regular, printFontSize, "Nothing left to do.")
+	}
+	return project, p.doc.Bytes(), nil
+}
+
+// checklist lays out a printed project page by page, top to bottom.
+type checklist struct {
+	doc *pdf.Document
+	loc *time.Location
+	y   float64 // baseline of the next line
+}
+
+func (p *checklist) newPage() {
+	p.doc.AddPage()
+	p.y = pdf.PageHeight - printMargin
+	if n := p.doc.Pages(); n > 1 {
+		p.doc.Text(pdf.PageWidth-printMargin-40, printMargin/2, pdf.Regular, 8, fmt.Sprintf("Page %d", n))
+	}
+}
+
+// fit starts a new page unless lines more lines fit on this one.
+func (p *checklist) fit(lines float64) {
+	if p.y-lines*printLine < printMargin {
+		p.newPage()
+	}
+}
+
+func (p *checklist) heading(title string) {
+	p.fit(2)
+	p.doc.Text(printMargin, p.y, pdf.Bold, 12, title)
+	p.doc.Line(printMargin, p.y-4, pdf.PageWidth-printMargin, p.y-4)
+	p.y -= printLine
+}
+
+func (p *checklist) task(t *domain.Task, depth int) {
+	p.fit(1)
+	x := printMargin + float64(depth)*printIndent
+	p.doc.Rect(x, p.y-1, printBox, printBox)
+	width := pdf.PageWidth - printMargin - printDueCol - (x + printBox + 8)
+	title := t.Title
+	if t.Priority == domain.TaskPriorityHigh {
+		title = "! " + title
+	}
+	p.doc.Text(x+printBox+8, p.y, pdf.Regular, printFontSize, pdf.Truncate(title, pdf.Regular, printFontSize, width))
+	if t.DueDate != nil {
+		p.doc.Text(pdf.PageWidth-printMargin-printDueCol+12, p.y, pdf.Regular, 9, "Due "+formatPrintDue(*t.DueDate, p.loc))
+	}
+	p.y -= printLine
+}
+
+// formatPrintDue shows a date-only due date without its midnight.
+func formatPrintDue(due time.Time, loc *time.Location) string {
+	local := due.In(loc)
+	if local.Hour() == 0 && local.Minute() == 0 {
+		return local.Format("Mon 2 Jan 2006")
+	}
+	return local.Format("Mon 2 Jan 15:04")
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
@@ -13,6 +14,9 @@ import (
 // ProjectService handles project management use cases.
 type ProjectService struct {
 	projectRepo domain.ProjectRepository
+	memberRepo  domain.ProjectMemberRepository
+	userRepo    domain.UserRepository
+	events      *eventbus.Bus
 	log         *logrus.Logger
 }
 
@@ -21,6 +25,18 @@ func NewProjectService(projectRepo domain.ProjectRepository, log *logrus.Logger)
 	return &ProjectService{projectRepo: projectRepo, log: log}
 }
 
+// UseEvents publishes project events on bus. Must be called before serving requests.
+func (s *ProjectService) UseEvents(bus *eventbus.Bus) {
+	s.events = bus
+}
+
+// UseMembers enables sharing projects with other users, found by email in
+// userRepo. Without it only owners reach their projects. Must be called
+// before serving requests.
+func (s *ProjectService) UseMembers(memberRepo domain.ProjectMemberRepository, userRepo domain.UserRepository) {
+	s.memberRepo, s.userRepo = memberRepo, userRepo
+}
+
 // Create creates a new project for the authenticated user.
 func (s *ProjectService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateProjectRequest) (*domain.Project, error) {
 	now := time.Now()
@@ -36,6 +52,7 @@ func (s *ProjectService) Create(ctx context.Context, userID uuid.UUID, req *doma
 		Description: req.Description,
 		Type:        req.Type,
 		Color:       color,
+		Icon:        req.Icon,
 		CreatedAt:   now,
 		UpdatedAt:   now,
 	}
@@ -45,22 +62,58 @@ func (s *ProjectService) Create(ctx context.Context, userID uuid.UUID, req *doma
 	}
 
 	s.log.WithFields(logrus.Fields{"project_id": project.ID, "user_id": userID}).Info("project created")
+	s.publish(ctx, domain.ProjectCreated{Project: project})
 	return project, nil
 }
 
-// GetByID retrieves a project, enforcing ownership.
+// GetByID retrieves a project the user owns or is a member of.
 func (s *ProjectService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Project, error) {
 	project, err := s.projectRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	if project.UserID == userID {
+		return project, nil
+	}
+	if s.memberRepo != nil {
+		ok, err := s.memberRepo.IsMember(ctx, id, userID)
+		if err != nil {
+			return nil, fmt.Errorf("projectService.GetByID: %w", err)
+		}
+		if ok {
+			return project, nil
+		}
+	}
+	return nil, domain.ErrForbidden
+}
+
+// Stats summarises a project's tasks for anyone with access to it.
+func (s *ProjectService) Stats(ctx context.Context, id, userID uuid.UUID) (*domain.ProjectStats, error) {
+	project, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.projectRepo.Stats(ctx, project.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.Stats: %w", err)
+	}
+	return stats, nil
+}
+
+// getOwned retrieves a project, enforcing that the user owns it rather than
+// being a member.
+func (s *ProjectService) getOwned(ctx context.Context, id, userID uuid.UUID) (*domain.Project, error) {
+	project, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
 	if project.UserID != userID {
 		return nil, domain.ErrForbidden
 	}
 	return project, nil
 }
 
-// List returns all projects for the authenticated user.
+// List returns the projects the user owns or is a member of.
 func (s *ProjectService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Project, error) {
 	projects, err := s.projectRepo.ListByUserID(ctx, userID)
 	if err != nil {
@@ -69,12 +122,25 @@ func (s *ProjectService) List(ctx context.Context, userID uuid.UUID) ([]*domain.
 	return projects, nil
 }
 
+// ListFields returns the user's projects with only fields loaded: the
+// archived ones when archived is set, the others otherwise.
+func (s *ProjectService) ListFields(ctx context.Context, userID uuid.UUID, fields domain.Fields, archived bool) ([]*domain.Project, error) {
+	projects, err := s.projectRepo.ListFields(ctx, userID, fields, archived)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.ListFields: %w", err)
+	}
+	return projects, nil
+}
+
 // Update applies partial updates to a project, enforcing ownership.
 func (s *ProjectService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateProjectRequest) (*domain.Project, error) {
-	project, err := s.GetByID(ctx, id, userID)
+	project, err := s.getOwned(ctx, id, userID)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkVersion(project.Version, req.IfVersion); err != nil {
+		return nil, err
+	}
 
 	if req.Name != nil {
 		project.Name = *req.Name
@@ -88,26 +154,126 @@ func (s *ProjectService) Update(ctx context.Context, id, userID uuid.UUID, req *
 	if req.Color != nil {
 		project.Color = *req.Color
 	}
+	if req.Icon != nil {
+		project.Icon = *req.Icon
+	}
 
 	project.UpdatedAt = time.Now()
 
-	if err := s.projectRepo.Update(ctx, project); err != nil {
+	if err := s.projectRepo.Update(ctx, project, req.IfVersion); err != nil {
 		return nil, fmt.Errorf("projectService.Update: %w", err)
 	}
 
+	s.publish(ctx, domain.ProjectUpdated{Project: project})
 	return project, nil
 }
 
-// Delete soft-deletes a project, enforcing ownership.
-func (s *ProjectService) Delete(ctx context.Context, id, userID uuid.UUID) error {
-	project, err := s.GetByID(ctx, id, userID)
+// Delete soft-deletes a project, enforcing ownership, and returns how many
+// of its tasks strategy moved to the inbox or deleted with it; an empty
+// strategy is ProjectDeleteInbox. A non-nil ifVersion rejects the delete if
+// the project has changed since that version.
+func (s *ProjectService) Delete(ctx context.Context, id, userID uuid.UUID, ifVersion *int64, strategy domain.ProjectDeleteStrategy) (int, error) {
+	if strategy == "" {
+		strategy = domain.ProjectDeleteInbox
+	}
+	if !strategy.Valid() {
+		return 0, fmt.Errorf("projectService.Delete: strategy must be inbox, cascade or block: %w", domain.ErrValidation)
+	}
+	project, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkVersion(project.Version, ifVersion); err != nil {
+		return 0, err
+	}
+
+	tasks, err := s.projectRepo.Delete(ctx, project.ID, strategy, ifVersion)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("projectService.Delete: %w", err)
+	}
+
+	s.publish(ctx, domain.ProjectDeleted{Project: project})
+	return tasks, nil
+}
+
+// Duplicate copies a project the user can access into a new project of
+// theirs, with its open tasks when req.IncludeTasks is set. Members get the
+// copy too, but only of the tags they own.
+func (s *ProjectService) Duplicate(ctx context.Context, id, userID uuid.UUID, req *domain.DuplicateProjectRequest) (*domain.ProjectDuplicateResult, error) {
+	source, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		// Names are capped at 100 characters, the suffix included.
+		base := []rune(source.Name)
+		if len(base) > 93 {
+			base = base[:93]
+		}
+		name = string(base) + " (copy)"
+	}
+	now := time.Now()
+	project := &domain.Project{
+		ID:          uuid.New(),
+		UserID:      userID,
+		Name:        name,
+		Description: source.Description,
+		Type:        source.Type,
+		Color:       source.Color,
+		Icon:        source.Icon,
+		CreatedAt:   now,
+		UpdatedAt:   now,
 	}
 
-	if err := s.projectRepo.Delete(ctx, project.ID); err != nil {
-		return fmt.Errorf("projectService.Delete: %w", err)
+	tasks, err := s.projectRepo.Duplicate(ctx, source.ID, project, req.IncludeTasks, req.ShiftDays)
+	if err != nil {
+		return nil, fmt.Errorf("projectService.Duplicate: %w", err)
 	}
+	project.TaskCount = tasks
+
+	s.log.WithFields(logrus.Fields{"project_id": project.ID, "source_id": source.ID, "user_id": userID, "tasks": tasks}).Info("project duplicated")
+	s.publish(ctx, domain.ProjectCreated{Project: project})
+	return &domain.ProjectDuplicateResult{Project: project, TaskCount: tasks}, nil
+}
+
+// Archive sets a project aside without deleting it: the project and its
+// tasks leave default lists, smart views and analytics but stay reachable by
+// id and by ?archived=true. Only the owner may archive a project; archiving
+// an archived project changes nothing.
+func (s *ProjectService) Archive(ctx context.Context, id, userID uuid.UUID) (*domain.Project, error) {
+	return s.setArchived(ctx, id, userID, true)
+}
+
+// Unarchive brings an archived project and its tasks back into lists.
+func (s *ProjectService) Unarchive(ctx context.Context, id, userID uuid.UUID) (*domain.Project, error) {
+	return s.setArchived(ctx, id, userID, false)
+}
+
+func (s *ProjectService) setArchived(ctx context.Context, id, userID uuid.UUID, archived bool) (*domain.Project, error) {
+	project, err := s.getOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if (project.ArchivedAt != nil) == archived {
+		return project, nil
+	}
+
+	now := time.Now()
+	var archivedAt *time.Time
+	if archived {
+		archivedAt = &now
+	}
+	if err := s.projectRepo.SetArchived(ctx, project.ID, archivedAt); err != nil {
+		return nil, fmt.Errorf("projectService.setArchived: %w", err)
+	}
+	project.ArchivedAt, project.UpdatedAt = archivedAt, now
+
+	s.publish(ctx, domain.ProjectUpdated{Project: project})
+	return project, nil
+}
 
-	return nil
+func (s *ProjectService) publish(ctx context.Context, e domain.ProjectChange) {
+	s.events.Publish(ctx, e)
 }
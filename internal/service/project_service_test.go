@@ -0,0 +1,104 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectService_ArchiveAndUnarchive(t *testing.T) {
+	ownerID, projectID := uuid.New(), uuid.New()
+	project := &domain.Project{ID: projectID, UserID: ownerID}
+	repo := &mockProjectRepo{}
+	repo.On("FindByID", mock.Anything, projectID).Return(project, nil)
+	repo.On("SetArchived", mock.Anything, projectID, mock.AnythingOfType("*time.Time")).Return(nil)
+	svc := service.NewProjectService(repo, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.Archive(ctx, projectID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	archived, err := svc.Archive(ctx, projectID, ownerID)
+	require.NoError(t, err)
+	assert.NotNil(t, archived.ArchivedAt)
+
+	_, err = svc.Archive(ctx, projectID, ownerID)
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "SetArchived", 1)
+
+	restored, err := svc.Unarchive(ctx, projectID, ownerID)
+	require.NoError(t, err)
+	assert.Nil(t, restored.ArchivedAt)
+	repo.AssertNumberOfCalls(t, "SetArchived", 2)
+}
+
+func TestProjectService_Stats(t *testing.T) {
+	ownerID, projectID := uuid.New(), uuid.New()
+	repo := &mockProjectRepo{}
+	repo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: ownerID}, nil)
+	want := &domain.ProjectStats{ProjectID: projectID, Total: 4, ByStatus: domain.ProjectStatusCount{Todo: 3, Done: 1}, CompletionRate: 25}
+	repo.On("Stats", mock.Anything, projectID, ownerID).Return(want, nil)
+	svc := service.NewProjectService(repo, logrus.New())
+
+	stats, err := svc.Stats(context.Background(), projectID, ownerID)
+	require.NoError(t, err)
+	assert.Equal(t, want, stats)
+
+	_, err = svc.Stats(context.Background(), projectID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	repo.AssertNumberOfCalls(t, "Stats", 1)
+}
+
+func TestProjectService_DeleteStrategy(t *testing.T) {
+	ownerID, projectID := uuid.New(), uuid.New()
+	repo := &mockProjectRepo{}
+	repo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: ownerID, Version: 3}, nil)
+	repo.On("Delete", mock.Anything, projectID, domain.ProjectDeleteInbox, mock.Anything).Return(4, nil).Once()
+	repo.On("Delete", mock.Anything, projectID, domain.ProjectDeleteBlock, mock.Anything).Return(0, domain.ErrProjectNotEmpty).Once()
+	svc := service.NewProjectService(repo, logrus.New())
+	ctx := context.Background()
+
+	moved, err := svc.Delete(ctx, projectID, ownerID, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, 4, moved, "tasks go to the inbox by default")
+
+	_, err = svc.Delete(ctx, projectID, ownerID, nil, domain.ProjectDeleteBlock)
+	assert.ErrorIs(t, err, domain.ErrProjectNotEmpty)
+
+	_, err = svc.Delete(ctx, projectID, ownerID, nil, "archive")
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	stale := int64(2)
+	_, err = svc.Delete(ctx, projectID, ownerID, &stale, domain.ProjectDeleteCascade)
+	assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+	repo.AssertNumberOfCalls(t, "Delete", 2)
+}
+
+func TestProjectService_Duplicate(t *testing.T) {
+	ownerID, projectID := uuid.New(), uuid.New()
+	source := &domain.Project{ID: projectID, UserID: ownerID, Name: "Moving house", Type: domain.ProjectTypePersonal, Color: "#10B981", Icon: "home"}
+	repo := &mockProjectRepo{}
+	repo.On("FindByID", mock.Anything, projectID).Return(source, nil)
+	repo.On("Duplicate", mock.Anything, projectID, mock.AnythingOfType("*domain.Project"), true, 7).Return(5, nil).Once()
+	svc := service.NewProjectService(repo, logrus.New())
+	ctx := context.Background()
+
+	result, err := svc.Duplicate(ctx, projectID, ownerID, &domain.DuplicateProjectRequest{IncludeTasks: true, ShiftDays: 7})
+	require.NoError(t, err)
+	assert.Equal(t, 5, result.TaskCount)
+	assert.NotEqual(t, projectID, result.Project.ID)
+	assert.Equal(t, ownerID, result.Project.UserID)
+	assert.Equal(t, "Moving house (copy)", result.Project.Name)
+	assert.Equal(t, source.Icon, result.Project.Icon)
+
+	_, err = svc.Duplicate(ctx, projectID, uuid.New(), &domain.DuplicateProjectRequest{})
+	assert.ErrorIs(t, err, domain.ErrForbidden, "only those with access may duplicate")
+	repo.AssertNumberOfCalls(t, "Duplicate", 1)
+}
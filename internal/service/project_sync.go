@@ -0,0 +1,442 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Sync converges the user's project called name on the desired state,
+// creating the project if it does not exist. Tasks are matched by title
+// among their unarchived siblings; unmatched desired tasks are created, and
+// tasks the document no longer lists are archived together with their
+// subtasks. With dryRun the diff is computed but nothing is written.
+func (s *ProjectTransferService) Sync(ctx context.Context, userID uuid.UUID, name string, desired *domain.DesiredProject, dryRun bool) (*domain.ProjectSyncResult, error) {
+	tagNames, err := checkDesired(desired)
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+	}
+
+	project, err := s.findProject(ctx, userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+	}
+
+	result := &domain.ProjectSyncResult{
+		DryRun:   dryRun,
+		Project:  domain.ProjectSyncChange{Name: name, Action: domain.SyncUnchanged},
+		Created:  []domain.TaskSyncChange{},
+		Updated:  []domain.TaskSyncChange{},
+		Archived: []domain.TaskSyncChange{},
+	}
+	var projectID *uuid.UUID
+	var tasks []*domain.Task
+	if project == nil {
+		result.Project.Action = domain.SyncCreated
+		if !dryRun {
+			project, err = s.projectSvc.Create(ctx, userID, &domain.CreateProjectRequest{
+				Name:        name,
+				Description: desired.Description,
+				Type:        desired.Type,
+				Color:       desired.Color,
+				Icon:        desired.Icon,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+			}
+			projectID = &project.ID
+		}
+	} else {
+		projectID = &project.ID
+		if changes := diffProject(project, desired); len(changes) > 0 {
+			result.Project.Action = domain.SyncUpdated
+			result.Project.Changes = changes
+			if !dryRun {
+				req := &domain.UpdateProjectRequest{Description: &desired.Description, Type: &desired.Type, Color: &desired.Color, Icon: &desired.Icon}
+				if _, err := s.projectSvc.Update(ctx, project.ID, userID, req); err != nil {
+					return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+				}
+			}
+		}
+		if tasks, err = s.projectTasks(ctx, project.ID, userID); err != nil {
+			return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+		}
+	}
+	result.Project.ID = projectID
+
+	// Missing tags are only created when the sync is applied; a dry run
+	// compares by name alone.
+	tagIDs := map[string]uuid.UUID{}
+	if !dryRun {
+		if tagIDs, _, err = s.resolveTags(ctx, userID, nil, tagNames); err != nil {
+			return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+		}
+	}
+
+	inProject := make(map[uuid.UUID]bool, len(tasks))
+	for _, t := range tasks {
+		inProject[t.ID] = true
+	}
+	children := map[uuid.UUID][]*domain.Task{}
+	var roots []*domain.Task
+	for _, t := range tasks {
+		if t.ParentID != nil && inProject[*t.ParentID] {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	sc := &projectSync{
+		svc:       s,
+		userID:    userID,
+		projectID: projectID,
+		dryRun:    dryRun,
+		tagIDs:    tagIDs,
+		children:  children,
+		result:    result,
+	}
+	if err := sc.level(ctx, desired.Tasks, roots, nil, ""); err != nil {
+		return nil, fmt.Errorf("projectTransferService.Sync: %w", err)
+	}
+
+	if !dryRun {
+		s.log.WithFields(logrus.Fields{
+			"project_id": *projectID,
+			"user_id":    userID,
+			"created":    len(result.Created),
+			"updated":    len(result.Updated),
+			"archived":   len(result.Archived),
+		}).Info("project synced")
+	}
+	return result, nil
+}
+
+// findProject returns the user's project with exactly this name, nil if
+// there is none, or ErrAlreadyExists when the name is ambiguous.
+func (s *ProjectTransferService) findProject(ctx context.Context, userID uuid.UUID, name string) (*domain.Project, error) {
+	projects, err := s.projectSvc.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var found *domain.Project
+	for _, p := range projects {
+		if p.Name != name {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("more than one project is named %q: %w", name, domain.ErrAlreadyExists)
+		}
+		found = p
+	}
+	return found, nil
+}
+
+// projectSync holds the state of one Sync call while it walks the task tree.
+type projectSync struct {
+	svc       *ProjectTransferService
+	userID    uuid.UUID
+	projectID *uuid.UUID // nil when a dry run would create the project
+	dryRun    bool
+	tagIDs    map[string]uuid.UUID
+	children  map[uuid.UUID][]*domain.Task
+	result    *domain.ProjectSyncResult
+}
+
+// level syncs one set of siblings. Tasks sharing a title are paired up in
+// creation order; the unmatched desired tasks are created together once
+// the matched ones are synced.
+func (p *projectSync) level(ctx context.Context, desired []domain.DesiredTask, current []*domain.Task, parentID *uuid.UUID, parentPath string) error {
+	byTitle := map[string][]*domain.Task{}
+	for _, t := range current {
+		byTitle[t.Title] = append(byTitle[t.Title], t)
+	}
+
+	var missing []*domain.DesiredTask
+	for i := range desired {
+		dt := &desired[i]
+		path := syncPath(parentPath, dt.Title)
+		matches := byTitle[dt.Title]
+		if len(matches) == 0 {
+			missing = append(missing, dt)
+			continue
+		}
+		task := matches[0]
+		byTitle[dt.Title] = matches[1:]
+		if err := p.update(ctx, dt, task, path); err != nil {
+			return err
+		}
+		if err := p.level(ctx, dt.Subtasks, p.children[task.ID], &task.ID, path); err != nil {
+			return err
+		}
+	}
+	if len(missing) > 0 {
+		if err := p.create(ctx, missing, parentID, parentPath); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range current {
+		left := byTitle[t.Title]
+		if len(left) == 0 || left[0] != t {
+			continue
+		}
+		byTitle[t.Title] = left[1:]
+		if err := p.archive(ctx, t, syncPath(parentPath, t.Title)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// create adds desired siblings and their subtasks, reporting each in
+// document order.
+func (p *projectSync) create(ctx context.Context, desired []*domain.DesiredTask, parentID *uuid.UUID, parentPath string) error {
+	ids := map[*domain.DesiredTask]*uuid.UUID{}
+	if !p.dryRun {
+		if err := p.store(ctx, desired, parentID, ids); err != nil {
+			return err
+		}
+	}
+	p.reportCreated(desired, parentPath, ids)
+	return nil
+}
+
+// store creates the desired tasks and their subtasks, a batch per level of
+// the tree, recording each new task's id.
+func (p *projectSync) store(ctx context.Context, level []*domain.DesiredTask, parentID *uuid.UUID, ids map[*domain.DesiredTask]*uuid.UUID) error {
+	parents := make([]*uuid.UUID, len(level))
+	for i := range parents {
+		parents[i] = parentID
+	}
+	for len(level) > 0 {
+		reqs := make([]*domain.CreateTaskRequest, len(level))
+		for i, dt := range level {
+			_, priority := desiredDefaults(dt)
+			reqs[i] = &domain.CreateTaskRequest{
+				ProjectID:      p.projectID,
+				ParentID:       parents[i],
+				Title:          dt.Title,
+				Description:    dt.Description,
+				Priority:       priority,
+				EstimatedHours: dt.EstimatedHours,
+				DueDate:        dt.DueDate,
+			}
+		}
+		tasks, err := p.svc.taskSvc.CreateBatch(ctx, p.userID, reqs)
+		if err != nil {
+			return err
+		}
+
+		var next []*domain.DesiredTask
+		var nextParents []*uuid.UUID
+		for i, dt := range level {
+			task := tasks[i]
+			ids[dt] = &task.ID
+			if len(dt.Tags) > 0 {
+				if _, err := p.svc.tagSvc.SetForTask(ctx, task.ID, p.userID, p.tagsFor(dt.Tags)); err != nil {
+					return err
+				}
+			}
+			if status, _ := desiredDefaults(dt); status != domain.TaskStatusTodo {
+				if _, err := p.svc.taskSvc.Update(ctx, task.ID, p.userID, &domain.UpdateTaskRequest{Status: &status}); err != nil {
+					return err
+				}
+			}
+			for j := range dt.Subtasks {
+				next = append(next, &dt.Subtasks[j])
+				nextParents = append(nextParents, &task.ID)
+			}
+		}
+		level, parents = next, nextParents
+	}
+	return nil
+}
+
+// reportCreated records the created tasks depth first; ids is empty on a
+// dry run.
+func (p *projectSync) reportCreated(desired []*domain.DesiredTask, parentPath string, ids map[*domain.DesiredTask]*uuid.UUID) {
+	for _, dt := range desired {
+		path := syncPath(parentPath, dt.Title)
+		p.result.Created = append(p.result.Created, domain.TaskSyncChange{ID: ids[dt], Path: path})
+		subtasks := make([]*domain.DesiredTask, len(dt.Subtasks))
+		for i := range dt.Subtasks {
+			subtasks[i] = &dt.Subtasks[i]
+		}
+		p.reportCreated(subtasks, path, ids)
+	}
+}
+
+// update brings a matched task in line with its desired state.
+func (p *projectSync) update(ctx context.Context, dt *domain.DesiredTask, task *domain.Task, path string) error {
+	status, priority := desiredDefaults(dt)
+	req := &domain.UpdateTaskRequest{
+		Description:         &dt.Description,
+		Status:              &status,
+		Priority:            &priority,
+		EstimatedHours:      dt.EstimatedHours,
+		DueDate:             dt.DueDate,
+		ClearEstimatedHours: dt.EstimatedHours == nil,
+		ClearDueDate:        dt.DueDate == nil,
+	}
+	after := *task
+	after.Description = dt.Description
+	after.Status = status
+	after.Priority = priority
+	after.EstimatedHours = dt.EstimatedHours
+	after.DueDate = dt.DueDate
+	changes := domain.DiffTasks(task, &after)
+
+	currentTags, err := p.svc.tagSvc.ListForTask(ctx, task.ID, p.userID)
+	if err != nil {
+		return err
+	}
+	oldNames := make([]string, 0, len(currentTags))
+	for _, t := range currentTags {
+		oldNames = append(oldNames, t.Name)
+	}
+	newNames := append([]string{}, dt.Tags...)
+	tagsChanged := !sameTagNames(oldNames, newNames)
+	if tagsChanged {
+		changes["tags"] = domain.FieldChange{Old: oldNames, New: newNames}
+	}
+
+	if len(changes) == 0 {
+		p.result.Unchanged++
+		return nil
+	}
+	if !p.dryRun {
+		if len(changes) > 1 || !tagsChanged {
+			if _, _, err := p.svc.taskSvc.UpdateWithChanges(ctx, task.ID, p.userID, req); err != nil {
+				return err
+			}
+		}
+		if tagsChanged {
+			if _, err := p.svc.tagSvc.SetForTask(ctx, task.ID, p.userID, p.tagsFor(dt.Tags)); err != nil {
+				return err
+			}
+		}
+	}
+	p.result.Updated = append(p.result.Updated, domain.TaskSyncChange{ID: &task.ID, Path: path, Changes: changes})
+	return nil
+}
+
+// archive hides a task the document no longer lists, subtasks first.
+func (p *projectSync) archive(ctx context.Context, task *domain.Task, path string) error {
+	for _, child := range p.children[task.ID] {
+		if err := p.archive(ctx, child, syncPath(path, child.Title)); err != nil {
+			return err
+		}
+	}
+	if !p.dryRun {
+		if _, err := p.svc.taskSvc.Archive(ctx, task.ID, p.userID); err != nil {
+			return err
+		}
+	}
+	p.result.Archived = append(p.result.Archived, domain.TaskSyncChange{ID: &task.ID, Path: path})
+	return nil
+}
+
+func (p *projectSync) tagsFor(names []string) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(names))
+	for _, name := range names {
+		ids = append(ids, p.tagIDs[strings.ToLower(name)])
+	}
+	return ids
+}
+
+// checkDesired caps the document's size and returns the distinct tag names
+// it uses.
+func checkDesired(desired *domain.DesiredProject) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	count := 0
+	var walk func(tasks []domain.DesiredTask) error
+	walk = func(tasks []domain.DesiredTask) error {
+		for i := range tasks {
+			if count++; count > maxBundleTasks {
+				return fmt.Errorf("document has more than %d tasks: %w", maxBundleTasks, domain.ErrValidation)
+			}
+			for _, name := range tasks[i].Tags {
+				if lower := strings.ToLower(name); !seen[lower] {
+					seen[lower] = true
+					names = append(names, name)
+				}
+			}
+			if err := walk(tasks[i].Subtasks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(desired.Tasks); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func diffProject(project *domain.Project, desired *domain.DesiredProject) domain.TaskChanges {
+	changes := domain.TaskChanges{}
+	if project.Description != desired.Description {
+		changes["description"] = domain.FieldChange{Old: project.Description, New: desired.Description}
+	}
+	if project.Type != desired.Type {
+		changes["type"] = domain.FieldChange{Old: project.Type, New: desired.Type}
+	}
+	if project.Color != desired.Color {
+		changes["color"] = domain.FieldChange{Old: project.Color, New: desired.Color}
+	}
+	if project.Icon != desired.Icon {
+		changes["icon"] = domain.FieldChange{Old: project.Icon, New: desired.Icon}
+	}
+	return changes
+}
+
+func desiredDefaults(dt *domain.DesiredTask) (domain.TaskStatus, domain.TaskPriority) {
+	status, priority := dt.Status, dt.Priority
+	if status == "" {
+		status = domain.TaskStatusTodo
+	}
+	if priority == "" {
+		priority = domain.TaskPriorityMedium
+	}
+	return status, priority
+}
+
+// sameTagNames compares two tag name lists as sets, ignoring case.
+func sameTagNames(a, b []string) bool {
+	norm := func(names []string) []string {
+		seen := map[string]bool{}
+		out := []string{}
+		for _, n := range names {
+			if lower := strings.ToLower(n); !seen[lower] {
+				seen[lower] = true
+				out = append(out, lower)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+	x, y := norm(a), norm(b)
+	if len(x) != len(y) {
+		return false
+	}
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func syncPath(parent, title string) string {
+	if parent == "" {
+		return title
+	}
+	return parent + " / " + title
+}
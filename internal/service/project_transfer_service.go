@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxBundleTasks caps the tasks in one import, subtasks included.
+	maxBundleTasks = 2000
+	// exportPageSize is how many tasks an export loads per query.
+	exportPageSize = 200
+)
+
+// ProjectTransferService exports a project with its tasks, tags and
+// dependencies as a ProjectBundle, and imports bundles as new projects.
+type ProjectTransferService struct {
+	projectSvc *ProjectService
+	taskSvc    *TaskService
+	tagSvc     *TagService
+	depSvc     *TaskDependencyService
+	branding   *BrandingService
+	log        *logrus.Logger
+}
+
+// NewProjectTransferService constructs a ProjectTransferService.
+func NewProjectTransferService(
+	projectSvc *ProjectService,
+	taskSvc *TaskService,
+	tagSvc *TagService,
+	depSvc *TaskDependencyService,
+	log *logrus.Logger,
+) *ProjectTransferService {
+	return &ProjectTransferService{projectSvc: projectSvc, taskSvc: taskSvc, tagSvc: tagSvc, depSvc: depSvc, log: log}
+}
+
+// UseBranding puts the workspace name on printed checklists. Must be called
+// before serving requests.
+func (s *ProjectTransferService) UseBranding(b *BrandingService) {
+	s.branding = b
+}
+
+// Export returns the project as a bundle, enforcing ownership. Subtasks whose
+// parent lives in another project are exported at the top level, and
+// dependencies on tasks outside the project are dropped.
+func (s *ProjectTransferService) Export(ctx context.Context, projectID, userID uuid.UUID) (*domain.ProjectBundle, error) {
+	project, err := s.projectSvc.GetByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.projectTasks(ctx, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Export: %w", err)
+	}
+	inProject := make(map[uuid.UUID]bool, len(tasks))
+	for _, t := range tasks {
+		inProject[t.ID] = true
+	}
+
+	children := map[uuid.UUID][]*domain.Task{}
+	var roots []*domain.Task
+	for _, t := range tasks {
+		if t.ParentID != nil && inProject[*t.ParentID] {
+			children[*t.ParentID] = append(children[*t.ParentID], t)
+		} else {
+			roots = append(roots, t)
+		}
+	}
+
+	// Blockers get a key so blocked_by can name them.
+	blockers := map[uuid.UUID][]uuid.UUID{}
+	keys := map[uuid.UUID]string{}
+	for _, t := range tasks {
+		deps, err := s.depSvc.List(ctx, t.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("projectTransferService.Export: %w", err)
+		}
+		for _, b := range deps.BlockedBy {
+			if inProject[b.ID] {
+				blockers[t.ID] = append(blockers[t.ID], b.ID)
+				keys[b.ID] = ""
+			}
+		}
+	}
+	n := 0
+	walkTasks(roots, children, func(t *domain.Task) {
+		if _, ok := keys[t.ID]; ok {
+			n++
+			keys[t.ID] = "t" + strconv.Itoa(n)
+		}
+	})
+
+	tagsByName := map[string]domain.BundleTag{}
+	var build func(t *domain.Task) (domain.BundleTask, error)
+	build = func(t *domain.Task) (domain.BundleTask, error) {
+		bt := domain.BundleTask{
+			Key:            keys[t.ID],
+			Title:          t.Title,
+			Description:    t.Description,
+			Status:         t.Status,
+			Priority:       t.Priority,
+			EstimatedHours: t.EstimatedHours,
+			DueDate:        t.DueDate,
+		}
+		// A symbolic due date travels as such, to resolve anew on import.
+		if t.DueExpr != nil {
+			bt.DueExpr, bt.DueDate = *t.DueExpr, nil
+		}
+		tags, err := s.tagSvc.ListForTask(ctx, t.ID, userID)
+		if err != nil {
+			return bt, err
+		}
+		for _, tag := range tags {
+			bt.Tags = append(bt.Tags, tag.Name)
+			tagsByName[tag.Name] = domain.BundleTag{Name: tag.Name, Color: tag.Color}
+		}
+		for _, id := range blockers[t.ID] {
+			bt.BlockedBy = append(bt.BlockedBy, keys[id])
+		}
+		for _, child := range children[t.ID] {
+			sub, err := build(child)
+			if err != nil {
+				return bt, err
+			}
+			bt.Subtasks = append(bt.Subtasks, sub)
+		}
+		return bt, nil
+	}
+
+	bundle := &domain.ProjectBundle{
+		Version: domain.ProjectBundleVersion,
+		Project: domain.BundleProject{
+			Name:        project.Name,
+			Description: project.Description,
+			Type:        project.Type,
+			Color:       project.Color,
+			Icon:        project.Icon,
+		},
+	}
+	for _, t := range roots {
+		bt, err := build(t)
+		if err != nil {
+			return nil, fmt.Errorf("projectTransferService.Export: %w", err)
+		}
+		bundle.Tasks = append(bundle.Tasks, bt)
+	}
+	for _, tag := range tagsByName {
+		bundle.Tags = append(bundle.Tags, tag)
+	}
+	sort.Slice(bundle.Tags, func(i, j int) bool { return bundle.Tags[i].Name < bundle.Tags[j].Name })
+	return bundle, nil
+}
+
+// Import creates a new project from a bundle that has passed struct
+// validation. Keys, references and dependency cycles are checked before
+// anything is written.
+func (s *ProjectTransferService) Import(ctx context.Context, userID uuid.UUID, bundle *domain.ProjectBundle) (*domain.ProjectImportResult, error) {
+	tagNames, err := checkBundle(bundle)
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+	}
+
+	project, err := s.projectSvc.Create(ctx, userID, &domain.CreateProjectRequest{
+		Name:        bundle.Project.Name,
+		Description: bundle.Project.Description,
+		Type:        bundle.Project.Type,
+		Color:       bundle.Project.Color,
+		Icon:        bundle.Project.Icon,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+	}
+	result := &domain.ProjectImportResult{Project: project}
+
+	tagIDs, created, err := s.resolveTags(ctx, userID, bundle.Tags, tagNames)
+	if err != nil {
+		return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+	}
+	result.TagsCreated = created
+
+	// Tasks are created a batch per level of the tree, since a subtask's
+	// parent must be stored first.
+	keyed := map[string]uuid.UUID{}
+	var pending []pendingDeps
+	level := make([]*domain.BundleTask, len(bundle.Tasks))
+	for i := range bundle.Tasks {
+		level[i] = &bundle.Tasks[i]
+	}
+	parents := make([]*uuid.UUID, len(level))
+	for len(level) > 0 {
+		reqs := make([]*domain.CreateTaskRequest, len(level))
+		for i, bt := range level {
+			priority := bt.Priority
+			if priority == "" {
+				priority = domain.TaskPriorityMedium
+			}
+			reqs[i] = &domain.CreateTaskRequest{
+				ProjectID:      &project.ID,
+				ParentID:       parents[i],
+				Title:          bt.Title,
+				Description:    bt.Description,
+				Priority:       priority,
+				EstimatedHours: bt.EstimatedHours,
+				DueDate:        bt.DueDate,
+			}
+			if bt.DueExpr != "" {
+				reqs[i].DueExpr, reqs[i].DueDate = &bt.DueExpr, nil
+			}
+		}
+		tasks, err := s.taskSvc.CreateBatch(ctx, userID, reqs)
+		if err != nil {
+			return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+		}
+
+		var next []*domain.BundleTask
+		var nextParents []*uuid.UUID
+		for i, bt := range level {
+			task := tasks[i]
+			result.TaskCount++
+			if bt.Key != "" {
+				keyed[bt.Key] = task.ID
+			}
+			if len(bt.BlockedBy) > 0 {
+				pending = append(pending, pendingDeps{taskID: task.ID, blockedBy: bt.BlockedBy})
+			}
+			if len(bt.Tags) > 0 {
+				ids := make([]uuid.UUID, 0, len(bt.Tags))
+				for _, name := range bt.Tags {
+					ids = append(ids, tagIDs[strings.ToLower(name)])
+				}
+				if _, err := s.tagSvc.SetForTask(ctx, task.ID, userID, ids); err != nil {
+					return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+				}
+			}
+			// Statuses go in before dependencies so finished tasks are not
+			// refused as blocked.
+			if bt.Status != "" && bt.Status != domain.TaskStatusTodo {
+				if _, err := s.taskSvc.Update(ctx, task.ID, userID, &domain.UpdateTaskRequest{Status: &bt.Status}); err != nil {
+					return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+				}
+			}
+			for j := range bt.Subtasks {
+				next = append(next, &bt.Subtasks[j])
+				nextParents = append(nextParents, &task.ID)
+			}
+		}
+		level, parents = next, nextParents
+	}
+	for _, p := range pending {
+		for _, key := range p.blockedBy {
+			if _, err := s.depSvc.Add(ctx, p.taskID, userID, keyed[key]); err != nil {
+				return nil, fmt.Errorf("projectTransferService.Import: %w", err)
+			}
+		}
+	}
+
+	s.log.WithFields(logrus.Fields{"project_id": project.ID, "user_id": userID, "tasks": result.TaskCount}).Info("project imported")
+	return result, nil
+}
+
+type pendingDeps struct {
+	taskID    uuid.UUID
+	blockedBy []string
+}
+
+// projectTasks loads every task of the project, oldest first.
+func (s *ProjectTransferService) projectTasks(ctx context.Context, projectID, userID uuid.UUID) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for page := 1; ; page++ {
+		batch, total, err := s.taskSvc.List(ctx, userID, domain.TaskFilter{ProjectIDs: []uuid.UUID{projectID}, IncludeDeferred: true}, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, batch...)
+		if len(batch) < exportPageSize || len(tasks) >= total {
+			break
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+	return tasks, nil
+}
+
+// resolveTags maps every lower-cased tag name to a tag id, creating the
+// tags the user does not have yet. Colours come from the bundle's tag list.
+func (s *ProjectTransferService) resolveTags(ctx context.Context, userID uuid.UUID, declared []domain.BundleTag, names []string) (map[string]uuid.UUID, int, error) {
+	ids := map[string]uuid.UUID{}
+	if len(names) == 0 {
+		return ids, 0, nil
+	}
+	existing, err := s.tagSvc.List(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, t := range existing {
+		ids[strings.ToLower(t.Name)] = t.ID
+	}
+	colors := map[string]string{}
+	for _, t := range declared {
+		colors[strings.ToLower(t.Name)] = t.Color
+	}
+
+	created := 0
+	for _, name := range names {
+		lower := strings.ToLower(name)
+		if _, ok := ids[lower]; ok {
+			continue
+		}
+		tag, err := s.tagSvc.Create(ctx, userID, &domain.CreateTagRequest{Name: name, Color: colors[lower]})
+		if err != nil {
+			return nil, 0, fmt.Errorf("tag %q: %w", name, err)
+		}
+		ids[lower] = tag.ID
+		created++
+	}
+	return ids, created, nil
+}
+
+// checkBundle checks what struct tags cannot: task keys are unique,
+// blocked_by names known keys without forming a cycle, and the bundle is not
+// too large. It returns the distinct tag names in use, declared first.
+func checkBundle(bundle *domain.ProjectBundle) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	addName := func(name string) {
+		if lower := strings.ToLower(name); !seen[lower] {
+			seen[lower] = true
+			names = append(names, name)
+		}
+	}
+	for _, t := range bundle.Tags {
+		addName(t.Name)
+	}
+
+	keys := map[string]bool{}
+	edges := map[string][]string{}
+	count := 0
+	var walk func(tasks []domain.BundleTask) error
+	walk = func(tasks []domain.BundleTask) error {
+		for i := range tasks {
+			t := &tasks[i]
+			if count++; count > maxBundleTasks {
+				return fmt.Errorf("bundle has more than %d tasks: %w", maxBundleTasks, domain.ErrValidation)
+			}
+			if t.Key != "" {
+				if keys[t.Key] {
+					return fmt.Errorf("duplicate task key %q: %w", t.Key, domain.ErrValidation)
+				}
+				keys[t.Key] = true
+			}
+			if len(t.BlockedBy) > 0 {
+				if t.Key == "" {
+					// Give it a private key so cycles through it are found.
+					t.Key = "\x00" + strconv.Itoa(count)
+				}
+				edges[t.Key] = t.BlockedBy
+			}
+			for _, name := range t.Tags {
+				addName(name)
+			}
+			if t.DueExpr != "" {
+				if _, err := domain.ParseDueExpr(t.DueExpr); err != nil {
+					return fmt.Errorf("task %q: %v: %w", t.Title, err, domain.ErrValidation)
+				}
+			}
+			if err := walk(t.Subtasks); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(bundle.Tasks); err != nil {
+		return nil, err
+	}
+
+	for key, blockers := range edges {
+		for _, b := range blockers {
+			if !keys[b] {
+				return nil, fmt.Errorf("blocked_by %q does not name a task key: %w", b, domain.ErrValidation)
+			}
+			if b == key {
+				return nil, fmt.Errorf("task %q cannot block itself: %w", key, domain.ErrValidation)
+			}
+		}
+	}
+	if hasCycle(edges) {
+		return nil, fmt.Errorf("blocked_by forms a cycle: %w", domain.ErrValidation)
+	}
+	return names, nil
+}
+
+// hasCycle reports whether the blocked_by graph has a cycle.
+func hasCycle(edges map[string][]string) bool {
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var visit func(key string) bool
+	visit = func(key string) bool {
+		switch state[key] {
+		case visiting:
+			return true
+		case done:
+			return false
+		}
+		state[key] = visiting
+		for _, next := range edges[key] {
+			if visit(next) {
+				return true
+			}
+		}
+		state[key] = done
+		return false
+	}
+	for key := range edges {
+		if visit(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// walkTasks visits tasks depth-first in export order.
+func walkTasks(tasks []*domain.Task, children map[uuid.UUID][]*domain.Task, fn func(*domain.Task)) {
+	for _, t := range tasks {
+		fn(t)
+		walkTasks(children[t.ID], children, fn)
+	}
+}
@@ -0,0 +1,250 @@
+package service_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newProjectTransferService(taskRepo *mockTaskRepo, projectRepo *mockProjectRepo, tags *fakeTagRepo, deps *fakeDependencyRepo) *service.ProjectTransferService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	taskSvc := newTaskService(taskRepo, projectRepo)
+	return service.NewProjectTransferService(
+		service.NewProjectService(projectRepo, log),
+		taskSvc,
+		service.NewTagService(tags, taskSvc, log),
+		service.NewTaskDependencyService(deps, taskSvc, log),
+		log,
+	)
+}
+
+func TestProjectTransferService_Import(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Project{UserID: userID}, nil)
+
+	var created []*domain.Task
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).([]*domain.Task)...)
+	}).Return(nil)
+	taskRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Task{UserID: userID}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	tags := &fakeTagRepo{tags: []*domain.Tag{{ID: uuid.New(), UserID: userID, Name: "Home"}}}
+	deps := &fakeDependencyRepo{}
+	svc := newProjectTransferService(taskRepo, projectRepo, tags, deps)
+
+	bundle := &domain.ProjectBundle{
+		Version: domain.ProjectBundleVersion,
+		Project: domain.BundleProject{Name: "Move house", Type: domain.ProjectTypePersonal},
+		Tags:    []domain.BundleTag{{Name: "errand", Color: "#FF0000"}},
+		Tasks: []domain.BundleTask{
+			{Key: "t1", Title: "Book van", Tags: []string{"errand", "home"}, Status: domain.TaskStatusDone},
+			{Title: "Pack", BlockedBy: []string{"t1"}, Subtasks: []domain.BundleTask{{Title: "Kitchen"}}},
+		},
+	}
+	result, err := svc.Import(context.Background(), userID, bundle)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.TaskCount)
+	assert.Equal(t, 1, result.TagsCreated, "home matches the existing tag ignoring case")
+	require.Len(t, created, 3)
+	for _, task := range created {
+		assert.Equal(t, &result.Project.ID, task.ProjectID)
+		assert.Equal(t, domain.TaskPriorityMedium, task.Priority)
+	}
+	assert.Equal(t, &created[1].ID, created[2].ParentID)
+	assert.Len(t, tags.onTask[created[0].ID], 2)
+	assert.Equal(t, []uuid.UUID{created[0].ID}, deps.blockers[created[1].ID])
+	taskRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestProjectTransferService_ImportRejectsBrokenReferences(t *testing.T) {
+	cases := map[string][]domain.BundleTask{
+		"unknown key":   {{Title: "a", BlockedBy: []string{"nope"}}},
+		"duplicate key": {{Key: "k", Title: "a"}, {Key: "k", Title: "b"}},
+		"cycle": {
+			{Key: "a", Title: "a", BlockedBy: []string{"b"}},
+			{Key: "b", Title: "b", Subtasks: []domain.BundleTask{{Title: "c", BlockedBy: []string{"a"}}}, BlockedBy: []string{"a"}},
+		},
+	}
+	for name, tasks := range cases {
+		t.Run(name, func(t *testing.T) {
+			// Nothing may be written, so the repositories have no expectations.
+			svc := newProjectTransferService(&mockTaskRepo{}, &mockProjectRepo{}, &fakeTagRepo{}, &fakeDependencyRepo{})
+			bundle := &domain.ProjectBundle{
+				Version: domain.ProjectBundleVersion,
+				Project: domain.BundleProject{Name: "p", Type: domain.ProjectTypeWork},
+				Tasks:   tasks,
+			}
+			_, err := svc.Import(context.Background(), uuid.New(), bundle)
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		})
+	}
+}
+
+func TestProjectTransferService_Export(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	now := time.Now()
+	parent := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Pack", Status: domain.TaskStatusTodo, CreatedAt: now}
+	child := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, ParentID: &parent.ID, Title: "Kitchen", CreatedAt: now.Add(time.Minute)}
+	van := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Book van", CreatedAt: now.Add(2 * time.Minute)}
+
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID, Name: "Move", Type: domain.ProjectTypePersonal}, nil)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{ProjectIDs: []uuid.UUID{projectID}, IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{van, child, parent}, 3, nil)
+	for _, task := range []*domain.Task{parent, child, van} {
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	errand := &domain.Tag{ID: uuid.New(), UserID: userID, Name: "errand", Color: "#FF0000"}
+	tags := &fakeTagRepo{tags: []*domain.Tag{errand}, onTask: map[uuid.UUID][]uuid.UUID{van.ID: {errand.ID}}}
+	deps := &fakeDependencyRepo{blockers: map[uuid.UUID][]uuid.UUID{parent.ID: {van.ID}}}
+	svc := newProjectTransferService(taskRepo, projectRepo, tags, deps)
+
+	bundle, err := svc.Export(context.Background(), projectID, userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Move", bundle.Project.Name)
+	assert.Equal(t, []domain.BundleTag{{Name: "errand", Color: "#FF0000"}}, bundle.Tags)
+	require.Len(t, bundle.Tasks, 2, "subtasks nest under their parent")
+	assert.Equal(t, "Pack", bundle.Tasks[0].Title)
+	assert.Empty(t, bundle.Tasks[0].Key)
+	assert.Equal(t, []string{"t1"}, bundle.Tasks[0].BlockedBy)
+	require.Len(t, bundle.Tasks[0].Subtasks, 1)
+	assert.Equal(t, "Kitchen", bundle.Tasks[0].Subtasks[0].Title)
+	assert.Equal(t, "t1", bundle.Tasks[1].Key)
+	assert.Equal(t, []string{"errand"}, bundle.Tasks[1].Tags)
+}
+
+func TestProjectTransferService_Sync(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	now := time.Now()
+	project := &domain.Project{ID: projectID, UserID: userID, Name: "Chores", Type: domain.ProjectTypePersonal}
+	task := func(title string, parent *uuid.UUID, age int) *domain.Task {
+		return &domain.Task{
+			ID: uuid.New(), UserID: userID, ProjectID: &projectID, ParentID: parent, Title: title,
+			Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, CreatedAt: now.Add(time.Duration(age) * time.Minute),
+		}
+	}
+	plants := task("Water plants", nil, 0)
+	laundry := task("Laundry", nil, 1)
+	old := task("Old", nil, 2)
+	oldChild := task("Old child", &old.ID, 3)
+
+	desired := &domain.DesiredProject{
+		Type: domain.ProjectTypePersonal,
+		Tasks: []domain.DesiredTask{
+			{Title: "Water plants", Status: domain.TaskStatusDone},
+			{Title: "Laundry"},
+			{Title: "Bins", Subtasks: []domain.DesiredTask{{Title: "Recycling"}}},
+		},
+	}
+
+	setup := func() (*mockTaskRepo, *service.ProjectTransferService) {
+		projectRepo := &mockProjectRepo{}
+		projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{project}, nil)
+		projectRepo.On("FindByID", mock.Anything, projectID).Return(project, nil)
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{ProjectIDs: []uuid.UUID{projectID}, IncludeDeferred: true}, 1, mock.Anything).
+			Return([]*domain.Task{oldChild, old, laundry, plants}, 4, nil)
+		for _, tk := range []*domain.Task{plants, laundry, old, oldChild} {
+			copied := *tk
+			taskRepo.On("FindByID", mock.Anything, tk.ID).Return(&copied, nil)
+		}
+		return taskRepo, newProjectTransferService(taskRepo, projectRepo, &fakeTagRepo{}, &fakeDependencyRepo{})
+	}
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		// The task repository has no write expectations, so any write panics.
+		_, svc := setup()
+		result, err := svc.Sync(context.Background(), userID, "Chores", desired, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, domain.SyncUnchanged, result.Project.Action)
+		assert.Equal(t, 1, result.Unchanged)
+		require.Len(t, result.Updated, 1)
+		assert.Equal(t, "Water plants", result.Updated[0].Path)
+		assert.Contains(t, result.Updated[0].Changes, "status")
+		require.Len(t, result.Created, 2)
+		assert.Equal(t, "Bins / Recycling", result.Created[1].Path)
+		assert.Nil(t, result.Created[0].ID)
+		require.Len(t, result.Archived, 2)
+		assert.Equal(t, "Old / Old child", result.Archived[0].Path, "subtasks go first")
+	})
+
+	t.Run("apply", func(t *testing.T) {
+		taskRepo, svc := setup()
+		var created []*domain.Task
+		taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			created = append(created, args.Get(1).([]*domain.Task)...)
+		}).Return(nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		taskRepo.On("SetArchived", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		// Subtasks look up the parent created just before them.
+		taskRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Task{UserID: userID, ProjectID: &projectID}, nil)
+
+		result, err := svc.Sync(context.Background(), userID, "Chores", desired, false)
+		require.NoError(t, err)
+
+		require.Len(t, created, 2)
+		assert.Equal(t, &created[0].ID, created[1].ParentID)
+		assert.Equal(t, &created[0].ID, result.Created[0].ID)
+		taskRepo.AssertCalled(t, "Update", mock.Anything, mock.MatchedBy(func(tk *domain.Task) bool {
+			return tk.ID == plants.ID && tk.Status == domain.TaskStatusDone
+		}), mock.Anything)
+		taskRepo.AssertNumberOfCalls(t, "Update", 1)
+		taskRepo.AssertCalled(t, "SetArchived", mock.Anything, oldChild.ID, mock.Anything)
+		taskRepo.AssertCalled(t, "SetArchived", mock.Anything, old.ID, mock.Anything)
+		taskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProjectTransferService_SyncAmbiguousName(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{{ID: uuid.New(), Name: "Dup"}, {ID: uuid.New(), Name: "Dup"}}, nil)
+	svc := newProjectTransferService(&mockTaskRepo{}, projectRepo, &fakeTagRepo{}, &fakeDependencyRepo{})
+
+	_, err := svc.Sync(context.Background(), userID, "Dup", &domain.DesiredProject{Type: domain.ProjectTypeWork}, false)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+func TestProjectTransferService_Print(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 23, 0, 0, 0, 0, time.UTC)
+	parent := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Pack (boxes)", Status: domain.TaskStatusTodo, DueDate: &due, CreatedAt: now}
+	child := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, ParentID: &parent.ID, Title: "Kitchen", Status: domain.TaskStatusTodo, CreatedAt: now.Add(time.Minute)}
+	done := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Book van", Status: domain.TaskStatusDone, CreatedAt: now.Add(2 * time.Minute)}
+
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID, Name: "Move"}, nil)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{ProjectIDs: []uuid.UUID{projectID}, IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{done, child, parent}, 3, nil)
+	svc := newProjectTransferService(taskRepo, projectRepo, &fakeTagRepo{}, &fakeDependencyRepo{})
+
+	project, out, err := svc.Print(context.Background(), projectID, userID, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Move", project.Name)
+	doc := string(out)
+	assert.True(t, strings.HasPrefix(doc, "%PDF-1.4"))
+	assert.True(t, strings.HasSuffix(doc, "%%EOF\n"))
+	assert.Contains(t, doc, "(2 open tasks \xb7 printed Mon 19 Oct 2026 09:00)")
+	assert.Contains(t, doc, "91.00 695.89 Td (Kitchen)", "subtasks are indented under their parent")
+	assert.Contains(t, doc, "(Pack \\(boxes\\)) Tj")
+	assert.Contains(t, doc, "(Due Fri 23 Oct 2026)")
+	assert.NotContains(t, doc, "Book van")
+}
@@ -0,0 +1,43 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/qr"
+)
+
+// defaultQRScale is the module size, in pixels, of QR codes rendered
+// without one: about 300 px square for a typical link.
+const defaultQRScale = 8
+
+// QRService renders QR codes server-side so that clients need no encoder of
+// their own.
+type QRService struct{}
+
+// NewQRService constructs a QRService.
+func NewQRService() *QRService {
+	return &QRService{}
+}
+
+// Render returns a PNG of the QR code for req.Data.
+func (s *QRService) Render(req *domain.QRRequest) ([]byte, error) {
+	return renderQR(req.Data, req.Scale)
+}
+
+// renderQR encodes data as a PNG QR code with scale pixels per module.
+// Data too long for a QR code is ErrValidation.
+func renderQR(data string, scale int) ([]byte, error) {
+	if scale <= 0 {
+		scale = defaultQRScale
+	}
+	code, err := qr.Encode([]byte(data))
+	if errors.Is(err, qr.ErrTooLong) {
+		return nil, fmt.Errorf("data must be at most %d bytes: %w", qr.MaxBytes, domain.ErrValidation)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("renderQR: %w", err)
+	}
+	return code.PNG(scale)
+}
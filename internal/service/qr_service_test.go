@@ -0,0 +1,39 @@
+package service_test
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQRService_Render(t *testing.T) {
+	svc := service.NewQRService()
+
+	out, err := svc.Render(&domain.QRRequest{Data: "otpauth://totp/todo-app:ada@example.com?secret=JBSWY3DPEHPK3PXP&issuer=todo-app"})
+	require.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	// 79 bytes need version 5: 37 modules plus a 4-module quiet zone on
+	// each side, at the default 8 px per module.
+	assert.Equal(t, (37+8)*8, img.Bounds().Dx())
+	assert.Equal(t, img.Bounds().Dx(), img.Bounds().Dy())
+
+	out, err = svc.Render(&domain.QRRequest{Data: "https://example.com", Scale: 2})
+	require.NoError(t, err)
+	img, err = png.Decode(bytes.NewReader(out))
+	require.NoError(t, err)
+	assert.Equal(t, (25+8)*2, img.Bounds().Dx(), "version 2 at 2 px per module")
+	r, _, _, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0xFFFF), r, "quiet zone is light")
+	r, _, _, _ = img.At(8, 8).RGBA()
+	assert.Equal(t, uint32(0), r, "finder corner is dark")
+
+	_, err = svc.Render(&domain.QRRequest{Data: strings.Repeat("x", 214)})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
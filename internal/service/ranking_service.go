@@ -0,0 +1,282 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Ranker orders a user's tasks for GET /tasks.
+type Ranker interface {
+	Name() string
+	// Rank returns tasks in display order. It may reorder the slice in place.
+	Rank(ctx context.Context, userID uuid.UUID, tasks []*domain.Task) ([]*domain.Task, error)
+}
+
+// RankerSmartScore is the default ranker: the stored smart score, which the
+// repository already sorts by.
+const RankerSmartScore = "smart_score"
+
+// maxRankCandidates bounds how many tasks a non-default ranker reorders. The
+// default ranker paginates in SQL and has no such limit.
+const maxRankCandidates = 1000
+
+// RankerVariant is one arm of the ranking experiment.
+type RankerVariant struct {
+	Ranker string
+	Weight int
+}
+
+// ParseRankerExperiment parses "ranker:weight,..." as read from config.
+func ParseRankerExperiment(s string) ([]RankerVariant, error) {
+	var variants []RankerVariant
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, weight, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("ranking experiment: %q is not ranker:weight", part)
+		}
+		w, err := strconv.Atoi(weight)
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("ranking experiment: invalid weight in %q", part)
+		}
+		variants = append(variants, RankerVariant{Ranker: strings.TrimSpace(name), Weight: w})
+	}
+	return variants, nil
+}
+
+// RankingService picks the ranker for each user and lists tasks in its order.
+// A user's explicit setting wins; otherwise users are deterministically
+// assigned to an experiment variant; otherwise the smart score is used.
+type RankingService struct {
+	taskRepo   domain.TaskRepository
+	userRepo   domain.UserRepository
+	rankers    map[string]Ranker
+	experiment []RankerVariant
+	log        *logrus.Logger
+}
+
+// NewRankingService constructs a RankingService. The smart score ranker is
+// always registered; variants naming unregistered rankers are dropped.
+func NewRankingService(
+	taskRepo domain.TaskRepository,
+	userRepo domain.UserRepository,
+	rankers []Ranker,
+	experiment []RankerVariant,
+	log *logrus.Logger,
+) *RankingService {
+	s := &RankingService{
+		taskRepo: taskRepo,
+		userRepo: userRepo,
+		rankers:  map[string]Ranker{RankerSmartScore: smartScoreRanker{}},
+		log:      log,
+	}
+	for _, r := range rankers {
+		s.rankers[r.Name()] = r
+	}
+	for _, v := range experiment {
+		if _, ok := s.rankers[v.Ranker]; !ok || v.Weight <= 0 {
+			log.WithField("ranker", v.Ranker).Warn("ignoring ranking experiment variant")
+			continue
+		}
+		s.experiment = append(s.experiment, v)
+	}
+	return s
+}
+
+// Resolve returns the user's ranking setting and how it was chosen.
+func (s *RankingService) Resolve(ctx context.Context, userID uuid.UUID) (*domain.RankingSetting, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("rankingService.Resolve: %w", err)
+	}
+
+	setting := &domain.RankingSetting{Available: s.available()}
+	switch {
+	case user.Ranker != nil && s.rankers[*user.Ranker] != nil:
+		setting.Ranker, setting.Source = *user.Ranker, domain.RankingSourceSetting
+	case len(s.experiment) > 0:
+		setting.Ranker, setting.Source = s.assign(userID), domain.RankingSourceExperiment
+	default:
+		setting.Ranker, setting.Source = RankerSmartScore, domain.RankingSourceDefault
+	}
+	return setting, nil
+}
+
+// SetRanker stores the user's explicit ranker choice; nil returns them to
+// experiment assignment.
+func (s *RankingService) SetRanker(ctx context.Context, userID uuid.UUID, ranker *string) (*domain.RankingSetting, error) {
+	if ranker != nil && s.rankers[*ranker] == nil {
+		return nil, fmt.Errorf("rankingService.SetRanker: unknown ranker %q: %w", *ranker, domain.ErrValidation)
+	}
+	if err := s.userRepo.SetRanker(ctx, userID, ranker); err != nil {
+		return nil, fmt.Errorf("rankingService.SetRanker: %w", err)
+	}
+	return s.Resolve(ctx, userID)
+}
+
+// List returns one page of the user's tasks in their ranker's order, and the
+// ranker's name so responses can be attributed to an experiment arm. A
+// manual sort bypasses the ranker and is reported as "manual".
+func (s *RankingService) List(ctx context.Context, userID uuid.UUID, filter domain.TaskFilter, page, limit int) ([]*domain.Task, int, string, error) {
+	if filter.Sort == domain.TaskSortManual {
+		tasks, total, err := s.taskRepo.List(ctx, userID, filter, page, limit)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("rankingService.List: %w", err)
+		}
+		return tasks, total, domain.TaskSortManual, nil
+	}
+
+	setting, err := s.Resolve(ctx, userID)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("rankingService.List: %w", err)
+	}
+	if setting.Ranker == RankerSmartScore {
+		tasks, total, err := s.taskRepo.List(ctx, userID, filter, page, limit)
+		if err != nil {
+			return nil, 0, "", fmt.Errorf("rankingService.List: %w", err)
+		}
+		return tasks, total, RankerSmartScore, nil
+	}
+
+	// Rankers may look at any field, so they get whole rows.
+	filter.Fields = nil
+	candidates, total, err := s.taskRepo.List(ctx, userID, filter, 1, maxRankCandidates)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("rankingService.List: %w", err)
+	}
+	ranker := s.rankers[setting.Ranker]
+	ranked, err := ranker.Rank(ctx, userID, candidates)
+	if err != nil {
+		// A broken ranker must not break the task list.
+		s.log.WithError(err).WithField("ranker", ranker.Name()).Warn("ranker failed; using smart score order")
+		ranked, setting.Ranker = candidates, RankerSmartScore
+	}
+	// Rankers order what they are given; pinned tasks still come first.
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Pinned && !ranked[j].Pinned })
+
+	start := (page - 1) * limit
+	if start > len(ranked) {
+		start = len(ranked)
+	}
+	end := start + limit
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+	return ranked[start:end], total, setting.Ranker, nil
+}
+
+// assign maps the user to an experiment variant. The assignment is stable
+// for a given variant list.
+func (s *RankingService) assign(userID uuid.UUID) string {
+	total := 0
+	for _, v := range s.experiment {
+		total += v.Weight
+	}
+	h := fnv.New32a()
+	_, _ = h.Write(userID[:])
+	bucket := int(h.Sum32() % uint32(total))
+	for _, v := range s.experiment {
+		if bucket < v.Weight {
+			return v.Ranker
+		}
+		bucket -= v.Weight
+	}
+	return RankerSmartScore
+}
+
+func (s *RankingService) available() []string {
+	names := make([]string, 0, len(s.rankers))
+	for name := range s.rankers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type smartScoreRanker struct{}
+
+func (smartScoreRanker) Name() string { return RankerSmartScore }
+
+func (smartScoreRanker) Rank(_ context.Context, _ uuid.UUID, tasks []*domain.Task) ([]*domain.Task, error) {
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].SmartScore > tasks[j].SmartScore })
+	return tasks, nil
+}
+
+// ExternalRanker delegates ordering to an HTTP service, such as an ML model.
+// It POSTs {"user_id", "tasks"} and expects {"order": [task ids]}; tasks the
+// service leaves out keep their relative order after the ranked ones.
+type ExternalRanker struct {
+	url    string
+	client *http.Client
+}
+
+// NewExternalRanker creates an ExternalRanker calling url with the given timeout.
+func NewExternalRanker(url string, timeout time.Duration) *ExternalRanker {
+	return &ExternalRanker{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+// Name implements Ranker.
+func (r *ExternalRanker) Name() string { return "external" }
+
+// Rank implements Ranker.
+func (r *ExternalRanker) Rank(ctx context.Context, userID uuid.UUID, tasks []*domain.Task) ([]*domain.Task, error) {
+	body, err := json.Marshal(map[string]any{"user_id": userID, "tasks": tasks})
+	if err != nil {
+		return nil, fmt.Errorf("externalRanker: encode: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("externalRanker: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("externalRanker: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("externalRanker: unexpected status %d", resp.StatusCode)
+	}
+
+	var out struct {
+		Order []uuid.UUID `json:"order"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("externalRanker: decode: %w", err)
+	}
+	if len(out.Order) == 0 && len(tasks) > 0 {
+		return nil, errors.New("externalRanker: empty order")
+	}
+
+	pos := make(map[uuid.UUID]int, len(out.Order))
+	for i, id := range out.Order {
+		if _, dup := pos[id]; !dup {
+			pos[id] = i
+		}
+	}
+	rank := func(t *domain.Task) int {
+		if p, ok := pos[t.ID]; ok {
+			return p
+		}
+		return len(out.Order)
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return rank(tasks[i]) < rank(tasks[j]) })
+	return tasks, nil
+}
@@ -0,0 +1,151 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type rankerUserRepo struct {
+	domain.UserRepository
+	ranker *string
+}
+
+func (f *rankerUserRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id, Ranker: f.ranker}, nil
+}
+
+func (f *rankerUserRepo) SetRanker(_ context.Context, _ uuid.UUID, ranker *string) error {
+	f.ranker = ranker
+	return nil
+}
+
+type reverseRanker struct{ err error }
+
+func (reverseRanker) Name() string { return "reverse" }
+
+func (r reverseRanker) Rank(_ context.Context, _ uuid.UUID, tasks []*domain.Task) ([]*domain.Task, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+		tasks[i], tasks[j] = tasks[j], tasks[i]
+	}
+	return tasks, nil
+}
+
+func TestRankingService_ResolvePrecedence(t *testing.T) {
+	users := &rankerUserRepo{}
+	rankers := []service.Ranker{reverseRanker{}}
+	ctx := context.Background()
+
+	noExperiment := service.NewRankingService(new(mockTaskRepo), users, rankers, nil, logrus.New())
+	got, err := noExperiment.Resolve(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, service.RankerSmartScore, got.Ranker)
+	assert.Equal(t, domain.RankingSourceDefault, got.Source)
+	assert.Equal(t, []string{"reverse", service.RankerSmartScore}, got.Available)
+
+	allReverse := []service.RankerVariant{{Ranker: "reverse", Weight: 1}}
+	svc := service.NewRankingService(new(mockTaskRepo), users, rankers, allReverse, logrus.New())
+	got, err = svc.Resolve(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, "reverse", got.Ranker)
+	assert.Equal(t, domain.RankingSourceExperiment, got.Source)
+
+	choice := service.RankerSmartScore
+	got, err = svc.SetRanker(ctx, uuid.New(), &choice)
+	require.NoError(t, err)
+	assert.Equal(t, service.RankerSmartScore, got.Ranker)
+	assert.Equal(t, domain.RankingSourceSetting, got.Source)
+
+	unknown := "nope"
+	_, err = svc.SetRanker(ctx, uuid.New(), &unknown)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestRankingService_ExperimentAssignmentIsStableAndWeighted(t *testing.T) {
+	variants := []service.RankerVariant{{Ranker: service.RankerSmartScore, Weight: 3}, {Ranker: "reverse", Weight: 1}}
+	svc := service.NewRankingService(new(mockTaskRepo), &rankerUserRepo{}, []service.Ranker{reverseRanker{}}, variants, logrus.New())
+	ctx := context.Background()
+
+	counts := map[string]int{}
+	for i := 0; i < 2000; i++ {
+		id := uuid.New()
+		first, err := svc.Resolve(ctx, id)
+		require.NoError(t, err)
+		again, err := svc.Resolve(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, first.Ranker, again.Ranker)
+		counts[first.Ranker]++
+	}
+	assert.InDelta(t, 1500, counts[service.RankerSmartScore], 150)
+	assert.InDelta(t, 500, counts["reverse"], 150)
+}
+
+func TestRankingService_ListRanksAndPaginatesInMemory(t *testing.T) {
+	userID := uuid.New()
+	tasks := []*domain.Task{{Title: "a"}, {Title: "b"}, {Title: "c"}}
+	repo := new(mockTaskRepo)
+	repo.On("List", mock.Anything, userID, domain.TaskFilter{}, 1, mock.Anything).Return(tasks, 3, nil)
+
+	reverse := "reverse"
+	users := &rankerUserRepo{ranker: &reverse}
+	svc := service.NewRankingService(repo, users, []service.Ranker{reverseRanker{}}, nil, logrus.New())
+
+	page, total, ranker, err := svc.List(context.Background(), userID, domain.TaskFilter{}, 2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, "reverse", ranker)
+	require.Len(t, page, 1)
+	assert.Equal(t, "a", page[0].Title)
+}
+
+func TestRankingService_ListKeepsPinnedFirst(t *testing.T) {
+	userID := uuid.New()
+	tasks := []*domain.Task{{Title: "pinned", Pinned: true}, {Title: "b"}, {Title: "c"}}
+	repo := new(mockTaskRepo)
+	repo.On("List", mock.Anything, userID, domain.TaskFilter{}, 1, mock.Anything).Return(tasks, 3, nil)
+
+	reverse := "reverse"
+	users := &rankerUserRepo{ranker: &reverse}
+	svc := service.NewRankingService(repo, users, []service.Ranker{reverseRanker{}}, nil, logrus.New())
+
+	page, _, _, err := svc.List(context.Background(), userID, domain.TaskFilter{}, 1, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 3)
+	assert.Equal(t, []string{"pinned", "c", "b"}, []string{page[0].Title, page[1].Title, page[2].Title})
+}
+
+func TestRankingService_ListFallsBackWhenRankerFails(t *testing.T) {
+	userID := uuid.New()
+	tasks := []*domain.Task{{Title: "a"}, {Title: "b"}}
+	repo := new(mockTaskRepo)
+	repo.On("List", mock.Anything, userID, domain.TaskFilter{}, 1, mock.Anything).Return(tasks, 2, nil)
+
+	reverse := "reverse"
+	users := &rankerUserRepo{ranker: &reverse}
+	svc := service.NewRankingService(repo, users, []service.Ranker{reverseRanker{err: errors.New("down")}}, nil, logrus.New())
+
+	page, _, ranker, err := svc.List(context.Background(), userID, domain.TaskFilter{}, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, service.RankerSmartScore, ranker)
+	assert.Equal(t, "a", page[0].Title)
+}
+
+func TestParseRankerExperiment(t *testing.T) {
+	got, err := service.ParseRankerExperiment("smart_score:90, external:10")
+	require.NoError(t, err)
+	assert.Equal(t, []service.RankerVariant{{Ranker: "smart_score", Weight: 90}, {Ranker: "external", Weight: 10}}, got)
+
+	_, err = service.ParseRankerExperiment("external")
+	assert.Error(t, err)
+}
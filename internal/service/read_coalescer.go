@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"golang.org/x/sync/singleflight"
+)
+
+// Names of the reads a ReadCoalescer counts.
+const (
+	coalesceDashboard = "analytics.dashboard"
+	coalesceBadges    = "views.badges"
+)
+
+// ReadCoalescer collapses identical reads that are in flight at the same
+// time into one query, so a burst of requests for the same user's data
+// (several open tabs, widgets polling) reaches the database once. Nothing
+// is kept after the query returns; it only shares results between callers
+// already waiting.
+type ReadCoalescer struct {
+	group singleflight.Group
+
+	mu    sync.Mutex
+	reads map[string]*readCounters
+}
+
+type readCounters struct {
+	calls, queries atomic.Int64
+}
+
+// NewReadCoalescer constructs a ReadCoalescer.
+func NewReadCoalescer() *ReadCoalescer {
+	return &ReadCoalescer{reads: map[string]*readCounters{}}
+}
+
+// Stats returns the counters of every read seen so far, by name.
+func (c *ReadCoalescer) Stats() map[string]domain.CoalescingStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[string]domain.CoalescingStats, len(c.reads))
+	for name, r := range c.reads {
+		calls, queries := r.calls.Load(), r.queries.Load()
+		out[name] = domain.CoalescingStats{Calls: calls, Queries: queries, Coalesced: calls - queries}
+	}
+	return out
+}
+
+func (c *ReadCoalescer) counters(name string) *readCounters {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.reads[name]
+	if !ok {
+		r = &readCounters{}
+		c.reads[name] = r
+	}
+	return r
+}
+
+// coalesce runs fn for the read name of key unless the same one is already
+// running, in which case it waits for and shares that result. fn runs
+// detached from the caller's cancellation, since other callers may be
+// waiting on it. Every caller gets the same value, so it must not be
+// modified. A nil ReadCoalescer just calls fn.
+func coalesce[V any](ctx context.Context, c *ReadCoalescer, name, key string, fn func(context.Context) (V, error)) (V, error) {
+	if c == nil {
+		return fn(ctx)
+	}
+	r := c.counters(name)
+	r.calls.Add(1)
+	v, err, _ := c.group.Do(name+"/"+key, func() (any, error) {
+		r.queries.Add(1)
+		return fn(context.WithoutCancel(ctx))
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return v.(V), nil
+}
@@ -0,0 +1,81 @@
+package service_test
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingAnalyticsRepo holds every dashboard query until release closes.
+type blockingAnalyticsRepo struct {
+	domain.AnalyticsRepository
+	started chan struct{}
+	release chan struct{}
+	queries atomic.Int32
+}
+
+func (f *blockingAnalyticsRepo) GetDashboard(ctx context.Context, userID uuid.UUID) (*domain.AnalyticsDashboard, error) {
+	if f.queries.Add(1) == 1 {
+		close(f.started)
+	}
+	<-f.release
+	return &domain.AnalyticsDashboard{}, ctx.Err()
+}
+
+func TestReadCoalescer_SharesDashboardQuery(t *testing.T) {
+	repo := &blockingAnalyticsRepo{started: make(chan struct{}), release: make(chan struct{})}
+	coalescer := service.NewReadCoalescer()
+	svc := service.NewAnalyticsService(repo, fakeUserRepo{})
+	svc.UseCoalescer(coalescer)
+	userID := uuid.New()
+
+	// The first caller gives up early; the query still serves the others.
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	results := make([]*domain.AnalyticsDashboard, 5)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		results[0], _ = svc.GetDashboard(leaderCtx, userID)
+	}()
+	<-repo.started
+	cancel()
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			dash, err := svc.GetDashboard(context.Background(), userID)
+			assert.NoError(t, err)
+			results[i] = dash
+		}(i)
+	}
+	// Wait until all followers are counted, and a moment more for them to
+	// join the query, before letting it finish.
+	for coalescer.Stats()["analytics.dashboard"].Calls < int64(len(results)) {
+		runtime.Gosched()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), repo.queries.Load())
+	for _, dash := range results[1:] {
+		require.NotNil(t, dash)
+		assert.Same(t, results[1], dash)
+	}
+	assert.Equal(t, domain.CoalescingStats{Calls: 5, Queries: 1, Coalesced: 4}, coalescer.Stats()["analytics.dashboard"])
+
+	// Once it has returned, the next call queries again.
+	_, err := svc.GetDashboard(context.Background(), userID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), repo.queries.Load())
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// recordViewTimeout keeps a slow view store from delaying task reads.
+const recordViewTimeout = 200 * time.Millisecond
+
+// RecentTaskService serves the "jump back in" lists of recently viewed and
+// recently modified tasks.
+type RecentTaskService struct {
+	taskRepo domain.TaskRepository
+	viewRepo domain.TaskViewRepository
+	log      *logrus.Logger
+}
+
+// NewRecentTaskService constructs a RecentTaskService.
+func NewRecentTaskService(taskRepo domain.TaskRepository, viewRepo domain.TaskViewRepository, log *logrus.Logger) *RecentTaskService {
+	return &RecentTaskService{taskRepo: taskRepo, viewRepo: viewRepo, log: log}
+}
+
+// RecordView notes that the user opened a task. Failures are logged, never
+// returned: losing a view must not fail the read that caused it.
+func (s *RecentTaskService) RecordView(ctx context.Context, userID, taskID uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), recordViewTimeout)
+	defer cancel()
+
+	if err := s.viewRepo.Record(ctx, userID, taskID, time.Now()); err != nil {
+		s.log.WithError(err).WithField("task_id", taskID).Warn("failed to record task view")
+	}
+}
+
+// List returns up to limit tasks of the given kind, most recent first.
+func (s *RecentTaskService) List(ctx context.Context, userID uuid.UUID, kind string, limit int) ([]*domain.Task, error) {
+	if limit <= 0 || limit > domain.MaxRecentTasks {
+		limit = domain.MaxRecentTasks
+	}
+
+	switch kind {
+	case domain.RecentModified:
+		tasks, err := s.taskRepo.ListRecentlyModified(ctx, userID, limit)
+		if err != nil {
+			return nil, fmt.Errorf("recentTaskService.List: %w", err)
+		}
+		return tasks, nil
+
+	case domain.RecentViewed:
+		ids, err := s.viewRepo.ListRecent(ctx, userID, limit)
+		if err != nil {
+			return nil, fmt.Errorf("recentTaskService.List: %w", err)
+		}
+		if len(ids) == 0 {
+			return []*domain.Task{}, nil
+		}
+		found, err := s.taskRepo.FindByIDs(ctx, userID, ids)
+		if err != nil {
+			return nil, fmt.Errorf("recentTaskService.List: %w", err)
+		}
+		// FindByIDs skips deleted tasks; restore view order for the rest.
+		byID := make(map[uuid.UUID]*domain.Task, len(found))
+		for _, t := range found {
+			byID[t.ID] = t
+		}
+		tasks := make([]*domain.Task, 0, len(found))
+		for _, id := range ids {
+			if t, ok := byID[id]; ok {
+				tasks = append(tasks, t)
+			}
+		}
+		return tasks, nil
+
+	default:
+		return nil, fmt.Errorf("recentTaskService.List: unknown kind %q: %w", kind, domain.ErrValidation)
+	}
+}
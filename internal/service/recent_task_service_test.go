@@ -0,0 +1,47 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/repository"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentTaskService_ViewedKeepsViewOrderAndSkipsDeleted(t *testing.T) {
+	userID := uuid.New()
+	first, second, deleted := uuid.New(), uuid.New(), uuid.New()
+
+	views := repository.NewMemoryTaskViewRepository()
+	taskRepo := &mockTaskRepo{}
+	svc := service.NewRecentTaskService(taskRepo, views, logrus.New())
+	ctx := context.Background()
+
+	now := time.Now()
+	require.NoError(t, views.Record(ctx, userID, first, now.Add(-3*time.Minute)))
+	require.NoError(t, views.Record(ctx, userID, deleted, now.Add(-2*time.Minute)))
+	require.NoError(t, views.Record(ctx, userID, second, now.Add(-time.Minute)))
+
+	taskRepo.On("FindByIDs", mock.Anything, userID, []uuid.UUID{second, deleted, first}).
+		Return([]*domain.Task{{ID: first}, {ID: second}}, nil)
+
+	tasks, err := svc.List(ctx, userID, domain.RecentViewed, 0)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	assert.Equal(t, second, tasks[0].ID)
+	assert.Equal(t, first, tasks[1].ID)
+}
+
+func TestRecentTaskService_UnknownKind(t *testing.T) {
+	svc := service.NewRecentTaskService(&mockTaskRepo{}, repository.NewMemoryTaskViewRepository(), logrus.New())
+
+	_, err := svc.List(context.Background(), uuid.New(), "starred", 10)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
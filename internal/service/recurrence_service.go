@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RecurrenceService keeps the completion history of recurring tasks. It
+// subscribes to task events: TaskService reopens a completed recurring task
+// itself, and publishes task.completed with the occurrence that was done.
+type RecurrenceService struct {
+	occurrenceRepo domain.TaskOccurrenceRepository
+	taskSvc        *TaskService
+	log            *logrus.Logger
+}
+
+// NewRecurrenceService constructs a RecurrenceService.
+func NewRecurrenceService(occurrenceRepo domain.TaskOccurrenceRepository, taskSvc *TaskService, log *logrus.Logger) *RecurrenceService {
+	return &RecurrenceService{occurrenceRepo: occurrenceRepo, taskSvc: taskSvc, log: log}
+}
+
+// TaskCompleted subscribes to completions, recording occurrences of
+// recurring tasks.
+func (s *RecurrenceService) TaskCompleted(ctx context.Context, e domain.TaskCompleted) error {
+	task := e.Task
+	if task.Recurrence == nil || task.DueDate == nil || task.CompletedAt == nil {
+		return nil
+	}
+	o := &domain.TaskOccurrence{
+		ID:          uuid.New(),
+		TaskID:      task.ID,
+		UserID:      task.UserID,
+		DueDate:     *task.DueDate,
+		CompletedAt: *task.CompletedAt,
+		OnTime:      !task.CompletedAt.After(*task.DueDate),
+	}
+	if err := s.occurrenceRepo.Create(ctx, o); err != nil {
+		return fmt.Errorf("recurrenceService.TaskCompleted: %w", err)
+	}
+	return nil
+}
+
+// Occurrences returns a page of a task's completed occurrences, most recent
+// first, with on-time statistics over all of them. It enforces ownership.
+func (s *RecurrenceService) Occurrences(ctx context.Context, taskID, userID uuid.UUID, page, limit int) (*domain.TaskOccurrenceHistory, int, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, 0, err
+	}
+
+	occurrences, total, err := s.occurrenceRepo.ListByTaskID(ctx, taskID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("recurrenceService.Occurrences: %w", err)
+	}
+	stats, err := s.occurrenceRepo.StatsByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("recurrenceService.Occurrences: %w", err)
+	}
+	return &domain.TaskOccurrenceHistory{Stats: *stats, Occurrences: occurrences}, total, nil
+}
@@ -0,0 +1,68 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOccurrenceRepo struct {
+	domain.TaskOccurrenceRepository
+	created []*domain.TaskOccurrence
+}
+
+func (f *fakeOccurrenceRepo) Create(_ context.Context, o *domain.TaskOccurrence) error {
+	f.created = append(f.created, o)
+	return nil
+}
+
+func TestRecurrenceService_CompletingRecurringTask(t *testing.T) {
+	userID := uuid.New()
+	due := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	task := &domain.Task{
+		ID: uuid.New(), UserID: userID, Title: "Water plants", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium,
+		DueDate: &due, Recurrence: &domain.Recurrence{Frequency: domain.RecurDaily, Anchor: due},
+	}
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	taskSvc := newTaskService(taskRepo, &mockProjectRepo{})
+	occurrences := &fakeOccurrenceRepo{}
+	bus := eventbus.New(eventbus.Config{}, logrus.New())
+	taskSvc.UseEvents(bus)
+	eventbus.Subscribe(bus, "recurrence", service.NewRecurrenceService(occurrences, taskSvc, logrus.New()).TaskCompleted)
+
+	done := domain.TaskStatusDone
+	updated, err := taskSvc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Status: &done})
+	require.NoError(t, err)
+
+	assert.Equal(t, domain.TaskStatusTodo, updated.Status, "the task reopens for the next occurrence")
+	assert.Nil(t, updated.CompletedAt)
+	assert.Equal(t, due.AddDate(0, 0, 1), *updated.DueDate)
+
+	require.Len(t, occurrences.created, 1)
+	o := occurrences.created[0]
+	assert.Equal(t, task.ID, o.TaskID)
+	assert.Equal(t, due, o.DueDate)
+	assert.False(t, o.OnTime, "completed two hours after it was due")
+}
+
+func TestTaskService_RecurrenceNeedsDueDate(t *testing.T) {
+	svc := newTaskService(&mockTaskRepo{}, &mockProjectRepo{})
+	_, err := svc.Create(context.Background(), uuid.New(), &domain.CreateTaskRequest{
+		Title:      "Weekly review",
+		Priority:   domain.TaskPriorityMedium,
+		Recurrence: &domain.Recurrence{Frequency: domain.RecurWeekly},
+	})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
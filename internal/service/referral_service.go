@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	referralCodeLength = 10
+	// referralCreditBatch is how many qualified referrals one GrantPending
+	// run hands to the rewarders.
+	referralCreditBatch = 100
+)
+
+// ReferralRewarder grants the referrer's reward once a referral qualifies,
+// typically plan credit in the billing module. A referral is marked credited
+// only when every rewarder succeeds; failures are retried by GrantPending, so
+// rewarders must be idempotent on the referral ID.
+type ReferralRewarder interface {
+	GrantReferralCredit(ctx context.Context, referral *domain.Referral) error
+}
+
+// ReferralService attributes signups to the users who referred them and
+// tracks each referral until its reward is granted.
+type ReferralService struct {
+	referralRepo domain.ReferralRepository
+	userRepo     domain.UserRepository
+	rewarders    []ReferralRewarder
+	log          *logrus.Logger
+}
+
+// NewReferralService constructs a ReferralService.
+func NewReferralService(referralRepo domain.ReferralRepository, userRepo domain.UserRepository, log *logrus.Logger) *ReferralService {
+	return &ReferralService{referralRepo: referralRepo, userRepo: userRepo, log: log}
+}
+
+// UseRewarder registers a ReferralRewarder called when a referral qualifies.
+// Must be called before serving requests.
+func (s *ReferralService) UseRewarder(r ReferralRewarder) {
+	s.rewarders = append(s.rewarders, r)
+}
+
+// Summary returns the user's referral code, counts by status and the
+// referrals themselves, newest first.
+func (s *ReferralService) Summary(ctx context.Context, userID uuid.UUID) (*domain.ReferralSummary, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("referralService.Summary: %w", err)
+	}
+	referrals, err := s.referralRepo.ListByReferrer(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("referralService.Summary: %w", err)
+	}
+
+	summary := &domain.ReferralSummary{Code: user.ReferralCode, Referrals: referrals}
+	summary.Counts.Total = len(referrals)
+	for _, r := range referrals {
+		switch r.Status {
+		case domain.ReferralSignedUp:
+			summary.Counts.SignedUp++
+		case domain.ReferralQualified:
+			summary.Counts.Qualified++
+		case domain.ReferralCredited:
+			summary.Counts.Credited++
+		}
+	}
+	return summary, nil
+}
+
+// UserRegistered subscribes to signups, recording who referred the new user:
+// the owner of the referral code if one was given, otherwise the creator of
+// the invite the user signed up with. Unknown codes are logged and ignored.
+func (s *ReferralService) UserRegistered(ctx context.Context, e domain.UserRegistered) error {
+	user, invite := e.User, e.Invite
+	ref := &domain.Referral{
+		ID:         uuid.New(),
+		ReferredID: user.ID,
+		Status:     domain.ReferralSignedUp,
+		CreatedAt:  user.CreatedAt,
+	}
+
+	if code := normalizeReferralCode(e.ReferralCode); code != "" {
+		referrer, err := s.userRepo.FindByReferralCode(ctx, code)
+		switch {
+		case err == nil:
+			ref.ReferrerID, ref.Code, ref.Source = referrer.ID, code, e.ReferralSource
+			if ref.Source == "" {
+				ref.Source = domain.ReferralSourceCode
+			}
+		case errors.Is(err, domain.ErrNotFound):
+			s.log.WithField("user_id", user.ID).Debug("ignoring unknown referral code")
+		default:
+			s.log.WithError(err).WithField("user_id", user.ID).Warn("failed to look up referral code")
+		}
+	}
+	if ref.ReferrerID == uuid.Nil && invite != nil {
+		ref.ReferrerID, ref.Code, ref.Source = invite.CreatedBy, invite.Code, domain.ReferralSourceInvite
+	}
+	if ref.ReferrerID == uuid.Nil || ref.ReferrerID == user.ID {
+		return nil
+	}
+
+	if err := s.referralRepo.Create(ctx, ref); err != nil {
+		return fmt.Errorf("referralService.UserRegistered: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{
+		"referrer_id": ref.ReferrerID,
+		"referred_id": user.ID,
+		"source":      ref.Source,
+	}).Info("referral recorded")
+	return nil
+}
+
+// TaskCompleted subscribes to completions: a referral qualifies when the
+// referred user completes their first task.
+func (s *ReferralService) TaskCompleted(ctx context.Context, e domain.TaskCompleted) error {
+	ref, err := s.referralRepo.Qualify(ctx, e.Task.UserID, time.Now())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("referralService.TaskCompleted: %w", err)
+	}
+	if err := s.grant(ctx, ref); err != nil {
+		s.log.WithError(err).WithField("referral_id", ref.ID).Warn("failed to grant referral credit; will retry")
+	}
+	return nil
+}
+
+// GrantPending retries rewards for referrals that qualified but were not
+// credited. Intended to be run by the scheduler.
+func (s *ReferralService) GrantPending(ctx context.Context) error {
+	if len(s.rewarders) == 0 {
+		return nil
+	}
+	pending, err := s.referralRepo.ListQualified(ctx, referralCreditBatch)
+	if err != nil {
+		return fmt.Errorf("referralService.GrantPending: %w", err)
+	}
+	for _, ref := range pending {
+		if err := s.grant(ctx, ref); err != nil {
+			s.log.WithError(err).WithField("referral_id", ref.ID).Warn("failed to grant referral credit")
+		}
+	}
+	return nil
+}
+
+// grant hands a qualified referral to the rewarders and marks it credited
+// when they all succeed. Without rewarders it stays qualified.
+func (s *ReferralService) grant(ctx context.Context, ref *domain.Referral) error {
+	if len(s.rewarders) == 0 {
+		return nil
+	}
+	for _, r := range s.rewarders {
+		if err := r.GrantReferralCredit(ctx, ref); err != nil {
+			return err
+		}
+	}
+	now := time.Now()
+	if err := s.referralRepo.MarkCredited(ctx, ref.ID, now); err != nil {
+		return err
+	}
+	ref.Status, ref.CreditedAt = domain.ReferralCredited, &now
+	return nil
+}
+
+// generateReferralCode returns a random code such as "K7QDM2XP9H".
+func generateReferralCode() (string, error) {
+	s, err := randomCode(referralCodeLength)
+	if err != nil {
+		return "", fmt.Errorf("generate referral code: %w", err)
+	}
+	return s, nil
+}
+
+// normalizeReferralCode accepts codes typed in lower case or with spaces.
+func normalizeReferralCode(code string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(code), ""))
+}
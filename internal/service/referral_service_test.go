@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReferralRepo struct {
+	domain.ReferralRepository
+	referrals []*domain.Referral
+}
+
+func (f *fakeReferralRepo) Create(_ context.Context, r *domain.Referral) error {
+	f.referrals = append(f.referrals, r)
+	return nil
+}
+
+func (f *fakeReferralRepo) Qualify(_ context.Context, referredID uuid.UUID, at time.Time) (*domain.Referral, error) {
+	for _, r := range f.referrals {
+		if r.ReferredID == referredID && r.Status == domain.ReferralSignedUp {
+			r.Status, r.QualifiedAt = domain.ReferralQualified, &at
+			return r, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeReferralRepo) ListQualified(_ context.Context, _ int) ([]*domain.Referral, error) {
+	var out []*domain.Referral
+	for _, r := range f.referrals {
+		if r.Status == domain.ReferralQualified {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeReferralRepo) MarkCredited(_ context.Context, id uuid.UUID, at time.Time) error {
+	for _, r := range f.referrals {
+		if r.ID == id {
+			r.Status, r.CreditedAt = domain.ReferralCredited, &at
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+type referralUserRepo struct {
+	domain.UserRepository
+	byCode map[string]*domain.User
+}
+
+func (f referralUserRepo) FindByReferralCode(_ context.Context, code string) (*domain.User, error) {
+	if u, ok := f.byCode[code]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakeRewarder struct {
+	err     error
+	granted []uuid.UUID
+}
+
+func (f *fakeRewarder) GrantReferralCredit(_ context.Context, r *domain.Referral) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.granted = append(f.granted, r.ID)
+	return nil
+}
+
+func newReferralService(repo *fakeReferralRepo, users ...*domain.User) *service.ReferralService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	byCode := map[string]*domain.User{}
+	for _, u := range users {
+		byCode[u.ReferralCode] = u
+	}
+	return service.NewReferralService(repo, referralUserRepo{byCode: byCode}, log)
+}
+
+func TestReferralService_UserRegistered(t *testing.T) {
+	referrer := &domain.User{ID: uuid.New(), ReferralCode: "K7QDM2XP9H"}
+	invite := &domain.InviteCode{ID: uuid.New(), Code: "AAAA-BBBB-CCCC", CreatedBy: uuid.New()}
+	ctx := context.Background()
+
+	t.Run("code from a link", func(t *testing.T) {
+		repo := &fakeReferralRepo{}
+		svc := newReferralService(repo, referrer)
+		user := &domain.User{ID: uuid.New()}
+
+		require.NoError(t, svc.UserRegistered(ctx, domain.UserRegistered{User: user, ReferralCode: " k7qdm2xp9h", ReferralSource: domain.ReferralSourceLink, Invite: invite}))
+
+		require.Len(t, repo.referrals, 1)
+		ref := repo.referrals[0]
+		assert.Equal(t, referrer.ID, ref.ReferrerID, "an explicit code wins over the invite")
+		assert.Equal(t, user.ID, ref.ReferredID)
+		assert.Equal(t, domain.ReferralSourceLink, ref.Source)
+		assert.Equal(t, domain.ReferralSignedUp, ref.Status)
+	})
+
+	t.Run("unknown code falls back to the invite", func(t *testing.T) {
+		repo := &fakeReferralRepo{}
+		svc := newReferralService(repo, referrer)
+
+		require.NoError(t, svc.UserRegistered(ctx, domain.UserRegistered{User: &domain.User{ID: uuid.New()}, ReferralCode: "NOPE", Invite: invite}))
+
+		require.Len(t, repo.referrals, 1)
+		assert.Equal(t, invite.CreatedBy, repo.referrals[0].ReferrerID)
+		assert.Equal(t, domain.ReferralSourceInvite, repo.referrals[0].Source)
+	})
+
+	t.Run("nothing to attribute", func(t *testing.T) {
+		repo := &fakeReferralRepo{}
+		svc := newReferralService(repo, referrer)
+
+		require.NoError(t, svc.UserRegistered(ctx, domain.UserRegistered{User: &domain.User{ID: uuid.New()}}))
+
+		assert.Empty(t, repo.referrals)
+	})
+}
+
+func TestReferralService_CompletionQualifiesAndCredits(t *testing.T) {
+	referred := uuid.New()
+	ref := &domain.Referral{ID: uuid.New(), ReferrerID: uuid.New(), ReferredID: referred, Status: domain.ReferralSignedUp}
+	repo := &fakeReferralRepo{referrals: []*domain.Referral{ref}}
+	svc := newReferralService(repo)
+	rewarder := &fakeRewarder{err: errors.New("billing unavailable")}
+	svc.UseRewarder(rewarder)
+	ctx := context.Background()
+	bus := eventbus.New(eventbus.Config{}, logrus.New())
+	eventbus.Subscribe(bus, "referrals", svc.TaskCompleted)
+
+	bus.Publish(ctx, domain.TaskUpdated{Task: &domain.Task{UserID: referred}})
+	assert.Equal(t, domain.ReferralSignedUp, ref.Status, "only completions qualify")
+
+	bus.Publish(ctx, domain.TaskCompleted{Task: &domain.Task{UserID: referred}})
+	assert.Equal(t, domain.ReferralQualified, ref.Status, "a failed grant leaves the referral qualified")
+
+	rewarder.err = nil
+	require.NoError(t, svc.GrantPending(ctx))
+	assert.Equal(t, domain.ReferralCredited, ref.Status)
+	assert.Equal(t, []uuid.UUID{ref.ID}, rewarder.granted)
+
+	bus.Publish(ctx, domain.TaskCompleted{Task: &domain.Task{UserID: referred}})
+	assert.Len(t, rewarder.granted, 1, "later completions grant nothing")
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxPendingReminders caps the reminders waiting to go off on one task.
+	maxPendingReminders = 10
+	// reminderBatchSize is how many due reminders one FireDue run sends.
+	reminderBatchSize = 500
+)
+
+// ReminderService manages reminders on tasks and sends them when they fall
+// due. It listens to task events to keep offset reminders in step with the
+// task's due date.
+type ReminderService struct {
+	reminderRepo domain.ReminderRepository
+	taskSvc      *TaskService
+	notifier     Notifier
+	log          *logrus.Logger
+}
+
+// NewReminderService constructs a ReminderService.
+func NewReminderService(reminderRepo domain.ReminderRepository, taskSvc *TaskService, notifier Notifier, log *logrus.Logger) *ReminderService {
+	return &ReminderService{reminderRepo: reminderRepo, taskSvc: taskSvc, notifier: notifier, log: log}
+}
+
+// Create adds a reminder to a task, enforcing ownership. Absolute reminders
+// must lie in the future; offset reminders need the task to have a due date.
+func (s *ReminderService) Create(ctx context.Context, taskID, userID uuid.UUID, req *domain.CreateReminderRequest) (*domain.Reminder, error) {
+	task, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := req.OffsetMinutes
+	if req.Offset != "" {
+		minutes, err := domain.ParseReminderOffset(req.Offset)
+		if err != nil {
+			return nil, fmt.Errorf("reminderService.Create: %v: %w", err, domain.ErrValidation)
+		}
+		offset = &minutes
+	}
+
+	now := time.Now()
+	r := &domain.Reminder{
+		ID:            uuid.New(),
+		TaskID:        task.ID,
+		UserID:        userID,
+		RemindAt:      req.RemindAt,
+		OffsetMinutes: offset,
+		Status:        domain.ReminderPending,
+		CreatedAt:     now,
+	}
+	switch {
+	case req.RemindAt != nil:
+		if !req.RemindAt.After(now) {
+			return nil, fmt.Errorf("reminderService.Create: remind_at must be in the future: %w", domain.ErrValidation)
+		}
+		r.FireAt = req.RemindAt
+	case task.DueDate == nil:
+		return nil, fmt.Errorf("reminderService.Create: offset reminders need a task with a due date: %w", domain.ErrValidation)
+	default:
+		r.FireAt = offsetFireAt(*task.DueDate, *offset)
+	}
+
+	n, err := s.reminderRepo.CountPending(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.Create: %w", err)
+	}
+	if n >= maxPendingReminders {
+		return nil, fmt.Errorf("reminderService.Create: at most %d pending reminders per task: %w", maxPendingReminders, domain.ErrValidation)
+	}
+
+	if err := s.reminderRepo.Create(ctx, r); err != nil {
+		return nil, fmt.Errorf("reminderService.Create: %w", err)
+	}
+	return r, nil
+}
+
+// List returns a task's reminders, soonest first, enforcing ownership.
+func (s *ReminderService) List(ctx context.Context, taskID, userID uuid.UUID) ([]*domain.Reminder, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	reminders, err := s.reminderRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("reminderService.List: %w", err)
+	}
+	return reminders, nil
+}
+
+// Dismiss marks a reminder as dismissed, so a pending one never goes off.
+func (s *ReminderService) Dismiss(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.Reminder, error) {
+	r, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if err := s.reminderRepo.Dismiss(ctx, r.ID, now); err != nil {
+		return nil, fmt.Errorf("reminderService.Dismiss: %w", err)
+	}
+	if r.DismissedAt == nil {
+		r.DismissedAt = &now
+	}
+	r.Status = domain.ReminderDismissed
+	return r, nil
+}
+
+// Delete removes a reminder.
+func (s *ReminderService) Delete(ctx context.Context, taskID, id, userID uuid.UUID) error {
+	r, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.reminderRepo.Delete(ctx, r.ID); err != nil {
+		return fmt.Errorf("reminderService.Delete: %w", err)
+	}
+	return nil
+}
+
+// TaskUpdated subscribes to task updates, moving offset reminders along
+// with the task's due date.
+func (s *ReminderService) TaskUpdated(ctx context.Context, e domain.TaskUpdated) error {
+	if err := s.reminderRepo.Reschedule(ctx, e.Task.ID, e.Task.DueDate); err != nil {
+		return fmt.Errorf("reminderService.TaskUpdated: %w", err)
+	}
+	return nil
+}
+
+// FireDue sends every reminder that has fallen due. Reminders on finished or
+// deleted tasks are dismissed instead. Intended to be run by the scheduler.
+func (s *ReminderService) FireDue(ctx context.Context) error {
+	now := time.Now()
+	due, err := s.reminderRepo.ListDue(ctx, now, reminderBatchSize)
+	if err != nil {
+		return fmt.Errorf("reminderService.FireDue: %w", err)
+	}
+	for _, r := range due {
+		if err := s.fire(ctx, r, now); err != nil {
+			s.log.WithError(err).WithField("reminder_id", r.ID).Error("failed to fire reminder")
+		}
+	}
+	return nil
+}
+
+// NudgeEffortExceeded flags in-progress tasks nobody has touched or logged
+// time on for longer than their estimate, and nudges each owner once per
+// stall. Intended to be run by the scheduler.
+func (s *ReminderService) NudgeEffortExceeded(ctx context.Context) error {
+	tasks, err := s.taskSvc.FlagEffortExceeded(ctx, reminderBatchSize)
+	if err != nil {
+		return fmt.Errorf("reminderService.NudgeEffortExceeded: %w", err)
+	}
+	for _, task := range tasks {
+		s.notifier.Notify(domain.NotificationEvent{
+			UserID:   task.UserID,
+			Type:     domain.EventTaskEffortExceeded,
+			Title:    task.Title,
+			Body:     fmt.Sprintf("In progress past its %.4g h estimate with no updates. Still on it?", *task.EstimatedHours),
+			EntityID: &task.ID,
+		})
+	}
+	if len(tasks) > 0 {
+		s.log.WithField("tasks", len(tasks)).Info("nudged tasks over their estimate")
+	}
+	return nil
+}
+
+func (s *ReminderService) fire(ctx context.Context, r *domain.Reminder, now time.Time) error {
+	task, err := s.taskSvc.GetByID(ctx, r.TaskID, r.UserID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && task.Status == domain.TaskStatusDone) {
+		return s.reminderRepo.Dismiss(ctx, r.ID, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Claim the reminder before sending, so a second worker cannot send it too.
+	if err := s.reminderRepo.MarkFired(ctx, r.ID, now); err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	body := "Reminder"
+	if task.DueDate != nil {
+		body = "Due " + task.DueDate.UTC().Format(time.RFC1123)
+	}
+	s.notifier.Notify(domain.NotificationEvent{
+		UserID:   r.UserID,
+		Type:     domain.EventTaskReminder,
+		Title:    task.Title,
+		Body:     body,
+		EntityID: &task.ID,
+		DedupKey: r.ID.String(),
+	})
+	return nil
+}
+
+// get loads a reminder of the user's task. Reminders of other tasks are
+// reported as not found.
+func (s *ReminderService) get(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.Reminder, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	r, err := s.reminderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if r.TaskID != taskID {
+		return nil, domain.ErrNotFound
+	}
+	return r, nil
+}
+
+func offsetFireAt(due time.Time, minutes int) *time.Time {
+	at := due.Add(-time.Duration(minutes) * time.Minute)
+	return &at
+}
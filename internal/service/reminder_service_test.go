@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeReminderRepo struct {
+	domain.ReminderRepository
+	reminders []*domain.Reminder
+}
+
+func (f *fakeReminderRepo) Create(_ context.Context, r *domain.Reminder) error {
+	f.reminders = append(f.reminders, r)
+	return nil
+}
+
+func (f *fakeReminderRepo) CountPending(_ context.Context, taskID uuid.UUID) (int, error) {
+	n := 0
+	for _, r := range f.reminders {
+		if r.TaskID == taskID && r.Status == domain.ReminderPending {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeReminderRepo) ListDue(_ context.Context, now time.Time, _ int) ([]*domain.Reminder, error) {
+	var out []*domain.Reminder
+	for _, r := range f.reminders {
+		if r.Status == domain.ReminderPending && r.FireAt != nil && !r.FireAt.After(now) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeReminderRepo) MarkFired(_ context.Context, id uuid.UUID, at time.Time) error {
+	for _, r := range f.reminders {
+		if r.ID == id && r.Status == domain.ReminderPending {
+			r.Status, r.FiredAt = domain.ReminderFired, &at
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func (f *fakeReminderRepo) Dismiss(_ context.Context, id uuid.UUID, at time.Time) error {
+	for _, r := range f.reminders {
+		if r.ID == id {
+			r.Status, r.DismissedAt = domain.ReminderDismissed, &at
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func newReminderService(reminders *fakeReminderRepo, taskRepo *mockTaskRepo, notifier service.Notifier) *service.ReminderService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewReminderService(reminders, newTaskService(taskRepo, &mockProjectRepo{}), notifier, log)
+}
+
+func TestReminderService_Create(t *testing.T) {
+	userID, taskID, undatedID := uuid.New(), uuid.New(), uuid.New()
+	due := time.Now().Add(48 * time.Hour)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, DueDate: &due}, nil)
+	taskRepo.On("FindByID", mock.Anything, undatedID).Return(&domain.Task{ID: undatedID, UserID: userID}, nil)
+	svc := newReminderService(&fakeReminderRepo{}, taskRepo, &fakeNotifier{})
+	ctx := context.Background()
+
+	offset := 90
+	r, err := svc.Create(ctx, taskID, userID, &domain.CreateReminderRequest{OffsetMinutes: &offset})
+	require.NoError(t, err)
+	assert.Equal(t, due.Add(-90*time.Minute), *r.FireAt)
+	assert.Equal(t, domain.ReminderPending, r.Status)
+
+	past := time.Now().Add(-time.Minute)
+	_, err = svc.Create(ctx, taskID, userID, &domain.CreateReminderRequest{RemindAt: &past})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	_, err = svc.Create(ctx, undatedID, userID, &domain.CreateReminderRequest{OffsetMinutes: &offset})
+	assert.ErrorIs(t, err, domain.ErrValidation, "offset needs a due date")
+
+	_, err = svc.Create(ctx, taskID, uuid.New(), &domain.CreateReminderRequest{OffsetMinutes: &offset})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestReminderService_CreateWithRelativeOffsets(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	due := time.Now().Add(30 * 24 * time.Hour)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, DueDate: &due}, nil)
+	svc := newReminderService(&fakeReminderRepo{}, taskRepo, &fakeNotifier{})
+	ctx := context.Background()
+
+	for offset, before := range map[string]time.Duration{
+		"-1d":    24 * time.Hour,
+		"-1h":    time.Hour,
+		"-1h30m": 90 * time.Minute,
+		"1W":     7 * 24 * time.Hour,
+	} {
+		r, err := svc.Create(ctx, taskID, userID, &domain.CreateReminderRequest{Offset: offset})
+		require.NoError(t, err, offset)
+		assert.Equal(t, int(before.Minutes()), *r.OffsetMinutes, offset)
+		assert.Equal(t, due.Add(-before), *r.FireAt, offset)
+	}
+
+	for _, offset := range []string{"-", "-1x", "soon", "-1d-2h", "-53w"} {
+		_, err := svc.Create(ctx, taskID, userID, &domain.CreateReminderRequest{Offset: offset})
+		assert.ErrorIs(t, err, domain.ErrValidation, offset)
+	}
+}
+
+func TestReminderService_FireDue(t *testing.T) {
+	userID, openID, doneID := uuid.New(), uuid.New(), uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, openID).Return(&domain.Task{ID: openID, UserID: userID, Title: "Call the bank", Status: domain.TaskStatusTodo}, nil)
+	taskRepo.On("FindByID", mock.Anything, doneID).Return(&domain.Task{ID: doneID, UserID: userID, Status: domain.TaskStatusDone}, nil)
+
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	dueOpen := &domain.Reminder{ID: uuid.New(), TaskID: openID, UserID: userID, FireAt: &past, Status: domain.ReminderPending}
+	dueDone := &domain.Reminder{ID: uuid.New(), TaskID: doneID, UserID: userID, FireAt: &past, Status: domain.ReminderPending}
+	later := &domain.Reminder{ID: uuid.New(), TaskID: openID, UserID: userID, FireAt: &future, Status: domain.ReminderPending}
+	reminders := &fakeReminderRepo{reminders: []*domain.Reminder{dueOpen, dueDone, later}}
+	notifier := &fakeNotifier{}
+	svc := newReminderService(reminders, taskRepo, notifier)
+
+	require.NoError(t, svc.FireDue(context.Background()))
+	require.NoError(t, svc.FireDue(context.Background()))
+
+	require.Len(t, notifier.events, 1, "each reminder fires once")
+	assert.Equal(t, domain.EventTaskReminder, notifier.events[0].Type)
+	assert.Equal(t, "Call the bank", notifier.events[0].Title)
+	assert.Equal(t, domain.ReminderFired, dueOpen.Status)
+	assert.Equal(t, domain.ReminderDismissed, dueDone.Status, "finished tasks are not reminded")
+	assert.Equal(t, domain.ReminderPending, later.Status)
+}
+
+func TestReminderService_NudgeEffortExceeded(t *testing.T) {
+	userID := uuid.New()
+	four := 4.0
+	stuck := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Migrate billing", Status: domain.TaskStatusInProgress, EstimatedHours: &four, EffortExceeded: true}
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FlagEffortExceeded", mock.Anything, mock.Anything).Return([]*domain.Task{stuck}, nil).Once()
+	taskRepo.On("FlagEffortExceeded", mock.Anything, mock.Anything).Return([]*domain.Task{}, nil)
+	notifier := &fakeNotifier{}
+	svc := newReminderService(&fakeReminderRepo{}, taskRepo, notifier)
+
+	require.NoError(t, svc.NudgeEffortExceeded(context.Background()))
+	require.NoError(t, svc.NudgeEffortExceeded(context.Background()))
+
+	require.Len(t, notifier.events, 1, "a flagged task is not nudged again")
+	assert.Equal(t, domain.EventTaskEffortExceeded, notifier.events[0].Type)
+	assert.Equal(t, stuck.ID, *notifier.events[0].EntityID)
+	assert.Contains(t, notifier.events[0].Body, "4 h estimate")
+}
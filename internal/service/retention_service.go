@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// RetentionService hard-deletes soft-deleted rows once their retention window
+// has passed. Defaults come from configuration; admins can override them per user.
+type RetentionService struct {
+	retentionRepo   domain.RetentionRepository
+	maintenanceRepo domain.MaintenanceRepository
+	userRepo        domain.UserRepository
+	defaults        domain.RetentionPolicy
+	interval        time.Duration
+	log             *logrus.Logger
+
+	mu      sync.Mutex
+	lastRun time.Time
+}
+
+// NewRetentionService constructs a RetentionService. interval must match the
+// schedule Purge is registered with so the report can predict the next run.
+func NewRetentionService(
+	retentionRepo domain.RetentionRepository,
+	maintenanceRepo domain.MaintenanceRepository,
+	userRepo domain.UserRepository,
+	defaults domain.RetentionPolicy,
+	interval time.Duration,
+	log *logrus.Logger,
+) *RetentionService {
+	return &RetentionService{
+		retentionRepo:   retentionRepo,
+		maintenanceRepo: maintenanceRepo,
+		userRepo:        userRepo,
+		defaults:        defaults,
+		interval:        interval,
+		log:             log,
+		lastRun:         time.Now(),
+	}
+}
+
+// Run is the scheduled purge job.
+func (s *RetentionService) Run(ctx context.Context) error {
+	_, err := s.Purge(ctx, false)
+	return err
+}
+
+// Purge hard-deletes every soft-deleted row past its retention window.
+func (s *RetentionService) Purge(ctx context.Context, dryRun bool) (*domain.OperationResult, error) {
+	now := time.Now()
+	result, err := s.purgeAsOf(now).run(ctx, dryRun)
+	if err != nil {
+		return nil, fmt.Errorf("retentionService.Purge: %w", err)
+	}
+	if !dryRun {
+		s.mu.Lock()
+		s.lastRun = now
+		s.mu.Unlock()
+		if result.Total > 0 {
+			s.log.WithField("rows", result.Total).Info("retention purge removed expired rows")
+		}
+	}
+	return result, nil
+}
+
+// Report lists exactly what the next scheduled purge would remove if nothing
+// else were deleted or restored before then.
+func (s *RetentionService) Report(ctx context.Context) (*domain.RetentionReport, error) {
+	s.mu.Lock()
+	next := s.lastRun.Add(s.interval)
+	s.mu.Unlock()
+
+	result, err := s.purgeAsOf(next).run(ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("retentionService.Report: %w", err)
+	}
+	return &domain.RetentionReport{Defaults: s.defaults, NextRunAt: next, Purge: result}, nil
+}
+
+// GetUserRetention returns the effective retention policy for a user.
+func (s *RetentionService) GetUserRetention(ctx context.Context, userID uuid.UUID) (*domain.UserRetention, error) {
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	overrides, err := s.retentionRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("retentionService.GetUserRetention: %w", err)
+	}
+
+	effective := make(domain.RetentionPolicy, len(s.defaults))
+	for entity, days := range s.defaults {
+		effective[entity] = days
+	}
+	for _, o := range overrides {
+		effective[o.EntityType] = o.RetentionDays
+	}
+	if overrides == nil {
+		overrides = []domain.RetentionOverride{}
+	}
+	return &domain.UserRetention{UserID: userID, Effective: effective, Overrides: overrides}, nil
+}
+
+// SetOverride replaces the retention window of one entity type for a user.
+func (s *RetentionService) SetOverride(ctx context.Context, userID uuid.UUID, req *domain.SetRetentionOverrideRequest) (*domain.UserRetention, error) {
+	override := &domain.RetentionOverride{
+		UserID:        userID,
+		EntityType:    req.EntityType,
+		RetentionDays: req.RetentionDays,
+		UpdatedAt:     time.Now(),
+	}
+	if err := s.retentionRepo.Upsert(ctx, override); err != nil {
+		return nil, fmt.Errorf("retentionService.SetOverride: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{
+		"user_id": userID, "entity_type": req.EntityType, "retention_days": req.RetentionDays,
+	}).Warn("admin overrode retention window")
+	return s.GetUserRetention(ctx, userID)
+}
+
+// DeleteOverride restores the default retention window of one entity type for a user.
+func (s *RetentionService) DeleteOverride(ctx context.Context, userID uuid.UUID, entityType string) error {
+	if err := s.retentionRepo.Delete(ctx, userID, entityType); err != nil {
+		return fmt.Errorf("retentionService.DeleteOverride: %w", err)
+	}
+	return nil
+}
+
+func (s *RetentionService) purgeAsOf(asOf time.Time) operation {
+	return operation{
+		name: "retention_purge",
+		plan: func(ctx context.Context) (map[string][]uuid.UUID, error) {
+			return s.maintenanceRepo.ListExpiredTrash(ctx, asOf, s.defaults)
+		},
+		apply: s.maintenanceRepo.PurgeTrash,
+	}
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRetentionRepo struct {
+	domain.RetentionRepository
+	overrides []domain.RetentionOverride
+}
+
+func (f *fakeRetentionRepo) ListByUserID(_ context.Context, userID uuid.UUID) ([]domain.RetentionOverride, error) {
+	var out []domain.RetentionOverride
+	for _, o := range f.overrides {
+		if o.UserID == userID {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+type fakeMaintenanceRepo struct {
+	domain.MaintenanceRepository
+	asOf   time.Time
+	expire map[string][]uuid.UUID
+	purged map[string][]uuid.UUID
+}
+
+func (f *fakeMaintenanceRepo) ListExpiredTrash(_ context.Context, asOf time.Time, _ domain.RetentionPolicy) (map[string][]uuid.UUID, error) {
+	f.asOf = asOf
+	return f.expire, nil
+}
+
+func (f *fakeMaintenanceRepo) PurgeTrash(_ context.Context, affected map[string][]uuid.UUID) error {
+	f.purged = affected
+	return nil
+}
+
+type fakeUserRepo struct {
+	domain.UserRepository
+}
+
+func (fakeUserRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id}, nil
+}
+
+var testRetention = domain.RetentionPolicy{domain.RetentionTasks: 30, domain.RetentionProjects: 30}
+
+func TestRetentionService_GetUserRetention_AppliesOverrides(t *testing.T) {
+	userID := uuid.New()
+	repo := &fakeRetentionRepo{overrides: []domain.RetentionOverride{
+		{UserID: userID, EntityType: domain.RetentionTasks, RetentionDays: 90},
+		{UserID: uuid.New(), EntityType: domain.RetentionProjects, RetentionDays: 7},
+	}}
+	svc := service.NewRetentionService(repo, &fakeMaintenanceRepo{}, fakeUserRepo{}, testRetention, time.Hour, logrus.New())
+
+	got, err := svc.GetUserRetention(context.Background(), userID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 90, got.Effective[domain.RetentionTasks])
+	assert.Equal(t, 30, got.Effective[domain.RetentionProjects])
+	assert.Len(t, got.Overrides, 1)
+}
+
+func TestRetentionService_ReportIsReadOnlyAndLooksAhead(t *testing.T) {
+	maint := &fakeMaintenanceRepo{expire: map[string][]uuid.UUID{domain.RetentionTasks: {uuid.New()}}}
+	svc := service.NewRetentionService(&fakeRetentionRepo{}, maint, fakeUserRepo{}, testRetention, 24*time.Hour, logrus.New())
+
+	report, err := svc.Report(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Purge.DryRun)
+	assert.Equal(t, 1, report.Purge.Total)
+	assert.Nil(t, maint.purged)
+	assert.Equal(t, report.NextRunAt, maint.asOf)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), report.NextRunAt, time.Minute)
+}
+
+func TestRetentionService_PurgeDeletesPlannedRows(t *testing.T) {
+	ids := map[string][]uuid.UUID{domain.RetentionProjects: {uuid.New(), uuid.New()}}
+	maint := &fakeMaintenanceRepo{expire: ids}
+	svc := service.NewRetentionService(&fakeRetentionRepo{}, maint, fakeUserRepo{}, testRetention, time.Hour, logrus.New())
+
+	result, err := svc.Purge(context.Background(), false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Total)
+	assert.Equal(t, ids, maint.purged)
+}
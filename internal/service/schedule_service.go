@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// maxWorkloadTasks caps the tasks summed into one workload.
+const maxWorkloadTasks = 1000
+
+// maxSuggestedSlots caps the slots suggested for a task.
+const maxSuggestedSlots = 10
+
+// ScheduleService measures tasks against the user's clock: how overdue they
+// are, how much work is due against the time available, and when there is
+// room to do it. With WorkingTimeOnly on their business calendar only
+// working hours count; otherwise every hour does.
+type ScheduleService struct {
+	calendarSvc *BusinessCalendarService
+	taskSvc     *TaskService
+	userRepo    domain.UserRepository
+	log         *logrus.Logger
+}
+
+// NewScheduleService constructs a ScheduleService.
+func NewScheduleService(calendarSvc *BusinessCalendarService, taskSvc *TaskService, userRepo domain.UserRepository, log *logrus.Logger) *ScheduleService {
+	return &ScheduleService{calendarSvc: calendarSvc, taskSvc: taskSvc, userRepo: userRepo, log: log}
+}
+
+// Schedule returns where a task stands at now: its deadline, how overdue it
+// is, the time left before it and up to slots places to fit the remaining
+// estimate in, finishing by the deadline while there is still time. It
+// enforces ownership.
+func (s *ScheduleService) Schedule(ctx context.Context, taskID, userID uuid.UUID, now time.Time, slots int) (*domain.TaskSchedule, error) {
+	task, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	loc, err := userLocation(ctx, s.userRepo, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.Schedule: %w", err)
+	}
+	clock, workingOnly, err := s.calendarSvc.Clock(ctx, userID, now)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.Schedule: %w", err)
+	}
+
+	sched := &domain.TaskSchedule{TaskID: task.ID, WorkingTimeOnly: workingOnly, Slots: []domain.TimeSlot{}}
+	if task.Status == domain.TaskStatusDone {
+		return sched, nil
+	}
+
+	var before time.Time
+	if task.DueDate != nil {
+		deadline := task.Deadline(loc)
+		sched.Deadline = &deadline
+		if deadline.After(now) {
+			sched.HoursLeft = clock.WorkingTime(now, deadline).Hours()
+			before = deadline
+		} else {
+			// Past the deadline: measure from it, so days off since count.
+			clock, _, err = s.calendarSvc.Clock(ctx, userID, deadline)
+			if err != nil {
+				return nil, fmt.Errorf("scheduleService.Schedule: %w", err)
+			}
+			overdue := task.OverdueFor(now, loc, clock.WorkingTime)
+			sched.Overdue = overdue > 0
+			sched.OverdueHours = overdue.Hours()
+		}
+	}
+
+	sched.SlotHours = 1
+	if remaining, ok := task.RemainingHours(); ok {
+		sched.SlotHours = remaining
+	}
+	if sched.SlotHours > 0 {
+		d := time.Duration(sched.SlotHours * float64(time.Hour))
+		for _, slot := range clock.Slots(now, d, before, min(max(slots, 1), maxSuggestedSlots)) {
+			sched.Slots = append(sched.Slots, domain.TimeSlot{Start: slot.Start, End: slot.End})
+		}
+	}
+	return sched, nil
+}
+
+// Workload compares the remaining estimates of the user's open tasks due by
+// to, overdue ones included, with the time available between from and to.
+// A task whose subtasks carry estimates counts their open estimate, and
+// those subtasks are not counted again.
+func (s *ScheduleService) Workload(ctx context.Context, userID uuid.UUID, from, to time.Time) (*domain.Workload, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("scheduleService.Workload: to must be after from: %w", domain.ErrValidation)
+	}
+	clock, workingOnly, err := s.calendarSvc.Clock(ctx, userID, from)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.Workload: %w", err)
+	}
+	tasks, _, err := s.taskSvc.List(ctx, userID, domain.TaskFilter{DueBefore: &to, IncludeDeferred: true}, 1, maxWorkloadTasks)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.Workload: %w", err)
+	}
+
+	w := &domain.Workload{
+		From:            from,
+		To:              to,
+		WorkingTimeOnly: workingOnly,
+		AvailableHours:  clock.WorkingTime(from, to).Hours(),
+	}
+	rolledUp := make(map[uuid.UUID]bool)
+	for _, t := range tasks {
+		if t.Status != domain.TaskStatusDone && t.RolledUpEstimate != nil {
+			rolledUp[t.ID] = true
+		}
+	}
+	for _, t := range tasks {
+		if t.Status == domain.TaskStatusDone || (t.ParentID != nil && rolledUp[*t.ParentID]) {
+			continue
+		}
+		w.Tasks++
+		remaining, ok := t.WorkloadHours()
+		if !ok {
+			w.UnestimatedTasks++
+			continue
+		}
+		w.PlannedHours += remaining
+	}
+	if w.AvailableHours > 0 {
+		w.UtilizationPercent = w.PlannedHours / w.AvailableHours * 100
+	}
+	return w, nil
+}
+
+// FilterOverdue implements OverdueFilter: for users counting working time
+// only, a task is overdue once working time has passed since its deadline,
+// so one due at the end of Friday is not overdue before work on Monday.
+func (s *ScheduleService) FilterOverdue(ctx context.Context, userID uuid.UUID, tasks []*domain.Task, now time.Time) ([]*domain.Task, error) {
+	if len(tasks) == 0 {
+		return tasks, nil
+	}
+	loc, err := userLocation(ctx, s.userRepo, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.FilterOverdue: %w", err)
+	}
+	earliest := now
+	for _, t := range tasks {
+		if t.DueDate != nil && t.Deadline(loc).Before(earliest) {
+			earliest = t.Deadline(loc)
+		}
+	}
+	clock, workingOnly, err := s.calendarSvc.Clock(ctx, userID, earliest)
+	if err != nil {
+		return nil, fmt.Errorf("scheduleService.FilterOverdue: %w", err)
+	}
+	if !workingOnly {
+		return tasks, nil
+	}
+
+	kept := make([]*domain.Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.OverdueFor(now, loc, clock.WorkingTime) > 0 {
+			kept = append(kept, t)
+		}
+	}
+	return kept, nil
+}
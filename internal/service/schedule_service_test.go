@@ -0,0 +1,109 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newScheduleService(t *testing.T, taskRepo *mockTaskRepo, userID uuid.UUID) *service.ScheduleService {
+	t.Helper()
+	calendarSvc := newBusinessCalendarService(t, &domain.BusinessCalendar{
+		UserID: userID, Timezone: "UTC", WeekendDays: domain.DefaultWeekendDays,
+		WorkStart: "09:00", WorkEnd: "17:00", WorkingTimeOnly: true,
+	})
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewScheduleService(calendarSvc, newTaskService(taskRepo, &mockProjectRepo{}), fakeUserRepo{}, log)
+}
+
+func TestScheduleService_CountsOnlyWorkingTime(t *testing.T) {
+	userID := uuid.New()
+	friday5pm := time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC)
+	task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Send report", Status: domain.TaskStatusTodo, DueDate: &friday5pm}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	svc := newScheduleService(t, taskRepo, userID)
+
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	sched, err := svc.Schedule(context.Background(), task.ID, userID, monday, 1)
+	require.NoError(t, err)
+	assert.True(t, sched.WorkingTimeOnly)
+	assert.False(t, sched.Overdue, "nothing of the working week has passed yet")
+
+	sched, err = svc.Schedule(context.Background(), task.ID, userID, monday.Add(90*time.Minute), 1)
+	require.NoError(t, err)
+	assert.True(t, sched.Overdue)
+	assert.Equal(t, 1.5, sched.OverdueHours)
+	require.Len(t, sched.Slots, 1)
+	assert.Equal(t, monday.Add(90*time.Minute), sched.Slots[0].Start)
+	assert.Equal(t, monday.Add(150*time.Minute), sched.Slots[0].End, "an hour without an estimate")
+}
+
+func TestScheduleService_FilterOverdue(t *testing.T) {
+	userID := uuid.New()
+	friday5pm := time.Date(2026, 10, 16, 17, 0, 0, 0, time.UTC)
+	fridayNoon := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	endOfWeek := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Weekly report", DueDate: &friday5pm}
+	midday := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Lunch order", DueDate: &fridayNoon}
+
+	svc := newScheduleService(t, &mockTaskRepo{}, userID)
+	kept, err := svc.FilterOverdue(context.Background(), userID, []*domain.Task{midday, endOfWeek}, time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Task{midday}, kept)
+}
+
+func TestScheduleService_Workload(t *testing.T) {
+	userID := uuid.New()
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2026, 10, 23, 17, 0, 0, 0, time.UTC)
+	ten, five := 10.0, 5.0
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{DueBefore: &friday, IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{
+		{ID: uuid.New(), UserID: userID, Status: domain.TaskStatusInProgress, EstimatedHours: &ten, TrackedSeconds: 2 * 3600},
+		{ID: uuid.New(), UserID: userID, Status: domain.TaskStatusTodo},
+		{ID: uuid.New(), UserID: userID, Status: domain.TaskStatusDone, EstimatedHours: &five},
+	}, 3, nil)
+	svc := newScheduleService(t, taskRepo, userID)
+
+	w, err := svc.Workload(context.Background(), userID, monday, friday)
+	require.NoError(t, err)
+	assert.Equal(t, 40.0, w.AvailableHours)
+	assert.Equal(t, 8.0, w.PlannedHours)
+	assert.Equal(t, 2, w.Tasks)
+	assert.Equal(t, 1, w.UnestimatedTasks)
+	assert.Equal(t, 20.0, w.UtilizationPercent)
+
+	_, err = svc.Workload(context.Background(), userID, friday, monday)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestScheduleService_Workload_RollsUpSubtasks(t *testing.T) {
+	userID := uuid.New()
+	monday := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2026, 10, 23, 17, 0, 0, 0, time.UTC)
+	own, rolled, progress, six := 2.0, 8.0, 25.0, 6.0
+	parentID := uuid.New()
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{DueBefore: &friday, IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{
+		{ID: parentID, UserID: userID, Status: domain.TaskStatusTodo, EstimatedHours: &own, RolledUpEstimate: &rolled, SubtaskProgress: &progress},
+		{ID: uuid.New(), UserID: userID, ParentID: &parentID, Status: domain.TaskStatusTodo, EstimatedHours: &six},
+	}, 2, nil)
+	svc := newScheduleService(t, taskRepo, userID)
+
+	w, err := svc.Workload(context.Background(), userID, monday, friday)
+	require.NoError(t, err)
+	assert.Equal(t, 6.0, w.PlannedHours, "the open quarter of the rollup, not the parent's own estimate")
+	assert.Equal(t, 1, w.Tasks, "the subtask counts through its parent")
+}
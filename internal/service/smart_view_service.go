@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// SmartViewService serves the built-in smart lists and their badge counts.
+// Day boundaries follow the location passed in, or the user's own time zone
+// when it is nil.
+type SmartViewService struct {
+	viewRepo  domain.SmartViewRepository
+	userRepo  domain.UserRepository
+	coalescer *ReadCoalescer
+}
+
+// NewSmartViewService constructs a SmartViewService.
+func NewSmartViewService(viewRepo domain.SmartViewRepository, userRepo domain.UserRepository) *SmartViewService {
+	return &SmartViewService{viewRepo: viewRepo, userRepo: userRepo}
+}
+
+// UseCoalescer shares each badge query between concurrent requests for the
+// same user and time zone.
+func (s *SmartViewService) UseCoalescer(c *ReadCoalescer) {
+	s.coalescer = c
+}
+
+// List returns every system view with its current task count.
+func (s *SmartViewService) List(ctx context.Context, userID uuid.UUID, loc *time.Location) ([]domain.SmartView, error) {
+	loc, err := userLocation(ctx, s.userRepo, userID, loc)
+	if err != nil {
+		return nil, fmt.Errorf("smartViewService.List: %w", err)
+	}
+	counts, err := s.viewRepo.Counts(ctx, userID, domain.NewViewWindow(time.Now(), loc))
+	if err != nil {
+		return nil, fmt.Errorf("smartViewService.List: %w", err)
+	}
+
+	views := make([]domain.SmartView, len(domain.SystemViews))
+	for i, v := range domain.SystemViews {
+		v.Count = counts[v.Key]
+		views[i] = v
+	}
+	return views, nil
+}
+
+// Tasks returns one page of the tasks in a system view.
+func (s *SmartViewService) Tasks(ctx context.Context, userID uuid.UUID, key string, loc *time.Location, page, limit int) ([]*domain.Task, int, error) {
+	if !domain.IsSystemView(key) {
+		return nil, 0, domain.ErrNotFound
+	}
+	loc, err := userLocation(ctx, s.userRepo, userID, loc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("smartViewService.Tasks: %w", err)
+	}
+	tasks, total, err := s.viewRepo.List(ctx, userID, key, domain.NewViewWindow(time.Now(), loc), page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("smartViewService.Tasks: %w", err)
+	}
+	return tasks, total, nil
+}
+
+// Badges returns the aggregated counts polled by clients for badges.
+func (s *SmartViewService) Badges(ctx context.Context, userID uuid.UUID, loc *time.Location) (*domain.Badges, error) {
+	loc, err := userLocation(ctx, s.userRepo, userID, loc)
+	if err != nil {
+		return nil, fmt.Errorf("smartViewService.Badges: %w", err)
+	}
+	badges, err := coalesce(ctx, s.coalescer, coalesceBadges, userID.String()+"/"+loc.String(), func(ctx context.Context) (*domain.Badges, error) {
+		return s.viewRepo.Badges(ctx, userID, domain.NewViewWindow(time.Now(), loc))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("smartViewService.Badges: %w", err)
+	}
+	return badges, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultTagColor = "#64748B" // slate
+
+// TagService handles tag management and tagging of tasks.
+type TagService struct {
+	tagRepo domain.TagRepository
+	taskSvc *TaskService
+	events  *eventbus.Bus
+	log     *logrus.Logger
+}
+
+// NewTagService constructs a TagService with its dependencies.
+func NewTagService(tagRepo domain.TagRepository, taskSvc *TaskService, log *logrus.Logger) *TagService {
+	return &TagService{tagRepo: tagRepo, taskSvc: taskSvc, log: log}
+}
+
+// UseEvents publishes tag events on bus. Must be called before serving requests.
+func (s *TagService) UseEvents(bus *eventbus.Bus) {
+	s.events = bus
+}
+
+// Create creates a new tag for the authenticated user.
+func (s *TagService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateTagRequest) (*domain.Tag, error) {
+	color := req.Color
+	if color == "" {
+		color = defaultTagColor
+	}
+	now := time.Now()
+	tag := &domain.Tag{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Color:     color,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := s.tagRepo.Create(ctx, tag); err != nil {
+		return nil, fmt.Errorf("tagService.Create: %w", err)
+	}
+
+	s.publish(ctx, domain.TagCreated{Tag: tag})
+	return tag, nil
+}
+
+// GetByID retrieves a tag, enforcing ownership.
+func (s *TagService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Tag, error) {
+	tag, err := s.tagRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if tag.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	return tag, nil
+}
+
+// List returns all tags for the authenticated user, by name.
+func (s *TagService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Tag, error) {
+	tags, err := s.tagRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("tagService.List: %w", err)
+	}
+	return tags, nil
+}
+
+// Update applies partial updates to a tag, enforcing ownership.
+func (s *TagService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateTagRequest) (*domain.Tag, error) {
+	tag, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		tag.Name = *req.Name
+	}
+	if req.Color != nil {
+		tag.Color = *req.Color
+	}
+	tag.UpdatedAt = time.Now()
+
+	if err := s.tagRepo.Update(ctx, tag); err != nil {
+		return nil, fmt.Errorf("tagService.Update: %w", err)
+	}
+
+	s.publish(ctx, domain.TagUpdated{Tag: tag})
+	return tag, nil
+}
+
+// Delete removes a tag from the user's tags and from every task.
+func (s *TagService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	tag, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.tagRepo.Delete(ctx, tag.ID); err != nil {
+		return fmt.Errorf("tagService.Delete: %w", err)
+	}
+
+	s.publish(ctx, domain.TagDeleted{Tag: tag})
+	return nil
+}
+
+// ListForTask returns the tags on a task, enforcing task ownership.
+func (s *TagService) ListForTask(ctx context.Context, taskID, userID uuid.UUID) ([]*domain.Tag, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("tagService.ListForTask: %w", err)
+	}
+	return tags, nil
+}
+
+// SetForTask replaces the tags on a task. Every tag must belong to the user.
+func (s *TagService) SetForTask(ctx context.Context, taskID, userID uuid.UUID, tagIDs []uuid.UUID) ([]*domain.Tag, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	tagIDs = uniqueIDs(tagIDs)
+	if len(tagIDs) > 0 {
+		owned, err := s.tagRepo.CountOwned(ctx, userID, tagIDs)
+		if err != nil {
+			return nil, fmt.Errorf("tagService.SetForTask: %w", err)
+		}
+		if owned != len(tagIDs) {
+			return nil, fmt.Errorf("tagService.SetForTask: unknown tag: %w", domain.ErrValidation)
+		}
+	}
+
+	if err := s.tagRepo.SetForTask(ctx, taskID, tagIDs); err != nil {
+		return nil, fmt.Errorf("tagService.SetForTask: %w", err)
+	}
+	return s.ListForTask(ctx, taskID, userID)
+}
+
+func (s *TagService) publish(ctx context.Context, e domain.TagChange) {
+	s.events.Publish(ctx, e)
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	out := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
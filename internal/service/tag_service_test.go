@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTagRepo struct {
+	domain.TagRepository
+	tags   []*domain.Tag
+	onTask map[uuid.UUID][]uuid.UUID
+}
+
+func (f *fakeTagRepo) Create(_ context.Context, tag *domain.Tag) error {
+	f.tags = append(f.tags, tag)
+	return nil
+}
+
+func (f *fakeTagRepo) ListByUserID(_ context.Context, userID uuid.UUID) ([]*domain.Tag, error) {
+	out := []*domain.Tag{}
+	for _, t := range f.tags {
+		if t.UserID == userID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTagRepo) CountOwned(_ context.Context, userID uuid.UUID, ids []uuid.UUID) (int, error) {
+	n := 0
+	for _, t := range f.tags {
+		for _, id := range ids {
+			if t.ID == id && t.UserID == userID {
+				n++
+			}
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeTagRepo) SetForTask(_ context.Context, taskID uuid.UUID, ids []uuid.UUID) error {
+	if f.onTask == nil {
+		f.onTask = map[uuid.UUID][]uuid.UUID{}
+	}
+	f.onTask[taskID] = ids
+	return nil
+}
+
+func (f *fakeTagRepo) ListByTaskID(_ context.Context, taskID uuid.UUID) ([]*domain.Tag, error) {
+	out := []*domain.Tag{}
+	for _, id := range f.onTask[taskID] {
+		for _, t := range f.tags {
+			if t.ID == id {
+				out = append(out, t)
+			}
+		}
+	}
+	return out, nil
+}
+
+func TestTagService_SetForTask(t *testing.T) {
+	userID, taskID := uuid.New(), uuid.New()
+	mine := &domain.Tag{ID: uuid.New(), UserID: userID, Name: "home"}
+	theirs := &domain.Tag{ID: uuid.New(), UserID: uuid.New(), Name: "work"}
+	tags := &fakeTagRepo{tags: []*domain.Tag{mine, theirs}}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID}, nil)
+	svc := service.NewTagService(tags, newTaskService(taskRepo, &mockProjectRepo{}), logrus.New())
+	ctx := context.Background()
+
+	got, err := svc.SetForTask(ctx, taskID, userID, []uuid.UUID{mine.ID, mine.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Tag{mine}, got)
+	assert.Equal(t, []uuid.UUID{mine.ID}, tags.onTask[taskID])
+
+	_, err = svc.SetForTask(ctx, taskID, userID, []uuid.UUID{theirs.ID})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
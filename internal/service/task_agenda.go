@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// maxAgendaTasks caps the tasks on one Today or Upcoming list.
+	maxAgendaTasks = 200
+	// todayHighScoreTasks is how many of the best-scoring tasks Today
+	// suggests besides those that are due.
+	todayHighScoreTasks = 5
+	// defaultNowTasks and maxNowTasks bound the Now list.
+	defaultNowTasks = 20
+	maxNowTasks     = 100
+)
+
+// Today lists what needs attention today: overdue tasks, most overdue
+// first, then tasks due today by due time, then the highest-scoring other
+// open tasks. Day boundaries follow loc, or the user's time zone when it is
+// nil. Tasks that have not started yet are left out.
+func (s *TaskService) Today(ctx context.Context, userID uuid.UUID, loc *time.Location) ([]*domain.AgendaTask, error) {
+	if loc == nil {
+		loc = s.location(ctx, userID)
+	}
+	now := time.Now()
+	w := domain.NewViewWindow(now, loc)
+	tasks, err := s.taskRepo.ListAgenda(ctx, userID, w.DayEnd, todayHighScoreTasks, maxAgendaTasks)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.Today: %w", err)
+	}
+
+	var overdue, dueToday, highScore []*domain.AgendaTask
+	for _, t := range tasks {
+		switch reason := agendaReason(t, now, w, loc); reason {
+		case domain.AgendaOverdue:
+			overdue = append(overdue, &domain.AgendaTask{Task: t, Reason: reason})
+		case domain.AgendaDueToday:
+			dueToday = append(dueToday, &domain.AgendaTask{Task: t, Reason: reason})
+		default:
+			highScore = append(highScore, &domain.AgendaTask{Task: t, Reason: domain.AgendaHighScore})
+		}
+	}
+	sort.SliceStable(highScore, func(i, j int) bool { return highScore[i].SmartScore > highScore[j].SmartScore })
+	return append(append(overdue, dueToday...), highScore...), nil
+}
+
+// Upcoming lists overdue tasks, then those due from today through the next
+// 7 days, each earliest due first. Day boundaries follow loc, or the user's time
+// zone when it is nil.
+func (s *TaskService) Upcoming(ctx context.Context, userID uuid.UUID, loc *time.Location) ([]*domain.AgendaTask, error) {
+	if loc == nil {
+		loc = s.location(ctx, userID)
+	}
+	now := time.Now()
+	w := domain.NewViewWindow(now, loc)
+	tasks, err := s.taskRepo.ListAgenda(ctx, userID, w.UpcomingEnd, 0, maxAgendaTasks)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.Upcoming: %w", err)
+	}
+
+	var overdue, due []*domain.AgendaTask
+	for _, t := range tasks {
+		item := &domain.AgendaTask{Task: t, Reason: agendaReason(t, now, w, loc)}
+		if item.Reason == domain.AgendaOverdue {
+			overdue = append(overdue, item)
+		} else {
+			due = append(due, item)
+		}
+	}
+	return append(overdue, due...), nil
+}
+
+// agendaReason places a task on an agenda: overdue by its deadline, due
+// today, or otherwise upcoming.
+func agendaReason(t *domain.Task, now time.Time, w domain.ViewWindow, loc *time.Location) string {
+	switch {
+	case t.IsOverdueAt(now, loc):
+		return domain.AgendaOverdue
+	case t.DueDate != nil && t.DueDate.Before(w.DayEnd):
+		return domain.AgendaDueToday
+	default:
+		return domain.AgendaUpcoming
+	}
+}
+
+// Now ranks the open, started, unblocked tasks that suit the user's current
+// situation: those needing no more than energy and doable in taskContext
+// (e.g. "@phone"), with tasks that set neither fitting anywhere. Tasks in
+// exactly that context lead, then those at exactly that energy, each by
+// smart score. A nil energy or empty context leaves that side open.
+func (s *TaskService) Now(ctx context.Context, userID uuid.UUID, energy *domain.TaskEnergy, taskContext string, limit int) ([]*domain.Task, error) {
+	if limit <= 0 {
+		limit = defaultNowTasks
+	}
+	limit = min(limit, maxNowTasks)
+	tasks, err := s.taskRepo.ListSuitable(ctx, userID, energy, domain.NormalizeTaskContext(taskContext), limit)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.Now: %w", err)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,84 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_Today(t *testing.T) {
+	userID := uuid.New()
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	local := time.Now().In(jkt)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, jkt)
+	yesterday := midnight.AddDate(0, 0, -1)
+	nextWeek := midnight.AddDate(0, 0, 7)
+
+	// A date-only due date today sorts first but is not overdue until the
+	// day is over.
+	today := &domain.Task{ID: uuid.New(), Title: "Today", Status: domain.TaskStatusTodo, DueDate: &midnight}
+	late := &domain.Task{ID: uuid.New(), Title: "Late", Status: domain.TaskStatusTodo, DueDate: &yesterday}
+	lowLater := &domain.Task{ID: uuid.New(), Title: "Later", Status: domain.TaskStatusTodo, DueDate: &nextWeek, SmartScore: 10}
+	high := &domain.Task{ID: uuid.New(), Title: "Undated", Status: domain.TaskStatusTodo, SmartScore: 40}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("ListAgenda", mock.Anything, userID, midnight.AddDate(0, 0, 1), 5, mock.Anything).
+		Return([]*domain.Task{late, today, lowLater, high}, nil)
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+	svc.UseLocator(fixedLocator{jkt})
+
+	out, err := svc.Today(context.Background(), userID, nil)
+	require.NoError(t, err)
+	require.Len(t, out, 4)
+	got := make([]string, len(out))
+	for i, a := range out {
+		got[i] = a.Title + ":" + a.Reason
+	}
+	assert.Equal(t, []string{"Late:overdue", "Today:due_today", "Undated:high_score", "Later:high_score"}, got)
+}
+
+func TestTaskService_Upcoming(t *testing.T) {
+	userID := uuid.New()
+	now := time.Now().UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	hourAgo := now.Add(-time.Hour)
+	tomorrow := midnight.AddDate(0, 0, 1)
+
+	earlier := &domain.Task{ID: uuid.New(), Title: "Earlier", Status: domain.TaskStatusTodo, DueDate: &midnight}
+	missed := &domain.Task{ID: uuid.New(), Title: "Missed", Status: domain.TaskStatusInProgress, DueDate: &hourAgo}
+	soon := &domain.Task{ID: uuid.New(), Title: "Soon", Status: domain.TaskStatusTodo, DueDate: &tomorrow}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("ListAgenda", mock.Anything, userID, midnight.AddDate(0, 0, 8), 0, mock.Anything).
+		Return([]*domain.Task{earlier, missed, soon}, nil)
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	out, err := svc.Upcoming(context.Background(), userID, time.UTC)
+	require.NoError(t, err)
+	require.Len(t, out, 3)
+	assert.Equal(t, missed.ID, out[0].ID)
+	assert.Equal(t, domain.AgendaOverdue, out[0].Reason)
+	assert.Equal(t, domain.AgendaDueToday, out[1].Reason)
+	assert.Equal(t, domain.AgendaUpcoming, out[2].Reason)
+}
+
+func TestTaskService_Now(t *testing.T) {
+	userID := uuid.New()
+	low := domain.TaskEnergyLow
+	call := &domain.Task{ID: uuid.New(), Title: "Call the bank"}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("ListSuitable", mock.Anything, userID, &low, "phone", 100).Return([]*domain.Task{call}, nil)
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	out, err := svc.Now(context.Background(), userID, &low, " @Phone", 1000)
+	require.NoError(t, err)
+	assert.Equal(t, []*domain.Task{call}, out)
+	taskRepo.AssertExpectations(t)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TaskDependencyService manages which tasks block which, and stops a task
+// from being completed while any of its blockers are still open.
+type TaskDependencyService struct {
+	depRepo domain.TaskDependencyRepository
+	taskSvc *TaskService
+	log     *logrus.Logger
+}
+
+// NewTaskDependencyService constructs a TaskDependencyService.
+func NewTaskDependencyService(depRepo domain.TaskDependencyRepository, taskSvc *TaskService, log *logrus.Logger) *TaskDependencyService {
+	return &TaskDependencyService{depRepo: depRepo, taskSvc: taskSvc, log: log}
+}
+
+// Add marks taskID as blocked by blockedByID. Both tasks must belong to the
+// user, and the new dependency must not close a cycle.
+func (s *TaskDependencyService) Add(ctx context.Context, taskID, userID, blockedByID uuid.UUID) (*domain.TaskDependencies, error) {
+	if taskID == blockedByID {
+		return nil, fmt.Errorf("taskDependencyService.Add: a task cannot block itself: %w", domain.ErrValidation)
+	}
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.taskSvc.GetByID(ctx, blockedByID, userID); err != nil {
+		return nil, err
+	}
+
+	cycle, err := s.depRepo.DependsOn(ctx, blockedByID, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("taskDependencyService.Add: %w", err)
+	}
+	if cycle {
+		return nil, fmt.Errorf("taskDependencyService.Add: dependency would create a cycle: %w", domain.ErrValidation)
+	}
+
+	if err := s.depRepo.Add(ctx, taskID, blockedByID); err != nil {
+		return nil, fmt.Errorf("taskDependencyService.Add: %w", err)
+	}
+	return s.List(ctx, taskID, userID)
+}
+
+// Remove deletes the dependency of taskID on blockedByID.
+func (s *TaskDependencyService) Remove(ctx context.Context, taskID, userID, blockedByID uuid.UUID) error {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return err
+	}
+	if err := s.depRepo.Remove(ctx, taskID, blockedByID); err != nil {
+		return fmt.Errorf("taskDependencyService.Remove: %w", err)
+	}
+	return nil
+}
+
+// List returns the tasks blocking taskID and the tasks it blocks.
+func (s *TaskDependencyService) List(ctx context.Context, taskID, userID uuid.UUID) (*domain.TaskDependencies, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	blockers, err := s.depRepo.ListBlockers(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("taskDependencyService.List: %w", err)
+	}
+	blocking, err := s.depRepo.ListBlocking(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("taskDependencyService.List: %w", err)
+	}
+	return &domain.TaskDependencies{BlockedBy: blockers, Blocking: blocking}, nil
+}
+
+// CheckCompletion implements TaskCompletionGuard.
+func (s *TaskDependencyService) CheckCompletion(ctx context.Context, task *domain.Task) error {
+	open, err := s.depRepo.CountOpenBlockers(ctx, task.ID)
+	if err != nil {
+		return fmt.Errorf("taskDependencyService.CheckCompletion: %w", err)
+	}
+	if open > 0 {
+		return fmt.Errorf("taskDependencyService.CheckCompletion: %d open blockers: %w", open, domain.ErrTaskBlocked)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeDependencyRepo stores edges as task -> blockers; tasks in open are
+// treated as not done.
+type fakeDependencyRepo struct {
+	domain.TaskDependencyRepository
+	blockers map[uuid.UUID][]uuid.UUID
+	open     map[uuid.UUID]bool
+}
+
+func (f *fakeDependencyRepo) Add(_ context.Context, taskID, blockedByID uuid.UUID) error {
+	if f.blockers == nil {
+		f.blockers = map[uuid.UUID][]uuid.UUID{}
+	}
+	f.blockers[taskID] = append(f.blockers[taskID], blockedByID)
+	return nil
+}
+
+func (f *fakeDependencyRepo) ListBlockers(_ context.Context, taskID uuid.UUID) ([]*domain.Task, error) {
+	out := []*domain.Task{}
+	for _, id := range f.blockers[taskID] {
+		out = append(out, &domain.Task{ID: id})
+	}
+	return out, nil
+}
+
+func (f *fakeDependencyRepo) ListBlocking(context.Context, uuid.UUID) ([]*domain.Task, error) {
+	return []*domain.Task{}, nil
+}
+
+func (f *fakeDependencyRepo) CountOpenBlockers(_ context.Context, taskID uuid.UUID) (int, error) {
+	n := 0
+	for _, id := range f.blockers[taskID] {
+		if f.open[id] {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeDependencyRepo) DependsOn(_ context.Context, taskID, otherID uuid.UUID) (bool, error) {
+	for _, id := range f.blockers[taskID] {
+		if id == otherID {
+			return true, nil
+		}
+		if found, _ := f.DependsOn(context.Background(), id, otherID); found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestTaskDependencyService_Add(t *testing.T) {
+	userID := uuid.New()
+	a, b, c := uuid.New(), uuid.New(), uuid.New()
+	taskRepo := &mockTaskRepo{}
+	for _, id := range []uuid.UUID{a, b, c} {
+		taskRepo.On("FindByID", mock.Anything, id).Return(&domain.Task{ID: id, UserID: userID}, nil)
+	}
+	deps := &fakeDependencyRepo{}
+	svc := service.NewTaskDependencyService(deps, newTaskService(taskRepo, &mockProjectRepo{}), logrus.New())
+	ctx := context.Background()
+
+	got, err := svc.Add(ctx, a, userID, b)
+	require.NoError(t, err)
+	require.Len(t, got.BlockedBy, 1)
+	assert.Equal(t, b, got.BlockedBy[0].ID)
+
+	_, err = svc.Add(ctx, b, userID, c)
+	require.NoError(t, err)
+
+	_, err = svc.Add(ctx, c, userID, a)
+	assert.ErrorIs(t, err, domain.ErrValidation, "a -> b -> c -> a is a cycle")
+
+	_, err = svc.Add(ctx, a, userID, a)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
+func TestTaskService_Update_RejectsDoneWhileBlocked(t *testing.T) {
+	userID, taskID, blockerID := uuid.New(), uuid.New(), uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID, Status: domain.TaskStatusTodo}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	deps := &fakeDependencyRepo{
+		blockers: map[uuid.UUID][]uuid.UUID{taskID: {blockerID}},
+		open:     map[uuid.UUID]bool{blockerID: true},
+	}
+	taskSvc := newTaskService(taskRepo, &mockProjectRepo{})
+	taskSvc.UseCompletionGuard(service.NewTaskDependencyService(deps, taskSvc, logrus.New()))
+	ctx := context.Background()
+	done := domain.TaskStatusDone
+
+	_, err := taskSvc.Update(ctx, taskID, userID, &domain.UpdateTaskRequest{Status: &done})
+	assert.ErrorIs(t, err, domain.ErrTaskBlocked)
+	taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+
+	deps.open[blockerID] = false
+	task, err := taskSvc.Update(ctx, taskID, userID, &domain.UpdateTaskRequest{Status: &done})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TaskStatusDone, task.Status)
+}
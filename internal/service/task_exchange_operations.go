@@ -0,0 +1,100 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// This file runs task exports and imports as async operations.
+
+// TaskExportPayload starts an OperationExportTasks operation.
+type TaskExportPayload struct {
+	Format domain.TaskExchangeFormat `json:"format"`
+}
+
+// TaskImportPayload starts an OperationImportTasks operation. Body is the
+// uploaded file.
+type TaskImportPayload struct {
+	Format domain.TaskExchangeFormat `json:"format"`
+	Body   []byte                    `json:"body"`
+}
+
+// Export returns the user's tasks as a file in format.
+func (s *TaskExchangeService) Export(ctx context.Context, userID uuid.UUID, format domain.TaskExchangeFormat) (*domain.Download, error) {
+	switch format {
+	case domain.TaskFormatTodoTxt:
+		out, err := s.ExportTodoTxt(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.Download{Name: "todo.txt", ContentType: "text/plain; charset=utf-8", Data: []byte(out)}, nil
+	case domain.TaskFormatTaskWarrior:
+		tasks, err := s.ExportTaskWarrior(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		out, err := json.Marshal(tasks)
+		if err != nil {
+			return nil, fmt.Errorf("taskExchangeService.Export: %w", err)
+		}
+		return &domain.Download{Name: "taskwarrior.json", ContentType: "application/json; charset=utf-8", Data: out}, nil
+	default:
+		return nil, fmt.Errorf("taskExchangeService.Export: unsupported format %q: %w", format, domain.ErrValidation)
+	}
+}
+
+// RunExport is the OperationRunner for OperationExportTasks.
+func (s *TaskExchangeService) RunExport(ctx context.Context, userID uuid.UUID, payload json.RawMessage, report func(int)) (*domain.OperationOutput, error) {
+	var p TaskExportPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("taskExchangeService.RunExport: %w", err)
+	}
+	file, err := s.Export(ctx, userID, p.Format)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OperationOutput{File: file}, nil
+}
+
+// RunImport is the OperationRunner for OperationImportTasks. A file that does
+// not parse fails the operation with a validation error.
+func (s *TaskExchangeService) RunImport(ctx context.Context, userID uuid.UUID, payload json.RawMessage, report func(int)) (*domain.OperationOutput, error) {
+	var p TaskImportPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, fmt.Errorf("taskExchangeService.RunImport: %w", err)
+	}
+	if !slices.Contains(domain.TaskImportFormats, p.Format) {
+		return nil, fmt.Errorf("taskExchangeService.RunImport: unsupported format %q: %w", p.Format, domain.ErrValidation)
+	}
+
+	var result *domain.TaskImportResult
+	var err error
+	switch p.Format {
+	case domain.TaskFormatTodoTxt:
+		result, err = s.ImportTodoTxt(ctx, userID, string(p.Body))
+	case domain.TaskFormatTaskWarrior:
+		tasks, parseErr := domain.ParseTaskWarrior(p.Body)
+		if parseErr != nil {
+			return nil, fmt.Errorf("taskExchangeService.RunImport: invalid TaskWarrior export: %s: %w", parseErr, domain.ErrValidation)
+		}
+		report(10)
+		result, err = s.ImportTaskWarrior(ctx, userID, tasks)
+	case domain.TaskFormatCSV:
+		rows, unmapped, parseErr := domain.ParseTaskCSV(bytes.NewReader(p.Body))
+		if parseErr != nil {
+			return nil, fmt.Errorf("taskExchangeService.RunImport: invalid CSV file: %s: %w", parseErr, domain.ErrValidation)
+		}
+		report(10)
+		result, err = s.ImportCSV(ctx, userID, rows, unmapped)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OperationOutput{Result: result}, nil
+}
@@ -0,0 +1,751 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxImportTasks caps the tasks in one exchange-format import.
+	maxImportTasks = 2000
+	// taskWarriorEstimate is the UDA carrying a task's estimate in hours.
+	taskWarriorEstimate = "estimate"
+)
+
+// todoTxtPriorities maps task priorities to todo.txt priority letters.
+var todoTxtPriorities = map[domain.TaskPriority]byte{
+	domain.TaskPriorityHigh:   'A',
+	domain.TaskPriorityMedium: 'B',
+	domain.TaskPriorityLow:    'C',
+}
+
+// taskWarriorPriorities maps task priorities to TaskWarrior's H, M and L.
+var taskWarriorPriorities = map[domain.TaskPriority]string{
+	domain.TaskPriorityHigh:   "H",
+	domain.TaskPriorityMedium: "M",
+	domain.TaskPriorityLow:    "L",
+}
+
+// TaskExchangeService converts a user's tasks to and from the file formats
+// of other task managers, for migrating in and out of the app.
+type TaskExchangeService struct {
+	taskSvc    *TaskService
+	projectSvc *ProjectService
+	tagSvc     *TagService
+	depSvc     *TaskDependencyService
+	log        *logrus.Logger
+}
+
+// NewTaskExchangeService constructs a TaskExchangeService.
+func NewTaskExchangeService(
+	taskSvc *TaskService,
+	projectSvc *ProjectService,
+	tagSvc *TagService,
+	depSvc *TaskDependencyService,
+	log *logrus.Logger,
+) *TaskExchangeService {
+	return &TaskExchangeService{taskSvc: taskSvc, projectSvc: projectSvc, tagSvc: tagSvc, depSvc: depSvc, log: log}
+}
+
+// ExportTodoTxt returns the user's tasks as a todo.txt file, one line per
+// task, open tasks first. Priorities become A (high) to C (low), the project
+// a +project, tags @contexts and the due date a due: tag; spaces in names
+// become underscores. Subtasks are exported as plain tasks.
+func (s *TaskExchangeService) ExportTodoTxt(ctx context.Context, userID uuid.UUID) (string, error) {
+	tasks, err := s.userTasks(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("taskExchangeService.ExportTodoTxt: %w", err)
+	}
+	projects, err := s.projectNames(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("taskExchangeService.ExportTodoTxt: %w", err)
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].Status != domain.TaskStatusDone && tasks[j].Status == domain.TaskStatusDone
+	})
+
+	var b strings.Builder
+	for _, t := range tasks {
+		created := t.CreatedAt
+		item := domain.TodoTxtItem{
+			Done:        t.Status == domain.TaskStatusDone,
+			Priority:    todoTxtPriorities[t.Priority],
+			CompletedOn: t.CompletedAt,
+			CreatedOn:   &created,
+			Text:        t.Title,
+			Tags:        map[string]string{},
+		}
+		if t.ProjectID != nil {
+			if name, ok := projects[*t.ProjectID]; ok {
+				item.Projects = []string{name}
+			}
+		}
+		tags, err := s.tagSvc.ListForTask(ctx, t.ID, userID)
+		if err != nil {
+			return "", fmt.Errorf("taskExchangeService.ExportTodoTxt: %w", err)
+		}
+		for _, tag := range tags {
+			item.Contexts = append(item.Contexts, tag.Name)
+		}
+		if t.DueDate != nil {
+			item.Tags["due"] = t.DueDate.Format("2006-01-02")
+		}
+		if t.Status == domain.TaskStatusInProgress {
+			item.Tags["status"] = string(domain.TaskStatusInProgress)
+		}
+		b.WriteString(item.String())
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// ImportTodoTxt creates a task for every non-blank line of a todo.txt file.
+// Priority A is high, B medium and C or lower low; lines without one are
+// medium. The first +project picks the project and @contexts the tags,
+// matched by name with underscores read as spaces and created when missing.
+// Every line is checked before anything is written.
+func (s *TaskExchangeService) ImportTodoTxt(ctx context.Context, userID uuid.UUID, data string) (*domain.TaskImportResult, error) {
+	var items []domain.TodoTxtItem
+	sc := bufio.NewScanner(strings.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		item, err := domain.ParseTodoTxtLine(line)
+		if err == nil {
+			err = checkTodoTxtItem(&item)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: line %d: %s: %w", n, err, domain.ErrValidation)
+		}
+		items = append(items, item)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %s: %w", err, domain.ErrValidation)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: file has no tasks: %w", domain.ErrValidation)
+	}
+	if len(items) > maxImportTasks {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: at most %d tasks can be imported at once: %w", maxImportTasks, domain.ErrValidation)
+	}
+
+	var projects, tags []string
+	for _, item := range items {
+		if len(item.Projects) > 0 {
+			projects = append(projects, item.Projects[0])
+		}
+		tags = append(tags, item.Contexts...)
+	}
+	result := &domain.TaskImportResult{}
+	projectIDs, err := s.resolveProjects(ctx, userID, projects, result)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %w", err)
+	}
+	tagIDs, err := s.resolveTags(ctx, userID, tags, result)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %w", err)
+	}
+
+	reqs := make([]*domain.CreateTaskRequest, len(items))
+	for i, item := range items {
+		reqs[i] = &domain.CreateTaskRequest{
+			Title:    item.Text,
+			Priority: todoTxtPriority(item.Priority),
+			DueDate:  item.Due(),
+		}
+		if len(item.Projects) > 0 {
+			id := projectIDs[exchangeKey(item.Projects[0])]
+			reqs[i].ProjectID = &id
+		}
+	}
+	tasks, err := s.taskSvc.CreateBatch(ctx, userID, reqs)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %w", err)
+	}
+
+	for i, item := range items {
+		task := tasks[i]
+		if len(item.Contexts) > 0 {
+			ids := make([]uuid.UUID, 0, len(item.Contexts))
+			for _, c := range item.Contexts {
+				ids = append(ids, tagIDs[exchangeKey(c)])
+			}
+			if _, err := s.tagSvc.SetForTask(ctx, task.ID, userID, ids); err != nil {
+				return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %w", err)
+			}
+		}
+		status := domain.TaskStatus(item.Tags["status"])
+		if item.Done {
+			status = domain.TaskStatusDone
+		}
+		if status == domain.TaskStatusDone || status == domain.TaskStatusInProgress {
+			if _, err := s.taskSvc.Update(ctx, task.ID, userID, &domain.UpdateTaskRequest{Status: &status}); err != nil {
+				return nil, fmt.Errorf("taskExchangeService.ImportTodoTxt: %w", err)
+			}
+		}
+		result.Imported++
+	}
+
+	s.log.WithFields(logrus.Fields{"user_id": userID, "format": domain.TaskFormatTodoTxt, "tasks": result.Imported}).Info("tasks imported")
+	return result, nil
+}
+
+// checkTodoTxtItem rejects lines that cannot become a valid task.
+func checkTodoTxtItem(item *domain.TodoTxtItem) error {
+	if item.Text == "" {
+		return fmt.Errorf("task has no description")
+	}
+	if n := len([]rune(item.Text)); n > 255 {
+		return fmt.Errorf("description is %d characters, at most 255 are allowed", n)
+	}
+	if len(item.Projects) > 0 && len(exchangeName(item.Projects[0])) > 100 {
+		return fmt.Errorf("project +%s is longer than 100 characters", item.Projects[0])
+	}
+	for _, c := range item.Contexts {
+		if len(exchangeName(c)) > 50 {
+			return fmt.Errorf("context @%s is longer than 50 characters", c)
+		}
+	}
+	return nil
+}
+
+func todoTxtPriority(p byte) domain.TaskPriority {
+	switch {
+	case p == 'A':
+		return domain.TaskPriorityHigh
+	case p == 0 || p == 'B':
+		return domain.TaskPriorityMedium
+	default:
+		return domain.TaskPriorityLow
+	}
+}
+
+// ExportTaskWarrior returns the user's tasks in TaskWarrior's JSON export
+// format, ready for `task import`. The description becomes an annotation,
+// tags have spaces turned into underscores, dependencies between exported
+// tasks become depends and the estimate an estimate UDA. TaskWarrior has no
+// separate in-progress status, so those tasks are pending with start set to
+// their last update.
+func (s *TaskExchangeService) ExportTaskWarrior(ctx context.Context, userID uuid.UUID) ([]domain.TaskWarriorTask, error) {
+	tasks, err := s.userTasks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ExportTaskWarrior: %w", err)
+	}
+	projects, err := s.projectNames(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ExportTaskWarrior: %w", err)
+	}
+	exported := make(map[uuid.UUID]bool, len(tasks))
+	for _, t := range tasks {
+		exported[t.ID] = true
+	}
+
+	out := make([]domain.TaskWarriorTask, 0, len(tasks))
+	for _, t := range tasks {
+		tw, err := s.taskWarriorTask(ctx, userID, t, projects, func(b *domain.Task) bool { return exported[b.ID] })
+		if err != nil {
+			return nil, fmt.Errorf("taskExchangeService.ExportTaskWarrior: %w", err)
+		}
+		out = append(out, tw)
+	}
+	return out, nil
+}
+
+// StreamTaskWarrior is ExportTaskWarrior calling fn with one task at a time,
+// oldest first, instead of loading them all.
+func (s *TaskExchangeService) StreamTaskWarrior(ctx context.Context, userID uuid.UUID, fn func(domain.TaskWarriorTask) error) error {
+	projects, err := s.projectNames(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("taskExchangeService.StreamTaskWarrior: %w", err)
+	}
+	// Every live, unarchived task is exported, so those are the blockers
+	// the exported tasks can depend on.
+	exported := func(b *domain.Task) bool { return b.DeletedAt == nil && b.ArchivedAt == nil }
+	filter := domain.TaskFilter{IncludeDeferred: true, Sort: domain.TaskSortCreated}
+	return s.taskSvc.Stream(ctx, userID, filter, func(t *domain.Task) error {
+		tw, err := s.taskWarriorTask(ctx, userID, t, projects, exported)
+		if err != nil {
+			return fmt.Errorf("taskExchangeService.StreamTaskWarrior: %w", err)
+		}
+		return fn(tw)
+	})
+}
+
+// taskWarriorTask converts one task for a TaskWarrior export, keeping the
+// dependencies on blockers that are exported too.
+func (s *TaskExchangeService) taskWarriorTask(ctx context.Context, userID uuid.UUID, t *domain.Task, projects map[uuid.UUID]string, exported func(*domain.Task) bool) (domain.TaskWarriorTask, error) {
+	tw := domain.TaskWarriorTask{
+		UUID:        t.ID.String(),
+		Description: t.Title,
+		Status:      domain.TaskWarriorPending,
+		Entry:       domain.NewTaskWarriorTime(&t.CreatedAt),
+		Modified:    domain.NewTaskWarriorTime(&t.UpdatedAt),
+		Due:         domain.NewTaskWarriorTime(t.DueDate),
+		Priority:    taskWarriorPriorities[t.Priority],
+	}
+	switch t.Status {
+	case domain.TaskStatusDone:
+		tw.Status = domain.TaskWarriorCompleted
+		tw.End = tw.Modified
+		if t.CompletedAt != nil {
+			tw.End = domain.NewTaskWarriorTime(t.CompletedAt)
+		}
+	case domain.TaskStatusInProgress:
+		tw.Start = tw.Modified
+	}
+	if t.ProjectID != nil {
+		tw.Project = projects[*t.ProjectID]
+	}
+	if t.Description != "" {
+		tw.Annotations = []domain.TaskWarriorAnnotation{{Entry: tw.Entry, Description: t.Description}}
+	}
+	if t.EstimatedHours != nil {
+		tw.UDA = map[string]any{taskWarriorEstimate: *t.EstimatedHours}
+	}
+
+	tags, err := s.tagSvc.ListForTask(ctx, t.ID, userID)
+	if err != nil {
+		return domain.TaskWarriorTask{}, err
+	}
+	for _, tag := range tags {
+		tw.Tags = append(tw.Tags, domain.TodoTxtToken(tag.Name))
+	}
+	deps, err := s.depSvc.List(ctx, t.ID, userID)
+	if err != nil {
+		return domain.TaskWarriorTask{}, err
+	}
+	for _, b := range deps.BlockedBy {
+		if exported(b) {
+			tw.Depends = append(tw.Depends, b.ID.String())
+		}
+	}
+	return tw, nil
+}
+
+// ImportTaskWarrior creates a task for every pending, waiting or completed
+// task of a TaskWarrior export. Deleted tasks and recurrence templates are
+// skipped. Annotations are joined into the description, the estimate UDA
+// sets the estimate and depends between imported tasks become dependencies.
+// Other attributes, such as wait, scheduled and remaining UDAs, are listed in
+// the result as unmapped. Every task is checked before anything is written.
+func (s *TaskExchangeService) ImportTaskWarrior(ctx context.Context, userID uuid.UUID, tasks []domain.TaskWarriorTask) (*domain.TaskImportResult, error) {
+	result := &domain.TaskImportResult{}
+	unmapped := map[string]bool{}
+	var items []domain.TaskWarriorTask
+	for n, t := range tasks {
+		if t.Status == domain.TaskWarriorDeleted || t.Status == domain.TaskWarriorRecurring {
+			result.Skipped++
+			continue
+		}
+		if err := checkTaskWarriorTask(&t); err != nil {
+			return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: task %d: %s: %w", n+1, err, domain.ErrValidation)
+		}
+		for _, name := range taskWarriorUnmapped(&t) {
+			unmapped[name] = true
+		}
+		items = append(items, t)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: file has no tasks to import: %w", domain.ErrValidation)
+	}
+	if len(items) > maxImportTasks {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: at most %d tasks can be imported at once: %w", maxImportTasks, domain.ErrValidation)
+	}
+
+	// depends may name tasks left out of the file or skipped; only those
+	// between imported tasks are kept.
+	index := map[string]int{}
+	for i, t := range items {
+		if t.UUID == "" {
+			continue
+		}
+		key := strings.ToLower(t.UUID)
+		if _, dup := index[key]; dup {
+			return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: uuid %s appears more than once: %w", t.UUID, domain.ErrValidation)
+		}
+		index[key] = i
+	}
+	edges := map[string][]string{}
+	for _, t := range items {
+		for _, d := range t.Depends {
+			if _, ok := index[strings.ToLower(d)]; ok && t.UUID != "" {
+				edges[strings.ToLower(t.UUID)] = append(edges[strings.ToLower(t.UUID)], strings.ToLower(d))
+			}
+		}
+	}
+	if hasCycle(edges) {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: depends forms a cycle: %w", domain.ErrValidation)
+	}
+
+	var projects, tags []string
+	for _, t := range items {
+		if t.Project != "" {
+			projects = append(projects, t.Project)
+		}
+		tags = append(tags, t.Tags...)
+	}
+	projectIDs, err := s.resolveProjects(ctx, userID, projects, result)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+	}
+	tagIDs, err := s.resolveTags(ctx, userID, tags, result)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+	}
+
+	reqs := make([]*domain.CreateTaskRequest, len(items))
+	for i, t := range items {
+		reqs[i] = &domain.CreateTaskRequest{
+			Title:          t.Description,
+			Description:    taskWarriorNotes(&t),
+			Priority:       taskWarriorPriority(t.Priority),
+			EstimatedHours: taskWarriorEstimateHours(&t),
+		}
+		if t.Due != nil {
+			due := t.Due.UTC()
+			reqs[i].DueDate = &due
+		}
+		if t.Project != "" {
+			id := projectIDs[exchangeKey(t.Project)]
+			reqs[i].ProjectID = &id
+		}
+	}
+	stored, err := s.taskSvc.CreateBatch(ctx, userID, reqs)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+	}
+
+	created := make([]uuid.UUID, len(items))
+	for i, t := range items {
+		task := stored[i]
+		created[i] = task.ID
+		if len(t.Tags) > 0 {
+			ids := make([]uuid.UUID, 0, len(t.Tags))
+			for _, name := range t.Tags {
+				ids = append(ids, tagIDs[exchangeKey(name)])
+			}
+			if _, err := s.tagSvc.SetForTask(ctx, task.ID, userID, ids); err != nil {
+				return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+			}
+		}
+		// Statuses go in before dependencies so finished tasks are not
+		// refused as blocked.
+		var status domain.TaskStatus
+		switch {
+		case t.Status == domain.TaskWarriorCompleted:
+			status = domain.TaskStatusDone
+		case t.Start != nil:
+			status = domain.TaskStatusInProgress
+		}
+		if status != "" {
+			if _, err := s.taskSvc.Update(ctx, task.ID, userID, &domain.UpdateTaskRequest{Status: &status}); err != nil {
+				return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+			}
+		}
+		result.Imported++
+	}
+	for key, blockers := range edges {
+		for _, b := range blockers {
+			if _, err := s.depSvc.Add(ctx, created[index[key]], userID, created[index[b]]); err != nil {
+				return nil, fmt.Errorf("taskExchangeService.ImportTaskWarrior: %w", err)
+			}
+		}
+	}
+
+	for name := range unmapped {
+		result.UnmappedFields = append(result.UnmappedFields, name)
+	}
+	sort.Strings(result.UnmappedFields)
+	s.log.WithFields(logrus.Fields{"user_id": userID, "format": domain.TaskFormatTaskWarrior, "tasks": result.Imported}).Info("tasks imported")
+	return result, nil
+}
+
+// checkTaskWarriorTask rejects tasks that cannot become a valid task.
+func checkTaskWarriorTask(t *domain.TaskWarriorTask) error {
+	switch t.Status {
+	case domain.TaskWarriorPending, domain.TaskWarriorWaiting, domain.TaskWarriorCompleted:
+	default:
+		return fmt.Errorf("unknown status %q", t.Status)
+	}
+	if strings.TrimSpace(t.Description) == "" {
+		return fmt.Errorf("task has no description")
+	}
+	if n := len([]rune(t.Description)); n > 255 {
+		return fmt.Errorf("description is %d characters, at most 255 are allowed", n)
+	}
+	switch t.Priority {
+	case "", "H", "M", "L":
+	default:
+		return fmt.Errorf("priority %q is not H, M or L", t.Priority)
+	}
+	if n := len([]rune(taskWarriorNotes(t))); n > 5000 {
+		return fmt.Errorf("annotations total %d characters, at most 5000 are allowed", n)
+	}
+	if len(exchangeName(t.Project)) > 100 {
+		return fmt.Errorf("project %s is longer than 100 characters", t.Project)
+	}
+	for _, tag := range t.Tags {
+		if tag == "" || len(exchangeName(tag)) > 50 {
+			return fmt.Errorf("tag %q must be 1 to 50 characters", tag)
+		}
+	}
+	if v, ok := t.UDA[taskWarriorEstimate]; ok {
+		if h := taskWarriorEstimateHours(t); h == nil || *h < 0 || *h > 999 {
+			return fmt.Errorf("estimate %v is not a number of hours from 0 to 999", v)
+		}
+	}
+	return nil
+}
+
+// taskWarriorUnmapped names the attributes of t that have no equivalent.
+func taskWarriorUnmapped(t *domain.TaskWarriorTask) []string {
+	var names []string
+	for name, set := range map[string]bool{
+		"wait": t.Wait != nil, "scheduled": t.Scheduled != nil, "until": t.Until != nil, "recur": t.Recur != "",
+	} {
+		if set {
+			names = append(names, name)
+		}
+	}
+	for name := range t.UDA {
+		if name != taskWarriorEstimate {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// taskWarriorNotes joins the annotations of t, oldest first as TaskWarrior
+// lists them.
+func taskWarriorNotes(t *domain.TaskWarriorTask) string {
+	notes := make([]string, 0, len(t.Annotations))
+	for _, a := range t.Annotations {
+		if d := strings.TrimSpace(a.Description); d != "" {
+			notes = append(notes, d)
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+// taskWarriorEstimateHours reads the estimate UDA, a number or numeric
+// string.
+func taskWarriorEstimateHours(t *domain.TaskWarriorTask) *float64 {
+	var h float64
+	switch v := t.UDA[taskWarriorEstimate].(type) {
+	case float64:
+		h = v
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil
+		}
+		h = parsed
+	default:
+		return nil
+	}
+	return &h
+}
+
+func taskWarriorPriority(p string) domain.TaskPriority {
+	switch p {
+	case "H":
+		return domain.TaskPriorityHigh
+	case "L":
+		return domain.TaskPriorityLow
+	default:
+		return domain.TaskPriorityMedium
+	}
+}
+
+// ImportCSV creates a task for every row of a CSV file read by
+// domain.ParseTaskCSV. A blank priority is medium and projects are matched
+// by name or created. Every row is checked first and all rejected rows are
+// reported together as domain.TaskImportErrors; the tasks are then inserted
+// in one transaction.
+func (s *TaskExchangeService) ImportCSV(ctx context.Context, userID uuid.UUID, rows []domain.TaskCSVRow, unmapped []string) (*domain.TaskImportResult, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("taskExchangeService.ImportCSV: file has no tasks: %w", domain.ErrValidation)
+	}
+	if len(rows) > maxImportTasks {
+		return nil, fmt.Errorf("taskExchangeService.ImportCSV: at most %d tasks can be imported at once: %w", maxImportTasks, domain.ErrValidation)
+	}
+
+	loc := s.taskSvc.location(ctx, userID)
+	reqs := make([]*domain.CreateTaskRequest, len(rows))
+	var rejected domain.TaskImportErrors
+	var projects []string
+	for i, row := range rows {
+		req, errs := csvTaskRequest(row, loc)
+		rejected = append(rejected, errs...)
+		reqs[i] = req
+		if row.Project != "" {
+			projects = append(projects, row.Project)
+		}
+	}
+	if len(rejected) > 0 {
+		return nil, fmt.Errorf("taskExchangeService.ImportCSV: %w", rejected)
+	}
+
+	result := &domain.TaskImportResult{UnmappedFields: unmapped}
+	projectIDs, err := s.resolveProjects(ctx, userID, projects, result)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportCSV: %w", err)
+	}
+	for i, row := range rows {
+		if row.Project != "" {
+			id := projectIDs[exchangeKey(row.Project)]
+			reqs[i].ProjectID = &id
+		}
+	}
+	tasks, err := s.taskSvc.CreateBatch(ctx, userID, reqs)
+	if err != nil {
+		return nil, fmt.Errorf("taskExchangeService.ImportCSV: %w", err)
+	}
+	result.Imported = len(tasks)
+
+	s.log.WithFields(logrus.Fields{"user_id": userID, "format": domain.TaskFormatCSV, "tasks": result.Imported}).Info("tasks imported")
+	return result, nil
+}
+
+// csvTaskRequest turns a CSV row into a create request, reporting every
+// problem with it.
+func csvTaskRequest(row domain.TaskCSVRow, loc *time.Location) (*domain.CreateTaskRequest, []domain.TaskImportRowError) {
+	var errs []domain.TaskImportRowError
+	reject := func(field, msg string, args ...any) {
+		errs = append(errs, domain.TaskImportRowError{Row: row.Line, Field: field, Message: fmt.Sprintf(msg, args...)})
+	}
+
+	req := &domain.CreateTaskRequest{Title: row.Title, Description: row.Description, Priority: domain.TaskPriorityMedium}
+	if row.Title == "" {
+		reject("title", "title is required")
+	} else if n := len([]rune(row.Title)); n > 255 {
+		reject("title", "title is %d characters, at most 255 are allowed", n)
+	}
+	if n := len([]rune(row.Description)); n > 5000 {
+		reject("description", "description is %d characters, at most 5000 are allowed", n)
+	}
+	if row.Priority != "" {
+		p, err := domain.ParseTaskPriority(row.Priority)
+		if err != nil {
+			reject("priority", "priority %q is not low, medium or high", row.Priority)
+		}
+		req.Priority = p
+	}
+	due, err := row.Due(loc)
+	if err != nil {
+		reject("due_date", "%s", err)
+	}
+	req.DueDate = due
+	if len(exchangeName(row.Project)) > 100 {
+		reject("project", "project is longer than 100 characters")
+	}
+	return req, errs
+}
+
+// resolveProjects maps project names to ids, creating the projects the
+// user does not have yet.
+func (s *TaskExchangeService) resolveProjects(ctx context.Context, userID uuid.UUID, names []string, result *domain.TaskImportResult) (map[string]uuid.UUID, error) {
+	ids := map[string]uuid.UUID{}
+	existing, err := s.projectSvc.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range existing {
+		ids[exchangeKey(p.Name)] = p.ID
+	}
+	for _, name := range names {
+		key := exchangeKey(name)
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		p, err := s.projectSvc.Create(ctx, userID, &domain.CreateProjectRequest{
+			Name: exchangeName(name),
+			Type: domain.ProjectTypePersonal,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("project %q: %w", name, err)
+		}
+		ids[key] = p.ID
+		result.ProjectsCreated++
+	}
+	return ids, nil
+}
+
+// resolveTags maps tag names to ids, creating the tags the user does not
+// have yet.
+func (s *TaskExchangeService) resolveTags(ctx context.Context, userID uuid.UUID, names []string, result *domain.TaskImportResult) (map[string]uuid.UUID, error) {
+	ids := map[string]uuid.UUID{}
+	existing, err := s.tagSvc.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range existing {
+		ids[exchangeKey(t.Name)] = t.ID
+	}
+	for _, name := range names {
+		key := exchangeKey(name)
+		if _, ok := ids[key]; ok {
+			continue
+		}
+		tag, err := s.tagSvc.Create(ctx, userID, &domain.CreateTagRequest{Name: exchangeName(name)})
+		if err != nil {
+			return nil, fmt.Errorf("tag %q: %w", name, err)
+		}
+		ids[key] = tag.ID
+		result.TagsCreated++
+	}
+	return ids, nil
+}
+
+// userTasks loads every task of the user, oldest first.
+func (s *TaskExchangeService) userTasks(ctx context.Context, userID uuid.UUID) ([]*domain.Task, error) {
+	var tasks []*domain.Task
+	for page := 1; ; page++ {
+		batch, total, err := s.taskSvc.List(ctx, userID, domain.TaskFilter{IncludeDeferred: true}, page, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, batch...)
+		if len(batch) < exportPageSize || len(tasks) >= total {
+			break
+		}
+	}
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+	return tasks, nil
+}
+
+func (s *TaskExchangeService) projectNames(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]string, error) {
+	projects, err := s.projectSvc.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(projects))
+	for _, p := range projects {
+		names[p.ID] = p.Name
+	}
+	return names, nil
+}
+
+// exchangeName reads a project or context token back as a name.
+func exchangeName(token string) string {
+	return strings.ReplaceAll(token, "_", " ")
+}
+
+// exchangeKey matches a token or a name case-insensitively, treating spaces
+// and underscores alike.
+func exchangeKey(name string) string {
+	return strings.ToLower(domain.TodoTxtToken(exchangeName(name)))
+}
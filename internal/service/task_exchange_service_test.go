@@ -0,0 +1,299 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTaskExchangeService(taskRepo *mockTaskRepo, projectRepo *mockProjectRepo, tags *fakeTagRepo, deps *fakeDependencyRepo) *service.TaskExchangeService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	taskSvc := newTaskService(taskRepo, projectRepo)
+	return service.NewTaskExchangeService(
+		taskSvc,
+		service.NewProjectService(projectRepo, log),
+		service.NewTagService(tags, taskSvc, log),
+		service.NewTaskDependencyService(deps, taskSvc, log),
+		log,
+	)
+}
+
+func TestTaskExchangeService_ExportTodoTxt(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	completed := created.AddDate(0, 0, 2)
+	due := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	open := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Renew passport", Status: domain.TaskStatusInProgress, Priority: domain.TaskPriorityHigh, DueDate: &due, CreatedAt: created}
+	done := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Buy milk", Status: domain.TaskStatusDone, Priority: domain.TaskPriorityLow, CompletedAt: &completed, CreatedAt: created.Add(-time.Hour)}
+
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{{ID: projectID, UserID: userID, Name: "Travel plans"}}, nil)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{open, done}, 2, nil)
+	for _, task := range []*domain.Task{open, done} {
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	errand := &domain.Tag{ID: uuid.New(), UserID: userID, Name: "errand"}
+	tags := &fakeTagRepo{tags: []*domain.Tag{errand}, onTask: map[uuid.UUID][]uuid.UUID{done.ID: {errand.ID}}}
+	svc := newTaskExchangeService(taskRepo, projectRepo, tags, &fakeDependencyRepo{})
+
+	out, err := svc.ExportTodoTxt(context.Background(), userID)
+	require.NoError(t, err)
+
+	assert.Equal(t,
+		"(A) 2026-03-01 Renew passport +Travel_plans due:2026-03-10 status:in_progress\n"+
+			"x 2026-03-03 2026-03-01 Buy milk @errand pri:C\n",
+		out, "open tasks come first")
+}
+
+func TestTaskExchangeService_ImportTodoTxt(t *testing.T) {
+	userID := uuid.New()
+	existing := &domain.Project{ID: uuid.New(), UserID: userID, Name: "Home admin"}
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{existing}, nil)
+	projectRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Project{UserID: userID}, nil)
+
+	var created []*domain.Task
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).([]*domain.Task)...)
+	}).Return(nil)
+	taskRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Task{UserID: userID}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	tags := &fakeTagRepo{tags: []*domain.Tag{{ID: uuid.New(), UserID: userID, Name: "Phone"}}}
+	svc := newTaskExchangeService(taskRepo, projectRepo, tags, &fakeDependencyRepo{})
+
+	result, err := svc.ImportTodoTxt(context.Background(), userID, "(A) File taxes +home_admin @desk due:2026-04-15\n\nx 2026-03-02 Call bank +Money @phone\nWater plants\n")
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.TaskImportResult{Imported: 3, ProjectsCreated: 1, TagsCreated: 1}, result)
+	require.Len(t, created, 3)
+	assert.Equal(t, domain.TaskPriorityHigh, created[0].Priority)
+	assert.Equal(t, &existing.ID, created[0].ProjectID, "project names match ignoring case and underscores")
+	assert.Equal(t, "2026-04-15", created[0].DueDate.Format("2006-01-02"))
+	assert.NotNil(t, created[1].ProjectID)
+	assert.Equal(t, domain.TaskPriorityMedium, created[2].Priority)
+	assert.Nil(t, created[2].ProjectID)
+	assert.Len(t, tags.onTask[created[0].ID], 1)
+	taskRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestTaskExchangeService_ImportTodoTxtRejectsBadLines(t *testing.T) {
+	for name, data := range map[string]string{
+		"bad due date": "Pay rent\nPay bills due:friday\n",
+		"no text":      "(B) +Home @desk\n",
+		"empty":        "\n\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Nothing may be written, so the repositories have no expectations.
+			svc := newTaskExchangeService(&mockTaskRepo{}, &mockProjectRepo{}, &fakeTagRepo{}, &fakeDependencyRepo{})
+			_, err := svc.ImportTodoTxt(context.Background(), uuid.New(), data)
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		})
+	}
+}
+
+func TestTaskExchangeService_ImportCSV(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{}, nil)
+	projectRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Project{UserID: userID}, nil)
+
+	var created []*domain.Task
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]*domain.Task)
+	}).Return(nil)
+	svc := newTaskExchangeService(taskRepo, projectRepo, &fakeTagRepo{}, &fakeDependencyRepo{})
+
+	rows, unmapped, err := domain.ParseTaskCSV(strings.NewReader("Title,Priority,Due_Date,Project,Owner\n" +
+		"File taxes,high,2026-04-15,Home admin,me\n" +
+		"\n" +
+		"\"Call bank, about the card\",,,home admin,\n"))
+	require.NoError(t, err)
+	result, err := svc.ImportCSV(context.Background(), userID, rows, unmapped)
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.TaskImportResult{Imported: 2, ProjectsCreated: 1, UnmappedFields: []string{"owner"}}, result)
+	require.Len(t, created, 2)
+	assert.Equal(t, domain.TaskPriorityHigh, created[0].Priority)
+	assert.Equal(t, time.Date(2026, 4, 15, 0, 0, 0, 0, time.UTC), *created[0].DueDate)
+	assert.Equal(t, "Call bank, about the card", created[1].Title)
+	assert.Equal(t, domain.TaskPriorityMedium, created[1].Priority)
+	assert.Equal(t, created[0].ProjectID, created[1].ProjectID)
+	taskRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTaskExchangeService_ImportCSVReportsEveryBadRow(t *testing.T) {
+	rows, _, err := domain.ParseTaskCSV(strings.NewReader("title,priority,due_date\n" +
+		"Pay rent,,2026-05-01\n" +
+		",urgent-ish,\n" +
+		"Pay bills,low,friday\n"))
+	require.NoError(t, err)
+
+	// Nothing may be written, so the repositories have no expectations.
+	svc := newTaskExchangeService(&mockTaskRepo{}, &mockProjectRepo{}, &fakeTagRepo{}, &fakeDependencyRepo{})
+	_, err = svc.ImportCSV(context.Background(), uuid.New(), rows, nil)
+	assert.ErrorIs(t, err, domain.ErrValidation)
+
+	var rejected domain.TaskImportErrors
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, []int{3, 3, 4}, []int{rejected[0].Row, rejected[1].Row, rejected[2].Row})
+	assert.Equal(t, []string{"title", "priority", "due_date"}, []string{rejected[0].Field, rejected[1].Field, rejected[2].Field})
+}
+
+func TestTaskExchangeService_ExportTaskWarrior(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	created := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	estimate := 1.5
+	rent := &domain.Task{ID: uuid.New(), UserID: userID, ProjectID: &projectID, Title: "Pay rent", Description: "Transfer before the 5th", Status: domain.TaskStatusInProgress, Priority: domain.TaskPriorityHigh, EstimatedHours: &estimate, CreatedAt: created, UpdatedAt: created.Add(time.Hour)}
+	bank := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Call bank", Status: domain.TaskStatusDone, Priority: domain.TaskPriorityMedium, CreatedAt: created.Add(-time.Hour), UpdatedAt: created.Add(2 * time.Hour)}
+
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{{ID: projectID, UserID: userID, Name: "Home admin"}}, nil)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("List", mock.Anything, userID, domain.TaskFilter{IncludeDeferred: true}, 1, mock.Anything).Return([]*domain.Task{rent, bank}, 2, nil)
+	for _, task := range []*domain.Task{rent, bank} {
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	money := &domain.Tag{ID: uuid.New(), UserID: userID, Name: "money matters"}
+	tags := &fakeTagRepo{tags: []*domain.Tag{money}, onTask: map[uuid.UUID][]uuid.UUID{rent.ID: {money.ID}}}
+	deps := &fakeDependencyRepo{blockers: map[uuid.UUID][]uuid.UUID{rent.ID: {bank.ID, uuid.New()}}}
+	svc := newTaskExchangeService(taskRepo, projectRepo, tags, deps)
+
+	out, err := svc.ExportTaskWarrior(context.Background(), userID)
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+
+	assert.Equal(t, bank.ID.String(), out[0].UUID, "oldest first")
+	assert.Equal(t, domain.TaskWarriorCompleted, out[0].Status)
+	assert.Equal(t, bank.UpdatedAt, out[0].End.Time, "completion time falls back to the last update")
+
+	tw := out[1]
+	assert.Equal(t, domain.TaskWarriorPending, tw.Status)
+	assert.Equal(t, rent.UpdatedAt, tw.Start.Time)
+	assert.Equal(t, "H", tw.Priority)
+	assert.Equal(t, "Home admin", tw.Project)
+	assert.Equal(t, []string{"money_matters"}, tw.Tags)
+	assert.Equal(t, domain.TaskWarriorUUIDs{bank.ID.String()}, tw.Depends, "blockers outside the export are left out")
+	require.Len(t, tw.Annotations, 1)
+	assert.Equal(t, "Transfer before the 5th", tw.Annotations[0].Description)
+	assert.Equal(t, 1.5, tw.UDA["estimate"])
+}
+
+func TestTaskExchangeService_StreamTaskWarrior(t *testing.T) {
+	userID := uuid.New()
+	bank := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Call bank", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow}
+	rent := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Pay rent", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityHigh}
+
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{}, nil)
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("Stream", mock.Anything, userID, domain.TaskFilter{IncludeDeferred: true, Sort: domain.TaskSortCreated}).Return([]*domain.Task{bank, rent}, nil)
+	for _, task := range []*domain.Task{rent, bank} {
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	}
+	deps := &fakeDependencyRepo{blockers: map[uuid.UUID][]uuid.UUID{rent.ID: {bank.ID}}}
+	svc := newTaskExchangeService(taskRepo, projectRepo, &fakeTagRepo{}, deps)
+
+	var out []domain.TaskWarriorTask
+	err := svc.StreamTaskWarrior(context.Background(), userID, func(tw domain.TaskWarriorTask) error {
+		out = append(out, tw)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	assert.Equal(t, bank.ID.String(), out[0].UUID)
+	assert.Equal(t, "H", out[1].Priority)
+	assert.Equal(t, domain.TaskWarriorUUIDs{bank.ID.String()}, out[1].Depends)
+
+	stop := errors.New("client went away")
+	calls := 0
+	err = svc.StreamTaskWarrior(context.Background(), userID, func(domain.TaskWarriorTask) error {
+		calls++
+		return stop
+	})
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, calls, "an error from fn ends the stream")
+}
+
+func TestTaskExchangeService_ImportTaskWarrior(t *testing.T) {
+	userID := uuid.New()
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("ListByUserID", mock.Anything, userID).Return([]*domain.Project{}, nil)
+	projectRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	projectRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Project{UserID: userID}, nil)
+
+	var created []*domain.Task
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = append(created, args.Get(1).([]*domain.Task)...)
+	}).Return(nil)
+	taskRepo.On("FindByID", mock.Anything, mock.Anything).Return(&domain.Task{UserID: userID}, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	deps := &fakeDependencyRepo{}
+	svc := newTaskExchangeService(taskRepo, projectRepo, &fakeTagRepo{}, deps)
+
+	tasks, err := domain.ParseTaskWarrior([]byte(`[
+		{"uuid":"a1","description":"Pay rent","status":"pending","priority":"H","project":"Home.Admin","tags":["money"],
+		 "due":"20260305T000000Z","start":"20260301T100000Z","estimate":"1.5","client":"ACME","depends":"b2,zz",
+		 "annotations":[{"entry":"20260301T090000Z","description":"Transfer before the 5th"},{"description":"IBAN in notes"}]},
+		{"uuid":"b2","description":"Call bank","status":"completed","end":"20260302T090000Z","wait":"20260303T000000Z"},
+		{"uuid":"c3","description":"Old chore","status":"deleted"},
+		{"uuid":"d4","description":"Weekly review","status":"recurring","recur":"weekly"}
+	]`))
+	require.NoError(t, err)
+
+	result, err := svc.ImportTaskWarrior(context.Background(), userID, tasks)
+	require.NoError(t, err)
+
+	assert.Equal(t, &domain.TaskImportResult{Imported: 2, ProjectsCreated: 1, TagsCreated: 1, Skipped: 2, UnmappedFields: []string{"client", "wait"}}, result)
+	require.Len(t, created, 2)
+	rent := created[0]
+	assert.Equal(t, domain.TaskPriorityHigh, rent.Priority)
+	assert.Equal(t, "Transfer before the 5th\nIBAN in notes", rent.Description)
+	require.NotNil(t, rent.EstimatedHours)
+	assert.Equal(t, 1.5, *rent.EstimatedHours)
+	assert.Equal(t, "2026-03-05", rent.DueDate.Format("2006-01-02"))
+	assert.NotNil(t, rent.ProjectID)
+	assert.Equal(t, domain.TaskPriorityMedium, created[1].Priority)
+	assert.Equal(t, map[uuid.UUID][]uuid.UUID{rent.ID: {created[1].ID}}, deps.blockers, "depends on tasks outside the file are dropped")
+	taskRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestTaskExchangeService_ImportTaskWarriorRejectsBadTasks(t *testing.T) {
+	for name, tasks := range map[string][]domain.TaskWarriorTask{
+		"no description": {{UUID: "a", Status: domain.TaskWarriorPending}},
+		"bad priority":   {{UUID: "a", Description: "x", Status: domain.TaskWarriorPending, Priority: "U"}},
+		"bad status":     {{UUID: "a", Description: "x", Status: "active"}},
+		"bad estimate":   {{UUID: "a", Description: "x", Status: domain.TaskWarriorPending, UDA: map[string]any{"estimate": "soon"}}},
+		"duplicate uuid": {{UUID: "a", Description: "x", Status: domain.TaskWarriorPending}, {UUID: "A", Description: "y", Status: domain.TaskWarriorPending}},
+		"cycle": {
+			{UUID: "a", Description: "x", Status: domain.TaskWarriorPending, Depends: domain.TaskWarriorUUIDs{"b"}},
+			{UUID: "b", Description: "y", Status: domain.TaskWarriorPending, Depends: domain.TaskWarriorUUIDs{"a"}},
+		},
+		"only deleted": {{UUID: "a", Description: "x", Status: domain.TaskWarriorDeleted}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// Nothing may be written, so the repositories have no expectations.
+			svc := newTaskExchangeService(&mockTaskRepo{}, &mockProjectRepo{}, &fakeTagRepo{}, &fakeDependencyRepo{})
+			_, err := svc.ImportTaskWarrior(context.Background(), uuid.New(), tasks)
+			assert.ErrorIs(t, err, domain.ErrValidation)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Hint tuning. Matches in the task's own project count extra, because the
+// same title often means different work in different projects.
+const (
+	hintThreshold     = 0.4
+	hintCandidates    = 20
+	hintMinMatches    = 2
+	hintProjectWeight = 1.5
+	hintTimeout       = 300 * time.Millisecond
+)
+
+// CreateWithHints is Create that also suggests a priority and estimate from
+// the user's similar tasks. Failing to compute hints never fails the create.
+func (s *TaskService) CreateWithHints(ctx context.Context, userID uuid.UUID, req *domain.CreateTaskRequest) (*domain.TaskWithHints, error) {
+	task, err := s.Create(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	hintCtx, cancel := context.WithTimeout(ctx, hintTimeout)
+	defer cancel()
+	hints, err := s.SuggestHints(hintCtx, task)
+	if err != nil {
+		s.log.WithError(err).WithField("task_id", task.ID).Warn("task hints unavailable")
+	}
+	return &domain.TaskWithHints{Task: task, Hints: hints}, nil
+}
+
+// SuggestHints derives hints for task from the user's other tasks with
+// similar titles: the similarity-weighted most common priority and the
+// weighted mean estimate. It returns nil when fewer than two tasks match.
+func (s *TaskService) SuggestHints(ctx context.Context, task *domain.Task) (*domain.TaskHints, error) {
+	matches, err := s.taskRepo.FuzzyFind(ctx, task.UserID, task.Title, hintThreshold, hintCandidates+1)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityWeight := map[domain.TaskPriority]float64{}
+	var hoursSum, hoursWeight float64
+	n := 0
+	for _, m := range matches {
+		if m.ID == task.ID {
+			continue
+		}
+		w := m.Similarity
+		if task.ProjectID != nil && m.ProjectID != nil && *m.ProjectID == *task.ProjectID {
+			w *= hintProjectWeight
+		}
+		priorityWeight[m.Priority] += w
+		if m.EstimatedHours != nil {
+			hoursSum += w * *m.EstimatedHours
+			hoursWeight += w
+		}
+		n++
+	}
+	if n < hintMinMatches {
+		return nil, nil
+	}
+
+	hints := &domain.TaskHints{BasedOn: n}
+	var best float64
+	for _, p := range []domain.TaskPriority{domain.TaskPriorityHigh, domain.TaskPriorityMedium, domain.TaskPriorityLow} {
+		if w := priorityWeight[p]; w > best {
+			p := p
+			hints.Priority, best = &p, w
+		}
+	}
+	if hoursWeight > 0 {
+		h := math.Round(hoursSum/hoursWeight*4) / 4
+		hints.EstimatedHours = &h
+	}
+
+	s.log.WithFields(logrus.Fields{"task_id": task.ID, "based_on": n}).Debug("task hints suggested")
+	return hints, nil
+}
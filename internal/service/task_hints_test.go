@@ -0,0 +1,60 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_CreateWithHints(t *testing.T) {
+	userID, projectID := uuid.New(), uuid.New()
+	hours := func(h float64) *float64 { return &h }
+	match := func(p domain.TaskPriority, est *float64, sim float64, project *uuid.UUID) *domain.TaskMatch {
+		return &domain.TaskMatch{
+			Task:       domain.Task{ID: uuid.New(), UserID: userID, Priority: p, EstimatedHours: est, ProjectID: project},
+			Similarity: sim,
+		}
+	}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	taskRepo.On("FuzzyFind", mock.Anything, userID, "Pay rent", mock.Anything, mock.Anything).Return([]*domain.TaskMatch{
+		match(domain.TaskPriorityHigh, hours(1), 0.9, &projectID),
+		match(domain.TaskPriorityLow, hours(3), 0.6, nil),
+		match(domain.TaskPriorityLow, nil, 0.5, nil),
+	}, nil)
+	projectRepo := &mockProjectRepo{}
+	projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID}, nil)
+	svc := newTaskService(taskRepo, projectRepo)
+
+	got, err := svc.CreateWithHints(context.Background(), userID, &domain.CreateTaskRequest{
+		Title: "Pay rent", Priority: domain.TaskPriorityMedium, ProjectID: &projectID,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, got.Hints)
+
+	// high: 0.9*1.5 = 1.35 beats low: 0.6+0.5 = 1.1
+	assert.Equal(t, domain.TaskPriorityHigh, *got.Hints.Priority)
+	// (1.35*1 + 0.6*3) / 1.95 ≈ 1.62, rounded to a quarter hour
+	assert.Equal(t, 1.5, *got.Hints.EstimatedHours)
+	assert.Equal(t, 3, got.Hints.BasedOn)
+}
+
+func TestTaskService_CreateWithHints_NoHistory(t *testing.T) {
+	userID := uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	taskRepo.On("FuzzyFind", mock.Anything, userID, mock.Anything, mock.Anything, mock.Anything).Return([]*domain.TaskMatch{}, nil)
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	got, err := svc.CreateWithHints(context.Background(), userID, &domain.CreateTaskRequest{
+		Title: "Something new", Priority: domain.TaskPriorityLow,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, got.Hints)
+}
@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TaskHistoryService records every persisted task change in the audit log,
+// along with the fields it changed, and reconstructs past task state and
+// activity feeds from it. Register it with TaskService.Subscribe.
+type TaskHistoryService struct {
+	eventRepo domain.TaskEventRepository
+	taskSvc   *TaskService
+	log       *logrus.Logger
+}
+
+// NewTaskHistoryService constructs a TaskHistoryService.
+func NewTaskHistoryService(eventRepo domain.TaskEventRepository, taskSvc *TaskService, log *logrus.Logger) *TaskHistoryService {
+	return &TaskHistoryService{eventRepo: eventRepo, taskSvc: taskSvc, log: log}
+}
+
+// TaskChanged subscribes to task events, recording each in the history.
+func (s *TaskHistoryService) TaskChanged(ctx context.Context, e domain.TaskChange) error {
+	event, task := e.EventName(), e.EventTask()
+	// task.completed and task.moved are always published alongside the
+	// task.updated that carries the same state, so they add nothing to the history.
+	if event == domain.EventTaskCompleted || event == domain.EventTaskMoved {
+		return nil
+	}
+
+	snapshot, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("taskHistoryService.TaskChanged snapshot: %w", err)
+	}
+	changes, err := json.Marshal(s.changesSincePrevious(ctx, event, task))
+	if err != nil {
+		return fmt.Errorf("taskHistoryService.TaskChanged changes: %w", err)
+	}
+	record := &domain.TaskEvent{
+		ID:        uuid.New(),
+		TaskID:    task.ID,
+		UserID:    task.UserID,
+		Event:     event,
+		Snapshot:  snapshot,
+		Changes:   changes,
+		CreatedAt: time.Now(),
+	}
+	if err := s.eventRepo.Create(ctx, record); err != nil {
+		return fmt.Errorf("taskHistoryService.TaskChanged: %w", err)
+	}
+	return nil
+}
+
+// changesSincePrevious diffs an update against the last recorded snapshot.
+// Creates and deletes, and updates with no earlier snapshot, report none.
+func (s *TaskHistoryService) changesSincePrevious(ctx context.Context, event string, task *domain.Task) domain.TaskChanges {
+	changes := domain.TaskChanges{}
+	if event != domain.EventTaskUpdated {
+		return changes
+	}
+	prev, err := s.eventRepo.FindLatest(ctx, task.ID, time.Now())
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.log.WithError(err).WithField("task_id", task.ID).Warn("failed to load previous task snapshot")
+		}
+		return changes
+	}
+	var before domain.Task
+	if err := json.Unmarshal(prev.Snapshot, &before); err != nil {
+		s.log.WithError(err).WithField("task_id", task.ID).Warn("failed to decode previous task snapshot")
+		return changes
+	}
+
+	changes = domain.DiffTasks(&before, task)
+	if (before.ArchivedAt == nil) != (task.ArchivedAt == nil) {
+		changes["archived"] = domain.FieldChange{Old: before.ArchivedAt != nil, New: task.ArchivedAt != nil}
+	}
+	if before.Pinned != task.Pinned {
+		changes["pinned"] = domain.FieldChange{Old: before.Pinned, New: task.Pinned}
+	}
+	return changes
+}
+
+// Activity returns one page of the task's activity, newest first, enforcing
+// ownership. Updates that changed nothing tracked, such as reordering, are
+// left out.
+func (s *TaskHistoryService) Activity(ctx context.Context, taskID, userID uuid.UUID, page, limit int) ([]*domain.TaskActivity, int, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, 0, err
+	}
+	events, total, err := s.eventRepo.ListActivity(ctx, taskID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("taskHistoryService.Activity: %w", err)
+	}
+
+	activity := make([]*domain.TaskActivity, 0, len(events))
+	for _, e := range events {
+		a := &domain.TaskActivity{ID: e.ID, Event: e.Event, UserID: e.UserID, CreatedAt: e.CreatedAt}
+		if len(e.Changes) > 0 {
+			if err := json.Unmarshal(e.Changes, &a.Changes); err != nil {
+				return nil, 0, fmt.Errorf("taskHistoryService.Activity decode changes: %w", err)
+			}
+		}
+		activity = append(activity, a)
+	}
+	return activity, total, nil
+}
+
+// AsOf returns the task as it was at asOf. It returns ErrNotFound when the
+// task did not exist yet or had already been deleted at that moment.
+func (s *TaskHistoryService) AsOf(ctx context.Context, taskID, userID uuid.UUID, asOf time.Time) (*domain.Task, error) {
+	e, err := s.eventRepo.FindLatest(ctx, taskID, asOf)
+	if errors.Is(err, domain.ErrNotFound) {
+		return s.unchangedSince(ctx, taskID, userID, asOf)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("taskHistoryService.AsOf: %w", err)
+	}
+
+	if e.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	if e.Event == domain.EventTaskDeleted {
+		return nil, domain.ErrNotFound
+	}
+
+	var task domain.Task
+	if err := json.Unmarshal(e.Snapshot, &task); err != nil {
+		return nil, fmt.Errorf("taskHistoryService.AsOf decode snapshot: %w", err)
+	}
+	return &task, nil
+}
+
+// unchangedSince covers tasks whose history predates the audit log: the
+// current row is the answer only if it was neither created nor modified after asOf.
+func (s *TaskHistoryService) unchangedSince(ctx context.Context, taskID, userID uuid.UUID, asOf time.Time) (*domain.Task, error) {
+	task, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.CreatedAt.After(asOf) || task.UpdatedAt.After(asOf) {
+		return nil, domain.ErrNotFound
+	}
+	return task, nil
+}
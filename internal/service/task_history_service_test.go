@@ -0,0 +1,126 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskEventRepo struct {
+	events []*domain.TaskEvent
+}
+
+func (f *fakeTaskEventRepo) Create(_ context.Context, e *domain.TaskEvent) error {
+	f.events = append(f.events, e)
+	return nil
+}
+
+func (f *fakeTaskEventRepo) FindLatest(_ context.Context, taskID uuid.UUID, asOf time.Time) (*domain.TaskEvent, error) {
+	var latest *domain.TaskEvent
+	for _, e := range f.events {
+		if e.TaskID == taskID && !e.CreatedAt.After(asOf) {
+			latest = e
+		}
+	}
+	if latest == nil {
+		return nil, domain.ErrNotFound
+	}
+	return latest, nil
+}
+
+func (f *fakeTaskEventRepo) ListActivity(_ context.Context, taskID uuid.UUID, page, limit int) ([]*domain.TaskEvent, int, error) {
+	var matched []*domain.TaskEvent
+	for i := len(f.events) - 1; i >= 0; i-- {
+		e := f.events[i]
+		if e.TaskID == taskID && (e.Event != domain.EventTaskUpdated || string(e.Changes) != "{}") {
+			matched = append(matched, e)
+		}
+	}
+	start := min((page-1)*limit, len(matched))
+	return matched[start:min(start+limit, len(matched))], len(matched), nil
+}
+
+func TestTaskHistoryService_AsOfReturnsSnapshotAtThatMoment(t *testing.T) {
+	repo := &fakeTaskEventRepo{}
+	svc := service.NewTaskHistoryService(repo, nil, logrus.New())
+	ctx := context.Background()
+
+	task := &domain.Task{ID: uuid.New(), UserID: uuid.New(), Title: "draft", Priority: domain.TaskPriorityLow}
+	svc.TaskChanged(ctx, domain.TaskCreated{Task: task})
+	beforeEdit := time.Now()
+	time.Sleep(time.Millisecond)
+
+	edited := *task
+	edited.Title, edited.Priority = "final", domain.TaskPriorityHigh
+	svc.TaskChanged(ctx, domain.TaskUpdated{Task: &edited})
+	svc.TaskChanged(ctx, domain.TaskCompleted{Task: &edited})
+	require.Len(t, repo.events, 2, "task.completed duplicates task.updated and is not recorded")
+
+	got, err := svc.AsOf(ctx, task.ID, task.UserID, beforeEdit)
+	require.NoError(t, err)
+	assert.Equal(t, "draft", got.Title)
+	assert.Equal(t, domain.TaskPriorityLow, got.Priority)
+
+	got, err = svc.AsOf(ctx, task.ID, task.UserID, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "final", got.Title)
+
+	_, err = svc.AsOf(ctx, task.ID, uuid.New(), time.Now())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestTaskHistoryService_AsOfAfterDeleteIsNotFound(t *testing.T) {
+	repo := &fakeTaskEventRepo{}
+	svc := service.NewTaskHistoryService(repo, nil, logrus.New())
+	ctx := context.Background()
+
+	task := &domain.Task{ID: uuid.New(), UserID: uuid.New(), Title: "gone"}
+	svc.TaskChanged(ctx, domain.TaskCreated{Task: task})
+	svc.TaskChanged(ctx, domain.TaskDeleted{Task: task})
+
+	_, err := svc.AsOf(ctx, task.ID, task.UserID, time.Now())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestTaskHistoryService_ActivityRecordsTransitions(t *testing.T) {
+	repo := &fakeTaskEventRepo{}
+	taskRepo := &mockTaskRepo{}
+	svc := service.NewTaskHistoryService(repo, newTaskService(taskRepo, &mockProjectRepo{}), logrus.New())
+	ctx := context.Background()
+
+	task := &domain.Task{ID: uuid.New(), UserID: uuid.New(), Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	now := time.Now()
+	projectID := uuid.New()
+
+	svc.TaskChanged(ctx, domain.TaskCreated{Task: task})
+	moved := *task
+	moved.ProjectID, moved.Status = &projectID, domain.TaskStatusInProgress
+	svc.TaskChanged(ctx, domain.TaskUpdated{Task: &moved})
+	reordered := moved
+	reordered.SortOrder = 42
+	svc.TaskChanged(ctx, domain.TaskUpdated{Task: &reordered})
+	archived := reordered
+	archived.ArchivedAt = &now
+	svc.TaskChanged(ctx, domain.TaskUpdated{Task: &archived})
+
+	activity, total, err := svc.Activity(ctx, task.ID, task.UserID, 1, 20)
+	require.NoError(t, err)
+	require.Equal(t, 3, total, "the reorder changed nothing tracked")
+	require.Len(t, activity, 3)
+
+	assert.Equal(t, domain.FieldChange{Old: false, New: true}, activity[0].Changes["archived"])
+	assert.Equal(t, string(domain.TaskStatusInProgress), activity[1].Changes["status"].New)
+	assert.Equal(t, projectID.String(), activity[1].Changes["project_id"].New, "changes round-trip through JSON")
+	assert.Nil(t, activity[1].Changes["project_id"].Old)
+	assert.Equal(t, domain.EventTaskCreated, activity[2].Event)
+	assert.Empty(t, activity[2].Changes)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// maxTaskLinks caps the links on one task.
+const maxTaskLinks = 50
+
+// TaskLinkService manages external links attached to tasks.
+type TaskLinkService struct {
+	linkRepo domain.TaskLinkRepository
+	taskSvc  *TaskService
+}
+
+// NewTaskLinkService constructs a TaskLinkService.
+func NewTaskLinkService(linkRepo domain.TaskLinkRepository, taskSvc *TaskService) *TaskLinkService {
+	return &TaskLinkService{linkRepo: linkRepo, taskSvc: taskSvc}
+}
+
+// Create attaches a link to a task, enforcing ownership. Only http and https
+// URLs are accepted, and a task cannot carry the same URL twice.
+func (s *TaskLinkService) Create(ctx context.Context, taskID, userID uuid.UUID, req *domain.CreateTaskLinkRequest) (*domain.TaskLink, error) {
+	task, err := s.taskSvc.GetByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	link := &domain.TaskLink{ID: uuid.New(), TaskID: task.ID, UserID: userID}
+	if err := setLink(link, req.URL, req.Label); err != nil {
+		return nil, fmt.Errorf("taskLinkService.Create: %w", err)
+	}
+
+	n, err := s.linkRepo.CountByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("taskLinkService.Create: %w", err)
+	}
+	if n >= maxTaskLinks {
+		return nil, fmt.Errorf("taskLinkService.Create: at most %d links per task: %w", maxTaskLinks, domain.ErrValidation)
+	}
+
+	link.CreatedAt = link.UpdatedAt
+	if err := s.linkRepo.Create(ctx, link); err != nil {
+		return nil, fmt.Errorf("taskLinkService.Create: %w", err)
+	}
+	return link, nil
+}
+
+// List returns a task's links in the order they were added, enforcing
+// ownership.
+func (s *TaskLinkService) List(ctx context.Context, taskID, userID uuid.UUID) ([]*domain.TaskLink, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	links, err := s.linkRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("taskLinkService.List: %w", err)
+	}
+	return links, nil
+}
+
+// Update changes a link's URL or label.
+func (s *TaskLinkService) Update(ctx context.Context, taskID, id, userID uuid.UUID, req *domain.UpdateTaskLinkRequest) (*domain.TaskLink, error) {
+	link, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	rawURL, label := link.URL, link.Label
+	if req.URL != nil {
+		rawURL = *req.URL
+	}
+	if req.Label != nil {
+		label = *req.Label
+	}
+	if err := setLink(link, rawURL, label); err != nil {
+		return nil, fmt.Errorf("taskLinkService.Update: %w", err)
+	}
+	if err := s.linkRepo.Update(ctx, link); err != nil {
+		return nil, fmt.Errorf("taskLinkService.Update: %w", err)
+	}
+	return link, nil
+}
+
+// Delete removes a link.
+func (s *TaskLinkService) Delete(ctx context.Context, taskID, id, userID uuid.UUID) error {
+	link, err := s.get(ctx, taskID, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.linkRepo.Delete(ctx, link.ID); err != nil {
+		return fmt.Errorf("taskLinkService.Delete: %w", err)
+	}
+	return nil
+}
+
+func (s *TaskLinkService) get(ctx context.Context, taskID, id, userID uuid.UUID) (*domain.TaskLink, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	link, err := s.linkRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if link.TaskID != taskID {
+		return nil, domain.ErrNotFound
+	}
+	return link, nil
+}
+
+// setLink checks rawURL and stores it on link with label, defaulting the
+// label to the URL's host.
+func setLink(link *domain.TaskLink, rawURL, label string) error {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL: %w", domain.ErrValidation)
+	}
+	label = strings.TrimSpace(label)
+	if label == "" {
+		label = u.Hostname()
+	}
+	link.URL, link.Label, link.UpdatedAt = u.String(), label, time.Now()
+	return nil
+}
@@ -0,0 +1,93 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskLinkRepo struct {
+	domain.TaskLinkRepository
+	links []*domain.TaskLink
+}
+
+func (f *fakeTaskLinkRepo) Create(_ context.Context, link *domain.TaskLink) error {
+	for _, l := range f.links {
+		if l.TaskID == link.TaskID && l.URL == link.URL {
+			return domain.ErrAlreadyExists
+		}
+	}
+	f.links = append(f.links, link)
+	return nil
+}
+
+func (f *fakeTaskLinkRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.TaskLink, error) {
+	for _, l := range f.links {
+		if l.ID == id {
+			copied := *l
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeTaskLinkRepo) CountByTaskID(_ context.Context, taskID uuid.UUID) (int, error) {
+	n := 0
+	for _, l := range f.links {
+		if l.TaskID == taskID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeTaskLinkRepo) Update(_ context.Context, link *domain.TaskLink) error {
+	for i, l := range f.links {
+		if l.ID == link.ID {
+			f.links[i] = link
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+func TestTaskLinkService_CreateAndUpdate(t *testing.T) {
+	userID, taskID, otherTaskID := uuid.New(), uuid.New(), uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(&domain.Task{ID: taskID, UserID: userID}, nil)
+	taskRepo.On("FindByID", mock.Anything, otherTaskID).Return(&domain.Task{ID: otherTaskID, UserID: userID}, nil)
+	links := &fakeTaskLinkRepo{}
+	svc := service.NewTaskLinkService(links, newTaskService(taskRepo, &mockProjectRepo{}))
+	ctx := context.Background()
+
+	link, err := svc.Create(ctx, taskID, userID, &domain.CreateTaskLinkRequest{URL: "HTTPS://github.com/acme/app/pull/42"})
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/app/pull/42", link.URL)
+	assert.Equal(t, "github.com", link.Label, "label defaults to the host")
+
+	_, err = svc.Create(ctx, taskID, userID, &domain.CreateTaskLinkRequest{URL: "https://github.com/acme/app/pull/42"})
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+
+	for _, bad := range []string{"javascript:alert(1)", "ftp://files.example.com/a", "/relative/path"} {
+		_, err = svc.Create(ctx, taskID, userID, &domain.CreateTaskLinkRequest{URL: bad})
+		assert.ErrorIs(t, err, domain.ErrValidation, bad)
+	}
+
+	_, err = svc.Create(ctx, taskID, uuid.New(), &domain.CreateTaskLinkRequest{URL: "https://example.com"})
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	label := "  PR #42  "
+	updated, err := svc.Update(ctx, taskID, link.ID, userID, &domain.UpdateTaskLinkRequest{Label: &label})
+	require.NoError(t, err)
+	assert.Equal(t, "PR #42", updated.Label)
+	assert.Equal(t, link.URL, updated.URL)
+
+	_, err = svc.Update(ctx, otherTaskID, link.ID, userID, &domain.UpdateTaskLinkRequest{Label: &label})
+	assert.ErrorIs(t, err, domain.ErrNotFound, "link belongs to another task")
+}
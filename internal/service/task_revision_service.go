@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TaskRevisionService keeps the last few descriptions of each task as
+// updates replace them, and restores them. Register it with
+// TaskService.UseDescriptionArchiver.
+type TaskRevisionService struct {
+	revisionRepo domain.TaskRevisionRepository
+	taskSvc      *TaskService
+	keep         atomic.Int64
+	log          *logrus.Logger
+}
+
+// NewTaskRevisionService constructs a TaskRevisionService keeping up to keep
+// revisions per task; zero keeps none.
+func NewTaskRevisionService(revisionRepo domain.TaskRevisionRepository, taskSvc *TaskService, keep int, log *logrus.Logger) *TaskRevisionService {
+	s := &TaskRevisionService{revisionRepo: revisionRepo, taskSvc: taskSvc, log: log}
+	s.keep.Store(int64(keep))
+	return s
+}
+
+// Keep is how many revisions are kept per task.
+func (s *TaskRevisionService) Keep() int {
+	return int(s.keep.Load())
+}
+
+// SetKeep changes how many revisions are kept per task; tasks are trimmed
+// to the new count as their descriptions next change.
+func (s *TaskRevisionService) SetKeep(n int) {
+	s.keep.Store(int64(n))
+}
+
+// ArchiveDescription implements DescriptionArchiver.
+func (s *TaskRevisionService) ArchiveDescription(ctx context.Context, task *domain.Task, previous string) error {
+	keep := s.Keep()
+	if keep <= 0 {
+		return nil
+	}
+	rev := &domain.TaskRevision{
+		ID:          uuid.New(),
+		TaskID:      task.ID,
+		UserID:      task.UserID,
+		Description: previous,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.revisionRepo.Create(ctx, rev, keep); err != nil {
+		return fmt.Errorf("taskRevisionService.ArchiveDescription: %w", err)
+	}
+	return nil
+}
+
+// List returns the task's earlier descriptions, most recent first. It
+// enforces ownership.
+func (s *TaskRevisionService) List(ctx context.Context, taskID, userID uuid.UUID) ([]*domain.TaskRevision, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisionRepo.ListByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("taskRevisionService.List: %w", err)
+	}
+	return revisions, nil
+}
+
+// Restore puts a revision back as the task's description. It is an ordinary
+// update, so the description it replaces becomes a revision in turn and the
+// restore can itself be undone.
+func (s *TaskRevisionService) Restore(ctx context.Context, taskID, revisionID, userID uuid.UUID) (*domain.Task, error) {
+	if _, err := s.taskSvc.GetByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	rev, err := s.revisionRepo.FindByID(ctx, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if rev.TaskID != taskID {
+		return nil, domain.ErrNotFound
+	}
+	return s.taskSvc.Update(ctx, taskID, userID, &domain.UpdateTaskRequest{Description: &rev.Description})
+}
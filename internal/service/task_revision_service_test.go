@@ -0,0 +1,83 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTaskRevisionRepo struct {
+	revisions []*domain.TaskRevision
+}
+
+func (f *fakeTaskRevisionRepo) Create(_ context.Context, rev *domain.TaskRevision, keep int) error {
+	f.revisions = append(f.revisions, rev)
+	if len(f.revisions) > keep {
+		f.revisions = f.revisions[len(f.revisions)-keep:]
+	}
+	return nil
+}
+
+func (f *fakeTaskRevisionRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.TaskRevision, error) {
+	for _, r := range f.revisions {
+		if r.ID == id {
+			return r, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeTaskRevisionRepo) ListByTaskID(_ context.Context, taskID uuid.UUID) ([]*domain.TaskRevision, error) {
+	out := []*domain.TaskRevision{}
+	for i := len(f.revisions) - 1; i >= 0; i-- {
+		if f.revisions[i].TaskID == taskID {
+			out = append(out, f.revisions[i])
+		}
+	}
+	return out, nil
+}
+
+func TestTaskRevisionService_KeepsAndRestoresDescriptions(t *testing.T) {
+	userID := uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Trip notes", Description: "v1", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, CreatedAt: time.Now()}
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	taskSvc := newTaskService(taskRepo, &mockProjectRepo{})
+	revisions := &fakeTaskRevisionRepo{}
+	svc := service.NewTaskRevisionService(revisions, taskSvc, 2, logrus.New())
+	taskSvc.UseDescriptionArchiver(svc)
+
+	ctx := context.Background()
+	for _, d := range []string{"v2", "v3", "oops"} {
+		_, err := taskSvc.Update(ctx, task.ID, userID, &domain.UpdateTaskRequest{Description: &d})
+		require.NoError(t, err)
+	}
+	title := "Trip notes (Lisbon)"
+	_, err := taskSvc.Update(ctx, task.ID, userID, &domain.UpdateTaskRequest{Title: &title})
+	require.NoError(t, err)
+
+	list, err := svc.List(ctx, task.ID, userID)
+	require.NoError(t, err)
+	require.Len(t, list, 2, "only the last two are kept, and only description changes count")
+	assert.Equal(t, "v3", list[0].Description)
+	assert.Equal(t, "v2", list[1].Description)
+
+	restored, err := svc.Restore(ctx, task.ID, list[0].ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "v3", restored.Description)
+	list, err = svc.List(ctx, task.ID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "oops", list[0].Description, "a restore can be undone")
+
+	_, err = svc.Restore(ctx, task.ID, uuid.New(), userID)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -2,68 +2,300 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/eventbus"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 )
 
+// TaskDefaulter fills in fields a new task was created without, before it is
+// persisted.
+type TaskDefaulter interface {
+	ApplyDefaults(ctx context.Context, task *domain.Task) error
+}
+
+// DueDateAdjuster can move a due date as it is set on a new task or on the
+// next occurrence of a recurring one, e.g. off a day the user does not work.
+type DueDateAdjuster interface {
+	AdjustDueDate(ctx context.Context, userID uuid.UUID, due time.Time) (time.Time, error)
+}
+
+// OverdueFilter can narrow the tasks past their deadline to those overdue
+// by the user's own measure, e.g. counting only working hours.
+type OverdueFilter interface {
+	FilterOverdue(ctx context.Context, userID uuid.UUID, tasks []*domain.Task, now time.Time) ([]*domain.Task, error)
+}
+
+// UserLocator returns the time zone a user's days are counted in.
+type UserLocator interface {
+	Location(ctx context.Context, userID uuid.UUID) (*time.Location, error)
+}
+
+// TaskCompletionGuard can veto marking a task done.
+type TaskCompletionGuard interface {
+	CheckCompletion(ctx context.Context, task *domain.Task) error
+}
+
+// DescriptionArchiver keeps the description an update is about to replace.
+// An error fails the update, so no description is lost unrecorded.
+type DescriptionArchiver interface {
+	ArchiveDescription(ctx context.Context, task *domain.Task, previous string) error
+}
+
 // TaskService handles task management use cases.
 type TaskService struct {
 	taskRepo    domain.TaskRepository
 	projectRepo domain.ProjectRepository
+	timeRepo    domain.TimeEntryRepository
+	events      *eventbus.Bus
+	defaulters  []TaskDefaulter
+	adjusters   []DueDateAdjuster
+	overdue     []OverdueFilter
+	guards      []TaskCompletionGuard
+	archivers   []DescriptionArchiver
+	locator     UserLocator
+	members     domain.ProjectMemberRepository
 	log         *logrus.Logger
 }
 
 // NewTaskService constructs a TaskService with its dependencies.
-func NewTaskService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, log *logrus.Logger) *TaskService {
-	return &TaskService{taskRepo: taskRepo, projectRepo: projectRepo, log: log}
+func NewTaskService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository, timeRepo domain.TimeEntryRepository, log *logrus.Logger) *TaskService {
+	return &TaskService{taskRepo: taskRepo, projectRepo: projectRepo, timeRepo: timeRepo, log: log}
+}
+
+// UseEvents publishes task events on bus. Must be called before serving requests.
+func (s *TaskService) UseEvents(bus *eventbus.Bus) {
+	s.events = bus
+}
+
+// UseDefaulter registers a TaskDefaulter run by Create. Must be called before serving requests.
+func (s *TaskService) UseDefaulter(d TaskDefaulter) {
+	s.defaulters = append(s.defaulters, d)
+}
+
+// UseDueDateAdjuster registers a DueDateAdjuster. Must be called before serving requests.
+func (s *TaskService) UseDueDateAdjuster(a DueDateAdjuster) {
+	s.adjusters = append(s.adjusters, a)
+}
+
+// UseOverdueFilter registers an OverdueFilter applied by EachOverdue. Must be called before serving requests.
+func (s *TaskService) UseOverdueFilter(f OverdueFilter) {
+	s.overdue = append(s.overdue, f)
+}
+
+// UseCompletionGuard registers a TaskCompletionGuard consulted before a task
+// is marked done. Must be called before serving requests.
+func (s *TaskService) UseCompletionGuard(g TaskCompletionGuard) {
+	s.guards = append(s.guards, g)
+}
+
+// UseDescriptionArchiver registers a DescriptionArchiver called by Update
+// before it replaces a non-empty description. Must be called before serving
+// requests.
+func (s *TaskService) UseDescriptionArchiver(a DescriptionArchiver) {
+	s.archivers = append(s.archivers, a)
+}
+
+// UseLocator sets the UserLocator that due expressions are resolved with;
+// without one they resolve in UTC. Must be called before serving requests.
+func (s *TaskService) UseLocator(l UserLocator) {
+	s.locator = l
+}
+
+// UseMembers lets users reach tasks in projects shared with them; without it
+// only project owners do. Must be called before serving requests.
+func (s *TaskService) UseMembers(members domain.ProjectMemberRepository) {
+	s.members = members
 }
 
 // Create creates a new task for the authenticated user.
 func (s *TaskService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateTaskRequest) (*domain.Task, error) {
+	task, err := s.newTask(ctx, userID, req, time.Now(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.Create: %w", err)
+	}
+
+	if err := s.taskRepo.Create(ctx, task); err != nil {
+		return nil, fmt.Errorf("taskService.Create: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"task_id": task.ID, "user_id": userID}).Info("task created")
+	s.publish(ctx, domain.TaskCreated{Task: task})
+	return task, nil
+}
+
+// CreateBatch creates a task for every request in one transaction: either
+// all of them are created or none is. Each request is built as by Create,
+// looking up each parent and project once for the whole batch, and the
+// tasks are inserted a chunk at a time; a failed chunk is reported as a
+// *domain.TaskBatchError. A subtask's parent must already be stored, so a
+// tree goes in one batch per level.
+func (s *TaskService) CreateBatch(ctx context.Context, userID uuid.UUID, reqs []*domain.CreateTaskRequest) ([]*domain.Task, error) {
+	now := time.Now()
+	lookups := &createLookups{parents: map[uuid.UUID]*domain.Task{}, projects: map[uuid.UUID]error{}}
+	tasks := make([]*domain.Task, len(reqs))
+	for i, req := range reqs {
+		task, err := s.newTask(ctx, userID, req, now, lookups)
+		if err != nil {
+			return nil, fmt.Errorf("taskService.CreateBatch: task %d: %w", i+1, err)
+		}
+		// Keep the batch in order at the bottom of the manual order.
+		task.SortOrder += float64(i)
+		tasks[i] = task
+	}
+
+	if err := s.taskRepo.CreateBatch(ctx, tasks); err != nil {
+		return nil, fmt.Errorf("taskService.CreateBatch: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"user_id": userID, "tasks": len(tasks)}).Info("tasks created")
+	for _, task := range tasks {
+		s.publish(ctx, domain.TaskCreated{Task: task})
+	}
+	return tasks, nil
+}
+
+// createLookups memoizes the parent and project checks of a batch, which
+// mostly repeat from one task to the next.
+type createLookups struct {
+	parents  map[uuid.UUID]*domain.Task
+	projects map[uuid.UUID]error
+}
+
+// newTask builds the task a create request describes, as of now, without
+// storing it. lookups is nil outside a batch.
+func (s *TaskService) newTask(ctx context.Context, userID uuid.UUID, req *domain.CreateTaskRequest, now time.Time, lookups *createLookups) (*domain.Task, error) {
+	// A subtask must belong to the same user and, unless told otherwise,
+	// lives in its parent's project.
+	if req.ParentID != nil {
+		parent, ok := lookups.parent(*req.ParentID)
+		if !ok {
+			var err error
+			if parent, err = s.GetByID(ctx, *req.ParentID, userID); err != nil {
+				return nil, err
+			}
+			lookups.setParent(parent)
+		}
+		if req.ProjectID == nil {
+			req.ProjectID = parent.ProjectID
+		}
+	}
+
 	// Validate project ownership if provided
 	if req.ProjectID != nil {
-		if err := s.assertProjectOwner(ctx, *req.ProjectID, userID); err != nil {
+		checked, err := lookups.project(*req.ProjectID)
+		if !checked {
+			err = s.assertProjectAccess(ctx, *req.ProjectID, userID)
+			lookups.setProject(*req.ProjectID, err)
+		}
+		if err != nil {
 			return nil, err
 		}
 	}
 
-	now := time.Now()
 	task := &domain.Task{
 		ID:             uuid.New(),
 		UserID:         userID,
 		ProjectID:      req.ProjectID,
+		ParentID:       req.ParentID,
 		Title:          req.Title,
 		Description:    req.Description,
 		Status:         domain.TaskStatusTodo,
 		Priority:       req.Priority,
 		EstimatedHours: req.EstimatedHours,
+		Energy:         req.Energy,
 		DueDate:        req.DueDate,
-		CreatedAt:      now,
-		UpdatedAt:      now,
+		StartDate:      req.StartDate,
+		ScheduledAt:    req.ScheduledAt,
+		Recurrence:     req.Recurrence,
+		NoEscalation:   req.NoEscalation,
+		// New tasks go to the bottom of the manual order.
+		SortOrder:       float64(now.UnixMilli()),
+		StatusChangedAt: now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
 
-	task.SmartScore = task.CalculateSmartScore()
+	if err := setTaskContext(task, req.Context); err != nil {
+		return nil, err
+	}
 
-	if err := s.taskRepo.Create(ctx, task); err != nil {
-		return nil, fmt.Errorf("taskService.Create: %w", err)
+	if req.DueExpr != nil && strings.TrimSpace(*req.DueExpr) != "" {
+		if req.DueDate != nil {
+			return nil, fmt.Errorf("set due_date or due_expr, not both: %w", domain.ErrValidation)
+		}
+		expr := strings.TrimSpace(*req.DueExpr)
+		task.DueExpr = &expr
+		if err := task.ResolveDueExpr(now, s.location(ctx, userID)); err != nil {
+			return nil, fmt.Errorf("%v: %w", err, domain.ErrValidation)
+		}
 	}
 
-	s.log.WithFields(logrus.Fields{"task_id": task.ID, "user_id": userID}).Info("task created")
+	// Defaults are a convenience; a failing one must not block the create.
+	for _, d := range s.defaulters {
+		if err := d.ApplyDefaults(ctx, task); err != nil {
+			s.log.WithError(err).WithField("user_id", userID).Warn("task defaults not applied")
+		}
+	}
+
+	// Checked after the defaulters, which may have supplied the due date.
+	if task.Recurrence != nil {
+		if task.DueDate == nil {
+			return nil, fmt.Errorf("a recurring task needs a due date: %w", domain.ErrValidation)
+		}
+		task.Recurrence.Anchor = *task.DueDate
+		task.Recurrence.Start = nil
+		// With a due expression the series counts occurrence starts instead.
+		if task.DueExpr != nil {
+			task.Recurrence.Anchor = now
+			task.Recurrence.Start = &now
+		}
+	}
+	// Adjusted after the anchor is taken, so the series keeps counting from
+	// the date that was asked for.
+	if task.DueDate != nil {
+		due := s.adjustDueDate(ctx, userID, *task.DueDate)
+		task.DueDate = &due
+	}
+	if err := s.checkStartDate(ctx, task); err != nil {
+		return nil, err
+	}
+	if err := s.setSchedule(task, req.ScheduledDuration); err != nil {
+		return nil, err
+	}
+	if !req.AllowOverlap {
+		if err := s.checkSchedule(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+
+	task.SmartScore = task.CalculateSmartScore()
 	return task, nil
 }
 
-// GetByID retrieves a task, enforcing ownership.
+// GetByID retrieves a task, enforcing ownership: the caller must own the
+// task, or have access to the project it is in.
 func (s *TaskService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Task, error) {
 	task, err := s.taskRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	if task.UserID != userID {
-		return nil, domain.ErrForbidden
+		if task.ProjectID == nil {
+			return nil, domain.ErrForbidden
+		}
+		if err := s.assertProjectAccess(ctx, *task.ProjectID, userID); err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, domain.ErrForbidden
+			}
+			return nil, err
+		}
 	}
 	return task, nil
 }
@@ -77,17 +309,145 @@ func (s *TaskService) List(ctx context.Context, userID uuid.UUID, filter domain.
 	return tasks, total, nil
 }
 
+// Stream calls fn with every task matching filter, without pagination and
+// without loading them all at once. Tasks come in smart score order, or
+// manual order when filter asks for it; other rankers are not applied.
+func (s *TaskService) Stream(ctx context.Context, userID uuid.UUID, filter domain.TaskFilter, fn func(*domain.Task) error) error {
+	if err := s.taskRepo.Stream(ctx, userID, filter, fn); err != nil {
+		return fmt.Errorf("taskService.Stream: %w", err)
+	}
+	return nil
+}
+
+// ListScheduled returns the user's open tasks with a time slot overlapping
+// [from, to), earliest first.
+func (s *TaskService) ListScheduled(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]*domain.Task, error) {
+	tasks, err := s.taskRepo.ListScheduled(ctx, userID, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.ListScheduled: %w", err)
+	}
+	return tasks, nil
+}
+
+// maxModifiedTasks caps a single modified_since poll.
+const maxModifiedTasks = 500
+
+// overdueBatchSize is how many overdue tasks EachOverdue loads at a time.
+const overdueBatchSize = 200
+
+// EachOverdue calls fn with the user's unfinished tasks past their due date,
+// most overdue first, as narrowed by any OverdueFilters. Tasks are loaded a
+// batch at a time, so a user with thousands overdue never has them all in
+// memory; an error from fn stops the scan and is returned.
+func (s *TaskService) EachOverdue(ctx context.Context, userID uuid.UUID, fn func(tasks []*domain.Task) error) error {
+	now := time.Now()
+	var after *domain.OverdueCursor
+	for {
+		tasks, err := s.taskRepo.FindOverdue(ctx, userID, after, overdueBatchSize)
+		if err != nil {
+			return fmt.Errorf("taskService.EachOverdue: %w", err)
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		after = domain.OverdueCursorAfter(tasks[len(tasks)-1])
+		full := len(tasks) == overdueBatchSize
+
+		for _, f := range s.overdue {
+			filtered, err := f.FilterOverdue(ctx, userID, tasks, now)
+			if err != nil {
+				s.log.WithError(err).WithField("user_id", userID).Warn("overdue tasks not filtered")
+				continue
+			}
+			tasks = filtered
+		}
+		if len(tasks) > 0 {
+			if err := fn(tasks); err != nil {
+				return err
+			}
+		}
+		if !full {
+			return nil
+		}
+	}
+}
+
+// ListModifiedSince returns the user's tasks changed after since, for
+// incremental polling.
+func (s *TaskService) ListModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time) (*domain.TaskChangeSet, error) {
+	// Taken before querying so changes committed while the query runs are
+	// picked up by the next poll rather than lost.
+	serverTime := time.Now()
+
+	tasks, err := s.taskRepo.ListModifiedSince(ctx, userID, since, maxModifiedTasks)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.ListModifiedSince: %w", err)
+	}
+
+	set := &domain.TaskChangeSet{Tasks: tasks, ServerTime: serverTime}
+	if len(tasks) == maxModifiedTasks {
+		set.HasMore = true
+		set.ServerTime = tasks[len(tasks)-1].UpdatedAt
+	}
+	return set, nil
+}
+
+// Fuzzy search defaults. A threshold of 0.3 matches pg_trgm's own default.
+const (
+	DefaultFuzzyThreshold = 0.3
+	maxFuzzyResults       = 20
+)
+
+// FuzzyFind returns the user's tasks whose titles resemble q despite typos,
+// for "did you mean" suggestions and duplicate detection.
+func (s *TaskService) FuzzyFind(ctx context.Context, userID uuid.UUID, q string, threshold float64, limit int) ([]*domain.TaskMatch, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return nil, fmt.Errorf("taskService.FuzzyFind: q is required: %w", domain.ErrValidation)
+	}
+	if threshold <= 0 || threshold > 1 {
+		return nil, fmt.Errorf("taskService.FuzzyFind: threshold must be in (0, 1]: %w", domain.ErrValidation)
+	}
+	if limit <= 0 || limit > maxFuzzyResults {
+		limit = maxFuzzyResults
+	}
+
+	matches, err := s.taskRepo.FuzzyFind(ctx, userID, q, threshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.FuzzyFind: %w", err)
+	}
+	return matches, nil
+}
+
 // Update applies partial updates to a task, enforcing ownership.
 func (s *TaskService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateTaskRequest) (*domain.Task, error) {
+	task, _, err := s.UpdateWithChanges(ctx, id, userID, req)
+	return task, err
+}
+
+// UpdateWithChanges is Update that also reports which fields the request
+// actually changed, diffed against the stored task before it is persisted.
+func (s *TaskService) UpdateWithChanges(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateTaskRequest) (*domain.Task, domain.TaskChanges, error) {
 	task, err := s.GetByID(ctx, id, userID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if err := checkVersion(task.Version, req.IfVersion); err != nil {
+		return nil, nil, err
 	}
+	before := *task
 
 	// Validate project ownership if changing project
 	if req.ProjectID != nil {
-		if err := s.assertProjectOwner(ctx, *req.ProjectID, userID); err != nil {
-			return nil, err
+		if err := s.assertProjectAccess(ctx, *req.ProjectID, userID); err != nil {
+			return nil, nil, err
+		}
+		// A member editing someone else's task may only move it where its
+		// owner can still reach it, or the owner would lose their own task.
+		if task.UserID != userID {
+			if err := s.assertProjectAccess(ctx, *req.ProjectID, task.UserID); err != nil {
+				return nil, nil, err
+			}
 		}
 		task.ProjectID = req.ProjectID
 	}
@@ -104,73 +464,612 @@ func (s *TaskService) Update(ctx context.Context, id, userID uuid.UUID, req *dom
 	if req.EstimatedHours != nil {
 		task.EstimatedHours = req.EstimatedHours
 	}
+	if req.ClearEstimatedHours {
+		task.EstimatedHours = nil
+	}
+	if req.ActualHours != nil {
+		task.ActualHours = req.ActualHours
+	}
+	if req.ClearActualHours {
+		task.ActualHours = nil
+	}
+	if req.Energy != nil {
+		task.Energy = req.Energy
+	}
+	if req.ClearEnergy {
+		task.Energy = nil
+	}
+	if err := setTaskContext(task, req.Context); err != nil {
+		return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+	}
+	if req.ClearContext {
+		task.Context = nil
+	}
 	if req.DueDate != nil {
+		if req.DueExpr != nil && *req.DueExpr != "" {
+			return nil, nil, fmt.Errorf("taskService.Update: set due_date or due_expr, not both: %w", domain.ErrValidation)
+		}
 		task.DueDate = req.DueDate
+		task.DueExpr = nil
+	}
+	if req.ClearDueDate {
+		task.DueDate = nil
+		task.DueExpr = nil
+	}
+	if req.StartDate != nil {
+		task.StartDate = req.StartDate
+	}
+	if req.ClearStartDate {
+		task.StartDate = nil
+	}
+	if req.ScheduledAt != nil {
+		task.ScheduledAt = req.ScheduledAt
+	}
+	if req.ClearSchedule {
+		task.ScheduledAt, task.ScheduledDuration = nil, nil
+	}
+	if err := s.setSchedule(task, req.ScheduledDuration); err != nil {
+		return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+	}
+	if req.Recurrence != nil {
+		task.Recurrence = req.Recurrence
+	}
+	if req.ClearRecurrence {
+		task.Recurrence = nil
+	}
+	if req.DueExpr != nil {
+		if expr := strings.TrimSpace(*req.DueExpr); expr == "" {
+			task.DueExpr = nil
+		} else {
+			task.DueExpr = &expr
+			if err := task.ResolveDueExpr(occurrenceStart(task), s.location(ctx, userID)); err != nil {
+				return nil, nil, fmt.Errorf("taskService.Update: %v: %w", err, domain.ErrValidation)
+			}
+		}
+	}
+	if req.NoEscalation != nil {
+		task.NoEscalation = *req.NoEscalation
+	}
+	if task.Recurrence != nil {
+		if task.DueDate == nil {
+			return nil, nil, fmt.Errorf("taskService.Update: a recurring task needs a due date: %w", domain.ErrValidation)
+		}
+		switch {
+		case task.DueExpr != nil && (req.Recurrence != nil || req.DueExpr != nil):
+			// With a due expression the series counts occurrence starts.
+			start := occurrenceStart(task)
+			r := *task.Recurrence
+			r.Anchor, r.Start = start, &start
+			task.Recurrence = &r
+		case task.DueExpr == nil && (req.Recurrence != nil || before.DueDate == nil || !task.DueDate.Equal(*before.DueDate)):
+			// A new series, or a due date moved by hand, counts from the due date.
+			r := *task.Recurrence
+			r.Anchor, r.Start = *task.DueDate, nil
+			task.Recurrence = &r
+		}
 	}
 
+	completed := false
 	if req.Status != nil && *req.Status != task.Status {
+		if *req.Status == domain.TaskStatusDone {
+			for _, g := range s.guards {
+				if err := g.CheckCompletion(ctx, task); err != nil {
+					return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+				}
+			}
+		}
 		task.Status = *req.Status
+		completed = task.Status == domain.TaskStatusDone
+		// A status change, including a recurring task reopening, starts
+		// its age afresh.
+		task.StatusChangedAt = time.Now()
+		task.AgePoints = 0
 		// Set completed_at when marking as done
 		if task.Status == domain.TaskStatusDone {
 			now := time.Now()
 			task.CompletedAt = &now
+			// Tracked time stands in for the actual hours unless given. A
+			// recurring task's total spans all its occurrences, so it is
+			// only used for one-off tasks.
+			if task.ActualHours == nil && task.TrackedSeconds > 0 && task.Recurrence == nil {
+				hours := math.Round(float64(task.TrackedSeconds)/36) / 100
+				task.ActualHours = &hours
+			}
 		} else {
 			task.CompletedAt = nil
 		}
 	}
 
+	// Completing a recurring task completes the current occurrence: the
+	// completion event carries the task as it was done, while the task
+	// itself reopens for the next one.
+	completedTask := task
+	if completed && task.Recurrence != nil {
+		done := *task
+		completedTask = &done
+		task.ActualHours = nil
+		if task.DueExpr != nil && task.Recurrence.Start != nil {
+			if err := task.RollForwardExpr(*done.CompletedAt, s.location(ctx, userID)); err != nil {
+				s.log.WithError(err).WithField("task_id", task.ID).Warn("due expression not resolved; following the series")
+				task.RollForward(*done.CompletedAt)
+			}
+		} else {
+			task.RollForward(*done.CompletedAt)
+		}
+		due := s.adjustDueDate(ctx, userID, *task.DueDate)
+		task.DueDate = &due
+		// The next occurrence starts, and is scheduled, as far ahead of its
+		// due date as this one was.
+		if done.StartDate != nil {
+			start := task.DueDate.Add(done.StartDate.Sub(*done.DueDate))
+			task.StartDate = &start
+		}
+		if done.ScheduledAt != nil {
+			at := task.DueDate.Add(done.ScheduledAt.Sub(*done.DueDate))
+			task.ScheduledAt = &at
+		}
+	}
+	if err := s.checkStartDate(ctx, task); err != nil {
+		return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+	}
+	// Only a slot booked by hand is checked: the next occurrence of a
+	// recurring task is placed regardless.
+	if (req.ScheduledAt != nil || req.ScheduledDuration != nil) && !req.AllowOverlap {
+		if err := s.checkSchedule(ctx, task); err != nil {
+			return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+		}
+	}
+
 	task.SmartScore = task.CalculateSmartScore()
 	task.UpdatedAt = time.Now()
+	changes := domain.DiffTasks(&before, task)
 
-	if err := s.taskRepo.Update(ctx, task); err != nil {
-		return nil, fmt.Errorf("taskService.Update: %w", err)
+	if _, ok := changes["description"]; ok && before.Description != "" {
+		for _, a := range s.archivers {
+			if err := a.ArchiveDescription(ctx, task, before.Description); err != nil {
+				return nil, nil, fmt.Errorf("taskService.Update: %w", err)
+			}
+		}
+	}
+	if err := s.taskRepo.Update(ctx, task, req.IfVersion); err != nil {
+		return nil, nil, fmt.Errorf("taskService.Update: %w", err)
 	}
 
-	return task, nil
+	s.publish(ctx, domain.TaskUpdated{Task: task})
+	if completed {
+		s.publish(ctx, domain.TaskCompleted{Task: completedTask})
+	}
+	if _, moved := changes["project_id"]; moved {
+		s.publish(ctx, domain.TaskMoved{Task: task})
+	}
+	return task, changes, nil
 }
 
-// Delete soft-deletes a task, enforcing ownership.
-func (s *TaskService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+// Delete soft-deletes a task, enforcing ownership. A non-nil ifVersion
+// rejects the delete if the task has changed since that version.
+func (s *TaskService) Delete(ctx context.Context, id, userID uuid.UUID, ifVersion *int64) error {
 	task, err := s.GetByID(ctx, id, userID)
 	if err != nil {
 		return err
 	}
+	if err := checkVersion(task.Version, ifVersion); err != nil {
+		return err
+	}
 
-	if err := s.taskRepo.Delete(ctx, task.ID); err != nil {
+	if err := s.taskRepo.Delete(ctx, task.ID, ifVersion); err != nil {
 		return fmt.Errorf("taskService.Delete: %w", err)
 	}
 
+	s.publish(ctx, domain.TaskDeleted{Task: task})
 	return nil
 }
 
-// RefreshSmartScores recalculates smart scores for all pending user tasks.
-// Intended to be called periodically (e.g. via a cron job).
+// Archive hides a task from lists without deleting it, enforcing
+// ownership. Archiving an archived task changes nothing.
+func (s *TaskService) Archive(ctx context.Context, id, userID uuid.UUID) (*domain.Task, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ArchivedAt != nil {
+		return task, nil
+	}
+
+	now := time.Now()
+	if err := s.taskRepo.SetArchived(ctx, task.ID, &now); err != nil {
+		return nil, fmt.Errorf("taskService.Archive: %w", err)
+	}
+	task.ArchivedAt, task.UpdatedAt = &now, now
+
+	s.publish(ctx, domain.TaskUpdated{Task: task})
+	return task, nil
+}
+
+// Pin keeps a task above all others in lists, enforcing ownership. Pinning
+// a pinned task is a no-op.
+func (s *TaskService) Pin(ctx context.Context, id, userID uuid.UUID) (*domain.Task, error) {
+	return s.setPinned(ctx, id, userID, true)
+}
+
+// Unpin returns a task to its place by score or position, enforcing
+// ownership.
+func (s *TaskService) Unpin(ctx context.Context, id, userID uuid.UUID) (*domain.Task, error) {
+	return s.setPinned(ctx, id, userID, false)
+}
+
+func (s *TaskService) setPinned(ctx context.Context, id, userID uuid.UUID, pinned bool) (*domain.Task, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.Pinned == pinned {
+		return task, nil
+	}
+
+	if err := s.taskRepo.SetPinned(ctx, task.ID, pinned); err != nil {
+		return nil, fmt.Errorf("taskService.setPinned: %w", err)
+	}
+	task.Pinned, task.UpdatedAt = pinned, time.Now()
+
+	s.publish(ctx, domain.TaskUpdated{Task: task})
+	return task, nil
+}
+
+// Unarchive brings an archived task back into lists, enforcing ownership.
+func (s *TaskService) Unarchive(ctx context.Context, id, userID uuid.UUID) (*domain.Task, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if task.ArchivedAt == nil {
+		return task, nil
+	}
+
+	if err := s.taskRepo.SetArchived(ctx, task.ID, nil); err != nil {
+		return nil, fmt.Errorf("taskService.Unarchive: %w", err)
+	}
+	task.ArchivedAt, task.UpdatedAt = nil, time.Now()
+
+	s.publish(ctx, domain.TaskUpdated{Task: task})
+	return task, nil
+}
+
+// Position moves a task in the user's manual order to just after another
+// task, or to the top when req.AfterID is nil, enforcing ownership of both.
+func (s *TaskService) Position(ctx context.Context, id, userID uuid.UUID, req *domain.PositionTaskRequest) (*domain.Task, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.AfterID != nil && *req.AfterID == task.ID {
+		return nil, fmt.Errorf("taskService.Position: a task cannot follow itself: %w", domain.ErrValidation)
+	}
+
+	order, err := s.sortOrderAfter(ctx, task, req.AfterID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.taskRepo.SetSortOrder(ctx, task.ID, order); err != nil {
+		return nil, fmt.Errorf("taskService.Position: %w", err)
+	}
+	task.SortOrder, task.UpdatedAt = order, time.Now()
+
+	s.publish(ctx, domain.TaskUpdated{Task: task})
+	return task, nil
+}
+
+// sortOrderAfter picks a sort order between the anchor task and the one
+// following it, renumbering the user's order once if the gap has run out.
+func (s *TaskService) sortOrderAfter(ctx context.Context, task *domain.Task, afterID *uuid.UUID) (float64, error) {
+	for renumbered := false; ; renumbered = true {
+		var lo *float64
+		if afterID != nil {
+			anchor, err := s.GetByID(ctx, *afterID, task.UserID)
+			if err != nil {
+				return 0, err
+			}
+			lo = &anchor.SortOrder
+		}
+		hi, err := s.taskRepo.NextSortOrder(ctx, task.UserID, task.ID, lo)
+		if err != nil {
+			return 0, fmt.Errorf("taskService.Position: %w", err)
+		}
+
+		var order float64
+		switch {
+		case lo == nil && hi == nil:
+			return task.SortOrder, nil
+		case lo == nil:
+			order = *hi - domain.TaskSortGap
+		case hi == nil:
+			order = *lo + domain.TaskSortGap
+		default:
+			order = *lo + (*hi-*lo)/2
+		}
+		if (lo == nil || order > *lo) && (hi == nil || order < *hi) {
+			return order, nil
+		}
+		if renumbered {
+			return 0, fmt.Errorf("taskService.Position: no room left in the manual order")
+		}
+		if err := s.taskRepo.RenumberSortOrder(ctx, task.UserID); err != nil {
+			return 0, fmt.Errorf("taskService.Position: %w", err)
+		}
+	}
+}
+
+// MoveTasks moves the user's tasks to another project (or out of any project
+// when projectID is nil). Tasks already there, or not owned by the user, are skipped.
+func (s *TaskService) MoveTasks(ctx context.Context, userID uuid.UUID, req *domain.MoveTasksRequest, dryRun bool) (*domain.OperationResult, error) {
+	if req.ProjectID != nil {
+		if err := s.assertProjectAccess(ctx, *req.ProjectID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	var moved []*domain.Task
+	return operation{
+		name: "move_tasks",
+		plan: func(ctx context.Context) (map[string][]uuid.UUID, error) {
+			tasks, err := s.taskRepo.FindByIDs(ctx, userID, req.TaskIDs)
+			if err != nil {
+				return nil, fmt.Errorf("taskService.MoveTasks: %w", err)
+			}
+			ids := []uuid.UUID{}
+			for _, t := range tasks {
+				if !sameProject(t.ProjectID, req.ProjectID) {
+					ids = append(ids, t.ID)
+					moved = append(moved, t)
+				}
+			}
+			return map[string][]uuid.UUID{"tasks": ids}, nil
+		},
+		apply: func(ctx context.Context, affected map[string][]uuid.UUID) error {
+			if err := s.taskRepo.SetProject(ctx, affected["tasks"], req.ProjectID); err != nil {
+				return err
+			}
+			for _, t := range moved {
+				t.ProjectID = req.ProjectID
+				s.publish(ctx, domain.TaskUpdated{Task: t})
+				s.publish(ctx, domain.TaskMoved{Task: t})
+			}
+			return nil
+		},
+	}.run(ctx, dryRun)
+}
+
+func sameProject(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// RefreshSmartScores recalculates smart scores for all pending user tasks
+// in a single set-based update.
 func (s *TaskService) RefreshSmartScores(ctx context.Context, userID uuid.UUID) error {
-	pending := domain.TaskStatusTodo
-	filter := domain.TaskFilter{Status: &pending}
-	tasks, _, err := s.taskRepo.List(ctx, userID, filter, 1, 1000)
+	n, err := s.taskRepo.RecalculateScores(ctx, userID)
 	if err != nil {
-		return fmt.Errorf("taskService.RefreshSmartScores list: %w", err)
+		return fmt.Errorf("taskService.RefreshSmartScores: %w", err)
 	}
+	s.log.WithFields(logrus.Fields{"user_id": userID, "updated": n}).Debug("smart scores refreshed")
+	return nil
+}
 
-	for _, task := range tasks {
-		task.SmartScore = task.CalculateSmartScore()
-		task.UpdatedAt = time.Now()
-		if err := s.taskRepo.Update(ctx, task); err != nil {
-			s.log.WithError(err).WithField("task_id", task.ID).Warn("failed to update smart score")
+// ScoreStarted gives tasks whose start date has just passed their smart
+// score, which stays zero while they are deferred. Intended to be run by the
+// scheduler.
+func (s *TaskService) ScoreStarted(ctx context.Context) error {
+	n, err := s.taskRepo.ScoreStarted(ctx)
+	if err != nil {
+		return fmt.Errorf("taskService.ScoreStarted: %w", err)
+	}
+	if n > 0 {
+		s.log.WithField("tasks", n).Info("scored started tasks")
+	}
+	return nil
+}
+
+// ApplyAging adds score to tasks that have sat in todo for longer than the
+// policy allows; a status change clears it again. It does nothing when the
+// policy does not score age.
+func (s *TaskService) ApplyAging(ctx context.Context, policy domain.AgingPolicy) error {
+	if !policy.ScoresAge() {
+		return nil
+	}
+	var maxPoints *float64
+	if policy.MaxPoints > 0 {
+		maxPoints = &policy.MaxPoints
+	}
+	n, err := s.taskRepo.ApplyAging(ctx, policy.After, policy.PointsPerDay, maxPoints)
+	if err != nil {
+		return fmt.Errorf("taskService.ApplyAging: %w", err)
+	}
+	if n > 0 {
+		s.log.WithField("tasks", n).Info("aged todo tasks")
+	}
+	return nil
+}
+
+// FlagEffortExceeded flags in-progress tasks left untouched for longer than
+// their estimate and returns those newly flagged, at most limit of them.
+func (s *TaskService) FlagEffortExceeded(ctx context.Context, limit int) ([]*domain.Task, error) {
+	tasks, err := s.taskRepo.FlagEffortExceeded(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.FlagEffortExceeded: %w", err)
+	}
+	return tasks, nil
+}
+
+// setSchedule applies a requested slot length to the task's time slot,
+// defaulting it when the task is newly scheduled.
+func (s *TaskService) setSchedule(task *domain.Task, duration *int) error {
+	if task.ScheduledAt == nil {
+		if duration != nil {
+			return fmt.Errorf("scheduled_duration needs scheduled_at: %w", domain.ErrValidation)
 		}
+		return nil
+	}
+	switch {
+	case duration != nil:
+		task.ScheduledDuration = duration
+	case task.ScheduledDuration == nil:
+		minutes := domain.DefaultScheduledDuration
+		task.ScheduledDuration = &minutes
 	}
+	return nil
+}
 
+// setTaskContext stores a requested context on the task, normalized so
+// "@Phone" and "phone" match. An empty context clears it; nil leaves it.
+func setTaskContext(task *domain.Task, raw *string) error {
+	if raw == nil {
+		return nil
+	}
+	c := domain.NormalizeTaskContext(*raw)
+	if c == "" {
+		task.Context = nil
+		return nil
+	}
+	if strings.ContainsAny(c, " \t\n@,") {
+		return fmt.Errorf("context must be a single word such as @phone: %w", domain.ErrValidation)
+	}
+	task.Context = &c
 	return nil
 }
 
-func (s *TaskService) assertProjectOwner(ctx context.Context, projectID, userID uuid.UUID) error {
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+// maxScheduleConflicts caps the overlapping slots looked up for a task; one
+// besides the task itself is enough to refuse it.
+const maxScheduleConflicts = 2
+
+// checkSchedule refuses a time slot overlapping that of another open task.
+// Finished tasks keep their slot as a record and never conflict.
+func (s *TaskService) checkSchedule(ctx context.Context, task *domain.Task) error {
+	end, ok := task.ScheduledEnd()
+	if !ok || task.Status == domain.TaskStatusDone {
+		return nil
+	}
+	others, err := s.taskRepo.ListScheduled(ctx, task.UserID, *task.ScheduledAt, end, maxScheduleConflicts)
 	if err != nil {
 		return err
 	}
-	if project.UserID != userID {
-		return domain.ErrForbidden
+	for _, o := range others {
+		if o.ID != task.ID {
+			return fmt.Errorf("slot overlaps %q at %s: %w", o.Title, o.ScheduledAt.Format(time.RFC3339), domain.ErrScheduleConflict)
+		}
+	}
+	return nil
+}
+
+// checkStartDate rejects a start date on or after the task's deadline.
+func (s *TaskService) checkStartDate(ctx context.Context, task *domain.Task) error {
+	if task.StartDate == nil || task.DueDate == nil {
+		return nil
+	}
+	if !task.StartDate.Before(task.Deadline(s.location(ctx, task.UserID))) {
+		return fmt.Errorf("start_date must be before the due date: %w", domain.ErrValidation)
 	}
 	return nil
 }
+
+// location returns the user's time zone from the UserLocator, or UTC when
+// there is none or it fails.
+func (s *TaskService) location(ctx context.Context, userID uuid.UUID) *time.Location {
+	if s.locator == nil {
+		return time.UTC
+	}
+	loc, err := s.locator.Location(ctx, userID)
+	if err != nil {
+		s.log.WithError(err).WithField("user_id", userID).Warn("user time zone unavailable; using UTC")
+		return time.UTC
+	}
+	return loc
+}
+
+// occurrenceStart is what a due expression resolves from: the start of the
+// current occurrence of a recurring task, otherwise the task's creation.
+func occurrenceStart(task *domain.Task) time.Time {
+	if task.Recurrence != nil && task.Recurrence.Start != nil {
+		return *task.Recurrence.Start
+	}
+	return task.CreatedAt
+}
+
+// adjustDueDate runs the registered DueDateAdjusters. Like defaults, a
+// failing one leaves the date as it was rather than blocking the write.
+func (s *TaskService) adjustDueDate(ctx context.Context, userID uuid.UUID, due time.Time) time.Time {
+	for _, a := range s.adjusters {
+		adjusted, err := a.AdjustDueDate(ctx, userID, due)
+		if err != nil {
+			s.log.WithError(err).WithField("user_id", userID).Warn("due date not adjusted")
+			continue
+		}
+		due = adjusted
+	}
+	return due
+}
+
+// checkVersion rejects a write made against a version other than the
+// stored one; a nil want skips the check. It only saves a doomed write: the
+// repositories compare the version again as they write.
+func checkVersion(stored int64, want *int64) error {
+	if want != nil && *want != stored {
+		return domain.ErrPreconditionFailed
+	}
+	return nil
+}
+
+func (s *TaskService) publish(ctx context.Context, e domain.TaskChange) {
+	s.events.Publish(ctx, e)
+}
+
+func (l *createLookups) parent(id uuid.UUID) (*domain.Task, bool) {
+	if l == nil {
+		return nil, false
+	}
+	parent, ok := l.parents[id]
+	return parent, ok
+}
+
+func (l *createLookups) setParent(parent *domain.Task) {
+	if l != nil {
+		l.parents[parent.ID] = parent
+	}
+}
+
+// project returns whether the project was checked already, and the result.
+func (l *createLookups) project(id uuid.UUID) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	err, ok := l.projects[id]
+	return ok, err
+}
+
+func (l *createLookups) setProject(id uuid.UUID, err error) {
+	if l != nil {
+		l.projects[id] = err
+	}
+}
+
+// assertProjectAccess checks that userID owns the project or is a member of
+// it.
+func (s *TaskService) assertProjectAccess(ctx context.Context, projectID, userID uuid.UUID) error {
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project.UserID == userID {
+		return nil
+	}
+	if s.members != nil {
+		ok, err := s.members.IsMember(ctx, projectID, userID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	return domain.ErrForbidden
+}
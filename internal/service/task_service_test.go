@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"math"
 	"testing"
 	"time"
 
@@ -20,6 +21,11 @@ type mockTaskRepo struct{ mock.Mock }
 func (m *mockTaskRepo) Create(ctx context.Context, task *domain.Task) error {
 	return m.Called(ctx, task).Error(0)
 }
+
+func (m *mockTaskRepo) CreateBatch(ctx context.Context, tasks []*domain.Task) error {
+	return m.Called(ctx, tasks).Error(0)
+}
+
 func (m *mockTaskRepo) FindByID(ctx context.Context, id uuid.UUID) (*domain.Task, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -31,21 +37,126 @@ func (m *mockTaskRepo) List(ctx context.Context, userID uuid.UUID, filter domain
 	args := m.Called(ctx, userID, filter, page, limit)
 	return args.Get(0).([]*domain.Task), args.Int(1), args.Error(2)
 }
-func (m *mockTaskRepo) Update(ctx context.Context, task *domain.Task) error {
-	return m.Called(ctx, task).Error(0)
+func (m *mockTaskRepo) Stream(ctx context.Context, userID uuid.UUID, filter domain.TaskFilter, fn func(*domain.Task) error) error {
+	args := m.Called(ctx, userID, filter)
+	tasks, _ := args.Get(0).([]*domain.Task)
+	for _, t := range tasks {
+		if err := fn(t); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+func (m *mockTaskRepo) Update(ctx context.Context, task *domain.Task, ifVersion *int64) error {
+	return m.Called(ctx, task, ifVersion).Error(0)
 }
-func (m *mockTaskRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	return m.Called(ctx, id).Error(0)
+func (m *mockTaskRepo) Delete(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	return m.Called(ctx, id, ifVersion).Error(0)
 }
 func (m *mockTaskRepo) CountByUserID(ctx context.Context, userID uuid.UUID) (int, error) {
 	args := m.Called(ctx, userID)
 	return args.Int(0), args.Error(1)
 }
-func (m *mockTaskRepo) FindOverdue(ctx context.Context, userID uuid.UUID) ([]*domain.Task, error) {
+func (m *mockTaskRepo) FindOverdue(ctx context.Context, userID uuid.UUID, after *domain.OverdueCursor, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, after, limit)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) FindByIDs(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) SetProject(ctx context.Context, ids []uuid.UUID, projectID *uuid.UUID) error {
+	return m.Called(ctx, ids, projectID).Error(0)
+}
+
+func (m *mockTaskRepo) FuzzyFind(ctx context.Context, userID uuid.UUID, q string, threshold float64, limit int) ([]*domain.TaskMatch, error) {
+	args := m.Called(ctx, userID, q, threshold, limit)
+	return args.Get(0).([]*domain.TaskMatch), args.Error(1)
+}
+
+func (m *mockTaskRepo) ListRecentlyModified(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, limit)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) ListModifiedSince(ctx context.Context, userID uuid.UUID, since time.Time, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, since, limit)
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	return m.Called(ctx, id, archivedAt).Error(0)
+}
+
+func (m *mockTaskRepo) SetPinned(ctx context.Context, id uuid.UUID, pinned bool) error {
+	return m.Called(ctx, id, pinned).Error(0)
+}
+
+func (m *mockTaskRepo) NextSortOrder(ctx context.Context, userID, excludeID uuid.UUID, after *float64) (*float64, error) {
+	args := m.Called(ctx, userID, excludeID, after)
+	if v := args.Get(0); v != nil {
+		return v.(*float64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *mockTaskRepo) SetSortOrder(ctx context.Context, id uuid.UUID, sortOrder float64) error {
+	return m.Called(ctx, id, sortOrder).Error(0)
+}
+
+func (m *mockTaskRepo) RenumberSortOrder(ctx context.Context, userID uuid.UUID) error {
+	return m.Called(ctx, userID).Error(0)
+}
+
+func (m *mockTaskRepo) RecalculateScores(ctx context.Context, userID uuid.UUID) (int, error) {
 	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockTaskRepo) ListAgenda(ctx context.Context, userID uuid.UUID, dueBefore time.Time, topScored, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, dueBefore, topScored, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) ListSuitable(ctx context.Context, userID uuid.UUID, energy *domain.TaskEnergy, taskContext string, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, energy, taskContext, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) ListScheduled(ctx context.Context, userID uuid.UUID, from, to time.Time, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, userID, from, to, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Task), args.Error(1)
+}
+
+func (m *mockTaskRepo) FlagEffortExceeded(ctx context.Context, limit int) ([]*domain.Task, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
 	return args.Get(0).([]*domain.Task), args.Error(1)
 }
 
+func (m *mockTaskRepo) ScoreStarted(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *mockTaskRepo) ApplyAging(ctx context.Context, after time.Duration, perDay float64, maxPoints *float64) (int, error) {
+	args := m.Called(ctx, after, perDay, maxPoints)
+	return args.Int(0), args.Error(1)
+}
+
 type mockProjectRepo struct{ mock.Mock }
 
 func (m *mockProjectRepo) Create(ctx context.Context, p *domain.Project) error {
@@ -62,11 +173,30 @@ func (m *mockProjectRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([
 	args := m.Called(ctx, userID)
 	return args.Get(0).([]*domain.Project), args.Error(1)
 }
-func (m *mockProjectRepo) Update(ctx context.Context, p *domain.Project) error {
-	return m.Called(ctx, p).Error(0)
+func (m *mockProjectRepo) ListFields(ctx context.Context, userID uuid.UUID, fields domain.Fields, archived bool) ([]*domain.Project, error) {
+	args := m.Called(ctx, userID, fields, archived)
+	return args.Get(0).([]*domain.Project), args.Error(1)
+}
+func (m *mockProjectRepo) Update(ctx context.Context, p *domain.Project, ifVersion *int64) error {
+	return m.Called(ctx, p, ifVersion).Error(0)
+}
+func (m *mockProjectRepo) Delete(ctx context.Context, id uuid.UUID, strategy domain.ProjectDeleteStrategy, ifVersion *int64) (int, error) {
+	args := m.Called(ctx, id, strategy, ifVersion)
+	return args.Int(0), args.Error(1)
+}
+func (m *mockProjectRepo) SetArchived(ctx context.Context, id uuid.UUID, archivedAt *time.Time) error {
+	return m.Called(ctx, id, archivedAt).Error(0)
 }
-func (m *mockProjectRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	return m.Called(ctx, id).Error(0)
+func (m *mockProjectRepo) Duplicate(ctx context.Context, sourceID uuid.UUID, p *domain.Project, withTasks bool, shiftDays int) (int, error) {
+	args := m.Called(ctx, sourceID, p, withTasks, shiftDays)
+	return args.Int(0), args.Error(1)
+}
+func (m *mockProjectRepo) Stats(ctx context.Context, projectID, userID uuid.UUID) (*domain.ProjectStats, error) {
+	args := m.Called(ctx, projectID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProjectStats), args.Error(1)
 }
 
 // --- Tests ---
@@ -74,7 +204,7 @@ func (m *mockProjectRepo) Delete(ctx context.Context, id uuid.UUID) error {
 func newTaskService(taskRepo domain.TaskRepository, projectRepo domain.ProjectRepository) *service.TaskService {
 	log := logrus.New()
 	log.SetLevel(logrus.FatalLevel) // silence logs in tests
-	return service.NewTaskService(taskRepo, projectRepo, log)
+	return service.NewTaskService(taskRepo, projectRepo, &fakeTimeEntryRepo{}, log)
 }
 
 func TestTaskService_Create_Success(t *testing.T) {
@@ -101,6 +231,24 @@ func TestTaskService_Create_Success(t *testing.T) {
 	taskRepo.AssertExpectations(t)
 }
 
+func TestTaskService_Create_NormalizesContext(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("Create", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+	ctx := context.Background()
+
+	raw := "@Errand"
+	task, err := svc.Create(ctx, uuid.New(), &domain.CreateTaskRequest{Title: "Buy stamps", Priority: domain.TaskPriorityLow, Context: &raw})
+	assert.NoError(t, err)
+	if assert.NotNil(t, task.Context) {
+		assert.Equal(t, "errand", *task.Context)
+	}
+
+	raw = "@post office"
+	_, err = svc.Create(ctx, uuid.New(), &domain.CreateTaskRequest{Title: "Buy stamps", Priority: domain.TaskPriorityLow, Context: &raw})
+	assert.ErrorIs(t, err, domain.ErrValidation)
+}
+
 func TestTaskService_Create_WithProject_NotOwner(t *testing.T) {
 	taskRepo := &mockTaskRepo{}
 	projectRepo := &mockProjectRepo{}
@@ -125,6 +273,233 @@ func TestTaskService_Create_WithProject_NotOwner(t *testing.T) {
 	taskRepo.AssertNotCalled(t, "Create")
 }
 
+type fixedLocator struct{ loc *time.Location }
+
+func (f fixedLocator) Location(context.Context, uuid.UUID) (*time.Location, error) { return f.loc, nil }
+
+func TestTaskService_DueExpr(t *testing.T) {
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	userID := uuid.New()
+
+	t.Run("resolved on create in the user's zone", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+		svc.UseLocator(fixedLocator{jkt})
+
+		expr := " End of month "
+		task, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "Invoice", Priority: domain.TaskPriorityMedium, DueExpr: &expr})
+		assert.NoError(t, err)
+		assert.Equal(t, "End of month", *task.DueExpr)
+		local := task.DueDate.In(jkt)
+		assert.Equal(t, 1, local.AddDate(0, 0, 1).Day(), "the last day of the month")
+		assert.Equal(t, 0, local.Hour())
+	})
+
+	t.Run("rejected when invalid or alongside a date", func(t *testing.T) {
+		svc := newTaskService(&mockTaskRepo{}, &mockProjectRepo{})
+		bad := "whenever"
+		_, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "X", Priority: domain.TaskPriorityLow, DueExpr: &bad})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+
+		expr, due := "tomorrow", time.Now()
+		_, err = svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "X", Priority: domain.TaskPriorityLow, DueExpr: &expr, DueDate: &due})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("a date set by hand replaces it", func(t *testing.T) {
+		expr := "in 3 days"
+		created := time.Now().Add(-time.Hour)
+		due := created.AddDate(0, 0, 3)
+		existing := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Call", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityLow, DueDate: &due, DueExpr: &expr, CreatedAt: created}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, existing.ID).Return(existing, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		later := due.AddDate(0, 0, 1)
+		_, changes, err := svc.UpdateWithChanges(context.Background(), existing.ID, userID, &domain.UpdateTaskRequest{DueDate: &later})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.FieldChange{Old: "in 3 days", New: nil}, changes["due_expr"])
+	})
+}
+
+func TestTaskService_StartDate(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("a deferred task scores nothing", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		start := time.Now().Add(48 * time.Hour)
+		task, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "File taxes", Priority: domain.TaskPriorityHigh, StartDate: &start})
+		assert.NoError(t, err)
+		assert.Zero(t, task.SmartScore)
+	})
+
+	t.Run("rejected on or after the due date", func(t *testing.T) {
+		svc := newTaskService(&mockTaskRepo{}, &mockProjectRepo{})
+		due := time.Now().Add(24 * time.Hour)
+		start := due.Add(time.Hour)
+		_, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "X", Priority: domain.TaskPriorityLow, DueDate: &due, StartDate: &start})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+
+	t.Run("keeps its lead on the next occurrence", func(t *testing.T) {
+		due := time.Now().Add(-time.Hour).Truncate(time.Second)
+		start := due.Add(-6 * time.Hour)
+		task := &domain.Task{
+			ID: uuid.New(), UserID: userID, Title: "Water plants", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium,
+			DueDate: &due, StartDate: &start, Recurrence: &domain.Recurrence{Frequency: domain.RecurDaily, Anchor: due},
+		}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		done := domain.TaskStatusDone
+		updated, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Status: &done})
+		assert.NoError(t, err)
+		assert.Equal(t, due.AddDate(0, 0, 1), *updated.DueDate)
+		assert.Equal(t, start.AddDate(0, 0, 1), *updated.StartDate)
+		assert.True(t, updated.IsDeferredAt(time.Now()))
+		assert.Zero(t, updated.SmartScore)
+	})
+}
+
+func TestTaskService_Schedule(t *testing.T) {
+	userID := uuid.New()
+	slot := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	t.Run("defaults to an hour", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("ListScheduled", mock.Anything, userID, slot, slot.Add(time.Hour), 2).Return([]*domain.Task{}, nil)
+		taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		task, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "Deep work", Priority: domain.TaskPriorityMedium, ScheduledAt: &slot})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.DefaultScheduledDuration, *task.ScheduledDuration)
+	})
+
+	t.Run("refuses an overlapping slot unless allowed", func(t *testing.T) {
+		other := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Standup", Status: domain.TaskStatusTodo, ScheduledAt: &slot}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("ListScheduled", mock.Anything, userID, mock.Anything, mock.Anything, 2).Return([]*domain.Task{other}, nil)
+		taskRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		start, thirty := slot.Add(30*time.Minute), 30
+		req := &domain.CreateTaskRequest{Title: "Review", Priority: domain.TaskPriorityMedium, ScheduledAt: &start, ScheduledDuration: &thirty}
+		_, err := svc.Create(context.Background(), userID, req)
+		assert.ErrorIs(t, err, domain.ErrScheduleConflict)
+
+		req.AllowOverlap = true
+		_, err = svc.Create(context.Background(), userID, req)
+		assert.NoError(t, err)
+	})
+
+	t.Run("a duration needs a slot", func(t *testing.T) {
+		svc := newTaskService(&mockTaskRepo{}, &mockProjectRepo{})
+		thirty := 30
+		_, err := svc.Create(context.Background(), userID, &domain.CreateTaskRequest{Title: "X", Priority: domain.TaskPriorityLow, ScheduledDuration: &thirty})
+		assert.ErrorIs(t, err, domain.ErrValidation)
+	})
+}
+
+func TestTaskService_Update_ActualHours(t *testing.T) {
+	userID := uuid.New()
+	done := domain.TaskStatusDone
+
+	t.Run("derived from tracked time on completion", func(t *testing.T) {
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Draft", Status: domain.TaskStatusInProgress, Priority: domain.TaskPriorityMedium, TrackedSeconds: 5430}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		updated, changes, err := svc.UpdateWithChanges(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Status: &done})
+		assert.NoError(t, err)
+		assert.Equal(t, 1.51, *updated.ActualHours)
+		assert.Contains(t, changes, "actual_hours")
+	})
+
+	t.Run("given hours win", func(t *testing.T) {
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Draft", Status: domain.TaskStatusInProgress, Priority: domain.TaskPriorityMedium, TrackedSeconds: 5430}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		three := 3.0
+		updated, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Status: &done, ActualHours: &three})
+		assert.NoError(t, err)
+		assert.Equal(t, 3.0, *updated.ActualHours)
+	})
+}
+
+func TestTaskService_IfVersion(t *testing.T) {
+	userID := uuid.New()
+	title := "Revised"
+	stale, current := int64(2), int64(3)
+
+	t.Run("stale update is rejected", func(t *testing.T) {
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Draft", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, Version: current}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		_, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Title: &title, IfVersion: &stale})
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("current update goes through", func(t *testing.T) {
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Draft", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, Version: current}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskRepo.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		updated, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Title: &title, IfVersion: &current})
+		assert.NoError(t, err)
+		assert.Equal(t, title, updated.Title)
+	})
+
+	t.Run("the version is compared as the write happens", func(t *testing.T) {
+		// Both devices read version 3 before either writes, so both pass the
+		// early check; the repository lets only the first through.
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Draft", Status: domain.TaskStatusTodo, Priority: domain.TaskPriorityMedium, Version: current}
+		taskRepo := &mockTaskRepo{}
+		onPhone, onLaptop := *task, *task
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(&onPhone, nil).Once()
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(&onLaptop, nil).Once()
+		isCurrent := mock.MatchedBy(func(v *int64) bool { return v != nil && *v == current })
+		taskRepo.On("Update", mock.Anything, mock.Anything, isCurrent).Return(nil).Once()
+		taskRepo.On("Update", mock.Anything, mock.Anything, isCurrent).Return(domain.ErrPreconditionFailed).Once()
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		_, err := svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Title: &title, IfVersion: &current})
+		assert.NoError(t, err)
+		other := "Other"
+		_, err = svc.Update(context.Background(), task.ID, userID, &domain.UpdateTaskRequest{Title: &other, IfVersion: &current})
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		taskRepo.AssertExpectations(t)
+	})
+
+	t.Run("stale delete is rejected", func(t *testing.T) {
+		task := &domain.Task{ID: uuid.New(), UserID: userID, Version: current}
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+		err := svc.Delete(context.Background(), task.ID, userID, &stale)
+		assert.ErrorIs(t, err, domain.ErrPreconditionFailed)
+		taskRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestTaskService_Update_CompletionSetsCompletedAt(t *testing.T) {
 	taskRepo := &mockTaskRepo{}
 	projectRepo := &mockProjectRepo{}
@@ -142,7 +517,7 @@ func TestTaskService_Update_CompletionSetsCompletedAt(t *testing.T) {
 	}
 
 	taskRepo.On("FindByID", mock.Anything, taskID).Return(existing, nil)
-	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task")).Return(nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(nil)
 
 	done := domain.TaskStatusDone
 	req := &domain.UpdateTaskRequest{Status: &done}
@@ -155,6 +530,167 @@ func TestTaskService_Update_CompletionSetsCompletedAt(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), *updated.CompletedAt, 5*time.Second)
 }
 
+func TestTaskService_UpdateWithChanges_ReportsOnlyModifiedFields(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	projectRepo := &mockProjectRepo{}
+	svc := newTaskService(taskRepo, projectRepo)
+
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	existing := &domain.Task{
+		ID:       taskID,
+		UserID:   userID,
+		Title:    "Write report",
+		Status:   domain.TaskStatusTodo,
+		Priority: domain.TaskPriorityLow,
+	}
+
+	taskRepo.On("FindByID", mock.Anything, taskID).Return(existing, nil)
+	taskRepo.On("Update", mock.Anything, mock.AnythingOfType("*domain.Task"), mock.Anything).Return(nil)
+
+	high := domain.TaskPriorityHigh
+	sameTitle := "Write report"
+	hours := 2.5
+	req := &domain.UpdateTaskRequest{Priority: &high, Title: &sameTitle, EstimatedHours: &hours}
+
+	_, changes, err := svc.UpdateWithChanges(context.Background(), taskID, userID, req)
+
+	assert.NoError(t, err)
+	assert.Len(t, changes, 2)
+	assert.Equal(t, domain.FieldChange{Old: domain.TaskPriorityLow, New: domain.TaskPriorityHigh}, changes["priority"])
+	assert.Equal(t, domain.FieldChange{Old: nil, New: 2.5}, changes["estimated_hours"])
+}
+
+func TestTaskService_ListModifiedSince_CappedResultResumesFromLastChange(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	since := time.Now().Add(-time.Hour)
+	tasks := make([]*domain.Task, 500)
+	for i := range tasks {
+		tasks[i] = &domain.Task{ID: uuid.New(), UpdatedAt: since.Add(time.Duration(i+1) * time.Second)}
+	}
+	taskRepo.On("ListModifiedSince", mock.Anything, userID, since, 500).Return(tasks, nil)
+
+	set, err := svc.ListModifiedSince(context.Background(), userID, since)
+
+	assert.NoError(t, err)
+	assert.True(t, set.HasMore)
+	assert.Equal(t, tasks[499].UpdatedAt, set.ServerTime)
+}
+
+func TestTaskService_Archive_IsIdempotent(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	live := &domain.Task{ID: uuid.New(), UserID: userID, Status: domain.TaskStatusDone}
+	archivedAt := time.Now().Add(-time.Hour)
+	archived := &domain.Task{ID: uuid.New(), UserID: userID, ArchivedAt: &archivedAt}
+	taskRepo.On("FindByID", mock.Anything, live.ID).Return(live, nil)
+	taskRepo.On("FindByID", mock.Anything, archived.ID).Return(archived, nil)
+	taskRepo.On("SetArchived", mock.Anything, live.ID, mock.AnythingOfType("*time.Time")).Return(nil)
+
+	got, err := svc.Archive(context.Background(), live.ID, userID)
+	assert.NoError(t, err)
+	assert.NotNil(t, got.ArchivedAt)
+
+	got, err = svc.Archive(context.Background(), archived.ID, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, archivedAt, *got.ArchivedAt, "archiving again keeps the original time")
+	taskRepo.AssertNumberOfCalls(t, "SetArchived", 1)
+}
+
+func TestTaskService_Unarchive_ClearsArchivedAt(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	archivedAt := time.Now().Add(-time.Hour)
+	task := &domain.Task{ID: uuid.New(), UserID: userID, ArchivedAt: &archivedAt}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("SetArchived", mock.Anything, task.ID, (*time.Time)(nil)).Return(nil)
+
+	got, err := svc.Unarchive(context.Background(), task.ID, userID)
+
+	assert.NoError(t, err)
+	assert.Nil(t, got.ArchivedAt)
+	taskRepo.AssertExpectations(t)
+}
+
+func TestTaskService_PinAndUnpin(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: userID}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("SetPinned", mock.Anything, task.ID, true).Return(nil).Once()
+	taskRepo.On("SetPinned", mock.Anything, task.ID, false).Return(nil).Once()
+
+	got, err := svc.Pin(context.Background(), task.ID, userID)
+	assert.NoError(t, err)
+	assert.True(t, got.Pinned)
+	_, err = svc.Pin(context.Background(), task.ID, userID)
+	assert.NoError(t, err, "pinning again is a no-op")
+
+	got, err = svc.Unpin(context.Background(), task.ID, userID)
+	assert.NoError(t, err)
+	assert.False(t, got.Pinned)
+	taskRepo.AssertExpectations(t)
+	taskRepo.AssertNumberOfCalls(t, "SetPinned", 2)
+
+	_, err = svc.Pin(context.Background(), task.ID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestTaskService_Position_BetweenNeighbours(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: userID, SortOrder: 9000}
+	anchor := &domain.Task{ID: uuid.New(), UserID: userID, SortOrder: 1024}
+	next := 2048.0
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("FindByID", mock.Anything, anchor.ID).Return(anchor, nil)
+	taskRepo.On("NextSortOrder", mock.Anything, userID, task.ID, &anchor.SortOrder).Return(&next, nil)
+	taskRepo.On("SetSortOrder", mock.Anything, task.ID, 1536.0).Return(nil)
+
+	got, err := svc.Position(context.Background(), task.ID, userID, &domain.PositionTaskRequest{AfterID: &anchor.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1536.0, got.SortOrder)
+	taskRepo.AssertNotCalled(t, "RenumberSortOrder", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_Position_RenumbersWhenGapIsExhausted(t *testing.T) {
+	taskRepo := &mockTaskRepo{}
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	userID := uuid.New()
+	task := &domain.Task{ID: uuid.New(), UserID: userID}
+	lo := 1.0
+	hi := math.Nextafter(lo, 2)
+	renumbered := 3072.0
+	anchor := &domain.Task{ID: uuid.New(), UserID: userID, SortOrder: lo}
+	taskRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	taskRepo.On("FindByID", mock.Anything, anchor.ID).Return(anchor, nil).Once()
+	taskRepo.On("NextSortOrder", mock.Anything, userID, task.ID, mock.Anything).Return(&hi, nil).Once()
+	taskRepo.On("RenumberSortOrder", mock.Anything, userID).Return(nil)
+	taskRepo.On("FindByID", mock.Anything, anchor.ID).Return(&domain.Task{ID: anchor.ID, UserID: userID, SortOrder: 2048}, nil)
+	taskRepo.On("NextSortOrder", mock.Anything, userID, task.ID, mock.Anything).Return(&renumbered, nil)
+	taskRepo.On("SetSortOrder", mock.Anything, task.ID, 2560.0).Return(nil)
+
+	got, err := svc.Position(context.Background(), task.ID, userID, &domain.PositionTaskRequest{AfterID: &anchor.ID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2560.0, got.SortOrder)
+	taskRepo.AssertCalled(t, "RenumberSortOrder", mock.Anything, userID)
+}
+
 func TestTask_CalculateSmartScore_Overdue(t *testing.T) {
 	pastDue := time.Now().Add(-48 * time.Hour) // 2 days overdue
 	task := &domain.Task{
@@ -169,6 +705,14 @@ func TestTask_CalculateSmartScore_Overdue(t *testing.T) {
 	assert.GreaterOrEqual(t, score, 80.0, "overdue high priority task should have high score")
 }
 
+func TestTask_CalculateSmartScore_Aging(t *testing.T) {
+	task := &domain.Task{Priority: domain.TaskPriorityLow, Status: domain.TaskStatusTodo, AgePoints: 12}
+	assert.Equal(t, 22.0, task.CalculateSmartScore(), "low priority (10) + aging (12)")
+
+	task.Status = domain.TaskStatusInProgress
+	assert.Equal(t, 25.0, task.CalculateSmartScore(), "aging counts only in todo")
+}
+
 func TestTask_IsOverdue(t *testing.T) {
 	past := time.Now().Add(-1 * time.Hour)
 	future := time.Now().Add(24 * time.Hour)
@@ -202,7 +746,107 @@ func TestTask_IsOverdue(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			assert.Equal(t, tt.expected, tt.task.IsOverdue())
+			assert.Equal(t, tt.expected, tt.task.IsOverdue(time.UTC))
 		})
 	}
 }
+
+func TestTask_IsOverdueAt_FollowsUserTimezone(t *testing.T) {
+	jkt, _ := time.LoadLocation("Asia/Jakarta")
+	// 17:00 UTC on March 4 is 00:00 on March 5 in Jakarta.
+	now := time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC)
+
+	lateEvening := time.Date(2026, 3, 4, 23, 0, 0, 0, jkt) // 16:00 UTC, an hour ago
+	task := domain.Task{DueDate: &lateEvening, Status: domain.TaskStatusTodo}
+	assert.True(t, task.IsOverdueAt(now, jkt))
+
+	// A date-only due date at local midnight lasts the whole local day.
+	dateOnly := time.Date(2026, 3, 5, 0, 0, 0, 0, jkt)
+	task = domain.Task{DueDate: &dateOnly, Status: domain.TaskStatusTodo}
+	assert.False(t, task.IsOverdueAt(now.Add(23*time.Hour), jkt))
+	assert.True(t, task.IsOverdueAt(now.Add(24*time.Hour+time.Second), jkt))
+	morning := now.Add(8 * time.Hour) // 08:00 in Jakarta, 01:00 on March 5 in UTC
+	assert.True(t, task.IsDueTodayAt(morning, jkt))
+	assert.False(t, task.IsDueTodayAt(morning, time.UTC), "due on March 4 in UTC")
+
+	// The same instant is not midnight in UTC, so there it is an ordinary deadline.
+	assert.True(t, task.IsOverdueAt(now.Add(time.Second), time.UTC))
+}
+
+func TestTaskService_CreateBatch(t *testing.T) {
+	userID := uuid.New()
+	projectID := uuid.New()
+	reqs := func() []*domain.CreateTaskRequest {
+		return []*domain.CreateTaskRequest{
+			{Title: "Pack books", Priority: domain.TaskPriorityMedium, ProjectID: &projectID},
+			{Title: "Pack kitchen", Priority: domain.TaskPriorityMedium, ProjectID: &projectID},
+			{Title: "Book van", Priority: domain.TaskPriorityHigh, ProjectID: &projectID},
+		}
+	}
+
+	t.Run("checks each project once", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(nil)
+		projectRepo := &mockProjectRepo{}
+		projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID}, nil)
+		svc := newTaskService(taskRepo, projectRepo)
+
+		tasks, err := svc.CreateBatch(context.Background(), userID, reqs())
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 3)
+		projectRepo.AssertNumberOfCalls(t, "FindByID", 1)
+	})
+
+	t.Run("reports the failed chunk", func(t *testing.T) {
+		taskRepo := &mockTaskRepo{}
+		taskRepo.On("CreateBatch", mock.Anything, mock.Anything).Return(&domain.TaskBatchError{Start: 0, End: 3, Err: domain.ErrNotFound})
+		projectRepo := &mockProjectRepo{}
+		projectRepo.On("FindByID", mock.Anything, projectID).Return(&domain.Project{ID: projectID, UserID: userID}, nil)
+		svc := newTaskService(taskRepo, projectRepo)
+
+		_, err := svc.CreateBatch(context.Background(), userID, reqs())
+		var chunk *domain.TaskBatchError
+		if assert.ErrorAs(t, err, &chunk) {
+			assert.Equal(t, 3, chunk.End)
+		}
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.Contains(t, err.Error(), "tasks 1-3")
+	})
+}
+
+func TestTaskService_EachOverdue_PagesByCursor(t *testing.T) {
+	userID := uuid.New()
+	const batch = 200 // overdueBatchSize
+	due := time.Now().Add(-48 * time.Hour)
+	first := make([]*domain.Task, batch)
+	for i := range first {
+		first[i] = &domain.Task{ID: uuid.New(), UserID: userID, DueDate: &due}
+	}
+	last := first[batch-1]
+	second := []*domain.Task{{ID: uuid.New(), UserID: userID, DueDate: &due}}
+
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindOverdue", mock.Anything, userID, (*domain.OverdueCursor)(nil), batch).Return(first, nil).Once()
+	taskRepo.On("FindOverdue", mock.Anything, userID, &domain.OverdueCursor{DueDate: due, ID: last.ID}, batch).Return(second, nil).Once()
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+
+	var sizes []int
+	err := svc.EachOverdue(context.Background(), userID, func(tasks []*domain.Task) error {
+		sizes = append(sizes, len(tasks))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{batch, 1}, sizes, "a short page ends the scan")
+	taskRepo.AssertExpectations(t)
+}
+
+func TestTaskService_RefreshSmartScores_UsesSetBasedUpdate(t *testing.T) {
+	userID := uuid.New()
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("RecalculateScores", mock.Anything, userID).Return(42, nil)
+
+	svc := newTaskService(taskRepo, &mockProjectRepo{})
+	assert.NoError(t, svc.RefreshSmartScores(context.Background(), userID))
+	taskRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	taskRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// StartTimer starts tracking time against a task, enforcing ownership. A
+// user has at most one running timer; starting a second one fails with
+// ErrTimerRunning until the first is stopped.
+func (s *TaskService) StartTimer(ctx context.Context, id, userID uuid.UUID) (*domain.TimeEntry, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.timeRepo.FindRunning(ctx, userID); err == nil {
+		return nil, fmt.Errorf("taskService.StartTimer: %w", domain.ErrTimerRunning)
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return nil, fmt.Errorf("taskService.StartTimer: %w", err)
+	}
+
+	now := time.Now()
+	entry := &domain.TimeEntry{
+		ID:        uuid.New(),
+		TaskID:    task.ID,
+		UserID:    userID,
+		StartedAt: now,
+		CreatedAt: now,
+	}
+	if err := s.timeRepo.Create(ctx, entry); err != nil {
+		// The partial unique index catches a timer started concurrently.
+		if errors.Is(err, domain.ErrAlreadyExists) {
+			err = domain.ErrTimerRunning
+		}
+		return nil, fmt.Errorf("taskService.StartTimer: %w", err)
+	}
+	return entry, nil
+}
+
+// StopTimer stops the user's running timer on a task and adds the elapsed
+// time to the task's tracked_seconds. It fails with ErrTimerNotRunning when
+// no timer is running on this task.
+func (s *TaskService) StopTimer(ctx context.Context, id, userID uuid.UUID) (*domain.TimeEntry, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.timeRepo.FindRunning(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && running.TaskID != task.ID) {
+		return nil, fmt.Errorf("taskService.StopTimer: %w", domain.ErrTimerNotRunning)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("taskService.StopTimer: %w", err)
+	}
+
+	entry, err := s.timeRepo.Stop(ctx, running.ID, time.Now())
+	if err != nil {
+		// Stopped by a concurrent request in the meantime.
+		if errors.Is(err, domain.ErrNotFound) {
+			err = domain.ErrTimerNotRunning
+		}
+		return nil, fmt.Errorf("taskService.StopTimer: %w", err)
+	}
+	return entry, nil
+}
+
+// ListTimeEntries returns a task's time entries, newest first, enforcing ownership.
+func (s *TaskService) ListTimeEntries(ctx context.Context, id, userID uuid.UUID) ([]*domain.TimeEntry, error) {
+	task, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.timeRepo.ListByTaskID(ctx, task.ID)
+	if err != nil {
+		return nil, fmt.Errorf("taskService.ListTimeEntries: %w", err)
+	}
+	return entries, nil
+}
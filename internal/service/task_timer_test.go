@@ -0,0 +1,87 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTimeEntryRepo struct {
+	domain.TimeEntryRepository
+	entries []*domain.TimeEntry
+	tracked map[uuid.UUID]int64
+}
+
+func (f *fakeTimeEntryRepo) Create(_ context.Context, e *domain.TimeEntry) error {
+	f.entries = append(f.entries, e)
+	return nil
+}
+
+func (f *fakeTimeEntryRepo) FindRunning(_ context.Context, userID uuid.UUID) (*domain.TimeEntry, error) {
+	for _, e := range f.entries {
+		if e.UserID == userID && e.IsRunning() {
+			return e, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeTimeEntryRepo) Stop(_ context.Context, id uuid.UUID, at time.Time) (*domain.TimeEntry, error) {
+	for _, e := range f.entries {
+		if e.ID == id && e.IsRunning() {
+			d := int64(at.Sub(e.StartedAt).Seconds())
+			e.StoppedAt, e.DurationSeconds = &at, &d
+			if f.tracked == nil {
+				f.tracked = map[uuid.UUID]int64{}
+			}
+			f.tracked[e.TaskID] += d
+			return e, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func TestTaskService_Timer(t *testing.T) {
+	userID := uuid.New()
+	first := &domain.Task{ID: uuid.New(), UserID: userID}
+	second := &domain.Task{ID: uuid.New(), UserID: userID}
+	taskRepo := &mockTaskRepo{}
+	taskRepo.On("FindByID", mock.Anything, first.ID).Return(first, nil)
+	taskRepo.On("FindByID", mock.Anything, second.ID).Return(second, nil)
+
+	entries := &fakeTimeEntryRepo{}
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	svc := service.NewTaskService(taskRepo, &mockProjectRepo{}, entries, log)
+	ctx := context.Background()
+
+	entry, err := svc.StartTimer(ctx, first.ID, userID)
+	require.NoError(t, err)
+	assert.True(t, entry.IsRunning())
+
+	_, err = svc.StartTimer(ctx, second.ID, userID)
+	assert.ErrorIs(t, err, domain.ErrTimerRunning, "one running timer per user")
+	_, err = svc.StopTimer(ctx, second.ID, userID)
+	assert.ErrorIs(t, err, domain.ErrTimerNotRunning, "the running timer is on another task")
+
+	// Backdate the start so the rollup has something to add.
+	entry.StartedAt = entry.StartedAt.Add(-90 * time.Second)
+	stopped, err := svc.StopTimer(ctx, first.ID, userID)
+	require.NoError(t, err)
+	require.NotNil(t, stopped.DurationSeconds)
+	assert.GreaterOrEqual(t, *stopped.DurationSeconds, int64(90))
+	assert.Equal(t, *stopped.DurationSeconds, entries.tracked[first.ID])
+
+	_, err = svc.StopTimer(ctx, first.ID, userID)
+	assert.ErrorIs(t, err, domain.ErrTimerNotRunning)
+	_, err = svc.StartTimer(ctx, second.ID, userID)
+	assert.NoError(t, err, "a new timer can start once the first is stopped")
+}
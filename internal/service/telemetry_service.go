@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// clientErrorGroupWindow is how far back the admin groups view looks by default.
+	clientErrorGroupWindow = 7 * 24 * time.Hour
+	clientErrorGroupLimit  = 100
+)
+
+// TelemetryService ingests error reports from frontends for admin triage.
+type TelemetryService struct {
+	clientErrorRepo domain.ClientErrorRepository
+	retention       time.Duration
+	log             *logrus.Logger
+}
+
+// NewTelemetryService constructs a TelemetryService. Reports older than
+// retention are removed by Prune.
+func NewTelemetryService(clientErrorRepo domain.ClientErrorRepository, retention time.Duration, log *logrus.Logger) *TelemetryService {
+	return &TelemetryService{clientErrorRepo: clientErrorRepo, retention: retention, log: log}
+}
+
+// Ingest scrubs and stores a batch of client errors. userID is nil for
+// reports sent without a valid access token. Errors claiming to be from the
+// future are stamped with the time they were received.
+func (s *TelemetryService) Ingest(ctx context.Context, userID *uuid.UUID, userAgent string, batch *domain.ClientErrorBatch) (*domain.ClientErrorIngestResult, error) {
+	now := time.Now()
+	errs := make([]*domain.ClientError, 0, len(batch.Errors))
+	for _, r := range batch.Errors {
+		e := &domain.ClientError{
+			ID:            uuid.New(),
+			UserID:        userID,
+			Kind:          strings.TrimSpace(r.Kind),
+			Message:       scrubPII(strings.TrimSpace(r.Message)),
+			Stack:         scrubPII(r.Stack),
+			URL:           scrubPII(r.URL),
+			RequestID:     r.RequestID,
+			ClientVersion: strings.TrimSpace(batch.ClientVersion),
+			Platform:      strings.TrimSpace(batch.Platform),
+			UserAgent:     userAgent,
+			Context:       scrubContext(r.Context),
+			OccurredAt:    r.OccurredAt,
+			ReceivedAt:    now,
+		}
+		if !clientRequestID.MatchString(e.RequestID) {
+			e.RequestID = ""
+		}
+		if e.OccurredAt.After(now) {
+			e.OccurredAt = now
+		}
+		e.Fingerprint = clientErrorFingerprint(e)
+		errs = append(errs, e)
+	}
+
+	if err := s.clientErrorRepo.CreateBatch(ctx, errs); err != nil {
+		return nil, fmt.Errorf("telemetryService.Ingest: %w", err)
+	}
+	return &domain.ClientErrorIngestResult{Accepted: len(errs)}, nil
+}
+
+// List returns reports for admins, newest first.
+func (s *TelemetryService) List(ctx context.Context, filter domain.ClientErrorFilter) ([]*domain.ClientError, int, error) {
+	reports, total, err := s.clientErrorRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("telemetryService.List: %w", err)
+	}
+	return reports, total, nil
+}
+
+// GetByID returns one report for admins.
+func (s *TelemetryService) GetByID(ctx context.Context, id uuid.UUID) (*domain.ClientError, error) {
+	e, err := s.clientErrorRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("telemetryService.GetByID: %w", err)
+	}
+	return e, nil
+}
+
+// Groups returns the fingerprints reported within window (default seven
+// days), most frequent first.
+func (s *TelemetryService) Groups(ctx context.Context, window time.Duration) ([]*domain.ClientErrorGroup, error) {
+	if window <= 0 {
+		window = clientErrorGroupWindow
+	}
+	groups, err := s.clientErrorRepo.Groups(ctx, time.Now().Add(-window), clientErrorGroupLimit)
+	if err != nil {
+		return nil, fmt.Errorf("telemetryService.Groups: %w", err)
+	}
+	return groups, nil
+}
+
+// Prune deletes reports past the retention window.
+// It is meant to be run periodically by the job scheduler.
+func (s *TelemetryService) Prune(ctx context.Context) error {
+	n, err := s.clientErrorRepo.DeleteOlderThan(ctx, time.Now().Add(-s.retention))
+	if err != nil {
+		return fmt.Errorf("telemetryService.Prune: %w", err)
+	}
+	if n > 0 {
+		s.log.WithField("count", n).Info("pruned client errors")
+	}
+	return nil
+}
+
+var (
+	piiEmail       = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	piiJWT         = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)
+	piiBearer      = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/-]+=*`)
+	piiQueryParam  = regexp.MustCompile(`(?i)([?&#](?:access_token|refresh_token|token|password|secret|api_key|key|code|auth)=)[^&#\s"']+`)
+	piiLongNumber  = regexp.MustCompile(`\b\d(?:[ -]?\d){8,}\b`)
+	sensitiveKey   = regexp.MustCompile(`(?i)(password|passwd|secret|token|auth|cookie|session|email|phone)`)
+	fingerprintNum = regexp.MustCompile(`\d+`)
+	// clientRequestID matches the X-Request-ID values the server accepts.
+	clientRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{0,64}$`)
+)
+
+// scrubPII masks email addresses, access tokens, credentials in URLs and
+// long digit runs such as card or phone numbers.
+func scrubPII(s string) string {
+	if s == "" {
+		return s
+	}
+	s = piiJWT.ReplaceAllString(s, "[token]")
+	s = piiBearer.ReplaceAllString(s, "Bearer [token]")
+	s = piiQueryParam.ReplaceAllString(s, "${1}[redacted]")
+	s = piiEmail.ReplaceAllString(s, "[email]")
+	return piiLongNumber.ReplaceAllString(s, "[number]")
+}
+
+// scrubContext drops the values of keys that look sensitive and scrubs the rest.
+func scrubContext(ctx domain.ClientErrorContext) domain.ClientErrorContext {
+	out := make(domain.ClientErrorContext, len(ctx))
+	for k, v := range ctx {
+		if sensitiveKey.MatchString(k) {
+			out[k] = "[redacted]"
+			continue
+		}
+		out[k] = scrubPII(v)
+	}
+	return out
+}
+
+// clientErrorFingerprint groups reports of the same bug: the kind, the
+// message with numbers blanked out and the top stack frame. Browsers repeat
+// the message as the first line of the stack, so that line is skipped.
+func clientErrorFingerprint(e *domain.ClientError) string {
+	var frame string
+	for _, line := range strings.Split(e.Stack, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.Contains(line, e.Message) {
+			frame = line
+			break
+		}
+	}
+	h := sha256.New()
+	h.Write([]byte(e.Kind + "\n"))
+	h.Write([]byte(fingerprintNum.ReplaceAllString(e.Message, "0") + "\n"))
+	h.Write([]byte(frame))
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeClientErrorRepo struct {
+	domain.ClientErrorRepository
+	stored []*domain.ClientError
+}
+
+func (f *fakeClientErrorRepo) CreateBatch(_ context.Context, errs []*domain.ClientError) error {
+	f.stored = append(f.stored, errs...)
+	return nil
+}
+
+func TestTelemetryService_Ingest_ScrubsPII(t *testing.T) {
+	repo := &fakeClientErrorRepo{}
+	svc := service.NewTelemetryService(repo, 30*24*time.Hour, logrus.New())
+	userID := uuid.New()
+
+	result, err := svc.Ingest(context.Background(), &userID, "Mozilla/5.0", &domain.ClientErrorBatch{
+		ClientVersion: "2.3.0",
+		Errors: []domain.ClientErrorReport{{
+			Kind:       "Error",
+			Message:    "Invite for jane.doe@example.com failed, card 4111 1111 1111 1111",
+			Stack:      "at send (https://app.example.com/main.js:12:4)\nAuthorization: Bearer abc.def-123",
+			URL:        "https://app.example.com/reset?token=s3cr3t&next=/tasks",
+			RequestID:  "9b2f6c1e-req",
+			OccurredAt: time.Now().Add(-time.Minute),
+			Context:    domain.ClientErrorContext{"screen": "invites", "session_id": "abc123"},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Accepted)
+	require.Len(t, repo.stored, 1)
+
+	e := repo.stored[0]
+	assert.Equal(t, "Invite for [email] failed, card [number]", e.Message)
+	assert.Contains(t, e.Stack, "Bearer [token]")
+	assert.NotContains(t, e.Stack, "abc.def-123")
+	assert.Equal(t, "https://app.example.com/reset?token=[redacted]&next=/tasks", e.URL)
+	assert.Equal(t, "invites", e.Context["screen"])
+	assert.Equal(t, "[redacted]", e.Context["session_id"])
+	assert.Equal(t, "9b2f6c1e-req", e.RequestID)
+	assert.Equal(t, &userID, e.UserID)
+	assert.Equal(t, "2.3.0", e.ClientVersion)
+}
+
+func TestTelemetryService_Ingest_FingerprintsIgnoreNumbers(t *testing.T) {
+	repo := &fakeClientErrorRepo{}
+	svc := service.NewTelemetryService(repo, 30*24*time.Hour, logrus.New())
+	stack := func(msg string) string { return "TypeError: " + msg + "\n    at render (main.js:40:7)" }
+	future := time.Now().Add(time.Hour)
+
+	_, err := svc.Ingest(context.Background(), nil, "", &domain.ClientErrorBatch{Errors: []domain.ClientErrorReport{
+		{Kind: "TypeError", Message: "Cannot read index 3", Stack: stack("Cannot read index 3"), RequestID: "bad id with spaces", OccurredAt: future},
+		{Kind: "TypeError", Message: "Cannot read index 17", Stack: stack("Cannot read index 17"), OccurredAt: time.Now()},
+		{Kind: "TypeError", Message: "Cannot read index 3", Stack: "at other (main.js:90:1)", OccurredAt: time.Now()},
+	}})
+	require.NoError(t, err)
+	require.Len(t, repo.stored, 3)
+
+	assert.Equal(t, repo.stored[0].Fingerprint, repo.stored[1].Fingerprint)
+	assert.NotEqual(t, repo.stored[0].Fingerprint, repo.stored[2].Fingerprint, "a different top frame is a different bug")
+	assert.Nil(t, repo.stored[0].UserID)
+	assert.Empty(t, repo.stored[0].RequestID)
+	assert.False(t, repo.stored[0].OccurredAt.After(repo.stored[0].ReceivedAt), "future timestamps are clamped")
+}
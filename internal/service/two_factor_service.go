@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/totp"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// recoveryCodeCount is how many recovery codes enabling hands out.
+	recoveryCodeCount = 10
+	// twoFactorSkew accepts codes one step either side of the current one,
+	// for clocks up to 30 seconds apart.
+	twoFactorSkew = 1
+	// maxTwoFactorFailures wrong codes in a row lock the user out of the
+	// second step for TwoFactorLockout after the last of them.
+	maxTwoFactorFailures = 5
+)
+
+// TwoFactorLockout is how long two-factor checks are refused after too many
+// wrong codes.
+const TwoFactorLockout = 15 * time.Minute
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorService handles TOTP enrollment and checks second-step codes.
+type TwoFactorService struct {
+	repo     domain.TwoFactorRepository
+	userRepo domain.UserRepository
+	issuer   string
+	now      func() time.Time
+	log      *logrus.Logger
+}
+
+// NewTwoFactorService constructs a TwoFactorService. issuer names the
+// account in authenticator apps.
+func NewTwoFactorService(repo domain.TwoFactorRepository, userRepo domain.UserRepository, issuer string, log *logrus.Logger) *TwoFactorService {
+	return &TwoFactorService{repo: repo, userRepo: userRepo, issuer: issuer, now: time.Now, log: log}
+}
+
+// Setup starts enrollment with a new secret, replacing one still pending.
+// Nothing changes at login until Enable verifies a code from it.
+func (s *TwoFactorService) Setup(ctx context.Context, userID uuid.UUID) (*domain.TwoFactorSetup, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("twoFactorService.Setup: %w", err)
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("twoFactorService.Setup: %w", err)
+	}
+	tf := &domain.TwoFactor{UserID: userID, Secret: secret, CreatedAt: s.now()}
+	if err := s.repo.SavePending(ctx, tf); err != nil {
+		return nil, fmt.Errorf("twoFactorService.Setup: %w", err)
+	}
+	return &domain.TwoFactorSetup{Secret: secret, OTPAuthURI: totp.URI(s.issuer, user.Email, secret)}, nil
+}
+
+// Enable verifies a code from the pending secret, turns two-factor
+// authentication on, and returns the recovery codes, which are not stored
+// in a form that can be shown again.
+func (s *TwoFactorService) Enable(ctx context.Context, userID uuid.UUID, code string) (*domain.TwoFactorRecoveryCodes, error) {
+	tf, err := s.repo.Find(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("twoFactorService.Enable: %w", err)
+	}
+	if tf.Enabled() {
+		return nil, fmt.Errorf("twoFactorService.Enable: %w", domain.ErrAlreadyExists)
+	}
+	now := s.now()
+	step, ok := totp.Verify(tf.Secret, strings.TrimSpace(code), now, twoFactorSkew)
+	if !ok {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		if codes[i], err = generateRecoveryCode(); err != nil {
+			return nil, fmt.Errorf("twoFactorService.Enable: %w", err)
+		}
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	if err := s.repo.Enable(ctx, userID, step, hashes, now); err != nil {
+		return nil, fmt.Errorf("twoFactorService.Enable: %w", err)
+	}
+	s.log.WithField("user_id", userID).Info("two-factor authentication enabled")
+	return &domain.TwoFactorRecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off once code checks out.
+func (s *TwoFactorService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	if err := s.Check(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, userID); err != nil {
+		return fmt.Errorf("twoFactorService.Disable: %w", err)
+	}
+	s.log.WithField("user_id", userID).Info("two-factor authentication disabled")
+	return nil
+}
+
+// Required reports whether the user must pass the second login step.
+func (s *TwoFactorService) Required(ctx context.Context, userID uuid.UUID) (bool, error) {
+	tf, err := s.repo.Find(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("twoFactorService.Required: %w", err)
+	}
+	return tf.Enabled(), nil
+}
+
+// Check accepts a code from the app, each at most once, or an unused
+// recovery code, which it spends. A wrong code is ErrInvalidCredentials;
+// after maxTwoFactorFailures of them in a row every code is refused with
+// ErrTooManyAttempts until TwoFactorLockout has passed.
+func (s *TwoFactorService) Check(ctx context.Context, userID uuid.UUID, code string) error {
+	tf, err := s.repo.Find(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.ErrInvalidCredentials
+	}
+	if err != nil {
+		return fmt.Errorf("twoFactorService.Check: %w", err)
+	}
+	if !tf.Enabled() {
+		return domain.ErrInvalidCredentials
+	}
+	now := s.now()
+	if tf.FailedAttempts >= maxTwoFactorFailures && tf.LastFailedAt != nil && now.Before(tf.LastFailedAt.Add(TwoFactorLockout)) {
+		return domain.ErrTooManyAttempts
+	}
+
+	code = strings.TrimSpace(code)
+	var accepted bool
+	if len(code) == totp.Digits {
+		if step, ok := totp.Verify(tf.Secret, code, now, twoFactorSkew); ok {
+			accepted, err = s.repo.AcceptStep(ctx, userID, step)
+		}
+	} else {
+		accepted, err = s.repo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code), now)
+		if accepted {
+			s.log.WithField("user_id", userID).Warn("two-factor recovery code used")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("twoFactorService.Check: %w", err)
+	}
+	if !accepted {
+		if err := s.repo.RecordFailure(ctx, userID, now); err != nil {
+			return fmt.Errorf("twoFactorService.Check: %w", err)
+		}
+		return domain.ErrInvalidCredentials
+	}
+	return nil
+}
+
+// generateRecoveryCode returns ten random base32 characters, lowercase and
+// split in two for reading aloud, e.g. "k7qdm-2xp4h".
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := strings.ToLower(recoveryEncoding.EncodeToString(b)[:10])
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode digests a recovery code as typed, ignoring case, spaces
+// and dashes. The codes are random enough that a fast hash will do.
+func hashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,189 @@
+package service_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/totp"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTwoFactorRepo struct {
+	domain.TwoFactorRepository
+	tf    *domain.TwoFactor
+	codes map[string]bool // hash -> used
+}
+
+func (f *fakeTwoFactorRepo) Find(_ context.Context, _ uuid.UUID) (*domain.TwoFactor, error) {
+	if f.tf == nil {
+		return nil, domain.ErrNotFound
+	}
+	cp := *f.tf
+	return &cp, nil
+}
+
+func (f *fakeTwoFactorRepo) SavePending(_ context.Context, tf *domain.TwoFactor) error {
+	if f.tf != nil && f.tf.Enabled() {
+		return domain.ErrAlreadyExists
+	}
+	f.tf = tf
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) Enable(_ context.Context, _ uuid.UUID, step int64, hashes []string, at time.Time) error {
+	f.tf.EnabledAt, f.tf.LastStep = &at, step
+	f.codes = map[string]bool{}
+	for _, h := range hashes {
+		f.codes[h] = false
+	}
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) AcceptStep(_ context.Context, _ uuid.UUID, step int64) (bool, error) {
+	if step <= f.tf.LastStep {
+		return false, nil
+	}
+	f.tf.LastStep, f.tf.FailedAttempts, f.tf.LastFailedAt = step, 0, nil
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepo) UseRecoveryCode(_ context.Context, _ uuid.UUID, hash string, _ time.Time) (bool, error) {
+	used, ok := f.codes[hash]
+	if !ok || used {
+		return false, nil
+	}
+	f.codes[hash] = true
+	f.tf.FailedAttempts, f.tf.LastFailedAt = 0, nil
+	return true, nil
+}
+
+func (f *fakeTwoFactorRepo) RecordFailure(_ context.Context, _ uuid.UUID, at time.Time) error {
+	f.tf.FailedAttempts++
+	f.tf.LastFailedAt = &at
+	return nil
+}
+
+func (f *fakeTwoFactorRepo) Delete(_ context.Context, _ uuid.UUID) error {
+	f.tf, f.codes = nil, nil
+	return nil
+}
+
+type twoFactorUserRepo struct {
+	domain.UserRepository
+}
+
+func (twoFactorUserRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.User, error) {
+	return &domain.User{ID: id, Email: "ada@example.com"}, nil
+}
+
+func newTwoFactorService(repo *fakeTwoFactorRepo) *service.TwoFactorService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return service.NewTwoFactorService(repo, twoFactorUserRepo{}, "Todo", log)
+}
+
+// enrolledTwoFactor enables two-factor authentication for a user with the
+// code of the previous step, leaving the current one unused.
+func enrolledTwoFactor(t *testing.T) (*service.TwoFactorService, *fakeTwoFactorRepo, uuid.UUID, []string) {
+	t.Helper()
+	repo := &fakeTwoFactorRepo{}
+	svc := newTwoFactorService(repo)
+	ctx, userID := context.Background(), uuid.New()
+
+	setup, err := svc.Setup(ctx, userID)
+	require.NoError(t, err)
+	code, err := totp.Code(setup.Secret, totp.Step(time.Now())-1)
+	require.NoError(t, err)
+	codes, err := svc.Enable(ctx, userID, code)
+	require.NoError(t, err)
+	return svc, repo, userID, codes.RecoveryCodes
+}
+
+func TestTwoFactorService_SetupIsPendingUntilEnabled(t *testing.T) {
+	repo := &fakeTwoFactorRepo{}
+	svc := newTwoFactorService(repo)
+	ctx, userID := context.Background(), uuid.New()
+
+	setup, err := svc.Setup(ctx, userID)
+	require.NoError(t, err)
+	uri, err := url.Parse(setup.OTPAuthURI)
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, setup.Secret, uri.Query().Get("secret"))
+
+	required, err := svc.Required(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, required, "a pending enrollment must not be asked for at login")
+
+	_, err = svc.Enable(ctx, userID, "000000x")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+
+	code, err := totp.Code(setup.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	codes, err := svc.Enable(ctx, userID, code)
+	require.NoError(t, err)
+	assert.Len(t, codes.RecoveryCodes, 10)
+
+	required, err = svc.Required(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, required)
+
+	_, err = svc.Setup(ctx, userID)
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+}
+
+func TestTwoFactorService_CheckRefusesReplayedCodes(t *testing.T) {
+	svc, repo, userID, _ := enrolledTwoFactor(t)
+	ctx := context.Background()
+
+	code, err := totp.Code(repo.tf.Secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+	require.NoError(t, svc.Check(ctx, userID, code))
+	assert.ErrorIs(t, svc.Check(ctx, userID, code), domain.ErrInvalidCredentials)
+}
+
+func TestTwoFactorService_RecoveryCodesWorkOnce(t *testing.T) {
+	svc, _, userID, codes := enrolledTwoFactor(t)
+	ctx := context.Background()
+
+	require.NoError(t, svc.Check(ctx, userID, codes[0]))
+	assert.ErrorIs(t, svc.Check(ctx, userID, codes[0]), domain.ErrInvalidCredentials)
+	// Typed in capitals without the dash still counts.
+	require.NoError(t, svc.Check(ctx, userID, strings.ToUpper(strings.ReplaceAll(codes[1], "-", ""))))
+}
+
+func TestTwoFactorService_LocksOutAfterRepeatedFailures(t *testing.T) {
+	svc, repo, userID, codes := enrolledTwoFactor(t)
+	ctx := context.Background()
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, svc.Check(ctx, userID, "wrong-code"), domain.ErrInvalidCredentials)
+	}
+	assert.ErrorIs(t, svc.Check(ctx, userID, codes[0]), domain.ErrTooManyAttempts,
+		"even a right code is refused during the lockout")
+
+	expired := time.Now().Add(-service.TwoFactorLockout - time.Second)
+	repo.tf.LastFailedAt = &expired
+	require.NoError(t, svc.Check(ctx, userID, codes[0]))
+	assert.Zero(t, repo.tf.FailedAttempts)
+}
+
+func TestTwoFactorService_DisableNeedsACode(t *testing.T) {
+	svc, _, userID, codes := enrolledTwoFactor(t)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, svc.Disable(ctx, userID, "123456"), domain.ErrInvalidCredentials)
+	require.NoError(t, svc.Disable(ctx, userID, codes[0]))
+
+	required, err := svc.Required(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, required)
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// UserService manages the user's own account settings.
+type UserService struct {
+	userRepo domain.UserRepository
+	log      *logrus.Logger
+}
+
+// NewUserService constructs a UserService.
+func NewUserService(userRepo domain.UserRepository, log *logrus.Logger) *UserService {
+	return &UserService{userRepo: userRepo, log: log}
+}
+
+// Timezone returns the IANA zone the user's days are counted in.
+func (s *UserService) Timezone(ctx context.Context, userID uuid.UUID) (string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("userService.Timezone: %w", err)
+	}
+	return user.Location().String(), nil
+}
+
+// Location implements UserLocator.
+func (s *UserService) Location(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	loc, err := userLocation(ctx, s.userRepo, userID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("userService.Location: %w", err)
+	}
+	return loc, nil
+}
+
+// SetTimezone changes the user's time zone.
+func (s *UserService) SetTimezone(ctx context.Context, userID uuid.UUID, tz string) error {
+	if _, err := time.LoadLocation(tz); err != nil || tz == "" {
+		return fmt.Errorf("userService.SetTimezone: unknown time zone %q: %w", tz, domain.ErrValidation)
+	}
+	if err := s.userRepo.SetTimezone(ctx, userID, tz); err != nil {
+		return fmt.Errorf("userService.SetTimezone: %w", err)
+	}
+	s.log.WithFields(logrus.Fields{"user_id": userID, "timezone": tz}).Info("user timezone changed")
+	return nil
+}
+
+// userLocation returns loc, or the user's own time zone when loc is nil.
+func userLocation(ctx context.Context, userRepo domain.UserRepository, userID uuid.UUID, loc *time.Location) (*time.Location, error) {
+	if loc != nil {
+		return loc, nil
+	}
+	user, err := userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return user.Location(), nil
+}
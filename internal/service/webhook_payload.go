@@ -0,0 +1,81 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/google/uuid"
+)
+
+// Webhook payload schemas. Once published a version is frozen: new task fields
+// only ever appear in a newer version, never in an existing one.
+
+// webhookPayloadV1 is the original flat payload.
+type webhookPayloadV1 struct {
+	Event     string        `json:"event"`
+	Timestamp int64         `json:"timestamp"`
+	Task      webhookTaskV1 `json:"task"`
+}
+
+type webhookTaskV1 struct {
+	ID          uuid.UUID  `json:"id"`
+	ProjectID   *uuid.UUID `json:"project_id,omitempty"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Status      string     `json:"status"`
+	Priority    string     `json:"priority"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// webhookPayloadV2 wraps the full resource in an envelope that carries a
+// stable event id, so receivers can de-duplicate redeliveries.
+type webhookPayloadV2 struct {
+	ID         uuid.UUID           `json:"id"`
+	Type       string              `json:"type"`
+	APIVersion string              `json:"api_version"`
+	CreatedAt  time.Time           `json:"created_at"`
+	Data       webhookPayloadV2Obj `json:"data"`
+}
+
+type webhookPayloadV2Obj struct {
+	ObjectType string       `json:"object_type"`
+	Object     *domain.Task `json:"object"`
+}
+
+// buildWebhookPayload renders a task event in the requested schema version.
+func buildWebhookPayload(version string, eventID uuid.UUID, event string, task *domain.Task, at time.Time) (json.RawMessage, error) {
+	switch version {
+	case domain.WebhookVersionV1:
+		return json.Marshal(webhookPayloadV1{
+			Event:     event,
+			Timestamp: at.Unix(),
+			Task: webhookTaskV1{
+				ID:          task.ID,
+				ProjectID:   task.ProjectID,
+				Title:       task.Title,
+				Description: task.Description,
+				Status:      string(task.Status),
+				Priority:    string(task.Priority),
+				DueDate:     task.DueDate,
+				CompletedAt: task.CompletedAt,
+				CreatedAt:   task.CreatedAt,
+				UpdatedAt:   task.UpdatedAt,
+			},
+		})
+	case domain.WebhookVersionV2:
+		return json.Marshal(webhookPayloadV2{
+			ID:         eventID,
+			Type:       event,
+			APIVersion: domain.WebhookVersionV2,
+			CreatedAt:  at.UTC(),
+			Data:       webhookPayloadV2Obj{ObjectType: "task", Object: task},
+		})
+	default:
+		return nil, fmt.Errorf("unknown webhook payload version %q", version)
+	}
+}
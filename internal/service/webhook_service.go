@@ -0,0 +1,468 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/galihaleanda/todo-app/pkg/signing"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// JobDeliverWebhook is the job type used to POST a stored delivery to its webhook.
+const JobDeliverWebhook = "webhook.deliver"
+
+const (
+	// webhookDeliveryRetention is how long delivered payloads stay available for redelivery.
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	webhookRequestTimeout    = 10 * time.Second
+	webhookErrorLimit        = 1024
+)
+
+// WebhookSignatureHeader carries the asymmetric signature when a signing key is configured.
+const WebhookSignatureHeader = "X-Webhook-Signature-Ed25519"
+
+// WebhookMeta is what receivers need to verify deliveries and allow-list our traffic.
+type WebhookMeta struct {
+	SignatureHeader string              `json:"signature_header"`
+	SignedContent   string              `json:"signed_content"`
+	Keys            []signing.PublicKey `json:"keys"`
+	Rotation        WebhookKeyRotation  `json:"rotation"`
+	EgressIPs       []string            `json:"egress_ips"`
+}
+
+// WebhookKeyRotation describes the signing key rotation schedule.
+type WebhookKeyRotation struct {
+	IntervalDays  int        `json:"interval_days,omitempty"`
+	CurrentKeyID  string     `json:"current_key_id,omitempty"`
+	NextRotatesAt *time.Time `json:"next_rotates_at,omitempty"`
+}
+
+type deliverWebhookJob struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+// WebhookService manages webhook subscriptions and delivers task events to them.
+type WebhookService struct {
+	webhookRepo  domain.WebhookRepository
+	deliveryRepo domain.WebhookDeliveryRepository
+	queue        *jobs.Queue
+	keys         *signing.KeySet
+	egressIPs    []string
+	breaker      CircuitBreakerPolicy
+	notifier     Notifier
+	client       *http.Client
+	log          *logrus.Logger
+}
+
+// NewWebhookService constructs a WebhookService and registers its job handler on queue.
+// keys may be nil, in which case only the shared-secret HMAC signature is sent.
+// notifier tells owners when a failing webhook is paused; it may be nil when
+// breakers are disabled.
+func NewWebhookService(
+	webhookRepo domain.WebhookRepository,
+	deliveryRepo domain.WebhookDeliveryRepository,
+	queue *jobs.Queue,
+	keys *signing.KeySet,
+	egressIPs []string,
+	breaker CircuitBreakerPolicy,
+	notifier Notifier,
+	log *logrus.Logger,
+) *WebhookService {
+	if egressIPs == nil {
+		egressIPs = []string{}
+	}
+	s := &WebhookService{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		queue:        queue,
+		keys:         keys,
+		egressIPs:    egressIPs,
+		breaker:      breaker,
+		notifier:     notifier,
+		client:       &http.Client{Timeout: webhookRequestTimeout},
+		log:          log,
+	}
+	queue.Register(JobDeliverWebhook, s.handleDeliverJob)
+	return s
+}
+
+// Create registers a webhook. The signing secret is only returned here.
+func (s *WebhookService) Create(ctx context.Context, userID uuid.UUID, req *domain.CreateWebhookRequest) (*domain.CreatedWebhook, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.Create: %w", err)
+	}
+
+	version := req.PayloadVersion
+	if version == "" {
+		version = domain.WebhookVersionLatest
+	}
+
+	now := time.Now()
+	w := &domain.Webhook{
+		ID:             uuid.New(),
+		UserID:         userID,
+		URL:            req.URL,
+		Secret:         secret,
+		Events:         req.Events,
+		PayloadVersion: version,
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := s.webhookRepo.Create(ctx, w); err != nil {
+		return nil, fmt.Errorf("webhookService.Create: %w", err)
+	}
+
+	s.log.WithFields(logrus.Fields{"webhook_id": w.ID, "user_id": userID}).Info("webhook created")
+	return &domain.CreatedWebhook{Webhook: w, Secret: secret}, nil
+}
+
+// Meta returns the public verification keys, their rotation schedule and our egress IPs.
+func (s *WebhookService) Meta() *WebhookMeta {
+	meta := &WebhookMeta{
+		SignatureHeader: WebhookSignatureHeader,
+		SignedContent:   "{X-Webhook-Timestamp}.{body}",
+		Keys:            s.keys.PublicKeys(),
+		EgressIPs:       s.egressIPs,
+	}
+	if s.keys.Enabled() {
+		meta.Rotation = WebhookKeyRotation{
+			IntervalDays:  int(s.keys.RotationInterval().Hours() / 24),
+			CurrentKeyID:  s.keys.KeyID(),
+			NextRotatesAt: s.keys.NextRotation(),
+		}
+	}
+	return meta
+}
+
+// GetByID retrieves a webhook, enforcing ownership.
+func (s *WebhookService) GetByID(ctx context.Context, id, userID uuid.UUID) (*domain.Webhook, error) {
+	w, err := s.webhookRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if w.UserID != userID {
+		return nil, domain.ErrForbidden
+	}
+	return w, nil
+}
+
+// List returns all webhooks of the user.
+func (s *WebhookService) List(ctx context.Context, userID uuid.UUID) ([]*domain.Webhook, error) {
+	webhooks, err := s.webhookRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("webhookService.List: %w", err)
+	}
+	return webhooks, nil
+}
+
+// Update applies partial updates to a webhook, enforcing ownership.
+func (s *WebhookService) Update(ctx context.Context, id, userID uuid.UUID, req *domain.UpdateWebhookRequest) (*domain.Webhook, error) {
+	w, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		w.URL = *req.URL
+	}
+	if req.Events != nil {
+		w.Events = req.Events
+	}
+	if req.PayloadVersion != nil {
+		w.PayloadVersion = *req.PayloadVersion
+	}
+	if req.Active != nil {
+		w.Active = *req.Active
+	}
+	w.UpdatedAt = time.Now()
+
+	if err := s.webhookRepo.Update(ctx, w); err != nil {
+		return nil, fmt.Errorf("webhookService.Update: %w", err)
+	}
+	return w, nil
+}
+
+// Enable reactivates a webhook and closes its circuit breaker, enforcing
+// ownership. Deliveries that failed while it was paused can be redelivered.
+func (s *WebhookService) Enable(ctx context.Context, id, userID uuid.UUID) (*domain.Webhook, error) {
+	w, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.ResetBreaker(ctx, w.ID); err != nil {
+		return nil, fmt.Errorf("webhookService.Enable: %w", err)
+	}
+	w.CircuitBreaker = domain.CircuitBreaker{}
+	if !w.Active {
+		w.Active = true
+		w.UpdatedAt = time.Now()
+		if err := s.webhookRepo.Update(ctx, w); err != nil {
+			return nil, fmt.Errorf("webhookService.Enable: %w", err)
+		}
+	}
+	return w, nil
+}
+
+// Delete removes a webhook and its delivery history, enforcing ownership.
+func (s *WebhookService) Delete(ctx context.Context, id, userID uuid.UUID) error {
+	w, err := s.GetByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.webhookRepo.Delete(ctx, w.ID); err != nil {
+		return fmt.Errorf("webhookService.Delete: %w", err)
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of stored deliveries for a webhook, enforcing ownership.
+func (s *WebhookService) ListDeliveries(ctx context.Context, webhookID, userID uuid.UUID, page, limit int) ([]*domain.WebhookDelivery, int, error) {
+	if _, err := s.GetByID(ctx, webhookID, userID); err != nil {
+		return nil, 0, err
+	}
+	deliveries, total, err := s.deliveryRepo.ListByWebhookID(ctx, webhookID, page, limit)
+	if err != nil {
+		return nil, 0, fmt.Errorf("webhookService.ListDeliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// GetDelivery retrieves one stored delivery, enforcing ownership of its webhook.
+func (s *WebhookService) GetDelivery(ctx context.Context, webhookID, deliveryID, userID uuid.UUID) (*domain.WebhookDelivery, error) {
+	if _, err := s.GetByID(ctx, webhookID, userID); err != nil {
+		return nil, err
+	}
+	d, err := s.deliveryRepo.FindByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if d.WebhookID != webhookID {
+		return nil, domain.ErrNotFound
+	}
+	return d, nil
+}
+
+// Redeliver queues the stored payload of a past delivery again. The payload is
+// sent byte-for-byte as before, so receivers can de-duplicate on its event id.
+func (s *WebhookService) Redeliver(ctx context.Context, webhookID, deliveryID, userID uuid.UUID) (*domain.WebhookDelivery, error) {
+	original, err := s.GetDelivery(ctx, webhookID, deliveryID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &domain.WebhookDelivery{
+		ID:             uuid.New(),
+		WebhookID:      original.WebhookID,
+		Event:          original.Event,
+		PayloadVersion: original.PayloadVersion,
+		Payload:        original.Payload,
+		Status:         domain.WebhookDeliveryPending,
+		RedeliveryOf:   &original.ID,
+		CreatedAt:      time.Now(),
+	}
+	if err := s.store(ctx, d); err != nil {
+		return nil, fmt.Errorf("webhookService.Redeliver: %w", err)
+	}
+	return d, nil
+}
+
+// TaskChanged fans a task event out to every subscribed webhook of the task owner.
+func (s *WebhookService) TaskChanged(ctx context.Context, e domain.TaskChange) error {
+	event, task := e.EventName(), e.EventTask()
+	webhooks, err := s.webhookRepo.ListSubscribed(ctx, task.UserID, event)
+	if err != nil {
+		return fmt.Errorf("webhookService.TaskChanged: %w", err)
+	}
+
+	now := time.Now()
+	for _, w := range webhooks {
+		d := &domain.WebhookDelivery{
+			ID:             uuid.New(),
+			WebhookID:      w.ID,
+			Event:          event,
+			PayloadVersion: w.PayloadVersion,
+			Status:         domain.WebhookDeliveryPending,
+			CreatedAt:      now,
+		}
+		d.Payload, err = buildWebhookPayload(w.PayloadVersion, d.ID, event, task, now)
+		if err == nil {
+			err = s.store(ctx, d)
+		}
+		if err != nil {
+			s.log.WithError(err).WithFields(logrus.Fields{"webhook_id": w.ID, "event": event}).Error("failed to queue webhook delivery")
+		}
+	}
+	return nil
+}
+
+// PruneDeliveries deletes stored deliveries past the retention window.
+// It is meant to be run periodically by the job scheduler.
+func (s *WebhookService) PruneDeliveries(ctx context.Context) error {
+	n, err := s.deliveryRepo.DeleteOlderThan(ctx, time.Now().Add(-webhookDeliveryRetention))
+	if err != nil {
+		return fmt.Errorf("webhookService.PruneDeliveries: %w", err)
+	}
+	if n > 0 {
+		s.log.WithField("count", n).Info("pruned webhook deliveries")
+	}
+	return nil
+}
+
+func (s *WebhookService) store(ctx context.Context, d *domain.WebhookDelivery) error {
+	if err := s.deliveryRepo.Create(ctx, d); err != nil {
+		return err
+	}
+	return s.queue.Enqueue(ctx, JobDeliverWebhook, deliverWebhookJob{DeliveryID: d.ID})
+}
+
+// handleDeliverJob POSTs a stored delivery and records the outcome.
+// Client errors other than 408/429 are not retried.
+func (s *WebhookService) handleDeliverJob(ctx context.Context, payload json.RawMessage) error {
+	var job deliverWebhookJob
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return fmt.Errorf("%w: decode webhook job: %w", jobs.ErrPermanent, err)
+	}
+
+	d, err := s.deliveryRepo.FindByID(ctx, job.DeliveryID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+		}
+		return err
+	}
+	w, err := s.webhookRepo.FindByID(ctx, d.WebhookID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: %w", jobs.ErrPermanent, err)
+		}
+		return err
+	}
+	if !w.Active {
+		return fmt.Errorf("%w: webhook %s is disabled", jobs.ErrPermanent, w.ID)
+	}
+	if now := time.Now(); w.IsOpen(now) {
+		// Fail fast without an attempt; the owner can redeliver once the
+		// endpoint is fixed.
+		d.Status = domain.WebhookDeliveryFailed
+		d.Error = "circuit breaker open until " + w.OpenUntil.UTC().Format(time.RFC3339)
+		if err := s.deliveryRepo.RecordAttempt(ctx, d); err != nil {
+			s.log.WithError(err).WithField("delivery_id", d.ID).Error("failed to record webhook attempt")
+		}
+		return fmt.Errorf("%w: webhook %s circuit breaker is open", jobs.ErrPermanent, w.ID)
+	}
+
+	code, sendErr := s.post(ctx, w, d)
+	s.recordBreaker(ctx, w, sendErr)
+
+	now := time.Now()
+	d.Attempts++
+	d.LastAttemptAt = &now
+	d.ResponseStatus = code
+	d.Status = domain.WebhookDeliverySucceeded
+	d.Error = ""
+	if sendErr != nil {
+		d.Status = domain.WebhookDeliveryFailed
+		d.Error = sendErr.Error()
+	}
+	if err := s.deliveryRepo.RecordAttempt(ctx, d); err != nil {
+		s.log.WithError(err).WithField("delivery_id", d.ID).Error("failed to record webhook attempt")
+	}
+
+	entry := s.log.WithFields(logrus.Fields{"webhook_id": w.ID, "delivery_id": d.ID, "event": d.Event, "attempt": d.Attempts})
+	if sendErr != nil {
+		entry.WithError(sendErr).Warn("webhook delivery failed")
+		return sendErr
+	}
+	entry.Info("webhook delivered")
+	return nil
+}
+
+// recordBreaker updates the webhook's circuit breaker after an attempt and
+// tells the owner when it trips.
+func (s *WebhookService) recordBreaker(ctx context.Context, w *domain.Webhook, sendErr error) {
+	openUntil, err := s.breaker.record(ctx, s.webhookRepo, w.ID, w.CircuitBreaker, sendErr)
+	if err != nil {
+		s.log.WithError(err).WithField("webhook_id", w.ID).Error("failed to update webhook circuit breaker")
+		return
+	}
+	if openUntil == nil {
+		return
+	}
+	s.log.WithFields(logrus.Fields{"webhook_id": w.ID, "open_until": *openUntil}).Warn("webhook circuit breaker opened")
+	s.notifier.Notify(domain.NotificationEvent{
+		UserID: w.UserID,
+		Type:   domain.EventCircuitOpened,
+		Title:  fmt.Sprintf("Webhook paused after %d failed deliveries", s.breaker.Threshold),
+		Body: fmt.Sprintf("Deliveries to %s are paused until %s. Re-enable the webhook once the endpoint is fixed.",
+			w.URL, openUntil.UTC().Format(time.RFC1123)),
+		EntityID: &w.ID,
+		DedupKey: "breaker:" + w.ID.String(),
+	})
+}
+
+// post sends the delivery. The signed content is timestamp + "." + body: it is
+// HMAC-SHA256'd with the webhook secret into X-Webhook-Signature and, when a
+// key pair is configured, Ed25519-signed into X-Webhook-Signature-Ed25519.
+func (s *WebhookService) post(ctx context.Context, w *domain.Webhook, d *domain.WebhookDelivery) (*int, error) {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	signed := append([]byte(ts+"."), d.Payload...)
+	mac := hmac.New(sha256.New, []byte(w.Secret))
+	mac.Write(signed)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return nil, fmt.Errorf("%w: build request: %w", jobs.ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "todo-app-webhooks/1")
+	req.Header.Set("X-Webhook-Id", w.ID.String())
+	req.Header.Set("X-Webhook-Delivery", d.ID.String())
+	req.Header.Set("X-Webhook-Event", d.Event)
+	req.Header.Set("X-Webhook-Version", d.PayloadVersion)
+	req.Header.Set("X-Webhook-Timestamp", ts)
+	req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	if s.keys.Enabled() {
+		req.Header.Set(WebhookSignatureHeader, fmt.Sprintf("keyid=%s,sig=%s", s.keys.KeyID(), s.keys.Sign(signed)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorLimit))
+
+	code := resp.StatusCode
+	switch {
+	case code >= 200 && code < 300:
+		return &code, nil
+	case code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests:
+		return &code, fmt.Errorf("%w: endpoint returned %d: %s", jobs.ErrPermanent, code, body)
+	default:
+		return &code, fmt.Errorf("endpoint returned %d: %s", code, body)
+	}
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,141 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/internal/domain"
+	"github.com/galihaleanda/todo-app/internal/service"
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWebhookRepo struct {
+	domain.WebhookRepository
+	webhooks []*domain.Webhook
+}
+
+func (f *fakeWebhookRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.Webhook, error) {
+	for _, w := range f.webhooks {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (f *fakeWebhookRepo) ListSubscribed(_ context.Context, userID uuid.UUID, event string) ([]*domain.Webhook, error) {
+	var out []*domain.Webhook
+	for _, w := range f.webhooks {
+		if w.UserID == userID && w.Active && w.Subscribed(event) {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+type fakeDeliveryRepo struct {
+	domain.WebhookDeliveryRepository
+	deliveries []*domain.WebhookDelivery
+}
+
+func (f *fakeDeliveryRepo) Create(_ context.Context, d *domain.WebhookDelivery) error {
+	f.deliveries = append(f.deliveries, d)
+	return nil
+}
+
+func (f *fakeDeliveryRepo) FindByID(_ context.Context, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	for _, d := range f.deliveries {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func newWebhookService(webhooks *fakeWebhookRepo, deliveries *fakeDeliveryRepo) *service.WebhookService {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	queue := jobs.New(jobs.Config{BufferSize: 10}, log) // never started; jobs just sit in the buffer
+	return service.NewWebhookService(webhooks, deliveries, queue, nil, nil, service.CircuitBreakerPolicy{}, nil, log)
+}
+
+func TestWebhookService_TaskChanged_RendersSubscribedVersion(t *testing.T) {
+	userID := uuid.New()
+	v1 := &domain.Webhook{ID: uuid.New(), UserID: userID, Events: []string{domain.EventTaskCreated}, PayloadVersion: "v1", Active: true}
+	v2 := &domain.Webhook{ID: uuid.New(), UserID: userID, Events: []string{domain.EventTaskCreated}, PayloadVersion: "v2", Active: true}
+	other := &domain.Webhook{ID: uuid.New(), UserID: userID, Events: []string{domain.EventTaskDeleted}, PayloadVersion: "v2", Active: true}
+	deliveries := &fakeDeliveryRepo{}
+	svc := newWebhookService(&fakeWebhookRepo{webhooks: []*domain.Webhook{v1, v2, other}}, deliveries)
+
+	task := &domain.Task{ID: uuid.New(), UserID: userID, Title: "Ship it", Status: domain.TaskStatusTodo, CreatedAt: time.Now()}
+	svc.TaskChanged(context.Background(), domain.TaskCreated{Task: task})
+
+	require.Len(t, deliveries.deliveries, 2, "only subscribed webhooks receive the event")
+
+	var p1 map[string]any
+	require.NoError(t, json.Unmarshal(deliveries.deliveries[0].Payload, &p1))
+	assert.Equal(t, "task.created", p1["event"])
+	assert.Equal(t, "Ship it", p1["task"].(map[string]any)["title"])
+	assert.NotContains(t, p1["task"], "smart_score", "v1 task shape is frozen")
+
+	var p2 map[string]any
+	require.NoError(t, json.Unmarshal(deliveries.deliveries[1].Payload, &p2))
+	assert.Equal(t, "task.created", p2["type"])
+	assert.Equal(t, "v2", p2["api_version"])
+	assert.Equal(t, deliveries.deliveries[1].ID.String(), p2["id"])
+	assert.Equal(t, "Ship it", p2["data"].(map[string]any)["object"].(map[string]any)["title"])
+}
+
+func TestWebhookService_Redeliver_CopiesStoredPayload(t *testing.T) {
+	userID := uuid.New()
+	w := &domain.Webhook{ID: uuid.New(), UserID: userID, PayloadVersion: "v2", Active: true}
+	original := &domain.WebhookDelivery{
+		ID: uuid.New(), WebhookID: w.ID, Event: domain.EventTaskUpdated, PayloadVersion: "v2",
+		Payload: json.RawMessage(`{"id":"abc"}`), Status: domain.WebhookDeliveryFailed,
+	}
+	deliveries := &fakeDeliveryRepo{deliveries: []*domain.WebhookDelivery{original}}
+	svc := newWebhookService(&fakeWebhookRepo{webhooks: []*domain.Webhook{w}}, deliveries)
+
+	_, err := svc.Redeliver(context.Background(), w.ID, original.ID, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	d, err := svc.Redeliver(context.Background(), w.ID, original.ID, userID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, d.ID)
+	assert.Equal(t, original.ID, *d.RedeliveryOf)
+	assert.JSONEq(t, string(original.Payload), string(d.Payload))
+	assert.Equal(t, domain.WebhookDeliveryPending, d.Status)
+}
+
+func TestWebhookService_Catalog(t *testing.T) {
+	svc := newWebhookService(&fakeWebhookRepo{}, &fakeDeliveryRepo{})
+	catalog := svc.Catalog()
+
+	require.Len(t, catalog.Events, len(domain.WebhookEvents))
+	assert.Equal(t, domain.WebhookVersionLatest, catalog.LatestVersion)
+
+	completed := catalog.Events[2]
+	assert.Equal(t, domain.EventTaskCompleted, completed.Type)
+	v1 := completed.Payloads[domain.WebhookVersionV1]
+	require.NotNil(t, v1)
+	assert.Equal(t, domain.EventTaskCompleted, v1.Properties["event"].Const)
+	assert.Contains(t, v1.Required, "timestamp")
+	task := v1.Properties["task"]
+	assert.Equal(t, "uuid", task.Properties["id"].Format)
+	assert.NotContains(t, task.Required, "due_date", "omitempty fields are optional")
+
+	v2 := completed.Payloads[domain.WebhookVersionV2]
+	require.NotNil(t, v2)
+	assert.Equal(t, domain.EventTaskCompleted, v2.Properties["type"].Const)
+	status := v2.Properties["data"].Properties["object"].Properties["status"]
+	assert.ElementsMatch(t, []any{domain.TaskStatusTodo, domain.TaskStatusInProgress, domain.TaskStatusDone}, status.Enum)
+
+	created := catalog.Events[0].Payloads[domain.WebhookVersionV1]
+	assert.Equal(t, domain.EventTaskCreated, created.Properties["event"].Const, "each event gets its own copy")
+}
@@ -4,11 +4,31 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/galihaleanda/todo-app/internal/domain"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
-var validate = validator.New()
+var validate = newValidate()
+
+func newValidate() *validator.Validate {
+	v := validator.New()
+	// Enum tags share the domain parsers with query-string handling so the
+	// same values are accepted everywhere.
+	_ = v.RegisterValidation("task_status", func(fl validator.FieldLevel) bool {
+		return domain.TaskStatus(fl.Field().String()).Valid()
+	})
+	_ = v.RegisterValidation("task_priority", func(fl validator.FieldLevel) bool {
+		return domain.TaskPriority(fl.Field().String()).Valid()
+	})
+	_ = v.RegisterValidation("task_energy", func(fl validator.FieldLevel) bool {
+		return domain.TaskEnergy(fl.Field().String()).Valid()
+	})
+	_ = v.RegisterValidation("project_icon", func(fl validator.FieldLevel) bool {
+		return domain.ValidProjectIcon(fl.Field().String())
+	})
+	return v
+}
 
 // ValidationError represents a single field validation failure.
 type ValidationError struct {
@@ -22,7 +42,12 @@ func BindAndValidate(c *gin.Context, dst any) ([]ValidationError, error) {
 	if err := c.ShouldBindJSON(dst); err != nil {
 		return []ValidationError{{Field: "body", Message: "invalid JSON: " + err.Error()}}, nil
 	}
+	return Validate(dst)
+}
 
+// Validate runs struct validation on a value decoded some other way than
+// BindAndValidate, such as a YAML upload. It returns like BindAndValidate.
+func Validate(dst any) ([]ValidationError, error) {
 	if err := validate.Struct(dst); err != nil {
 		var errs validator.ValidationErrors
 		if ok := isValidationErrors(err, &errs); ok {
@@ -34,6 +59,21 @@ func BindAndValidate(c *gin.Context, dst any) ([]ValidationError, error) {
 	return nil, nil
 }
 
+// Invalid builds the error details for a single rejected field, for input
+// checked outside of struct validation such as query parameters.
+func Invalid(field, message string) []ValidationError {
+	return []ValidationError{{Field: field, Message: message}}
+}
+
+// EnumMessage describes the accepted values of an enum field.
+func EnumMessage[T ~string](values []T) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		names[i] = string(v)
+	}
+	return "must be one of: " + strings.Join(names, ", ")
+}
+
 func isValidationErrors(err error, target *validator.ValidationErrors) bool {
 	if v, ok := err.(validator.ValidationErrors); ok {
 		*target = v
@@ -57,6 +97,10 @@ func fieldMessage(e validator.FieldError) string {
 	switch e.Tag() {
 	case "required":
 		return "this field is required"
+	case "required_without":
+		return fmt.Sprintf("this field is required unless %s is set", strings.ToLower(e.Param()))
+	case "excluded_with":
+		return fmt.Sprintf("cannot be combined with %s", strings.ToLower(e.Param()))
 	case "email":
 		return "must be a valid email address"
 	case "min":
@@ -65,8 +109,20 @@ func fieldMessage(e validator.FieldError) string {
 		return fmt.Sprintf("must be at most %s characters", e.Param())
 	case "oneof":
 		return fmt.Sprintf("must be one of: %s", e.Param())
+	case "task_status":
+		return EnumMessage(domain.TaskStatusValues)
+	case "task_priority":
+		return EnumMessage(domain.TaskPriorityValues)
+	case "task_energy":
+		return EnumMessage(domain.TaskEnergyValues)
+	case "project_icon":
+		return "must be a single emoji or one of: " + strings.Join(domain.ProjectIconKeys, ", ")
 	case "hexcolor":
 		return "must be a valid hex color (e.g. #3B82F6)"
+	case "timezone":
+		return "must be an IANA time zone (e.g. Asia/Jakarta)"
+	case "datetime":
+		return fmt.Sprintf("must match the time format %s", e.Param())
 	default:
 		return fmt.Sprintf("failed validation: %s", e.Tag())
 	}
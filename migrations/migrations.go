@@ -0,0 +1,11 @@
+// Package migrations embeds the schema migrations so the binary can apply
+// them without the source tree.
+package migrations
+
+import _ "embed"
+
+// Schema holds every migration in order, each under a
+// "-- migrations/NNN_name.sql" header.
+//
+//go:embed schema.sql
+var Schema string
@@ -77,3 +77,823 @@ CREATE INDEX idx_tasks_smart_score ON tasks (smart_score DESC) WHERE deleted_at
 -- Partial index for overdue query
 CREATE INDEX idx_tasks_overdue ON tasks (user_id, due_date)
     WHERE deleted_at IS NULL AND status != 'done';
+
+
+-- migrations/005_create_email_suppressions.sql
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    email      VARCHAR(255) PRIMARY KEY,
+    reason     TEXT         NOT NULL DEFAULT '',
+    type       VARCHAR(32)  NOT NULL,
+    provider   VARCHAR(32)  NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+
+-- migrations/006_create_notifications.sql
+CREATE TABLE IF NOT EXISTS notifications (
+    id          UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type        VARCHAR(64)  NOT NULL,
+    title       VARCHAR(255) NOT NULL,
+    body        TEXT         NOT NULL DEFAULT '',
+    data        JSONB        NOT NULL DEFAULT '{}',
+    event_count INT          NOT NULL DEFAULT 1,
+    read_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_notifications_user_created ON notifications (user_id, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+
+-- migrations/007_create_quiet_hours.sql
+CREATE TABLE IF NOT EXISTS user_quiet_hours (
+    user_id    UUID        PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled    BOOLEAN     NOT NULL DEFAULT FALSE,
+    timezone   VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    start_time VARCHAR(5)  NOT NULL DEFAULT '22:00',
+    end_time   VARCHAR(5)  NOT NULL DEFAULT '07:00',
+    quiet_days SMALLINT    NOT NULL DEFAULT 0, -- bitmask, bit 0 = Sunday
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS deferred_notifications (
+    id            UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id       UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel       VARCHAR(16)  NOT NULL,
+    type          VARCHAR(64)  NOT NULL,
+    title         VARCHAR(255) NOT NULL,
+    body          TEXT         NOT NULL DEFAULT '',
+    data          JSONB        NOT NULL DEFAULT '{}',
+    event_count   INT          NOT NULL DEFAULT 1,
+    deliver_after TIMESTAMPTZ  NOT NULL,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_deferred_notifications_due ON deferred_notifications (deliver_after);
+
+
+-- migrations/008_add_notification_snooze.sql
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS snoozed_until TIMESTAMPTZ;
+
+CREATE INDEX idx_notifications_snoozed ON notifications (snoozed_until) WHERE snoozed_until IS NOT NULL;
+
+
+-- migrations/009_create_webhooks.sql
+CREATE TABLE IF NOT EXISTS webhooks (
+    id              UUID          PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id         UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url             VARCHAR(2048) NOT NULL,
+    secret          VARCHAR(128)  NOT NULL,
+    events          TEXT[]        NOT NULL,
+    payload_version VARCHAR(8)    NOT NULL DEFAULT 'v2',
+    active          BOOLEAN       NOT NULL DEFAULT TRUE,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_webhooks_user ON webhooks (user_id) WHERE active;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    webhook_id      UUID        NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event           VARCHAR(64) NOT NULL,
+    payload_version VARCHAR(8)  NOT NULL,
+    payload         JSONB       NOT NULL,
+    status          VARCHAR(16) NOT NULL DEFAULT 'pending',
+    response_status INT,
+    error           TEXT        NOT NULL DEFAULT '',
+    attempts        INT         NOT NULL DEFAULT 0,
+    redelivery_of   UUID        REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+    last_attempt_at TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Delivered payloads are kept for 30 days so integrators can request redelivery.
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_created ON webhook_deliveries (created_at);
+
+
+-- migrations/010_add_users_is_admin.sql
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+-- migrations/011_create_retention_overrides.sql
+-- Per-user replacements for the RETENTION_*_DAYS defaults used by the purge job.
+CREATE TABLE IF NOT EXISTS retention_overrides (
+    user_id        UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    entity_type    VARCHAR(32) NOT NULL,
+    retention_days INT         NOT NULL CHECK (retention_days > 0),
+    updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, entity_type)
+);
+
+CREATE INDEX idx_tasks_deleted_at    ON tasks (deleted_at)    WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_projects_deleted_at ON projects (deleted_at) WHERE deleted_at IS NOT NULL;
+
+
+-- migrations/012_create_task_events.sql
+-- Append-only audit log; each row holds the task as persisted by that change.
+CREATE TABLE IF NOT EXISTS task_events (
+    id         UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id    UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event      VARCHAR(64) NOT NULL,
+    snapshot   JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_events_task_created ON task_events (task_id, created_at DESC);
+
+
+-- migrations/013_add_tasks_updated_at_index.sql
+-- Serves GET /tasks?modified_since=. Not partial: soft-deleted rows must show up too.
+CREATE INDEX idx_tasks_user_updated_at ON tasks (user_id, updated_at);
+
+
+-- migrations/014_add_tasks_title_trgm.sql
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops) WHERE deleted_at IS NULL;
+
+
+-- migrations/015_add_users_ranker.sql
+-- NULL leaves the user to experiment assignment or the default ranker.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS ranker VARCHAR(64);
+
+
+-- migrations/016_add_tasks_parent_id.sql
+-- Subtasks are ordinary tasks pointing at their parent; deleting the parent removes them.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS parent_id UUID REFERENCES tasks(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_tasks_parent_id ON tasks (parent_id) WHERE parent_id IS NOT NULL;
+
+
+-- migrations/017_create_tags.sql
+CREATE TABLE IF NOT EXISTS tags (
+    id         UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(50) NOT NULL,
+    color      VARCHAR(7)  NOT NULL DEFAULT '#64748B',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_tags_user_name ON tags (user_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS task_tags (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    tag_id  UUID NOT NULL REFERENCES tags(id)  ON DELETE CASCADE,
+    PRIMARY KEY (task_id, tag_id)
+);
+
+CREATE INDEX idx_task_tags_tag_id ON task_tags (tag_id);
+
+
+-- migrations/018_create_due_date_rules.sql
+CREATE TABLE IF NOT EXISTS due_date_rules (
+    id         UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    enabled    BOOLEAN      NOT NULL DEFAULT TRUE,
+    position   INT          NOT NULL DEFAULT 0,
+    timezone   VARCHAR(64)  NOT NULL DEFAULT 'UTC',
+    condition  JSONB        NOT NULL DEFAULT '{}',
+    action     JSONB        NOT NULL,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_due_date_rules_user ON due_date_rules (user_id, position);
+
+
+-- migrations/019_create_task_dependencies.sql
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id       UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked_by ON task_dependencies (blocked_by_id);
+
+
+-- migrations/020_create_automation_rules.sql
+CREATE TABLE IF NOT EXISTS automation_rules (
+    id         UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       VARCHAR(100) NOT NULL,
+    enabled    BOOLEAN      NOT NULL DEFAULT TRUE,
+    trigger    VARCHAR(32)  NOT NULL,
+    condition  JSONB        NOT NULL DEFAULT '{}',
+    actions    JSONB        NOT NULL,
+    async      BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_automation_rules_trigger ON automation_rules (trigger, user_id) WHERE enabled;
+
+CREATE TABLE IF NOT EXISTS automation_executions (
+    id              UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id         UUID        NOT NULL REFERENCES automation_rules(id) ON DELETE CASCADE,
+    user_id         UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id         UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    trigger         VARCHAR(32) NOT NULL,
+    status          VARCHAR(16) NOT NULL,
+    actions_applied INT         NOT NULL DEFAULT 0,
+    error           TEXT,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_automation_executions_rule ON automation_executions (rule_id, created_at DESC);
+CREATE INDEX idx_automation_executions_task ON automation_executions (rule_id, task_id, created_at DESC);
+
+
+-- migrations/021_create_attachments.sql
+CREATE TABLE IF NOT EXISTS attachments (
+    id           UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id      UUID         NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id      UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename     VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size_bytes   BIGINT       NOT NULL CHECK (size_bytes > 0),
+    storage_key  TEXT         NOT NULL UNIQUE,
+    status       VARCHAR(16)  NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    uploaded_at  TIMESTAMPTZ
+);
+
+CREATE INDEX idx_attachments_task ON attachments (task_id, created_at);
+CREATE INDEX idx_attachments_pending ON attachments (created_at) WHERE status = 'pending';
+
+
+-- migrations/022_add_circuit_breakers.sql
+ALTER TABLE automation_rules
+    ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS breaker_open_until   TIMESTAMPTZ;
+
+ALTER TABLE webhooks
+    ADD COLUMN IF NOT EXISTS consecutive_failures INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS breaker_open_until   TIMESTAMPTZ;
+
+
+-- migrations/023_create_task_reminders.sql
+CREATE TABLE IF NOT EXISTS task_reminders (
+    id             UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id        UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id        UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    remind_at      TIMESTAMPTZ,
+    offset_minutes INT         CHECK (offset_minutes >= 0),
+    fire_at        TIMESTAMPTZ, -- NULL while an offset reminder's task has no due date
+    status         VARCHAR(16) NOT NULL DEFAULT 'pending',
+    fired_at       TIMESTAMPTZ,
+    dismissed_at   TIMESTAMPTZ,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((remind_at IS NULL) <> (offset_minutes IS NULL))
+);
+
+CREATE INDEX idx_task_reminders_task ON task_reminders (task_id, created_at);
+CREATE INDEX idx_task_reminders_due ON task_reminders (fire_at) WHERE status = 'pending';
+
+
+-- migrations/024_create_time_entries.sql
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tracked_seconds BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id               UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id          UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id          UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    started_at       TIMESTAMPTZ NOT NULL,
+    stopped_at       TIMESTAMPTZ,
+    duration_seconds BIGINT      CHECK (duration_seconds >= 0),
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_time_entries_task ON time_entries (task_id, started_at);
+-- At most one running timer per user.
+CREATE UNIQUE INDEX idx_time_entries_running ON time_entries (user_id) WHERE stopped_at IS NULL;
+
+
+-- migrations/025_create_invite_codes.sql
+CREATE TABLE IF NOT EXISTS invite_codes (
+    id         UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code       VARCHAR(32) NOT NULL UNIQUE,
+    created_by UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    max_uses   INT         NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    uses       INT         NOT NULL DEFAULT 0 CHECK (uses >= 0 AND uses <= max_uses),
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_invite_codes_creator ON invite_codes (created_by, created_at);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS invite_code_id UUID REFERENCES invite_codes(id) ON DELETE SET NULL;
+
+
+-- migrations/026_add_task_archiving.sql
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_archived ON tasks (user_id, archived_at) WHERE archived_at IS NOT NULL AND deleted_at IS NULL;
+
+
+-- migrations/027_create_referrals.sql
+ALTER TABLE users ADD COLUMN IF NOT EXISTS referral_code VARCHAR(16);
+UPDATE users SET referral_code = upper(substr(md5(id::text), 1, 10)) WHERE referral_code IS NULL;
+ALTER TABLE users ALTER COLUMN referral_code SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_referral_code ON users (referral_code);
+
+CREATE TABLE IF NOT EXISTS referrals (
+    id           UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    referrer_id  UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referred_id  UUID        NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    code         VARCHAR(32) NOT NULL,
+    source       VARCHAR(10) NOT NULL CHECK (source IN ('code', 'link', 'invite')),
+    status       VARCHAR(10) NOT NULL DEFAULT 'signed_up' CHECK (status IN ('signed_up', 'qualified', 'credited')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    qualified_at TIMESTAMPTZ,
+    credited_at  TIMESTAMPTZ,
+    CHECK (referrer_id <> referred_id)
+);
+
+CREATE INDEX idx_referrals_referrer ON referrals (referrer_id, created_at DESC);
+CREATE INDEX idx_referrals_qualified ON referrals (qualified_at) WHERE status = 'qualified';
+
+
+-- migrations/028_add_task_sort_order.sql
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sort_order DOUBLE PRECISION NOT NULL DEFAULT 0;
+UPDATE tasks SET sort_order = floor(extract(epoch FROM created_at) * 1000);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_sort_order ON tasks (user_id, sort_order) WHERE deleted_at IS NULL;
+
+
+-- migrations/029_create_changelog.sql
+CREATE TABLE IF NOT EXISTS changelog_entries (
+    id           UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version      VARCHAR(32)  NOT NULL UNIQUE,
+    title        VARCHAR(200) NOT NULL,
+    body         TEXT         NOT NULL DEFAULT '',
+    published_at TIMESTAMPTZ  NOT NULL,
+    created_by   UUID         REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_changelog_published ON changelog_entries (published_at DESC);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS changelog_seen_at TIMESTAMPTZ;
+
+
+-- migrations/030_add_task_events_changes.sql
+-- JSON null marks events recorded before changes were tracked.
+ALTER TABLE task_events ADD COLUMN IF NOT EXISTS changes JSONB NOT NULL DEFAULT 'null'::jsonb;
+
+
+-- migrations/031_create_feedback.sql
+CREATE TABLE IF NOT EXISTS feedback (
+    id             UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id        UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category       VARCHAR(10) NOT NULL CHECK (category IN ('bug', 'idea', 'question', 'other')),
+    message        TEXT        NOT NULL,
+    client_version VARCHAR(64) NOT NULL DEFAULT '',
+    diagnostics    JSONB       NOT NULL DEFAULT '{}'::jsonb,
+    user_agent     TEXT        NOT NULL DEFAULT '',
+    forwarded_at   TIMESTAMPTZ,
+    forward_error  TEXT        NOT NULL DEFAULT '',
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_feedback_created ON feedback (created_at DESC);
+CREATE INDEX idx_feedback_user ON feedback (user_id, created_at DESC);
+
+
+-- migrations/032_create_client_errors.sql
+CREATE TABLE IF NOT EXISTS client_errors (
+    id             UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id        UUID         REFERENCES users(id) ON DELETE SET NULL,
+    fingerprint    VARCHAR(32)  NOT NULL,
+    kind           VARCHAR(100) NOT NULL DEFAULT '',
+    message        TEXT         NOT NULL,
+    stack          TEXT         NOT NULL DEFAULT '',
+    url            TEXT         NOT NULL DEFAULT '',
+    request_id     VARCHAR(64)  NOT NULL DEFAULT '',
+    client_version VARCHAR(64)  NOT NULL DEFAULT '',
+    platform       VARCHAR(100) NOT NULL DEFAULT '',
+    user_agent     TEXT         NOT NULL DEFAULT '',
+    context        JSONB        NOT NULL DEFAULT '{}'::jsonb,
+    occurred_at    TIMESTAMPTZ  NOT NULL,
+    received_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_client_errors_received ON client_errors (received_at DESC);
+CREATE INDEX idx_client_errors_fingerprint ON client_errors (fingerprint, received_at DESC);
+CREATE INDEX idx_client_errors_request ON client_errors (request_id) WHERE request_id <> '';
+
+
+-- migrations/033_add_task_recurrence.sql
+-- A recurring task is one row that reopens on completion; each completed
+-- occurrence is kept in task_occurrences.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence JSONB;
+
+CREATE TABLE IF NOT EXISTS task_occurrences (
+    id           UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id      UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id      UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    due_date     TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ NOT NULL,
+    on_time      BOOLEAN     NOT NULL
+);
+
+CREATE INDEX idx_task_occurrences_task ON task_occurrences (task_id, completed_at DESC);
+CREATE INDEX idx_task_occurrences_user ON task_occurrences (user_id, completed_at);
+
+
+-- migrations/034_create_business_calendars.sql
+-- A user's working week, used to move due dates off weekends and holidays.
+CREATE TABLE IF NOT EXISTS user_business_calendars (
+    user_id         UUID        PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    country         VARCHAR(2),             -- holiday calendar code; NULL for none
+    timezone        VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    weekend_days    SMALLINT    NOT NULL DEFAULT 65, -- bitmask, bit 0 = Sunday
+    shift_due_dates BOOLEAN     NOT NULL DEFAULT FALSE,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS user_days_off (
+    id         UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    date       DATE         NOT NULL,
+    name       VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, date)
+);
+
+
+-- migrations/035_add_users_timezone.sql
+-- The zone "due today" and date-only due dates are evaluated in.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+
+-- migrations/036_add_task_escalations.sql
+-- Priority auto-escalation: a per-task opt-out and the audit trail of raises.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS no_escalation BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS task_escalations (
+    id            UUID          PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id       UUID          NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id       UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_priority task_priority NOT NULL,
+    to_priority   task_priority NOT NULL,
+    due_date      TIMESTAMPTZ   NOT NULL,
+    escalated_at  TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_escalations_task ON task_escalations (task_id, escalated_at DESC);
+CREATE INDEX idx_task_escalations_due ON task_escalations (task_id, due_date);
+
+
+-- migrations/037_add_business_calendar_working_hours.sql
+-- Working hours of a business day, and whether overdue time, workload and
+-- slot suggestions count only them.
+ALTER TABLE user_business_calendars ADD COLUMN IF NOT EXISTS work_start        VARCHAR(5) NOT NULL DEFAULT '09:00';
+ALTER TABLE user_business_calendars ADD COLUMN IF NOT EXISTS work_end          VARCHAR(5) NOT NULL DEFAULT '17:00';
+ALTER TABLE user_business_calendars ADD COLUMN IF NOT EXISTS working_time_only BOOLEAN    NOT NULL DEFAULT FALSE;
+
+
+-- migrations/038_add_tasks_due_expr.sql
+-- Symbolic due dates ("end of month") the due date was resolved from.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_expr VARCHAR(100);
+
+
+-- migrations/039_add_tasks_start_date.sql
+-- Deferred tasks stay out of default lists and the Today view until then.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS start_date TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_start_date ON tasks (start_date)
+    WHERE deleted_at IS NULL AND start_date IS NOT NULL AND status != 'done';
+
+
+-- migrations/040_add_tasks_schedule.sql
+-- Time slots a task is planned for, independent of its due date, and the
+-- secret tokens of users' iCalendar feeds.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS scheduled_at       TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS scheduled_duration INT CHECK (scheduled_duration > 0);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_scheduled ON tasks (user_id, scheduled_at)
+    WHERE deleted_at IS NULL AND scheduled_at IS NOT NULL AND status != 'done';
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id    UUID        PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token      VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- migrations/041_add_tasks_effort_exceeded.sql
+-- Set by the nudge worker on in-progress tasks left untouched past their
+-- estimate; cleared by the next update or logged time.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS effort_exceeded BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_in_progress ON tasks (updated_at)
+    WHERE status = 'in_progress' AND NOT effort_exceeded AND deleted_at IS NULL;
+
+
+-- migrations/042_create_task_revisions.sql
+-- Descriptions replaced by updates, pruned to the last few per task.
+CREATE TABLE IF NOT EXISTS task_revisions (
+    id          UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id     UUID        NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id     UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    description TEXT        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_revisions_task_created ON task_revisions (task_id, created_at DESC);
+
+
+-- migrations/043_add_tasks_actual_hours.sql
+-- How long a finished task took, compared with estimated_hours in analytics.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS actual_hours NUMERIC(6,2) CHECK (actual_hours >= 0);
+
+
+-- migrations/044_add_tasks_pinned.sql
+-- Pinned tasks sort above all others in lists.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+
+-- migrations/045_add_row_versions.sql
+-- Bumped by every update so clients can send If-Match and have stale
+-- writes rejected rather than silently overwriting another device's.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_version() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version := OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_tasks_version BEFORE UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+CREATE TRIGGER trg_projects_version BEFORE UPDATE ON projects
+    FOR EACH ROW EXECUTE FUNCTION bump_version();
+
+
+-- migrations/046_reindex_tasks_overdue.sql
+-- Overdue scans page by (due_date, id) and skip archived tasks; cover both.
+DROP INDEX IF EXISTS idx_tasks_overdue;
+CREATE INDEX IF NOT EXISTS idx_tasks_overdue ON tasks (user_id, due_date, id)
+    WHERE deleted_at IS NULL AND archived_at IS NULL AND status != 'done' AND due_date IS NOT NULL;
+
+
+-- migrations/047_create_operations.sql
+-- Long-running requests run in the background; clients poll the row for
+-- progress and the outcome. Finished rows are pruned after a day.
+CREATE TABLE IF NOT EXISTS operations (
+    id               UUID         PRIMARY KEY,
+    user_id          UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind             VARCHAR(50)  NOT NULL,
+    status           VARCHAR(20)  NOT NULL DEFAULT 'pending'
+                     CHECK (status IN ('pending', 'running', 'succeeded', 'failed', 'cancelled')),
+    progress         SMALLINT     NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    result           JSONB,
+    error            TEXT,
+    file             BYTEA,
+    file_name        VARCHAR(255),
+    file_type        VARCHAR(100),
+    cancel_requested BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    started_at       TIMESTAMPTZ,
+    finished_at      TIMESTAMPTZ,
+    updated_at       TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_operations_unfinished ON operations (updated_at) WHERE status IN ('pending', 'running');
+CREATE INDEX idx_operations_finished ON operations (finished_at) WHERE finished_at IS NOT NULL;
+
+
+-- migrations/048_create_task_links.sql
+CREATE TABLE IF NOT EXISTS task_links (
+    id         UUID          PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id    UUID          NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id    UUID          NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    url        VARCHAR(2048) NOT NULL,
+    label      VARCHAR(200)  NOT NULL,
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    UNIQUE (task_id, url)
+);
+
+
+-- migrations/049_add_task_aging.sql
+-- Priority aging: when each task last changed status, the score aging has
+-- added, and aging raises alongside due-date ones in the escalation trail.
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMPTZ;
+UPDATE tasks SET status_changed_at = COALESCE(completed_at, created_at) WHERE status_changed_at IS NULL;
+ALTER TABLE tasks ALTER COLUMN status_changed_at SET DEFAULT NOW();
+ALTER TABLE tasks ALTER COLUMN status_changed_at SET NOT NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS age_points NUMERIC(10,2) NOT NULL DEFAULT 0;
+
+CREATE INDEX idx_tasks_todo_since ON tasks (status_changed_at) WHERE status = 'todo' AND deleted_at IS NULL;
+
+ALTER TABLE task_escalations ADD COLUMN IF NOT EXISTS reason VARCHAR(20) NOT NULL DEFAULT 'due_date';
+ALTER TABLE task_escalations ALTER COLUMN due_date DROP NOT NULL;
+
+
+-- migrations/050_add_task_energy_context.sql
+-- Energy and context say when a task suits: the focus it takes and where it
+-- can be done (e.g. phone, computer, errand), for the Now view.
+CREATE TYPE task_energy AS ENUM ('low', 'medium', 'high');
+
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS energy  task_energy;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS context VARCHAR(50);
+
+CREATE INDEX idx_tasks_user_context ON tasks (user_id, context) WHERE context IS NOT NULL AND deleted_at IS NULL;
+
+
+-- migrations/051_create_project_members.sql
+-- Users a project is shared with, besides its owner.
+CREATE TABLE IF NOT EXISTS project_members (
+    project_id UUID        NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+CREATE INDEX idx_project_members_user_id ON project_members (user_id);
+
+
+-- migrations/052_index_task_filters.sql
+-- List filters combine status and priority sets per user and bound
+-- created_at; the due-date range is served by idx_tasks_overdue for open
+-- tasks and idx_tasks_due_date otherwise.
+CREATE INDEX IF NOT EXISTS idx_tasks_user_status_priority ON tasks (user_id, status, priority) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_user_created_at ON tasks (user_id, created_at) WHERE deleted_at IS NULL;
+
+
+-- migrations/053_add_project_archiving.sql
+-- Archived projects, and the tasks in them, are left out of default lists
+-- and analytics but stay restorable.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS archived_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_projects_archived ON projects (user_id, archived_at) WHERE archived_at IS NOT NULL AND deleted_at IS NULL;
+
+
+-- migrations/054_create_workspace_branding.sql
+-- The deployment's own branding, edited by admins, over the BRAND_* and
+-- MAIL_* defaults. There is a single row.
+CREATE TABLE IF NOT EXISTS workspace_branding (
+    id                BOOLEAN      PRIMARY KEY DEFAULT TRUE CHECK (id),
+    name              VARCHAR(100) NOT NULL DEFAULT '',
+    logo_url          TEXT         NOT NULL DEFAULT '',
+    accent_color      VARCHAR(9)   NOT NULL DEFAULT '',
+    email_from_domain VARCHAR(253) NOT NULL DEFAULT '',
+    dkim_selector     VARCHAR(63)  NOT NULL DEFAULT '',
+    dkim_status       VARCHAR(20)  NOT NULL DEFAULT 'unconfigured',
+    dkim_checked_at   TIMESTAMPTZ,
+    updated_at        TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+
+-- migrations/055_create_metering_events.sql
+-- Daily usage per user for usage-based pricing. The metering.record job
+-- upserts each UTC day's rows until the billing module, which reads rows
+-- with invoiced_at unset, bills them and stamps the invoice columns; an
+-- invoiced row is never changed again.
+CREATE TABLE IF NOT EXISTS metering_events (
+    id                UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id           UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    metric            VARCHAR(32) NOT NULL,
+    day               DATE        NOT NULL,
+    quantity          BIGINT      NOT NULL CHECK (quantity >= 0),
+    recorded_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    invoice_id        VARCHAR(128),
+    invoiced_quantity BIGINT,
+    invoiced_at       TIMESTAMPTZ,
+    UNIQUE (user_id, metric, day)
+);
+
+CREATE INDEX idx_metering_events_day ON metering_events (day, metric);
+CREATE INDEX idx_metering_events_uninvoiced ON metering_events (day) WHERE invoiced_at IS NULL;
+
+
+-- migrations/056_create_instance_settings.sql
+-- Settings admins change at runtime. A NULL column keeps the value from the
+-- environment; there is a single row.
+CREATE TABLE IF NOT EXISTS instance_settings (
+    id               BOOLEAN     PRIMARY KEY DEFAULT TRUE CHECK (id),
+    signup_mode      VARCHAR(20),
+    invite_quota     INTEGER     CHECK (invite_quota >= 0),
+    max_upload_bytes BIGINT      CHECK (max_upload_bytes >= 0),
+    revisions_keep   INTEGER     CHECK (revisions_keep >= 0),
+    disabled_modules TEXT[],
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+
+-- migrations/057_create_backups.sql
+-- Logical backups kept in object storage. Backups and restores leave this
+-- table out so the list survives restoring an older backup, which is also
+-- why created_by has no foreign key.
+CREATE TABLE IF NOT EXISTS backups (
+    id          UUID        PRIMARY KEY,
+    kind        VARCHAR(16) NOT NULL CHECK (kind IN ('manual', 'scheduled', 'pre_restore')),
+    status      VARCHAR(16) NOT NULL DEFAULT 'running' CHECK (status IN ('running', 'succeeded', 'failed')),
+    storage_key TEXT        NOT NULL UNIQUE,
+    size_bytes  BIGINT      NOT NULL DEFAULT 0,
+    checksum    VARCHAR(64) NOT NULL DEFAULT '',
+    row_count   BIGINT      NOT NULL DEFAULT 0,
+    error       TEXT,
+    created_by  UUID,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMPTZ,
+    restored_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_backups_kind_created ON backups (kind, created_at DESC);
+
+
+-- migrations/058_add_projects_icon.sql
+-- An emoji or a named icon shown next to the project name; empty for none.
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS icon VARCHAR(32) NOT NULL DEFAULT '';
+
+
+-- migrations/059_create_scheduled_task_claims.sql
+-- The interval slot each scheduled task last ran for and the instance that
+-- ran it. Instances sharing the database claim a slot before running the
+-- task, so it runs once per interval however many of them there are.
+CREATE TABLE IF NOT EXISTS scheduled_task_claims (
+    task       VARCHAR(100) PRIMARY KEY,
+    slot       TIMESTAMPTZ  NOT NULL,
+    holder     VARCHAR(255) NOT NULL,
+    claimed_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+
+-- migrations/060_create_two_factor.sql
+-- TOTP enrollments: pending from setup until a first code is verified
+-- (enabled_at). last_step is the time step of the last accepted code, so
+-- none is accepted twice; failed_attempts counts wrong codes since the last
+-- right one, for the lockout.
+CREATE TABLE IF NOT EXISTS user_two_factor (
+    user_id         UUID        PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret          VARCHAR(64) NOT NULL,
+    enabled_at      TIMESTAMPTZ,
+    last_step       BIGINT      NOT NULL DEFAULT 0,
+    failed_attempts INT         NOT NULL DEFAULT 0,
+    last_failed_at  TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Single-use recovery codes, stored as SHA-256 digests.
+CREATE TABLE IF NOT EXISTS two_factor_recovery_codes (
+    id         UUID        PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id    UUID        NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash  CHAR(64)    NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, code_hash)
+);
+
+
+-- migrations/061_create_personal_access_tokens.sql
+-- Long-lived tokens for scripts and integrations. Only a SHA-256 digest of
+-- each token is stored; hint is its first characters, for lists.
+CREATE TABLE IF NOT EXISTS personal_access_tokens (
+    id           UUID         PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id      UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         VARCHAR(100) NOT NULL,
+    scopes       TEXT[]       NOT NULL,
+    hint         VARCHAR(16)  NOT NULL,
+    token_hash   CHAR(64)     NOT NULL UNIQUE,
+    expires_at   TIMESTAMPTZ,
+    last_used_at TIMESTAMPTZ,
+    revoked_at   TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_personal_access_tokens_user ON personal_access_tokens (user_id, created_at);
+
+
+-- migrations/062_task_version_user_edits.sql
+-- Background upkeep (smart scores, aging, the effort nudge) rewrites tasks
+-- without anyone editing them. Only a change to another column bumps a
+-- task's version, so an ETag a client holds stays valid across that upkeep.
+CREATE OR REPLACE FUNCTION bump_task_version() RETURNS TRIGGER AS $$
+DECLARE
+    upkeep TEXT[] := ARRAY['smart_score', 'age_points', 'effort_exceeded', 'updated_at', 'version'];
+BEGIN
+    IF (to_jsonb(NEW) - upkeep) IS DISTINCT FROM (to_jsonb(OLD) - upkeep) THEN
+        NEW.version := OLD.version + 1;
+    ELSE
+        NEW.version := OLD.version;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_tasks_version ON tasks;
+CREATE TRIGGER trg_tasks_version BEFORE UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION bump_task_version();
@@ -0,0 +1,53 @@
+package awsv4
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unsignedPayload is the payload hash used by presigned URLs, whose body is
+// not known when the URL is signed.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// Presign returns a copy of u carrying SigV4 query authentication, valid for
+// expires. Any headers given are signed, so the eventual request must send
+// them with exactly these values.
+func (s *Signer) Presign(method string, u *url.URL, headers http.Header, expires time.Duration, now time.Time) *url.URL {
+	now = now.UTC()
+	amzDate := now.Format(amzDateFormat)
+	scope := s.scope(now)
+
+	signed := map[string]string{"host": u.Host}
+	for key, values := range headers {
+		signed[strings.ToLower(key)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	signedHeaders, canonicalHeaders := canonicalizeHeaders(signed)
+
+	out := *u
+	query := out.Query()
+	query.Set("X-Amz-Algorithm", algorithm)
+	query.Set("X-Amz-Credential", s.AccessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", signedHeaders)
+	if s.SessionToken != "" {
+		query.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		canonicalURI(out.EscapedPath()),
+		canonicalQuery(query),
+		canonicalHeaders,
+		signedHeaders,
+		unsignedPayload,
+	}, "\n")
+	signature := s.signature(now, scope, canonicalRequest, amzDate)
+
+	out.RawQuery = fmt.Sprintf("%s&X-Amz-Signature=%s", canonicalQuery(query), signature)
+	return &out
+}
@@ -0,0 +1,68 @@
+// Package cache provides a small in-process cache with per-entry expiry.
+package cache
+
+import (
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+// TTL is a concurrency-safe map whose entries expire ttl after being set.
+// Expired entries are dropped lazily on access and by Set once the map has
+// grown past the size it had at the previous sweep.
+type TTL[K comparable, V any] struct {
+	ttl       time.Duration
+	mu        sync.Mutex
+	items     map[K]entry[V]
+	sweepSize int
+}
+
+// NewTTL creates an empty TTL cache.
+func NewTTL[K comparable, V any](ttl time.Duration) *TTL[K, V] {
+	return &TTL[K, V]{ttl: ttl, items: make(map[K]entry[V]), sweepSize: 64}
+}
+
+// Get returns the cached value for key, if present and not expired.
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.items[key]
+	if !ok || time.Now().After(e.expiresAt) {
+		delete(c.items, key)
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key.
+func (c *TTL[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.items[key] = entry[V]{value: value, expiresAt: now.Add(c.ttl)}
+	if len(c.items) >= c.sweepSize {
+		for k, e := range c.items {
+			if now.After(e.expiresAt) {
+				delete(c.items, k)
+			}
+		}
+		c.sweepSize = 2 * len(c.items)
+		if c.sweepSize < 64 {
+			c.sweepSize = 64
+		}
+	}
+}
+
+// Delete removes key.
+func (c *TTL[K, V]) Delete(key K) {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+}
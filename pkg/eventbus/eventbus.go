@@ -0,0 +1,190 @@
+// Package eventbus delivers in-process domain events to the subscribers of
+// their type.
+//
+// Subscribers are registered by the Go type they accept, which may be an
+// interface so one subscriber receives a whole family of events. A
+// synchronous subscriber runs inside Publish, in registration order, with
+// the publisher's context; an asynchronous one runs on the bus's workers
+// with a context that keeps the publisher's values but not its deadline or
+// cancellation, so work started by a request outlives the response.
+// Asynchronous events are held in memory: those still queued when the
+// process exits are lost.
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Event is a fact published on a Bus. EventName identifies the kind, e.g.
+// "task.completed".
+type Event interface {
+	EventName() string
+}
+
+// Config tunes asynchronous delivery.
+type Config struct {
+	Workers    int // goroutines running async subscribers
+	BufferSize int // queued deliveries before Publish runs them inline
+}
+
+type subscriber struct {
+	name    string
+	async   bool
+	accepts func(Event) bool
+	handle  func(ctx context.Context, e Event) error
+}
+
+type delivery struct {
+	ctx   context.Context
+	sub   *subscriber
+	event Event
+}
+
+// Bus routes published events to subscribers.
+type Bus struct {
+	log    *logrus.Logger
+	mu     sync.RWMutex
+	subs   []*subscriber
+	queue  chan delivery
+	wg     sync.WaitGroup
+	closed bool
+}
+
+// New creates a Bus and starts its async workers.
+func New(cfg Config, log *logrus.Logger) *Bus {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	b := &Bus{log: log, queue: make(chan delivery, cfg.BufferSize)}
+	for i := 0; i < cfg.Workers; i++ {
+		b.wg.Add(1)
+		go b.work()
+	}
+	return b
+}
+
+// Subscribe registers fn to run inside Publish for every event assignable
+// to E. An error is logged; it does not reach the publisher or stop the
+// other subscribers. Must be called before serving requests.
+func Subscribe[E Event](b *Bus, name string, fn func(ctx context.Context, e E) error) {
+	b.add(newSubscriber(name, false, fn))
+}
+
+// SubscribeAsync registers fn to run on the bus's workers for every event
+// assignable to E. Must be called before serving requests.
+func SubscribeAsync[E Event](b *Bus, name string, fn func(ctx context.Context, e E) error) {
+	b.add(newSubscriber(name, true, fn))
+}
+
+func newSubscriber[E Event](name string, async bool, fn func(ctx context.Context, e E) error) *subscriber {
+	return &subscriber{
+		name:  name,
+		async: async,
+		accepts: func(e Event) bool {
+			_, ok := e.(E)
+			return ok
+		},
+		handle: func(ctx context.Context, e Event) error {
+			return fn(ctx, e.(E))
+		},
+	}
+}
+
+func (b *Bus) add(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+}
+
+// Publish delivers e to its subscribers: the synchronous ones before it
+// returns, the asynchronous ones queued. When the queue is full, or the bus
+// is closed, async subscribers run inline instead of losing the event.
+// A nil Bus publishes nothing.
+func (b *Bus) Publish(ctx context.Context, e Event) {
+	if b == nil {
+		return
+	}
+	// Subscribers may publish in turn, so none runs under the lock.
+	b.mu.RLock()
+	subs := b.subs
+	b.mu.RUnlock()
+
+	for _, s := range subs {
+		if !s.accepts(e) {
+			continue
+		}
+		if !s.async {
+			b.run(ctx, s, e)
+			continue
+		}
+		d := delivery{ctx: context.WithoutCancel(ctx), sub: s, event: e}
+		if !b.enqueue(d) {
+			b.run(d.ctx, s, e)
+		}
+	}
+}
+
+// enqueue queues d for the workers, reporting false when the bus is closed
+// or the queue is full.
+func (b *Bus) enqueue(d delivery) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return false
+	}
+	select {
+	case b.queue <- d:
+		return true
+	default:
+		b.log.WithFields(logrus.Fields{"event": d.event.EventName(), "subscriber": d.sub.name}).
+			Warn("event queue full; delivering inline")
+		return false
+	}
+}
+
+// Close stops accepting async deliveries and waits for the queued ones to
+// finish, or for ctx to be done.
+func (b *Bus) Close(ctx context.Context) {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		b.log.Warn("event bus closed with deliveries still running")
+	}
+}
+
+func (b *Bus) work() {
+	defer b.wg.Done()
+	for d := range b.queue {
+		b.run(d.ctx, d.sub, d.event)
+	}
+}
+
+// run calls one subscriber, logging its error or panic.
+func (b *Bus) run(ctx context.Context, s *subscriber, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.log.WithFields(logrus.Fields{"event": e.EventName(), "subscriber": s.name}).
+				Error(fmt.Sprintf("event subscriber panicked: %v", r))
+		}
+	}()
+	if err := s.handle(ctx, e); err != nil {
+		b.log.WithError(err).WithFields(logrus.Fields{"event": e.EventName(), "subscriber": s.name}).
+			Error("event subscriber failed")
+	}
+}
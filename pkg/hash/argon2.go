@@ -0,0 +1,137 @@
+package hash
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Argon2Params tunes argon2id. Memory is in KiB.
+type Argon2Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2Params is the second recommended option of RFC 9106, for
+// machines without 2 GiB to spare per login: 64 MiB and three passes.
+var DefaultArgon2Params = Argon2Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 4,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+const argon2Prefix = "$argon2id$"
+
+// Upper bounds on the cost a digest may ask for. They cap what one verify
+// of a corrupted or planted digest can allocate and spend; the heaviest RFC
+// 9106 option needs 2 GiB and one pass.
+const (
+	maxArgon2Memory     = 2 * 1024 * 1024
+	maxArgon2Iterations = 64
+)
+
+var b64 = base64.RawStdEncoding
+
+type argon2id struct {
+	params Argon2Params
+}
+
+func newArgon2(p Argon2Params) (*argon2id, error) {
+	d := DefaultArgon2Params
+	if p.Memory == 0 {
+		p.Memory = d.Memory
+	}
+	if p.Iterations == 0 {
+		p.Iterations = d.Iterations
+	}
+	if p.Parallelism == 0 {
+		p.Parallelism = d.Parallelism
+	}
+	if p.SaltLength == 0 {
+		p.SaltLength = d.SaltLength
+	}
+	if p.KeyLength == 0 {
+		p.KeyLength = d.KeyLength
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &argon2id{params: p}, nil
+}
+
+// validate checks p against the bounds argon2id accepts and the ones this
+// package allows, for configured parameters and stored digests alike.
+func (p Argon2Params) validate() error {
+	if p.Iterations == 0 || p.Parallelism == 0 {
+		return fmt.Errorf("hash: argon2id needs at least one pass and one lane")
+	}
+	if p.Memory < 8*uint32(p.Parallelism) {
+		return fmt.Errorf("hash: argon2id memory must be at least 8 KiB per lane")
+	}
+	if p.Memory > maxArgon2Memory || p.Iterations > maxArgon2Iterations {
+		return fmt.Errorf("hash: argon2id allows at most %d KiB and %d passes", maxArgon2Memory, maxArgon2Iterations)
+	}
+	if p.SaltLength < 8 || p.KeyLength < 16 {
+		return fmt.Errorf("hash: argon2id needs a salt of 8 bytes and a key of 16 at least")
+	}
+	return nil
+}
+
+func (a *argon2id) owns(digest string) bool {
+	return strings.HasPrefix(digest, argon2Prefix)
+}
+
+func (a *argon2id) hash(plain string) (string, error) {
+	p := a.params
+	salt := make([]byte, p.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("argon2id: %w", err)
+	}
+	key := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2Prefix, argon2.Version, p.Memory, p.Iterations, p.Parallelism, b64.EncodeToString(salt), b64.EncodeToString(key),
+	), nil
+}
+
+func (a *argon2id) verify(plain, digest string) (bool, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(digest, "$")
+	if len(parts) != 6 {
+		return false, ErrUnknownFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrUnknownFormat
+	}
+	var p Argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return false, ErrUnknownFormat
+	}
+	salt, err := b64.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrUnknownFormat
+	}
+	key, err := b64.DecodeString(parts[5])
+	if err != nil {
+		return false, ErrUnknownFormat
+	}
+	p.SaltLength, p.KeyLength = uint32(len(salt)), uint32(len(key))
+	if p.validate() != nil {
+		return false, ErrUnknownFormat
+	}
+
+	got := argon2.IDKey([]byte(plain), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+	if subtle.ConstantTimeCompare(got, key) != 1 {
+		return false, ErrMismatch
+	}
+	return p == a.params, nil
+}
@@ -0,0 +1,47 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type bcryptHash struct {
+	cost int
+}
+
+func newBcrypt(cost int) (*bcryptHash, error) {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("hash: bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+	}
+	return &bcryptHash{cost: cost}, nil
+}
+
+func (b *bcryptHash) owns(digest string) bool {
+	return strings.HasPrefix(digest, "$2a$") || strings.HasPrefix(digest, "$2b$") || strings.HasPrefix(digest, "$2y$")
+}
+
+func (b *bcryptHash) hash(plain string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), b.cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt: %w", err)
+	}
+	return string(hashed), nil
+}
+
+func (b *bcryptHash) verify(plain, digest string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(digest), []byte(plain))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, ErrMismatch
+	}
+	if err != nil {
+		return false, fmt.Errorf("bcrypt: %w", err)
+	}
+	cost, err := bcrypt.Cost([]byte(digest))
+	return err == nil && cost == b.cost, nil
+}
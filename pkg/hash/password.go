@@ -1,24 +1,99 @@
+// Package hash hashes and verifies passwords.
+//
+// Digests are self-describing strings, so one store can hold digests of
+// several algorithms and parameters: argon2id digests use the PHC string
+// format ($argon2id$v=19$m=...,t=...,p=...$salt$key) and bcrypt ones its
+// own ($2a$cost$...). A Hasher writes new digests with one configured
+// algorithm and verifies any supported one, reporting when a digest
+// should be replaced so callers can upgrade it after a successful login.
 package hash
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+)
+
+// Algorithm names, as accepted by Config.
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
 
-	"golang.org/x/crypto/bcrypt"
+var (
+	// ErrMismatch means the password does not match the digest.
+	ErrMismatch = errors.New("hash: password does not match")
+	// ErrUnknownFormat means no supported algorithm wrote the digest.
+	ErrUnknownFormat = errors.New("hash: unknown digest format")
 )
 
-const defaultCost = bcrypt.DefaultCost
+// Config selects the algorithm for new digests and its parameters. Zero
+// values take the defaults.
+type Config struct {
+	Algorithm  string // argon2id (default) or bcrypt
+	BcryptCost int
+	Argon2     Argon2Params
+}
+
+// algorithm is one digest format.
+type algorithm interface {
+	// owns reports whether digest is in this algorithm's format.
+	owns(digest string) bool
+	hash(plain string) (string, error)
+	// verify checks plain against digest and reports whether the digest
+	// was written with this algorithm's current parameters.
+	verify(plain, digest string) (current bool, err error)
+}
+
+// Hasher writes digests with its configured algorithm and verifies digests
+// of every supported one.
+type Hasher struct {
+	primary    algorithm
+	algorithms []algorithm
+}
 
-// Password hashes a plain-text password using bcrypt.
-func Password(plain string) (string, error) {
-	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), defaultCost)
+// New creates a Hasher for cfg.
+func New(cfg Config) (*Hasher, error) {
+	a2, err := newArgon2(cfg.Argon2)
 	if err != nil {
-		return "", fmt.Errorf("bcrypt: %w", err)
+		return nil, err
 	}
-	return string(hashed), nil
+	bc, err := newBcrypt(cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &Hasher{algorithms: []algorithm{a2, bc}}
+	switch strings.ToLower(cfg.Algorithm) {
+	case "", Argon2id:
+		h.primary = a2
+	case Bcrypt:
+		h.primary = bc
+	default:
+		return nil, fmt.Errorf("hash: unknown algorithm %q", cfg.Algorithm)
+	}
+	return h, nil
 }
 
-// CheckPassword compares a plain-text password against a bcrypt hash.
-// Returns nil on match, an error otherwise.
-func CheckPassword(plain, hashed string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(plain))
+// Hash returns a digest of plain made with the configured algorithm.
+func (h *Hasher) Hash(plain string) (string, error) {
+	return h.primary.hash(plain)
+}
+
+// Verify checks plain against digest, returning ErrMismatch when it does not
+// match. rehash reports a match whose digest was written with another
+// algorithm or other parameters than the configured ones; the caller should
+// then store Hash(plain) in its place.
+func (h *Hasher) Verify(plain, digest string) (rehash bool, err error) {
+	for _, a := range h.algorithms {
+		if !a.owns(digest) {
+			continue
+		}
+		current, err := a.verify(plain, digest)
+		if err != nil {
+			return false, err
+		}
+		return a != h.primary || !current, nil
+	}
+	return false, ErrUnknownFormat
 }
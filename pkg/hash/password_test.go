@@ -0,0 +1,130 @@
+package hash_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/pkg/hash"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Cheap parameters keep the tests fast; only their equality matters here.
+var (
+	fastArgon2  = hash.Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1}
+	otherArgon2 = hash.Argon2Params{Memory: 128, Iterations: 1, Parallelism: 1}
+)
+
+func newHasher(t *testing.T, cfg hash.Config) *hash.Hasher {
+	t.Helper()
+	h, err := hash.New(cfg)
+	require.NoError(t, err)
+	return h
+}
+
+func TestHasher_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    hash.Config
+		prefix string
+	}{
+		{"argon2id", hash.Config{Algorithm: hash.Argon2id, Argon2: fastArgon2}, "$argon2id$v=19$m=64,t=1,p=1$"},
+		{"bcrypt", hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4}, "$2a$04$"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHasher(t, tc.cfg)
+			digest, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(digest, tc.prefix), digest)
+
+			rehash, err := h.Verify("correct horse", digest)
+			require.NoError(t, err)
+			assert.False(t, rehash, "a digest made with the current settings stays")
+
+			_, err = h.Verify("wrong horse", digest)
+			assert.ErrorIs(t, err, hash.ErrMismatch)
+		})
+	}
+}
+
+func TestHasher_VerifyReportsRehash(t *testing.T) {
+	tests := []struct {
+		name   string
+		wrote  hash.Config
+		reads  hash.Config
+		rehash bool
+	}{
+		{"same argon2id parameters", hash.Config{Argon2: fastArgon2}, hash.Config{Argon2: fastArgon2}, false},
+		{"argon2id memory changed", hash.Config{Argon2: fastArgon2}, hash.Config{Argon2: otherArgon2}, true},
+		{"argon2id passes changed", hash.Config{Argon2: fastArgon2}, hash.Config{Argon2: hash.Argon2Params{Memory: 64, Iterations: 2, Parallelism: 1}}, true},
+		{"same bcrypt cost", hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4}, hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4}, false},
+		{"bcrypt cost changed", hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4}, hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 5}, true},
+		{"bcrypt to argon2id", hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4}, hash.Config{Argon2: fastArgon2, BcryptCost: 4}, true},
+		{"argon2id to bcrypt", hash.Config{Argon2: fastArgon2}, hash.Config{Algorithm: hash.Bcrypt, BcryptCost: 4, Argon2: fastArgon2}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			digest, err := newHasher(t, tc.wrote).Hash("s3cret")
+			require.NoError(t, err)
+
+			rehash, err := newHasher(t, tc.reads).Verify("s3cret", digest)
+			require.NoError(t, err)
+			assert.Equal(t, tc.rehash, rehash)
+		})
+	}
+}
+
+func TestHasher_VerifyRejectsBadDigests(t *testing.T) {
+	h := newHasher(t, hash.Config{Argon2: fastArgon2, BcryptCost: 4})
+	// A well-formed salt and key, so only the parameters differ.
+	const salt, key = "c29tZXNhbHRzb21lc2FsdA", "a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2V5a2U"
+
+	tests := []struct {
+		name   string
+		digest string
+	}{
+		{"empty", ""},
+		{"plain text", "s3cret"},
+		{"unknown algorithm", "$scrypt$ln=15,r=8,p=1$" + salt + "$" + key},
+		{"missing fields", "$argon2id$v=19$m=64,t=1,p=1$" + salt},
+		{"other version", "$argon2id$v=16$m=64,t=1,p=1$" + salt + "$" + key},
+		{"garbled parameters", "$argon2id$v=19$m=x,t=1,p=1$" + salt + "$" + key},
+		{"zero passes", "$argon2id$v=19$m=64,t=0,p=1$" + salt + "$" + key},
+		{"zero lanes", "$argon2id$v=19$m=64,t=1,p=0$" + salt + "$" + key},
+		{"too little memory", "$argon2id$v=19$m=4,t=1,p=1$" + salt + "$" + key},
+		{"too much memory", "$argon2id$v=19$m=4294967295,t=1,p=1$" + salt + "$" + key},
+		{"too many passes", "$argon2id$v=19$m=64,t=100000,p=1$" + salt + "$" + key},
+		{"short key", "$argon2id$v=19$m=64,t=1,p=1$" + salt + "$a2V5"},
+		{"bad salt encoding", "$argon2id$v=19$m=64,t=1,p=1$***$" + key},
+	}
+	_, err := h.Verify("s3cret", "$argon2id$v=19$m=64,t=1,p=1$"+salt+"$"+key)
+	require.ErrorIs(t, err, hash.ErrMismatch, "the fixture parses when its parameters are in range")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rehash, err := h.Verify("s3cret", tc.digest)
+			assert.ErrorIs(t, err, hash.ErrUnknownFormat)
+			assert.False(t, rehash)
+		})
+	}
+}
+
+func TestNew_RejectsOutOfRangeParams(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  hash.Config
+	}{
+		{"unknown algorithm", hash.Config{Algorithm: "md5"}},
+		{"bcrypt cost too high", hash.Config{BcryptCost: 40}},
+		{"argon2id memory below 8 KiB per lane", hash.Config{Argon2: hash.Argon2Params{Memory: 16, Parallelism: 4}}},
+		{"argon2id memory above the cap", hash.Config{Argon2: hash.Argon2Params{Memory: 4 * 1024 * 1024}}},
+		{"argon2id short salt", hash.Config{Argon2: hash.Argon2Params{SaltLength: 4}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := hash.New(tc.cfg)
+			assert.Error(t, err)
+		})
+	}
+}
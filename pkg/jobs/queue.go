@@ -0,0 +1,248 @@
+// Package jobs provides an in-process background job queue with retries.
+//
+// Jobs are held in memory, so anything still queued when the process exits is
+// lost. Handlers must therefore be idempotent and callers should treat
+// enqueueing as best-effort delivery.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// HandlerFunc processes a single job payload. Returning an error schedules a
+// retry unless the error wraps ErrPermanent or attempts are exhausted.
+type HandlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+// ErrPermanent marks a job failure that must not be retried.
+var ErrPermanent = errors.New("permanent job failure")
+
+// ErrQueueFull is returned by Enqueue when the buffer is saturated.
+var ErrQueueFull = errors.New("job queue is full")
+
+// Job is a unit of background work.
+type Job struct {
+	ID          uuid.UUID
+	Type        string
+	Payload     json.RawMessage
+	Attempt     int
+	MaxAttempts int
+	Timeout     time.Duration // overrides Config.JobTimeout when set
+	EnqueuedAt  time.Time
+}
+
+// Config tunes queue concurrency and retry behaviour.
+type Config struct {
+	Workers     int
+	BufferSize  int
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	JobTimeout  time.Duration
+}
+
+// Queue dispatches enqueued jobs to registered handlers on a worker pool.
+type Queue struct {
+	cfg      Config
+	log      *logrus.Logger
+	jobs     chan *Job
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+	wg       sync.WaitGroup
+	stopped  chan struct{}
+}
+
+// EnqueueOption customises a single Enqueue call.
+type EnqueueOption func(*Job)
+
+// WithMaxAttempts overrides the default attempt limit for one job.
+func WithMaxAttempts(n int) EnqueueOption {
+	return func(j *Job) { j.MaxAttempts = n }
+}
+
+// WithTimeout overrides the default per-attempt timeout for one job.
+func WithTimeout(d time.Duration) EnqueueOption {
+	return func(j *Job) { j.Timeout = d }
+}
+
+// New creates a Queue. Call Start to begin processing.
+func New(cfg Config, log *logrus.Logger) *Queue {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.BufferSize < 1 {
+		cfg.BufferSize = 100
+	}
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = time.Second
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = time.Minute
+	}
+	return &Queue{
+		cfg:      cfg,
+		log:      log,
+		jobs:     make(chan *Job, cfg.BufferSize),
+		handlers: make(map[string]HandlerFunc),
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Register binds a handler to a job type. Registering the same type twice replaces the handler.
+func (q *Queue) Register(jobType string, h HandlerFunc) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.handlers[jobType] = h
+}
+
+// Enqueue marshals payload and schedules the job for processing.
+func (q *Queue) Enqueue(_ context.Context, jobType string, payload any, opts ...EnqueueOption) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("jobs: marshal payload for %s: %w", jobType, err)
+	}
+
+	job := &Job{
+		ID:          uuid.New(),
+		Type:        jobType,
+		Payload:     raw,
+		MaxAttempts: q.cfg.MaxAttempts,
+		EnqueuedAt:  time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	return q.push(job)
+}
+
+func (q *Queue) push(job *Job) error {
+	select {
+	case <-q.stopped:
+		return fmt.Errorf("jobs: queue stopped")
+	default:
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+// Start launches the worker pool. Workers exit when ctx is cancelled or Stop is called.
+func (q *Queue) Start(ctx context.Context) {
+	for i := 0; i < q.cfg.Workers; i++ {
+		q.wg.Add(1)
+		go q.worker(ctx)
+	}
+}
+
+// Stop stops accepting new jobs and waits for in-flight work to finish or ctx to expire.
+// Jobs still waiting on a retry backoff are dropped.
+func (q *Queue) Stop(ctx context.Context) {
+	close(q.stopped)
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		q.log.Warn("job queue stop timed out; abandoning in-flight jobs")
+	}
+}
+
+func (q *Queue) worker(ctx context.Context) {
+	defer q.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopped:
+			// Drain whatever is already buffered before exiting.
+			for {
+				select {
+				case job := <-q.jobs:
+					q.process(ctx, job)
+				default:
+					return
+				}
+			}
+		case job := <-q.jobs:
+			q.process(ctx, job)
+		}
+	}
+}
+
+func (q *Queue) process(ctx context.Context, job *Job) {
+	q.mu.RLock()
+	handler, ok := q.handlers[job.Type]
+	q.mu.RUnlock()
+
+	entry := q.log.WithFields(logrus.Fields{"job_id": job.ID, "job_type": job.Type, "attempt": job.Attempt + 1})
+	if !ok {
+		entry.Error("no handler registered for job type; dropping")
+		return
+	}
+
+	timeout := q.cfg.JobTimeout
+	if job.Timeout > 0 {
+		timeout = job.Timeout
+	}
+	jobCtx, cancel := context.WithTimeout(ctx, timeout)
+	err := safeCall(jobCtx, handler, job.Payload)
+	cancel()
+	if err == nil {
+		return
+	}
+
+	job.Attempt++
+	if errors.Is(err, ErrPermanent) || job.Attempt >= job.MaxAttempts {
+		entry.WithError(err).Error("job failed permanently")
+		return
+	}
+
+	delay := q.backoff(job.Attempt)
+	entry.WithError(err).WithField("retry_in", delay.String()).Warn("job failed; retrying")
+	time.AfterFunc(delay, func() {
+		if err := q.push(job); err != nil {
+			entry.WithError(err).Error("failed to requeue job")
+		}
+	})
+}
+
+// backoff returns an exponential delay capped at MaxBackoff.
+func (q *Queue) backoff(attempt int) time.Duration {
+	d := q.cfg.BaseBackoff << (attempt - 1)
+	if d <= 0 || d > q.cfg.MaxBackoff {
+		return q.cfg.MaxBackoff
+	}
+	return d
+}
+
+func safeCall(ctx context.Context, h HandlerFunc, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v", r)
+		}
+	}()
+	return h(ctx, payload)
+}
@@ -0,0 +1,54 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueue_RetriesUntilAttemptsRunOut(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		calls int32
+	}{
+		{"transient failures use every attempt", errors.New("timeout"), 3},
+		{"permanent failures run once", fmt.Errorf("%w: bad payload", jobs.ErrPermanent), 1},
+		{"success runs once", nil, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			q := jobs.New(jobs.Config{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}, quietLogger())
+			var calls atomic.Int32
+			q.Register("work", func(context.Context, json.RawMessage) error {
+				calls.Add(1)
+				return tc.err
+			})
+			q.Start(context.Background())
+			defer q.Stop(context.Background())
+
+			require.NoError(t, q.Enqueue(context.Background(), "work", map[string]int{"n": 1}))
+			require.Eventually(t, func() bool { return calls.Load() == tc.calls }, time.Second, time.Millisecond)
+			// Past every backoff another retry would wait.
+			time.Sleep(20 * time.Millisecond)
+			assert.Equal(t, tc.calls, calls.Load())
+		})
+	}
+}
+
+func TestQueue_EnqueueAfterStopFails(t *testing.T) {
+	q := jobs.New(jobs.Config{BufferSize: 1}, quietLogger())
+	require.NoError(t, q.Enqueue(context.Background(), "work", nil))
+	assert.ErrorIs(t, q.Enqueue(context.Background(), "work", nil), jobs.ErrQueueFull)
+
+	q.Stop(context.Background())
+	assert.Error(t, q.Enqueue(context.Background(), "work", nil))
+}
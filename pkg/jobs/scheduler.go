@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PeriodicFunc is a unit of recurring work run by the Scheduler.
+type PeriodicFunc func(ctx context.Context) error
+
+// Claimer coordinates instances running the same schedule. Claim reports
+// whether the caller, holder, runs task for the interval starting at slot;
+// for each task and slot exactly one caller gets true.
+type Claimer interface {
+	Claim(ctx context.Context, task string, slot time.Time, holder string) (bool, error)
+}
+
+type periodicTask struct {
+	name     string
+	interval time.Duration
+	fn       PeriodicFunc
+	local    bool
+}
+
+// Scheduler runs registered functions on fixed intervals.
+type Scheduler struct {
+	log     *logrus.Logger
+	tasks   []periodicTask
+	claimer Claimer
+	holder  string
+	wg      sync.WaitGroup
+}
+
+// NewScheduler creates an empty Scheduler.
+func NewScheduler(log *logrus.Logger) *Scheduler {
+	return &Scheduler{log: log}
+}
+
+// UseClaimer makes tasks registered with Every run on one instance per
+// interval: each instance still ticks, but only the one claiming the slot
+// runs the task. holder names this instance in the claims. Must be called
+// before Start.
+func (s *Scheduler) UseClaimer(c Claimer, holder string) {
+	s.claimer, s.holder = c, holder
+}
+
+// Every registers fn to run once per interval across the instances sharing
+// the claimer, or once per interval on this instance without one. Must be
+// called before Start.
+func (s *Scheduler) Every(name string, interval time.Duration, fn PeriodicFunc) {
+	s.tasks = append(s.tasks, periodicTask{name: name, interval: interval, fn: fn})
+}
+
+// EveryInstance registers fn to run once per interval on every instance,
+// for work on state of the instance's own. Must be called before Start.
+func (s *Scheduler) EveryInstance(name string, interval time.Duration, fn PeriodicFunc) {
+	s.tasks = append(s.tasks, periodicTask{name: name, interval: interval, fn: fn, local: true})
+}
+
+// Start launches one goroutine per registered task. They stop when ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, t := range s.tasks {
+		s.wg.Add(1)
+		go s.run(ctx, t)
+	}
+}
+
+// Wait blocks until every task goroutine has returned.
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) run(ctx context.Context, t periodicTask) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(t.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.claim(ctx, t, now) {
+				continue
+			}
+			start := time.Now()
+			if err := t.fn(ctx); err != nil {
+				s.log.WithError(err).WithField("task", t.name).Error("scheduled task failed")
+				continue
+			}
+			s.log.WithFields(logrus.Fields{"task": t.name, "duration": time.Since(start).String()}).Debug("scheduled task completed")
+		}
+	}
+}
+
+// claim reports whether this instance runs t for the tick at now. Slots
+// are aligned to the interval on the wall clock, so instances started at
+// different times agree on them. A failed claim skips the run: the other
+// instances may have claimed it.
+func (s *Scheduler) claim(ctx context.Context, t periodicTask, now time.Time) bool {
+	if s.claimer == nil || t.local {
+		return true
+	}
+	ok, err := s.claimer.Claim(ctx, t.name, now.Truncate(t.interval), s.holder)
+	if err != nil {
+		s.log.WithError(err).WithField("task", t.name).Warn("could not claim scheduled task; skipping this run")
+		return false
+	}
+	if !ok {
+		s.log.WithField("task", t.name).Debug("scheduled task claimed by another instance")
+	}
+	return ok
+}
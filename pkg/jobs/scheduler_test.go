@@ -0,0 +1,151 @@
+package jobs_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/jobs"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClaimer hands each task and slot to the first holder asking, as the
+// scheduled_task_claims upsert does, and records every attempt.
+type fakeClaimer struct {
+	mu       sync.Mutex
+	winners  map[string]string // task@slot -> holder
+	attempts map[string][]string
+	err      error
+}
+
+func newFakeClaimer() *fakeClaimer {
+	return &fakeClaimer{winners: map[string]string{}, attempts: map[string][]string{}}
+}
+
+func (f *fakeClaimer) Claim(_ context.Context, task string, slot time.Time, holder string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := task + "@" + slot.Format(time.RFC3339Nano)
+	f.attempts[key] = append(f.attempts[key], holder)
+	if f.err != nil {
+		return false, f.err
+	}
+	if _, taken := f.winners[key]; taken {
+		return false, nil
+	}
+	f.winners[key] = holder
+	return true, nil
+}
+
+// runCounter counts runs per scheduler.
+type runCounter struct {
+	mu   sync.Mutex
+	runs map[string]int
+}
+
+func (c *runCounter) fn(holder string) jobs.PeriodicFunc {
+	return func(context.Context) error {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.runs[holder]++
+		return nil
+	}
+}
+
+func (c *runCounter) total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, r := range c.runs {
+		n += r
+	}
+	return n
+}
+
+func quietLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.FatalLevel)
+	return log
+}
+
+// runFor starts the schedulers together, lets them tick for d and waits
+// for them to stop.
+func runFor(d time.Duration, schedulers ...*jobs.Scheduler) {
+	ctx, cancel := context.WithCancel(context.Background())
+	for _, s := range schedulers {
+		s.Start(ctx)
+	}
+	time.Sleep(d)
+	cancel()
+	for _, s := range schedulers {
+		s.Wait()
+	}
+}
+
+func TestScheduler_EveryRunsEachSlotOnOneInstance(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	claimer := newFakeClaimer()
+	shared := &runCounter{runs: map[string]int{}}
+	local := &runCounter{runs: map[string]int{}}
+
+	var schedulers []*jobs.Scheduler
+	for _, holder := range []string{"api-1", "api-2"} {
+		s := jobs.NewScheduler(quietLogger())
+		s.UseClaimer(claimer, holder)
+		s.Every("digest", interval, shared.fn(holder))
+		s.EveryInstance("flush-cache", interval, local.fn(holder))
+		schedulers = append(schedulers, s)
+	}
+	runFor(12*interval, schedulers...)
+
+	claimer.mu.Lock()
+	defer claimer.mu.Unlock()
+	require.NotEmpty(t, claimer.winners)
+	assert.Equal(t, len(claimer.winners), shared.total(), "every claimed slot runs exactly once")
+
+	contested := 0
+	for key, holders := range claimer.attempts {
+		assert.NotContains(t, key, "flush-cache@", "EveryInstance tasks do not claim")
+		if len(holders) == 2 {
+			contested++
+		}
+	}
+	assert.Positive(t, contested, "both instances asked for the same slot")
+
+	local.mu.Lock()
+	defer local.mu.Unlock()
+	assert.Positive(t, local.runs["api-1"])
+	assert.Positive(t, local.runs["api-2"])
+}
+
+func TestScheduler_FailedClaimSkipsRun(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	claimer := newFakeClaimer()
+	claimer.err = errors.New("connection refused")
+	runs := &runCounter{runs: map[string]int{}}
+
+	s := jobs.NewScheduler(quietLogger())
+	s.UseClaimer(claimer, "api-1")
+	s.Every("digest", interval, runs.fn("api-1"))
+	runFor(5*interval, s)
+
+	claimer.mu.Lock()
+	defer claimer.mu.Unlock()
+	assert.NotEmpty(t, claimer.attempts)
+	assert.Zero(t, runs.total(), "another instance may hold the slot")
+}
+
+func TestScheduler_WithoutClaimerRunsEveryTick(t *testing.T) {
+	const interval = 10 * time.Millisecond
+	runs := &runCounter{runs: map[string]int{}}
+
+	s := jobs.NewScheduler(quietLogger())
+	s.Every("digest", interval, runs.fn("api-1"))
+	runFor(5*interval, s)
+
+	assert.Positive(t, runs.total())
+}
@@ -0,0 +1,313 @@
+// Package jsonschema describes Go types as JSON Schema (draft 2020-12)
+// documents, following the same struct tags encoding/json does, so a
+// published schema cannot drift from the payloads actually sent, and checks
+// documents against them.
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect produced.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document, limited to the keywords Reflect emits.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Defs        map[string]*Schema `json:"$defs,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        Types              `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Enum        []any              `json:"enum,omitempty"`
+	Const       any                `json:"const,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is the *Schema of keys not in Properties, or
+	// false when there may be none.
+	AdditionalProperties any     `json:"additionalProperties,omitempty"`
+	Items                *Schema `json:"items,omitempty"`
+	MinItems             *int    `json:"minItems,omitempty"`
+	MaxItems             *int    `json:"maxItems,omitempty"`
+}
+
+// Types is the "type" keyword: one JSON type, or several when a value may
+// also be null.
+type Types []string
+
+// MarshalJSON writes a single type as a bare string.
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+// Reflector builds schemas from Go types. The zero value is ready to use.
+type Reflector struct {
+	// Enums lists the allowed values of named types, such as string
+	// enumerations, that the type itself cannot express.
+	Enums map[reflect.Type][]any
+	// Strict describes documents sent to the server rather than by it: a
+	// field is required only when its validate tag says so, and unknown
+	// fields are rejected.
+	Strict bool
+}
+
+// Reflect returns the schema of v's type as a standalone document. The
+// rules of validate struct tags that JSON Schema can express (required,
+// min, max, eq, oneof, hexcolor, and those after dive for the items of a
+// list) become constraints. Recursive types are described once, under
+// $defs.
+func (r *Reflector) Reflect(v any) *Schema {
+	st := &reflectState{seen: map[reflect.Type]bool{}, recursive: map[reflect.Type]bool{}, defs: map[string]*Schema{}}
+	s := r.reflect(reflect.TypeOf(v), st)
+	if len(st.defs) > 0 {
+		s.Defs = st.defs
+	}
+	s.Schema = Draft
+	return s
+}
+
+type reflectState struct {
+	seen      map[reflect.Type]bool // structs being described
+	recursive map[reflect.Type]bool // structs that refer back to themselves
+	defs      map[string]*Schema
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (r *Reflector) reflect(t reflect.Type, st *reflectState) *Schema {
+	if t.Kind() == reflect.Pointer {
+		s := r.reflect(t.Elem(), st)
+		if len(s.Type) > 0 {
+			s.Type = append(s.Type, "null")
+		}
+		if len(s.Enum) > 0 {
+			s.Enum = append(s.Enum, nil)
+		}
+		return s
+	}
+	if values, ok := r.Enums[t]; ok {
+		return &Schema{Type: Types{jsonType(t.Kind())}, Enum: append([]any(nil), values...)}
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: Types{"string"}, Format: "date-time"}
+	case t.Implements(marshalerType) || reflect.PointerTo(t).Implements(marshalerType):
+		// Custom JSON can be anything.
+		return &Schema{}
+	case t.Implements(textMarshalType) || reflect.PointerTo(t).Implements(textMarshalType):
+		s := &Schema{Type: Types{"string"}}
+		if t.PkgPath() == "github.com/google/uuid" && t.Name() == "UUID" {
+			s.Format = "uuid"
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		ref := &Schema{Ref: "#/$defs/" + t.Name()}
+		if st.seen[t] {
+			st.recursive[t] = true
+			return ref
+		}
+		st.seen[t] = true
+		s := &Schema{Type: Types{"object"}, Properties: map[string]*Schema{}}
+		if r.Strict {
+			s.AdditionalProperties = false
+		}
+		r.addFields(s, t, st)
+		delete(st.seen, t)
+		if st.recursive[t] {
+			st.defs[t.Name()] = s
+			return ref
+		}
+		return s
+	case reflect.Map:
+		return &Schema{Type: Types{"object"}, AdditionalProperties: r.reflect(t.Elem(), st)}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: Types{"string"}, Format: "byte"}
+		}
+		return &Schema{Type: Types{"array"}, Items: r.reflect(t.Elem(), st)}
+	case reflect.Interface:
+		return &Schema{}
+	default:
+		return &Schema{Type: Types{jsonType(t.Kind())}}
+	}
+}
+
+// addFields adds t's exported fields to s, flattening embedded structs the
+// way encoding/json does. Unless Strict, fields without omitempty are always
+// present, so they are required.
+func (r *Reflector) addFields(s *Schema, t reflect.Type, st *reflectState) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.addFields(s, ft, st)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := r.reflect(f.Type, st)
+		rules := strings.Split(f.Tag.Get("validate"), ",")
+		required := applyRules(prop, f.Type, rules)
+		if r.Strict && f.Type.Kind() == reflect.Struct && len(prop.Required) > 0 {
+			// The validator checks a nested struct's fields even when the
+			// struct itself is left out.
+			required = true
+		}
+		if r.Strict && !required && len(prop.Type) > 0 && !slices.Contains(prop.Type, "null") {
+			// An explicit null is as good as leaving the field out.
+			prop.Type = append(prop.Type, "null")
+			if len(prop.Enum) > 0 {
+				prop.Enum = append(prop.Enum, nil)
+			}
+		}
+		s.Properties[name] = prop
+		if required || (!r.Strict && !strings.Contains(","+opts+",", ",omitempty,")) {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// hexColorPattern is the hexcolor rule of the validator package.
+const hexColorPattern = "^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{4}|[0-9a-fA-F]{6}|[0-9a-fA-F]{8})$"
+
+// applyRules adds the constraints of validate rules to s, the schema of a
+// value of type t, and reports whether they make it required. Rules after
+// dive apply to the items of a list.
+func applyRules(s *Schema, t reflect.Type, rules []string) (required bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if i := slices.Index(rules, "dive"); i >= 0 {
+		if s.Items != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			applyRules(s.Items, t.Elem(), rules[i+1:])
+		}
+		rules = rules[:i]
+	}
+
+	omitEmpty := false
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "omitempty":
+			omitEmpty = true
+		case "required":
+			required = true
+			if t.Kind() == reflect.String && s.MinLength == nil {
+				one := 1
+				s.MinLength = &one
+			}
+		case "min", "max", "eq":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			setBound(s, t.Kind(), name, n)
+		case "oneof":
+			if len(s.Enum) == 0 {
+				for _, v := range strings.Fields(param) {
+					s.Enum = append(s.Enum, v)
+				}
+			}
+		case "hexcolor":
+			s.Pattern = hexColorPattern
+		}
+	}
+	if omitEmpty && t.Kind() == reflect.String {
+		// The other rules only apply to a non-empty string.
+		if len(s.Enum) > 0 {
+			s.Enum = append(s.Enum, "")
+		}
+		if s.Pattern != "" {
+			s.Pattern = "^$|" + s.Pattern
+		}
+		s.MinLength = nil
+	}
+	return required
+}
+
+// setBound applies a min, max or eq rule: a length for strings, a count for
+// lists and a value for numbers.
+func setBound(s *Schema, kind reflect.Kind, rule string, n float64) {
+	switch kind {
+	case reflect.String:
+		if rule != "max" {
+			s.MinLength = intPtr(n)
+		}
+		if rule != "min" {
+			s.MaxLength = intPtr(n)
+		}
+	case reflect.Map:
+		// Not expressed: no document limits the size of a map.
+	case reflect.Slice, reflect.Array:
+		if rule != "max" {
+			s.MinItems = intPtr(n)
+		}
+		if rule != "min" {
+			s.MaxItems = intPtr(n)
+		}
+	default:
+		if rule != "max" {
+			s.Minimum = &n
+		}
+		if rule != "min" {
+			s.Maximum = &n
+		}
+	}
+}
+
+func intPtr(n float64) *int {
+	i := int(n)
+	return &i
+}
+
+func jsonType(k reflect.Kind) string {
+	switch k {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
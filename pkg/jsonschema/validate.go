@@ -0,0 +1,231 @@
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Error is one way a document breaks its schema. Path is the JSON Pointer
+// of the offending value, empty for the document itself.
+type Error struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e Error) Error() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// Validate checks doc, a value as decoded by encoding/json into an any,
+// against s and returns every violation in document order, or nil. Only the
+// keywords Reflect emits are understood, with $ref limited to s's $defs.
+func (s *Schema) Validate(doc any) []Error {
+	v := &validation{root: s, patterns: map[string]*regexp.Regexp{}}
+	v.check(s, doc, "")
+	return v.errs
+}
+
+type validation struct {
+	root     *Schema
+	patterns map[string]*regexp.Regexp
+	errs     []Error
+}
+
+func (v *validation) fail(path, format string, args ...any) {
+	v.errs = append(v.errs, Error{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validation) check(s *Schema, value any, path string) {
+	if s.Ref != "" {
+		def, ok := v.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			v.fail(path, "schema refers to unknown %s", s.Ref)
+			return
+		}
+		s = def
+	}
+	if len(s.Type) > 0 && !hasType(value, s.Type) {
+		v.fail(path, "must be %s", strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		names := make([]string, 0, len(s.Enum))
+		for _, e := range s.Enum {
+			if e != nil {
+				names = append(names, fmt.Sprint(e))
+			}
+		}
+		v.fail(path, "must be one of: %s", strings.Join(names, ", "))
+		return
+	}
+	if s.Const != nil && !equalValues(s.Const, value) {
+		v.fail(path, "must be %v", s.Const)
+		return
+	}
+
+	switch x := value.(type) {
+	case string:
+		v.checkString(s, x, path)
+	case float64:
+		if s.Minimum != nil && x < *s.Minimum {
+			v.fail(path, "must be at least %g", *s.Minimum)
+		}
+		if s.Maximum != nil && x > *s.Maximum {
+			v.fail(path, "must be at most %g", *s.Maximum)
+		}
+	case []any:
+		if s.MinItems != nil && len(x) < *s.MinItems {
+			v.fail(path, "must have at least %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(x) > *s.MaxItems {
+			v.fail(path, "must have at most %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, item := range x {
+				v.check(s.Items, item, path+"/"+strconv.Itoa(i))
+			}
+		}
+	case map[string]any:
+		v.checkObject(s, x, path)
+	}
+}
+
+func (v *validation) checkString(s *Schema, x, path string) {
+	n := utf8.RuneCountInString(x)
+	if s.MinLength != nil && n < *s.MinLength {
+		if *s.MinLength == 1 {
+			v.fail(path, "must not be empty")
+		} else {
+			v.fail(path, "must be at least %d characters", *s.MinLength)
+		}
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		v.fail(path, "must be at most %d characters", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, ok := v.patterns[s.Pattern]
+		if !ok {
+			re, _ = regexp.Compile(s.Pattern)
+			v.patterns[s.Pattern] = re
+		}
+		if re != nil && !re.MatchString(x) {
+			v.fail(path, "must match %s", s.Pattern)
+		}
+	}
+	if s.Format == "date-time" {
+		if _, err := time.Parse(time.RFC3339, x); err != nil {
+			v.fail(path, "must be an RFC 3339 date-time (e.g. 2024-05-01T09:00:00Z)")
+		}
+	}
+}
+
+func (v *validation) checkObject(s *Schema, x map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := x[name]; !ok {
+			v.fail(path+"/"+escapePointer(name), "this field is required")
+		}
+	}
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		child := path + "/" + escapePointer(k)
+		if prop, ok := s.Properties[k]; ok {
+			v.check(prop, x[k], child)
+			continue
+		}
+		switch extra := s.AdditionalProperties.(type) {
+		case bool:
+			if !extra {
+				v.fail(child, "unknown field")
+			}
+		case *Schema:
+			v.check(extra, x[k], child)
+		}
+	}
+}
+
+func hasType(value any, types Types) bool {
+	for _, t := range types {
+		switch x := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && x == math.Trunc(x)) {
+				return true
+			}
+		case []any:
+			if t == "array" {
+				return true
+			}
+		case map[string]any:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsValue(values []any, value any) bool {
+	for _, e := range values {
+		if equalValues(e, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// equalValues compares a schema value, which may be a named Go string or
+// number type, with a decoded JSON value.
+func equalValues(a, b any) bool {
+	return normalize(a) == normalize(b)
+}
+
+func normalize(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapePointer escapes a key for use in a JSON Pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -8,14 +8,18 @@ import (
 	"github.com/google/uuid"
 )
 
-// TokenType differentiates access and refresh tokens.
+// TokenType differentiates access, refresh and login challenge tokens.
 type TokenType string
 
 const (
-	AccessToken  TokenType = "access"
-	RefreshToken TokenType = "refresh"
+	AccessToken    TokenType = "access"
+	RefreshToken   TokenType = "refresh"
+	ChallengeToken TokenType = "challenge"
 )
 
+// ChallengeTTL is how long a login challenge token is valid.
+const ChallengeTTL = 5 * time.Minute
+
 // Claims extends standard JWT claims with application-specific fields.
 type Claims struct {
 	UserID    uuid.UUID `json:"user_id"`
@@ -51,6 +55,13 @@ func (m *Manager) GenerateRefreshToken(userID uuid.UUID) (string, error) {
 	return m.generate(userID, RefreshToken, m.refreshSecret, m.refreshTTL)
 }
 
+// GenerateChallengeToken creates a signed token proving the given user has
+// passed the first login step. It is not an access token and grants nothing
+// else.
+func (m *Manager) GenerateChallengeToken(userID uuid.UUID) (string, error) {
+	return m.generate(userID, ChallengeToken, m.accessSecret, ChallengeTTL)
+}
+
 func (m *Manager) generate(userID uuid.UUID, tokenType TokenType, secret []byte, ttl time.Duration) (string, error) {
 	now := time.Now()
 	claims := &Claims{
@@ -81,6 +92,11 @@ func (m *Manager) ParseRefreshToken(tokenStr string) (*Claims, error) {
 	return m.parse(tokenStr, m.refreshSecret, RefreshToken)
 }
 
+// ParseChallengeToken validates and parses a login challenge token string.
+func (m *Manager) ParseChallengeToken(tokenStr string) (*Claims, error) {
+	return m.parse(tokenStr, m.accessSecret, ChallengeToken)
+}
+
 func (m *Manager) parse(tokenStr string, secret []byte, expectedType TokenType) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -0,0 +1,55 @@
+// Package llm provides interchangeable large language model backends.
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Supported backend drivers. An empty driver disables LLM features.
+const (
+	DriverNone   = "none"
+	DriverOpenAI = "openai" // any OpenAI-compatible chat completions API
+)
+
+// Provider answers a single prompt with text.
+type Provider interface {
+	Complete(ctx context.Context, system, prompt string) (string, error)
+	Name() string
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Driver  string
+	BaseURL string
+	APIKey  string
+	Model   string
+	Timeout time.Duration
+}
+
+// New builds the Provider selected by cfg.Driver. It returns nil, nil when
+// LLM features are disabled.
+func New(cfg Config) (Provider, error) {
+	switch cfg.Driver {
+	case "", DriverNone:
+		return nil, nil
+	case DriverOpenAI:
+		if cfg.APIKey == "" || cfg.Model == "" {
+			return nil, fmt.Errorf("llm: LLM_API_KEY and LLM_MODEL are required for the openai driver")
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = defaultOpenAIBaseURL
+		}
+		return &openAIProvider{
+			baseURL: baseURL,
+			apiKey:  cfg.APIKey,
+			model:   cfg.Model,
+			client:  &http.Client{Timeout: cfg.Timeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("llm: unknown driver %q", cfg.Driver)
+	}
+}
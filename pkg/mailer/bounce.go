@@ -0,0 +1,155 @@
+package mailer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Bounce types reported by providers that should stop further delivery.
+const (
+	BounceTypeHard      = "hard_bounce"
+	BounceTypeComplaint = "complaint"
+	BounceTypeDropped   = "dropped"
+)
+
+// Bounce is a provider-agnostic delivery failure for a single address.
+type Bounce struct {
+	Email  string
+	Type   string
+	Reason string
+}
+
+// SubscriptionConfirmation is returned by ParseBounces when SES/SNS asks the
+// endpoint to confirm a new subscription. An operator must visit URL once.
+type SubscriptionConfirmation struct {
+	URL string
+}
+
+func (s *SubscriptionConfirmation) Error() string {
+	return "sns subscription confirmation required: " + s.URL
+}
+
+// ParseBounces decodes a provider's bounce/complaint webhook body. Events that
+// do not indicate a bad address (deliveries, opens, soft bounces) are skipped.
+func ParseBounces(provider string, body []byte) ([]Bounce, error) {
+	switch provider {
+	case DriverSendGrid:
+		return parseSendGrid(body)
+	case DriverMailgun:
+		return parseMailgun(body)
+	case DriverSES:
+		return parseSES(body)
+	default:
+		return nil, fmt.Errorf("mailer: bounce webhooks not supported for provider %q", provider)
+	}
+}
+
+func parseSendGrid(body []byte) ([]Bounce, error) {
+	var events []struct {
+		Email  string `json:"email"`
+		Event  string `json:"event"`
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	}
+	if err := json.Unmarshal(body, &events); err != nil {
+		return nil, fmt.Errorf("sendgrid: decode events: %w", err)
+	}
+
+	var out []Bounce
+	for _, e := range events {
+		switch {
+		case e.Event == "bounce" && e.Type != "blocked":
+			out = append(out, Bounce{Email: e.Email, Type: BounceTypeHard, Reason: e.Reason})
+		case e.Event == "dropped":
+			out = append(out, Bounce{Email: e.Email, Type: BounceTypeDropped, Reason: e.Reason})
+		case e.Event == "spamreport":
+			out = append(out, Bounce{Email: e.Email, Type: BounceTypeComplaint, Reason: "spam report"})
+		}
+	}
+	return out, nil
+}
+
+func parseMailgun(body []byte) ([]Bounce, error) {
+	var payload struct {
+		EventData struct {
+			Event          string `json:"event"`
+			Severity       string `json:"severity"`
+			Recipient      string `json:"recipient"`
+			DeliveryStatus struct {
+				Description string `json:"description"`
+				Message     string `json:"message"`
+			} `json:"delivery-status"`
+		} `json:"event-data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("mailgun: decode event: %w", err)
+	}
+
+	e := payload.EventData
+	switch {
+	case e.Event == "failed" && e.Severity == "permanent":
+		reason := e.DeliveryStatus.Description
+		if reason == "" {
+			reason = e.DeliveryStatus.Message
+		}
+		return []Bounce{{Email: e.Recipient, Type: BounceTypeHard, Reason: reason}}, nil
+	case e.Event == "complained":
+		return []Bounce{{Email: e.Recipient, Type: BounceTypeComplaint, Reason: "spam complaint"}}, nil
+	}
+	return nil, nil
+}
+
+func parseSES(body []byte) ([]Bounce, error) {
+	var envelope struct {
+		Type         string `json:"Type"`
+		Message      string `json:"Message"`
+		SubscribeURL string `json:"SubscribeURL"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("ses: decode sns envelope: %w", err)
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		return nil, &SubscriptionConfirmation{URL: envelope.SubscribeURL}
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var n struct {
+		NotificationType string `json:"notificationType"`
+		Bounce           struct {
+			BounceType        string `json:"bounceType"`
+			BouncedRecipients []struct {
+				EmailAddress   string `json:"emailAddress"`
+				DiagnosticCode string `json:"diagnosticCode"`
+			} `json:"bouncedRecipients"`
+		} `json:"bounce"`
+		Complaint struct {
+			ComplainedRecipients []struct {
+				EmailAddress string `json:"emailAddress"`
+			} `json:"complainedRecipients"`
+		} `json:"complaint"`
+	}
+	if err := json.Unmarshal([]byte(envelope.Message), &n); err != nil {
+		return nil, fmt.Errorf("ses: decode notification: %w", err)
+	}
+
+	var out []Bounce
+	switch n.NotificationType {
+	case "Bounce":
+		if !strings.EqualFold(n.Bounce.BounceType, "Permanent") {
+			return nil, nil
+		}
+		for _, r := range n.Bounce.BouncedRecipients {
+			out = append(out, Bounce{Email: r.EmailAddress, Type: BounceTypeHard, Reason: r.DiagnosticCode})
+		}
+	case "Complaint":
+		for _, r := range n.Complaint.ComplainedRecipients {
+			out = append(out, Bounce{Email: r.EmailAddress, Type: BounceTypeComplaint, Reason: "spam complaint"})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,108 @@
+package mailer_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/galihaleanda/todo-app/pkg/mailer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBounces(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		body     string
+		want     []mailer.Bounce
+	}{
+		{
+			name:     "sendgrid event batch",
+			provider: mailer.DriverSendGrid,
+			body: `[
+				{"email": "gone@example.com", "event": "bounce", "type": "bounce", "reason": "550 5.1.1 user unknown"},
+				{"email": "busy@example.com", "event": "bounce", "type": "blocked", "reason": "421 try later"},
+				{"email": "old@example.com", "event": "dropped", "reason": "Bounced Address"},
+				{"email": "angry@example.com", "event": "spamreport"},
+				{"email": "ok@example.com", "event": "delivered"}
+			]`,
+			want: []mailer.Bounce{
+				{Email: "gone@example.com", Type: mailer.BounceTypeHard, Reason: "550 5.1.1 user unknown"},
+				{Email: "old@example.com", Type: mailer.BounceTypeDropped, Reason: "Bounced Address"},
+				{Email: "angry@example.com", Type: mailer.BounceTypeComplaint, Reason: "spam report"},
+			},
+		},
+		{
+			name:     "mailgun permanent failure",
+			provider: mailer.DriverMailgun,
+			body: `{"signature": {}, "event-data": {
+				"event": "failed", "severity": "permanent", "recipient": "gone@example.com",
+				"delivery-status": {"code": 550, "message": "mailbox unavailable", "description": ""}
+			}}`,
+			want: []mailer.Bounce{{Email: "gone@example.com", Type: mailer.BounceTypeHard, Reason: "mailbox unavailable"}},
+		},
+		{
+			name:     "mailgun temporary failure",
+			provider: mailer.DriverMailgun,
+			body:     `{"event-data": {"event": "failed", "severity": "temporary", "recipient": "busy@example.com"}}`,
+		},
+		{
+			name:     "mailgun complaint",
+			provider: mailer.DriverMailgun,
+			body:     `{"event-data": {"event": "complained", "recipient": "angry@example.com"}}`,
+			want:     []mailer.Bounce{{Email: "angry@example.com", Type: mailer.BounceTypeComplaint, Reason: "spam complaint"}},
+		},
+		{
+			name:     "ses permanent bounce",
+			provider: mailer.DriverSES,
+			body:     `{"Type": "Notification", "MessageId": "1", "Message": "{\"notificationType\":\"Bounce\",\"bounce\":{\"bounceType\":\"Permanent\",\"bouncedRecipients\":[{\"emailAddress\":\"gone@example.com\",\"diagnosticCode\":\"smtp; 550 user unknown\"}]}}"}`,
+			want:     []mailer.Bounce{{Email: "gone@example.com", Type: mailer.BounceTypeHard, Reason: "smtp; 550 user unknown"}},
+		},
+		{
+			name:     "ses transient bounce",
+			provider: mailer.DriverSES,
+			body:     `{"Type": "Notification", "Message": "{\"notificationType\":\"Bounce\",\"bounce\":{\"bounceType\":\"Transient\",\"bouncedRecipients\":[{\"emailAddress\":\"full@example.com\"}]}}"}`,
+		},
+		{
+			name:     "ses complaint",
+			provider: mailer.DriverSES,
+			body:     `{"Type": "Notification", "Message": "{\"notificationType\":\"Complaint\",\"complaint\":{\"complainedRecipients\":[{\"emailAddress\":\"angry@example.com\"}]}}"}`,
+			want:     []mailer.Bounce{{Email: "angry@example.com", Type: mailer.BounceTypeComplaint, Reason: "spam complaint"}},
+		},
+		{
+			name:     "ses unsubscribe confirmation",
+			provider: mailer.DriverSES,
+			body:     `{"Type": "UnsubscribeConfirmation"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := mailer.ParseBounces(tc.provider, []byte(tc.body))
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseBounces_SESSubscriptionConfirmation(t *testing.T) {
+	body := `{"Type": "SubscriptionConfirmation", "TopicArn": "arn:aws:sns:eu-west-1:123456789012:bounces",
+		"SubscribeURL": "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc"}`
+
+	got, err := mailer.ParseBounces(mailer.DriverSES, []byte(body))
+	assert.Nil(t, got)
+	var confirm *mailer.SubscriptionConfirmation
+	require.True(t, errors.As(err, &confirm))
+	assert.Equal(t, "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription&Token=abc", confirm.URL)
+}
+
+func TestParseBounces_Errors(t *testing.T) {
+	_, err := mailer.ParseBounces(mailer.DriverSMTP, []byte(`{}`))
+	assert.Error(t, err, "smtp has no bounce webhook")
+
+	for _, provider := range []string{mailer.DriverSendGrid, mailer.DriverMailgun, mailer.DriverSES} {
+		_, err := mailer.ParseBounces(provider, []byte(`not json`))
+		assert.Error(t, err, provider)
+	}
+	_, err = mailer.ParseBounces(mailer.DriverSES, []byte(`{"Type": "Notification", "Message": "not json"}`))
+	assert.Error(t, err)
+}
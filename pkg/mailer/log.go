@@ -0,0 +1,34 @@
+package mailer
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logMailer writes messages to the application log instead of sending them.
+// Intended for local development.
+type logMailer struct {
+	from string
+	log  *logrus.Logger
+}
+
+func (m *logMailer) Name() string { return DriverLog }
+
+func (m *logMailer) sender(msg *Message) string {
+	if msg.From != "" {
+		return msg.From
+	}
+	return m.from
+}
+
+func (m *logMailer) Send(_ context.Context, msg *Message) error {
+	m.log.WithFields(logrus.Fields{
+		"from":       m.sender(msg),
+		"to":         msg.To,
+		"subject":    msg.Subject,
+		"html_bytes": len(msg.HTML),
+		"text_bytes": len(msg.Text),
+	}).Info("email (log driver, not sent)")
+	return nil
+}
@@ -0,0 +1,130 @@
+// Package mailer provides interchangeable email delivery backends.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Supported backend drivers.
+const (
+	DriverLog      = "log"
+	DriverSMTP     = "smtp"
+	DriverSendGrid = "sendgrid"
+	DriverSES      = "ses"
+	DriverMailgun  = "mailgun"
+)
+
+// Message is a single outgoing email.
+type Message struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text,omitempty"`
+	// From and FromName override the configured sender when set.
+	From     string `json:"from,omitempty"`
+	FromName string `json:"from_name,omitempty"`
+}
+
+// Mailer delivers messages through a concrete provider.
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+	Name() string
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Driver   string
+	From     string
+	FromName string
+
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+
+	SESRegion          string
+	SESAccessKeyID     string
+	SESSecretAccessKey string
+
+	MailgunDomain  string
+	MailgunAPIKey  string
+	MailgunBaseURL string
+}
+
+// ErrPermanent marks a delivery failure that will not succeed on retry
+// (e.g. rejected recipient, invalid credentials). Check with errors.Is.
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// New builds the Mailer selected by cfg.Driver.
+func New(cfg Config, log *logrus.Logger) (Mailer, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	switch cfg.Driver {
+	case "", DriverLog:
+		return &logMailer{from: cfg.From, log: log}, nil
+	case DriverSMTP:
+		if cfg.SMTPHost == "" {
+			return nil, fmt.Errorf("mailer: MAIL_SMTP_HOST is required for the smtp driver")
+		}
+		return &smtpMailer{cfg: cfg}, nil
+	case DriverSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, fmt.Errorf("mailer: MAIL_SENDGRID_API_KEY is required for the sendgrid driver")
+		}
+		return &sendGridMailer{cfg: cfg, client: client}, nil
+	case DriverSES:
+		if cfg.SESRegion == "" || cfg.SESAccessKeyID == "" || cfg.SESSecretAccessKey == "" {
+			return nil, fmt.Errorf("mailer: MAIL_SES_REGION and AWS credentials are required for the ses driver")
+		}
+		return newSESMailer(cfg, client), nil
+	case DriverMailgun:
+		if cfg.MailgunDomain == "" || cfg.MailgunAPIKey == "" {
+			return nil, fmt.Errorf("mailer: MAIL_MAILGUN_DOMAIN and MAIL_MAILGUN_API_KEY are required for the mailgun driver")
+		}
+		return &mailgunMailer{cfg: cfg, client: client}, nil
+	default:
+		return nil, fmt.Errorf("mailer: unknown driver %q", cfg.Driver)
+	}
+}
+
+// sender returns the address and display name msg is sent from.
+func (c Config) sender(msg *Message) (from, name string) {
+	from, name = c.From, c.FromName
+	if msg.From != "" {
+		from = msg.From
+	}
+	if msg.FromName != "" {
+		name = msg.FromName
+	}
+	return from, name
+}
+
+// fromHeader formats the From address of msg with an optional display name.
+func (c Config) fromHeader(msg *Message) string {
+	from, name := c.sender(msg)
+	if name == "" {
+		return from
+	}
+	return fmt.Sprintf("%s <%s>", name, from)
+}
+
+// classifyHTTPStatus turns a provider API status code into an error.
+// 4xx responses (other than 429) are permanent; everything else is retryable.
+func classifyHTTPStatus(provider string, status int, body string) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	err := fmt.Errorf("%s: unexpected status %d: %s", provider, status, body)
+	if status >= 400 && status < 500 && status != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", ErrPermanent, err)
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const mailgunDefaultBaseURL = "https://api.mailgun.net"
+
+// mailgunMailer delivers through the Mailgun Messages API.
+type mailgunMailer struct {
+	cfg    Config
+	client *http.Client
+}
+
+func (m *mailgunMailer) Name() string { return DriverMailgun }
+
+func (m *mailgunMailer) Send(ctx context.Context, msg *Message) error {
+	base := m.cfg.MailgunBaseURL
+	if base == "" {
+		base = mailgunDefaultBaseURL
+	}
+	endpoint := fmt.Sprintf("%s/v3/%s/messages", strings.TrimRight(base, "/"), m.cfg.MailgunDomain)
+
+	form := url.Values{}
+	form.Set("from", m.cfg.fromHeader(msg))
+	form.Set("to", msg.To)
+	form.Set("subject", msg.Subject)
+	form.Set("html", msg.HTML)
+	if msg.Text != "" {
+		form.Set("text", msg.Text)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("mailgun: build request: %w", err)
+	}
+	req.SetBasicAuth("api", m.cfg.MailgunAPIKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailgun: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return classifyHTTPStatus(DriverMailgun, resp.StatusCode, string(respBody))
+}
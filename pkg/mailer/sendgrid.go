@@ -0,0 +1,78 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const sendGridEndpoint = "https://api.sendgrid.com/v3/mail/send"
+
+// sendGridMailer delivers through the SendGrid v3 Mail Send API.
+type sendGridMailer struct {
+	cfg    Config
+	client *http.Client
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendGridPayload struct {
+	Personalizations []struct {
+		To []sendGridAddress `json:"to"`
+	} `json:"personalizations"`
+	From    sendGridAddress   `json:"from"`
+	Subject string            `json:"subject"`
+	Content []sendGridContent `json:"content"`
+}
+
+func (m *sendGridMailer) Name() string { return DriverSendGrid }
+
+func (m *sendGridMailer) Send(ctx context.Context, msg *Message) error {
+	from, name := m.cfg.sender(msg)
+	payload := sendGridPayload{
+		From:    sendGridAddress{Email: from, Name: name},
+		Subject: msg.Subject,
+	}
+	payload.Personalizations = make([]struct {
+		To []sendGridAddress `json:"to"`
+	}, 1)
+	payload.Personalizations[0].To = []sendGridAddress{{Email: msg.To}}
+
+	// SendGrid requires text/plain to precede text/html.
+	if msg.Text != "" {
+		payload.Content = append(payload.Content, sendGridContent{Type: "text/plain", Value: msg.Text})
+	}
+	payload.Content = append(payload.Content, sendGridContent{Type: "text/html", Value: msg.HTML})
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("sendgrid: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sendGridEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("sendgrid: build request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+m.cfg.SendGridAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return classifyHTTPStatus(DriverSendGrid, resp.StatusCode, string(respBody))
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/awsv4"
+)
+
+// sesMailer delivers through the Amazon SES v2 SendEmail API.
+type sesMailer struct {
+	cfg      Config
+	client   *http.Client
+	signer   *awsv4.Signer
+	endpoint string
+}
+
+func newSESMailer(cfg Config, client *http.Client) *sesMailer {
+	return &sesMailer{
+		cfg:    cfg,
+		client: client,
+		signer: &awsv4.Signer{
+			AccessKeyID:     cfg.SESAccessKeyID,
+			SecretAccessKey: cfg.SESSecretAccessKey,
+			Region:          cfg.SESRegion,
+			Service:         "ses",
+		},
+		endpoint: fmt.Sprintf("https://email.%s.amazonaws.com/v2/email/outbound-emails", cfg.SESRegion),
+	}
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesPayload struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				HTML *sesContent `json:"Html,omitempty"`
+				Text *sesContent `json:"Text,omitempty"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (m *sesMailer) Name() string { return DriverSES }
+
+func (m *sesMailer) Send(ctx context.Context, msg *Message) error {
+	var payload sesPayload
+	payload.FromEmailAddress = m.cfg.fromHeader(msg)
+	payload.Destination.ToAddresses = []string{msg.To}
+	payload.Content.Simple.Subject = sesContent{Data: msg.Subject, Charset: "UTF-8"}
+	payload.Content.Simple.Body.HTML = &sesContent{Data: msg.HTML, Charset: "UTF-8"}
+	if msg.Text != "" {
+		payload.Content.Simple.Body.Text = &sesContent{Data: msg.Text, Charset: "UTF-8"}
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("ses: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("ses: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	m.signer.Sign(req, body, time.Now())
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("ses: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	return classifyHTTPStatus(DriverSES, resp.StatusCode, string(respBody))
+}
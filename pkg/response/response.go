@@ -1,7 +1,10 @@
 package response
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -39,6 +42,11 @@ func Created(c *gin.Context, data any) {
 	c.JSON(http.StatusCreated, Envelope{Success: true, Data: data})
 }
 
+// Accepted sends a 202 response with data, for work that completes asynchronously.
+func Accepted(c *gin.Context, data any) {
+	c.JSON(http.StatusAccepted, Envelope{Success: true, Data: data})
+}
+
 // OKPaginated sends a 200 response with data and pagination metadata.
 func OKPaginated(c *gin.Context, data any, page, limit, total int) {
 	totalPages := total / limit
@@ -89,6 +97,14 @@ func NotFound(c *gin.Context, msg string) {
 	})
 }
 
+// MethodNotAllowed sends a 405 error response.
+func MethodNotAllowed(c *gin.Context, msg string) {
+	c.JSON(http.StatusMethodNotAllowed, Envelope{
+		Success: false,
+		Error:   &ErrorBody{Code: "METHOD_NOT_ALLOWED", Message: msg},
+	})
+}
+
 // UnprocessableEntity sends a 422 error response (validation errors).
 func UnprocessableEntity(c *gin.Context, details any) {
 	c.JSON(http.StatusUnprocessableEntity, Envelope{
@@ -112,3 +128,28 @@ func Conflict(c *gin.Context, msg string) {
 		Error:   &ErrorBody{Code: "CONFLICT", Message: msg},
 	})
 }
+
+// PreconditionFailed sends a 412 error response.
+func PreconditionFailed(c *gin.Context, msg string) {
+	c.JSON(http.StatusPreconditionFailed, Envelope{
+		Success: false,
+		Error:   &ErrorBody{Code: "PRECONDITION_FAILED", Message: msg},
+	})
+}
+
+// TooManyRequests sends a 429 error response telling the client when to retry.
+func TooManyRequests(c *gin.Context, msg string, retryAfter time.Duration) {
+	c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+	c.JSON(http.StatusTooManyRequests, Envelope{
+		Success: false,
+		Error:   &ErrorBody{Code: "RATE_LIMITED", Message: msg},
+	})
+}
+
+// ServiceUnavailable sends a 503 error response.
+func ServiceUnavailable(c *gin.Context, msg string) {
+	c.JSON(http.StatusServiceUnavailable, Envelope{
+		Success: false,
+		Error:   &ErrorBody{Code: "SERVICE_UNAVAILABLE", Message: msg},
+	})
+}
@@ -0,0 +1,166 @@
+// Package signing manages the Ed25519 key pairs used to sign outgoing
+// webhook payloads, so receivers can verify them with a published public key
+// instead of a shared secret.
+package signing
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Algorithm is the signature algorithm advertised to receivers.
+const Algorithm = "ed25519"
+
+// Key statuses reported by PublicKeys.
+const (
+	StatusCurrent  = "current"
+	StatusPrevious = "previous"
+)
+
+// Config describes the active key pair and any retired public keys that
+// receivers should still accept during a rotation.
+type Config struct {
+	KeyID      string
+	PrivateKey string // base64 Ed25519 seed (32 bytes) or full private key (64 bytes)
+	CreatedAt  string // YYYY-MM-DD the current key was introduced
+	// PreviousPublicKeys lists retired keys as "id=base64key" pairs separated by commas.
+	PreviousPublicKeys string
+	RotationInterval   time.Duration
+}
+
+// PublicKey is a verification key published to webhook receivers.
+type PublicKey struct {
+	ID        string     `json:"id"`
+	Algorithm string     `json:"algorithm"`
+	Key       string     `json:"public_key"` // base64
+	Status    string     `json:"status"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// KeySet signs with the current private key and publishes every key receivers may see.
+// A nil *KeySet is valid and means asymmetric signing is disabled.
+type KeySet struct {
+	id        string
+	private   ed25519.PrivateKey
+	createdAt *time.Time
+	previous  []PublicKey
+	rotation  time.Duration
+}
+
+// New parses cfg. It returns a nil KeySet, without error, when no private key is configured.
+func New(cfg Config) (*KeySet, error) {
+	if cfg.PrivateKey == "" {
+		return nil, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(cfg.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("signing: private key is not valid base64: %w", err)
+	}
+
+	ks := &KeySet{id: cfg.KeyID, rotation: cfg.RotationInterval}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		ks.private = ed25519.NewKeyFromSeed(raw)
+	case ed25519.PrivateKeySize:
+		ks.private = ed25519.PrivateKey(raw)
+	default:
+		return nil, fmt.Errorf("signing: private key must be %d or %d bytes, got %d",
+			ed25519.SeedSize, ed25519.PrivateKeySize, len(raw))
+	}
+	if ks.id == "" {
+		return nil, fmt.Errorf("signing: key id is required")
+	}
+
+	if cfg.CreatedAt != "" {
+		t, err := time.Parse("2006-01-02", cfg.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("signing: created-at must be YYYY-MM-DD: %w", err)
+		}
+		ks.createdAt = &t
+	}
+
+	for _, pair := range strings.Split(cfg.PreviousPublicKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, key, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("signing: previous key %q must be id=base64key", pair)
+		}
+		if b, err := base64.StdEncoding.DecodeString(key); err != nil || len(b) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("signing: previous key %q is not a base64 Ed25519 public key", id)
+		}
+		ks.previous = append(ks.previous, PublicKey{ID: id, Algorithm: Algorithm, Key: key, Status: StatusPrevious})
+	}
+
+	return ks, nil
+}
+
+// Enabled reports whether a signing key is configured.
+func (k *KeySet) Enabled() bool {
+	return k != nil
+}
+
+// KeyID returns the id of the current signing key.
+func (k *KeySet) KeyID() string {
+	if k == nil {
+		return ""
+	}
+	return k.id
+}
+
+// Sign returns the base64 signature of msg made with the current key.
+func (k *KeySet) Sign(msg []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(k.private, msg))
+}
+
+// PublicKeys returns the current key first, followed by retired keys still in rotation.
+func (k *KeySet) PublicKeys() []PublicKey {
+	if k == nil {
+		return []PublicKey{}
+	}
+	pub := k.private.Public().(ed25519.PublicKey)
+	keys := []PublicKey{{
+		ID:        k.id,
+		Algorithm: Algorithm,
+		Key:       base64.StdEncoding.EncodeToString(pub),
+		Status:    StatusCurrent,
+		CreatedAt: k.createdAt,
+	}}
+	return append(keys, k.previous...)
+}
+
+// RotationInterval returns how often the signing key is expected to be replaced.
+func (k *KeySet) RotationInterval() time.Duration {
+	if k == nil {
+		return 0
+	}
+	return k.rotation
+}
+
+// NextRotation returns when the current key is due to be replaced, if known.
+func (k *KeySet) NextRotation() *time.Time {
+	if k == nil || k.createdAt == nil || k.rotation <= 0 {
+		return nil
+	}
+	t := k.createdAt.Add(k.rotation)
+	return &t
+}
+
+// Verify checks a base64 signature against a base64 public key.
+func Verify(publicKey string, msg []byte, signature string) bool {
+	pub, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pub, msg, sig)
+}
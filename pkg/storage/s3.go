@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/galihaleanda/todo-app/pkg/awsv4"
+)
+
+type s3Storage struct {
+	base      *url.URL
+	bucket    string
+	pathStyle bool
+	signer    *awsv4.Signer
+	client    *http.Client
+}
+
+func newS3(cfg Config, client *http.Client) (*s3Storage, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	base, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("storage: invalid endpoint %q", endpoint)
+	}
+	return &s3Storage{
+		base:      base,
+		bucket:    cfg.Bucket,
+		pathStyle: cfg.PathStyle,
+		signer: &awsv4.Signer{
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			Region:          cfg.Region,
+			Service:         "s3",
+		},
+		client: client,
+	}, nil
+}
+
+func (s *s3Storage) PresignUpload(_ context.Context, key, contentType string, size int64, ttl time.Duration) (*PresignedRequest, error) {
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Content-Length", strconv.FormatInt(size, 10))
+	return s.presign(http.MethodPut, s.objectURL(key), headers, ttl), nil
+}
+
+func (s *s3Storage) PresignDownload(_ context.Context, key, filename string, ttl time.Duration) (*PresignedRequest, error) {
+	u := s.objectURL(key)
+	q := u.Query()
+	q.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	u.RawQuery = q.Encode()
+	return s.presign(http.MethodGet, u, nil, ttl), nil
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &ObjectInfo{Size: resp.ContentLength, ContentType: resp.Header.Get("Content-Type")}, nil
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	default:
+		return nil, fmt.Errorf("storage: HEAD %s returned %d", key, resp.StatusCode)
+	}
+}
+
+func (s *s3Storage) Put(ctx context.Context, key, contentType string, body []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, key, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage: PUT %s returned %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("storage: GET %s: %w", key, err)
+		}
+		return body, nil
+	case http.StatusNotFound:
+		return nil, ErrObjectNotFound
+	default:
+		return nil, fmt.Errorf("storage: GET %s returned %d", key, resp.StatusCode)
+	}
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, "", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 answers 204 whether or not the object existed.
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage: DELETE %s returned %d", key, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *s3Storage) presign(method string, u *url.URL, headers http.Header, ttl time.Duration) *PresignedRequest {
+	now := time.Now()
+	signed := s.signer.Presign(method, u, headers, ttl, now)
+	req := &PresignedRequest{Method: method, URL: signed.String(), ExpiresAt: now.Add(ttl)}
+	if len(headers) > 0 {
+		req.Headers = make(map[string]string, len(headers))
+		for k := range headers {
+			req.Headers[k] = headers.Get(k)
+		}
+	}
+	return req
+}
+
+func (s *s3Storage) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("storage: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.signer.Sign(req, body, time.Now())
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %s %s: %w", method, key, err)
+	}
+	return resp, nil
+}
+
+// objectURL addresses key in the bucket, virtual-hosted or path style.
+func (s *s3Storage) objectURL(key string) *url.URL {
+	u := *s.base
+	if s.pathStyle {
+		u.Path = "/" + s.bucket + "/" + key
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = "/" + key
+	}
+	return &u
+}
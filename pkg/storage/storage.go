@@ -0,0 +1,81 @@
+// Package storage provides object storage for file attachments and backups.
+// Clients upload and download attachments directly against the store
+// through presigned URLs, so their bodies never pass through the API; only
+// backups are read and written by the server itself.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Supported drivers. An empty driver disables attachments.
+const (
+	DriverNone = "none"
+	DriverS3   = "s3" // AWS S3 or any S3-compatible store such as MinIO
+)
+
+// ErrObjectNotFound is returned by Stat and Get when no object exists at
+// the key.
+var ErrObjectNotFound = errors.New("storage: object not found")
+
+// Storage is an object store addressed by key.
+type Storage interface {
+	// PresignUpload returns a request that stores exactly size bytes of
+	// contentType at key.
+	PresignUpload(ctx context.Context, key, contentType string, size int64, ttl time.Duration) (*PresignedRequest, error)
+	// PresignDownload returns a request that fetches key as an attachment
+	// named filename.
+	PresignDownload(ctx context.Context, key, filename string, ttl time.Duration) (*PresignedRequest, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Put stores body at key, replacing any object there.
+	Put(ctx context.Context, key, contentType string, body []byte) error
+	// Get returns the body of the object at key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// PresignedRequest is a request the client sends straight to the store.
+type PresignedRequest struct {
+	Method    string
+	URL       string
+	Headers   map[string]string // must be sent as given
+	ExpiresAt time.Time
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// Config selects and configures a driver.
+type Config struct {
+	Driver          string
+	Endpoint        string // defaults to AWS S3 in Region
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	PathStyle       bool // bucket in the path rather than the host name, as MinIO expects
+	Timeout         time.Duration
+}
+
+// New builds the Storage selected by cfg.Driver. It returns nil, nil when
+// attachments are disabled.
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverNone:
+		return nil, nil
+	case DriverS3:
+		if cfg.Bucket == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+			return nil, fmt.Errorf("storage: STORAGE_BUCKET, STORAGE_ACCESS_KEY_ID and STORAGE_SECRET_ACCESS_KEY are required for the s3 driver")
+		}
+		return newS3(cfg, &http.Client{Timeout: cfg.Timeout})
+	default:
+		return nil, fmt.Errorf("storage: unknown driver %q", cfg.Driver)
+	}
+}